@@ -0,0 +1,7 @@
+//go:build postgres
+
+package main
+
+// Registers the "postgres" database/sql driver for store.backend
+// "postgres". Build with -tags postgres.
+import _ "github.com/lib/pq"
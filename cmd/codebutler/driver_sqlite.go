@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package main
+
+// Registers the "sqlite3" database/sql driver for store.backend "sqlite".
+// Build with -tags sqlite; the driver needs cgo.
+import _ "github.com/mattn/go-sqlite3"
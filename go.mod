@@ -3,8 +3,10 @@ module github.com/leandrotocalini/codebutler
 go 1.24.7
 
 require (
-	github.com/gorilla/websocket v1.4.2 // indirect
-	github.com/slack-go/slack v0.15.0 // indirect
-	github.com/sony/gobreaker/v2 v2.4.0 // indirect
-	golang.org/x/sync v0.19.0 // indirect
+	github.com/gorilla/websocket v1.4.2
+	github.com/lib/pq v1.12.3
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/slack-go/slack v0.15.0
+	github.com/sony/gobreaker/v2 v2.4.0
+	golang.org/x/sync v0.19.0
 )
@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ArgError is one schema violation in a tool call's arguments.
+type ArgError struct {
+	Path    string // dotted path to the offending value ("" for the root)
+	Message string
+}
+
+func (e ArgError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidateToolArgs checks raw JSON arguments against a tool's parameters
+// schema. It supports the JSON Schema subset used by tool definitions:
+// type, properties, required, enum, items, and additionalProperties=false.
+// An empty schema accepts anything.
+func ValidateToolArgs(schema json.RawMessage, args string) []ArgError {
+	if strings.TrimSpace(args) == "" {
+		args = "{}"
+	}
+	var value any
+	if err := json.Unmarshal([]byte(args), &value); err != nil {
+		return []ArgError{{Message: fmt.Sprintf("arguments are not valid JSON: %s", err)}}
+	}
+	if len(schema) == 0 {
+		return nil
+	}
+	var s map[string]any
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil // a broken schema is the tool's bug, not the model's
+	}
+	var errs []ArgError
+	validateValue(s, value, "", &errs)
+	return errs
+}
+
+func validateValue(schema map[string]any, value any, path string, errs *[]ArgError) {
+	if want, ok := schema["type"].(string); ok && !matchesType(want, value) {
+		*errs = append(*errs, ArgError{Path: path, Message: fmt.Sprintf("expected %s, got %s", want, jsonType(value))})
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(enum, value) {
+		var opts []string
+		for _, e := range enum {
+			b, _ := json.Marshal(e)
+			opts = append(opts, string(b))
+		}
+		*errs = append(*errs, ArgError{Path: path, Message: "must be one of " + strings.Join(opts, ", ")})
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, present := v[name]; !present {
+					*errs = append(*errs, ArgError{Path: joinPath(path, name), Message: "required property missing"})
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := props[k].(map[string]any); ok {
+				validateValue(sub, v[k], joinPath(path, k), errs)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					*errs = append(*errs, ArgError{Path: joinPath(path, k), Message: "unknown property"})
+				}
+			case map[string]any:
+				validateValue(extra, v[k], joinPath(path, k), errs)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	}
+}
+
+func matchesType(want string, value any) bool {
+	switch want {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func inEnum(enum []any, value any) bool {
+	vb, _ := json.Marshal(value)
+	for _, e := range enum {
+		eb, _ := json.Marshal(e)
+		if string(eb) == string(vb) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// FormatArgErrors renders validation errors as a tool result the model can
+// act on: what was wrong and the schema it should follow.
+func FormatArgErrors(tool string, errs []ArgError, schema json.RawMessage) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("invalid arguments for %s:\n", tool))
+	for _, e := range errs {
+		b.WriteString("- " + e.String() + "\n")
+	}
+	if len(schema) > 0 {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, schema); err == nil {
+			b.WriteString("Expected parameters schema: " + compact.String() + "\n")
+		}
+	}
+	b.WriteString("Fix the arguments and call the tool again.")
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var readSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"path":   {"type": "string"},
+		"limit":  {"type": "integer"},
+		"mode":   {"type": "string", "enum": ["text", "hex"]},
+		"ranges": {"type": "array", "items": {"type": "integer"}},
+		"opts":   {"type": "object", "properties": {"follow": {"type": "boolean"}}, "additionalProperties": false}
+	},
+	"required": ["path"]
+}`)
+
+func TestValidateToolArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{"valid", `{"path": "main.go", "limit": 10, "mode": "hex", "ranges": [1, 2]}`, nil},
+		{"empty args treated as object", ``, []string{"path: required property missing"}},
+		{"malformed JSON", `{"path": "main.go"`, []string{"arguments are not valid JSON"}},
+		{"wrong type", `{"path": 42}`, []string{"path: expected string, got integer"}},
+		{"float for integer", `{"path": "a", "limit": 1.5}`, []string{"limit: expected integer, got number"}},
+		{"enum", `{"path": "a", "mode": "binary"}`, []string{`mode: must be one of "text", "hex"`}},
+		{"array items", `{"path": "a", "ranges": [1, "two"]}`, []string{"ranges[1]: expected integer, got string"}},
+		{"nested unknown property", `{"path": "a", "opts": {"follow": true, "depth": 3}}`, []string{"opts.depth: unknown property"}},
+		{"root not object", `["main.go"]`, []string{"expected object, got array"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateToolArgs(readSchema, tt.args)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("got %v, want %v", errs, tt.want)
+			}
+			for i, w := range tt.want {
+				if !strings.Contains(errs[i].String(), w) {
+					t.Errorf("error %d = %q, want containing %q", i, errs[i], w)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateToolArgs_NoSchema(t *testing.T) {
+	if errs := ValidateToolArgs(nil, `{"anything": true}`); len(errs) != 0 {
+		t.Errorf("no schema should accept any object, got %v", errs)
+	}
+	if errs := ValidateToolArgs(nil, `not json`); len(errs) != 1 {
+		t.Error("invalid JSON is rejected even without a schema")
+	}
+}
+
+func TestRun_InvalidArgsReturnedToModel(t *testing.T) {
+	provider := &mockProvider{responses: []*ChatResponse{
+		{Message: Message{Role: "assistant", ToolCalls: []ToolCall{{ID: "c1", Name: "Read", Arguments: `{"file": "main.go"}`}}}},
+		{Message: Message{Role: "assistant", ToolCalls: []ToolCall{{ID: "c2", Name: "Read", Arguments: `{"path": "main.go"}`}}}},
+		{Message: Message{Role: "assistant", Content: "done"}},
+	}}
+	executor := &mockExecutor{toolDefs: []ToolDefinition{{Name: "Read", Parameters: readSchema}}}
+	runner := NewAgentRunner(provider, &discardSender{}, executor, AgentConfig{Role: "coder", MaxTurns: 5})
+
+	result, err := runner.Run(context.Background(), Task{Messages: []Message{{Role: "user", Content: "read it"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if executor.callCount.Load() != 1 {
+		t.Errorf("only the valid call should reach the executor, got %d", executor.callCount.Load())
+	}
+
+	toolMsg := provider.requests[1].Messages[len(provider.requests[1].Messages)-1]
+	if toolMsg.Role != "tool" || !strings.Contains(toolMsg.Content, "path: required property missing") ||
+		!strings.Contains(toolMsg.Content, "Expected parameters schema") {
+		t.Errorf("unexpected tool message: %+v", toolMsg)
+	}
+	if s := result.ToolStats["Read"]; s.Calls != 2 || s.Failures != 1 {
+		t.Errorf("Read stats = %+v", s)
+	}
+}
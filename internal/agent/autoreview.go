@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// DiffSource returns the diff of head against base.
+type DiffSource interface {
+	Diff(ctx context.Context, base, head string) (string, error)
+}
+
+// PRReviewPoster submits a review on a pull request, identified by number
+// or URL.
+type PRReviewPoster interface {
+	PostReview(ctx context.Context, pr string, approve bool, body string) error
+}
+
+// InlineComment is a review note anchored to a line of the PR diff.
+type InlineComment struct {
+	Path string
+	Line int
+	Body string
+}
+
+// InlineReviewPoster submits a batch review: the overall verdict and body
+// plus comments on individual lines. A PRReviewPoster that also implements
+// it gets issues with file:line references as inline comments.
+type InlineReviewPoster interface {
+	PostInlineReview(ctx context.Context, pr string, approve bool, body string, comments []InlineComment) error
+}
+
+// InlineComments turns the issues that point at a file and line into
+// inline review comments. Issues without a location stay in the review body.
+func InlineComments(issues []ReviewIssue) []InlineComment {
+	var out []InlineComment
+	for _, issue := range issues {
+		if issue.File == "" || issue.Line <= 0 {
+			continue
+		}
+		body := fmt.Sprintf("**[%s]** %s", issue.Tag, issue.Message)
+		if issue.Severity != "" {
+			body += fmt.Sprintf(" (%s)", issue.Severity)
+		}
+		out = append(out, InlineComment{Path: issue.File, Line: issue.Line, Body: body})
+	}
+	return out
+}
+
+// AutoReviewer runs the Reviewer as soon as the Coder opens a PR, instead
+// of waiting for a manual handoff, and posts the structured review both on
+// the PR and in the thread.
+type AutoReviewer struct {
+	reviewer *ReviewerRunner
+	diffs    DiffSource
+	poster   PRReviewPoster // optional; nil posts only to the thread
+	sender   MessageSender
+	logger   *slog.Logger
+}
+
+// NewAutoReviewer creates an auto reviewer. poster may be nil.
+func NewAutoReviewer(reviewer *ReviewerRunner, diffs DiffSource, poster PRReviewPoster, sender MessageSender, logger *slog.Logger) *AutoReviewer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AutoReviewer{reviewer: reviewer, diffs: diffs, poster: poster, sender: sender, logger: logger}
+}
+
+// ReviewPR reviews the PR from head into base and publishes the result.
+// The review is approved when it reports no blockers.
+func (a *AutoReviewer) ReviewPR(ctx context.Context, channel, thread, prURL, base, head string) (*Result, error) {
+	if !a.reviewer.CanReview() {
+		return nil, fmt.Errorf("review rounds exhausted for %s", head)
+	}
+	diff, err := a.diffs.Diff(ctx, base, head)
+	if err != nil {
+		return nil, fmt.Errorf("load PR diff: %w", err)
+	}
+
+	res, err := a.reviewer.ReviewWithDiff(ctx, diff, head, channel, thread)
+	if err != nil {
+		return res, fmt.Errorf("review %s: %w", prURL, err)
+	}
+	if res.Response == "" {
+		return res, fmt.Errorf("review %s: reviewer gave no response", prURL)
+	}
+
+	issues := ParseReviewIssues(res.Response)
+	approve := !HasBlockers(issues)
+	inline := 0
+	if a.poster != nil {
+		var err error
+		if ip, ok := a.poster.(InlineReviewPoster); ok && len(InlineComments(issues)) > 0 {
+			comments := InlineComments(issues)
+			err = ip.PostInlineReview(ctx, prURL, approve, res.Response, comments)
+			if err == nil {
+				inline = len(comments)
+			}
+		} else {
+			err = a.poster.PostReview(ctx, prURL, approve, res.Response)
+		}
+		if err != nil {
+			// The thread still gets the review; only the GitHub copy is lost.
+			a.logger.Error("failed to post PR review", "pr", prURL, "err", err)
+		}
+	}
+
+	verdict := "approved"
+	if !approve {
+		verdict = "changes requested"
+	}
+	switch {
+	case inline == 1:
+		verdict += ", 1 inline comment on the PR"
+	case inline > 1:
+		verdict += fmt.Sprintf(", %d inline comments on the PR", inline)
+	}
+	msg := fmt.Sprintf("Automatic review of %s (%s):\n\n%s", prURL, verdict, res.Response)
+	if err := a.sender.SendMessage(ctx, channel, thread, msg); err != nil {
+		return res, fmt.Errorf("post review to thread: %w", err)
+	}
+	a.logger.Info("auto-review posted", "pr", prURL, "approved", approve)
+	return res, nil
+}
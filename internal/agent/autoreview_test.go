@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type stubDiffs struct{ base, head string }
+
+func (s *stubDiffs) Diff(_ context.Context, base, head string) (string, error) {
+	s.base, s.head = base, head
+	return "diff --git a/auth.go b/auth.go\n@@ -1 +1 @@\n-x := 1\n+x := load()", nil
+}
+
+type recordingReviewPoster struct {
+	pr      string
+	approve bool
+	body    string
+}
+
+func (r *recordingReviewPoster) PostReview(_ context.Context, pr string, approve bool, body string) error {
+	r.pr, r.approve, r.body = pr, approve, body
+	return nil
+}
+
+func TestAutoReviewer_PostsReviewToPRAndThread(t *testing.T) {
+	review := "**Invariants**: login keeps working\n\n1. [security] auth.go:1 — unchecked error, blocker"
+	provider := &mockProvider{responses: []*ChatResponse{{Message: Message{Role: "assistant", Content: review}}}}
+	reviewer := NewReviewerRunner(provider, &discardSender{}, &mockExecutor{}, DefaultReviewerConfig(), "You are the Reviewer.")
+	diffs := &stubDiffs{}
+	poster := &recordingReviewPoster{}
+	sender := &captureSender{}
+
+	a := NewAutoReviewer(reviewer, diffs, poster, sender, nil)
+	if _, err := a.ReviewPR(context.Background(), "C1", "100.1", "https://github.com/org/repo/pull/7", "main", "codebutler/login"); err != nil {
+		t.Fatal(err)
+	}
+
+	if diffs.base != "main" || diffs.head != "codebutler/login" {
+		t.Errorf("diff range = %s...%s", diffs.base, diffs.head)
+	}
+	if poster.pr != "https://github.com/org/repo/pull/7" || poster.approve || poster.body != review {
+		t.Errorf("PR review = %+v", poster)
+	}
+	if len(sender.messages) != 1 || !strings.Contains(sender.messages[0].Text, "(changes requested)") ||
+		sender.messages[0].Thread != "100.1" {
+		t.Errorf("thread messages = %+v", sender.messages)
+	}
+}
+
+type recordingInlinePoster struct {
+	recordingReviewPoster
+	comments []InlineComment
+}
+
+func (r *recordingInlinePoster) PostInlineReview(_ context.Context, pr string, approve bool, body string, comments []InlineComment) error {
+	r.pr, r.approve, r.body, r.comments = pr, approve, body, comments
+	return nil
+}
+
+func TestAutoReviewer_PostsInlineComments(t *testing.T) {
+	review := "1. [security] auth.go:42 — query built from user input, blocker\n2. [test] — no test for the lockout path"
+	provider := &mockProvider{responses: []*ChatResponse{{Message: Message{Role: "assistant", Content: review}}}}
+	reviewer := NewReviewerRunner(provider, &discardSender{}, &mockExecutor{}, DefaultReviewerConfig(), "You are the Reviewer.")
+	poster := &recordingInlinePoster{}
+	sender := &captureSender{}
+
+	a := NewAutoReviewer(reviewer, &stubDiffs{}, poster, sender, nil)
+	if _, err := a.ReviewPR(context.Background(), "C1", "100.1", "https://github.com/org/repo/pull/8", "main", "codebutler/login"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(poster.comments) != 1 || poster.comments[0].Path != "auth.go" || poster.comments[0].Line != 42 ||
+		!strings.HasPrefix(poster.comments[0].Body, "**[security]**") {
+		t.Errorf("inline comments = %+v", poster.comments)
+	}
+	if poster.approve || poster.body != review {
+		t.Errorf("review = approve %v, body %q", poster.approve, poster.body)
+	}
+	if !strings.Contains(sender.messages[0].Text, "1 inline comment on the PR") {
+		t.Errorf("thread message = %q", sender.messages[0].Text)
+	}
+}
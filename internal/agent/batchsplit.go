@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// BatchTask is one independent request extracted from a message batch.
+type BatchTask struct {
+	Messages []string // the batch messages that belong to this task, in order
+	Intent   Intent   // pre-classified intent of the combined text
+}
+
+// Text joins the task's messages into a single prompt.
+func (t BatchTask) Text() string {
+	return strings.Join(t.Messages, "\n")
+}
+
+// BatchSplitter splits a batch of messages that arrived together into
+// independent tasks, so "fix the login bug" and "what's our test
+// coverage?" get separate runs and separate replies instead of one mixed
+// prompt.
+type BatchSplitter struct {
+	provider  LLMProvider // optional; nil uses the keyword heuristic only
+	model     string
+	workflows []WorkflowDef
+	logger    *slog.Logger
+}
+
+// NewBatchSplitter creates a splitter. With a provider, a cheap model groups
+// the messages; when it is nil or its answer is unusable, messages are
+// grouped by keyword intent.
+func NewBatchSplitter(provider LLMProvider, model string, logger *slog.Logger) *BatchSplitter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &BatchSplitter{
+		provider:  provider,
+		model:     model,
+		workflows: DefaultWorkflows(),
+		logger:    logger,
+	}
+}
+
+// Split groups msgs into independent tasks, preserving message order within
+// each task and ordering tasks by their first message.
+func (s *BatchSplitter) Split(ctx context.Context, msgs []string) []BatchTask {
+	if len(msgs) <= 1 {
+		return s.tasks([][]int{indexes(len(msgs))}, msgs)
+	}
+
+	if s.provider != nil {
+		groups, err := s.classify(ctx, msgs)
+		if err == nil {
+			return s.tasks(groups, msgs)
+		}
+		s.logger.Warn("batch split: model grouping unusable, using keywords", "err", err)
+	}
+	return s.tasks(s.heuristicGroups(msgs), msgs)
+}
+
+// heuristicGroups walks the batch in order and starts a new group only when
+// a message has a clear intent that differs from the current group's.
+// Messages without a clear intent are treated as continuations.
+func (s *BatchSplitter) heuristicGroups(msgs []string) [][]int {
+	var groups [][]int
+	var current Intent
+	for i, m := range msgs {
+		intent := ClassifyIntent(m, s.workflows, nil)
+		switch {
+		case len(groups) == 0:
+			groups = append(groups, []int{i})
+			current = intent
+		case intent.Type != IntentAmbiguous && current.Type != IntentAmbiguous && intent.Name != current.Name:
+			groups = append(groups, []int{i})
+			current = intent
+		default:
+			groups[len(groups)-1] = append(groups[len(groups)-1], i)
+			if current.Type == IntentAmbiguous {
+				current = intent
+			}
+		}
+	}
+	return groups
+}
+
+// classify asks the model to group message numbers into tasks.
+func (s *BatchSplitter) classify(ctx context.Context, msgs []string) ([][]int, error) {
+	var b strings.Builder
+	b.WriteString("These chat messages arrived together. Group them into independent requests: ")
+	b.WriteString("messages that continue, clarify, or depend on each other belong to the same request.\n")
+	b.WriteString("Reply with only a JSON array of arrays of message numbers, e.g. [[1,3],[2]].\n\n")
+	for i, m := range msgs {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, m)
+	}
+
+	resp, err := s.provider.ChatCompletion(ctx, ChatRequest{
+		Model:    s.model,
+		Messages: []Message{{Role: "user", Content: b.String()}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseGroups(resp.Message.Content, len(msgs))
+}
+
+// parseGroups validates a [[1,3],[2]] reply: every message number from 1..n
+// must appear exactly once. Returned indexes are zero-based.
+func parseGroups(reply string, n int) ([][]int, error) {
+	raw := strings.TrimSpace(reply)
+	if start, end := strings.Index(raw, "["), strings.LastIndex(raw, "]"); start >= 0 && end > start {
+		raw = raw[start : end+1]
+	}
+	var numbered [][]int
+	if err := json.Unmarshal([]byte(raw), &numbered); err != nil {
+		return nil, fmt.Errorf("parse groups: %w", err)
+	}
+
+	seen := make([]bool, n)
+	var groups [][]int
+	for _, g := range numbered {
+		if len(g) == 0 {
+			continue
+		}
+		var idx []int
+		for _, num := range g {
+			if num < 1 || num > n || seen[num-1] {
+				return nil, fmt.Errorf("invalid or repeated message number %d", num)
+			}
+			seen[num-1] = true
+			idx = append(idx, num-1)
+		}
+		groups = append(groups, idx)
+	}
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("message %d not assigned", i+1)
+		}
+	}
+	return groups, nil
+}
+
+// tasks materializes groups, sorting each group's indexes and ordering the
+// groups by first message.
+func (s *BatchSplitter) tasks(groups [][]int, msgs []string) []BatchTask {
+	nonEmpty := groups[:0]
+	for _, g := range groups {
+		if len(g) > 0 {
+			sort.Ints(g)
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	sort.Slice(nonEmpty, func(i, j int) bool { return nonEmpty[i][0] < nonEmpty[j][0] })
+
+	out := make([]BatchTask, 0, len(nonEmpty))
+	for _, g := range nonEmpty {
+		t := BatchTask{}
+		for _, i := range g {
+			t.Messages = append(t.Messages, msgs[i])
+		}
+		t.Intent = ClassifyIntent(t.Text(), s.workflows, nil)
+		out = append(out, t)
+	}
+	return out
+}
+
+func indexes(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func taskTexts(tasks []BatchTask) [][]string {
+	var out [][]string
+	for _, t := range tasks {
+		out = append(out, t.Messages)
+	}
+	return out
+}
+
+func TestBatchSplitter_Heuristic(t *testing.T) {
+	s := NewBatchSplitter(nil, "", nil)
+	tasks := s.Split(context.Background(), []string{
+		"fix the login bug",
+		"it happens after the session expires",
+		"what's our test coverage?",
+	})
+
+	want := [][]string{
+		{"fix the login bug", "it happens after the session expires"},
+		{"what's our test coverage?"},
+	}
+	if got := taskTexts(tasks); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v", got)
+	}
+	if tasks[0].Intent.Name != "bugfix" || tasks[1].Intent.Name != "question" {
+		t.Errorf("intents = %+v, %+v", tasks[0].Intent, tasks[1].Intent)
+	}
+}
+
+func TestBatchSplitter_AmbiguousLeadAdoptsIntent(t *testing.T) {
+	s := NewBatchSplitter(nil, "", nil)
+	tasks := s.Split(context.Background(), []string{"the checkout page", "is crashing on submit"})
+	if len(tasks) != 1 || tasks[0].Text() != "the checkout page\nis crashing on submit" {
+		t.Errorf("got %v", taskTexts(tasks))
+	}
+}
+
+func TestBatchSplitter_Model(t *testing.T) {
+	provider := &mockProvider{responses: []*ChatResponse{
+		{Message: Message{Role: "assistant", Content: "```json\n[[2],[1,3]]\n```"}},
+	}}
+	s := NewBatchSplitter(provider, "cheap/model", nil)
+
+	tasks := s.Split(context.Background(), []string{"add dark mode", "why is CI red?", "use the brand colors"})
+
+	want := [][]string{{"add dark mode", "use the brand colors"}, {"why is CI red?"}}
+	if got := taskTexts(tasks); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v", got)
+	}
+	if provider.requests[0].Model != "cheap/model" {
+		t.Errorf("model = %q", provider.requests[0].Model)
+	}
+}
+
+type errProvider struct{}
+
+func (errProvider) ChatCompletion(context.Context, ChatRequest) (*ChatResponse, error) {
+	return nil, errors.New("boom")
+}
+
+func TestBatchSplitter_FallsBack(t *testing.T) {
+	msgs := []string{"fix the crash", "explain the router?"}
+	for name, p := range map[string]LLMProvider{
+		"error":   errProvider{},
+		"garbage": &mockProvider{responses: []*ChatResponse{{Message: Message{Content: "two tasks"}}}},
+		"missing": &mockProvider{responses: []*ChatResponse{{Message: Message{Content: "[[1]]"}}}},
+	} {
+		tasks := NewBatchSplitter(p, "m", slog.New(slog.NewTextHandler(io.Discard, nil))).Split(context.Background(), msgs)
+		if len(tasks) != 2 {
+			t.Errorf("%s: expected heuristic split, got %v", name, taskTexts(tasks))
+		}
+	}
+}
+
+func TestBatchSplitter_SingleMessageSkipsModel(t *testing.T) {
+	provider := &mockProvider{}
+	tasks := NewBatchSplitter(provider, "m", nil).Split(context.Background(), []string{"hello"})
+	if len(tasks) != 1 || len(provider.requests) != 0 {
+		t.Errorf("tasks=%v requests=%d", taskTexts(tasks), len(provider.requests))
+	}
+	if got := NewBatchSplitter(nil, "", nil).Split(context.Background(), nil); len(got) != 0 {
+		t.Errorf("empty batch: %v", got)
+	}
+}
+
+func TestParseGroups(t *testing.T) {
+	if _, err := parseGroups("[[1,2],[2]]", 2); err == nil {
+		t.Error("repeated number should fail")
+	}
+	if _, err := parseGroups("[[1],[5]]", 2); err == nil {
+		t.Error("out of range should fail")
+	}
+	g, err := parseGroups("Sure: [[1],[],[2]]", 2)
+	if err != nil || len(g) != 2 {
+		t.Errorf("g=%v err=%v", g, err)
+	}
+}
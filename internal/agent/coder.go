@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -202,21 +203,157 @@ func (v *SandboxValidator) ValidateCommand(command string) error {
 
 // PRDescription generates a PR description from the plan and implementation context.
 func PRDescription(plan string, filesChanged []string) string {
+	return BuildPRDescription(PRContext{Plan: plan, FilesChanged: filesChanged})
+}
+
+// TestRun is the outcome of a test command run during implementation.
+type TestRun struct {
+	Command string // e.g. "go test ./..."
+	Passed  bool
+	Summary string // short result, e.g. "142 passed, 0 failed"
+}
+
+// PRArtifact is a screenshot or other file produced while implementing.
+type PRArtifact struct {
+	Name string
+	URL  string // link or repo-relative path; images are embedded
+}
+
+// SourceMessage references the chat message that started the thread.
+type SourceMessage struct {
+	Permalink string // link to the message in chat
+	Text      string // original request text
+}
+
+// PRContext is everything known about a change when opening its PR.
+type PRContext struct {
+	Plan         string
+	FilesChanged []string
+	TestRuns     []TestRun
+	Artifacts    []PRArtifact
+	Review       *ReviewResult  // Reviewer's risk matrix, if a review has run
+	Source       *SourceMessage // originating chat message, if known
+
+	// Redact strips sensitive content from the source message text before
+	// it is published in the PR (e.g. router.Redactor.Redact). When nil the
+	// source text is omitted and only the permalink is included.
+	Redact func(string) string
+}
+
+// imageExts are artifact extensions embedded as images rather than linked.
+var imageExts = []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}
+
+// BuildPRDescription generates a PR description with optional test plan,
+// artifacts, risk notes, and source reference sections. Sections without
+// data are omitted.
+func BuildPRDescription(pr PRContext) string {
 	var b strings.Builder
 
 	b.WriteString("## Summary\n\n")
 
 	// Extract first paragraph of the plan as summary
-	paragraphs := strings.SplitN(plan, "\n\n", 2)
+	paragraphs := strings.SplitN(pr.Plan, "\n\n", 2)
 	if len(paragraphs) > 0 {
 		b.WriteString(paragraphs[0])
 	}
 
 	b.WriteString("\n\n## Changes\n\n")
-	for _, f := range filesChanged {
+	for _, f := range pr.FilesChanged {
 		b.WriteString(fmt.Sprintf("- `%s`\n", f))
 	}
 
+	if len(pr.TestRuns) > 0 {
+		b.WriteString("\n## Test Plan\n\n")
+		for _, t := range pr.TestRuns {
+			mark := "x"
+			if !t.Passed {
+				mark = " "
+			}
+			line := fmt.Sprintf("- [%s] `%s`", mark, t.Command)
+			if t.Summary != "" {
+				line += " — " + t.Summary
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if len(pr.Artifacts) > 0 {
+		b.WriteString("\n## Screenshots & Artifacts\n\n")
+		for _, a := range pr.Artifacts {
+			if isImage(a.URL) {
+				b.WriteString(fmt.Sprintf("![%s](%s)\n", a.Name, a.URL))
+			} else {
+				b.WriteString(fmt.Sprintf("- [%s](%s)\n", a.Name, a.URL))
+			}
+		}
+	}
+
+	if notes := riskNotes(pr.Review); len(notes) > 0 {
+		b.WriteString("\n## Risk Notes\n\n")
+		for _, n := range notes {
+			b.WriteString("- " + n + "\n")
+		}
+	}
+
+	if pr.Source != nil && (pr.Source.Permalink != "" || pr.Source.Text != "") {
+		b.WriteString("\n## Source\n\n")
+		if pr.Source.Permalink != "" {
+			b.WriteString(fmt.Sprintf("Requested in [chat](%s)", pr.Source.Permalink))
+		} else {
+			b.WriteString("Requested in chat")
+		}
+		if pr.Redact != nil && pr.Source.Text != "" {
+			quoted := strings.ReplaceAll(truncate(pr.Redact(pr.Source.Text), 500), "\n", "\n> ")
+			b.WriteString(":\n\n> " + quoted)
+		}
+		b.WriteString("\n")
+	}
+
 	b.WriteString("\n---\n*Generated by CodeButler*\n")
 	return b.String()
 }
+
+// riskNotes lists non-trivial risk matrix entries, highest first, followed
+// by the invariants the Reviewer identified.
+func riskNotes(review *ReviewResult) []string {
+	if review == nil {
+		return nil
+	}
+
+	order := map[RiskLevel]int{RiskHigh: 0, RiskMedium: 1, RiskLow: 2}
+	type entry struct {
+		cat   RiskCategory
+		level RiskLevel
+	}
+	var entries []entry
+	for cat, level := range review.RiskMatrix {
+		if _, ok := order[level]; ok {
+			entries = append(entries, entry{cat, level})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if order[entries[i].level] != order[entries[j].level] {
+			return order[entries[i].level] < order[entries[j].level]
+		}
+		return entries[i].cat < entries[j].cat
+	})
+
+	var notes []string
+	for _, e := range entries {
+		notes = append(notes, fmt.Sprintf("**%s**: %s", e.cat, e.level))
+	}
+	for _, inv := range review.Invariants {
+		notes = append(notes, "Must not break: "+inv)
+	}
+	return notes
+}
+
+func isImage(url string) bool {
+	lower := strings.ToLower(url)
+	for _, ext := range imageExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -137,3 +138,72 @@ func TestDefaultCoderConfig(t *testing.T) {
 		t.Errorf("expected main base branch, got %s", cfg.BaseBranch)
 	}
 }
+
+func TestBuildPRDescription_Enriched(t *testing.T) {
+	desc := BuildPRDescription(PRContext{
+		Plan:         "Add rate limiting to the login endpoint.",
+		FilesChanged: []string{"internal/auth/handler.go"},
+		TestRuns: []TestRun{
+			{Command: "go test ./internal/auth/...", Passed: true, Summary: "24 passed"},
+			{Command: "make lint", Passed: false},
+		},
+		Artifacts: []PRArtifact{
+			{Name: "login page", URL: ".codebutler/images/login.png"},
+			{Name: "bench results", URL: "https://example.com/bench.txt"},
+		},
+		Review: &ReviewResult{
+			RiskMatrix: map[RiskCategory]RiskLevel{
+				RiskSecurity:    RiskHigh,
+				RiskPerformance: RiskLow,
+				RiskCorrectness: RiskNone,
+			},
+			Invariants: []string{"existing sessions stay valid"},
+		},
+		Source: &SourceMessage{
+			Permalink: "https://team.slack.com/archives/C1/p123",
+			Text:      "add rate limiting, staging key is sk-abc123",
+		},
+		Redact: func(s string) string { return strings.ReplaceAll(s, "sk-abc123", "[REDACTED]") },
+	})
+
+	for _, want := range []string{
+		"## Test Plan",
+		"- [x] `go test ./internal/auth/...` — 24 passed",
+		"- [ ] `make lint`",
+		"![login page](.codebutler/images/login.png)",
+		"- [bench results](https://example.com/bench.txt)",
+		"**security**: high",
+		"Must not break: existing sessions stay valid",
+		"Requested in [chat](https://team.slack.com/archives/C1/p123)",
+		"[REDACTED]",
+	} {
+		if !containsStr(desc, want) {
+			t.Errorf("missing %q in:\n%s", want, desc)
+		}
+	}
+	if containsStr(desc, "sk-abc123") {
+		t.Error("secret leaked into PR description")
+	}
+	if containsStr(desc, "correctness") {
+		t.Error("risk level none should be omitted")
+	}
+	if strings.Index(desc, "security") > strings.Index(desc, "performance") {
+		t.Error("higher risks should be listed first")
+	}
+}
+
+func TestBuildPRDescription_SourceWithoutRedactor(t *testing.T) {
+	desc := BuildPRDescription(PRContext{
+		Plan:   "Fix typo.",
+		Source: &SourceMessage{Permalink: "https://x/p1", Text: "secret stuff"},
+	})
+	if containsStr(desc, "secret stuff") {
+		t.Error("source text must be omitted without a redactor")
+	}
+	if !containsStr(desc, "https://x/p1") {
+		t.Error("permalink should still be included")
+	}
+	if containsStr(desc, "## Test Plan") || containsStr(desc, "## Risk Notes") {
+		t.Error("empty sections should be omitted")
+	}
+}
@@ -34,6 +34,10 @@ type CompactionConfig struct {
 	// RecentKeep is how many recent message pairs (assistant+tool) to preserve
 	// verbatim. Default 4.
 	RecentKeep int
+
+	// Model writes the summary. Empty uses the agent's configured model; an
+	// "ollama/<name>" model keeps compaction local and free.
+	Model string
 }
 
 // DefaultCompactionConfig returns a config with sensible defaults.
@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -245,3 +246,39 @@ func TestFindRecentStart(t *testing.T) {
 func ctx() context.Context {
 	return context.Background()
 }
+
+func TestRun_CompactionUsesConfiguredModel(t *testing.T) {
+	usage := TokenUsage{TotalTokens: 30}
+	readCall := func(id string) *ChatResponse {
+		return &ChatResponse{Usage: usage, Message: Message{
+			Role:      "assistant",
+			ToolCalls: []ToolCall{{ID: id, Name: "Read", Arguments: `{"path":"` + id + `.go"}`}},
+		}}
+	}
+	provider := &mockProvider{responses: []*ChatResponse{
+		readCall("a"),
+		readCall("b"),
+		{Message: Message{Role: "assistant", Content: "## Progress so far\n- read a.go and b.go"}},
+		{Usage: usage, Message: Message{Role: "assistant", Content: "done"}},
+	}}
+	cfg := DefaultCompactionConfig(100)
+	cfg.Threshold = 0.5
+	cfg.RecentKeep = 1
+	cfg.Model = "ollama/llama3.1"
+
+	runner := NewAgentRunner(provider, &discardSender{}, &mockExecutor{}, AgentConfig{
+		Role: "coder", Model: "cloud-model", MaxTurns: 10, SystemPrompt: "You are a coder.",
+	}, WithCompaction(cfg))
+	if _, err := runner.Run(context.Background(), Task{Messages: []Message{{Role: "user", Content: "read"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var models []string
+	for _, r := range provider.requests {
+		models = append(models, r.Model)
+	}
+	want := []string{"cloud-model", "cloud-model", "ollama/llama3.1", "cloud-model"}
+	if strings.Join(models, ",") != strings.Join(want, ",") {
+		t.Errorf("models = %v, want %v", models, want)
+	}
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+)
+
+// Tool concurrency classes. Heavy tools (builds, test runs) are serialized,
+// read-only tools run freely in parallel, everything else shares a pool.
+const (
+	ClassHeavy   = "heavy"
+	ClassRead    = "read"
+	ClassDefault = "default"
+)
+
+// ToolConcurrency limits how many tool calls of each class may run at once.
+// Limits are shared across all runs of an AgentRunner, so parallel threads
+// in one process cannot start three `go test ./...` at the same time.
+type ToolConcurrency struct {
+	// Classes maps tool name → class. Unlisted tools are ClassDefault.
+	Classes map[string]string
+	// Limits maps class → max concurrent calls. Zero or missing means
+	// unlimited.
+	Limits map[string]int
+}
+
+// DefaultToolConcurrency serializes shell-driven tools and leaves reads
+// unbounded.
+func DefaultToolConcurrency() ToolConcurrency {
+	return ToolConcurrency{
+		Classes: map[string]string{
+			"Bash":           ClassHeavy,
+			"RunTarget":      ClassHeavy,
+			"StartDevServer": ClassHeavy,
+			"Read":           ClassRead,
+			"Grep":           ClassRead,
+			"Glob":           ClassRead,
+			"WebFetch":       ClassRead,
+			"WebSearch":      ClassRead,
+			"LogsQuery":      ClassRead,
+			"MetricsQuery":   ClassRead,
+		},
+		Limits: map[string]int{
+			ClassHeavy:   1,
+			ClassDefault: 4,
+		},
+	}
+}
+
+// Merge overlays o's classes and limits on c.
+func (c ToolConcurrency) Merge(o ToolConcurrency) ToolConcurrency {
+	out := ToolConcurrency{Classes: make(map[string]string), Limits: make(map[string]int)}
+	for k, v := range c.Classes {
+		out.Classes[k] = v
+	}
+	for k, v := range o.Classes {
+		out.Classes[k] = v
+	}
+	for k, v := range c.Limits {
+		out.Limits[k] = v
+	}
+	for k, v := range o.Limits {
+		out.Limits[k] = v
+	}
+	return out
+}
+
+// ClassOf returns the concurrency class of a tool.
+func (c ToolConcurrency) ClassOf(tool string) string {
+	if class, ok := c.Classes[tool]; ok {
+		return class
+	}
+	return ClassDefault
+}
+
+// toolLimiter enforces ToolConcurrency with one semaphore per class.
+type toolLimiter struct {
+	cfg  ToolConcurrency
+	sems map[string]chan struct{}
+}
+
+func newToolLimiter(cfg ToolConcurrency) *toolLimiter {
+	l := &toolLimiter{cfg: cfg, sems: make(map[string]chan struct{})}
+	for class, n := range cfg.Limits {
+		if n > 0 {
+			l.sems[class] = make(chan struct{}, n)
+		}
+	}
+	return l
+}
+
+// acquire blocks until the tool's class has capacity or ctx is done. The
+// returned release func must be called when the call finishes.
+func (l *toolLimiter) acquire(ctx context.Context, tool string) (func(), error) {
+	sem, ok := l.sems[l.cfg.ClassOf(tool)]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyExecutor records the peak number of in-flight calls per tool.
+type concurrencyExecutor struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	peak     map[string]int
+}
+
+func (e *concurrencyExecutor) Execute(_ context.Context, call ToolCall) (ToolResult, error) {
+	e.mu.Lock()
+	e.inFlight[call.Name]++
+	e.peak[call.Name] = max(e.peak[call.Name], e.inFlight[call.Name])
+	e.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	e.mu.Lock()
+	e.inFlight[call.Name]--
+	e.mu.Unlock()
+	return ToolResult{ToolCallID: call.ID, Content: "ok"}, nil
+}
+
+func (e *concurrencyExecutor) ListTools() []ToolDefinition { return nil }
+
+func runParallelCalls(t *testing.T, calls []ToolCall, opts ...RunnerOption) *concurrencyExecutor {
+	t.Helper()
+	exec := &concurrencyExecutor{inFlight: map[string]int{}, peak: map[string]int{}}
+	provider := &mockProvider{responses: []*ChatResponse{
+		{Message: Message{Role: "assistant", ToolCalls: calls}},
+		{Message: Message{Role: "assistant", Content: "done"}},
+	}}
+	runner := NewAgentRunner(provider, &discardSender{}, exec, AgentConfig{Role: "coder", MaxTurns: 3}, opts...)
+	if _, err := runner.Run(context.Background(), Task{Messages: []Message{{Role: "user", Content: "go"}}}); err != nil {
+		t.Fatal(err)
+	}
+	return exec
+}
+
+func TestToolConcurrency_HeavySerializedReadsParallel(t *testing.T) {
+	exec := runParallelCalls(t, []ToolCall{
+		{ID: "1", Name: "Bash", Arguments: `{"command":"go test ./a"}`},
+		{ID: "2", Name: "Bash", Arguments: `{"command":"go test ./b"}`},
+		{ID: "3", Name: "Bash", Arguments: `{"command":"go test ./c"}`},
+		{ID: "4", Name: "Read", Arguments: `{"path":"a"}`},
+		{ID: "5", Name: "Read", Arguments: `{"path":"b"}`},
+		{ID: "6", Name: "Read", Arguments: `{"path":"c"}`},
+	})
+
+	if exec.peak["Bash"] != 1 {
+		t.Errorf("Bash should be serialized, peak %d", exec.peak["Bash"])
+	}
+	if exec.peak["Read"] != 3 {
+		t.Errorf("Reads should run in parallel, peak %d", exec.peak["Read"])
+	}
+}
+
+func TestToolConcurrency_ConfigOverride(t *testing.T) {
+	exec := runParallelCalls(t, []ToolCall{
+		{ID: "1", Name: "Bash", Arguments: `{"n":1}`},
+		{ID: "2", Name: "Bash", Arguments: `{"n":2}`},
+		{ID: "3", Name: "Bash", Arguments: `{"n":3}`},
+		{ID: "4", Name: "Grep", Arguments: `{"n":4}`},
+		{ID: "5", Name: "Grep", Arguments: `{"n":5}`},
+		{ID: "6", Name: "Grep", Arguments: `{"n":6}`},
+	}, WithToolConcurrency(ToolConcurrency{
+		Classes: map[string]string{"Grep": "search"},
+		Limits:  map[string]int{ClassHeavy: 2, "search": 1},
+	}))
+
+	if exec.peak["Bash"] != 2 {
+		t.Errorf("heavy limit 2 should allow 2 Bash calls, peak %d", exec.peak["Bash"])
+	}
+	if exec.peak["Grep"] != 1 {
+		t.Errorf("Grep reclassified into a class of 1, peak %d", exec.peak["Grep"])
+	}
+}
+
+func TestToolLimiter_ContextCancelled(t *testing.T) {
+	l := newToolLimiter(ToolConcurrency{Classes: map[string]string{"Bash": ClassHeavy}, Limits: map[string]int{ClassHeavy: 1}})
+	release, err := l.acquire(context.Background(), "Bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.acquire(ctx, "Bash"); err == nil {
+		t.Error("acquire should fail when the class is full and ctx is done")
+	}
+	if rel, err := l.acquire(ctx, "Unlisted"); err != nil {
+		t.Error("unlimited classes should never block")
+	} else {
+		rel()
+	}
+}
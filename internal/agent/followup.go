@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultReplyWindow is how long after a reply a message with no other
+// signal is still treated as a follow-up.
+const DefaultReplyWindow = 10 * time.Minute
+
+// continuationPrefixes open messages that only make sense against the
+// previous exchange ("also add pagination", "it still fails").
+var continuationPrefixes = []string{
+	"also", "and", "but", "it", "its", "it's", "that", "this", "those", "these",
+	"same", "still", "now", "then", "what about", "how about", "ok", "okay",
+	"thanks", "thank you", "yes", "no", "instead", "actually",
+}
+
+// FollowUpDetector decides whether a new message continues the current
+// conversation or starts a fresh one. Arrival time alone gets both edges
+// wrong: a new topic typed seconds after a reply is not a follow-up, and
+// "it still fails" an hour later is.
+type FollowUpDetector struct {
+	provider  LLMProvider // optional; nil uses the heuristic only
+	model     string
+	window    time.Duration
+	workflows []WorkflowDef
+	logger    *slog.Logger
+}
+
+// NewFollowUpDetector creates a detector. With a provider, a cheap model
+// makes the call; when it is nil or its answer is unusable, topic overlap and
+// the reply window decide. A window <= 0 uses DefaultReplyWindow.
+func NewFollowUpDetector(provider LLMProvider, model string, window time.Duration, logger *slog.Logger) *FollowUpDetector {
+	if window <= 0 {
+		window = DefaultReplyWindow
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FollowUpDetector{
+		provider:  provider,
+		model:     model,
+		window:    window,
+		workflows: DefaultWorkflows(),
+		logger:    logger,
+	}
+}
+
+// IsFollowUp reports whether msg continues history. sinceReply is the time
+// elapsed since the last assistant reply.
+func (d *FollowUpDetector) IsFollowUp(ctx context.Context, history []Message, msg string, sinceReply time.Duration) bool {
+	if len(history) == 0 {
+		return false
+	}
+	if d.provider != nil {
+		follow, err := d.classify(ctx, history, msg, sinceReply)
+		if err == nil {
+			return follow
+		}
+		d.logger.Warn("follow-up: model answer unusable, using heuristic", "err", err)
+	}
+	return d.heuristic(history, msg, sinceReply)
+}
+
+// heuristic checks, in order: explicit continuation wording, a clearly
+// different intent with no shared vocabulary, shared vocabulary, and finally
+// the reply window.
+func (d *FollowUpDetector) heuristic(history []Message, msg string, sinceReply time.Duration) bool {
+	if hasContinuationPrefix(msg) {
+		return true
+	}
+
+	var thread []string
+	for _, m := range history {
+		if m.Role == "user" {
+			thread = append(thread, m.Content)
+		}
+	}
+	if len(thread) == 0 {
+		return sinceReply <= d.window
+	}
+
+	overlap := sharedTerms(strings.Join(thread, " "), msg)
+	topic := ClassifyIntent(thread[0], d.workflows, nil)
+	intent := ClassifyIntent(msg, d.workflows, nil)
+	if overlap == 0 && topic.Type != IntentAmbiguous && intent.Type != IntentAmbiguous && topic.Name != intent.Name {
+		return false
+	}
+	if overlap >= 2 {
+		return true
+	}
+	return sinceReply <= d.window
+}
+
+// classify asks the model whether msg continues the recent exchange.
+func (d *FollowUpDetector) classify(ctx context.Context, history []Message, msg string, sinceReply time.Duration) (bool, error) {
+	recent := history
+	if len(recent) > 6 {
+		recent = recent[len(recent)-6:]
+	}
+
+	var b strings.Builder
+	b.WriteString("Decide whether the new message continues the conversation below or starts an unrelated request.\n")
+	b.WriteString("Reply with exactly FOLLOW_UP or NEW.\n\n")
+	for _, m := range recent {
+		fmt.Fprintf(&b, "%s: %s\n", m.Role, truncate(m.Content, 400))
+	}
+	fmt.Fprintf(&b, "\nNew message (%s after the last reply): %s\n", sinceReply.Round(time.Second), msg)
+
+	resp, err := d.provider.ChatCompletion(ctx, ChatRequest{
+		Model:    d.model,
+		Messages: []Message{{Role: "user", Content: b.String()}},
+	})
+	if err != nil {
+		return false, err
+	}
+	answer := strings.ToUpper(strings.TrimSpace(resp.Message.Content))
+	switch {
+	case strings.HasPrefix(answer, "FOLLOW"):
+		return true, nil
+	case strings.HasPrefix(answer, "NEW"):
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected answer %q", truncate(answer, 40))
+}
+
+func hasContinuationPrefix(msg string) bool {
+	lower := strings.ToLower(strings.TrimSpace(msg))
+	for _, p := range continuationPrefixes {
+		if lower == p || strings.HasPrefix(lower, p+" ") || strings.HasPrefix(lower, p+",") {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedTerms counts distinct words of four or more letters present in both
+// texts; shorter words are mostly glue ("the", "fix", "add").
+func sharedTerms(a, b string) int {
+	seen := make(map[string]bool)
+	for _, w := range contentWords(a) {
+		seen[w] = true
+	}
+	n := 0
+	for _, w := range contentWords(b) {
+		if seen[w] {
+			n++
+			seen[w] = false
+		}
+	}
+	return n
+}
+
+func contentWords(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var out []string
+	for _, f := range fields {
+		if len([]rune(f)) >= 4 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestFollowUpDetector_Heuristic(t *testing.T) {
+	d := NewFollowUpDetector(nil, "", time.Minute, nil)
+	history := []Message{
+		{Role: "user", Content: "fix the login crash on the checkout page"},
+		{Role: "assistant", Content: "On it."},
+	}
+
+	tests := []struct {
+		name  string
+		msg   string
+		since time.Duration
+		want  bool
+	}{
+		{"new topic right after reply", "what's our test coverage?", 10 * time.Second, false},
+		{"continuation after window", "it still crashes", time.Hour, true},
+		{"shared vocabulary after window", "the checkout login now loops", time.Hour, true},
+		{"unrelated after window", "hello there", time.Hour, false},
+		{"ambiguous inside window", "hello there", 10 * time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.IsFollowUp(context.Background(), history, tt.msg, tt.since); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowUpDetector_EmptyHistory(t *testing.T) {
+	d := NewFollowUpDetector(nil, "", 0, nil)
+	if d.IsFollowUp(context.Background(), nil, "also this", 0) {
+		t.Error("nothing to follow up on")
+	}
+}
+
+func TestFollowUpDetector_Model(t *testing.T) {
+	history := []Message{{Role: "user", Content: "fix the login crash"}}
+
+	provider := &mockProvider{responses: []*ChatResponse{
+		{Message: Message{Content: "NEW"}},
+		{Message: Message{Content: "follow_up"}},
+	}}
+	d := NewFollowUpDetector(provider, "cheap/model", time.Minute, nil)
+
+	if d.IsFollowUp(context.Background(), history, "it still crashes", time.Second) {
+		t.Error("model said NEW")
+	}
+	if !d.IsFollowUp(context.Background(), history, "what's our coverage?", time.Hour) {
+		t.Error("model said FOLLOW_UP")
+	}
+	if provider.requests[0].Model != "cheap/model" || !containsStr(provider.requests[0].Messages[0].Content, "fix the login crash") {
+		t.Errorf("request = %+v", provider.requests[0])
+	}
+}
+
+func TestFollowUpDetector_ModelFallsBack(t *testing.T) {
+	history := []Message{{Role: "user", Content: "fix the login crash"}}
+	provider := &mockProvider{responses: []*ChatResponse{{Message: Message{Content: "maybe?"}}}}
+	d := NewFollowUpDetector(provider, "m", time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if !d.IsFollowUp(context.Background(), history, "also the signup page", time.Hour) {
+		t.Error("expected heuristic follow-up")
+	}
+}
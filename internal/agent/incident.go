@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IncidentConfig tunes agents for production firefighting. Incident mode is
+// read-only by default, surfaces log/metric tools first, and keeps replies
+// short so responders can scan them on a phone.
+type IncidentConfig struct {
+	// AllowWrites lifts the read-only restriction (e.g. to ship a hotfix).
+	AllowWrites bool
+	// PriorityTools are listed first, in order. Prefix matches are allowed
+	// (e.g. "Logs" matches "LogsQuery" and "LogsTail").
+	PriorityTools []string
+	// AccumulationWindow is how long to wait for follow-up messages before
+	// starting a turn. Shorter than normal: incidents favor latency.
+	AccumulationWindow time.Duration
+	// MaxTurns caps the agent loop for each request.
+	MaxTurns int
+	// IsMutating reports whether a call changes state. Nil uses
+	// DefaultMutatingTools by name.
+	IsMutating func(call ToolCall) bool
+}
+
+// DefaultMutatingTools are the tools blocked in read-only incident mode.
+var DefaultMutatingTools = []string{
+	"Write", "Edit", "GitCommit", "GitPush", "GHCreatePR", "GenerateImage", "EditImage",
+}
+
+// DefaultIncidentConfig returns the standard incident mode configuration.
+func DefaultIncidentConfig() IncidentConfig {
+	return IncidentConfig{
+		PriorityTools:      []string{"Logs", "Metrics", "Query", "Grep", "Read"},
+		AccumulationWindow: 500 * time.Millisecond,
+		MaxTurns:           20,
+	}
+}
+
+func (c IncidentConfig) mutating(call ToolCall) bool {
+	if c.IsMutating != nil {
+		return c.IsMutating(call)
+	}
+	for _, name := range DefaultMutatingTools {
+		if call.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// incidentPromptSection is appended to the role prompt in incident mode.
+const incidentPromptSection = `
+
+## Incident Mode
+
+Production is affected. Optimize for time to mitigation.
+- Be terse: short bullets, no preamble, no summaries of what you are about to do.
+- Check logs and metrics before reading code.
+- State each finding with its evidence (log line, metric, commit) in one line.
+- Do not modify files, commit, or push unless the responder explicitly lifts read-only mode.
+- When you have a likely cause, say so with your confidence and the next check to confirm it.`
+
+// IncidentSystemPrompt appends the incident instructions to a role prompt.
+func IncidentSystemPrompt(base string) string {
+	return base + incidentPromptSection
+}
+
+// IncidentExecutor wraps a ToolExecutor for incident mode: mutating tools
+// are hidden and refused (unless AllowWrites), priority tools come first,
+// and every call is recorded on the timeline.
+type IncidentExecutor struct {
+	inner    ToolExecutor
+	cfg      IncidentConfig
+	timeline *IncidentTimeline
+}
+
+// NewIncidentExecutor wraps inner with incident mode restrictions. The
+// timeline may be nil.
+func NewIncidentExecutor(inner ToolExecutor, cfg IncidentConfig, timeline *IncidentTimeline) *IncidentExecutor {
+	return &IncidentExecutor{inner: inner, cfg: cfg, timeline: timeline}
+}
+
+// ListTools returns the tools available in incident mode, priority tools first.
+func (e *IncidentExecutor) ListTools() []ToolDefinition {
+	var defs []ToolDefinition
+	for _, d := range e.inner.ListTools() {
+		if !e.cfg.AllowWrites && e.cfg.mutating(ToolCall{Name: d.Name}) {
+			continue
+		}
+		defs = append(defs, d)
+	}
+
+	rank := func(name string) int {
+		for i, p := range e.cfg.PriorityTools {
+			if strings.HasPrefix(name, p) {
+				return i
+			}
+		}
+		return len(e.cfg.PriorityTools)
+	}
+	sort.SliceStable(defs, func(i, j int) bool {
+		return rank(defs[i].Name) < rank(defs[j].Name)
+	})
+	return defs
+}
+
+// Execute refuses mutating calls in read-only mode and records each call.
+func (e *IncidentExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	if !e.cfg.AllowWrites && e.cfg.mutating(call) {
+		e.timeline.Add(TimelineAction, fmt.Sprintf("blocked %s (read-only)", call.Name))
+		return ToolResult{
+			ToolCallID: call.ID,
+			Content:    fmt.Sprintf("%s is disabled in incident mode (read-only). Ask the responder to lift read-only mode if a change is required.", call.Name),
+			IsError:    true,
+		}, nil
+	}
+
+	result, err := e.inner.Execute(ctx, call)
+	switch {
+	case err != nil:
+		e.timeline.Add(TimelineAction, fmt.Sprintf("%s failed: %v", call.Name, err))
+	case result.IsError:
+		e.timeline.Add(TimelineAction, fmt.Sprintf("%s returned an error", call.Name))
+	default:
+		e.timeline.Add(TimelineAction, fmt.Sprintf("ran %s %s", call.Name, truncate(call.Arguments, 120)))
+	}
+	return result, err
+}
+
+// TimelineKind classifies an incident timeline entry.
+type TimelineKind string
+
+const (
+	TimelineAction     TimelineKind = "action"     // tool call or agent step
+	TimelineFinding    TimelineKind = "finding"    // evidence or conclusion
+	TimelineMitigation TimelineKind = "mitigation" // change that reduced impact
+	TimelineNote       TimelineKind = "note"       // responder message
+)
+
+// TimelineEntry is one timestamped event during an incident.
+type TimelineEntry struct {
+	Time time.Time    `json:"time"`
+	Kind TimelineKind `json:"kind"`
+	Text string       `json:"text"`
+}
+
+// IncidentTimeline accumulates what happened during an incident and renders
+// it as a document published when the incident is closed. All methods are
+// safe on a nil receiver, which records nothing.
+type IncidentTimeline struct {
+	mu      sync.Mutex
+	title   string
+	started time.Time
+	entries []TimelineEntry
+	now     func() time.Time
+}
+
+// NewIncidentTimeline starts a timeline for the incident described by title.
+func NewIncidentTimeline(title string) *IncidentTimeline {
+	t := &IncidentTimeline{title: title, now: time.Now}
+	t.started = t.now()
+	return t
+}
+
+// Add records an entry at the current time.
+func (t *IncidentTimeline) Add(kind TimelineKind, text string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, TimelineEntry{Time: t.now(), Kind: kind, Text: text})
+}
+
+// Entries returns a copy of the recorded entries.
+func (t *IncidentTimeline) Entries() []TimelineEntry {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TimelineEntry(nil), t.entries...)
+}
+
+// Markdown renders the timeline document. Findings and mitigations are
+// summarized up top; the full chronological log follows.
+func (t *IncidentTimeline) Markdown() string {
+	if t == nil {
+		return ""
+	}
+	entries := t.Entries()
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Incident: %s\n\n", t.title))
+	b.WriteString(fmt.Sprintf("Started: %s\n", t.started.UTC().Format(time.RFC3339)))
+	if len(entries) > 0 {
+		end := entries[len(entries)-1].Time
+		b.WriteString(fmt.Sprintf("Duration: %s\n", end.Sub(t.started).Round(time.Second)))
+	}
+
+	for _, section := range []struct {
+		kind  TimelineKind
+		title string
+	}{
+		{TimelineFinding, "Findings"},
+		{TimelineMitigation, "Mitigations"},
+	} {
+		var lines []string
+		for _, e := range entries {
+			if e.Kind == section.kind {
+				lines = append(lines, "- "+e.Text)
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString(fmt.Sprintf("\n## %s\n\n%s\n", section.title, strings.Join(lines, "\n")))
+		}
+	}
+
+	b.WriteString("\n## Timeline\n\n")
+	if len(entries) == 0 {
+		b.WriteString("_No events recorded._\n")
+	}
+	for _, e := range entries {
+		b.WriteString(fmt.Sprintf("- `%s` **%s** %s\n", e.Time.UTC().Format("15:04:05"), e.Kind, e.Text))
+	}
+	return b.String()
+}
+
+// Publish posts the timeline document to the incident thread.
+func (t *IncidentTimeline) Publish(ctx context.Context, sender MessageSender, channel, thread string) error {
+	if t == nil {
+		return nil
+	}
+	return sender.SendMessage(ctx, channel, thread, t.Markdown())
+}
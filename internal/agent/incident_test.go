@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func incidentTools() *mockExecutor {
+	return &mockExecutor{
+		toolDefs: []ToolDefinition{
+			{Name: "Read"}, {Name: "Write"}, {Name: "Edit"}, {Name: "Bash"},
+			{Name: "LogsQuery"}, {Name: "GitPush"}, {Name: "MetricsFetch"},
+		},
+	}
+}
+
+func TestIncidentExecutor_ListTools(t *testing.T) {
+	exec := NewIncidentExecutor(incidentTools(), DefaultIncidentConfig(), nil)
+
+	var names []string
+	for _, d := range exec.ListTools() {
+		names = append(names, d.Name)
+	}
+	got := strings.Join(names, ",")
+	if got != "LogsQuery,MetricsFetch,Read,Bash" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestIncidentExecutor_AllowWrites(t *testing.T) {
+	cfg := DefaultIncidentConfig()
+	cfg.AllowWrites = true
+	exec := NewIncidentExecutor(incidentTools(), cfg, nil)
+
+	if n := len(exec.ListTools()); n != 7 {
+		t.Errorf("expected all 7 tools with writes allowed, got %d", n)
+	}
+	res, err := exec.Execute(context.Background(), ToolCall{ID: "1", Name: "Write"})
+	if err != nil || res.IsError {
+		t.Errorf("write should pass through: %+v %v", res, err)
+	}
+}
+
+func TestIncidentExecutor_BlocksWrites(t *testing.T) {
+	inner := incidentTools()
+	timeline := NewIncidentTimeline("checkout 500s")
+	exec := NewIncidentExecutor(inner, DefaultIncidentConfig(), timeline)
+
+	res, err := exec.Execute(context.Background(), ToolCall{ID: "1", Name: "GitPush"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError || !strings.Contains(res.Content, "read-only") {
+		t.Errorf("expected read-only refusal, got %+v", res)
+	}
+	if inner.callCount.Load() != 0 {
+		t.Error("blocked call should not reach the inner executor")
+	}
+
+	if _, err := exec.Execute(context.Background(), ToolCall{ID: "2", Name: "LogsQuery", Arguments: `{"q":"status=500"}`}); err != nil {
+		t.Fatal(err)
+	}
+	entries := timeline.Entries()
+	if len(entries) != 2 || !strings.Contains(entries[1].Text, "LogsQuery") {
+		t.Errorf("unexpected timeline: %+v", entries)
+	}
+}
+
+func TestIncidentExecutor_CustomClassifier(t *testing.T) {
+	cfg := DefaultIncidentConfig()
+	cfg.IsMutating = func(call ToolCall) bool {
+		return call.Name == "Bash" && strings.Contains(call.Arguments, "kubectl")
+	}
+	exec := NewIncidentExecutor(incidentTools(), cfg, nil)
+
+	res, _ := exec.Execute(context.Background(), ToolCall{Name: "Bash", Arguments: `{"command":"kubectl rollout restart"}`})
+	if !res.IsError {
+		t.Error("kubectl should be blocked by the custom classifier")
+	}
+	res, _ = exec.Execute(context.Background(), ToolCall{Name: "Bash", Arguments: `{"command":"tail app.log"}`})
+	if res.IsError {
+		t.Error("tail should be allowed")
+	}
+}
+
+func TestIncidentTimeline_Markdown(t *testing.T) {
+	start := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	clock := start
+	timeline := NewIncidentTimeline("checkout 500s")
+	timeline.now = func() time.Time { return clock }
+	timeline.started = start
+
+	clock = start.Add(2 * time.Minute)
+	timeline.Add(TimelineAction, "ran LogsQuery")
+	clock = start.Add(5 * time.Minute)
+	timeline.Add(TimelineFinding, "DB pool exhausted since deploy abc123")
+	clock = start.Add(12 * time.Minute)
+	timeline.Add(TimelineMitigation, "rolled back to def456")
+
+	md := timeline.Markdown()
+	for _, want := range []string{
+		"# Incident: checkout 500s",
+		"Duration: 12m0s",
+		"## Findings\n\n- DB pool exhausted",
+		"## Mitigations\n\n- rolled back",
+		"`14:02:00` **action** ran LogsQuery",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestIncidentTimeline_Publish(t *testing.T) {
+	sender := &captureSender{}
+	timeline := NewIncidentTimeline("db outage")
+	timeline.Add(TimelineNote, "paged by alertmanager")
+
+	if err := timeline.Publish(context.Background(), sender, "C1", "T1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.messages) != 1 || !strings.Contains(sender.messages[0].Text, "paged by alertmanager") {
+		t.Errorf("unexpected messages: %+v", sender.messages)
+	}
+
+	var nilTimeline *IncidentTimeline
+	nilTimeline.Add(TimelineNote, "ignored")
+	if err := nilTimeline.Publish(context.Background(), sender, "C1", "T1"); err != nil || len(sender.messages) != 1 {
+		t.Error("nil timeline should be a no-op")
+	}
+}
+
+func TestIncidentSystemPrompt(t *testing.T) {
+	p := IncidentSystemPrompt("You are the PM.")
+	if !strings.HasPrefix(p, "You are the PM.") || !strings.Contains(p, "Be terse") {
+		t.Errorf("got %q", p)
+	}
+}
@@ -29,6 +29,7 @@ type LeadRunner struct {
 	*AgentRunner
 	leadConfig LeadConfig
 	logger     *slog.Logger
+	feedback   func() []string // recent negative user feedback, optional
 }
 
 // LeadRunnerOption configures the Lead runner.
@@ -41,6 +42,14 @@ func WithLeadLogger(l *slog.Logger) LeadRunnerOption {
 	}
 }
 
+// WithRecentFeedback sets a source of recent negative user feedback
+// (/bad ratings) that is included in every retrospective prompt.
+func WithRecentFeedback(fn func() []string) LeadRunnerOption {
+	return func(r *LeadRunner) {
+		r.feedback = fn
+	}
+}
+
 // NewLeadRunner creates a Lead agent runner.
 func NewLeadRunner(
 	provider LLMProvider,
@@ -76,6 +85,9 @@ func NewLeadRunner(
 // RunRetrospective starts a retrospective after a thread completes.
 func (l *LeadRunner) RunRetrospective(ctx context.Context, threadSummary string, agentResults map[string]*Result, channel, thread string) (*Result, error) {
 	prompt := FormatRetroPrompt(threadSummary, agentResults)
+	if l.feedback != nil {
+		prompt = WithFeedbackSection(prompt, l.feedback())
+	}
 
 	task := Task{
 		Messages: []Message{
@@ -218,6 +230,8 @@ type ThreadReport struct {
 	WentWell          []string          `json:"went_well"`
 	Friction          []string          `json:"friction"`
 	Proposals         []RetroProposal   `json:"proposals"`
+	Rating            int               `json:"rating,omitempty"`   // net /good − /bad score
+	Feedback          []string          `json:"feedback,omitempty"` // user feedback comments
 }
 
 // AgentMetrics tracks per-agent metrics for a thread.
@@ -227,6 +241,7 @@ type AgentMetrics struct {
 	LoopsDetected int     `json:"loops_detected"`
 	TokensUsed    int     `json:"tokens_used"`
 	EstimatedCost float64 `json:"estimated_cost"`
+	ToolStats     map[string]ToolStats `json:"tool_stats,omitempty"`
 }
 
 // ThreadPattern represents a pattern observed during the thread.
@@ -237,14 +252,50 @@ type ThreadPattern struct {
 	Severity    string `json:"severity"`    // info, warning, improvement
 }
 
+// FeedbackSource supplies the users' verdict on a thread: the net /good
+// − /bad score and the comments left with them. feedback.Store
+// satisfies it.
+type FeedbackSource interface {
+	ThreadFeedback(threadID string) (rating int, comments []string, err error)
+}
+
+// ReportOption configures NewThreadReport.
+type ReportOption func(*reportOptions)
+
+type reportOptions struct {
+	feedback FeedbackSource
+}
+
+// WithReportFeedback fills the report's Rating and Feedback from src.
+func WithReportFeedback(src FeedbackSource) ReportOption {
+	return func(o *reportOptions) {
+		o.feedback = src
+	}
+}
+
 // NewThreadReport creates a report from agent results.
-func NewThreadReport(threadID string, results map[string]*Result) ThreadReport {
+func NewThreadReport(threadID string, results map[string]*Result, opts ...ReportOption) ThreadReport {
+	var o reportOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	report := ThreadReport{
 		ThreadID:     threadID,
 		Timestamp:    time.Now(),
 		AgentMetrics: make(map[string]AgentMetrics),
 	}
 
+	if o.feedback != nil {
+		rating, comments, err := o.feedback.ThreadFeedback(threadID)
+		if err != nil {
+			// A report without ratings beats no report.
+			slog.Warn("thread report: read feedback", "thread", threadID, "err", err)
+		} else {
+			report.Rating, report.Feedback = rating, comments
+		}
+	}
+
 	var totalTokens int
 	for role, result := range results {
 		if result == nil {
@@ -255,6 +306,7 @@ func NewThreadReport(threadID string, results map[string]*Result) ThreadReport {
 			ToolCalls:     result.ToolCalls,
 			LoopsDetected: result.LoopsDetected,
 			TokensUsed:    result.TokenUsage.TotalTokens,
+			ToolStats:     result.ToolStats,
 		}
 		report.AgentMetrics[role] = metrics
 		totalTokens += result.TokenUsage.TotalTokens
@@ -289,6 +341,15 @@ func FormatUsageReport(report ThreadReport) string {
 
 	b.WriteString(fmt.Sprintf("\n**Estimated cost:** $%.4f\n", report.TotalCost))
 
+	var perAgent []map[string]ToolStats
+	for _, metrics := range report.AgentMetrics {
+		perAgent = append(perAgent, metrics.ToolStats)
+	}
+	if breakdown := FormatToolBreakdown(MergeToolStats(perAgent...)); breakdown != "" {
+		b.WriteString("\n### Tool Time\n\n")
+		b.WriteString(breakdown)
+	}
+
 	return b.String()
 }
 
@@ -335,6 +396,29 @@ func FormatRetroPrompt(threadSummary string, agentResults map[string]*Result) st
 	return b.String()
 }
 
+// WithFeedbackSection inserts recent negative user feedback into a
+// retrospective prompt, ahead of the instructions, so the Lead can look for
+// recurring causes. Returns the prompt unchanged when there is no feedback.
+func WithFeedbackSection(prompt string, feedback []string) string {
+	if len(feedback) == 0 {
+		return prompt
+	}
+
+	var b strings.Builder
+	b.WriteString("### Recent Negative Feedback\n\n")
+	b.WriteString("Users rated these recent threads /bad. Look for causes that recur in this thread:\n\n")
+	for _, f := range feedback {
+		b.WriteString("- " + f + "\n")
+	}
+	b.WriteString("\n")
+
+	const marker = "### Instructions"
+	if i := strings.Index(prompt, marker); i >= 0 {
+		return prompt[:i] + b.String() + prompt[i:]
+	}
+	return prompt + "\n" + b.String()
+}
+
 // FormatMediationContext creates context for a mediation decision.
 func FormatMediationContext(agent1, position1, agent2, position2 string) string {
 	return fmt.Sprintf("**%s's position:** %s\n\n**%s's position:** %s",
@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -276,6 +277,30 @@ func TestNewThreadReport_NilResults(t *testing.T) {
 	}
 }
 
+type fakeFeedback struct {
+	rating   int
+	comments []string
+	err      error
+}
+
+func (f fakeFeedback) ThreadFeedback(string) (int, []string, error) {
+	return f.rating, f.comments, f.err
+}
+
+func TestNewThreadReport_Feedback(t *testing.T) {
+	results := map[string]*Result{"coder": {TurnsUsed: 2}}
+
+	report := NewThreadReport("T1", results, WithReportFeedback(fakeFeedback{rating: -1, comments: []string{"wrong file edited"}}))
+	if report.Rating != -1 || len(report.Feedback) != 1 || report.Feedback[0] != "wrong file edited" {
+		t.Errorf("rating=%d feedback=%v", report.Rating, report.Feedback)
+	}
+
+	report = NewThreadReport("T1", results, WithReportFeedback(fakeFeedback{rating: 3, err: errors.New("disk")}))
+	if report.Rating != 0 || report.Feedback != nil {
+		t.Errorf("unreadable feedback should leave the fields empty, got %d %v", report.Rating, report.Feedback)
+	}
+}
+
 func TestMarshalReport(t *testing.T) {
 	report := ThreadReport{
 		ThreadID: "T-test",
@@ -347,3 +372,21 @@ func TestFormatMediationContext(t *testing.T) {
 		t.Error("missing coder's argument")
 	}
 }
+
+func TestWithFeedbackSection(t *testing.T) {
+	prompt := FormatRetroPrompt("summary", nil)
+	got := WithFeedbackSection(prompt, []string{"thread t1 — edited the wrong service"})
+
+	fb := strings.Index(got, "### Recent Negative Feedback")
+	instr := strings.Index(got, "### Instructions")
+	if fb < 0 || fb > instr {
+		t.Errorf("feedback section should precede instructions:\n%s", got)
+	}
+	if !strings.Contains(got, "- thread t1 — edited the wrong service") {
+		t.Error("missing feedback line")
+	}
+
+	if WithFeedbackSection(prompt, nil) != prompt {
+		t.Error("empty feedback should leave the prompt unchanged")
+	}
+}
@@ -0,0 +1,16 @@
+package agent
+
+// ModelSelector picks the model for a runner's next turn, e.g. switching to
+// a cheaper model when a thread nears its budget. It returns current to
+// keep the model unchanged.
+type ModelSelector interface {
+	SelectModel(role, thread, current string) string
+}
+
+// WithModelSelector lets sel change the model before each LLM call. The
+// model of the last turn is reported in Result.Model.
+func WithModelSelector(sel ModelSelector) RunnerOption {
+	return func(r *AgentRunner) {
+		r.selector = sel
+	}
+}
@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"testing"
+)
+
+// switchAfter switches to cheap once turn calls have been made.
+type switchAfter struct {
+	turn, calls int
+	cheap       string
+}
+
+func (s *switchAfter) SelectModel(_, _, current string) string {
+	s.calls++
+	if s.calls > s.turn {
+		return s.cheap
+	}
+	return current
+}
+
+func TestRun_ModelSelectorSwitchesModel(t *testing.T) {
+	provider := &mockProvider{
+		responses: []*ChatResponse{
+			{Message: Message{Role: "assistant", ToolCalls: []ToolCall{{ID: "c1", Name: "Read", Arguments: `{}`}}}},
+			{Message: Message{Role: "assistant", Content: "done"}},
+		},
+	}
+	executor := &mockExecutor{
+		results:  map[string]ToolResult{"Read": {Content: "ok"}},
+		toolDefs: []ToolDefinition{{Name: "Read"}},
+	}
+	runner := NewAgentRunner(provider, &discardSender{}, executor,
+		AgentConfig{Role: "coder", Model: "expensive", MaxTurns: 5},
+		WithModelSelector(&switchAfter{turn: 1, cheap: "cheap"}))
+
+	result, err := runner.Run(context.Background(), Task{Messages: []Message{{Role: "user", Content: "go"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.requests[0].Model != "expensive" || provider.requests[1].Model != "cheap" {
+		t.Errorf("models = %s, %s", provider.requests[0].Model, provider.requests[1].Model)
+	}
+	if result.Model != "cheap" {
+		t.Errorf("Result.Model = %q", result.Model)
+	}
+}
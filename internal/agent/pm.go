@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
 )
 
 // IntentType represents the classification of a user's intent.
@@ -47,6 +50,8 @@ func DefaultWorkflows() []WorkflowDef {
 		{Name: "refactor", Description: "restructure existing code", Keywords: []string{"refactor", "restructure", "reorganize", "clean up", "simplify"}},
 		{Name: "discover", Description: "plan multiple features, build a roadmap", Keywords: []string{"discover", "plan", "roadmap", "multiple", "batch"}},
 		{Name: "learn", Description: "explore the codebase and build knowledge", Keywords: []string{"learn", "onboard", "understand", "explore"}},
+		{Name: "incident", Description: "investigate a production incident (read-only, terse)", Keywords: []string{"incident", "outage", "on-call", "sev1", "sev2", "production down", "prod is down"}},
+		{Name: "triage", Description: "label open issues and draft clarifying questions", Keywords: []string{"triage", "label issues", "open issues"}},
 	}
 }
 
@@ -174,10 +179,12 @@ func ModelForComplexity(complexity TaskComplexity, defaultModel string) string {
 	}
 }
 
-// FormatWorkflowMenu formats the available workflows and skills as a Slack message.
-func FormatWorkflowMenu(workflows []WorkflowDef, skills []SkillDef) string {
+// FormatWorkflowMenu formats the available workflows and skills as a Slack
+// message. The header and footer come from cat; nil uses the default locale.
+func FormatWorkflowMenu(cat *messages.Catalog, workflows []WorkflowDef, skills []SkillDef) string {
+	cat = catalogOrDefault(cat)
 	var b strings.Builder
-	b.WriteString("I can help you with:\n")
+	b.WriteString(cat.Render(messages.WorkflowMenuHeader, nil) + "\n")
 
 	for _, w := range workflows {
 		b.WriteString(fmt.Sprintf("- *%s* — %s\n", w.Name, w.Description))
@@ -193,10 +200,23 @@ func FormatWorkflowMenu(workflows []WorkflowDef, skills []SkillDef) string {
 		b.WriteString(")_\n")
 	}
 
-	b.WriteString("\nWhat would you like to do?")
+	b.WriteString("\n" + cat.Render(messages.WorkflowMenuFooter, nil))
 	return b.String()
 }
 
+// defaultCatalog renders messages for callers that have no configured
+// locale.
+var defaultCatalog = sync.OnceValue(func() *messages.Catalog {
+	return messages.New(messages.DefaultLocale)
+})
+
+func catalogOrDefault(c *messages.Catalog) *messages.Catalog {
+	if c == nil {
+		return defaultCatalog()
+	}
+	return c
+}
+
 // DelegationMessage creates a message for delegating work to another agent.
 func DelegationMessage(targetRole, plan, context string) string {
 	var b strings.Builder
@@ -217,6 +237,7 @@ type PMRunner struct {
 	skills    []SkillDef
 	pmConfig  PMConfig
 	logger    *slog.Logger
+	pastWork  func(query string) string // similar past solutions, optional
 }
 
 // PMRunnerOption configures the PM runner.
@@ -236,6 +257,22 @@ func WithPMSkills(s []SkillDef) PMRunnerOption {
 	}
 }
 
+// WithPMLogger sets the logger used by the PM and its underlying runner.
+func WithPMLogger(l *slog.Logger) PMRunnerOption {
+	return func(r *PMRunner) {
+		r.logger = l
+	}
+}
+
+// WithPastSolutions sets a lookup that returns context about similar past
+// solutions for a user request. Non-empty results are injected ahead of the
+// task so the PM can reuse a known approach.
+func WithPastSolutions(fn func(query string) string) PMRunnerOption {
+	return func(r *PMRunner) {
+		r.pastWork = fn
+	}
+}
+
 // NewPMRunner creates a PM agent runner.
 func NewPMRunner(
 	provider LLMProvider,
@@ -287,6 +324,13 @@ func (pm *PMRunner) ClassifyAndRun(ctx context.Context, task Task) (*Result, Int
 		"message_preview", truncate(userMessage, 80),
 	)
 
+	if pm.pastWork != nil && userMessage != "" {
+		if past := pm.pastWork(userMessage); past != "" {
+			pm.logger.Info("injecting similar past solutions")
+			task.Messages = append([]Message{{Role: "user", Content: past}}, task.Messages...)
+		}
+	}
+
 	result, err := pm.AgentRunner.Run(ctx, task)
 	return result, intent, err
 }
@@ -1,7 +1,11 @@
 package agent
 
 import (
+	"context"
+	"strings"
 	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
 )
 
 func TestClassifyIntent_Workflow(t *testing.T) {
@@ -18,6 +22,8 @@ func TestClassifyIntent_Workflow(t *testing.T) {
 		{"refactor the database layer", "refactor"},
 		{"build a new search feature", "implement"},
 		{"this is crashing on startup", "bugfix"},
+		{"triage the open issues", "triage"},
+		{"outage: checkout returns 500s", "incident"},
 	}
 
 	for _, tt := range tests {
@@ -122,7 +128,7 @@ func TestFormatWorkflowMenu(t *testing.T) {
 		{Name: "test"},
 	}
 
-	menu := FormatWorkflowMenu(workflows, skills)
+	menu := FormatWorkflowMenu(nil, workflows, skills)
 
 	for _, want := range []string{"implement", "bugfix", "question", "explain", "test"} {
 		if !containsStr(menu, want) {
@@ -133,13 +139,21 @@ func TestFormatWorkflowMenu(t *testing.T) {
 
 func TestFormatWorkflowMenu_NoSkills(t *testing.T) {
 	workflows := DefaultWorkflows()
-	menu := FormatWorkflowMenu(workflows, nil)
+	menu := FormatWorkflowMenu(nil, workflows, nil)
 
 	if containsStr(menu, "skills:") {
 		t.Error("should not show skills section when none")
 	}
 }
 
+func TestFormatWorkflowMenu_Localized(t *testing.T) {
+	menu := FormatWorkflowMenu(messages.New("es"), DefaultWorkflows(), nil)
+
+	if !strings.HasPrefix(menu, "Puedo ayudarte con:\n") || !strings.HasSuffix(menu, "¿Qué querés hacer?") {
+		t.Errorf("menu not localized:\n%s", menu)
+	}
+}
+
 func TestDelegationMessage(t *testing.T) {
 	msg := DelegationMessage("coder", "Implement login page", "Auth module is at internal/auth/")
 
@@ -189,3 +203,34 @@ func containsStr(s, sub string) bool {
 	}
 	return false
 }
+
+func TestPMRunner_InjectsPastSolutions(t *testing.T) {
+	provider := &mockProvider{
+		responses: []*ChatResponse{
+			{Message: Message{Role: "assistant", Content: "On it."}},
+		},
+	}
+
+	var gotQuery string
+	pm := NewPMRunner(provider, &discardSender{}, &mockExecutor{}, DefaultPMConfig(), "You are the PM.",
+		WithPastSolutions(func(query string) string {
+			gotQuery = query
+			return "## Similar Past Solutions\n\n1. refresh JWT in middleware"
+		}),
+	)
+
+	_, _, err := pm.ClassifyAndRun(context.Background(), Task{
+		Messages: []Message{{Role: "user", Content: "login breaks when the JWT expires"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotQuery != "login breaks when the JWT expires" {
+		t.Errorf("lookup got %q", gotQuery)
+	}
+	msgs := provider.requests[0].Messages
+	if len(msgs) != 3 || !containsStr(msgs[1].Content, "Similar Past Solutions") {
+		t.Errorf("past solutions should precede the request, got %+v", msgs)
+	}
+}
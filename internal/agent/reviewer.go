@@ -32,6 +32,7 @@ type ReviewerRunner struct {
 	reviewerConfig ReviewerConfig
 	logger         *slog.Logger
 	currentRound   int
+	static         *StaticReviewer // optional no-LLM fast path for trivial diffs
 }
 
 // ReviewerRunnerOption configures the Reviewer runner.
@@ -44,6 +45,14 @@ func WithReviewerLogger(l *slog.Logger) ReviewerRunnerOption {
 	}
 }
 
+// WithStaticReview enables the rule-based fast path: trivial diffs (docs,
+// comments, renames under the size threshold) skip the LLM review.
+func WithStaticReview(sr *StaticReviewer) ReviewerRunnerOption {
+	return func(r *ReviewerRunner) {
+		r.static = sr
+	}
+}
+
 // NewReviewerRunner creates a Reviewer agent runner.
 func NewReviewerRunner(
 	provider LLMProvider,
@@ -82,6 +91,19 @@ func (r *ReviewerRunner) ReviewWithDiff(ctx context.Context, diff, branch, chann
 	r.currentRound++
 	round := r.currentRound
 
+	if r.static != nil {
+		if review, ok := r.static.Review(ctx, diff); ok {
+			r.logger.Info("reviewer used static fast path",
+				"branch", branch,
+				"round", round,
+				"kind", review.Kind,
+				"lines", review.Lines,
+				"issues", len(review.Issues),
+			)
+			return &Result{Response: review.Format()}, nil
+		}
+	}
+
 	prompt := FormatReviewPrompt(diff, branch, r.reviewerConfig.BaseBranch, round, r.reviewerConfig.MaxRounds)
 
 	task := Task{
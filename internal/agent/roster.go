@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Participant is a human in the thread. ID is the platform handle used for
+// mentions (a Slack user ID); Name is what people call them.
+type Participant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Label returns the participant's name, or the ID when no name is known.
+func (p Participant) Label() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.ID
+}
+
+// SenderMessage builds a user message attributed to its sender, so in a
+// multi-person thread the model can tell who asked what ("as Maria asked
+// earlier").
+func SenderMessage(sender Participant, text string) Message {
+	label := sender.Label()
+	if label == "" {
+		return Message{Role: "user", Content: text}
+	}
+	return Message{Role: "user", Content: label + ": " + text}
+}
+
+// RosterPrompt renders the participant list appended to the system prompt.
+// It is empty for fewer than two people, where attribution adds nothing.
+func RosterPrompt(participants []Participant) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for _, p := range participants {
+		key := p.ID
+		if key == "" {
+			key = p.Name
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		line := "- " + p.Label()
+		if p.ID != "" && p.Name != "" {
+			line += fmt.Sprintf(" (mention as <@%s>)", p.ID)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) < 2 {
+		return ""
+	}
+	return "## Participants\n\n" +
+		"Several people are in this thread. User messages are prefixed with the sender's name. " +
+		"Attribute requests to the person who made them, and address a question to the person who can answer it.\n\n" +
+		strings.Join(lines, "\n")
+}
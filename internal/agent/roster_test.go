@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSenderMessage(t *testing.T) {
+	tests := []struct {
+		sender Participant
+		want   string
+	}{
+		{Participant{ID: "U1", Name: "Maria"}, "Maria: fix the login"},
+		{Participant{ID: "U1"}, "U1: fix the login"},
+		{Participant{}, "fix the login"},
+	}
+	for _, tt := range tests {
+		m := SenderMessage(tt.sender, "fix the login")
+		if m.Role != "user" || m.Content != tt.want {
+			t.Errorf("%+v: got %+v", tt.sender, m)
+		}
+	}
+}
+
+func TestRosterPrompt(t *testing.T) {
+	if got := RosterPrompt([]Participant{{ID: "U1", Name: "Maria"}, {ID: "U1", Name: "Maria"}}); got != "" {
+		t.Errorf("a single person needs no roster, got %q", got)
+	}
+
+	got := RosterPrompt([]Participant{{ID: "U1", Name: "Maria"}, {ID: "U2"}})
+	for _, want := range []string{"## Participants", "- Maria (mention as <@U1>)", "- U2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRun_InjectsRoster(t *testing.T) {
+	provider := &mockProvider{
+		responses: []*ChatResponse{
+			{Message: Message{Role: "assistant", Content: "Sure, Juan."}},
+		},
+	}
+	runner := NewAgentRunner(provider, &discardSender{}, &mockExecutor{}, AgentConfig{
+		Role:         "pm",
+		Model:        "test-model",
+		MaxTurns:     3,
+		SystemPrompt: "You are the PM.",
+	})
+
+	maria := Participant{ID: "U1", Name: "Maria"}
+	juan := Participant{ID: "U2", Name: "Juan"}
+	_, err := runner.Run(context.Background(), Task{
+		Messages: []Message{
+			SenderMessage(maria, "the checkout crashes"),
+			SenderMessage(juan, "can we add a test for what Maria reported?"),
+		},
+		Participants: []Participant{maria, juan},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := provider.requests[0].Messages
+	if !strings.HasPrefix(msgs[0].Content, "You are the PM.") || !strings.Contains(msgs[0].Content, "- Juan (mention as <@U2>)") {
+		t.Errorf("system prompt = %q", msgs[0].Content)
+	}
+	if msgs[1].Content != "Maria: the checkout crashes" {
+		t.Errorf("first message = %q", msgs[1].Content)
+	}
+}
@@ -2,9 +2,13 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
 )
 
 // AgentRunner executes the agent loop: prompt → LLM → tool calls → execute → repeat.
@@ -20,6 +24,16 @@ type AgentRunner struct {
 	// Safety features (M7)
 	compaction *CompactionConfig  // optional, for context compaction
 	tracker    *ProgressTracker   // stuck detection + escape strategies
+
+	slowTools map[string]time.Duration // per-tool slow-call warning thresholds
+	limiter   *toolLimiter             // per-class tool concurrency limits
+
+	partial  PartialSink   // optional; receives intermediate output
+	selector ModelSelector // optional; may switch models between turns
+
+	catalog *messages.Catalog // user-facing text; nil uses the default locale
+
+	teardown []func() // run, in order, when Run returns
 }
 
 // RunnerOption configures optional AgentRunner parameters.
@@ -32,6 +46,22 @@ func WithLogger(l *slog.Logger) RunnerOption {
 	}
 }
 
+// WithCatalog sets the catalog user-facing messages, such as the
+// escalation notice, are rendered from.
+func WithCatalog(c *messages.Catalog) RunnerOption {
+	return func(r *AgentRunner) {
+		r.catalog = c
+	}
+}
+
+// WithTeardown registers fns to run when Run returns, however it ends:
+// task-scoped cleanup such as tools.DevServerManager.StopAll.
+func WithTeardown(fns ...func()) RunnerOption {
+	return func(r *AgentRunner) {
+		r.teardown = append(r.teardown, fns...)
+	}
+}
+
 // WithConversationStore sets the conversation store for crash recovery.
 // When set, the runner saves the conversation after every model round
 // and supports resuming from the last saved state.
@@ -58,6 +88,22 @@ func WithProgressTracker(pt *ProgressTracker) RunnerOption {
 	}
 }
 
+// WithSlowToolThresholds overrides the per-tool durations above which a
+// call is logged as slow. Tools not listed use DefaultSlowToolThreshold.
+func WithSlowToolThresholds(thresholds map[string]time.Duration) RunnerOption {
+	return func(r *AgentRunner) {
+		r.slowTools = thresholds
+	}
+}
+
+// WithToolConcurrency sets the per-class limits for parallel tool calls.
+// The given config is merged over DefaultToolConcurrency.
+func WithToolConcurrency(cfg ToolConcurrency) RunnerOption {
+	return func(r *AgentRunner) {
+		r.limiter = newToolLimiter(DefaultToolConcurrency().Merge(cfg))
+	}
+}
+
 // NewAgentRunner creates a new agent runner with the given dependencies.
 // Interfaces are defined by the consumer (this package), not the implementer.
 func NewAgentRunner(
@@ -74,6 +120,9 @@ func NewAgentRunner(
 		config:   config,
 		logger:   slog.Default(),
 		tracker:  NewProgressTracker(),
+
+		slowTools: DefaultSlowToolThresholds(),
+		limiter:   newToolLimiter(DefaultToolConcurrency()),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -96,9 +145,23 @@ func NewAgentRunner(
 // When a ConversationStore is configured, Run saves the conversation after every
 // model round (assistant response + tool results). On the next call, it loads the
 // stored conversation and resumes from the last saved round, enabling crash recovery.
-func (r *AgentRunner) Run(ctx context.Context, task Task) (*Result, error) {
+func (r *AgentRunner) Run(ctx context.Context, task Task) (res *Result, err error) {
 	log := r.logger.With("role", r.config.Role, "thread", task.Thread)
 
+	stats := newToolRecorder()
+	model := r.config.Model // may change per turn under a ModelSelector
+	defer func() {
+		for _, fn := range r.teardown {
+			fn()
+		}
+	}()
+	defer func() {
+		if res != nil {
+			res.ToolStats = stats.snapshot()
+			res.Model = model
+		}
+	}()
+
 	var messages []Message
 	var startTurn int
 
@@ -139,9 +202,13 @@ func (r *AgentRunner) Run(ctx context.Context, task Task) (*Result, error) {
 	// Build conversation from scratch if nothing was loaded
 	if len(messages) == 0 {
 		messages = make([]Message, 0, len(task.Messages)+1)
+		system := r.config.SystemPrompt
+		if roster := RosterPrompt(task.Participants); roster != "" {
+			system += "\n\n---\n\n" + roster
+		}
 		messages = append(messages, Message{
 			Role:    "system",
-			Content: r.config.SystemPrompt,
+			Content: system,
 		})
 		messages = append(messages, task.Messages...)
 	}
@@ -189,8 +256,12 @@ func (r *AgentRunner) Run(ctx context.Context, task Task) (*Result, error) {
 		// --- Context compaction (M7) ---
 		if r.compaction != nil && NeedsCompaction(*r.compaction, totalUsage.TotalTokens) {
 			log.Info("triggering context compaction", "tokens", totalUsage.TotalTokens)
+			summaryModel := r.compaction.Model
+			if summaryModel == "" {
+				summaryModel = r.config.Model
+			}
 			compacted, err := CompactConversation(
-				ctx, r.provider, r.config.Model, messages,
+				ctx, r.provider, summaryModel, messages,
 				r.compaction.RecentKeep, log,
 			)
 			if err != nil {
@@ -200,10 +271,17 @@ func (r *AgentRunner) Run(ctx context.Context, task Task) (*Result, error) {
 			}
 		}
 
+		if r.selector != nil {
+			if next := r.selector.SelectModel(r.config.Role, task.Thread, model); next != model {
+				log.Warn("switching model", "from", model, "to", next, "turn", turn)
+				model = next
+			}
+		}
+
 		log.Info("llm call", "turn", turn, "messages", len(messages))
 
 		resp, err := r.provider.ChatCompletion(ctx, ChatRequest{
-			Model:    r.config.Model,
+			Model:    model,
 			Messages: messages,
 			Tools:    activeTools,
 		})
@@ -238,6 +316,10 @@ func (r *AgentRunner) Run(ctx context.Context, task Task) (*Result, error) {
 			}, nil
 		}
 
+		if r.partial != nil {
+			r.partial.Partial(ctx, task.Channel, task.Thread, formatPartial(resp.Message))
+		}
+
 		// Record tool calls for stuck detection
 		for _, tc := range resp.Message.ToolCalls {
 			r.tracker.RecordToolCall(tc.Name, tc.Arguments)
@@ -246,7 +328,7 @@ func (r *AgentRunner) Run(ctx context.Context, task Task) (*Result, error) {
 
 		// Execute tool calls (parallel when multiple)
 		log.Info("executing tools", "count", len(resp.Message.ToolCalls))
-		results := r.executeToolCalls(ctx, resp.Message.ToolCalls)
+		results := r.executeToolCalls(ctx, resp.Message.ToolCalls, stats)
 		totalToolCalls += len(results)
 
 		// Record errors for stuck detection, and check for progress
@@ -345,7 +427,7 @@ func (r *AgentRunner) applyEscapeStrategy(
 	case EscapeEscalate:
 		log.Warn("escape: escalating to user/PM")
 		summary := describeSignal(signal, r.tracker)
-		msg := EscalationMessage(r.config.Role, summary)
+		msg := EscalationMessage(r.catalog, r.config.Role, summary)
 		// Post escalation to the thread via MessageSender
 		if r.sender != nil {
 			if err := r.sender.SendMessage(ctx, "", "", msg); err != nil {
@@ -385,10 +467,11 @@ func (r *AgentRunner) saveConversation(ctx context.Context, log *slog.Logger, me
 }
 
 // executeToolCalls dispatches tool calls, running them in parallel when there
-// are multiple independent calls in a single LLM response.
-func (r *AgentRunner) executeToolCalls(ctx context.Context, calls []ToolCall) []ToolResult {
+// are multiple independent calls in a single LLM response. Each call waits
+// for a slot in its concurrency class before executing.
+func (r *AgentRunner) executeToolCalls(ctx context.Context, calls []ToolCall, stats *toolRecorder) []ToolResult {
 	if len(calls) == 1 {
-		return []ToolResult{r.executeSingleTool(ctx, calls[0])}
+		return []ToolResult{r.executeSingleTool(ctx, calls[0], stats)}
 	}
 
 	// Parallel execution for multiple tool calls
@@ -398,22 +481,69 @@ func (r *AgentRunner) executeToolCalls(ctx context.Context, calls []ToolCall) []
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			results[i] = r.executeSingleTool(ctx, call)
+			results[i] = r.executeSingleTool(ctx, call, stats)
 		}()
 	}
 	wg.Wait()
 	return results
 }
 
+// toolSchema returns the parameters schema of a listed tool.
+func (r *AgentRunner) toolSchema(name string) (json.RawMessage, bool) {
+	for _, def := range r.executor.ListTools() {
+		if def.Name == name {
+			return def.Parameters, true
+		}
+	}
+	return nil, false
+}
+
 // executeSingleTool executes one tool call, converting executor errors into
-// error ToolResults so the LLM can handle them.
-func (r *AgentRunner) executeSingleTool(ctx context.Context, call ToolCall) ToolResult {
+// error ToolResults so the LLM can handle them. Arguments are validated
+// against the tool's schema first, so malformed calls never reach the tool
+// and the model gets a structured error to self-correct. Latency and failures are
+// recorded in stats; calls over the tool's threshold are logged as slow.
+func (r *AgentRunner) executeSingleTool(ctx context.Context, call ToolCall, stats *toolRecorder) ToolResult {
 	log := r.logger.With("tool", call.Name, "call_id", call.ID)
 	log.Info("tool execute start")
 
+	if schema, ok := r.toolSchema(call.Name); ok {
+		if errs := ValidateToolArgs(schema, call.Arguments); len(errs) > 0 {
+			log.Warn("tool arguments failed validation", "errors", len(errs))
+			stats.record(call.Name, 0, true)
+			return ToolResult{
+				ToolCallID: call.ID,
+				Content:    FormatArgErrors(call.Name, errs, schema),
+				IsError:    true,
+			}
+		}
+	}
+
+	release, err := r.limiter.acquire(ctx, call.Name)
+	if err != nil {
+		return ToolResult{
+			ToolCallID: call.ID,
+			Content:    fmt.Sprintf("error: %s", err),
+			IsError:    true,
+		}
+	}
+	defer release()
+
+	start := time.Now()
 	result, err := r.executor.Execute(ctx, call)
+	elapsed := time.Since(start)
+	stats.record(call.Name, elapsed, err != nil || result.IsError)
+
+	threshold, ok := r.slowTools[call.Name]
+	if !ok {
+		threshold = DefaultSlowToolThreshold
+	}
+	if elapsed > threshold {
+		log.Warn("slow tool call", "duration", elapsed.Round(time.Millisecond), "threshold", threshold)
+	}
+
 	if err != nil {
-		log.Error("tool execute failed", "err", err)
+		log.Error("tool execute failed", "err", err, "duration", elapsed.Round(time.Millisecond))
 		return ToolResult{
 			ToolCallID: call.ID,
 			Content:    fmt.Sprintf("error: %s", err),
@@ -421,6 +551,6 @@ func (r *AgentRunner) executeSingleTool(ctx context.Context, call ToolCall) Tool
 		}
 	}
 
-	log.Info("tool execute done")
+	log.Info("tool execute done", "duration", elapsed.Round(time.Millisecond))
 	return result
 }
@@ -148,6 +148,32 @@ func TestRun_TextResponse(t *testing.T) {
 	}
 }
 
+func TestRun_TeardownRunsOnReturn(t *testing.T) {
+	provider := &mockProvider{
+		responses: []*ChatResponse{
+			{Message: Message{Role: "assistant", Content: "done"}},
+		},
+	}
+	var order []string
+	runner := NewAgentRunner(provider, &discardSender{}, &mockExecutor{}, AgentConfig{
+		Role:     "coder",
+		Model:    "test-model",
+		MaxTurns: 10,
+	}, WithTeardown(
+		func() { order = append(order, "devservers") },
+		func() { order = append(order, "other") },
+	))
+
+	if _, err := runner.Run(context.Background(), Task{
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order) != 2 || order[0] != "devservers" || order[1] != "other" {
+		t.Errorf("teardown order = %v, want [devservers other]", order)
+	}
+}
+
 func TestRun_ToolCallThenTextResponse(t *testing.T) {
 	provider := &mockProvider{
 		responses: []*ChatResponse{
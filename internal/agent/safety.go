@@ -3,6 +3,8 @@ package agent
 import (
 	"crypto/sha256"
 	"fmt"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
 )
 
 // StuckSignal identifies the type of stuck condition detected.
@@ -216,8 +218,9 @@ func ForceReasoningPrompt() string {
 		"approach you haven't tried yet. If you can't think of one, say so."
 }
 
-// EscalationMessage returns the message posted to the thread when all strategies are exhausted.
-func EscalationMessage(role, summary string) string {
+// EscalationMessage returns the message posted to the thread when all
+// strategies are exhausted, rendered from cat; nil uses the default locale.
+func EscalationMessage(cat *messages.Catalog, role, summary string) string {
 	target := "the user"
 	switch role {
 	case "coder":
@@ -227,10 +230,10 @@ func EscalationMessage(role, summary string) string {
 	default:
 		target = "@codebutler.pm"
 	}
-	return fmt.Sprintf(
-		"I'm stuck. Here's what I tried: %s. I need help. Escalating to %s.",
-		summary, target,
-	)
+	return catalogOrDefault(cat).Render(messages.Escalation, map[string]string{
+		"Summary": summary,
+		"Target":  target,
+	})
 }
 
 // hashToolCall produces a deterministic hash of tool name + arguments.
@@ -1,6 +1,10 @@
 package agent
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
 
 func TestProgressTracker_DetectSameToolParams(t *testing.T) {
 	pt := NewProgressTracker()
@@ -209,11 +213,16 @@ func TestEscalationMessage(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		msg := EscalationMessage(tt.role, "tried X three times")
+		msg := EscalationMessage(nil, tt.role, "tried X three times")
 		if msg == "" {
 			t.Errorf("expected non-empty escalation message for role %s", tt.role)
 		}
 	}
+
+	es := EscalationMessage(messages.New("es"), "coder", "probé X tres veces")
+	if es != "Estoy trabado. Esto es lo que intenté: probé X tres veces. Necesito ayuda. Escalando a @codebutler.pm." {
+		t.Errorf("localized escalation = %q", es)
+	}
 }
 
 func TestStuckSignal_String(t *testing.T) {
@@ -0,0 +1,277 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// defaultStaticMaxLines is the changed-line budget for the static fast path.
+const defaultStaticMaxLines = 30
+
+// StaticReviewConfig controls the no-LLM fast path for trivial diffs.
+type StaticReviewConfig struct {
+	MaxChangedLines int      // added+removed lines; above this the LLM reviews (default 30)
+	Checks          []string // shell commands that must pass, e.g. "go build ./...", "go vet ./..."
+}
+
+// CheckRunner runs a repo check command (build, lint) in the worktree.
+type CheckRunner interface {
+	RunCheck(ctx context.Context, command string) (output string, err error)
+}
+
+// StaticReviewer reviews tiny docs/comment/rename diffs with rule-based
+// checks instead of a model call.
+type StaticReviewer struct {
+	config StaticReviewConfig
+	runner CheckRunner // optional; nil skips config.Checks
+}
+
+// NewStaticReviewer creates a static reviewer. runner may be nil when no
+// check commands are configured.
+func NewStaticReviewer(config StaticReviewConfig, runner CheckRunner) *StaticReviewer {
+	if config.MaxChangedLines <= 0 {
+		config.MaxChangedLines = defaultStaticMaxLines
+	}
+	return &StaticReviewer{config: config, runner: runner}
+}
+
+// StaticReview is the outcome of a fast-path review.
+type StaticReview struct {
+	Kind   string        // docs, comments, rename (or a mix, comma-separated)
+	Lines  int           // changed lines
+	Issues []ReviewIssue // empty means approved
+}
+
+// Approved reports whether every static check passed.
+func (s StaticReview) Approved() bool {
+	return len(s.Issues) == 0
+}
+
+// Format renders the review as a reviewer reply.
+func (s StaticReview) Format() string {
+	header := fmt.Sprintf("Static review (%s, %d changed lines, no LLM review needed).", s.Kind, s.Lines)
+	if s.Approved() {
+		return header + "\nNo issues found. LGTM!"
+	}
+	return header + "\n" + FormatReviewFeedback(s.Issues)
+}
+
+// Review classifies diff and, when it is trivial, runs the static checks.
+// ok is false when the diff needs a full LLM review.
+func (sr *StaticReviewer) Review(ctx context.Context, diff string) (review StaticReview, ok bool) {
+	files := ParseDiffFiles(diff)
+	if len(files) == 0 {
+		return StaticReview{}, false
+	}
+
+	lines := 0
+	kinds := map[string]bool{}
+	var order []string
+	for _, f := range files {
+		lines += len(f.Added) + len(f.Removed)
+		kind := f.trivialKind()
+		if kind == "" {
+			return StaticReview{}, false
+		}
+		if !kinds[kind] {
+			kinds[kind] = true
+			order = append(order, kind)
+		}
+	}
+	if lines > sr.config.MaxChangedLines {
+		return StaticReview{}, false
+	}
+
+	review = StaticReview{Kind: strings.Join(order, ", "), Lines: lines}
+	for _, f := range files {
+		review.Issues = append(review.Issues, scanAddedLines(f)...)
+	}
+	if sr.runner != nil {
+		for _, cmd := range sr.config.Checks {
+			if out, err := sr.runner.RunCheck(ctx, cmd); err != nil {
+				review.Issues = append(review.Issues, ReviewIssue{
+					Tag:      "quality",
+					Message:  fmt.Sprintf("`%s` failed: %s", cmd, lastLines(out, err, 5)),
+					Severity: "blocker",
+				})
+			}
+		}
+	}
+	return review, true
+}
+
+// DiffFile is one file's changes in a unified diff.
+type DiffFile struct {
+	Path    string
+	Renamed bool // "rename from/to" header present
+	Added   []string
+	Removed []string
+}
+
+// ParseDiffFiles splits a unified git diff into per-file added and removed
+// lines (without the +/- prefix).
+func ParseDiffFiles(diff string) []DiffFile {
+	var files []DiffFile
+	var cur *DiffFile
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, DiffFile{})
+			cur = &files[len(files)-1]
+			inHunk = false
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				cur.Path = line[i+3:]
+			}
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+			if strings.HasPrefix(line, "rename to ") {
+				cur.Renamed = true
+				cur.Path = strings.TrimPrefix(line, "rename to ")
+			} else if p, ok := strings.CutPrefix(line, "+++ b/"); ok {
+				cur.Path = p
+			}
+		case strings.HasPrefix(line, "+"):
+			cur.Added = append(cur.Added, line[1:])
+		case strings.HasPrefix(line, "-"):
+			cur.Removed = append(cur.Removed, line[1:])
+		}
+	}
+	return files
+}
+
+var docExtensions = map[string]bool{".md": true, ".txt": true, ".rst": true, ".adoc": true}
+
+// trivialKind returns why a file's change is trivial, or "" if it is not.
+func (f DiffFile) trivialKind() string {
+	switch {
+	case docExtensions[strings.ToLower(path.Ext(f.Path))] || strings.HasPrefix(f.Path, "docs/"):
+		return "docs"
+	case f.Renamed && len(f.Added) == 0 && len(f.Removed) == 0:
+		return "rename"
+	case allComments(f.Added) && allComments(f.Removed):
+		return "comments"
+	case identifierRename(f.Removed, f.Added):
+		return "rename"
+	}
+	return ""
+}
+
+func allComments(lines []string) bool {
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		if t == "" {
+			continue
+		}
+		if !strings.HasPrefix(t, "//") && !strings.HasPrefix(t, "#") && !strings.HasPrefix(t, "/*") &&
+			!strings.HasPrefix(t, "*") && !strings.HasPrefix(t, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+var identRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*|[^A-Za-z0-9_\s]+|\d+`)
+
+// identifierRename reports whether removed and added pair up line by line
+// and differ only by consistently replacing identifiers (old -> new).
+func identifierRename(removed, added []string) bool {
+	if len(removed) == 0 || len(removed) != len(added) {
+		return false
+	}
+	mapping := map[string]string{}
+	kept := map[string]bool{}
+	for i := range removed {
+		a, b := identRe.FindAllString(removed[i], -1), identRe.FindAllString(added[i], -1)
+		if len(a) != len(b) {
+			return false
+		}
+		for j := range a {
+			if a[j] == b[j] {
+				kept[a[j]] = true
+				continue
+			}
+			if !isIdent(a[j]) || !isIdent(b[j]) {
+				return false
+			}
+			if prev, ok := mapping[a[j]]; ok && prev != b[j] {
+				return false
+			}
+			mapping[a[j]] = b[j]
+		}
+	}
+	for old := range mapping {
+		if kept[old] {
+			return false // renamed in one place but not another
+		}
+	}
+	return len(mapping) > 0
+}
+
+// behaviorWords change meaning when swapped, so they never count as renames.
+var behaviorWords = map[string]bool{
+	"true": true, "false": true, "nil": true, "null": true, "None": true, "True": true, "False": true,
+	"if": true, "for": true, "return": true, "break": true, "continue": true, "go": true, "defer": true,
+	"and": true, "or": true, "not": true,
+}
+
+func isIdent(tok string) bool {
+	c := tok[0]
+	return (c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) && !behaviorWords[tok]
+}
+
+var (
+	todoRe   = regexp.MustCompile(`\b(TODO|FIXME|XXX|HACK)\b`)
+	secretRe = []*regexp.Regexp{
+		regexp.MustCompile(`sk-[a-zA-Z0-9]{20,}`),
+		regexp.MustCompile(`xox[bpa]-[a-zA-Z0-9-]{10,}`),
+		regexp.MustCompile(`gh[po]_[a-zA-Z0-9]{36,}`),
+		regexp.MustCompile(`AKIA[A-Z0-9]{16}`),
+		regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----`),
+		regexp.MustCompile(`(?i)(password|secret|api[_-]?key|token)\s*[:=]\s*["'][^"'\s]{8,}["']`),
+	}
+)
+
+// scanAddedLines flags TODOs, secrets, and merge conflict markers added by
+// the diff.
+func scanAddedLines(f DiffFile) []ReviewIssue {
+	var issues []ReviewIssue
+	for _, l := range f.Added {
+		switch {
+		case strings.HasPrefix(l, "<<<<<<< ") || strings.HasPrefix(l, ">>>>>>> ") || l == "=======":
+			issues = append(issues, ReviewIssue{Tag: "quality", File: f.Path, Message: "merge conflict marker added", Severity: "blocker"})
+		case matchesAny(secretRe, l):
+			issues = append(issues, ReviewIssue{Tag: "security", File: f.Path, Message: "possible secret added", Severity: "blocker"})
+		case todoRe.MatchString(l):
+			issues = append(issues, ReviewIssue{Tag: "quality", File: f.Path, Message: "new " + todoRe.FindString(l) + " added: " + truncate(strings.TrimSpace(l), 80), Severity: "warning"})
+		}
+	}
+	return issues
+}
+
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func lastLines(out string, err error, n int) string {
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return err.Error()
+	}
+	lines := strings.Split(out, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, " / ")
+}
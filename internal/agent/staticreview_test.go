@@ -0,0 +1,137 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const docsDiff = `diff --git a/README.md b/README.md
+index 1111111..2222222 100644
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,3 @@
+ # Project
+-Instal with make.
++Install with make.
+`
+
+const commentDiff = `diff --git a/db/schema.sql b/db/schema.sql
+--- a/db/schema.sql
++++ b/db/schema.sql
+@@ -1,2 +1,2 @@
+--- old note
++-- new note
+ CREATE TABLE t (id int);
+`
+
+const renameDiff = `diff --git a/internal/auth/login.go b/internal/auth/login.go
+--- a/internal/auth/login.go
++++ b/internal/auth/login.go
+@@ -10,4 +10,4 @@
+-func chk(u User) error {
+-	return validate(u, chkLimit)
++func checkUser(u User) error {
++	return validate(u, checkUserLimit)
+ }
+`
+
+const logicDiff = `diff --git a/internal/auth/login.go b/internal/auth/login.go
+--- a/internal/auth/login.go
++++ b/internal/auth/login.go
+@@ -10,3 +10,3 @@
+-	if retries > 3 {
++	if retries > 5 {
+`
+
+type fakeChecks struct {
+	fail map[string]string
+	ran  []string
+}
+
+func (f *fakeChecks) RunCheck(_ context.Context, cmd string) (string, error) {
+	f.ran = append(f.ran, cmd)
+	if out, ok := f.fail[cmd]; ok {
+		return out, errors.New("exit status 1")
+	}
+	return "", nil
+}
+
+func TestStaticReviewer_Classifies(t *testing.T) {
+	sr := NewStaticReviewer(StaticReviewConfig{}, nil)
+	tests := []struct {
+		name string
+		diff string
+		ok   bool
+		kind string
+	}{
+		{"docs", docsDiff, true, "docs"},
+		{"sql comment", commentDiff, true, "comments"},
+		{"identifier rename", renameDiff, true, "rename"},
+		{"logic change", logicDiff, false, ""},
+		{"mixed", docsDiff + logicDiff, false, ""},
+		{"bool flip", strings.Replace(renameDiff, "chkLimit)", "true)", 1), false, ""},
+		{"empty", "", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review, ok := sr.Review(context.Background(), tt.diff)
+			if ok != tt.ok || review.Kind != tt.kind {
+				t.Errorf("got ok=%v kind=%q", ok, review.Kind)
+			}
+		})
+	}
+}
+
+func TestStaticReviewer_SizeThreshold(t *testing.T) {
+	sr := NewStaticReviewer(StaticReviewConfig{MaxChangedLines: 1}, nil)
+	if _, ok := sr.Review(context.Background(), docsDiff); ok {
+		t.Error("2 changed lines should exceed a threshold of 1")
+	}
+}
+
+func TestStaticReviewer_Checks(t *testing.T) {
+	diff := strings.Replace(docsDiff, "+Install with make.", "+Install with make. TODO: windows\n+token = \"abcd1234efgh\"", 1)
+	checks := &fakeChecks{fail: map[string]string{"go vet ./...": "line1\nvet: bad printf"}}
+	sr := NewStaticReviewer(StaticReviewConfig{Checks: []string{"go build ./...", "go vet ./..."}}, checks)
+
+	review, ok := sr.Review(context.Background(), diff)
+	if !ok {
+		t.Fatal("docs diff should take the fast path")
+	}
+	if len(checks.ran) != 2 {
+		t.Errorf("ran %v", checks.ran)
+	}
+	counts := CountByTag(review.Issues)
+	if counts["security"] != 1 || counts["quality"] != 2 || !HasBlockers(review.Issues) {
+		t.Errorf("issues = %+v", review.Issues)
+	}
+	if !strings.Contains(review.Format(), "vet: bad printf") {
+		t.Errorf("format = %q", review.Format())
+	}
+}
+
+func TestReviewerRunner_StaticFastPath(t *testing.T) {
+	provider := &mockProvider{responses: []*ChatResponse{
+		{Message: Message{Role: "assistant", Content: "Full review."}},
+	}}
+	r := NewReviewerRunner(provider, &discardSender{}, &mockExecutor{}, DefaultReviewerConfig(), "You review.",
+		WithStaticReview(NewStaticReviewer(StaticReviewConfig{}, nil)))
+
+	res, err := r.ReviewWithDiff(context.Background(), docsDiff, "fix-typo", "C", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.requests) != 0 || !strings.Contains(res.Response, "LGTM") {
+		t.Errorf("expected static LGTM without LLM, got %q after %d calls", res.Response, len(provider.requests))
+	}
+
+	res, err = r.ReviewWithDiff(context.Background(), logicDiff, "fix-typo", "C", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.requests) != 1 || res.Response != "Full review." {
+		t.Errorf("logic change should get the LLM review, got %q", res.Response)
+	}
+}
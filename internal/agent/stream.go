@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+	"strings"
+)
+
+// PartialSink receives an agent's intermediate output while a run is in
+// progress, so a messenger can show live progress instead of a single reply
+// at the end.
+type PartialSink interface {
+	Partial(ctx context.Context, channel, thread, text string)
+}
+
+// WithPartialSink streams each intermediate model round (any text the model
+// wrote plus the tools it is calling) to sink. The final response is still
+// returned in Result; the caller decides how to present it.
+func WithPartialSink(sink PartialSink) RunnerOption {
+	return func(r *AgentRunner) {
+		r.partial = sink
+	}
+}
+
+// formatPartial renders a tool-calling round for a PartialSink.
+func formatPartial(msg Message) string {
+	var b strings.Builder
+	if text := strings.TrimSpace(msg.Content); text != "" {
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	names := make([]string, 0, len(msg.ToolCalls))
+	for _, tc := range msg.ToolCalls {
+		names = append(names, "`"+tc.Name+"`")
+	}
+	b.WriteString("_running " + strings.Join(names, ", ") + "…_")
+	return b.String()
+}
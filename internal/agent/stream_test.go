@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+	"testing"
+)
+
+type recordingSink struct {
+	partials []string
+}
+
+func (s *recordingSink) Partial(_ context.Context, channel, thread, text string) {
+	s.partials = append(s.partials, channel+"/"+thread+": "+text)
+}
+
+func TestRun_StreamsPartialOutput(t *testing.T) {
+	provider := &mockProvider{
+		responses: []*ChatResponse{
+			{Message: Message{
+				Role:      "assistant",
+				Content:   "Let me look at the handler.",
+				ToolCalls: []ToolCall{{ID: "c1", Name: "Read", Arguments: `{"path":"main.go"}`}, {ID: "c2", Name: "Grep", Arguments: `{}`}},
+			}},
+			{Message: Message{Role: "assistant", Content: "Found it."}},
+		},
+	}
+	executor := &mockExecutor{
+		results:  map[string]ToolResult{"Read": {Content: "package main"}, "Grep": {Content: "none"}},
+		toolDefs: []ToolDefinition{{Name: "Read"}, {Name: "Grep"}},
+	}
+	sink := &recordingSink{}
+	runner := NewAgentRunner(provider, &discardSender{}, executor,
+		AgentConfig{Role: "coder", Model: "m", MaxTurns: 5}, WithPartialSink(sink))
+
+	result, err := runner.Run(context.Background(), Task{
+		Messages: []Message{{Role: "user", Content: "fix it"}},
+		Channel:  "C1",
+		Thread:   "100.1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Response != "Found it." {
+		t.Errorf("response = %q", result.Response)
+	}
+
+	want := "C1/100.1: Let me look at the handler.\n_running `Read`, `Grep`…_"
+	if len(sink.partials) != 1 || sink.partials[0] != want {
+		t.Errorf("partials = %q", sink.partials)
+	}
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ToolStats aggregates executions of one tool.
+type ToolStats struct {
+	Calls    int           `json:"calls"`
+	Failures int           `json:"failures"`
+	Total    time.Duration `json:"total_ns"`
+	Max      time.Duration `json:"max_ns"`
+}
+
+// Avg returns the mean execution time.
+func (s ToolStats) Avg() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+// FailureRate returns the fraction of calls that failed (0..1).
+func (s ToolStats) FailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Calls)
+}
+
+func (s ToolStats) add(o ToolStats) ToolStats {
+	s.Calls += o.Calls
+	s.Failures += o.Failures
+	s.Total += o.Total
+	s.Max = max(s.Max, o.Max)
+	return s
+}
+
+// DefaultSlowToolThreshold applies to tools without a specific threshold.
+const DefaultSlowToolThreshold = 30 * time.Second
+
+// DefaultSlowToolThresholds are per-tool limits above which a call is
+// logged as slow. Commands that run builds and tests get more headroom.
+func DefaultSlowToolThresholds() map[string]time.Duration {
+	return map[string]time.Duration{
+		"Bash":      2 * time.Minute,
+		"RunTarget": 5 * time.Minute,
+	}
+}
+
+// toolRecorder collects per-tool stats for a single Run. Safe for the
+// parallel tool executions within a round.
+type toolRecorder struct {
+	mu    sync.Mutex
+	stats map[string]ToolStats
+}
+
+func newToolRecorder() *toolRecorder {
+	return &toolRecorder{stats: make(map[string]ToolStats)}
+}
+
+func (r *toolRecorder) record(name string, d time.Duration, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := ToolStats{Calls: 1, Total: d, Max: d}
+	if failed {
+		s.Failures = 1
+	}
+	r.stats[name] = r.stats[name].add(s)
+}
+
+func (r *toolRecorder) snapshot() map[string]ToolStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.stats) == 0 {
+		return nil
+	}
+	out := make(map[string]ToolStats, len(r.stats))
+	for k, v := range r.stats {
+		out[k] = v
+	}
+	return out
+}
+
+// MergeToolStats sums per-tool stats across agents.
+func MergeToolStats(all ...map[string]ToolStats) map[string]ToolStats {
+	out := make(map[string]ToolStats)
+	for _, m := range all {
+		for name, s := range m {
+			out[name] = out[name].add(s)
+		}
+	}
+	return out
+}
+
+// FormatToolBreakdown renders per-tool time as a markdown table, sorted by
+// total time so the dominant tools come first.
+func FormatToolBreakdown(stats map[string]ToolStats) string {
+	if len(stats) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(stats))
+	var total time.Duration
+	for name, s := range stats {
+		names = append(names, name)
+		total += s.Total
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if stats[names[i]].Total != stats[names[j]].Total {
+			return stats[names[i]].Total > stats[names[j]].Total
+		}
+		return names[i] < names[j]
+	})
+
+	var b strings.Builder
+	b.WriteString("| Tool | Calls | Failed | Total | Avg | Max | Share |\n")
+	b.WriteString("|------|-------|--------|-------|-----|-----|-------|\n")
+	for _, name := range names {
+		s := stats[name]
+		share := 0.0
+		if total > 0 {
+			share = float64(s.Total) / float64(total) * 100
+		}
+		b.WriteString(fmt.Sprintf("| %s | %d | %d | %s | %s | %s | %.0f%% |\n",
+			name, s.Calls, s.Failures, roundDuration(s.Total), roundDuration(s.Avg()), roundDuration(s.Max), share))
+	}
+	return b.String()
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(100 * time.Millisecond)
+	}
+	return d.Round(time.Millisecond)
+}
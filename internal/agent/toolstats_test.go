@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_RecordsToolStats(t *testing.T) {
+	provider := &mockProvider{
+		responses: []*ChatResponse{
+			{Message: Message{Role: "assistant", ToolCalls: []ToolCall{
+				{ID: "c1", Name: "Read", Arguments: `{"path":"a.go"}`},
+				{ID: "c2", Name: "Bash", Arguments: `{"command":"go test ./..."}`},
+			}}},
+			{Message: Message{Role: "assistant", ToolCalls: []ToolCall{
+				{ID: "c3", Name: "Read", Arguments: `{"path":"b.go"}`},
+			}}},
+			{Message: Message{Role: "assistant", Content: "done"}},
+		},
+	}
+	executor := &mockExecutor{
+		errTools: map[string]error{"Bash": fmt.Errorf("exit status 1")},
+	}
+
+	var logs bytes.Buffer
+	runner := NewAgentRunner(provider, &discardSender{}, executor, AgentConfig{Role: "coder", MaxTurns: 5},
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithSlowToolThresholds(map[string]time.Duration{"Read": -1}), // every Read is "slow"
+	)
+
+	result, err := runner.Run(context.Background(), Task{Messages: []Message{{Role: "user", Content: "go"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, bash := result.ToolStats["Read"], result.ToolStats["Bash"]
+	if read.Calls != 2 || read.Failures != 0 {
+		t.Errorf("Read stats = %+v", read)
+	}
+	if bash.Calls != 1 || bash.Failures != 1 || bash.FailureRate() != 1 {
+		t.Errorf("Bash stats = %+v", bash)
+	}
+	if n := strings.Count(logs.String(), "slow tool call"); n != 2 {
+		t.Errorf("expected 2 slow-call warnings (Read only), got %d", n)
+	}
+}
+
+func TestToolStats_MergeAndFormat(t *testing.T) {
+	coder := map[string]ToolStats{
+		"Bash": {Calls: 3, Failures: 1, Total: 90 * time.Second, Max: 60 * time.Second},
+		"Read": {Calls: 10, Total: 50 * time.Millisecond, Max: 10 * time.Millisecond},
+	}
+	reviewer := map[string]ToolStats{
+		"Bash": {Calls: 1, Total: 30 * time.Second, Max: 30 * time.Second},
+	}
+
+	merged := MergeToolStats(coder, reviewer)
+	if b := merged["Bash"]; b.Calls != 4 || b.Total != 2*time.Minute || b.Max != time.Minute || b.Avg() != 30*time.Second {
+		t.Errorf("merged Bash = %+v", b)
+	}
+
+	table := FormatToolBreakdown(merged)
+	if strings.Index(table, "| Bash") > strings.Index(table, "| Read") {
+		t.Errorf("tools should be sorted by total time:\n%s", table)
+	}
+	if !strings.Contains(table, "| Bash | 4 | 1 | 2m0s | 30s | 1m0s | 100% |") {
+		t.Errorf("unexpected Bash row:\n%s", table)
+	}
+	if FormatToolBreakdown(nil) != "" {
+		t.Error("empty stats should render nothing")
+	}
+}
+
+func TestFormatUsageReport_ToolTime(t *testing.T) {
+	report := NewThreadReport("T1", map[string]*Result{
+		"coder": {TurnsUsed: 3, ToolStats: map[string]ToolStats{"Bash": {Calls: 2, Total: time.Minute, Max: 40 * time.Second}}},
+	})
+	out := FormatUsageReport(report)
+	if !strings.Contains(out, "### Tool Time") || !strings.Contains(out, "| Bash | 2 |") {
+		t.Errorf("missing tool breakdown:\n%s", out)
+	}
+}
@@ -45,6 +45,7 @@ type ChatRequest struct {
 
 // ChatResponse is the LLM's response.
 type ChatResponse struct {
+	ID      string     `json:"id,omitempty"` // provider generation ID, when the provider has one
 	Message Message    `json:"message"`
 	Usage   TokenUsage `json:"usage"`
 }
@@ -58,19 +59,22 @@ type TokenUsage struct {
 
 // Task represents work for the agent to perform.
 type Task struct {
-	Messages []Message // Messages to process (user input, agent mentions, etc.)
-	Channel  string    // Communication channel ID (e.g., Slack channel)
-	Thread   string    // Thread ID for threaded conversations
+	Messages     []Message     // Messages to process (user input, agent mentions, etc.)
+	Channel      string        // Communication channel ID (e.g., Slack channel)
+	Thread       string        // Thread ID for threaded conversations
+	Participants []Participant // People in the thread; two or more adds a roster to the system prompt
 }
 
 // Result represents the outcome of an agent run.
 type Result struct {
-	Response      string     // Final text response (empty if max turns reached)
-	TurnsUsed     int        // Number of LLM calls made
-	TokenUsage    TokenUsage // Cumulative token usage across all turns
-	ToolCalls     int        // Total number of tool calls executed
-	LoopsDetected int        // Number of stuck conditions detected during the run
-	Escalated     bool       // True if the agent escalated (all escape strategies exhausted)
+	Response      string               // Final text response (empty if max turns reached)
+	TurnsUsed     int                  // Number of LLM calls made
+	TokenUsage    TokenUsage           // Cumulative token usage across all turns
+	ToolCalls     int                  // Total number of tool calls executed
+	LoopsDetected int                  // Number of stuck conditions detected during the run
+	Escalated     bool                 // True if the agent escalated (all escape strategies exhausted)
+	ToolStats     map[string]ToolStats // Per-tool latency and failures for this run
+	Model         string               // Model used for the last turn (differs from config after a downgrade)
 }
 
 // AgentConfig configures an agent runner instance.
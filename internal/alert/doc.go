@@ -0,0 +1,6 @@
+// Package alert pushes fatal-state notifications to an out-of-band
+// escalation channel (Slack incoming webhook, Pushover, ntfy, or email) so
+// the owner learns about a dead agent even when the main Slack connection is
+// the thing that broke. The same channels can optionally carry task
+// lifecycle pushes (done, approval needed, failed) via TaskNotifier.
+package alert
@@ -0,0 +1,512 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// Severity ranks an alert.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert is a single escalation.
+type Alert struct {
+	Key         string // dedup key, e.g. "crash-loop:coder"
+	Severity    Severity
+	Role        string            // agent role that raised the alert
+	Title       string            // one-line summary
+	Message     string            // details
+	Diagnostics map[string]string // attached key/value diagnostics
+	Time        time.Time
+}
+
+// Text renders the alert as plain text, diagnostics sorted by key.
+func (a Alert) Text() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[%s] CodeButler %s: %s\n", strings.ToUpper(string(a.Severity)), a.Role, a.Title))
+	if a.Message != "" {
+		b.WriteString(a.Message)
+		b.WriteString("\n")
+	}
+	if len(a.Diagnostics) > 0 {
+		keys := make([]string, 0, len(a.Diagnostics))
+		for k := range a.Diagnostics {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("\nDiagnostics:\n")
+		for _, k := range keys {
+			b.WriteString(fmt.Sprintf("  %s: %s\n", k, a.Diagnostics[k]))
+		}
+	}
+	return b.String()
+}
+
+// Diagnostics returns process-level diagnostics to attach to an alert.
+func Diagnostics(started time.Time) map[string]string {
+	host, _ := os.Hostname()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return map[string]string{
+		"host":       host,
+		"pid":        fmt.Sprintf("%d", os.Getpid()),
+		"go":         runtime.Version(),
+		"goroutines": fmt.Sprintf("%d", runtime.NumGoroutine()),
+		"heap_mb":    fmt.Sprintf("%.1f", float64(mem.HeapAlloc)/(1024*1024)),
+		"uptime":     time.Since(started).Round(time.Second).String(),
+	}
+}
+
+// Notifier delivers an alert to one escalation channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, a Alert) error
+}
+
+// HTTPDoer abstracts the HTTP client for testing.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// SlackWebhook posts alerts to a Slack incoming webhook. It deliberately
+// does not use the bot token: the webhook still works when the bot's
+// Socket Mode session is down.
+type SlackWebhook struct {
+	URL    string
+	Client HTTPDoer
+}
+
+// Name implements Notifier.
+func (s *SlackWebhook) Name() string { return "slack-webhook" }
+
+// Notify implements Notifier.
+func (s *SlackWebhook) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(map[string]string{"text": "```\n" + a.Text() + "```"})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(s.Client, req)
+}
+
+// Pushover sends alerts through the Pushover API.
+type Pushover struct {
+	Token   string // application token
+	User    string // user or group key
+	BaseURL string // default https://api.pushover.net/1/messages.json
+	Client  HTTPDoer
+}
+
+// Name implements Notifier.
+func (p *Pushover) Name() string { return "pushover" }
+
+// Notify implements Notifier.
+func (p *Pushover) Notify(ctx context.Context, a Alert) error {
+	endpoint := p.BaseURL
+	if endpoint == "" {
+		endpoint = "https://api.pushover.net/1/messages.json"
+	}
+
+	form := url.Values{}
+	form.Set("token", p.Token)
+	form.Set("user", p.User)
+	form.Set("title", fmt.Sprintf("CodeButler %s: %s", a.Role, a.Title))
+	form.Set("message", truncate(a.Text(), 1024)) // Pushover message limit
+	if a.Severity == SeverityCritical {
+		form.Set("priority", "1")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create pushover request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doRequest(p.Client, req)
+}
+
+// Ntfy publishes alerts to an ntfy topic, which fans out to the ntfy
+// mobile and web apps.
+type Ntfy struct {
+	Server string // default https://ntfy.sh
+	Topic  string
+	Token  string // optional bearer token
+	Client HTTPDoer
+}
+
+// Name implements Notifier.
+func (n *Ntfy) Name() string { return "ntfy" }
+
+// Notify implements Notifier.
+func (n *Ntfy) Notify(ctx context.Context, a Alert) error {
+	server := n.Server
+	if server == "" {
+		server = "https://ntfy.sh"
+	}
+	endpoint := strings.TrimRight(server, "/") + "/" + url.PathEscape(n.Topic)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(truncate(a.Text(), 4096)))
+	if err != nil {
+		return fmt.Errorf("create ntfy request: %w", err)
+	}
+	req.Header.Set("Title", fmt.Sprintf("CodeButler %s: %s", a.Role, a.Title))
+	switch a.Severity {
+	case SeverityCritical:
+		req.Header.Set("Priority", "urgent")
+		req.Header.Set("Tags", "rotating_light")
+	case SeverityWarning:
+		req.Header.Set("Priority", "high")
+		req.Header.Set("Tags", "warning")
+	default:
+		req.Header.Set("Tags", "robot")
+	}
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+	return doRequest(n.Client, req)
+}
+
+// SendMailFunc is smtp.SendMail with a context; injectable for tests.
+type SendMailFunc func(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// smtpTimeout bounds an SMTP session when ctx carries no deadline.
+const smtpTimeout = 30 * time.Second
+
+// Email sends alerts over SMTP.
+type Email struct {
+	Addr     string // host:port
+	Username string
+	Password string
+	From     string
+	To       []string
+	SendMail SendMailFunc // defaults to sendMail
+}
+
+// Name implements Notifier.
+func (e *Email) Name() string { return "email" }
+
+// Notify implements Notifier.
+func (e *Email) Notify(ctx context.Context, a Alert) error {
+	send := e.SendMail
+	if send == nil {
+		send = sendMail
+	}
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		host := e.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	date := a.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+	subject := mime.QEncoding.Encode("utf-8", fmt.Sprintf("[CodeButler %s] %s", a.Role, a.Title))
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n"+
+		"MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		e.From, strings.Join(e.To, ", "), subject, date.Format(time.RFC1123Z),
+		strings.ReplaceAll(a.Text(), "\n", "\r\n"))
+
+	if err := send(ctx, e.Addr, auth, e.From, e.To, []byte(msg)); err != nil {
+		return fmt.Errorf("send alert email: %w", err)
+	}
+	return nil
+}
+
+// sendMail is smtp.SendMail with the dial bound to ctx and a deadline on
+// the whole session, so an unresponsive server cannot hang an escalation.
+func sendMail(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp server %s does not support AUTH", host)
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func doRequest(client HTTPDoer, req *http.Request) error {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// truncate caps s at maxLen bytes without splitting a UTF-8 rune.
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	cut := maxLen - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// Escalator fans an alert out to every configured notifier and suppresses
+// repeats of the same key within a cooldown window, so a crash loop pages
+// the owner once rather than every few seconds.
+type Escalator struct {
+	notifiers []Notifier
+	cooldown  time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu   sync.Mutex
+	sent map[string]time.Time // key → last sent
+}
+
+// EscalatorOption configures an Escalator.
+type EscalatorOption func(*Escalator)
+
+// WithCooldown sets the per-key suppression window (default 1h).
+func WithCooldown(d time.Duration) EscalatorOption {
+	return func(e *Escalator) {
+		e.cooldown = d
+	}
+}
+
+// WithAlertLogger sets the logger.
+func WithAlertLogger(l *slog.Logger) EscalatorOption {
+	return func(e *Escalator) {
+		e.logger = l
+	}
+}
+
+// WithAlertClock sets the clock (for testing).
+func WithAlertClock(now func() time.Time) EscalatorOption {
+	return func(e *Escalator) {
+		e.now = now
+	}
+}
+
+// NewEscalator creates an escalator over the given notifiers.
+func NewEscalator(notifiers []Notifier, opts ...EscalatorOption) *Escalator {
+	e := &Escalator{
+		notifiers: notifiers,
+		cooldown:  time.Hour,
+		logger:    slog.Default(),
+		now:       time.Now,
+		sent:      make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Enabled reports whether any escalation channel is configured.
+func (e *Escalator) Enabled() bool {
+	return len(e.notifiers) > 0
+}
+
+// Escalate sends the alert through every notifier. It returns false without
+// sending if the same key was delivered within the cooldown. Delivery
+// failures on individual channels are logged; an error is returned only if
+// every channel failed, and then the key is not put on cooldown, so the
+// next attempt retries instead of being suppressed.
+func (e *Escalator) Escalate(ctx context.Context, a Alert) (bool, error) {
+	if a.Time.IsZero() {
+		a.Time = e.now()
+	}
+	if a.Severity == "" {
+		a.Severity = SeverityCritical
+	}
+	if len(e.notifiers) == 0 {
+		return false, nil
+	}
+
+	// Reserve the key before sending so concurrent callers do not send the
+	// same alert twice; release it below if nothing was delivered.
+	var prev time.Time
+	var hadPrev bool
+	e.mu.Lock()
+	if a.Key != "" {
+		prev, hadPrev = e.sent[a.Key]
+		if hadPrev && a.Time.Sub(prev) < e.cooldown {
+			e.mu.Unlock()
+			e.logger.Debug("alert suppressed by cooldown", "key", a.Key)
+			return false, nil
+		}
+		e.sent[a.Key] = a.Time
+	}
+	e.mu.Unlock()
+
+	var errs []string
+	for _, n := range e.notifiers {
+		if err := n.Notify(ctx, a); err != nil {
+			e.logger.Error("alert delivery failed", "channel", n.Name(), "key", a.Key, "err", err)
+			errs = append(errs, fmt.Sprintf("%s: %v", n.Name(), err))
+			continue
+		}
+		e.logger.Info("alert delivered", "channel", n.Name(), "key", a.Key)
+	}
+
+	if len(errs) == len(e.notifiers) {
+		if a.Key != "" {
+			e.mu.Lock()
+			if e.sent[a.Key].Equal(a.Time) { // still our reservation
+				if hadPrev {
+					e.sent[a.Key] = prev
+				} else {
+					delete(e.sent, a.Key)
+				}
+			}
+			e.mu.Unlock()
+		}
+		return true, fmt.Errorf("all alert channels failed: %s", strings.Join(errs, "; "))
+	}
+	return true, nil
+}
+
+// CrashLoopDetector counts restarts within a sliding window and reports
+// when the threshold is crossed.
+type CrashLoopDetector struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	restarts []time.Time
+}
+
+// NewCrashLoopDetector creates a detector that trips after threshold
+// restarts within window.
+func NewCrashLoopDetector(threshold int, window time.Duration, now func() time.Time) *CrashLoopDetector {
+	if now == nil {
+		now = time.Now
+	}
+	return &CrashLoopDetector{threshold: threshold, window: window, now: now}
+}
+
+// RecordRestart records a restart and returns true if the process is now
+// in a crash loop.
+func (d *CrashLoopDetector) RecordRestart() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	cutoff := now.Add(-d.window)
+	kept := d.restarts[:0]
+	for _, t := range d.restarts {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	d.restarts = append(kept, now)
+	return len(d.restarts) >= d.threshold
+}
+
+// NotifiersFromConfig builds the notifiers enabled in the global config.
+func NotifiersFromConfig(cfg config.GlobalAlerts) []Notifier {
+	var ns []Notifier
+	if cfg.SlackWebhookURL != "" {
+		ns = append(ns, &SlackWebhook{URL: cfg.SlackWebhookURL})
+	}
+	if cfg.Pushover != nil && cfg.Pushover.Token != "" && cfg.Pushover.User != "" {
+		ns = append(ns, &Pushover{Token: cfg.Pushover.Token, User: cfg.Pushover.User})
+	}
+	if cfg.Ntfy != nil && cfg.Ntfy.Topic != "" {
+		ns = append(ns, &Ntfy{Server: cfg.Ntfy.Server, Topic: cfg.Ntfy.Topic, Token: cfg.Ntfy.Token})
+	}
+	if cfg.Email != nil && cfg.Email.SMTPAddr != "" && len(cfg.Email.To) > 0 {
+		ns = append(ns, &Email{
+			Addr:     cfg.Email.SMTPAddr,
+			Username: cfg.Email.Username,
+			Password: cfg.Email.Password,
+			From:     cfg.Email.From,
+			To:       cfg.Email.To,
+		})
+	}
+	return ns
+}
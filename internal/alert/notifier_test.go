@@ -0,0 +1,360 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+type recordingDoer struct {
+	reqs   []*http.Request
+	bodies []string
+	status int
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	d.reqs = append(d.reqs, req)
+	d.bodies = append(d.bodies, string(body))
+	status := d.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+}
+
+type fakeNotifier struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (f *fakeNotifier) Name() string { return f.name }
+func (f *fakeNotifier) Notify(_ context.Context, _ Alert) error {
+	f.calls++
+	return f.err
+}
+
+func testAlert() Alert {
+	return Alert{
+		Key:         "crash-loop:coder",
+		Severity:    SeverityCritical,
+		Role:        "coder",
+		Title:       "crash loop detected",
+		Message:     "restarted 5 times in 10m",
+		Diagnostics: map[string]string{"pid": "42", "host": "box"},
+	}
+}
+
+func TestAlert_Text(t *testing.T) {
+	text := testAlert().Text()
+	if !strings.Contains(text, "[CRITICAL] CodeButler coder: crash loop detected") {
+		t.Errorf("missing header: %s", text)
+	}
+	if strings.Index(text, "host: box") > strings.Index(text, "pid: 42") {
+		t.Error("diagnostics should be sorted by key")
+	}
+}
+
+func TestSlackWebhook_Notify(t *testing.T) {
+	doer := &recordingDoer{}
+	n := &SlackWebhook{URL: "https://hooks.slack.com/services/x", Client: doer}
+
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doer.reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(doer.reqs))
+	}
+	if !strings.Contains(doer.bodies[0], "crash loop detected") {
+		t.Errorf("payload missing title: %s", doer.bodies[0])
+	}
+}
+
+func TestSlackWebhook_ErrorStatus(t *testing.T) {
+	n := &SlackWebhook{URL: "https://hooks.slack.com/services/x", Client: &recordingDoer{status: 404}}
+	if err := n.Notify(context.Background(), testAlert()); err == nil {
+		t.Error("expected error for non-2xx status")
+	}
+}
+
+func TestPushover_Notify(t *testing.T) {
+	doer := &recordingDoer{}
+	n := &Pushover{Token: "tok", User: "usr", Client: doer}
+
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := doer.bodies[0]
+	for _, want := range []string{"token=tok", "user=usr", "priority=1"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q: %s", want, body)
+		}
+	}
+}
+
+func TestEmail_Notify(t *testing.T) {
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	n := &Email{
+		Addr: "smtp.example.com:587",
+		From: "bot@example.com",
+		To:   []string{"owner@example.com"},
+		SendMail: func(_ context.Context, addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			gotAddr, gotTo, gotMsg = addr, to, string(msg)
+			return nil
+		},
+	}
+
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || len(gotTo) != 1 {
+		t.Errorf("unexpected envelope: %s %v", gotAddr, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: [CodeButler coder] crash loop detected\r\n") {
+		t.Errorf("missing subject: %s", gotMsg)
+	}
+	for _, h := range []string{"\r\nDate: ", "\r\nMIME-Version: 1.0\r\n", "\r\nContent-Type: text/plain; charset=UTF-8\r\n"} {
+		if !strings.Contains(gotMsg, h) {
+			t.Errorf("missing header %q: %s", h, gotMsg)
+		}
+	}
+}
+
+func TestEmail_NotifyEncodesSubject(t *testing.T) {
+	var gotMsg string
+	n := &Email{
+		Addr: "smtp.example.com:587",
+		From: "bot@example.com",
+		To:   []string{"owner@example.com"},
+		SendMail: func(_ context.Context, _ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+			gotMsg = string(msg)
+			return nil
+		},
+	}
+	a := testAlert()
+	a.Title = "déploiement échoué"
+
+	if err := n.Notify(context.Background(), a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	header, _, _ := strings.Cut(gotMsg, "\r\n\r\n")
+	if !strings.Contains(header, "Subject: =?utf-8?q?") {
+		t.Errorf("subject not RFC 2047 encoded: %s", header)
+	}
+	for _, r := range header {
+		if r > 127 {
+			t.Fatalf("non-ASCII byte in headers: %s", header)
+		}
+	}
+}
+
+func TestSendMail_HonorsContext(t *testing.T) {
+	// A server that accepts but never greets would hang smtp.SendMail.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := sendMail(ctx, ln.Addr().String(), nil, "a@b", []string{"c@d"}, []byte("x")); err == nil {
+		t.Fatal("expected an error from a silent server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("sendMail took %s, want it bounded by the context", elapsed)
+	}
+}
+
+func TestTruncate_RuneSafe(t *testing.T) {
+	got := truncate(strings.Repeat("é", 10), 8)
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "...") || len(got) > 8 {
+		t.Errorf("truncate = %q", got)
+	}
+}
+
+func TestEscalator_Cooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := &fakeNotifier{name: "fake"}
+	e := NewEscalator([]Notifier{n}, WithCooldown(time.Hour), WithAlertClock(func() time.Time { return now }))
+
+	sent, err := e.Escalate(context.Background(), testAlert())
+	if err != nil || !sent {
+		t.Fatalf("first escalation: sent=%v err=%v", sent, err)
+	}
+
+	sent, _ = e.Escalate(context.Background(), testAlert())
+	if sent {
+		t.Error("second escalation within cooldown should be suppressed")
+	}
+
+	now = now.Add(2 * time.Hour)
+	sent, _ = e.Escalate(context.Background(), testAlert())
+	if !sent {
+		t.Error("escalation after cooldown should be sent")
+	}
+	if n.calls != 2 {
+		t.Errorf("expected 2 deliveries, got %d", n.calls)
+	}
+}
+
+func TestEscalator_PartialFailure(t *testing.T) {
+	bad := &fakeNotifier{name: "bad", err: errors.New("down")}
+	good := &fakeNotifier{name: "good"}
+	e := NewEscalator([]Notifier{bad, good})
+
+	if _, err := e.Escalate(context.Background(), testAlert()); err != nil {
+		t.Errorf("partial failure should not error: %v", err)
+	}
+}
+
+func TestEscalator_AllFail(t *testing.T) {
+	bad := &fakeNotifier{name: "bad", err: errors.New("down")}
+	e := NewEscalator([]Notifier{bad})
+
+	if _, err := e.Escalate(context.Background(), testAlert()); err == nil {
+		t.Error("expected error when every channel fails")
+	}
+}
+
+func TestEscalator_AllFailDoesNotStartCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	webhook := &fakeNotifier{name: "webhook", err: errors.New("down")}
+	email := &fakeNotifier{name: "email", err: errors.New("smtp refused")}
+	e := NewEscalator([]Notifier{webhook, email}, WithCooldown(time.Hour), WithAlertClock(func() time.Time { return now }))
+
+	if _, err := e.Escalate(context.Background(), testAlert()); err == nil {
+		t.Fatal("expected error when every channel fails")
+	}
+
+	// The channels recover a minute later: the retry must go out.
+	now = now.Add(time.Minute)
+	webhook.err, email.err = nil, nil
+	sent, err := e.Escalate(context.Background(), testAlert())
+	if err != nil || !sent {
+		t.Fatalf("retry after total failure: sent=%v err=%v", sent, err)
+	}
+	if webhook.calls != 2 || email.calls != 2 {
+		t.Errorf("calls = %d, %d; want 2 each", webhook.calls, email.calls)
+	}
+
+	// Now that it was delivered, the cooldown applies.
+	now = now.Add(time.Minute)
+	if sent, _ := e.Escalate(context.Background(), testAlert()); sent {
+		t.Error("escalation after a delivery should be suppressed")
+	}
+}
+
+func TestCrashLoopDetector(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewCrashLoopDetector(3, 10*time.Minute, func() time.Time { return now })
+
+	if d.RecordRestart() || d.RecordRestart() {
+		t.Fatal("should not trip before threshold")
+	}
+	if !d.RecordRestart() {
+		t.Error("should trip at threshold")
+	}
+
+	now = now.Add(time.Hour)
+	if d.RecordRestart() {
+		t.Error("old restarts should fall out of the window")
+	}
+}
+
+func TestNotifiersFromConfig(t *testing.T) {
+	if got := NotifiersFromConfig(config.GlobalAlerts{}); len(got) != 0 {
+		t.Errorf("expected no notifiers, got %d", len(got))
+	}
+
+	got := NotifiersFromConfig(config.GlobalAlerts{
+		SlackWebhookURL: "https://hooks.slack.com/x",
+		Pushover:        &config.PushoverAuth{Token: "t", User: "u"},
+		Email:           &config.EmailAlerts{SMTPAddr: "h:25", From: "a@b", To: []string{"c@d"}},
+		Ntfy:            &config.NtfyConfig{Topic: "cb"},
+	})
+	if len(got) != 4 {
+		t.Errorf("expected 4 notifiers, got %d", len(got))
+	}
+}
+
+func TestNtfy_Notify(t *testing.T) {
+	doer := &recordingDoer{}
+	n := &Ntfy{Server: "https://ntfy.example.com/", Topic: "my topic", Token: "tk", Client: doer}
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatal(err)
+	}
+	req := doer.reqs[0]
+	if req.URL.String() != "https://ntfy.example.com/my%20topic" {
+		t.Errorf("url = %s", req.URL)
+	}
+	if req.Header.Get("Priority") != "urgent" || req.Header.Get("Authorization") != "Bearer tk" {
+		t.Errorf("headers = %v", req.Header)
+	}
+	if !strings.Contains(req.Header.Get("Title"), "crash loop detected") || !strings.Contains(doer.bodies[0], "restarted 5 times") {
+		t.Errorf("title %q body %q", req.Header.Get("Title"), doer.bodies[0])
+	}
+}
+
+func TestTaskNotifier(t *testing.T) {
+	doer := &recordingDoer{}
+	tn := NewTaskNotifier([]Notifier{&Ntfy{Topic: "cb", Client: doer}}, []string{"done", " Failed ", "bogus"})
+
+	if !tn.Wants(TaskDone) || !tn.Wants(TaskFailed) || tn.Wants(TaskApproval) {
+		t.Error("kind selection wrong")
+	}
+
+	ctx := context.Background()
+	tn.Notify(ctx, TaskEvent{Kind: TaskApproval, Role: "pm", Summary: "deploy"})
+	if len(doer.reqs) != 0 {
+		t.Fatal("disabled kind should not push")
+	}
+
+	if err := tn.Notify(ctx, TaskEvent{Kind: TaskDone, Role: "coder", Summary: "add login page", Detail: "PR #12 opened", Link: "https://slack/t/1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tn.Notify(ctx, TaskEvent{Kind: TaskDone, Role: "coder", Summary: "add login page"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(doer.reqs) != 2 {
+		t.Fatalf("repeats must not be suppressed, got %d pushes", len(doer.reqs))
+	}
+	if got := doer.reqs[0].Header.Get("Title"); got != "CodeButler coder: done — add login page" {
+		t.Errorf("title = %q", got)
+	}
+	if !strings.Contains(doer.bodies[0], "PR #12 opened") || !strings.Contains(doer.bodies[0], "https://slack/t/1") {
+		t.Errorf("body = %q", doer.bodies[0])
+	}
+
+	failing := NewTaskNotifier([]Notifier{&fakeNotifier{name: "x", err: errors.New("down")}}, []string{"failed"})
+	if err := failing.Notify(ctx, TaskEvent{Kind: TaskFailed}); err == nil {
+		t.Error("expected error when every channel fails")
+	}
+
+	var nilNotifier *TaskNotifier
+	if nilNotifier.Wants(TaskDone) {
+		t.Error("nil notifier wants nothing")
+	}
+}
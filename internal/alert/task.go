@@ -0,0 +1,96 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TaskEventKind is a task lifecycle event worth a push notification.
+type TaskEventKind string
+
+const (
+	TaskDone     TaskEventKind = "done"
+	TaskApproval TaskEventKind = "approval"
+	TaskFailed   TaskEventKind = "failed"
+)
+
+// TaskEvent describes a task lifecycle change.
+type TaskEvent struct {
+	Kind    TaskEventKind
+	Role    string // agent that owns the task
+	Thread  string // chat thread ID
+	Summary string // what the task was
+	Detail  string // result, approval question, or error
+	Link    string // permalink to the thread, optional
+}
+
+// TaskNotifier pushes selected task events through the alert channels so a
+// long task's outcome reaches the owner without the chat open. Unlike
+// Escalator it never suppresses repeats: each event is distinct.
+type TaskNotifier struct {
+	notifiers []Notifier
+	kinds     map[TaskEventKind]bool
+}
+
+// NewTaskNotifier creates a notifier for the given event kinds. Unknown
+// kinds are ignored.
+func NewTaskNotifier(notifiers []Notifier, kinds []string) *TaskNotifier {
+	t := &TaskNotifier{notifiers: notifiers, kinds: make(map[TaskEventKind]bool)}
+	for _, k := range kinds {
+		switch kind := TaskEventKind(strings.ToLower(strings.TrimSpace(k))); kind {
+		case TaskDone, TaskApproval, TaskFailed:
+			t.kinds[kind] = true
+		}
+	}
+	return t
+}
+
+// Wants reports whether events of kind are pushed.
+func (t *TaskNotifier) Wants(kind TaskEventKind) bool {
+	return t != nil && len(t.notifiers) > 0 && t.kinds[kind]
+}
+
+// Notify pushes ev if its kind is enabled. It returns an error only if
+// every channel failed.
+func (t *TaskNotifier) Notify(ctx context.Context, ev TaskEvent) error {
+	if !t.Wants(ev.Kind) {
+		return nil
+	}
+	a := ev.Alert()
+
+	var errs []string
+	for _, n := range t.notifiers {
+		if err := n.Notify(ctx, a); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", n.Name(), err))
+		}
+	}
+	if len(errs) == len(t.notifiers) {
+		return fmt.Errorf("all notification channels failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Alert converts the event to an Alert for delivery.
+func (ev TaskEvent) Alert() Alert {
+	a := Alert{
+		Key:      fmt.Sprintf("task-%s:%s", ev.Kind, ev.Thread),
+		Role:     ev.Role,
+		Severity: SeverityInfo,
+		Message:  ev.Detail,
+	}
+	switch ev.Kind {
+	case TaskDone:
+		a.Title = "done — " + ev.Summary
+	case TaskApproval:
+		a.Title = "approval needed — " + ev.Summary
+		a.Severity = SeverityWarning
+	case TaskFailed:
+		a.Title = "failed — " + ev.Summary
+		a.Severity = SeverityWarning
+	}
+	if ev.Link != "" {
+		a.Diagnostics = map[string]string{"thread": ev.Link}
+	}
+	return a
+}
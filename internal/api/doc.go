@@ -0,0 +1,14 @@
+// Package api is the daemon's HTTP API, so CI jobs, scripts, and editors
+// can drive CodeButler without going through a messenger. Every endpoint
+// takes the shared token as "Authorization: Bearer <token>" and speaks
+// JSON:
+//
+//	POST /api/tasks            submit a prompt: {"prompt", "channel"?, "thread"?}
+//	GET  /api/tasks/{id}       task status, plus its result file once finished
+//	GET  /api/conversations    stored conversations, most recent first
+//	GET  /api/budget           today's spend against the configured limits
+//	POST /api/agent/stop       stop one task ({"task_id"}) or every active task
+//	POST /api/gc               run worktree GC now; ?dry_run=true previews it
+//
+// Endpoints whose backing component is not configured answer 503.
+package api
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/budget"
+	"github.com/leandrotocalini/codebutler/internal/conversation"
+	"github.com/leandrotocalini/codebutler/internal/httpauth"
+	"github.com/leandrotocalini/codebutler/internal/results"
+	"github.com/leandrotocalini/codebutler/internal/taskqueue"
+	"github.com/leandrotocalini/codebutler/internal/worktree"
+)
+
+// maxBodyBytes caps request bodies; prompts are text, not uploads.
+const maxBodyBytes = 1 << 20
+
+// TaskQueue is the part of taskqueue.Queue the API uses.
+type TaskQueue interface {
+	Enqueue(channel, thread, userID, summary string) (taskqueue.Task, error)
+	Get(id string) (taskqueue.Task, error)
+	Cancel(id string) (taskqueue.Task, error)
+	Active() []taskqueue.Task
+}
+
+// Dispatcher hands a submitted prompt to the agents. It must return
+// quickly; the run itself continues in the background and reports through
+// the queue.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, task taskqueue.Task, prompt string) error
+}
+
+// ResultLoader reads a finished task's result file.
+type ResultLoader interface {
+	Load(taskID string) (*results.TaskResult, error)
+}
+
+// BudgetReporter reports today's spend.
+type BudgetReporter interface {
+	DailyCost() float64
+	CheckDaily() (remaining float64, exhausted bool)
+	Limits() budget.BudgetConfig
+}
+
+// GCTrigger runs a worktree GC pass; *worktree.GarbageCollector implements
+// it.
+type GCTrigger interface {
+	Trigger(ctx context.Context, dryRun bool) (*worktree.GCReport, error)
+}
+
+// Server serves the API. Build it with New and mount it at "/api/".
+type Server struct {
+	token      string
+	queue      TaskQueue
+	dispatcher Dispatcher
+	results    ResultLoader
+	convDir    string
+	convOpts   []conversation.Option
+	budget     BudgetReporter
+	gc         GCTrigger
+	logger     *slog.Logger
+	mux        *http.ServeMux
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithTasks enables task submission, lookup, and stopping.
+func WithTasks(q TaskQueue, d Dispatcher) Option {
+	return func(s *Server) {
+		s.queue = q
+		s.dispatcher = d
+	}
+}
+
+// WithResults adds result files to GET /api/tasks/{id}.
+func WithResults(r ResultLoader) Option {
+	return func(s *Server) {
+		s.results = r
+	}
+}
+
+// WithConversations enables GET /api/conversations for the repo at baseDir.
+// Pass conversation.WithCipher when the files are sealed.
+func WithConversations(baseDir string, opts ...conversation.Option) Option {
+	return func(s *Server) {
+		s.convDir = baseDir
+		s.convOpts = opts
+	}
+}
+
+// WithBudget enables GET /api/budget.
+func WithBudget(b BudgetReporter) Option {
+	return func(s *Server) {
+		s.budget = b
+	}
+}
+
+// WithGC enables POST /api/gc.
+func WithGC(gc GCTrigger) Option {
+	return func(s *Server) {
+		s.gc = gc
+	}
+}
+
+// WithLogger sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// New creates the API. An empty token disables every endpoint.
+func New(token string, opts ...Option) *Server {
+	s := &Server{token: token, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /api/tasks", s.submitTask)
+	s.mux.HandleFunc("GET /api/tasks/{id}", s.getTask)
+	s.mux.HandleFunc("GET /api/conversations", s.listConversations)
+	s.mux.HandleFunc("GET /api/budget", s.getBudget)
+	s.mux.HandleFunc("POST /api/agent/stop", s.stopAgent)
+	s.mux.HandleFunc("POST /api/gc", s.triggerGC)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token == "" {
+		writeError(w, http.StatusServiceUnavailable, "API is disabled (no token configured)")
+		return
+	}
+	if !httpauth.Authorized(r, s.token) {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// SubmitRequest is the POST /api/tasks body. Channel and thread are where
+// the agents post their replies; an empty thread starts a new one.
+type SubmitRequest struct {
+	Prompt  string `json:"prompt"`
+	Channel string `json:"channel,omitempty"`
+	Thread  string `json:"thread,omitempty"`
+}
+
+func (s *Server) submitTask(w http.ResponseWriter, r *http.Request) {
+	if s.queue == nil || s.dispatcher == nil {
+		writeError(w, http.StatusServiceUnavailable, "task submission is not configured")
+		return
+	}
+	var req SubmitRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Prompt = strings.TrimSpace(req.Prompt)
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, "prompt is required")
+		return
+	}
+
+	task, err := s.queue.Enqueue(req.Channel, req.Thread, "api", summaryLine(req.Prompt))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := s.dispatcher.Dispatch(r.Context(), task, req.Prompt); err != nil {
+		s.logger.Warn("api task dispatch failed", "task", task.ID, "err", err)
+		s.queue.Cancel(task.ID) //nolint:errcheck // best effort; the dispatch error is what matters
+		writeError(w, http.StatusInternalServerError, "dispatch task: "+err.Error())
+		return
+	}
+	s.logger.Info("api task submitted", "task", task.ID, "channel", task.Channel, "thread", task.Thread)
+	writeJSON(w, http.StatusAccepted, task)
+}
+
+// TaskResponse is the GET /api/tasks/{id} body. Result is set once the
+// task's result file exists.
+type TaskResponse struct {
+	Task   *taskqueue.Task     `json:"task,omitempty"`
+	Result *results.TaskResult `json:"result,omitempty"`
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	if s.queue == nil && s.results == nil {
+		writeError(w, http.StatusServiceUnavailable, "task lookup is not configured")
+		return
+	}
+	id := r.PathValue("id")
+
+	var resp TaskResponse
+	if s.queue != nil {
+		task, err := s.queue.Get(id)
+		switch {
+		case err == nil:
+			resp.Task = &task
+		case !errors.Is(err, taskqueue.ErrNotFound):
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if s.results != nil {
+		result, err := s.results.Load(id)
+		switch {
+		case err == nil:
+			resp.Result = result
+		case !errors.Is(err, results.ErrNotFound):
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if resp.Task == nil && resp.Result == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) listConversations(w http.ResponseWriter, r *http.Request) {
+	if s.convDir == "" {
+		writeError(w, http.StatusServiceUnavailable, "conversations are not configured")
+		return
+	}
+	list, err := conversation.List(s.convDir, s.convOpts...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if list == nil {
+		list = []conversation.Summary{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"conversations": list})
+}
+
+// BudgetResponse is the GET /api/budget body. Limits of 0 mean unlimited,
+// in which case RemainingUSD is omitted.
+type BudgetResponse struct {
+	DailyCostUSD   float64  `json:"daily_cost_usd"`
+	DailyLimitUSD  float64  `json:"daily_limit_usd"`
+	ThreadLimitUSD float64  `json:"thread_limit_usd"`
+	RemainingUSD   *float64 `json:"remaining_usd,omitempty"`
+	Exhausted      bool     `json:"exhausted"`
+}
+
+func (s *Server) getBudget(w http.ResponseWriter, r *http.Request) {
+	if s.budget == nil {
+		writeError(w, http.StatusServiceUnavailable, "budget tracking is not configured")
+		return
+	}
+	limits := s.budget.Limits()
+	remaining, exhausted := s.budget.CheckDaily()
+	resp := BudgetResponse{
+		DailyCostUSD:   s.budget.DailyCost(),
+		DailyLimitUSD:  limits.PerDayUSD,
+		ThreadLimitUSD: limits.PerThreadUSD,
+		Exhausted:      exhausted,
+	}
+	if limits.PerDayUSD > 0 {
+		resp.RemainingUSD = &remaining
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// StopRequest is the POST /api/agent/stop body. An empty TaskID stops
+// every pending and running task.
+type StopRequest struct {
+	TaskID string `json:"task_id,omitempty"`
+}
+
+func (s *Server) stopAgent(w http.ResponseWriter, r *http.Request) {
+	if s.queue == nil {
+		writeError(w, http.StatusServiceUnavailable, "task control is not configured")
+		return
+	}
+	var req StopRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ids := []string{req.TaskID}
+	if req.TaskID == "" {
+		ids = ids[:0]
+		for _, t := range s.queue.Active() {
+			ids = append(ids, t.ID)
+		}
+	}
+	stopped := []taskqueue.Task{}
+	for _, id := range ids {
+		task, err := s.queue.Cancel(id)
+		if errors.Is(err, taskqueue.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		if err != nil {
+			if req.TaskID != "" {
+				writeError(w, http.StatusConflict, err.Error())
+				return
+			}
+			continue // finished between Active and Cancel
+		}
+		stopped = append(stopped, task)
+	}
+	s.logger.Info("api stop", "tasks", len(stopped))
+	writeJSON(w, http.StatusOK, map[string]any{"stopped": stopped})
+}
+
+// triggerGC runs a GC pass and returns its worktree.GCReport.
+// "?dry_run=true" previews the pass without changing anything.
+func (s *Server) triggerGC(w http.ResponseWriter, r *http.Request) {
+	if s.gc == nil {
+		writeError(w, http.StatusServiceUnavailable, "worktree GC is not configured")
+		return
+	}
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			writeError(w, http.StatusBadRequest, "dry_run must be a boolean")
+			return
+		}
+	}
+	report, err := s.gc.Trigger(r.Context(), dryRun)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// decodeBody parses an optional JSON body into v; an empty body leaves v
+// unchanged.
+func decodeBody(r *http.Request, v any) error {
+	err := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes)).Decode(v)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.New("invalid JSON body: " + err.Error())
+	}
+	return nil
+}
+
+// summaryLine is the first line of a prompt, as the queue stores it.
+func summaryLine(prompt string) string {
+	line, _, _ := strings.Cut(prompt, "\n")
+	return strings.TrimSpace(line)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck // client went away
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
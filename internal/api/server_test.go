@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/budget"
+	"github.com/leandrotocalini/codebutler/internal/results"
+	"github.com/leandrotocalini/codebutler/internal/taskqueue"
+	"github.com/leandrotocalini/codebutler/internal/worktree"
+)
+
+type mockDispatcher struct {
+	prompts []string
+	err     error
+}
+
+func (m *mockDispatcher) Dispatch(_ context.Context, _ taskqueue.Task, prompt string) error {
+	m.prompts = append(m.prompts, prompt)
+	return m.err
+}
+
+type mockBudget struct {
+	cost   float64
+	limits budget.BudgetConfig
+}
+
+func (m mockBudget) DailyCost() float64 { return m.cost }
+func (m mockBudget) CheckDaily() (float64, bool) {
+	return m.limits.PerDayUSD - m.cost, m.cost >= m.limits.PerDayUSD
+}
+func (m mockBudget) Limits() budget.BudgetConfig { return m.limits }
+
+func do(t *testing.T, s *Server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func newQueue(t *testing.T) *taskqueue.Queue {
+	t.Helper()
+	q, err := taskqueue.Open(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := New("secret")
+	req := httptest.NewRequest(http.MethodGet, "/api/budget", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: %d", rec.Code)
+	}
+	req.Header.Set("Authorization", "secret")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("token without the Bearer scheme: %d", rec.Code)
+	}
+
+	if rec := do(t, New(""), http.MethodGet, "/api/budget", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("no token: %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/budget", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("unconfigured budget: %d", rec.Code)
+	}
+}
+
+func TestServer_SubmitGetStop(t *testing.T) {
+	q := newQueue(t)
+	d := &mockDispatcher{}
+	s := New("secret", WithTasks(q, d))
+
+	rec := do(t, s, http.MethodPost, "/api/tasks", `{"prompt":"add a health check\nwith details","channel":"C1"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit: %d %s", rec.Code, rec.Body)
+	}
+	var task taskqueue.Task
+	json.Unmarshal(rec.Body.Bytes(), &task)
+	if task.ID == "" || task.Summary != "add a health check" || task.UserID != "api" {
+		t.Errorf("task = %+v", task)
+	}
+	if len(d.prompts) != 1 || !strings.Contains(d.prompts[0], "with details") {
+		t.Errorf("dispatched %q", d.prompts)
+	}
+
+	if rec := do(t, s, http.MethodPost, "/api/tasks", `{"prompt":"  "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty prompt: %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/tasks/"+task.ID, "")
+	var got TaskResponse
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if rec.Code != http.StatusOK || got.Task == nil || got.Task.Status != taskqueue.StatusPending {
+		t.Errorf("get: %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, s, http.MethodGet, "/api/tasks/t99", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown task: %d", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/agent/stop", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), task.ID) {
+		t.Errorf("stop all: %d %s", rec.Code, rec.Body)
+	}
+	if len(q.Active()) != 0 {
+		t.Errorf("still active: %+v", q.Active())
+	}
+	if rec := do(t, s, http.MethodPost, "/api/agent/stop", `{"task_id":"`+task.ID+`"}`); rec.Code != http.StatusConflict {
+		t.Errorf("stop finished task: %d", rec.Code)
+	}
+}
+
+func TestServer_SubmitDispatchFailureCancels(t *testing.T) {
+	q := newQueue(t)
+	s := New("secret", WithTasks(q, &mockDispatcher{err: errors.New("no runner")}))
+	if rec := do(t, s, http.MethodPost, "/api/tasks", `{"prompt":"x"}`); rec.Code != http.StatusInternalServerError {
+		t.Errorf("code = %d", rec.Code)
+	}
+	if len(q.Active()) != 0 {
+		t.Error("failed dispatch left the task active")
+	}
+}
+
+func TestServer_ConversationsAndBudget(t *testing.T) {
+	dir := t.TempDir()
+	convDir := filepath.Join(dir, ".codebutler", "branches", "codebutler-login", "conversations")
+	os.MkdirAll(convDir, 0o755)
+	os.WriteFile(filepath.Join(convDir, "coder.json"), []byte(`[{"role":"user"},{"role":"assistant"}]`), 0o644)
+
+	s := New("secret", WithConversations(dir), WithBudget(mockBudget{cost: 2, limits: budget.BudgetConfig{PerDayUSD: 10}}))
+
+	rec := do(t, s, http.MethodGet, "/api/conversations", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"branch":"codebutler-login"`) ||
+		!strings.Contains(rec.Body.String(), `"messages":2`) {
+		t.Errorf("conversations: %d %s", rec.Code, rec.Body)
+	}
+
+	rec = do(t, s, http.MethodGet, "/api/budget", "")
+	var b BudgetResponse
+	json.Unmarshal(rec.Body.Bytes(), &b)
+	if b.DailyCostUSD != 2 || b.RemainingUSD == nil || *b.RemainingUSD != 8 || b.Exhausted {
+		t.Errorf("budget: %s", rec.Body)
+	}
+}
+
+type mockGC struct{ dryRuns []bool }
+
+func (m *mockGC) Trigger(_ context.Context, dryRun bool) (*worktree.GCReport, error) {
+	m.dryRuns = append(m.dryRuns, dryRun)
+	return &worktree.GCReport{DryRun: dryRun, Cleaned: []worktree.GCAction{{Branch: "codebutler/old", Reason: "grace period elapsed"}}}, nil
+}
+
+func TestServer_ResultsAndGC(t *testing.T) {
+	store := results.NewStore(t.TempDir())
+	store.Save(&results.TaskResult{TaskID: "1714.55", Outcome: results.OutcomeSuccess})
+	gc := &mockGC{}
+	s := New("secret", WithResults(store), WithGC(gc))
+
+	rec := do(t, s, http.MethodGet, "/api/tasks/1714.55", "")
+	var got TaskResponse
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if rec.Code != http.StatusOK || got.Task != nil || got.Result == nil || got.Result.Outcome != results.OutcomeSuccess {
+		t.Errorf("result lookup: %d %s", rec.Code, rec.Body)
+	}
+
+	rec = do(t, s, http.MethodPost, "/api/gc?dry_run=true", "")
+	var report worktree.GCReport
+	json.Unmarshal(rec.Body.Bytes(), &report)
+	if rec.Code != http.StatusOK || !report.DryRun || len(report.Cleaned) != 1 || len(gc.dryRuns) != 1 || !gc.dryRuns[0] {
+		t.Errorf("gc dry run: %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, s, http.MethodPost, "/api/gc?dry_run=maybe", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad dry_run: %d", rec.Code)
+	}
+	if rec := do(t, New("secret"), http.MethodPost, "/api/gc", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("unconfigured gc: %d", rec.Code)
+	}
+}
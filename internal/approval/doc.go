@@ -0,0 +1,8 @@
+// Package approval pauses a run until a person confirms a risky step in the
+// thread. Gate posts a "Reply 1 to approve / 2 to reject" prompt and blocks
+// the caller until the reply (or a button click) arrives, so the same
+// reply-style confirmation works for destructive tools, deploys, or any
+// other action that needs a human yes. Only the task's requester and the
+// configured approvers can answer, and a thread asks one question at a
+// time, queuing the rest.
+package approval
@@ -0,0 +1,240 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+// DefaultTimeout is how long a request waits for an answer before it counts
+// as rejected.
+const DefaultTimeout = 30 * time.Minute
+
+// MessageSender posts the approval prompt.
+type MessageSender interface {
+	SendMessage(ctx context.Context, channel, threadTS, text string) error
+}
+
+// Gate asks one question per thread at a time; further requests in the
+// same thread wait their turn, first come first served. Thread-safe.
+type Gate struct {
+	sender    MessageSender
+	catalog   *messages.Catalog
+	timeout   time.Duration
+	approvers map[string]bool
+
+	mu      sync.Mutex
+	pending map[string]*request    // channel/thread → the question being asked
+	turns   map[string]*threadTurn // channel/thread → queue of askers
+}
+
+// request is the question currently shown in a thread.
+type request struct {
+	requester string
+	answer    chan bool
+}
+
+// threadTurn serializes requests in one thread. Blocked senders on a
+// channel are served in order, so holding the one slot is a FIFO lock.
+type threadTurn struct {
+	slot    chan struct{}
+	waiters int
+}
+
+// Option configures a Gate.
+type Option func(*Gate)
+
+// WithTimeout overrides DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(g *Gate) {
+		if d > 0 {
+			g.timeout = d
+		}
+	}
+}
+
+// WithCatalog renders prompts in the catalog's locale, with the repo's
+// overrides. The default is the built-in English text.
+func WithCatalog(c *messages.Catalog) Option {
+	return func(g *Gate) {
+		if c != nil {
+			g.catalog = c
+		}
+	}
+}
+
+// WithApprovers lets these user IDs answer any request, in addition to the
+// requester.
+func WithApprovers(userIDs ...string) Option {
+	return func(g *Gate) {
+		for _, id := range userIDs {
+			if id != "" {
+				g.approvers[id] = true
+			}
+		}
+	}
+}
+
+// NewGate creates an approval gate that posts prompts through sender.
+func NewGate(sender MessageSender, opts ...Option) *Gate {
+	g := &Gate{
+		sender:    sender,
+		catalog:   messages.New(messages.DefaultLocale),
+		timeout:   DefaultTimeout,
+		approvers: make(map[string]bool),
+		pending:   make(map[string]*request),
+		turns:     make(map[string]*threadTurn),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+func key(channel, thread string) string {
+	return channel + "/" + thread
+}
+
+// Request posts question to the thread and blocks until an allowed user
+// answers, the timeout passes (rejected), or ctx is cancelled. requester
+// is the user whose task asked; with no requester and no configured
+// approvers, anyone in the thread may answer. If the thread already has a
+// question open, this one is posted after it is settled.
+func (g *Gate) Request(ctx context.Context, channel, thread, requester, question string) (bool, error) {
+	k := key(channel, thread)
+	release, err := g.waitTurn(ctx, k)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	req := &request{requester: requester, answer: make(chan bool, 1)}
+	g.mu.Lock()
+	g.pending[k] = req
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.pending, k)
+		g.mu.Unlock()
+	}()
+
+	prompt := g.catalog.Render(messages.ApprovalPrompt, map[string]string{"Question": question})
+	if err := g.sender.SendMessage(ctx, channel, thread, prompt); err != nil {
+		return false, fmt.Errorf("post approval prompt: %w", err)
+	}
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+	select {
+	case ok := <-req.answer:
+		return ok, nil
+	case <-timer.C:
+		g.sender.SendMessage(ctx, channel, thread, //nolint:errcheck // best-effort notice
+			g.catalog.Render(messages.ApprovalTimedOut, map[string]any{"Timeout": g.timeout}))
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// waitTurn blocks until no other request is open in thread k. The
+// returned func hands the turn to the next waiter.
+func (g *Gate) waitTurn(ctx context.Context, k string) (release func(), err error) {
+	g.mu.Lock()
+	t, ok := g.turns[k]
+	if !ok {
+		t = &threadTurn{slot: make(chan struct{}, 1)}
+		g.turns[k] = t
+	}
+	t.waiters++
+	g.mu.Unlock()
+
+	done := func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if t.waiters--; t.waiters == 0 {
+			delete(g.turns, k)
+		}
+	}
+	select {
+	case t.slot <- struct{}{}:
+		return func() {
+			<-t.slot
+			done()
+		}, nil
+	case <-ctx.Done():
+		done()
+		return nil, ctx.Err()
+	}
+}
+
+// Resolve answers the thread's pending request on behalf of userID, e.g.
+// from an approve or reject button. It returns false if nothing was
+// waiting or userID may not answer it.
+func (g *Gate) Resolve(channel, thread, userID string, approve bool) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	req, ok := g.pending[key(channel, thread)]
+	if !ok || !g.allowed(req, userID) {
+		return false
+	}
+	delete(g.pending, key(channel, thread))
+	req.answer <- approve
+	return true
+}
+
+// allowed reports whether userID may answer req. Caller holds g.mu.
+func (g *Gate) allowed(req *request, userID string) bool {
+	if req.requester == "" && len(g.approvers) == 0 {
+		return true
+	}
+	return userID != "" && (userID == req.requester || g.approvers[userID])
+}
+
+// HandleReply treats a thread message from userID as the answer to its
+// pending request. It returns true when the message was consumed: "1",
+// "yes", or "approve" approves; "2", "no", or "reject" rejects. Anything
+// else, or an answer from someone who may not give it, is left for the
+// normal message flow.
+func (g *Gate) HandleReply(channel, thread, userID, text string) bool {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(text), ".!")) {
+	case "1", "yes", "y", "approve", "approved":
+		return g.Resolve(channel, thread, userID, true)
+	case "2", "no", "n", "reject", "rejected":
+		return g.Resolve(channel, thread, userID, false)
+	}
+	return false
+}
+
+// Pending reports whether the thread is waiting for an answer.
+func (g *Gate) Pending(channel, thread string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.pending[key(channel, thread)]
+	return ok
+}
+
+// ThreadApprover binds a Gate to one thread and its task's requester. It
+// satisfies tools.Approver.
+type ThreadApprover struct {
+	gate      *Gate
+	channel   string
+	thread    string
+	requester string
+}
+
+// For returns an approver that asks in the given thread and accepts
+// answers from requester and the configured approvers.
+func (g *Gate) For(channel, thread, requester string) *ThreadApprover {
+	return &ThreadApprover{gate: g, channel: channel, thread: thread, requester: requester}
+}
+
+// RequestApproval asks whether the described action may run.
+func (a *ThreadApprover) RequestApproval(ctx context.Context, summary string) (bool, error) {
+	question := a.gate.catalog.Render(messages.ApprovalToolCall, map[string]string{"Summary": summary})
+	return a.gate.Request(ctx, a.channel, a.thread, a.requester, question)
+}
@@ -0,0 +1,187 @@
+package approval
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *recordingSender) SendMessage(_ context.Context, _, _, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, text)
+	return nil
+}
+
+func (s *recordingSender) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.sent...)
+}
+
+func waitPending(t *testing.T, g *Gate) {
+	t.Helper()
+	for i := 0; i < 100 && !g.Pending("C1", "T1"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGate_ReplyApproves(t *testing.T) {
+	sender := &recordingSender{}
+	g := NewGate(sender)
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.For("C1", "T1", "U1").RequestApproval(context.Background(), "Bash: `rm -rf build`")
+		done <- ok
+	}()
+	waitPending(t, g)
+
+	if g.HandleReply("C1", "T1", "U1", "looks fine?") {
+		t.Error("unrelated text should not answer the request")
+	}
+	if g.HandleReply("C1", "T1", "U2", "yes") {
+		t.Error("someone other than the requester approved")
+	}
+	if !g.HandleReply("C1", "T1", "U1", "1") {
+		t.Fatal("reply 1 was not consumed")
+	}
+	if !<-done {
+		t.Error("expected approval")
+	}
+	msgs := sender.messages()
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "rm -rf build") || !strings.Contains(msgs[0], "Reply *1* to approve") {
+		t.Errorf("prompt = %v", msgs)
+	}
+	if g.Pending("C1", "T1") {
+		t.Error("request still pending after answer")
+	}
+}
+
+func TestGate_RejectAndTimeout(t *testing.T) {
+	sender := &recordingSender{}
+	g := NewGate(sender, WithTimeout(20*time.Millisecond))
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.Request(context.Background(), "C1", "T1", "", "Deploy?")
+		done <- ok
+	}()
+	waitPending(t, g)
+	g.HandleReply("C1", "T1", "U9", "2") // no requester or approvers: anyone answers
+	if <-done {
+		t.Error("expected rejection")
+	}
+
+	ok, err := g.Request(context.Background(), "C1", "T1", "", "Deploy?")
+	if ok || err != nil {
+		t.Errorf("timeout: ok=%v err=%v", ok, err)
+	}
+	if msgs := sender.messages(); !strings.Contains(msgs[len(msgs)-1], "treating it as rejected") {
+		t.Errorf("missing timeout notice: %v", msgs)
+	}
+}
+
+func TestGate_CatalogLocale(t *testing.T) {
+	sender := &recordingSender{}
+	g := NewGate(sender, WithCatalog(messages.New("es")))
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.For("C1", "T1", "U1").RequestApproval(context.Background(), "Bash: `make deploy`")
+		done <- ok
+	}()
+	waitPending(t, g)
+	g.HandleReply("C1", "T1", "U1", "1")
+	<-done
+
+	msgs := sender.messages()
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "Estoy por ejecutar Bash") || !strings.Contains(msgs[0], "Respondé *1* para aprobar") {
+		t.Errorf("prompt = %v", msgs)
+	}
+}
+
+func TestGate_ConfiguredApprover(t *testing.T) {
+	g := NewGate(&recordingSender{}, WithApprovers("ULEAD"))
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.Request(context.Background(), "C1", "T1", "U1", "Deploy?")
+		done <- ok
+	}()
+	waitPending(t, g)
+	if g.Resolve("C1", "T1", "", true) || g.Resolve("C1", "T1", "U2", true) {
+		t.Error("button click from an unknown user was accepted")
+	}
+	if !g.Resolve("C1", "T1", "ULEAD", true) {
+		t.Fatal("configured approver was refused")
+	}
+	if !<-done {
+		t.Error("expected approval")
+	}
+}
+
+func TestGate_QueuesRequestsInThread(t *testing.T) {
+	sender := &recordingSender{}
+	g := NewGate(sender)
+
+	first, second := make(chan bool), make(chan bool)
+	go func() {
+		ok, _ := g.Request(context.Background(), "C1", "T1", "U1", "Deploy?")
+		first <- ok
+	}()
+	waitPending(t, g)
+	go func() {
+		ok, _ := g.Request(context.Background(), "C1", "T1", "U1", "Migrate?")
+		second <- ok
+	}()
+
+	// Only the first question is shown until it is answered.
+	time.Sleep(10 * time.Millisecond)
+	if msgs := sender.messages(); len(msgs) != 1 || !strings.HasPrefix(msgs[0], "Deploy?") {
+		t.Fatalf("prompts before answer = %v", msgs)
+	}
+	g.HandleReply("C1", "T1", "U1", "yes")
+	if !<-first {
+		t.Error("first: expected approval")
+	}
+
+	for i := 0; i < 100 && len(sender.messages()) < 2; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if msgs := sender.messages(); len(msgs) != 2 || !strings.HasPrefix(msgs[1], "Migrate?") {
+		t.Fatalf("prompts after answer = %v", msgs)
+	}
+	waitPending(t, g)
+	g.HandleReply("C1", "T1", "U1", "no")
+	if <-second {
+		t.Error("second: expected rejection")
+	}
+}
+
+func TestGate_QueuedRequestCancelled(t *testing.T) {
+	g := NewGate(&recordingSender{})
+	go g.Request(context.Background(), "C1", "T1", "", "Deploy?") //nolint:errcheck // answered below
+	waitPending(t, g)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		_, err := g.Request(ctx, "C1", "T1", "", "Migrate?")
+		errc <- err
+	}()
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("queued request err = %v, want context.Canceled", err)
+	}
+	g.HandleReply("C1", "T1", "", "1")
+}
@@ -0,0 +1,143 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// ReadOnlyTools are the only tools an /ask session may call.
+var ReadOnlyTools = []string{"Read", "Grep", "Glob", "ListTargets"}
+
+const defaultMaxTurns = 10
+
+const systemPrompt = `You answer quick questions about this repository.
+- You can only read and search files. Do not propose running commands or editing code unless asked how.
+- Cite files as path:line.
+- Answer in a few sentences or a short list; this is a chat reply, not a report.
+- If the code doesn't answer the question, say so instead of guessing.`
+
+// Asker runs ephemeral read-only sessions.
+type Asker struct {
+	provider agent.LLMProvider
+	sender   agent.MessageSender
+	tools    agent.ToolExecutor
+	model    string
+	maxTurns int
+	logger   *slog.Logger
+}
+
+// Option configures an Asker.
+type Option func(*Asker)
+
+// WithMaxTurns caps the agent loop of each question.
+func WithMaxTurns(n int) Option {
+	return func(a *Asker) {
+		if n > 0 {
+			a.maxTurns = n
+		}
+	}
+}
+
+// WithLogger sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(a *Asker) {
+		a.logger = l
+	}
+}
+
+// New creates an Asker that answers with model. tools is the full executor;
+// only ReadOnlyTools are exposed from it.
+func New(provider agent.LLMProvider, sender agent.MessageSender, tools agent.ToolExecutor, model string, opts ...Option) *Asker {
+	a := &Asker{
+		provider: provider,
+		sender:   sender,
+		tools:    &readOnly{ToolExecutor: tools},
+		model:    model,
+		maxTurns: defaultMaxTurns,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Ask answers question in a fresh session. The runner gets no conversation
+// store, so nothing about the session outlives the call.
+func (a *Asker) Ask(ctx context.Context, channel, thread, question string) (*agent.Result, error) {
+	runner := agent.NewAgentRunner(a.provider, a.sender, a.tools, agent.AgentConfig{
+		Role:         "ask",
+		Model:        a.model,
+		MaxTurns:     a.maxTurns,
+		SystemPrompt: systemPrompt,
+	}, agent.WithLogger(a.logger))
+
+	return runner.Run(ctx, agent.Task{
+		Messages: []agent.Message{{Role: "user", Content: question}},
+		Channel:  channel,
+		Thread:   thread,
+	})
+}
+
+// readOnly hides and refuses every tool not in ReadOnlyTools.
+type readOnly struct {
+	agent.ToolExecutor
+}
+
+func allowed(name string) bool {
+	for _, t := range ReadOnlyTools {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *readOnly) ListTools() []agent.ToolDefinition {
+	var defs []agent.ToolDefinition
+	for _, d := range r.ToolExecutor.ListTools() {
+		if allowed(d.Name) {
+			defs = append(defs, d)
+		}
+	}
+	return defs
+}
+
+func (r *readOnly) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	if !allowed(call.Name) {
+		return agent.ToolResult{
+			ToolCallID: call.ID,
+			Content:    fmt.Sprintf("%s is not available in /ask sessions (read-only).", call.Name),
+			IsError:    true,
+		}, nil
+	}
+	return r.ToolExecutor.Execute(ctx, call)
+}
+
+// Command returns /ask <question>.
+func Command(a *Asker) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "ask",
+		Usage:       "/ask <question>",
+		Description: "Answer a quick codebase question without touching the thread's session",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			question := strings.TrimSpace(inv.RawArgs)
+			if question == "" {
+				return "Usage: /ask <question>", nil
+			}
+			res, err := a.Ask(ctx, inv.Channel, inv.Thread, question)
+			if err != nil {
+				return "", err
+			}
+			if strings.TrimSpace(res.Response) == "" {
+				return fmt.Sprintf("No answer after %d turns; try a narrower question.", res.TurnsUsed), nil
+			}
+			return res.Response, nil
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package ask
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// scriptedProvider replays responses in order and records each request.
+type scriptedProvider struct {
+	responses []agent.Message
+	requests  []agent.ChatRequest
+}
+
+func (p *scriptedProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	p.requests = append(p.requests, req)
+	msg := p.responses[0]
+	p.responses = p.responses[1:]
+	return &agent.ChatResponse{Message: msg}, nil
+}
+
+type mockSender struct{}
+
+func (mockSender) SendMessage(context.Context, string, string, string) error { return nil }
+
+type mockTools struct {
+	executed []string
+}
+
+func (m *mockTools) Execute(_ context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	m.executed = append(m.executed, call.Name)
+	return agent.ToolResult{ToolCallID: call.ID, Content: "func main() {}"}, nil
+}
+
+func (m *mockTools) ListTools() []agent.ToolDefinition {
+	return []agent.ToolDefinition{{Name: "Read"}, {Name: "Write"}, {Name: "Bash"}, {Name: "Grep"}}
+}
+
+func TestAskIsReadOnly(t *testing.T) {
+	provider := &scriptedProvider{responses: []agent.Message{
+		{Role: "assistant", ToolCalls: []agent.ToolCall{
+			{ID: "1", Name: "Read", Arguments: `{"path":"main.go"}`},
+			{ID: "2", Name: "Write", Arguments: `{"path":"main.go","content":""}`},
+		}},
+		{Role: "assistant", Content: "Entry point is main.go:1."},
+	}}
+	tools := &mockTools{}
+	a := New(provider, mockSender{}, tools, "test-model")
+
+	out, err := Command(a).Run(context.Background(), chatcmd.Invocation{Channel: "C", Thread: "T", RawArgs: "where is main?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Entry point is main.go:1." {
+		t.Errorf("answer = %q", out)
+	}
+	if len(tools.executed) != 1 || tools.executed[0] != "Read" {
+		t.Errorf("executed = %v, want only Read", tools.executed)
+	}
+
+	first := provider.requests[0]
+	var names []string
+	for _, d := range first.Tools {
+		names = append(names, d.Name)
+	}
+	if strings.Join(names, ",") != "Read,Grep" {
+		t.Errorf("tools offered = %v", names)
+	}
+	// A fresh session: system prompt plus the question, no thread history.
+	if n := len(first.Messages); n != 2 || first.Messages[1].Content != "where is main?" {
+		t.Errorf("messages = %+v", first.Messages)
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	out, err := Command(New(&scriptedProvider{}, mockSender{}, &mockTools{}, "m")).Run(context.Background(), chatcmd.Invocation{})
+	if err != nil || !strings.HasPrefix(out, "Usage:") {
+		t.Errorf("Run = %q, %v", out, err)
+	}
+}
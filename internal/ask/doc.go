@@ -0,0 +1,5 @@
+// Package ask implements /ask: a quick question about the codebase
+// answered by a throwaway, read-only agent session. The session starts
+// from nothing, can only read and search files, and is never persisted or
+// summarized, so the thread's main conversation stays focused on its task.
+package ask
@@ -0,0 +1,5 @@
+// Package audio turns agent replies into speech for voice-first use. A
+// Synthesizer (OpenAI TTS or ElevenLabs) renders text to audio, and a
+// VoiceReplier posts short final responses as audio files alongside the
+// text reply.
+package audio
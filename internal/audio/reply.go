@@ -0,0 +1,67 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxChars is the longest reply spoken by default. Longer answers
+// (plans, diffs, reports) are better read than heard.
+const DefaultMaxChars = 600
+
+// AudioUploader posts an audio file to a chat thread.
+type AudioUploader interface {
+	UploadAudio(ctx context.Context, channel, thread, filename string, data []byte) error
+}
+
+// VoiceReplier speaks short final responses back to the thread.
+type VoiceReplier struct {
+	synth    Synthesizer
+	uploader AudioUploader
+	maxChars int
+}
+
+// NewVoiceReplier creates a replier. maxChars <= 0 means DefaultMaxChars.
+func NewVoiceReplier(synth Synthesizer, uploader AudioUploader, maxChars int) *VoiceReplier {
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChars
+	}
+	return &VoiceReplier{synth: synth, uploader: uploader, maxChars: maxChars}
+}
+
+// Reply synthesizes text and uploads it as a voice message. It returns false
+// without calling the TTS provider when the spoken text is empty or longer
+// than the threshold.
+func (v *VoiceReplier) Reply(ctx context.Context, channel, thread, text string) (bool, error) {
+	spoken := SpeakableText(text)
+	if spoken == "" || utf8.RuneCountInString(spoken) > v.maxChars {
+		return false, nil
+	}
+	data, err := v.synth.Synthesize(ctx, spoken)
+	if err != nil {
+		return false, fmt.Errorf("synthesize reply: %w", err)
+	}
+	if err := v.uploader.UploadAudio(ctx, channel, thread, "reply.mp3", data); err != nil {
+		return false, fmt.Errorf("upload voice reply: %w", err)
+	}
+	return true, nil
+}
+
+var (
+	codeBlockRe = regexp.MustCompile("(?s)```.*?```")
+	linkRe      = regexp.MustCompile(`<(https?://[^|>]+)\|([^>]+)>`)
+	markupRe    = regexp.MustCompile("[*_`~>#]+")
+	spaceRe     = regexp.MustCompile(`\s+`)
+)
+
+// SpeakableText strips what does not read well aloud: code blocks,
+// formatting marks, and link targets (keeping their labels).
+func SpeakableText(text string) string {
+	text = codeBlockRe.ReplaceAllString(text, " ")
+	text = linkRe.ReplaceAllString(text, "$2")
+	text = markupRe.ReplaceAllString(text, "")
+	return strings.TrimSpace(spaceRe.ReplaceAllString(text, " "))
+}
@@ -0,0 +1,179 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// Synthesizer renders text as speech. The returned audio is MP3.
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// HTTPDoer abstracts the HTTP client for testing.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+const (
+	defaultOpenAIBaseURL     = "https://api.openai.com/v1"
+	defaultOpenAIModel       = "gpt-4o-mini-tts"
+	defaultOpenAIVoice       = "alloy"
+	defaultElevenLabsBaseURL = "https://api.elevenlabs.io/v1"
+	defaultElevenLabsModel   = "eleven_multilingual_v2"
+	defaultElevenLabsVoice   = "21m00Tcm4TlvDq8ikWAM" // "Rachel", a stock voice
+)
+
+// Option configures a TTS client.
+type Option func(*settings)
+
+type settings struct {
+	httpClient HTTPDoer
+	baseURL    string
+	model      string
+	voice      string
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(doer HTTPDoer) Option {
+	return func(s *settings) {
+		s.httpClient = doer
+	}
+}
+
+// WithBaseURL sets a custom API base URL (for testing).
+func WithBaseURL(url string) Option {
+	return func(s *settings) {
+		if url != "" {
+			s.baseURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithModel overrides the provider's default TTS model.
+func WithModel(model string) Option {
+	return func(s *settings) {
+		if model != "" {
+			s.model = model
+		}
+	}
+}
+
+// WithVoice selects a voice: a name like "alloy" for OpenAI, a voice ID for
+// ElevenLabs.
+func WithVoice(voice string) Option {
+	return func(s *settings) {
+		if voice != "" {
+			s.voice = voice
+		}
+	}
+}
+
+func newSettings(baseURL, model, voice string, opts []Option) settings {
+	s := settings{httpClient: http.DefaultClient, baseURL: baseURL, model: model, voice: voice}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// OpenAITTS synthesizes speech with OpenAI's /audio/speech endpoint.
+type OpenAITTS struct {
+	apiKey string
+	settings
+}
+
+// NewOpenAITTS creates an OpenAI TTS client.
+func NewOpenAITTS(apiKey string, opts ...Option) *OpenAITTS {
+	return &OpenAITTS{apiKey: apiKey, settings: newSettings(defaultOpenAIBaseURL, defaultOpenAIModel, defaultOpenAIVoice, opts)}
+}
+
+// Synthesize implements Synthesizer.
+func (c *OpenAITTS) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	body := map[string]string{
+		"model":           c.model,
+		"voice":           c.voice,
+		"input":           text,
+		"response_format": "mp3",
+	}
+	headers := map[string]string{"Authorization": "Bearer " + c.apiKey}
+	return post(ctx, c.httpClient, c.baseURL+"/audio/speech", headers, body)
+}
+
+// ElevenLabsTTS synthesizes speech with the ElevenLabs text-to-speech API.
+type ElevenLabsTTS struct {
+	apiKey string
+	settings
+}
+
+// NewElevenLabsTTS creates an ElevenLabs TTS client.
+func NewElevenLabsTTS(apiKey string, opts ...Option) *ElevenLabsTTS {
+	return &ElevenLabsTTS{apiKey: apiKey, settings: newSettings(defaultElevenLabsBaseURL, defaultElevenLabsModel, defaultElevenLabsVoice, opts)}
+}
+
+// Synthesize implements Synthesizer.
+func (c *ElevenLabsTTS) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	body := map[string]string{
+		"text":     text,
+		"model_id": c.model,
+	}
+	headers := map[string]string{"xi-api-key": c.apiKey, "Accept": "audio/mpeg"}
+	return post(ctx, c.httpClient, c.baseURL+"/text-to-speech/"+c.voice, headers, body)
+}
+
+// post sends a JSON body and returns the raw (audio) response.
+func post(ctx context.Context, client HTTPDoer, url string, headers map[string]string, body any) ([]byte, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tts request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read tts response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tts API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// SynthesizerFromConfig builds the TTS client selected by the repo's
+// voiceReplies settings, using the API keys from the global config.
+func SynthesizerFromConfig(global *config.GlobalConfig, voice config.RepoVoiceReplies) (Synthesizer, error) {
+	switch voice.Provider {
+	case "", "openai":
+		if global.OpenAI.APIKey == "" {
+			return nil, fmt.Errorf("voiceReplies with provider openai needs openai.apiKey")
+		}
+		return NewOpenAITTS(global.OpenAI.APIKey, WithVoice(voice.Voice)), nil
+	case "elevenlabs":
+		if global.ElevenLabs == nil || global.ElevenLabs.APIKey == "" {
+			return nil, fmt.Errorf("voiceReplies with provider elevenlabs needs elevenlabs.apiKey")
+		}
+		return NewElevenLabsTTS(global.ElevenLabs.APIKey, WithVoice(voice.Voice)), nil
+	default:
+		return nil, fmt.Errorf("unknown voiceReplies provider %q (supported: openai, elevenlabs)", voice.Provider)
+	}
+}
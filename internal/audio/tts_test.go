@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAITTS_Synthesize(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("request = %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("ID3audio"))
+	}))
+	defer srv.Close()
+
+	tts := NewOpenAITTS("sk-test", WithBaseURL(srv.URL), WithVoice("nova"))
+	data, err := tts.Synthesize(context.Background(), "PR is ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "ID3audio" {
+		t.Errorf("audio = %q", data)
+	}
+	if got["input"] != "PR is ready" || got["voice"] != "nova" || got["model"] != defaultOpenAIModel {
+		t.Errorf("body = %v", got)
+	}
+}
+
+func TestElevenLabsTTS_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/text-to-speech/voice1" || r.Header.Get("xi-api-key") != "el-key" {
+			t.Errorf("request = %s %s", r.URL.Path, r.Header.Get("xi-api-key"))
+		}
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	tts := NewElevenLabsTTS("el-key", WithBaseURL(srv.URL), WithVoice("voice1"))
+	if _, err := tts.Synthesize(context.Background(), "hi"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type fakeSynth struct{ texts []string }
+
+func (f *fakeSynth) Synthesize(_ context.Context, text string) ([]byte, error) {
+	f.texts = append(f.texts, text)
+	return []byte("mp3"), nil
+}
+
+type fakeUploader struct{ files []string }
+
+func (f *fakeUploader) UploadAudio(_ context.Context, channel, thread, filename string, _ []byte) error {
+	f.files = append(f.files, channel+"/"+thread+"/"+filename)
+	return nil
+}
+
+func TestVoiceReplier(t *testing.T) {
+	synth := &fakeSynth{}
+	up := &fakeUploader{}
+	v := NewVoiceReplier(synth, up, 40)
+
+	sent, err := v.Reply(context.Background(), "C1", "1.1", "*Done!* Opened <https://github.com/o/r/pull/3|PR #3>.")
+	if err != nil || !sent {
+		t.Fatalf("short reply: sent=%v err=%v", sent, err)
+	}
+	if synth.texts[0] != "Done! Opened PR 3." {
+		t.Errorf("spoken = %q", synth.texts[0])
+	}
+	if len(up.files) != 1 || up.files[0] != "C1/1.1/reply.mp3" {
+		t.Errorf("uploads = %v", up.files)
+	}
+
+	sent, _ = v.Reply(context.Background(), "C1", "1.1", "This answer is far too long to be read aloud by the voice replier.")
+	if sent || len(synth.texts) != 1 {
+		t.Errorf("long reply was synthesized")
+	}
+}
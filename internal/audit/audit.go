@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// Entry kinds.
+const (
+	KindMessage = "message" // a person's message or slash command
+	KindTool    = "tool"    // an agent's tool call
+)
+
+// maxArgsBytes caps stored tool arguments; file contents passed to Write
+// are not worth keeping in full.
+const maxArgsBytes = 2000
+
+// Entry is one audit record.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Channel string    `json:"channel,omitempty"`
+	Thread  string    `json:"thread,omitempty"`
+	UserID  string    `json:"user_id,omitempty"` // message sender
+	Role    string    `json:"role,omitempty"`    // agent that ran the tool
+	Text    string    `json:"text,omitempty"`    // message text
+	Tool    string    `json:"tool,omitempty"`
+	Args    string    `json:"args,omitempty"`
+	Files   []string  `json:"files,omitempty"`  // files the tool changed
+	Status  string    `json:"status,omitempty"` // "ok", "error", or the exit status line
+}
+
+// Path returns the audit file for a repo.
+func Path(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "audit.jsonl")
+}
+
+// Log appends entries to one file. Entries are never rewritten or
+// removed. Thread-safe.
+type Log struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// Open returns the audit log at path. The file is created on first write.
+func Open(path string) *Log {
+	return &Log{path: path, now: time.Now}
+}
+
+// Append records e, stamping its time.
+func (l *Log) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal audit entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("create audit dir: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// Message records a person's message or command.
+func (l *Log) Message(channel, thread, userID, text string) error {
+	return l.Append(Entry{Kind: KindMessage, Channel: channel, Thread: thread, UserID: userID, Text: text})
+}
+
+// Filter selects entries in Query. Zero fields match everything.
+type Filter struct {
+	Channel string
+	Thread  string
+	UserID  string
+	Tool    string
+	Since   time.Time
+	Limit   int // newest N; 0 means all
+}
+
+func (f Filter) match(e Entry) bool {
+	return (f.Channel == "" || e.Channel == f.Channel) &&
+		(f.Thread == "" || e.Thread == f.Thread) &&
+		(f.UserID == "" || e.UserID == f.UserID) &&
+		(f.Tool == "" || strings.EqualFold(e.Tool, f.Tool)) &&
+		(f.Since.IsZero() || !e.Time.Before(f.Since))
+}
+
+// Query returns matching entries, oldest first.
+func (l *Log) Query(f Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var out []Entry
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil || !f.match(e) {
+			continue
+		}
+		out = append(out, e)
+		if f.Limit > 0 && len(out) > f.Limit {
+			out = out[1:]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	return out, nil
+}
+
+// Tools records every call made through the wrapped executor.
+type Tools struct {
+	agent.ToolExecutor
+	log                   *Log
+	role, channel, thread string
+}
+
+// WrapTools returns e recording its calls for role in the given thread.
+func (l *Log) WrapTools(e agent.ToolExecutor, role, channel, thread string) *Tools {
+	return &Tools{ToolExecutor: e, log: l, role: role, channel: channel, thread: thread}
+}
+
+// Execute implements agent.ToolExecutor. An audit write failure fails the
+// call: on work repositories an unrecorded action is worse than none.
+func (t *Tools) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	result, err := t.ToolExecutor.Execute(ctx, call)
+
+	e := Entry{
+		Kind:    KindTool,
+		Channel: t.channel,
+		Thread:  t.thread,
+		Role:    t.role,
+		Tool:    call.Name,
+		Args:    truncate(call.Arguments, maxArgsBytes),
+		Status:  status(result, err),
+	}
+	if err == nil && !result.IsError {
+		e.Files = changedFiles(call)
+	}
+	if logErr := t.log.Append(e); logErr != nil && err == nil {
+		return result, fmt.Errorf("audit: %w", logErr)
+	}
+	return result, err
+}
+
+// status summarizes how a tool call ended, using the Bash tool's
+// "exit status: ..." line when there is one.
+func status(result agent.ToolResult, err error) string {
+	switch {
+	case err != nil:
+		return "error: " + err.Error()
+	case result.IsError:
+		if line, _, _ := strings.Cut(result.Content, "\n"); strings.HasPrefix(line, "exit status:") {
+			return line
+		}
+		return "error"
+	}
+	return "ok"
+}
+
+// changedFiles extracts the paths written by file-editing tools.
+func changedFiles(call agent.ToolCall) []string {
+	switch call.Name {
+	case "Write", "Edit":
+	default:
+		return nil
+	}
+	var args struct {
+		Path string `json:"path"`
+	}
+	if json.Unmarshal([]byte(call.Arguments), &args) != nil || args.Path == "" {
+		return nil
+	}
+	return []string{args.Path}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+type mockTools struct {
+	results map[string]agent.ToolResult
+}
+
+func (m *mockTools) Execute(_ context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	return m.results[call.Name], nil
+}
+
+func (m *mockTools) ListTools() []agent.ToolDefinition { return nil }
+
+func newLog(t *testing.T) *Log {
+	t.Helper()
+	l := Open(Path(t.TempDir()))
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	l.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return l
+}
+
+func TestToolsRecordsCalls(t *testing.T) {
+	l := newLog(t)
+	tools := l.WrapTools(&mockTools{results: map[string]agent.ToolResult{
+		"Write": {Content: "ok"},
+		"Bash":  {Content: "exit status: 1\nFAIL", IsError: true},
+	}}, "coder", "C1", "T1")
+
+	ctx := context.Background()
+	tools.Execute(ctx, agent.ToolCall{Name: "Write", Arguments: `{"path":"main.go","content":"package main"}`})
+	tools.Execute(ctx, agent.ToolCall{Name: "Bash", Arguments: `{"command":"go test ./..."}`})
+
+	entries, err := l.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if w := entries[0]; w.Role != "coder" || w.Status != "ok" || len(w.Files) != 1 || w.Files[0] != "main.go" {
+		t.Errorf("write entry = %+v", w)
+	}
+	if b := entries[1]; b.Status != "exit status: 1" || len(b.Files) != 0 {
+		t.Errorf("bash entry = %+v", b)
+	}
+
+	info, err := os.Stat(l.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("perm = %o, want 600", perm)
+	}
+}
+
+func TestQueryFilterAndLimit(t *testing.T) {
+	l := newLog(t)
+	l.Message("C1", "T1", "U1", "first")
+	l.Message("C1", "T2", "U2", "other thread")
+	l.Message("C1", "T1", "U1", "second")
+	l.Message("C1", "T1", "U1", "third")
+
+	entries, err := l.Query(Filter{Thread: "T1", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Text != "second" || entries[1].Text != "third" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	if entries, _ := l.Query(Filter{UserID: "U2"}); len(entries) != 1 {
+		t.Errorf("user filter got %d entries", len(entries))
+	}
+}
+
+func TestQueryMissingFile(t *testing.T) {
+	l := Open(filepath.Join(t.TempDir(), "none.jsonl"))
+	entries, err := l.Query(Filter{})
+	if err != nil || entries != nil {
+		t.Errorf("Query = %v, %v", entries, err)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	l := newLog(t)
+	l.Message("C1", "T1", "U1", "/pr merge")
+	l.Message("C1", "T2", "U2", "fix the login bug")
+	cmd := Command(l)
+
+	out, err := cmd.Run(context.Background(), chatcmd.Invocation{Channel: "C1", Thread: "T1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "/pr merge") || strings.Contains(out, "login bug") {
+		t.Errorf("thread output = %q", out)
+	}
+
+	out, _ = cmd.Run(context.Background(), chatcmd.Invocation{Channel: "C1", Thread: "T1", Args: []string{"all"}})
+	if !strings.Contains(out, "login bug") {
+		t.Errorf("all output = %q", out)
+	}
+
+	out, _ = cmd.Run(context.Background(), chatcmd.Invocation{Args: []string{"x"}})
+	if !strings.HasPrefix(out, "Usage:") {
+		t.Errorf("bad args output = %q", out)
+	}
+}
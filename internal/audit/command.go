@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+const defaultLimit = 20
+
+// Command returns /audit [all] [N], which lists the newest N audit entries
+// for the current thread, or for the whole channel with "all".
+func Command(l *Log) *chatcmd.Command {
+	const usage = "/audit [all] [N]"
+	return &chatcmd.Command{
+		Name:        "audit",
+		Usage:       usage,
+		Description: "Show who sent what and which tools ran, with changed files and exit status",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			f := Filter{Channel: inv.Channel, Thread: inv.Thread, Limit: defaultLimit}
+			for _, arg := range inv.Args {
+				if strings.EqualFold(arg, "all") {
+					f.Thread = ""
+					continue
+				}
+				n, err := strconv.Atoi(arg)
+				if err != nil || n <= 0 {
+					return "Usage: " + usage, nil
+				}
+				f.Limit = n
+			}
+			entries, err := l.Query(f)
+			if err != nil {
+				return "", err
+			}
+			return Format(entries), nil
+		},
+	}
+}
+
+// Format renders entries as a Slack message, oldest first.
+func Format(entries []Entry) string {
+	if len(entries) == 0 {
+		return "No audit entries."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Audit log* (%d entries)\n", len(entries))
+	for _, e := range entries {
+		ts := e.Time.UTC().Format("01-02 15:04:05")
+		switch e.Kind {
+		case KindTool:
+			fmt.Fprintf(&b, "`%s` %s ran *%s* %s", ts, e.Role, e.Tool, e.Status)
+			if len(e.Files) > 0 {
+				fmt.Fprintf(&b, " — changed %s", strings.Join(e.Files, ", "))
+			}
+			if e.Args != "" {
+				fmt.Fprintf(&b, "\n    `%s`", truncate(oneLine(e.Args), 120))
+			}
+		default:
+			fmt.Fprintf(&b, "`%s` <@%s>: %s", ts, e.UserID, truncate(oneLine(e.Text), 200))
+		}
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,6 @@
+// Package audit keeps an append-only record of who asked CodeButler to do
+// what and what it did: incoming messages and commands, every tool call
+// with its arguments, the files it changed, and its exit status. Entries go
+// to .codebutler/audit.jsonl (owner-only permissions) and are browsed in
+// chat with /audit.
+package audit
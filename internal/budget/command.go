@@ -0,0 +1,35 @@
+package budget
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// Command returns /budget, which prints the current thread's, today's and
+// this month's spend against their limits, with the month broken down by
+// model.
+func Command(t *Tracker) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "budget",
+		Usage:       "/budget",
+		Description: "Show spend for this thread, today, and this month by model",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			var parts []string
+			if tb := t.GetThreadBudget(inv.Thread); tb != nil {
+				parts = append(parts, FormatCostSummary(tb))
+			}
+			if db := t.GetDailyBudget(); db != nil {
+				parts = append(parts, FormatDailySummary(db))
+			}
+			if mb := t.GetMonthlyBudget(); mb != nil {
+				parts = append(parts, FormatMonthlySummary(mb))
+			}
+			if len(parts) == 0 {
+				return "No spend recorded yet.", nil
+			}
+			return strings.Join(parts, "\n"), nil
+		},
+	}
+}
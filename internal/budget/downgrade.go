@@ -0,0 +1,77 @@
+package budget
+
+import "fmt"
+
+// DefaultDowngradeThreshold is the fraction of the thread budget after
+// which turns switch to a cheaper model.
+const DefaultDowngradeThreshold = 0.8
+
+// Downgrader switches a role to a cheaper model from its pool once a
+// thread has spent most of its budget, so long tasks finish on a cheaper
+// model instead of hard-stopping. It implements agent.ModelSelector.
+type Downgrader struct {
+	tracker   *Tracker
+	pools     map[string][]string // role → candidate models
+	threshold float64
+}
+
+// NewDowngrader creates a downgrader. threshold <= 0 uses
+// DefaultDowngradeThreshold.
+func NewDowngrader(tracker *Tracker, pools map[string][]string, threshold float64) *Downgrader {
+	if threshold <= 0 {
+		threshold = DefaultDowngradeThreshold
+	}
+	return &Downgrader{tracker: tracker, pools: pools, threshold: threshold}
+}
+
+// SelectModel returns the cheapest pool model for role when threadID is
+// over the threshold, otherwise current.
+func (d *Downgrader) SelectModel(role, threadID, current string) string {
+	if !d.tracker.NearLimit(threadID, d.threshold) {
+		return current
+	}
+	if cheaper, ok := CheaperModel(current, d.pools[role]); ok {
+		return cheaper
+	}
+	return current
+}
+
+// NearLimit reports whether a thread with a per-thread limit has spent at
+// least fraction of it.
+func (t *Tracker) NearLimit(threadID string, fraction float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tb, ok := t.threads[threadID]
+	if !ok || tb.LimitUSD <= 0 {
+		return false
+	}
+	return tb.TotalCost >= fraction*tb.LimitUSD
+}
+
+// CheaperModel returns the cheapest model in pool that costs less than
+// current (input + output price per million tokens). Ties keep pool order.
+func CheaperModel(current string, pool []string) (string, bool) {
+	best, bestPrice := "", blendedPrice(current)
+	for _, m := range pool {
+		if p := blendedPrice(m); p < bestPrice {
+			best, bestPrice = m, p
+		}
+	}
+	return best, best != ""
+}
+
+func blendedPrice(model string) float64 {
+	in, out := modelPrice(model)
+	return in + out
+}
+
+// DowngradeNote is appended to the response footer when a run ended on a
+// different model than configured. It returns "" when nothing changed.
+func DowngradeNote(configured, used string) string {
+	if used == "" || used == configured {
+		return ""
+	}
+	return fmt.Sprintf(" · switched from %s to %s (thread budget nearly spent)",
+		ShortModelName(configured), ShortModelName(used))
+}
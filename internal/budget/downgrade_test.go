@@ -0,0 +1,47 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheaperModel(t *testing.T) {
+	pool := []string{"anthropic/claude-opus-4-6", "openai/gpt-4o-mini", "deepseek/deepseek-chat"}
+	if m, ok := CheaperModel("anthropic/claude-sonnet-4-20250514", pool); !ok || m != "deepseek/deepseek-chat" {
+		t.Errorf("got %q, %v", m, ok)
+	}
+	if _, ok := CheaperModel("deepseek/deepseek-chat", pool); ok {
+		t.Error("nothing in the pool is cheaper than deepseek-chat")
+	}
+}
+
+func TestDowngrader_SwitchesNearLimit(t *testing.T) {
+	tr := NewTrackerWithClock(BudgetConfig{PerThreadUSD: 1.0}, t.TempDir(), &fixedClock{now: time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)})
+	d := NewDowngrader(tr, map[string][]string{"coder": {"openai/gpt-4o-mini"}}, 0)
+	sonnet := "anthropic/claude-sonnet-4-20250514"
+
+	// $0.75 spent: under 80%.
+	tr.Record("t1", "coder", sonnet, TokenUsage{PromptTokens: 250_000})
+	if got := d.SelectModel("coder", "t1", sonnet); got != sonnet {
+		t.Errorf("below threshold: got %q", got)
+	}
+
+	// $0.90 spent: over 80%.
+	tr.Record("t1", "coder", sonnet, TokenUsage{PromptTokens: 50_000})
+	if got := d.SelectModel("coder", "t1", sonnet); got != "openai/gpt-4o-mini" {
+		t.Errorf("above threshold: got %q", got)
+	}
+	if got := d.SelectModel("reviewer", "t1", sonnet); got != sonnet {
+		t.Errorf("role without a pool: got %q", got)
+	}
+}
+
+func TestDowngradeNote(t *testing.T) {
+	if note := DowngradeNote("anthropic/claude-sonnet-4-20250514", "anthropic/claude-sonnet-4-20250514"); note != "" {
+		t.Errorf("unchanged model: %q", note)
+	}
+	want := " · switched from sonnet to gpt-4o-mini (thread budget nearly spent)"
+	if note := DowngradeNote("anthropic/claude-sonnet-4-20250514", "openai/gpt-4o-mini"); note != want {
+		t.Errorf("note = %q", note)
+	}
+}
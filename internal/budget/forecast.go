@@ -0,0 +1,136 @@
+package budget
+
+import (
+	"context"
+
+	"github.com/leandrotocalini/codebutler/internal/approval"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+	"github.com/leandrotocalini/codebutler/internal/worktree"
+)
+
+// Forecast defaults, used when the matching ForecastConfig field is zero.
+const (
+	DefaultLargeMessages       = 5
+	DefaultLargeBytes          = 100 * 1024
+	DefaultExpectedTurns       = 15
+	DefaultOutputTokensPerTurn = 1500
+)
+
+// Batch describes an incoming group of messages before any agent runs.
+type Batch struct {
+	Messages        []string
+	AttachmentBytes int64 // total size of attached files
+}
+
+func (b Batch) chars() int64 {
+	var n int64
+	for _, m := range b.Messages {
+		n += int64(len(m))
+	}
+	return n + b.AttachmentBytes
+}
+
+// ForecastConfig tunes what counts as a large batch and how its run is
+// projected.
+type ForecastConfig struct {
+	LargeMessages       int   // a batch with at least this many messages is large
+	LargeBytes          int64 // ...or at least this many bytes of text and attachments
+	ExpectedTurns       int   // agent turns assumed for the run
+	OutputTokensPerTurn int
+}
+
+func (c ForecastConfig) withDefaults() ForecastConfig {
+	if c.LargeMessages <= 0 {
+		c.LargeMessages = DefaultLargeMessages
+	}
+	if c.LargeBytes <= 0 {
+		c.LargeBytes = DefaultLargeBytes
+	}
+	if c.ExpectedTurns <= 0 {
+		c.ExpectedTurns = DefaultExpectedTurns
+	}
+	if c.OutputTokensPerTurn <= 0 {
+		c.OutputTokensPerTurn = DefaultOutputTokensPerTurn
+	}
+	return c
+}
+
+// Large reports whether the batch is big enough to be worth forecasting.
+func (c ForecastConfig) Large(b Batch) bool {
+	c = c.withDefaults()
+	return len(b.Messages) >= c.LargeMessages || b.chars() >= c.LargeBytes
+}
+
+// ForecastBatch projects the cost of running the batch on model. The batch
+// is re-sent as context on every turn, so input grows with the expected
+// turn count (1 token ≈ 4 bytes).
+func ForecastBatch(model string, b Batch, c ForecastConfig) []CostEstimate {
+	c = c.withDefaults()
+	input := int(b.chars()/4) * c.ExpectedTurns
+	output := c.OutputTokensPerTurn * c.ExpectedTurns
+	return []CostEstimate{{
+		Model:            model,
+		EstimatedInput:   input,
+		EstimatedOutput:  output,
+		EstimatedCostUSD: EstimateCost(model, input, output),
+	}}
+}
+
+// BatchGate asks before a large batch whose forecast is above a threshold
+// spawns the agent, so a pasted log dump or a pile of attachments does not
+// silently start an expensive run. The question goes through an
+// approval.Gate, so only the requester and the configured approvers can
+// answer it. Thread-safe.
+type BatchGate struct {
+	approvals *approval.Gate
+	catalog   *messages.Catalog
+	threshold float64
+	model     string
+	config    ForecastConfig
+}
+
+// NewBatchGate creates a gate for batches forecast above thresholdUSD on
+// model. A threshold <= 0 lets every batch through. A nil cat uses the
+// built-in English text.
+func NewBatchGate(approvals *approval.Gate, thresholdUSD float64, model string, config ForecastConfig, cat *messages.Catalog) *BatchGate {
+	return &BatchGate{
+		approvals: approvals,
+		catalog:   cat,
+		threshold: thresholdUSD,
+		model:     model,
+		config:    config,
+	}
+}
+
+// Confirm returns whether the thread's batch may spawn the agent. Small or
+// cheap batches pass at once; otherwise the forecast is posted and Confirm
+// blocks until requester (or an approver) answers, the approval times out,
+// or ctx ends. Each large batch asks on its own.
+func (g *BatchGate) Confirm(ctx context.Context, channel, thread, requester string, b Batch) (bool, error) {
+	if g.threshold <= 0 || !g.config.Large(b) {
+		return true, nil
+	}
+	steps := ForecastBatch(g.model, b, g.config)
+	if EstimatePlanCost(steps) <= g.threshold {
+		return true, nil
+	}
+	return g.approvals.Request(ctx, channel, thread, requester, FormatBatchForecast(g.catalog, b, steps, g.threshold))
+}
+
+// FormatBatchForecast renders the question asked for a large batch. A nil
+// cat uses the built-in English text.
+func FormatBatchForecast(cat *messages.Catalog, b Batch, steps []CostEstimate, thresholdUSD float64) string {
+	if cat == nil {
+		cat = messages.New(messages.DefaultLocale)
+	}
+	var attachments string
+	if b.AttachmentBytes > 0 {
+		attachments = worktree.FormatSize(b.AttachmentBytes)
+	}
+	return cat.Render(messages.BudgetBatchForecast, map[string]any{
+		"Messages":    len(b.Messages),
+		"Attachments": attachments,
+		"Cost":        EstimatePlanCost(steps),
+		"Threshold":   thresholdUSD,
+	})
+}
@@ -0,0 +1,124 @@
+package budget
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/approval"
+)
+
+func TestForecastConfig_Large(t *testing.T) {
+	var c ForecastConfig
+	if c.Large(Batch{Messages: []string{"fix the typo"}}) {
+		t.Error("single short message should not be large")
+	}
+	if !c.Large(Batch{Messages: make([]string, DefaultLargeMessages)}) {
+		t.Error("many messages should be large")
+	}
+	if !c.Large(Batch{Messages: []string{"see attached"}, AttachmentBytes: DefaultLargeBytes}) {
+		t.Error("big attachment should be large")
+	}
+	if (ForecastConfig{LargeMessages: 2}).Large(Batch{Messages: []string{"a"}}) {
+		t.Error("custom threshold not applied")
+	}
+}
+
+func TestForecastBatch(t *testing.T) {
+	b := Batch{Messages: []string{strings.Repeat("x", 4000)}}
+	steps := ForecastBatch("anthropic/claude-sonnet-4-5-20250929", b, ForecastConfig{ExpectedTurns: 10, OutputTokensPerTurn: 1000})
+	if len(steps) != 1 {
+		t.Fatalf("steps = %d", len(steps))
+	}
+	s := steps[0]
+	if s.EstimatedInput != 10_000 || s.EstimatedOutput != 10_000 {
+		t.Errorf("tokens = %d/%d", s.EstimatedInput, s.EstimatedOutput)
+	}
+	want := EstimateCost(s.Model, 10_000, 10_000)
+	if s.EstimatedCostUSD != want || EstimatePlanCost(steps) != want {
+		t.Errorf("cost = %f, want %f", s.EstimatedCostUSD, want)
+	}
+}
+
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *recordingSender) SendMessage(_ context.Context, _, _, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, text)
+	return nil
+}
+
+func (s *recordingSender) last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sent) == 0 {
+		return ""
+	}
+	return s.sent[len(s.sent)-1]
+}
+
+// answer waits for the thread's approval question and replies as userID.
+func answer(t *testing.T, gate *approval.Gate, thread, userID, text string) bool {
+	t.Helper()
+	for i := 0; i < 100 && !gate.Pending("C1", thread); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	return gate.HandleReply("C1", thread, userID, text)
+}
+
+func TestBatchGate(t *testing.T) {
+	sender := &recordingSender{}
+	approvals := approval.NewGate(sender)
+	g := NewBatchGate(approvals, 1.0, "anthropic/claude-opus-4-6", ForecastConfig{}, nil)
+	ctx := context.Background()
+	small := Batch{Messages: []string{"hi"}}
+	big := Batch{Messages: []string{"here is the dump"}, AttachmentBytes: 2 << 20}
+
+	if ok, err := g.Confirm(ctx, "C1", "t1", "U1", small); !ok || err != nil {
+		t.Error("small batch should pass")
+	}
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.Confirm(ctx, "C1", "t1", "U1", big)
+		done <- ok
+	}()
+	if answer(t, approvals, "t1", "U2", "yes") {
+		t.Error("someone other than the requester confirmed the spend")
+	}
+	if !answer(t, approvals, "t1", "U1", "1") || !<-done {
+		t.Fatal("requester's 1 should confirm")
+	}
+	if p := sender.last(); !strings.Contains(p, "2.0 MB of attachments") || !strings.Contains(p, "$1.00 confirmation threshold") {
+		t.Errorf("prompt = %q", p)
+	}
+
+	go func() {
+		ok, _ := g.Confirm(ctx, "C1", "t1", "U1", big)
+		done <- ok
+	}()
+	if !answer(t, approvals, "t1", "U1", "2") || <-done {
+		t.Error("a confirmation covers one batch; 2 should cancel the next")
+	}
+}
+
+func TestFormatBatchForecast_NoAttachments(t *testing.T) {
+	got := FormatBatchForecast(nil, Batch{Messages: make([]string, 6)}, []CostEstimate{{EstimatedCostUSD: 2.5}}, 1)
+	want := "This request (6 messages) is forecast at ~$2.50, above the $1.00 confirmation threshold. Run it?"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBatchGate_Disabled(t *testing.T) {
+	g := NewBatchGate(approval.NewGate(&recordingSender{}), 0, "anthropic/claude-opus-4-6", ForecastConfig{}, nil)
+	if ok, _ := g.Confirm(context.Background(), "C1", "t1", "U1", Batch{AttachmentBytes: 50 << 20}); !ok {
+		t.Error("zero threshold should never ask")
+	}
+}
@@ -0,0 +1,292 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+// MessageSender posts the pause notice.
+type MessageSender interface {
+	SendMessage(ctx context.Context, channel, thread, text string) error
+}
+
+// Guard enforces the Tracker's limits on live agent runs. Each provider
+// call is recorded; once the thread, day, month or the call's model is over
+// its limit, the next call blocks until an approver replies /approve-budget (or the run's context
+// ends), so the run picks up exactly where it paused. Thread-safe.
+type Guard struct {
+	tracker   *Tracker
+	sender    MessageSender
+	catalog   *messages.Catalog
+	logger    *slog.Logger
+	approvers map[string]bool
+
+	mu      sync.Mutex
+	waiting map[string]chan struct{} // thread → closed on approval
+}
+
+// GuardOption configures a Guard.
+type GuardOption func(*Guard)
+
+// WithGuardLogger sets the structured logger.
+func WithGuardLogger(l *slog.Logger) GuardOption {
+	return func(g *Guard) {
+		g.logger = l
+	}
+}
+
+// WithGuardCatalog renders the pause notice in the catalog's locale, with
+// the repo's overrides. The default is the built-in English text.
+func WithGuardCatalog(c *messages.Catalog) GuardOption {
+	return func(g *Guard) {
+		g.catalog = c
+	}
+}
+
+// WithGuardApprovers restricts /approve-budget to these user IDs, the same
+// list approval.Gate takes. With none configured, anyone in the thread may
+// approve.
+func WithGuardApprovers(userIDs ...string) GuardOption {
+	return func(g *Guard) {
+		for _, id := range userIDs {
+			if id != "" {
+				g.approvers[id] = true
+			}
+		}
+	}
+}
+
+// NewGuard creates a guard over tracker that posts pause notices through
+// sender.
+func NewGuard(tracker *Tracker, sender MessageSender, opts ...GuardOption) *Guard {
+	g := &Guard{
+		tracker:   tracker,
+		sender:    sender,
+		logger:    slog.Default(),
+		approvers: make(map[string]bool),
+		waiting:   make(map[string]chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Wrap returns p with budget enforcement for one role's run in a thread.
+func (g *Guard) Wrap(p agent.LLMProvider, role, channel, thread string) agent.LLMProvider {
+	return &guardedProvider{LLMProvider: p, guard: g, role: role, channel: channel, thread: thread}
+}
+
+type guardedProvider struct {
+	agent.LLMProvider
+	guard                 *Guard
+	role, channel, thread string
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *guardedProvider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	if err := p.guard.wait(ctx, p.channel, p.thread, req.Model); err != nil {
+		return nil, err
+	}
+	resp, err := p.LLMProvider.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// Over-limit usage is still recorded; the next call pauses.
+	if err := p.guard.tracker.RecordGeneration(p.thread, p.role, req.Model, resp.ID, TokenUsage(resp.Usage)); err != nil {
+		p.guard.logger.Info("budget limit reached", "thread", p.thread, "role", p.role, "err", err)
+	}
+	return resp, nil
+}
+
+// exceeded reports the limit blocking a call with model in thread, or nil.
+// An empty model skips the per-model check.
+func (g *Guard) exceeded(thread, model string) *BudgetExceeded {
+	if _, paused := g.tracker.CheckThread(thread); paused {
+		tb := g.tracker.GetThreadBudget(thread)
+		return &BudgetExceeded{Scope: "thread", ThreadID: thread, LimitUSD: tb.LimitUSD, ActualUSD: tb.TotalCost}
+	}
+	if _, exhausted := g.tracker.CheckDaily(); exhausted {
+		db := g.tracker.GetDailyBudget()
+		return &BudgetExceeded{Scope: "day", LimitUSD: db.LimitUSD, ActualUSD: db.TotalCost}
+	}
+	if _, exhausted := g.tracker.CheckMonthly(); exhausted {
+		mb := g.tracker.GetMonthlyBudget()
+		return &BudgetExceeded{Scope: "month", LimitUSD: mb.LimitUSD, ActualUSD: mb.TotalCost}
+	}
+	if model == "" {
+		return nil
+	}
+	if key, exhausted := g.tracker.CheckModel(model); exhausted {
+		mb := g.tracker.GetMonthlyBudget()
+		return &BudgetExceeded{Scope: "model", Model: key, LimitUSD: mb.ModelLimits[key], ActualUSD: mb.modelKeyCost(key)}
+	}
+	return nil
+}
+
+// globalExceeded reports whether a limit shared by every thread is hit:
+// the day, the month, or any per-model limit.
+func (g *Guard) globalExceeded() bool {
+	if _, exhausted := g.tracker.CheckDaily(); exhausted {
+		return true
+	}
+	if _, exhausted := g.tracker.CheckMonthly(); exhausted {
+		return true
+	}
+	mb := g.tracker.GetMonthlyBudget()
+	return mb != nil && len(mb.ModelsExhausted) > 0
+}
+
+// wait blocks while thread is over budget. The notice is posted once per
+// pause, however many runs in the thread are waiting.
+func (g *Guard) wait(ctx context.Context, channel, thread, model string) error {
+	for {
+		ex := g.exceeded(thread, model)
+		if ex == nil {
+			return nil
+		}
+
+		g.mu.Lock()
+		ch, ok := g.waiting[thread]
+		if !ok {
+			ch = make(chan struct{})
+			g.waiting[thread] = ch
+		}
+		g.mu.Unlock()
+		if !ok {
+			if err := g.sender.SendMessage(ctx, channel, thread, FormatPauseNotice(g.catalog, ex)); err != nil {
+				g.logger.Warn("post budget notice failed", "thread", thread, "err", err)
+			}
+		}
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return fmt.Errorf("%w (waiting for /approve-budget)", ex)
+		}
+	}
+}
+
+// ApproveResult is the outcome of Guard.Approve.
+type ApproveResult int
+
+const (
+	// ApproveDenied means the user is not a configured approver.
+	ApproveDenied ApproveResult = iota
+	// ApproveNothingPaused means no limit blocks the thread.
+	ApproveNothingPaused
+	// ApproveNeedsGlobal means a shared limit still blocks the thread and
+	// global was not requested. The thread's own limit, if hit, was lifted.
+	ApproveNeedsGlobal
+	// ApproveResumed means the thread's runs continue.
+	ApproveResumed
+)
+
+// Approve lifts the limits blocking thread on behalf of userID, each with a
+// fresh allocation. The thread's own limit is always lifted; the day, month
+// and per-model limits are shared by every thread, so they are lifted only
+// when global is set.
+func (g *Guard) Approve(thread, userID string, global bool) ApproveResult {
+	if len(g.approvers) > 0 && !g.approvers[userID] {
+		return ApproveDenied
+	}
+
+	_, threadPaused := g.tracker.CheckThread(thread)
+	globalBlocked := g.globalExceeded()
+	if !threadPaused && !globalBlocked && !g.Paused(thread) {
+		return ApproveNothingPaused
+	}
+	if threadPaused {
+		g.tracker.ResumeThread(thread)
+	}
+	if globalBlocked {
+		if !global {
+			return ApproveNeedsGlobal
+		}
+		g.tracker.ResumeDaily()
+		g.tracker.ResumeMonthly()
+	}
+
+	// Lifting a shared limit wakes every paused thread so each re-checks
+	// its own limits; otherwise only this thread's runs continue.
+	g.mu.Lock()
+	var wake []chan struct{}
+	for t, ch := range g.waiting {
+		if t == thread || globalBlocked {
+			wake = append(wake, ch)
+			delete(g.waiting, t)
+		}
+	}
+	g.mu.Unlock()
+	for _, ch := range wake {
+		close(ch)
+	}
+	return ApproveResumed
+}
+
+// Paused reports whether thread is waiting for approval.
+func (g *Guard) Paused(thread string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.waiting[thread]
+	return ok
+}
+
+// pauseNoticeKeys maps a BudgetExceeded scope to its catalog message.
+var pauseNoticeKeys = map[string]messages.Key{
+	"thread": messages.BudgetThreadExceeded,
+	"day":    messages.BudgetDailyExceeded,
+	"month":  messages.BudgetMonthlyExceeded,
+	"model":  messages.BudgetModelExceeded,
+}
+
+// FormatPauseNotice renders the message posted when a run pauses on a
+// limit. A nil cat uses the built-in English text.
+func FormatPauseNotice(cat *messages.Catalog, ex *BudgetExceeded) string {
+	if cat == nil {
+		cat = messages.New(messages.DefaultLocale)
+	}
+	key, ok := pauseNoticeKeys[ex.Scope]
+	if !ok {
+		key = messages.BudgetThreadExceeded
+	}
+	return cat.Render(key, map[string]any{"Model": ex.Model, "Spent": ex.ActualUSD, "Limit": ex.LimitUSD})
+}
+
+// approveReplies maps an ApproveResult to its catalog message.
+var approveReplies = map[ApproveResult]messages.Key{
+	ApproveDenied:        messages.BudgetApproveDenied,
+	ApproveNothingPaused: messages.BudgetNothingPaused,
+	ApproveNeedsGlobal:   messages.BudgetNeedsGlobal,
+	ApproveResumed:       messages.BudgetApproved,
+}
+
+// ApproveCommand returns /approve-budget, which resumes the current thread.
+// "/approve-budget global" also lifts the day, month and per-model limits.
+func ApproveCommand(g *Guard) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "approve-budget",
+		Usage:       "/approve-budget [global]",
+		Description: "Resume work paused on a budget limit with a fresh allocation",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			global := len(inv.Args) > 0 && strings.EqualFold(inv.Args[0], "global")
+			result := g.Approve(inv.Thread, inv.UserID, global)
+			cat := g.catalog
+			if cat == nil {
+				cat = messages.New(messages.DefaultLocale)
+			}
+			remaining, _ := g.tracker.CheckThread(inv.Thread)
+			return cat.Render(approveReplies[result], map[string]any{
+				"Limited":   g.tracker.Limits().PerThreadUSD > 0,
+				"Remaining": remaining,
+			}), nil
+		},
+	}
+}
@@ -0,0 +1,212 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+type usageProvider struct {
+	usage agent.TokenUsage
+	calls int
+}
+
+func (p *usageProvider) ChatCompletion(context.Context, agent.ChatRequest) (*agent.ChatResponse, error) {
+	p.calls++
+	return &agent.ChatResponse{Message: agent.Message{Role: "assistant", Content: "ok"}, Usage: p.usage}, nil
+}
+
+type noticeSender struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (s *noticeSender) SendMessage(_ context.Context, _, _, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, text)
+	return nil
+}
+
+func (s *noticeSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.msgs)
+}
+
+// expensive costs $0.45 on opus (15 × 10k + 75 × 4k per million).
+var expensive = agent.TokenUsage{PromptTokens: 10000, CompletionTokens: 4000, TotalTokens: 14000}
+
+func TestGuardPausesAndResumes(t *testing.T) {
+	tracker := NewTracker(BudgetConfig{PerThreadUSD: 0.5}, "")
+	sender := &noticeSender{}
+	guard := NewGuard(tracker, sender)
+	inner := &usageProvider{usage: expensive}
+	p := guard.Wrap(inner, "coder", "C", "T")
+	req := agent.ChatRequest{Model: "anthropic/claude-opus-4-6"}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := p.ChatCompletion(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.ChatCompletion(ctx, req)
+		done <- err
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for !guard.Paused("T") {
+		if time.Now().After(deadline) {
+			t.Fatal("third call did not pause")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if sender.count() != 1 || !strings.Contains(sender.msgs[0], "/approve-budget") {
+		t.Fatalf("notices = %v", sender.msgs)
+	}
+
+	out, err := ApproveCommand(guard).Run(ctx, chatcmd.Invocation{Thread: "T"})
+	if err != nil || !strings.Contains(out, "resuming") {
+		t.Fatalf("approve = %q, %v", out, err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("resumed call failed: %v", err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("provider calls = %d, want 3", inner.calls)
+	}
+	// The approval is a fresh allocation, not a single call.
+	if remaining, paused := tracker.CheckThread("T"); paused || remaining <= 0 {
+		t.Errorf("after approval remaining=%.2f paused=%v", remaining, paused)
+	}
+}
+
+func TestGuardCancelWhilePaused(t *testing.T) {
+	tracker := NewTracker(BudgetConfig{PerDayUSD: 0.1}, "")
+	guard := NewGuard(tracker, &noticeSender{})
+	p := guard.Wrap(&usageProvider{usage: expensive}, "pm", "C", "T")
+	req := agent.ChatRequest{Model: "anthropic/claude-opus-4-6"}
+
+	p.ChatCompletion(context.Background(), req)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := p.ChatCompletion(ctx, req)
+	var ex *BudgetExceeded
+	if !errors.As(err, &ex) || ex.Scope != "day" {
+		t.Fatalf("err = %v, want daily BudgetExceeded", err)
+	}
+
+	if got := guard.Approve("T", "U1", false); got != ApproveNeedsGlobal {
+		t.Errorf("thread approval of a shared limit = %v, want ApproveNeedsGlobal", got)
+	}
+	if _, exhausted := tracker.CheckDaily(); !exhausted {
+		t.Error("day lifted without a global approval")
+	}
+	if got := guard.Approve("T", "U1", true); got != ApproveResumed {
+		t.Errorf("global approval = %v, want ApproveResumed", got)
+	}
+	if _, exhausted := tracker.CheckDaily(); exhausted {
+		t.Error("day still exhausted after approval")
+	}
+	if out, _ := ApproveCommand(guard).Run(context.Background(), chatcmd.Invocation{Thread: "T"}); !strings.HasPrefix(out, "Nothing is paused") {
+		t.Errorf("second approve = %q", out)
+	}
+}
+
+func TestGuardGlobalApprovalWakesAllThreads(t *testing.T) {
+	tracker := NewTracker(BudgetConfig{PerDayUSD: 0.1}, "")
+	guard := NewGuard(tracker, &noticeSender{})
+	tracker.Record("T0", "pm", "anthropic/claude-opus-4-6", TokenUsage(expensive))
+	req := agent.ChatRequest{Model: "anthropic/claude-opus-4-6"}
+
+	done := make(chan error, 2)
+	for _, thread := range []string{"T1", "T2"} {
+		p := guard.Wrap(&usageProvider{usage: agent.TokenUsage{}}, "coder", "C", thread)
+		go func() {
+			_, err := p.ChatCompletion(context.Background(), req)
+			done <- err
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !guard.Paused("T1") || !guard.Paused("T2") {
+		if time.Now().After(deadline) {
+			t.Fatal("threads did not pause on the daily limit")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := guard.Approve("T1", "U1", true); got != ApproveResumed {
+		t.Fatalf("global approval = %v, want ApproveResumed", got)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("resumed call failed: %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("a paused thread stayed blocked after the global approval")
+		}
+	}
+	if guard.Paused("T2") {
+		t.Error("T2 still marked paused")
+	}
+}
+
+func TestGuardApproversOnly(t *testing.T) {
+	tracker := NewTracker(BudgetConfig{PerThreadUSD: 0.1}, "")
+	guard := NewGuard(tracker, &noticeSender{}, WithGuardApprovers("ULEAD"))
+	tracker.Record("T", "coder", "anthropic/claude-opus-4-6", TokenUsage(expensive))
+
+	cmd := ApproveCommand(guard)
+	out, _ := cmd.Run(context.Background(), chatcmd.Invocation{Thread: "T", UserID: "U1"})
+	if !strings.HasPrefix(out, "Only a configured approver") {
+		t.Errorf("non-approver reply = %q", out)
+	}
+	if _, paused := tracker.CheckThread("T"); !paused {
+		t.Fatal("non-approver lifted the limit")
+	}
+	out, _ = cmd.Run(context.Background(), chatcmd.Invocation{Thread: "T", UserID: "ULEAD"})
+	if !strings.HasPrefix(out, "Budget approved; resuming. $") {
+		t.Errorf("approver reply = %q", out)
+	}
+}
+
+func TestFormatPauseNotice(t *testing.T) {
+	ex := &BudgetExceeded{Scope: "day", LimitUSD: 5, ActualUSD: 5.5}
+	if got, want := FormatPauseNotice(nil, ex), ":money_with_wings: Today's budget is exceeded ($5.50 of $5.00). Work is paused; reply /approve-budget global to continue."; got != want {
+		t.Errorf("en = %q, want %q", got, want)
+	}
+	es := FormatPauseNotice(messages.New("es"), ex)
+	if !strings.Contains(es, "presupuesto de hoy") || !strings.Contains(es, "$5.50") {
+		t.Errorf("es = %q", es)
+	}
+
+	cat := messages.New("en")
+	if err := cat.Override(messages.BudgetDailyExceeded, "Out of budget: {{.Spent}}"); err != nil {
+		t.Fatal(err)
+	}
+	tracker := NewTracker(BudgetConfig{PerDayUSD: 0.1}, "")
+	sender := &noticeSender{}
+	guard := NewGuard(tracker, sender, WithGuardCatalog(cat))
+	p := guard.Wrap(&usageProvider{usage: expensive}, "pm", "C", "T")
+	req := agent.ChatRequest{Model: "anthropic/claude-opus-4-6"}
+	p.ChatCompletion(context.Background(), req)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	p.ChatCompletion(ctx, req)
+	if sender.count() != 1 || !strings.HasPrefix(sender.msgs[0], "Out of budget: ") {
+		t.Errorf("notices = %v", sender.msgs)
+	}
+}
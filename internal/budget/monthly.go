@@ -0,0 +1,327 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const monthFormat = "2006-01"
+
+// MonthlyBudget tracks one calendar month's spend, in total and per model.
+// A new month starts from zero. Limits come from the tracker's config plus
+// whatever approvals granted this month.
+type MonthlyBudget struct {
+	Month       string  `json:"month"` // YYYY-MM
+	TotalCost   float64 `json:"total_cost"`
+	TotalTokens int     `json:"total_tokens"`
+	Calls       int     `json:"calls"`
+	LimitUSD    float64 `json:"limit_usd"`           // effective limit (PerMonthUSD + ExtraUSD); 0 = unlimited
+	ExtraUSD    float64 `json:"extra_usd,omitempty"` // allowance granted by approvals on top of PerMonthUSD
+	Exhausted   bool    `json:"exhausted"`
+
+	Models map[string]*ModelSpend `json:"models"` // model ID → spend
+
+	// ModelLimits, ModelExtraUSD and ModelsExhausted are keyed like
+	// BudgetConfig.PerModelUSD. ModelLimits is the effective limit, the
+	// configured one plus ModelExtraUSD.
+	ModelLimits     map[string]float64 `json:"model_limits,omitempty"`
+	ModelExtraUSD   map[string]float64 `json:"model_extra_usd,omitempty"`
+	ModelsExhausted map[string]bool    `json:"models_exhausted,omitempty"`
+}
+
+// ModelSpend is one model's share of a month.
+type ModelSpend struct {
+	CostUSD float64 `json:"cost_usd"`
+	Tokens  int     `json:"tokens"`
+	Calls   int     `json:"calls"`
+}
+
+// modelKeyCost sums the month's spend on models matching a PerModelUSD key.
+func (mb *MonthlyBudget) modelKeyCost(key string) float64 {
+	var total float64
+	for model, s := range mb.Models {
+		if modelMatches(key, model) {
+			total += s.CostUSD
+		}
+	}
+	return total
+}
+
+func modelMatches(key, model string) bool {
+	return key == model || strings.Contains(model, key)
+}
+
+// recordMonthly adds entry to the current month and reports the first
+// monthly or per-model limit it crossed. Must be called under lock.
+func (t *Tracker) recordMonthly(e UsageEntry) error {
+	mb := t.getOrCreateMonthly(e.Timestamp.Format(monthFormat))
+	mb.TotalCost += e.CostUSD
+	mb.TotalTokens += e.Tokens.TotalTokens
+	mb.Calls++
+	ms, ok := mb.Models[e.Model]
+	if !ok {
+		ms = &ModelSpend{}
+		mb.Models[e.Model] = ms
+	}
+	ms.CostUSD += e.CostUSD
+	ms.Tokens += e.Tokens.TotalTokens
+	ms.Calls++
+	t.refreshMonthlyLimits(mb)
+
+	var exceeded error
+	for _, key := range sortedKeys(mb.ModelLimits) {
+		limit := mb.ModelLimits[key]
+		if !modelMatches(key, e.Model) || limit <= 0 {
+			continue
+		}
+		if spent := mb.modelKeyCost(key); spent > limit {
+			mb.ModelsExhausted[key] = true
+			if exceeded == nil {
+				exceeded = &BudgetExceeded{Scope: "model", Model: key, LimitUSD: limit, ActualUSD: spent}
+			}
+		}
+	}
+	if mb.LimitUSD > 0 && mb.TotalCost > mb.LimitUSD {
+		mb.Exhausted = true
+		return &BudgetExceeded{Scope: "month", LimitUSD: mb.LimitUSD, ActualUSD: mb.TotalCost}
+	}
+	return exceeded
+}
+
+// CheckMonthly returns whether this month's budget allows more work.
+func (t *Tracker) CheckMonthly() (remaining float64, exhausted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	mb, ok := t.monthly[t.clock.Now().Format(monthFormat)]
+	if !ok {
+		return t.config.PerMonthUSD, false
+	}
+	t.refreshMonthlyLimits(mb)
+	if mb.LimitUSD <= 0 {
+		return 0, false // unlimited
+	}
+	return mb.LimitUSD - mb.TotalCost, mb.Exhausted
+}
+
+// refreshMonthlyLimits recomputes mb's effective limits from the config, so
+// a changed limit applies to a month already tracked. Must be called under
+// lock.
+func (t *Tracker) refreshMonthlyLimits(mb *MonthlyBudget) {
+	mb.LimitUSD = 0
+	if t.config.PerMonthUSD > 0 {
+		mb.LimitUSD = t.config.PerMonthUSD + mb.ExtraUSD
+	}
+	mb.ModelLimits = make(map[string]float64, len(t.config.PerModelUSD))
+	for key, limit := range t.config.PerModelUSD {
+		if limit > 0 {
+			mb.ModelLimits[key] = limit + mb.ModelExtraUSD[key]
+		}
+	}
+}
+
+// CheckModel reports whether model is blocked by an exhausted per-model
+// limit this month, and which PerModelUSD key blocks it.
+func (t *Tracker) CheckModel(model string) (key string, exhausted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	mb, ok := t.monthly[t.clock.Now().Format(monthFormat)]
+	if !ok {
+		return "", false
+	}
+	for _, k := range sortedKeys(mb.ModelLimits) {
+		if mb.ModelsExhausted[k] && modelMatches(k, model) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// ResumeMonthly lifts this month's exhaustion and any exhausted per-model
+// limits (user approved continuation), each with a fresh allocation on top
+// of what has been spent.
+func (t *Tracker) ResumeMonthly() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	mb, ok := t.monthly[t.clock.Now().Format(monthFormat)]
+	if !ok {
+		return
+	}
+	if mb.Exhausted {
+		mb.ExtraUSD = mb.TotalCost
+	}
+	mb.Exhausted = false
+	for key := range mb.ModelsExhausted {
+		mb.ModelExtraUSD[key] = mb.modelKeyCost(key)
+		delete(mb.ModelsExhausted, key)
+	}
+	t.refreshMonthlyLimits(mb)
+}
+
+// GetMonthlyBudget returns a copy of this month's budget (nil if no
+// activity).
+func (t *Tracker) GetMonthlyBudget() *MonthlyBudget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	mb, ok := t.monthly[t.clock.Now().Format(monthFormat)]
+	if !ok {
+		return nil
+	}
+	t.refreshMonthlyLimits(mb)
+	return mb.clone()
+}
+
+func (mb *MonthlyBudget) clone() *MonthlyBudget {
+	cp := *mb
+	cp.Models = make(map[string]*ModelSpend, len(mb.Models))
+	for k, v := range mb.Models {
+		s := *v
+		cp.Models[k] = &s
+	}
+	cp.ModelLimits = make(map[string]float64, len(mb.ModelLimits))
+	for k, v := range mb.ModelLimits {
+		cp.ModelLimits[k] = v
+	}
+	cp.ModelExtraUSD = make(map[string]float64, len(mb.ModelExtraUSD))
+	for k, v := range mb.ModelExtraUSD {
+		cp.ModelExtraUSD[k] = v
+	}
+	cp.ModelsExhausted = make(map[string]bool, len(mb.ModelsExhausted))
+	for k, v := range mb.ModelsExhausted {
+		cp.ModelsExhausted[k] = v
+	}
+	return &cp
+}
+
+// SaveMonthly persists this month's budget so monthly limits survive
+// restarts.
+func (t *Tracker) SaveMonthly() error {
+	mb := t.GetMonthlyBudget()
+	if mb == nil {
+		return nil
+	}
+
+	dir := filepath.Join(t.dataDir, "budgets")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create budget dir: %w", err)
+	}
+	data, err := json.MarshalIndent(mb, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal monthly budget: %w", err)
+	}
+	path := filepath.Join(dir, "month-"+mb.Month+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write monthly budget: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadMonthly reads this month's budget from disk. A file from an earlier
+// month is never read: the new month starts fresh.
+func (t *Tracker) LoadMonthly() error {
+	month := t.clock.Now().Format(monthFormat)
+	data, err := os.ReadFile(filepath.Join(t.dataDir, "budgets", "month-"+month+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read monthly budget: %w", err)
+	}
+
+	var mb MonthlyBudget
+	if err := json.Unmarshal(data, &mb); err != nil {
+		return fmt.Errorf("parse monthly budget: %w", err)
+	}
+	if mb.Models == nil {
+		mb.Models = make(map[string]*ModelSpend)
+	}
+	if mb.ModelExtraUSD == nil {
+		mb.ModelExtraUSD = make(map[string]float64)
+	}
+	if mb.ModelsExhausted == nil {
+		mb.ModelsExhausted = make(map[string]bool)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.monthly[month] = &mb
+	return nil
+}
+
+// getOrCreateMonthly returns or creates a monthly budget. Must be called
+// under lock.
+func (t *Tracker) getOrCreateMonthly(month string) *MonthlyBudget {
+	if mb, ok := t.monthly[month]; ok {
+		return mb
+	}
+	mb := &MonthlyBudget{
+		Month:           month,
+		Models:          make(map[string]*ModelSpend),
+		ModelExtraUSD:   make(map[string]float64),
+		ModelsExhausted: make(map[string]bool),
+	}
+	t.refreshMonthlyLimits(mb)
+	t.monthly[month] = mb
+	return mb
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatMonthlySummary creates a human-readable monthly cost summary with a
+// per-model breakdown, most expensive first.
+func FormatMonthlySummary(mb *MonthlyBudget) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("## Monthly Cost Summary — %s\n\n", mb.Month))
+	b.WriteString(fmt.Sprintf("**Total cost:** $%.4f", mb.TotalCost))
+	if mb.LimitUSD > 0 {
+		b.WriteString(fmt.Sprintf(" / $%.2f limit (%.1f%%)", mb.LimitUSD, mb.TotalCost/mb.LimitUSD*100))
+	}
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("**Total tokens:** %d\n", mb.TotalTokens))
+	b.WriteString(fmt.Sprintf("**API calls:** %d\n", mb.Calls))
+
+	if len(mb.Models) > 0 {
+		models := sortedKeys(mb.Models)
+		sort.SliceStable(models, func(i, j int) bool {
+			return mb.Models[models[i]].CostUSD > mb.Models[models[j]].CostUSD
+		})
+		b.WriteString("\n| Model | Calls | Tokens | Cost |\n")
+		b.WriteString("|-------|-------|--------|------|\n")
+		for _, m := range models {
+			s := mb.Models[m]
+			b.WriteString(fmt.Sprintf("| %s | %d | %d | $%.4f |\n", m, s.Calls, s.Tokens, s.CostUSD))
+		}
+	}
+
+	if len(mb.ModelLimits) > 0 {
+		b.WriteString("\n**Model limits:**\n")
+		for _, key := range sortedKeys(mb.ModelLimits) {
+			line := fmt.Sprintf("- %s: $%.4f / $%.2f", key, mb.modelKeyCost(key), mb.ModelLimits[key])
+			if mb.ModelsExhausted[key] {
+				line += " — exhausted"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if mb.Exhausted {
+		b.WriteString("\n**Status:** Monthly budget exhausted — all agents stopped\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+const (
+	opus   = "anthropic/claude-opus-4-6"
+	sonnet = "anthropic/claude-sonnet-4-20250514"
+)
+
+// million costs $15 on opus input pricing and $3 on sonnet.
+var million = TokenUsage{PromptTokens: 1_000_000, TotalTokens: 1_000_000}
+
+func TestPerModelLimit(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+	tr := NewTrackerWithClock(BudgetConfig{PerModelUSD: map[string]float64{"opus": 20}}, "", clock)
+
+	if err := tr.Record("T1", "coder", opus, million); err != nil {
+		t.Fatalf("first opus call: %v", err)
+	}
+	err := tr.Record("T2", "coder", opus, million)
+	var ex *BudgetExceeded
+	if !errors.As(err, &ex) || ex.Scope != "model" || ex.Model != "opus" || ex.ActualUSD != 30 {
+		t.Fatalf("second opus call: %v", err)
+	}
+	if key, blocked := tr.CheckModel(opus); !blocked || key != "opus" {
+		t.Errorf("CheckModel(opus) = %q, %v", key, blocked)
+	}
+	if _, blocked := tr.CheckModel(sonnet); blocked {
+		t.Error("sonnet should not be blocked by the opus cap")
+	}
+	if err := tr.Record("T1", "reviewer", sonnet, million); err != nil {
+		t.Errorf("sonnet call: %v", err)
+	}
+
+	tr.ResumeMonthly()
+	if _, blocked := tr.CheckModel(opus); blocked {
+		t.Error("opus still blocked after resume")
+	}
+	if mb := tr.GetMonthlyBudget(); mb.ModelLimits["opus"] != 50 {
+		t.Errorf("fresh opus limit = %.2f, want 50", mb.ModelLimits["opus"])
+	}
+}
+
+func TestMonthlyLimitAndRollover(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)}
+	dir := t.TempDir()
+	tr := NewTrackerWithClock(BudgetConfig{PerMonthUSD: 2}, dir, clock)
+
+	err := tr.Record("T1", "coder", sonnet, million)
+	var ex *BudgetExceeded
+	if !errors.As(err, &ex) || ex.Scope != "month" {
+		t.Fatalf("Record = %v, want monthly BudgetExceeded", err)
+	}
+	if _, exhausted := tr.CheckMonthly(); !exhausted {
+		t.Error("month should be exhausted")
+	}
+	if err := tr.SaveMonthly(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted tracker in the same month picks the state back up.
+	again := NewTrackerWithClock(BudgetConfig{PerMonthUSD: 2}, dir, clock)
+	if err := again.LoadMonthly(); err != nil {
+		t.Fatal(err)
+	}
+	if _, exhausted := again.CheckMonthly(); !exhausted {
+		t.Error("reloaded month should be exhausted")
+	}
+
+	clock.now = time.Date(2026, 4, 1, 0, 30, 0, 0, time.UTC)
+	if remaining, exhausted := again.CheckMonthly(); exhausted || remaining != 2 {
+		t.Errorf("new month: remaining=%.2f exhausted=%v", remaining, exhausted)
+	}
+	if err := again.LoadMonthly(); err != nil || again.GetMonthlyBudget() != nil {
+		t.Errorf("April should start empty, got %v", err)
+	}
+}
+
+func TestGuardBlocksCappedModelOnly(t *testing.T) {
+	tr := NewTracker(BudgetConfig{PerModelUSD: map[string]float64{"opus": 0.1}}, "")
+	g := NewGuard(tr, &noticeSender{})
+	tr.Record("T", "coder", opus, TokenUsage{PromptTokens: 100_000, TotalTokens: 100_000})
+
+	if ex := g.exceeded("T", sonnet); ex != nil {
+		t.Errorf("sonnet blocked: %v", ex)
+	}
+	ex := g.exceeded("T", opus)
+	if ex == nil || ex.Scope != "model" {
+		t.Fatalf("opus not blocked: %v", ex)
+	}
+	if notice := FormatPauseNotice(nil, ex); !strings.Contains(notice, "*opus* budget") {
+		t.Errorf("notice = %q", notice)
+	}
+	if g.Approve("T", "", true) != ApproveResumed || g.exceeded("T", opus) != nil {
+		t.Error("approve should lift the model cap")
+	}
+}
+
+func TestBudgetCommand(t *testing.T) {
+	tr := NewTracker(BudgetConfig{PerMonthUSD: 100, PerModelUSD: map[string]float64{"opus": 50}}, "")
+	cmd := Command(tr)
+	if out, _ := cmd.Run(context.Background(), chatcmd.Invocation{Thread: "T"}); out != "No spend recorded yet." {
+		t.Errorf("empty = %q", out)
+	}
+
+	tr.Record("T", "coder", opus, million)
+	tr.Record("T", "pm", sonnet, million)
+	out, err := cmd.Run(context.Background(), chatcmd.Invocation{Thread: "T"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Cost Summary — Thread T", "Daily Cost Summary", "Monthly Cost Summary", "$18.0000 / $100.00 limit", "| " + opus + " | 1 |", "- opus: $15.0000 / $50.00"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, opus) > strings.Index(out, sonnet) {
+		t.Error("models should be listed most expensive first")
+	}
+}
@@ -0,0 +1,83 @@
+package budget
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/approval"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+// PreflightGate asks for approval before a plan whose estimated cost
+// exceeds a threshold starts, so an expensive refactor never starts from a
+// vague request without a second look. The question goes through an
+// approval.Gate, so only the requester and the configured approvers can
+// answer it. An approval covers the task it was given for: call Finish
+// when the task ends so the thread's next task asks again. Thread-safe.
+type PreflightGate struct {
+	approvals *approval.Gate
+	catalog   *messages.Catalog
+	threshold float64
+
+	mu       sync.Mutex
+	approved map[string]float64 // thread → estimate the user approved
+}
+
+// NewPreflightGate creates a gate for plans above thresholdUSD. A threshold
+// <= 0 approves everything. A nil cat uses the built-in English text.
+func NewPreflightGate(approvals *approval.Gate, thresholdUSD float64, cat *messages.Catalog) *PreflightGate {
+	return &PreflightGate{
+		approvals: approvals,
+		catalog:   cat,
+		threshold: thresholdUSD,
+		approved:  make(map[string]float64),
+	}
+}
+
+// Confirm returns whether the thread's plan may start. A plan under the
+// threshold, or already approved at the same or higher estimate, passes at
+// once; otherwise the cost table is posted and Confirm blocks until
+// requester (or an approver) answers, the approval times out, or ctx ends.
+func (g *PreflightGate) Confirm(ctx context.Context, channel, thread, requester string, steps []CostEstimate) (bool, error) {
+	total := EstimatePlanCost(steps)
+	if g.threshold <= 0 || total <= g.threshold {
+		return true, nil
+	}
+
+	g.mu.Lock()
+	approved, ok := g.approved[thread]
+	g.mu.Unlock()
+	if ok && total <= approved {
+		return true, nil
+	}
+
+	ok, err := g.approvals.Request(ctx, channel, thread, requester, FormatPlanApproval(g.catalog, steps, g.threshold))
+	if err != nil || !ok {
+		return false, err
+	}
+	g.mu.Lock()
+	g.approved[thread] = total
+	g.mu.Unlock()
+	return true, nil
+}
+
+// Finish forgets the thread's approval. Call it when the task ends,
+// whatever the outcome.
+func (g *PreflightGate) Finish(thread string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.approved, thread)
+}
+
+// FormatPlanApproval renders the approval question for an expensive plan:
+// the cost table plus the question. A nil cat uses the built-in English
+// text.
+func FormatPlanApproval(cat *messages.Catalog, steps []CostEstimate, thresholdUSD float64) string {
+	if cat == nil {
+		cat = messages.New(messages.DefaultLocale)
+	}
+	return FormatCostEstimate(steps) + "\n" + cat.Render(messages.BudgetPlanApproval, map[string]float64{
+		"Total":     EstimatePlanCost(steps),
+		"Threshold": thresholdUSD,
+	})
+}
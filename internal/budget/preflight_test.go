@@ -0,0 +1,91 @@
+package budget
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/approval"
+)
+
+func TestPreflightGate(t *testing.T) {
+	sender := &recordingSender{}
+	approvals := approval.NewGate(sender, approval.WithApprovers("ULEAD"))
+	g := NewPreflightGate(approvals, 5.0, nil)
+	ctx := context.Background()
+	cheap := []CostEstimate{{Model: "m", EstimatedCostUSD: 1.2}}
+	big := []CostEstimate{
+		{Model: "anthropic/claude-opus-4-6", EstimatedInput: 2_000_000, EstimatedOutput: 200_000, EstimatedCostUSD: 45},
+	}
+
+	if ok, _ := g.Confirm(ctx, "C1", "t1", "U1", cheap); !ok {
+		t.Error("cheap plan should start without approval")
+	}
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.Confirm(ctx, "C1", "t1", "U1", big)
+		done <- ok
+	}()
+	if answer(t, approvals, "t1", "U2", "yes") {
+		t.Error("someone other than the requester or an approver approved")
+	}
+	if !answer(t, approvals, "t1", "ULEAD", "yes") || !<-done {
+		t.Fatal("configured approver should release the plan")
+	}
+	if p := sender.last(); !strings.Contains(p, "**Total estimated:** $45.0000") || !strings.Contains(p, "above the $5.00 approval threshold") {
+		t.Errorf("prompt = %q", p)
+	}
+
+	if ok, _ := g.Confirm(ctx, "C1", "t1", "U1", big); !ok {
+		t.Error("approved plan should pass")
+	}
+	bigger := []CostEstimate{{EstimatedCostUSD: 60}}
+	go func() {
+		ok, _ := g.Confirm(ctx, "C1", "t1", "U1", bigger)
+		done <- ok
+	}()
+	if !answer(t, approvals, "t1", "U1", "no") || <-done {
+		t.Error("a costlier revision needs a new approval")
+	}
+}
+
+func TestPreflightGate_FinishClearsApproval(t *testing.T) {
+	approvals := approval.NewGate(&recordingSender{})
+	g := NewPreflightGate(approvals, 5.0, nil)
+	ctx := context.Background()
+	big := []CostEstimate{{EstimatedCostUSD: 45}}
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := g.Confirm(ctx, "C1", "t1", "U1", big)
+		done <- ok
+	}()
+	answer(t, approvals, "t1", "U1", "1")
+	if !<-done {
+		t.Fatal("approved plan should pass")
+	}
+
+	g.Finish("t1")
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ok, _ := g.Confirm(ctx, "C1", "t1", "U1", big)
+		done <- ok
+	}()
+	for i := 0; i < 100 && !approvals.Pending("C1", "t1"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if !approvals.Pending("C1", "t1") {
+		t.Error("the thread's next task should need its own approval")
+	}
+	cancel()
+	<-done
+}
+
+func TestPreflightGate_Disabled(t *testing.T) {
+	g := NewPreflightGate(approval.NewGate(&recordingSender{}), 0, nil)
+	if ok, _ := g.Confirm(context.Background(), "C1", "t1", "U1", []CostEstimate{{EstimatedCostUSD: 1000}}); !ok {
+		t.Error("zero threshold should never ask")
+	}
+}
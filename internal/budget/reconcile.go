@@ -0,0 +1,206 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+// DefaultDriftThreshold flags a reconciled call whose billed cost differs
+// from the token-based estimate by more than this fraction.
+const DefaultDriftThreshold = 0.25
+
+// minDriftUSD ignores relative drift on calls too cheap to matter.
+const minDriftUSD = 0.001
+
+// Reconcile replaces the estimated cost of the call with generationID by
+// the billed cost, in both the thread and daily budgets. It returns the
+// drift (billed - estimated). Unknown or already reconciled calls are a
+// no-op.
+func (t *Tracker) Reconcile(threadID, generationID string, billedUSD float64) (drift float64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tb, ok := t.threads[threadID]
+	if !ok {
+		return 0, fmt.Errorf("reconcile %s: thread %s not tracked", generationID, threadID)
+	}
+	i := findGeneration(tb.Entries, generationID)
+	if i < 0 {
+		return 0, fmt.Errorf("reconcile: generation %s not recorded in thread %s", generationID, threadID)
+	}
+	if tb.Entries[i].Reconciled {
+		return 0, nil
+	}
+	drift = reconcileEntry(&tb.Entries[i], billedUSD)
+	tb.TotalCost += drift
+
+	dateKey := tb.Entries[i].Timestamp.Format("2006-01-02")
+	if db, ok := t.daily[dateKey]; ok {
+		if j := findGeneration(db.Entries, generationID); j >= 0 && !db.Entries[j].Reconciled {
+			reconcileEntry(&db.Entries[j], billedUSD)
+			db.TotalCost += drift
+		}
+	}
+	return drift, nil
+}
+
+func findGeneration(entries []UsageEntry, id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range entries {
+		if entries[i].GenerationID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func reconcileEntry(e *UsageEntry, billedUSD float64) float64 {
+	drift := billedUSD - e.CostUSD
+	e.EstimatedUSD = e.CostUSD
+	e.CostUSD = billedUSD
+	e.Reconciled = true
+	return drift
+}
+
+// Discrepancy is a reconciled call whose billed cost drifted from the
+// estimate.
+type Discrepancy struct {
+	GenerationID string
+	Agent        string
+	Model        string
+	EstimatedUSD float64
+	BilledUSD    float64
+}
+
+// Ratio is the relative drift, e.g. 0.4 for billed 40% above estimate.
+func (d Discrepancy) Ratio() float64 {
+	if d.EstimatedUSD == 0 {
+		return math.Inf(1)
+	}
+	return (d.BilledUSD - d.EstimatedUSD) / d.EstimatedUSD
+}
+
+// Discrepancies returns reconciled entries whose relative drift exceeds
+// threshold (DefaultDriftThreshold if <= 0).
+func Discrepancies(entries []UsageEntry, threshold float64) []Discrepancy {
+	if threshold <= 0 {
+		threshold = DefaultDriftThreshold
+	}
+	var out []Discrepancy
+	for _, e := range entries {
+		if !e.Reconciled || math.Abs(e.CostUSD-e.EstimatedUSD) < minDriftUSD {
+			continue
+		}
+		d := Discrepancy{GenerationID: e.GenerationID, Agent: e.Agent, Model: e.Model, EstimatedUSD: e.EstimatedUSD, BilledUSD: e.CostUSD}
+		if math.Abs(d.Ratio()) > threshold {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// formatReconciliation summarizes billed vs estimated cost for the budget
+// reports. It is empty when nothing was reconciled.
+func formatReconciliation(entries []UsageEntry) string {
+	var n int
+	var billed, estimated float64
+	for _, e := range entries {
+		if e.Reconciled {
+			n++
+			billed += e.CostUSD
+			estimated += e.EstimatedUSD
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Billed vs estimated:** $%.4f billed, $%.4f estimated (%d of %d calls reconciled)\n",
+		billed, estimated, n, len(entries))
+	for _, d := range Discrepancies(entries, 0) {
+		fmt.Fprintf(&b, "- :warning: %s (%s) %s: estimated $%.4f, billed $%.4f (%+.0f%%)\n",
+			d.Agent, d.Model, d.GenerationID, d.EstimatedUSD, d.BilledUSD, d.Ratio()*100)
+	}
+	return b.String()
+}
+
+// GenerationCoster fetches what the provider billed for a generation.
+// The OpenRouter client implements it.
+type GenerationCoster interface {
+	GenerationCost(ctx context.Context, generationID string) (float64, error)
+}
+
+// Reconciler fetches billed costs after each call and feeds them to the
+// tracker. Providers publish generation stats a few seconds after the
+// response, so lookups are retried with a delay.
+type Reconciler struct {
+	tracker  *Tracker
+	coster   GenerationCoster
+	attempts int
+	delay    time.Duration
+	logger   *slog.Logger
+	sleep    func(context.Context, time.Duration) error
+}
+
+// NewReconciler creates a reconciler that tries attempts times, delay
+// apart.
+func NewReconciler(tracker *Tracker, coster GenerationCoster, logger *slog.Logger) *Reconciler {
+	return &Reconciler{
+		tracker:  tracker,
+		coster:   coster,
+		attempts: 3,
+		delay:    2 * time.Second,
+		logger:   logger,
+		sleep:    sleepCtx,
+	}
+}
+
+// Reconcile looks up the billed cost of generationID and reconciles it,
+// logging a warning when the drift is large. Call it in a goroutine after
+// RecordGeneration.
+func (r *Reconciler) Reconcile(ctx context.Context, threadID, generationID string) error {
+	if generationID == "" {
+		return nil
+	}
+	var billed float64
+	var err error
+	for attempt := 0; attempt < r.attempts; attempt++ {
+		if err = r.sleep(ctx, r.delay); err != nil {
+			return err
+		}
+		if billed, err = r.coster.GenerationCost(ctx, generationID); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("fetch billed cost for %s: %w", generationID, err)
+	}
+
+	drift, err := r.tracker.Reconcile(threadID, generationID, billed)
+	if err != nil {
+		return err
+	}
+	if estimated := billed - drift; math.Abs(drift) >= minDriftUSD && (estimated == 0 || math.Abs(drift/estimated) > DefaultDriftThreshold) {
+		r.logger.Warn("billed cost differs from estimate",
+			"thread", threadID, "generation", generationID, "estimated_usd", estimated, "billed_usd", billed)
+	}
+	return nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
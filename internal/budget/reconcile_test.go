@@ -0,0 +1,87 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeCoster struct {
+	costs map[string]float64
+	calls int
+}
+
+func (f *fakeCoster) GenerationCost(_ context.Context, id string) (float64, error) {
+	f.calls++
+	if f.calls == 1 {
+		return 0, errors.New("404: generation not ready") // stats lag the response
+	}
+	c, ok := f.costs[id]
+	if !ok {
+		return 0, errors.New("unknown generation")
+	}
+	return c, nil
+}
+
+func TestTracker_Reconcile(t *testing.T) {
+	tr := NewTracker(BudgetConfig{}, "")
+	tokens := TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 0, TotalTokens: 1_000_000}
+	tr.RecordGeneration("T1", "coder", "openai/gpt-4o", "gen-1", tokens) // estimated $2.50
+	tr.Record("T1", "pm", "openai/gpt-4o", tokens)
+
+	drift, err := tr.Reconcile("T1", "gen-1", 1.00)
+	if err != nil || drift != -1.5 {
+		t.Fatalf("drift=%v err=%v", drift, err)
+	}
+	if got := tr.ThreadCost("T1"); got != 3.5 {
+		t.Errorf("thread cost = %v, want 3.5", got)
+	}
+	if got := tr.DailyCost(); got != 3.5 {
+		t.Errorf("daily cost = %v, want 3.5", got)
+	}
+	if drift, _ := tr.Reconcile("T1", "gen-1", 9); drift != 0 {
+		t.Error("second reconcile should be a no-op")
+	}
+	if _, err := tr.Reconcile("T1", "gen-x", 1); err == nil {
+		t.Error("expected error for unknown generation")
+	}
+
+	summary := FormatDailySummary(tr.GetDailyBudget())
+	for _, want := range []string{"$1.0000 billed, $2.5000 estimated (1 of 2 calls reconciled)", "gen-1", "-60%"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestDiscrepancies_Threshold(t *testing.T) {
+	entries := []UsageEntry{
+		{GenerationID: "a", Reconciled: true, EstimatedUSD: 1.0, CostUSD: 1.1},
+		{GenerationID: "b", Reconciled: true, EstimatedUSD: 1.0, CostUSD: 1.5},
+		{GenerationID: "c", CostUSD: 5},
+	}
+	got := Discrepancies(entries, 0)
+	if len(got) != 1 || got[0].GenerationID != "b" {
+		t.Errorf("discrepancies = %+v", got)
+	}
+}
+
+func TestReconciler_RetriesUntilStatsAppear(t *testing.T) {
+	tr := NewTracker(BudgetConfig{}, "")
+	tr.RecordGeneration("T1", "coder", "openai/gpt-4o", "gen-1", TokenUsage{PromptTokens: 1_000_000, TotalTokens: 1_000_000})
+
+	coster := &fakeCoster{costs: map[string]float64{"gen-1": 2.0}}
+	r := NewReconciler(tr, coster, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	r.sleep = func(context.Context, time.Duration) error { return nil }
+
+	if err := r.Reconcile(context.Background(), "T1", "gen-1"); err != nil {
+		t.Fatal(err)
+	}
+	if coster.calls != 2 || tr.ThreadCost("T1") != 2.0 {
+		t.Errorf("calls=%d cost=%v", coster.calls, tr.ThreadCost("T1"))
+	}
+}
@@ -1,5 +1,5 @@
-// Package budget implements per-thread and per-day token budget tracking
-// with cost estimation and enforcement. It provides thread-safe tracking,
+// Package budget implements per-thread, per-day, per-month and per-model
+// token budget tracking with cost estimation and enforcement. It provides thread-safe tracking,
 // persistence to JSON files, and budget limit checks.
 package budget
 
@@ -29,6 +29,10 @@ var modelPricing = map[string][2]float64{
 	"moonshotai/kimi-k2":      {0.6, 2.0},
 }
 
+// localModelPrefix marks models served by a local Ollama instance, which
+// cost nothing per token.
+const localModelPrefix = "ollama/"
+
 const defaultInputPrice = 3.0
 const defaultOutputPrice = 15.0
 
@@ -46,6 +50,13 @@ type UsageEntry struct {
 	Model     string     `json:"model"`
 	Tokens    TokenUsage `json:"tokens"`
 	CostUSD   float64    `json:"cost_usd"`
+
+	// GenerationID is the provider's ID for the call, used to fetch the
+	// billed cost. Once reconciled, CostUSD is the billed amount and
+	// EstimatedUSD keeps the token-based estimate.
+	GenerationID string  `json:"generation_id,omitempty"`
+	EstimatedUSD float64 `json:"estimated_usd,omitempty"`
+	Reconciled   bool    `json:"reconciled,omitempty"`
 }
 
 // ThreadBudget tracks cumulative cost for a single thread.
@@ -54,7 +65,8 @@ type ThreadBudget struct {
 	Entries     []UsageEntry `json:"entries"`
 	TotalCost   float64      `json:"total_cost"`
 	TotalTokens int          `json:"total_tokens"`
-	LimitUSD    float64      `json:"limit_usd"`    // 0 = unlimited
+	LimitUSD    float64      `json:"limit_usd"`    // effective limit (PerThreadUSD + ExtraUSD); 0 = unlimited
+	ExtraUSD    float64      `json:"extra_usd,omitempty"` // allowance granted by approvals on top of PerThreadUSD
 	Paused      bool         `json:"paused"`        // true if budget exceeded and awaiting approval
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
@@ -66,7 +78,8 @@ type DailyBudget struct {
 	Entries     []UsageEntry `json:"entries"`
 	TotalCost   float64      `json:"total_cost"`
 	TotalTokens int          `json:"total_tokens"`
-	LimitUSD    float64      `json:"limit_usd"` // 0 = unlimited
+	LimitUSD    float64      `json:"limit_usd"` // effective limit (PerDayUSD + ExtraUSD); 0 = unlimited
+	ExtraUSD    float64      `json:"extra_usd,omitempty"` // allowance granted by approvals on top of PerDayUSD
 	Exhausted   bool         `json:"exhausted"`  // true if daily budget hit
 }
 
@@ -74,20 +87,34 @@ type DailyBudget struct {
 type BudgetConfig struct {
 	PerThreadUSD float64 `json:"per_thread_usd"` // per-thread limit (0 = unlimited)
 	PerDayUSD    float64 `json:"per_day_usd"`    // per-day limit (0 = unlimited)
+	PerMonthUSD  float64 `json:"per_month_usd"`  // per-calendar-month limit (0 = unlimited)
+
+	// PerModelUSD caps each calendar month's spend per model. Keys are full
+	// model IDs or substrings ("opus"), so a family can be capped apart
+	// from the others.
+	PerModelUSD map[string]float64 `json:"per_model_usd,omitempty"`
 }
 
 // BudgetExceeded is returned when a budget limit is hit.
 type BudgetExceeded struct {
-	Scope     string  // "thread" or "day"
+	Scope     string  // "thread", "day", "month" or "model"
 	LimitUSD  float64
 	ActualUSD float64
-	ThreadID  string // empty for daily
+	ThreadID  string // empty unless Scope is "thread"
+	Model     string // PerModelUSD key; set when Scope is "model"
 }
 
 func (e *BudgetExceeded) Error() string {
-	if e.Scope == "thread" {
+	switch e.Scope {
+	case "thread":
 		return fmt.Sprintf("thread %s budget exceeded: $%.4f / $%.4f limit",
 			e.ThreadID, e.ActualUSD, e.LimitUSD)
+	case "month":
+		return fmt.Sprintf("monthly budget exceeded: $%.4f / $%.4f limit",
+			e.ActualUSD, e.LimitUSD)
+	case "model":
+		return fmt.Sprintf("monthly %s budget exceeded: $%.4f / $%.4f limit",
+			e.Model, e.ActualUSD, e.LimitUSD)
 	}
 	return fmt.Sprintf("daily budget exceeded: $%.4f / $%.4f limit",
 		e.ActualUSD, e.LimitUSD)
@@ -106,7 +133,8 @@ func (realClock) Now() time.Time { return time.Now() }
 type Tracker struct {
 	mu      sync.Mutex
 	threads map[string]*ThreadBudget
-	daily   map[string]*DailyBudget // date string → budget
+	daily   map[string]*DailyBudget   // date string → budget
+	monthly map[string]*MonthlyBudget // "2006-01" → budget
 	config  BudgetConfig
 	dataDir string // directory for persisting budget files
 	clock   Clock
@@ -117,6 +145,7 @@ func NewTracker(config BudgetConfig, dataDir string) *Tracker {
 	return &Tracker{
 		threads: make(map[string]*ThreadBudget),
 		daily:   make(map[string]*DailyBudget),
+		monthly: make(map[string]*MonthlyBudget),
 		config:  config,
 		dataDir: dataDir,
 		clock:   realClock{},
@@ -133,13 +162,20 @@ func NewTrackerWithClock(config BudgetConfig, dataDir string, clock Clock) *Trac
 // Record adds a usage entry to both thread and daily budgets.
 // Returns a *BudgetExceeded error if any limit is hit (but still records the usage).
 func (t *Tracker) Record(threadID, agent, model string, tokens TokenUsage) error {
+	return t.RecordGeneration(threadID, agent, model, "", tokens)
+}
+
+// RecordGeneration is Record for a call with a provider generation ID, so
+// its estimated cost can later be replaced by the billed one (Reconcile).
+func (t *Tracker) RecordGeneration(threadID, agent, model, generationID string, tokens TokenUsage) error {
 	cost := CalculateCost(model, tokens)
 	entry := UsageEntry{
-		Timestamp: t.clock.Now(),
-		Agent:     agent,
-		Model:     model,
-		Tokens:    tokens,
-		CostUSD:   cost,
+		Timestamp:    t.clock.Now(),
+		Agent:        agent,
+		Model:        model,
+		Tokens:       tokens,
+		CostUSD:      cost,
+		GenerationID: generationID,
 	}
 
 	t.mu.Lock()
@@ -159,27 +195,38 @@ func (t *Tracker) Record(threadID, agent, model string, tokens TokenUsage) error
 	db.TotalCost += cost
 	db.TotalTokens += tokens.TotalTokens
 
+	// Record in monthly budget; its limits are checked last
+	monthErr := t.recordMonthly(entry)
+
+	// Limits are read from config on every call, so a changed limit
+	// applies to threads and days already tracked.
+	tb.LimitUSD = t.threadLimit(tb)
+	db.LimitUSD = t.dailyLimit(db)
+
 	// Check thread limit
-	if t.config.PerThreadUSD > 0 && tb.TotalCost > t.config.PerThreadUSD {
+	if tb.LimitUSD > 0 && tb.TotalCost > tb.LimitUSD {
 		tb.Paused = true
 		return &BudgetExceeded{
 			Scope:     "thread",
-			LimitUSD:  t.config.PerThreadUSD,
+			LimitUSD:  tb.LimitUSD,
 			ActualUSD: tb.TotalCost,
 			ThreadID:  threadID,
 		}
 	}
 
 	// Check daily limit
-	if t.config.PerDayUSD > 0 && db.TotalCost > t.config.PerDayUSD {
+	if db.LimitUSD > 0 && db.TotalCost > db.LimitUSD {
 		db.Exhausted = true
 		return &BudgetExceeded{
 			Scope:     "day",
-			LimitUSD:  t.config.PerDayUSD,
+			LimitUSD:  db.LimitUSD,
 			ActualUSD: db.TotalCost,
 		}
 	}
 
+	if monthErr != nil {
+		return monthErr
+	}
 	return nil
 }
 
@@ -193,11 +240,12 @@ func (t *Tracker) CheckThread(threadID string) (remaining float64, paused bool)
 		return t.config.PerThreadUSD, false
 	}
 
-	if t.config.PerThreadUSD <= 0 {
+	limit := t.threadLimit(tb)
+	if limit <= 0 {
 		return 0, false // unlimited
 	}
 
-	return t.config.PerThreadUSD - tb.TotalCost, tb.Paused
+	return limit - tb.TotalCost, tb.Paused
 }
 
 // CheckDaily returns whether the daily budget allows more work.
@@ -211,23 +259,60 @@ func (t *Tracker) CheckDaily() (remaining float64, exhausted bool) {
 		return t.config.PerDayUSD, false
 	}
 
-	if t.config.PerDayUSD <= 0 {
+	limit := t.dailyLimit(db)
+	if limit <= 0 {
 		return 0, false // unlimited
 	}
 
-	return t.config.PerDayUSD - db.TotalCost, db.Exhausted
+	return limit - db.TotalCost, db.Exhausted
+}
+
+// threadLimit is the thread's effective limit: the configured per-thread
+// limit plus what approvals granted. 0 = unlimited. Must be called under
+// lock.
+func (t *Tracker) threadLimit(tb *ThreadBudget) float64 {
+	if t.config.PerThreadUSD <= 0 {
+		return 0
+	}
+	return t.config.PerThreadUSD + tb.ExtraUSD
+}
+
+// dailyLimit is the day's effective limit: the configured per-day limit
+// plus what approvals granted. 0 = unlimited. Must be called under lock.
+func (t *Tracker) dailyLimit(db *DailyBudget) float64 {
+	if t.config.PerDayUSD <= 0 {
+		return 0
+	}
+	return t.config.PerDayUSD + db.ExtraUSD
 }
 
-// ResumeThread unpauses a thread (user approved continuation).
+// ResumeThread unpauses a thread (user approved continuation) and grants it
+// a fresh per-thread allocation on top of what it has spent.
 func (t *Tracker) ResumeThread(threadID string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if tb, ok := t.threads[threadID]; ok {
+		if tb.Paused {
+			tb.ExtraUSD = tb.TotalCost
+		}
 		tb.Paused = false
 	}
 }
 
+// ResumeDaily lifts today's exhaustion (user approved continuation) with a
+// fresh per-day allocation on top of what has been spent.
+func (t *Tracker) ResumeDaily() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dateKey := t.clock.Now().Format("2006-01-02")
+	if db, ok := t.daily[dateKey]; ok && db.Exhausted {
+		db.ExtraUSD = db.TotalCost
+		db.Exhausted = false
+	}
+}
+
 // ThreadCost returns the total cost for a thread.
 func (t *Tracker) ThreadCost(threadID string) float64 {
 	t.mu.Lock()
@@ -251,6 +336,11 @@ func (t *Tracker) DailyCost() float64 {
 	return 0
 }
 
+// Limits returns the configured budget limits.
+func (t *Tracker) Limits() BudgetConfig {
+	return t.config
+}
+
 // GetThreadBudget returns a copy of a thread's budget (nil if not tracked).
 func (t *Tracker) GetThreadBudget(threadID string) *ThreadBudget {
 	t.mu.Lock()
@@ -263,6 +353,7 @@ func (t *Tracker) GetThreadBudget(threadID string) *ThreadBudget {
 
 	// Return copy
 	cp := *tb
+	cp.LimitUSD = t.threadLimit(tb)
 	cp.Entries = make([]UsageEntry, len(tb.Entries))
 	copy(cp.Entries, tb.Entries)
 	return &cp
@@ -281,6 +372,7 @@ func (t *Tracker) GetDailyBudget() *DailyBudget {
 
 	// Return copy
 	cp := *db
+	cp.LimitUSD = t.dailyLimit(db)
 	cp.Entries = make([]UsageEntry, len(db.Entries))
 	copy(cp.Entries, db.Entries)
 	return &cp
@@ -296,6 +388,7 @@ func (t *Tracker) Save(threadID string) error {
 	}
 	// Copy under lock
 	cp := *tb
+	cp.LimitUSD = t.threadLimit(tb)
 	cp.Entries = make([]UsageEntry, len(tb.Entries))
 	copy(cp.Entries, tb.Entries)
 	t.mu.Unlock()
@@ -358,6 +451,9 @@ func modelPrice(model string) (float64, float64) {
 	if prices, ok := modelPricing[model]; ok {
 		return prices[0], prices[1]
 	}
+	if strings.HasPrefix(model, localModelPrefix) {
+		return 0, 0
+	}
 	return defaultInputPrice, defaultOutputPrice
 }
 
@@ -389,6 +485,10 @@ func FormatCostSummary(tb *ThreadBudget) string {
 		b.WriteString(fmt.Sprintf("| %s | %d | $%.4f |\n", agent, agentTokens[agent], cost))
 	}
 
+	if r := formatReconciliation(tb.Entries); r != "" {
+		b.WriteString("\n" + r)
+	}
+
 	if tb.Paused {
 		b.WriteString("\n**Status:** Paused (budget exceeded, awaiting approval)\n")
 	}
@@ -410,6 +510,9 @@ func FormatDailySummary(db *DailyBudget) string {
 	b.WriteString("\n")
 	b.WriteString(fmt.Sprintf("**Total tokens:** %d\n", db.TotalTokens))
 	b.WriteString(fmt.Sprintf("**API calls:** %d\n", len(db.Entries)))
+	if r := formatReconciliation(db.Entries); r != "" {
+		b.WriteString(r)
+	}
 
 	if db.Exhausted {
 		b.WriteString("\n**Status:** Daily budget exhausted — all agents stopped\n")
@@ -482,3 +585,46 @@ func (t *Tracker) getOrCreateDaily(dateKey string) *DailyBudget {
 	t.daily[dateKey] = db
 	return db
 }
+
+// FormatFooter renders the compact per-response annotation posted under bot
+// replies when cost footers are enabled, e.g.
+// "— 7 turns · 24k tok · $0.31 · sonnet".
+func FormatFooter(model string, turns int, tokens TokenUsage) string {
+	cost := CalculateCost(model, tokens)
+	unit := "turns"
+	if turns == 1 {
+		unit = "turn"
+	}
+	return fmt.Sprintf("— %d %s · %s tok · $%.2f · %s",
+		turns, unit, compactCount(tokens.TotalTokens), cost, ShortModelName(model))
+}
+
+// ShortModelName reduces a provider model ID to a recognizable short name:
+// the Claude family ("sonnet", "opus", "haiku") when present, otherwise the
+// ID without its provider prefix.
+func ShortModelName(model string) string {
+	name := model
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, family := range []string{"opus", "sonnet", "haiku"} {
+		if strings.Contains(name, family) {
+			return family
+		}
+	}
+	return name
+}
+
+// compactCount formats a token count as 950, 24k, or 1.2M.
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 10_000:
+		return fmt.Sprintf("%dk", (n+500)/1000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -132,6 +132,36 @@ func TestTracker_ResumeThread(t *testing.T) {
 	}
 }
 
+func TestTracker_LimitsFollowConfig(t *testing.T) {
+	dir := t.TempDir()
+	usage := TokenUsage{PromptTokens: 1_000_000, TotalTokens: 1_000_000} // $15 on opus
+	tr := NewTracker(BudgetConfig{PerThreadUSD: 10}, dir)
+	tr.Record("T1", "coder", "anthropic/claude-opus-4-6", usage)
+	tr.ResumeThread("T1") // approved: $10 more on top of the $15 spent
+	if err := tr.Save("T1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Raising the configured limit applies to the persisted thread, on top
+	// of the allowance the approval granted.
+	raised := NewTracker(BudgetConfig{PerThreadUSD: 50}, dir)
+	if err := raised.Load("T1"); err != nil {
+		t.Fatal(err)
+	}
+	if remaining, paused := raised.CheckThread("T1"); paused || remaining != 50 {
+		t.Errorf("CheckThread = %.2f, %v; want 50.00, false", remaining, paused)
+	}
+	if tb := raised.GetThreadBudget("T1"); tb.LimitUSD != 65 {
+		t.Errorf("LimitUSD = %.2f, want 65", tb.LimitUSD)
+	}
+
+	unlimited := NewTracker(BudgetConfig{}, dir)
+	unlimited.Load("T1")
+	if err := unlimited.Record("T1", "coder", "anthropic/claude-opus-4-6", usage); err != nil {
+		t.Errorf("removing the limit should stop enforcing it: %v", err)
+	}
+}
+
 func TestTracker_CheckThread_NoRecord(t *testing.T) {
 	tr := NewTracker(BudgetConfig{PerThreadUSD: 10.0}, "")
 
@@ -519,3 +549,31 @@ func TestTracker_ThreadBudgetThenDailyExceeded(t *testing.T) {
 		t.Errorf("expected day scope (lower limit), got %s", be.Scope)
 	}
 }
+
+func TestFormatFooter(t *testing.T) {
+	got := FormatFooter("anthropic/claude-sonnet-4-20250514", 7, TokenUsage{
+		PromptTokens: 20000, CompletionTokens: 4000, TotalTokens: 24000,
+	})
+	if got != "— 7 turns · 24k tok · $0.12 · sonnet" {
+		t.Errorf("got %q", got)
+	}
+
+	got = FormatFooter("moonshotai/kimi-k2", 1, TokenUsage{PromptTokens: 900, TotalTokens: 950})
+	if got != "— 1 turn · 950 tok · $0.00 · kimi-k2" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCompactCount(t *testing.T) {
+	for n, want := range map[int]string{0: "0", 999: "999", 1500: "1.5k", 24400: "24k", 1_250_000: "1.2M"} {
+		if got := compactCount(n); got != want {
+			t.Errorf("compactCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestCalculateCost_LocalModelIsFree(t *testing.T) {
+	if cost := CalculateCost("ollama/qwen2.5-coder:14b", TokenUsage{PromptTokens: 50000, CompletionTokens: 8000}); cost != 0 {
+		t.Errorf("local model cost = %f", cost)
+	}
+}
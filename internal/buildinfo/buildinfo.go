@@ -0,0 +1,273 @@
+package buildinfo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Set via -ldflags "-X" at release time.
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// DefaultChecksumsURL is where release checksums are published; %s is the
+// version tag. The signature sits next to it with a ".sig" suffix.
+const DefaultChecksumsURL = "https://github.com/leandrotocalini/codebutler/releases/download/%s/SHA256SUMS"
+
+// ReleasePublicKey is the base64 Ed25519 public key that signs release
+// SHA256SUMS files. It is set in source rather than with ldflags so a
+// self-build embeds the same key and can reproduce the release binary.
+var ReleasePublicKey = ""
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // built from a dirty tree
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build info, preferring ldflags values and falling back to
+// the toolchain's VCS stamp.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, Date: Date, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		fillFromBuildInfo(&info, bi)
+	}
+	return info
+}
+
+func fillFromBuildInfo(info *Info, bi *debug.BuildInfo) {
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+}
+
+// ShortCommit returns the first 12 characters of the commit hash.
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 12 {
+		return i.Commit[:12]
+	}
+	return i.Commit
+}
+
+// String renders a one-line description, e.g.
+// "v1.2.0 (3f2a9c1d04be, built 2026-03-01T10:00:00Z)".
+func (i Info) String() string {
+	var parts []string
+	if c := i.ShortCommit(); c != "" {
+		if i.Modified {
+			c += "-dirty"
+		}
+		parts = append(parts, c)
+	}
+	if i.Date != "" {
+		parts = append(parts, "built "+i.Date)
+	}
+	if len(parts) == 0 {
+		return i.Version
+	}
+	return fmt.Sprintf("%s (%s)", i.Version, strings.Join(parts, ", "))
+}
+
+var (
+	exeSumOnce sync.Once
+	exeSum     string
+)
+
+// ExecutableSHA256 returns the hex SHA-256 of the running binary, or "" if
+// it cannot be read. It is computed once.
+func ExecutableSHA256() string {
+	exeSumOnce.Do(func() {
+		if exe, err := os.Executable(); err == nil {
+			exeSum, _ = FileSHA256(exe)
+		}
+	})
+	return exeSum
+}
+
+// StatusSection renders the running build and its binary hash for /status;
+// pass it to taskqueue.Commands.
+func StatusSection(_, _ string) string {
+	info := Get()
+	out := fmt.Sprintf("Build: %s (%s)", info, info.GoVersion)
+	if sum := ExecutableSHA256(); sum != "" {
+		out += fmt.Sprintf("\nBinary sha256: `%s`", sum)
+	}
+	return out
+}
+
+// Announcement is the startup line posted when an agent comes online.
+func Announcement(role string) string {
+	return fmt.Sprintf("codebutler %s online — %s", role, Get())
+}
+
+// VerifyResult reports how a binary compares to the published checksums.
+type VerifyResult struct {
+	Path     string
+	SHA256   string
+	Matched  bool
+	Artifact string // checksum entry that matched, if any
+}
+
+// FileSHA256 returns the hex SHA-256 of the file at path.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParseChecksums reads a sha256sum-style file ("<hex>  <name>" per line,
+// optionally with a "*" binary marker) into hash → artifact name.
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		hash := strings.ToLower(fields[0])
+		if len(hash) != sha256.Size*2 {
+			continue
+		}
+		sums[hash] = strings.TrimPrefix(fields[1], "*")
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(sums) == 0 {
+		return nil, fmt.Errorf("no SHA-256 entries found")
+	}
+	return sums, nil
+}
+
+// Verify hashes the binary at path and looks it up in sums.
+func Verify(path string, sums map[string]string) (VerifyResult, error) {
+	sum, err := FileSHA256(path)
+	if err != nil {
+		return VerifyResult{}, fmt.Errorf("hash %s: %w", path, err)
+	}
+	res := VerifyResult{Path: path, SHA256: sum}
+	if name, ok := sums[sum]; ok {
+		res.Matched = true
+		res.Artifact = name
+	}
+	return res, nil
+}
+
+// VerifySignature checks that sig, a base64 Ed25519 signature, signs data
+// under publicKey (base64).
+func VerifySignature(publicKey string, data, sig []byte) error {
+	pub, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed signature")
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), data, raw) {
+		return errors.New("signature does not match the release public key")
+	}
+	return nil
+}
+
+// LoadChecksums reads checksums from an http(s) URL or a local file. It
+// checks integrity only; use LoadSignedChecksums to also check who
+// published them.
+func LoadChecksums(ctx context.Context, client *http.Client, source string) (map[string]string, error) {
+	data, err := fetch(ctx, client, source)
+	if err != nil {
+		return nil, err
+	}
+	return ParseChecksums(bytes.NewReader(data))
+}
+
+// LoadSignedChecksums reads checksums and their signature (URLs or local
+// files) and returns the checksums only if the signature verifies under
+// publicKey.
+func LoadSignedChecksums(ctx context.Context, client *http.Client, source, sigSource, publicKey string) (map[string]string, error) {
+	if publicKey == "" {
+		return nil, errors.New("no release public key to verify the checksums with")
+	}
+	data, err := fetch(ctx, client, source)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := fetch(ctx, client, sigSource)
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+	if err := VerifySignature(publicKey, data, sig); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return ParseChecksums(bytes.NewReader(data))
+}
+
+// fetch reads an http(s) URL or a local file, up to 1 MiB.
+func fetch(ctx context.Context, client *http.Client, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(filepath.Clean(source))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(io.LimitReader(f, 1<<20))
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
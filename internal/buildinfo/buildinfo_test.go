@@ -0,0 +1,144 @@
+package buildinfo
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestInfo_String(t *testing.T) {
+	i := Info{Version: "v1.2.0", Commit: "3f2a9c1d04be77aa", Date: "2026-03-01T10:00:00Z"}
+	if got := i.String(); got != "v1.2.0 (3f2a9c1d04be, built 2026-03-01T10:00:00Z)" {
+		t.Errorf("got %q", got)
+	}
+	i.Modified = true
+	if !strings.Contains(i.String(), "3f2a9c1d04be-dirty") {
+		t.Errorf("dirty marker missing: %q", i.String())
+	}
+	if got := (Info{Version: "dev"}).String(); got != "dev" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFillFromBuildInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		Main: debug.Module{Version: "v0.9.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	info := Info{Version: "dev"}
+	fillFromBuildInfo(&info, bi)
+	if info.Version != "v0.9.0" || info.Commit != "abc123" || info.Date != "2026-01-02T03:04:05Z" || !info.Modified {
+		t.Errorf("info = %+v", info)
+	}
+
+	stamped := Info{Version: "v1.0.0", Commit: "fromldflags"}
+	fillFromBuildInfo(&stamped, bi)
+	if stamped.Version != "v1.0.0" || stamped.Commit != "fromldflags" {
+		t.Errorf("ldflags values should win: %+v", stamped)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "codebutler")
+	os.WriteFile(bin, []byte("binary contents"), 0o755)
+	sum := sha256.Sum256([]byte("binary contents"))
+	good := hex.EncodeToString(sum[:])
+
+	sumsText := fmt.Sprintf("# release v1.2.0\n%s  codebutler_linux_amd64\n%s *codebutler_darwin_arm64\n",
+		good, strings.Repeat("0", 64))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/SHA256SUMS" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, sumsText)
+	}))
+	defer srv.Close()
+
+	sums, err := LoadChecksums(context.Background(), srv.Client(), srv.URL+"/SHA256SUMS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sums) != 2 || sums[strings.Repeat("0", 64)] != "codebutler_darwin_arm64" {
+		t.Errorf("sums = %v", sums)
+	}
+
+	res, err := Verify(bin, sums)
+	if err != nil || !res.Matched || res.Artifact != "codebutler_linux_amd64" || res.SHA256 != good {
+		t.Errorf("res = %+v, err = %v", res, err)
+	}
+
+	os.WriteFile(bin, []byte("tampered"), 0o755)
+	if res, _ := Verify(bin, sums); res.Matched {
+		t.Error("tampered binary should not match")
+	}
+
+	sumsFile := filepath.Join(dir, "SHA256SUMS")
+	os.WriteFile(sumsFile, []byte(sumsText), 0o644)
+	if _, err := LoadChecksums(context.Background(), nil, sumsFile); err != nil {
+		t.Errorf("local file: %v", err)
+	}
+	if _, err := LoadChecksums(context.Background(), srv.Client(), srv.URL+"/missing"); err == nil {
+		t.Error("expected HTTP error")
+	}
+	if _, err := ParseChecksums(strings.NewReader("not a checksum file")); err == nil {
+		t.Error("expected error for empty checksum list")
+	}
+}
+
+func TestLoadSignedChecksums(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := base64.StdEncoding.EncodeToString(pub)
+	sums := []byte(strings.Repeat("a", 64) + "  codebutler_linux_amd64\n")
+
+	dir := t.TempDir()
+	sumsPath := filepath.Join(dir, "SHA256SUMS")
+	sigPath := sumsPath + ".sig"
+	os.WriteFile(sumsPath, sums, 0o644)
+	os.WriteFile(sigPath, []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, sums))+"\n"), 0o644)
+
+	got, err := LoadSignedChecksums(context.Background(), nil, sumsPath, sigPath, pubKey)
+	if err != nil || got[strings.Repeat("a", 64)] != "codebutler_linux_amd64" {
+		t.Fatalf("got %v, %v", got, err)
+	}
+
+	os.WriteFile(sumsPath, append(sums, []byte(strings.Repeat("b", 64)+"  evil\n")...), 0o644)
+	if _, err := LoadSignedChecksums(context.Background(), nil, sumsPath, sigPath, pubKey); err == nil {
+		t.Error("tampered checksums should fail verification")
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	os.WriteFile(sumsPath, sums, 0o644)
+	if _, err := LoadSignedChecksums(context.Background(), nil, sumsPath, sigPath, base64.StdEncoding.EncodeToString(otherPub)); err == nil {
+		t.Error("signature from another key should fail verification")
+	}
+	if _, err := LoadSignedChecksums(context.Background(), nil, sumsPath, sigPath, ""); err == nil {
+		t.Error("missing public key should fail")
+	}
+}
+
+func TestStatusSection(t *testing.T) {
+	out := StatusSection("C", "T")
+	if !strings.HasPrefix(out, "Build: "+Get().String()) || !strings.Contains(out, "Binary sha256: `") {
+		t.Errorf("status = %q", out)
+	}
+}
@@ -0,0 +1,19 @@
+// Package buildinfo reports which build of codebutler is running and
+// verifies the binary against signed release checksums.
+//
+// Release builds stamp the version, commit, and commit time with ldflags
+// (scripts/build.sh does this, writing to dist/):
+//
+//	go build -trimpath -ldflags "-X github.com/leandrotocalini/codebutler/internal/buildinfo.Version=v1.2.0 \
+//	  -X github.com/leandrotocalini/codebutler/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/leandrotocalini/codebutler/internal/buildinfo.Date=$(git log -1 --format=%cI)" ./cmd/codebutler
+//
+// Stamping the commit time instead of the wall clock keeps builds
+// reproducible: a self-build of a release tag hashes the same as the
+// published binary. The release SHA256SUMS is signed with an Ed25519 key
+// whose public half is ReleasePublicKey, so `codebutler version --verify`
+// checks who published the checksums, not only that they match.
+//
+// Self-builds without ldflags fall back to the VCS stamp the Go toolchain
+// embeds (vcs.revision, vcs.time, vcs.modified).
+package buildinfo
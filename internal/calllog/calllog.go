@@ -0,0 +1,185 @@
+package calllog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/budget"
+)
+
+// Call is one provider call.
+type Call struct {
+	Time             time.Time `json:"time"`
+	Thread           string    `json:"thread,omitempty"`
+	Role             string    `json:"role"`
+	Model            string    `json:"model"`
+	LatencyMS        int64     `json:"latency_ms"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	GenerationID     string    `json:"generation_id,omitempty"`
+	ErrorKind        string    `json:"error_kind,omitempty"` // e.g. rate_limit, timeout; empty on success
+	Error            string    `json:"error,omitempty"`
+}
+
+// dayFormat names the daily files.
+const dayFormat = "2006-01-02"
+
+// Dir returns the call log directory for a repo.
+func Dir(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "calls")
+}
+
+// Store appends calls to one JSONL file per UTC day. Thread-safe.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore creates a store in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(day time.Time) string {
+	return filepath.Join(s.dir, day.UTC().Format(dayFormat)+".jsonl")
+}
+
+// Append records a call.
+func (s *Store) Append(c Call) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("marshal call: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create calls dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path(c.Time), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open call log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write call log: %w", err)
+	}
+	return nil
+}
+
+// Query returns calls with from <= Time < to, oldest first. Malformed
+// lines are skipped.
+func (s *Store) Query(from, to time.Time) ([]Call, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Call
+	for day := from.UTC().Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		f, err := os.Open(s.path(day))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("open call log: %w", err)
+		}
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		for sc.Scan() {
+			var c Call
+			if json.Unmarshal(sc.Bytes(), &c) != nil {
+				continue
+			}
+			if !c.Time.Before(from) && c.Time.Before(to) {
+				out = append(out, c)
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read call log: %w", err)
+		}
+	}
+	return out, nil
+}
+
+// kinded is implemented by provider errors that carry a classification,
+// such as openrouter.ClassifiedError.
+type kinded interface {
+	ErrorKind() string
+}
+
+// ErrorKind classifies err for the log.
+func ErrorKind(err error) string {
+	var k kinded
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &k):
+		return k.ErrorKind()
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// Provider records every call made through the wrapped provider.
+type Provider struct {
+	agent.LLMProvider
+	store  *Store
+	role   string
+	thread string
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+// Wrap returns p recording its calls for role in thread.
+func (s *Store) Wrap(p agent.LLMProvider, role, thread string, logger *slog.Logger) *Provider {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Provider{LLMProvider: p, store: s, role: role, thread: thread, now: time.Now, logger: logger}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *Provider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	start := p.now()
+	resp, err := p.LLMProvider.ChatCompletion(ctx, req)
+
+	c := Call{
+		Time:      start,
+		Thread:    p.thread,
+		Role:      p.role,
+		Model:     req.Model,
+		LatencyMS: p.now().Sub(start).Milliseconds(),
+		ErrorKind: ErrorKind(err),
+	}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	if resp != nil {
+		c.PromptTokens = resp.Usage.PromptTokens
+		c.CompletionTokens = resp.Usage.CompletionTokens
+		c.GenerationID = resp.ID
+		c.CostUSD = budget.CalculateCost(req.Model, budget.TokenUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		})
+	}
+	if logErr := p.store.Append(c); logErr != nil {
+		p.logger.Warn("record provider call failed", "err", logErr)
+	}
+	return resp, err
+}
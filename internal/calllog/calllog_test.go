@@ -0,0 +1,86 @@
+package calllog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/provider/openrouter"
+)
+
+type stubProvider struct {
+	err error
+}
+
+func (s stubProvider) ChatCompletion(context.Context, agent.ChatRequest) (*agent.ChatResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &agent.ChatResponse{ID: "gen-1", Usage: agent.TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 100_000, TotalTokens: 1_100_000}}, nil
+}
+
+func TestProvider_RecordsCalls(t *testing.T) {
+	store := NewStore(t.TempDir())
+	ctx := context.Background()
+	req := agent.ChatRequest{Model: "openai/gpt-4o"}
+
+	store.Wrap(stubProvider{}, "coder", "t1", nil).ChatCompletion(ctx, req)
+	rateLimited := &openrouter.ClassifiedError{Type: openrouter.ErrRateLimit, StatusCode: 429}
+	if _, err := store.Wrap(stubProvider{err: rateLimited}, "pm", "t2", nil).ChatCompletion(ctx, req); !errors.Is(err, rateLimited) {
+		t.Fatalf("error not passed through: %v", err)
+	}
+
+	now := time.Now()
+	calls, err := store.Query(now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil || len(calls) != 2 {
+		t.Fatalf("calls=%+v err=%v", calls, err)
+	}
+	ok, failed := calls[0], calls[1]
+	if ok.Role != "coder" || ok.GenerationID != "gen-1" || ok.CostUSD != 3.5 || ok.ErrorKind != "" {
+		t.Errorf("ok call = %+v", ok)
+	}
+	if failed.ErrorKind != "rate_limit" || failed.Thread != "t2" || failed.CostUSD != 0 {
+		t.Errorf("failed call = %+v", failed)
+	}
+}
+
+func TestCommand_SummarizesDay(t *testing.T) {
+	store := NewStore(t.TempDir())
+	yesterday := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	for _, c := range []Call{
+		{Time: yesterday, Role: "coder", Model: "anthropic/claude-opus-4-6", Thread: "t1", CostUSD: 25, PromptTokens: 1_000_000},
+		{Time: yesterday.Add(time.Minute), Role: "reviewer", Model: "openai/gpt-4o", Thread: "t1", CostUSD: 4},
+		{Time: yesterday.Add(2 * time.Minute), Role: "pm", Model: "openai/gpt-4o", Thread: "t2", CostUSD: 1, ErrorKind: "timeout"},
+		{Time: yesterday.Add(24 * time.Hour), Role: "pm", Model: "openai/gpt-4o", CostUSD: 100},
+	} {
+		if err := store.Append(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := Command(store, func() time.Time { return yesterday.Add(20 * time.Hour) })
+	out, err := cmd.Run(context.Background(), chatcmd.Invocation{RawArgs: "yesterday"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Provider calls — 2026-10-15", "3 calls", "$30.00", "1 failed",
+		"anthropic/claude-opus-4-6: $25.00 (1 calls", "t1: $29.00 (2 calls",
+		"14:00 coder anthropic/claude-opus-4-6: $25.0000",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+
+	if out, _ := cmd.Run(context.Background(), chatcmd.Invocation{RawArgs: "2026-01-01"}); !strings.Contains(out, "No provider calls") {
+		t.Errorf("empty day = %q", out)
+	}
+	if out, _ := cmd.Run(context.Background(), chatcmd.Invocation{RawArgs: "last week"}); !strings.HasPrefix(out, "Usage:") {
+		t.Errorf("bad arg = %q", out)
+	}
+}
@@ -0,0 +1,6 @@
+// Package calllog persists one record per provider call (model, role,
+// thread, latency, tokens, cost, classified error) in daily JSONL files
+// under .codebutler/calls/, so spend can be audited at call granularity.
+// Summarize and the /calls command answer "why did yesterday cost $30?"
+// by breaking a day down by model, role, and thread.
+package calllog
@@ -0,0 +1,148 @@
+package calllog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// Group is the spend of one model, role, or thread.
+type Group struct {
+	Key     string
+	Calls   int
+	Tokens  int
+	CostUSD float64
+}
+
+// Summary breaks a set of calls down for auditing.
+type Summary struct {
+	Calls    int
+	Errors   int
+	Tokens   int
+	CostUSD  float64
+	ByModel  []Group // most expensive first
+	ByRole   []Group
+	ByThread []Group
+	Priciest []Call // the most expensive single calls
+}
+
+// priciestCalls is how many single calls a Summary keeps.
+const priciestCalls = 3
+
+// Summarize aggregates calls.
+func Summarize(calls []Call) Summary {
+	s := Summary{Calls: len(calls)}
+	models, roles, threads := map[string]*Group{}, map[string]*Group{}, map[string]*Group{}
+	for _, c := range calls {
+		tokens := c.PromptTokens + c.CompletionTokens
+		s.Tokens += tokens
+		s.CostUSD += c.CostUSD
+		if c.ErrorKind != "" {
+			s.Errors++
+		}
+		for _, by := range []struct {
+			groups map[string]*Group
+			key    string
+		}{{models, c.Model}, {roles, c.Role}, {threads, c.Thread}} {
+			m, key := by.groups, by.key
+			if key == "" {
+				key = "(none)"
+			}
+			g := m[key]
+			if g == nil {
+				g = &Group{Key: key}
+				m[key] = g
+			}
+			g.Calls++
+			g.Tokens += tokens
+			g.CostUSD += c.CostUSD
+		}
+	}
+	s.ByModel, s.ByRole, s.ByThread = sortGroups(models), sortGroups(roles), sortGroups(threads)
+
+	s.Priciest = append([]Call(nil), calls...)
+	sort.SliceStable(s.Priciest, func(i, j int) bool { return s.Priciest[i].CostUSD > s.Priciest[j].CostUSD })
+	if len(s.Priciest) > priciestCalls {
+		s.Priciest = s.Priciest[:priciestCalls]
+	}
+	return s
+}
+
+func sortGroups(m map[string]*Group) []Group {
+	out := make([]Group, 0, len(m))
+	for _, g := range m {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CostUSD != out[j].CostUSD {
+			return out[i].CostUSD > out[j].CostUSD
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// topGroups is how many groups per breakdown FormatSummary shows.
+const topGroups = 5
+
+// FormatSummary renders s for chat, titled with label (e.g. "2026-10-15").
+func FormatSummary(label string, s Summary) string {
+	if s.Calls == 0 {
+		return fmt.Sprintf("No provider calls recorded for %s.", label)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Provider calls — %s*\n%d calls, %d tokens, $%.2f", label, s.Calls, s.Tokens, s.CostUSD)
+	if s.Errors > 0 {
+		fmt.Fprintf(&b, ", %d failed", s.Errors)
+	}
+	b.WriteString("\n")
+	for _, sec := range []struct {
+		title  string
+		groups []Group
+	}{{"By model", s.ByModel}, {"By role", s.ByRole}, {"By thread", s.ByThread}} {
+		fmt.Fprintf(&b, "\n*%s*\n", sec.title)
+		for _, g := range sec.groups[:min(len(sec.groups), topGroups)] {
+			fmt.Fprintf(&b, "• %s: $%.2f (%d calls, %d tokens)\n", g.Key, g.CostUSD, g.Calls, g.Tokens)
+		}
+	}
+	b.WriteString("\n*Most expensive calls*\n")
+	for _, c := range s.Priciest {
+		fmt.Fprintf(&b, "• %s %s %s: $%.4f, %d in / %d out tokens, %dms\n",
+			c.Time.UTC().Format("15:04"), c.Role, c.Model, c.CostUSD, c.PromptTokens, c.CompletionTokens, c.LatencyMS)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Command returns /calls [today|yesterday|YYYY-MM-DD], which summarizes a
+// UTC day's provider calls (default today).
+func Command(store *Store, now func() time.Time) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "calls",
+		Usage:       "/calls [today|yesterday|YYYY-MM-DD]",
+		Description: "Break down a day's provider calls and spend by model, role, and thread",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			today := now().UTC().Truncate(24 * time.Hour)
+			day := today
+			switch arg := strings.ToLower(inv.RawArgs); arg {
+			case "", "today":
+			case "yesterday":
+				day = today.Add(-24 * time.Hour)
+			default:
+				d, err := time.Parse(dayFormat, arg)
+				if err != nil {
+					return "Usage: /calls [today|yesterday|YYYY-MM-DD]", nil
+				}
+				day = d
+			}
+			calls, err := store.Query(day, day.Add(24*time.Hour))
+			if err != nil {
+				return "", err
+			}
+			return FormatSummary(day.Format(dayFormat), Summarize(calls)), nil
+		},
+	}
+}
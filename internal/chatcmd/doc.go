@@ -0,0 +1,4 @@
+// Package chatcmd routes slash commands typed in chat ("/good", "/status",
+// ...) to handlers. Commands are matched with a string prefix check before
+// any model is involved, the same way router.ShouldProcess filters mentions.
+package chatcmd
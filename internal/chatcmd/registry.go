@@ -0,0 +1,136 @@
+package chatcmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Invocation is a parsed slash command with the chat context it came from.
+type Invocation struct {
+	Name    string   // command name without the slash
+	Args    []string // whitespace-separated arguments
+	RawArgs string   // everything after the command name, trimmed
+	Channel string
+	Thread  string
+	UserID  string
+}
+
+// Command is a chat slash command. Run returns the reply to post in the
+// thread; an empty reply posts nothing.
+type Command struct {
+	Name        string
+	Usage       string // e.g. "/bad [comment]"
+	Description string
+	Run         func(ctx context.Context, inv Invocation) (string, error)
+}
+
+// Registry dispatches chat slash commands.
+type Registry struct {
+	commands map[string]*Command
+}
+
+// NewRegistry creates an empty command registry.
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]*Command)}
+}
+
+// Register adds a command. A later registration with the same name wins.
+func (r *Registry) Register(cmd *Command) {
+	r.commands[cmd.Name] = cmd
+}
+
+// Has reports whether a command is registered.
+func (r *Registry) Has(name string) bool {
+	_, ok := r.commands[name]
+	return ok
+}
+
+// leadingMention matches an optional @codebutler.<role> (or Slack <@U123>)
+// mention in front of the command.
+var leadingMention = regexp.MustCompile(`^\s*(?:@codebutler\.\w+|<@\w+>)[\s:,]*`)
+
+// Parse extracts a slash command from message text. It returns false when
+// the text does not start with "/" (after an optional leading mention).
+func Parse(text string) (name, rawArgs string, ok bool) {
+	t := leadingMention.ReplaceAllString(text, "")
+	t = strings.TrimSpace(t)
+	if !strings.HasPrefix(t, "/") || len(t) < 2 {
+		return "", "", false
+	}
+
+	t = t[1:]
+	name, rawArgs, _ = strings.Cut(t, " ")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(rawArgs), true
+}
+
+// Handle parses text and runs the matching command. handled is false if the
+// text is not a slash command or the command is unknown, in which case the
+// message should flow to the agent as usual.
+func (r *Registry) Handle(ctx context.Context, channel, thread, userID, text string) (reply string, handled bool, err error) {
+	name, rawArgs, ok := Parse(text)
+	if !ok {
+		return "", false, nil
+	}
+
+	cmd, ok := r.commands[name]
+	if !ok {
+		return "", false, nil
+	}
+
+	inv := Invocation{
+		Name:    name,
+		Args:    strings.Fields(rawArgs),
+		RawArgs: rawArgs,
+		Channel: channel,
+		Thread:  thread,
+		UserID:  userID,
+	}
+
+	reply, err = cmd.Run(ctx, inv)
+	if err != nil {
+		return fmt.Sprintf("/%s failed: %v", name, err), true, err
+	}
+	return reply, true, nil
+}
+
+// List returns registered commands sorted by name.
+func (r *Registry) List() []Command {
+	cmds := make([]Command, 0, len(r.commands))
+	for _, c := range r.commands {
+		cmds = append(cmds, *c)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	return cmds
+}
+
+// Help returns a formatted list of commands for a "/help" reply.
+func (r *Registry) Help() string {
+	var b strings.Builder
+	b.WriteString("*Commands:*\n")
+	for _, c := range r.List() {
+		usage := c.Usage
+		if usage == "" {
+			usage = "/" + c.Name
+		}
+		fmt.Fprintf(&b, "• `%s` — %s\n", usage, c.Description)
+	}
+	return b.String()
+}
+
+// HelpCommand returns a /help command listing the registry's commands.
+func HelpCommand(r *Registry) *Command {
+	return &Command{
+		Name:        "help",
+		Description: "List available commands",
+		Run: func(_ context.Context, _ Invocation) (string, error) {
+			return r.Help(), nil
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package chatcmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs string
+		wantOK   bool
+	}{
+		{"/good", "good", "", true},
+		{"/bad  wrong file edited ", "bad", "wrong file edited", true},
+		{"@codebutler.pm /status", "status", "", true},
+		{"<@U123> /Help", "help", "", true},
+		{"please run /good", "", "", false},
+		{"/", "", "", false},
+		{"hello", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, args, ok := Parse(tt.text)
+		if name != tt.wantName || args != tt.wantArgs || ok != tt.wantOK {
+			t.Errorf("Parse(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.text, name, args, ok, tt.wantName, tt.wantArgs, tt.wantOK)
+		}
+	}
+}
+
+func TestRegistry_Handle(t *testing.T) {
+	r := NewRegistry()
+	var got Invocation
+	r.Register(&Command{
+		Name:        "echo",
+		Description: "Echo args",
+		Run: func(_ context.Context, inv Invocation) (string, error) {
+			got = inv
+			return strings.Join(inv.Args, "|"), nil
+		},
+	})
+
+	reply, handled, err := r.Handle(context.Background(), "C1", "T1", "U1", "/echo a b")
+	if err != nil || !handled {
+		t.Fatalf("handled=%v err=%v", handled, err)
+	}
+	if reply != "a|b" {
+		t.Errorf("reply = %q", reply)
+	}
+	if got.Channel != "C1" || got.Thread != "T1" || got.UserID != "U1" || got.RawArgs != "a b" {
+		t.Errorf("unexpected invocation: %+v", got)
+	}
+}
+
+func TestRegistry_UnknownFallsThrough(t *testing.T) {
+	r := NewRegistry()
+	_, handled, err := r.Handle(context.Background(), "C1", "T1", "U1", "/unknown")
+	if handled || err != nil {
+		t.Errorf("unknown command should not be handled: %v %v", handled, err)
+	}
+}
+
+func TestRegistry_Error(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&Command{Name: "fail", Run: func(context.Context, Invocation) (string, error) {
+		return "", errors.New("boom")
+	}})
+
+	reply, handled, err := r.Handle(context.Background(), "C1", "T1", "U1", "/fail")
+	if !handled || err == nil {
+		t.Fatal("expected handled error")
+	}
+	if !strings.Contains(reply, "boom") {
+		t.Errorf("reply should include error: %q", reply)
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&Command{Name: "good", Usage: "/good [comment]", Description: "Rate the task"})
+	r.Register(HelpCommand(r))
+
+	reply, _, _ := r.Handle(context.Background(), "C1", "T1", "U1", "/help")
+	if !strings.Contains(reply, "/good [comment]") || !strings.Contains(reply, "/help") {
+		t.Errorf("unexpected help: %s", reply)
+	}
+	if strings.Index(reply, "/good") > strings.Index(reply, "/help") {
+		t.Error("commands should be sorted")
+	}
+}
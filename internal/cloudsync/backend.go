@@ -0,0 +1,200 @@
+package cloudsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// ErrNotFound is returned by Backend.Get when the key does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Backend is a remote key/value store. Keys are slash-separated paths.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// DirBackend stores objects in a local directory. Point it at a mounted
+// bucket or a synced folder.
+type DirBackend struct {
+	Root string
+}
+
+// Get implements Backend.
+func (d *DirBackend) Get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(d.Root, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Put implements Backend.
+func (d *DirBackend) Put(_ context.Context, key string, data []byte) error {
+	p := filepath.Join(d.Root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// HTTPDoer abstracts the HTTP client for testing.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebDAVBackend stores objects on a WebDAV server (Nextcloud, Synology,
+// Apache mod_dav, rclone serve webdav, ...).
+type WebDAVBackend struct {
+	BaseURL  string // e.g. https://dav.example.com/codebutler
+	Username string
+	Password string
+	Client   HTTPDoer
+}
+
+func (w *WebDAVBackend) client() HTTPDoer {
+	if w.Client != nil {
+		return w.Client
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+func (w *WebDAVBackend) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := strings.TrimRight(w.BaseURL, "/") + "/" + strings.TrimLeft(key, "/")
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if w.Username != "" {
+		req.SetBasicAuth(w.Username, w.Password)
+	}
+	return req, nil
+}
+
+// Get implements Backend.
+func (w *WebDAVBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := w.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := w.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webdav get %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webdav get %s: status %d", key, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put implements Backend. Parent collections are created as needed.
+func (w *WebDAVBackend) Put(ctx context.Context, key string, data []byte) error {
+	if err := w.mkcolAll(ctx, path.Dir(key)); err != nil {
+		return err
+	}
+
+	req, err := w.newRequest(ctx, http.MethodPut, key, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err := w.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("webdav put %s: %w", key, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webdav put %s: status %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+// mkcolAll creates each collection along dir. 405 (already exists) is fine.
+func (w *WebDAVBackend) mkcolAll(ctx context.Context, dir string) error {
+	if dir == "." || dir == "/" || dir == "" {
+		return nil
+	}
+	var built string
+	for _, part := range strings.Split(dir, "/") {
+		if part == "" {
+			continue
+		}
+		built = path.Join(built, part)
+		req, err := w.newRequest(ctx, "MKCOL", built+"/", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := w.client().Do(req)
+		if err != nil {
+			return fmt.Errorf("webdav mkcol %s: %w", built, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("webdav mkcol %s: status %d", built, resp.StatusCode)
+		}
+	}
+	return nil
+}
+
+// BackendFromConfig builds the backend described by the global sync config.
+func BackendFromConfig(cfg *config.GlobalSync) (Backend, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("sync is not configured")
+	}
+	switch cfg.Backend {
+	case "webdav":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("sync.url is required for the webdav backend")
+		}
+		return &WebDAVBackend{BaseURL: cfg.URL, Username: cfg.Username, Password: cfg.Password}, nil
+	case "s3", "gcs":
+		if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, fmt.Errorf("sync.bucket, sync.accessKeyID and sync.secretAccessKey are required for the %s backend", cfg.Backend)
+		}
+		b := &S3Backend{
+			Bucket:          cfg.Bucket,
+			Region:          cfg.Region,
+			Endpoint:        cfg.Endpoint,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		}
+		if cfg.Backend == "gcs" {
+			if b.Endpoint == "" {
+				b.Endpoint = DefaultGCSEndpoint
+			}
+			if b.Region == "" {
+				b.Region = "auto"
+			}
+		}
+		if b.Region == "" {
+			b.Region = "us-east-1"
+		}
+		return b, nil
+	case "dir":
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("sync.dir is required for the dir backend")
+		}
+		return &DirBackend{Root: cfg.Dir}, nil
+	default:
+		return nil, fmt.Errorf("unknown sync backend %q (supported: webdav, s3, gcs, dir)", cfg.Backend)
+	}
+}
@@ -0,0 +1,13 @@
+// Package cloudsync replicates conversation state, agent memory (the
+// .codebutler/*.md files), and budget state to a remote store so the same
+// repo driven from two machines shares context instead of diverging.
+//
+// Backends are deliberately minimal (get/put by key). WebDAV and S3 are
+// supported natively; the S3 backend also serves GCS (through its
+// S3-compatible API with HMAC keys) and other compatible stores. Anything
+// else can be mounted (rclone mount, gcsfuse) and used with the Dir
+// backend.
+//
+// The remote manifest is not trusted: entries that would land outside the
+// repo, or outside the synced globs, are skipped before any IO.
+package cloudsync
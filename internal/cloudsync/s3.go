@@ -0,0 +1,125 @@
+package cloudsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/sigv4"
+)
+
+// DefaultGCSEndpoint is the S3-compatible XML API of Google Cloud Storage.
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// S3Backend stores objects in an S3 bucket, or any S3-compatible store:
+// GCS with HMAC keys, MinIO, Cloudflare R2. Requests are signed with
+// SigV4 directly, so no SDK is needed.
+//
+// With no Endpoint it addresses AWS virtual-hosted style
+// (bucket.s3.region.amazonaws.com); with one, path style
+// (endpoint/bucket/key), which every compatible store accepts.
+//
+// On AWS, grant s3:ListBucket as well as Get/PutObject: without it a
+// missing key answers 403 instead of 404 and the first sync fails.
+type S3Backend struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	Client          HTTPDoer
+
+	now func() time.Time
+}
+
+func (b *S3Backend) client() HTTPDoer {
+	if b.Client != nil {
+		return b.Client
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+func (b *S3Backend) objectURL(key string) string {
+	segs := strings.Split(strings.TrimLeft(key, "/"), "/")
+	for i, s := range segs {
+		segs[i] = s3Escape(s)
+	}
+	escaped := strings.Join(segs, "/")
+	if b.Endpoint == "" {
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", b.Bucket, b.Region, escaped)
+	}
+	return strings.TrimRight(b.Endpoint, "/") + "/" + b.Bucket + "/" + escaped
+}
+
+// s3Escape percent-encodes everything but RFC 3986 unreserved characters,
+// as SigV4's canonical URI requires.
+func s3Escape(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			out.WriteByte(c)
+		} else {
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+	return out.String()
+}
+
+func (b *S3Backend) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", sigv4.PayloadHash(body))
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	now := time.Now
+	if b.now != nil {
+		now = b.now
+	}
+	sigv4.Sign(req, body, b.Region, "s3", sigv4.Credentials{AccessKeyID: b.AccessKeyID, SecretAccessKey: b.SecretAccessKey}, now())
+	return b.client().Do(req)
+}
+
+// Get implements Backend.
+func (b *S3Backend) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := b.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("s3 get %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("s3 get %s: status %d: %s", key, resp.StatusCode, errorBody(resp.Body))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put implements Backend.
+func (b *S3Backend) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := b.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return fmt.Errorf("s3 put %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("s3 put %s: status %d: %s", key, resp.StatusCode, errorBody(resp.Body))
+	}
+	return nil
+}
+
+// errorBody returns the start of an error response; S3 puts the error code
+// there.
+func errorBody(r io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(r, 512))
+	return strings.TrimSpace(string(data))
+}
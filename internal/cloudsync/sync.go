@@ -0,0 +1,270 @@
+package cloudsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultIncludes are the repo-relative globs replicated by default:
+// per-thread conversations, agent memory MDs, and budget state.
+var DefaultIncludes = []string{
+	".codebutler/branches/*/conversations/*.json",
+	".codebutler/*.md",
+	".codebutler/budgets/*.json",
+}
+
+const manifestKey = "manifest.json"
+
+// Entry records the last synced version of one file.
+type Entry struct {
+	Hash    string    `json:"hash"`
+	ModTime time.Time `json:"mod_time"`
+	Machine string    `json:"machine"`
+}
+
+// Manifest maps repo-relative slash paths to their latest synced version.
+type Manifest map[string]Entry
+
+// Result summarizes a sync pass.
+type Result struct {
+	Pushed []string
+	Pulled []string
+}
+
+// Syncer reconciles local files with a Backend. Conflicts resolve
+// last-writer-wins on modification time.
+type Syncer struct {
+	backend  Backend
+	root     string // repo root
+	prefix   string // remote key prefix, e.g. the repo name
+	includes []string
+	machine  string
+	logger   *slog.Logger
+}
+
+// SyncerOption configures a Syncer.
+type SyncerOption func(*Syncer)
+
+// WithIncludes overrides DefaultIncludes.
+func WithIncludes(globs []string) SyncerOption {
+	return func(s *Syncer) {
+		s.includes = globs
+	}
+}
+
+// WithMachine sets the machine name recorded in the manifest (default hostname).
+func WithMachine(name string) SyncerOption {
+	return func(s *Syncer) {
+		s.machine = name
+	}
+}
+
+// WithSyncLogger sets the logger.
+func WithSyncLogger(l *slog.Logger) SyncerOption {
+	return func(s *Syncer) {
+		s.logger = l
+	}
+}
+
+// NewSyncer creates a syncer for the repo at root. prefix namespaces the
+// repo's objects in the backend so one store can serve several repos.
+func NewSyncer(backend Backend, root, prefix string, opts ...SyncerOption) *Syncer {
+	host, _ := os.Hostname()
+	s := &Syncer{
+		backend:  backend,
+		root:     root,
+		prefix:   prefix,
+		includes: DefaultIncludes,
+		machine:  host,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Syncer) key(rel string) string {
+	return path.Join(s.prefix, rel)
+}
+
+// Sync runs one push/pull pass and updates the remote manifest.
+func (s *Syncer) Sync(ctx context.Context) (*Result, error) {
+	remote, err := s.loadManifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := s.scanLocal()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for p := range local {
+		paths[p] = true
+	}
+	for p := range remote {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	res := &Result{}
+	changed := false
+
+	for _, rel := range sorted {
+		l, hasLocal := local[rel]
+		r, hasRemote := remote[rel]
+		if !hasLocal && !s.syncable(rel) {
+			// A corrupted or tampered manifest must not make us read
+			// other objects or write outside the repo.
+			s.logger.Warn("skipping unsafe manifest entry", "path", rel)
+			continue
+		}
+
+		switch {
+		case hasLocal && hasRemote && l.Hash == r.Hash:
+			continue
+		case hasLocal && (!hasRemote || l.ModTime.After(r.ModTime)):
+			if err := s.push(ctx, rel); err != nil {
+				return res, err
+			}
+			l.Machine = s.machine
+			remote[rel] = l
+			res.Pushed = append(res.Pushed, rel)
+			changed = true
+		default:
+			if err := s.pull(ctx, rel, r); err != nil {
+				return res, err
+			}
+			res.Pulled = append(res.Pulled, rel)
+		}
+	}
+
+	if changed {
+		if err := s.saveManifest(ctx, remote); err != nil {
+			return res, err
+		}
+	}
+
+	s.logger.Info("sync complete", "pushed", len(res.Pushed), "pulled", len(res.Pulled))
+	return res, nil
+}
+
+func (s *Syncer) push(ctx context.Context, rel string) error {
+	data, err := os.ReadFile(filepath.Join(s.root, filepath.FromSlash(rel)))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", rel, err)
+	}
+	if err := s.backend.Put(ctx, s.key(rel), data); err != nil {
+		return fmt.Errorf("push %s: %w", rel, err)
+	}
+	return nil
+}
+
+// syncable reports whether a manifest path is a clean, repo-local path
+// matching one of the include globs. Local scans only produce such paths;
+// remote entries are checked before they are fetched or written.
+func (s *Syncer) syncable(rel string) bool {
+	if rel == "" || path.Clean(rel) != rel || !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return false
+	}
+	for _, glob := range s.includes {
+		if ok, _ := path.Match(glob, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Syncer) pull(ctx context.Context, rel string, e Entry) error {
+	if !s.syncable(rel) {
+		return fmt.Errorf("pull %s: path is outside the synced files", rel)
+	}
+	data, err := s.backend.Get(ctx, s.key(rel))
+	if err != nil {
+		return fmt.Errorf("pull %s: %w", rel, err)
+	}
+
+	dst := filepath.Join(s.root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("create dir for %s: %w", rel, err)
+	}
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", rel, err)
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename %s: %w", rel, err)
+	}
+	// Keep the remote mtime so the next pass sees the file as in sync.
+	return os.Chtimes(dst, e.ModTime, e.ModTime)
+}
+
+func (s *Syncer) scanLocal() (Manifest, error) {
+	m := make(Manifest)
+	for _, glob := range s.includes {
+		matches, err := filepath.Glob(filepath.Join(s.root, filepath.FromSlash(glob)))
+		if err != nil {
+			return nil, fmt.Errorf("bad include %q: %w", glob, err)
+		}
+		for _, abs := range matches {
+			info, err := os.Stat(abs)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(abs)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", abs, err)
+			}
+			rel, _ := filepath.Rel(s.root, abs)
+			sum := sha256.Sum256(data)
+			m[filepath.ToSlash(rel)] = Entry{
+				Hash:    hex.EncodeToString(sum[:]),
+				ModTime: info.ModTime().UTC(),
+			}
+		}
+	}
+	return m, nil
+}
+
+func (s *Syncer) loadManifest(ctx context.Context) (Manifest, error) {
+	data, err := s.backend.Get(ctx, s.key(manifestKey))
+	if errors.Is(err, ErrNotFound) {
+		return make(Manifest), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load manifest: %w", err)
+	}
+	m := make(Manifest)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	return m, nil
+}
+
+func (s *Syncer) saveManifest(ctx context.Context, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := s.backend.Put(ctx, s.key(manifestKey), data); err != nil {
+		return fmt.Errorf("save manifest: %w", err)
+	}
+	return nil
+}
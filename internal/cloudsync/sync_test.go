@@ -0,0 +1,308 @@
+package cloudsync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+}
+
+func writeFile(t *testing.T, root, rel, content string, mtime time.Time) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	os.MkdirAll(filepath.Dir(p), 0o755)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(p, mtime, mtime)
+}
+
+func readFile(t *testing.T, root, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSync_TwoMachines(t *testing.T) {
+	remote := &DirBackend{Root: t.TempDir()}
+	desktop := t.TempDir()
+	server := t.TempDir()
+	base := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	conv := ".codebutler/branches/login/conversations/coder.json"
+	writeFile(t, desktop, conv, `{"v":1}`, base)
+	writeFile(t, desktop, ".codebutler/coder.md", "# Coder\n", base)
+
+	a := NewSyncer(remote, desktop, "myrepo", WithMachine("desktop"), WithSyncLogger(testLogger()))
+	b := NewSyncer(remote, server, "myrepo", WithMachine("server"), WithSyncLogger(testLogger()))
+
+	res, err := a.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pushed) != 2 {
+		t.Fatalf("expected 2 pushed, got %v", res.Pushed)
+	}
+
+	res, err = b.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pulled) != 2 {
+		t.Fatalf("expected 2 pulled, got %v", res.Pulled)
+	}
+	if got := readFile(t, server, conv); got != `{"v":1}` {
+		t.Errorf("server conversation = %s", got)
+	}
+
+	// Server continues the conversation; desktop picks it up.
+	writeFile(t, server, conv, `{"v":2}`, base.Add(time.Hour))
+	if res, _ := b.Sync(context.Background()); len(res.Pushed) != 1 {
+		t.Fatalf("expected server to push 1, got %v", res.Pushed)
+	}
+	if res, _ := a.Sync(context.Background()); len(res.Pulled) != 1 {
+		t.Fatalf("expected desktop to pull 1, got %v", res.Pulled)
+	}
+	if got := readFile(t, desktop, conv); got != `{"v":2}` {
+		t.Errorf("desktop conversation = %s", got)
+	}
+
+	// Nothing changed: no-op.
+	res, _ = a.Sync(context.Background())
+	if len(res.Pushed)+len(res.Pulled) != 0 {
+		t.Errorf("expected no-op, got %+v", res)
+	}
+}
+
+func TestSync_IgnoresUnlistedFiles(t *testing.T) {
+	remote := &DirBackend{Root: t.TempDir()}
+	root := t.TempDir()
+	writeFile(t, root, ".codebutler/images/logo.png", "png", time.Now())
+
+	res, err := NewSyncer(remote, root, "r", WithSyncLogger(testLogger())).Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pushed) != 0 {
+		t.Errorf("images should not sync, got %v", res.Pushed)
+	}
+}
+
+func TestDirBackend_NotFound(t *testing.T) {
+	d := &DirBackend{Root: t.TempDir()}
+	if _, err := d.Get(context.Background(), "missing.json"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+type fakeDAV struct {
+	files   map[string][]byte
+	methods []string
+}
+
+func (f *fakeDAV) Do(req *http.Request) (*http.Response, error) {
+	f.methods = append(f.methods, req.Method+" "+req.URL.Path)
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(""))}
+
+	if u, p, ok := req.BasicAuth(); !ok || u != "me" || p != "secret" {
+		resp.StatusCode = http.StatusUnauthorized
+		return resp, nil
+	}
+
+	switch req.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		f.files[req.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.files[req.URL.Path]
+		if !ok {
+			resp.StatusCode = http.StatusNotFound
+			return resp, nil
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+	case "MKCOL":
+		resp.StatusCode = http.StatusMethodNotAllowed // already exists
+	}
+	return resp, nil
+}
+
+func TestWebDAVBackend_PutGet(t *testing.T) {
+	dav := &fakeDAV{files: make(map[string][]byte)}
+	w := &WebDAVBackend{BaseURL: "https://dav.example.com/cb", Username: "me", Password: "secret", Client: dav}
+	ctx := context.Background()
+
+	if _, err := w.Get(ctx, "repo/x.json"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := w.Put(ctx, "repo/a/x.json", []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := w.Get(ctx, "repo/a/x.json")
+	if err != nil || string(got) != "hi" {
+		t.Errorf("got %q, %v", got, err)
+	}
+
+	var mkcols int
+	for _, m := range dav.methods {
+		if strings.HasPrefix(m, "MKCOL") {
+			mkcols++
+		}
+	}
+	if mkcols != 2 {
+		t.Errorf("expected 2 MKCOL requests, got %d (%v)", mkcols, dav.methods)
+	}
+}
+
+func TestBackendFromConfig(t *testing.T) {
+	if _, err := BackendFromConfig(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+	if _, err := BackendFromConfig(&config.GlobalSync{Backend: "ftp"}); err == nil {
+		t.Error("expected error for unsupported backend")
+	}
+	if _, err := BackendFromConfig(&config.GlobalSync{Backend: "s3", Bucket: "b"}); err == nil {
+		t.Error("expected error for s3 without keys")
+	}
+	if _, err := BackendFromConfig(&config.GlobalSync{Backend: "webdav"}); err == nil {
+		t.Error("expected error for webdav without url")
+	}
+	b, err := BackendFromConfig(&config.GlobalSync{Backend: "dir", Dir: "/mnt/bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.(*DirBackend); !ok {
+		t.Errorf("expected DirBackend, got %T", b)
+	}
+
+	b, err = BackendFromConfig(&config.GlobalSync{Backend: "gcs", Bucket: "b", AccessKeyID: "GOOG1", SecretAccessKey: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s3, ok := b.(*S3Backend); !ok || s3.Endpoint != DefaultGCSEndpoint || s3.Region != "auto" {
+		t.Errorf("gcs backend = %+v", b)
+	}
+	b, _ = BackendFromConfig(&config.GlobalSync{Backend: "s3", Bucket: "b", AccessKeyID: "AKIA", SecretAccessKey: "s"})
+	if s3 := b.(*S3Backend); s3.Region != "us-east-1" || s3.objectURL("r/a b.json") != "https://b.s3.us-east-1.amazonaws.com/r/a%20b.json" {
+		t.Errorf("s3 backend = %+v, url %s", s3, s3.objectURL("r/a b.json"))
+	}
+}
+
+// recordingBackend logs every key it is asked for.
+type recordingBackend struct {
+	Backend
+	keys []string
+}
+
+func (r *recordingBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	r.keys = append(r.keys, key)
+	return r.Backend.Get(ctx, key)
+}
+
+func TestSync_RejectsHostileManifest(t *testing.T) {
+	outer := t.TempDir()
+	root := filepath.Join(outer, "repo")
+	os.MkdirAll(root, 0o755)
+	store := &DirBackend{Root: filepath.Join(outer, "remote")}
+	ctx := context.Background()
+
+	hostile := []string{
+		"../../.ssh/authorized_keys",
+		"../outside.json",
+		"/etc/cron.d/evil",
+		".codebutler/budgets/../../../escape.json",
+		".codebutler/../README.md",
+		"Makefile", // repo-local, but not a synced file
+	}
+	manifest := `{`
+	for i, p := range hostile {
+		if i > 0 {
+			manifest += ","
+		}
+		manifest += `"` + p + `": {"hash": "x", "mod_time": "2030-01-01T00:00:00Z"}`
+	}
+	store.Put(ctx, "r/Makefile", []byte("pwned"))
+	manifest += `, ".codebutler/budgets/day.json": {"hash": "y", "mod_time": "2030-01-01T00:00:00Z"}}`
+	store.Put(ctx, "r/manifest.json", []byte(manifest))
+	store.Put(ctx, "r/.codebutler/budgets/day.json", []byte(`{"cost":1}`))
+
+	rec := &recordingBackend{Backend: store}
+	res, err := NewSyncer(rec, root, "r", WithSyncLogger(testLogger())).Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pulled) != 1 || res.Pulled[0] != ".codebutler/budgets/day.json" {
+		t.Errorf("pulled = %v", res.Pulled)
+	}
+	if len(rec.keys) != 2 { // manifest + the one legitimate file
+		t.Errorf("fetched %v", rec.keys)
+	}
+	for _, p := range []string{".ssh/authorized_keys", "outside.json", "escape.json", "repo/README.md", "repo/Makefile"} {
+		if _, err := os.Stat(filepath.Join(outer, p)); err == nil {
+			t.Errorf("%s was written", p)
+		}
+	}
+}
+
+// fakeS3 checks signing and stores objects by path.
+type fakeS3 struct {
+	t       *testing.T
+	objects map[string][]byte
+}
+
+func (f *fakeS3) Do(req *http.Request) (*http.Response, error) {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIA/") || !strings.Contains(auth, "/auto/s3/aws4_request") ||
+		!strings.Contains(auth, "x-amz-content-sha256") {
+		f.t.Errorf("authorization = %q", auth)
+	}
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}
+	switch req.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		f.objects[req.URL.EscapedPath()] = data
+	case http.MethodGet:
+		data, ok := f.objects[req.URL.EscapedPath()]
+		if !ok {
+			resp.StatusCode = http.StatusNotFound
+			resp.Body = io.NopCloser(strings.NewReader("<Error><Code>NoSuchKey</Code></Error>"))
+			return resp, nil
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	return resp, nil
+}
+
+func TestS3Backend_PutGet(t *testing.T) {
+	fake := &fakeS3{t: t, objects: map[string][]byte{}}
+	b := &S3Backend{Bucket: "cb", Region: "auto", Endpoint: DefaultGCSEndpoint + "/", AccessKeyID: "AKIA", SecretAccessKey: "s", Client: fake}
+	ctx := context.Background()
+
+	if _, err := b.Get(ctx, "repo/x.json"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := b.Put(ctx, "repo/fix (1)/x.json", []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fake.objects["/cb/repo/fix%20%281%29/x.json"]; !ok {
+		t.Errorf("objects = %v", fake.objects)
+	}
+	if got, err := b.Get(ctx, "repo/fix (1)/x.json"); err != nil || string(got) != "hi" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,299 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RequiredGlobal and RequiredRepo list, as JSON paths, the fields
+// CodeButler cannot start without. Load, CheckGlobal, CheckRepo and the
+// schemas all read them from here.
+var (
+	RequiredGlobal = []string{"slack.botToken", "slack.appToken", "openrouter.apiKey"}
+	RequiredRepo   = []string{"slack.channelID"}
+)
+
+// schemaKey is the editor hint a config file may carry; it is not a
+// config field.
+const schemaKey = "$schema"
+
+// Issue is one problem in a config file, with what to do about it.
+type Issue struct {
+	File    string `json:"file"`
+	Field   string `json:"field,omitempty"` // JSON path, e.g. "slack.botToken"
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+func (i Issue) String() string {
+	s := i.File
+	if i.Field != "" {
+		s += ": " + i.Field
+	}
+	s += ": " + i.Message
+	if i.Fix != "" {
+		s += " (" + i.Fix + ")"
+	}
+	return s
+}
+
+// CheckGlobal reads the global config under globalDir (default
+// ~/.codebutler) and reports every problem it finds instead of stopping
+// at the first. The config is nil when the file cannot be read or parsed.
+func CheckGlobal(globalDir string) (*GlobalConfig, []Issue) {
+	if globalDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, []Issue{{File: "~/" + codebutlerDir + "/" + configFile, Message: err.Error()}}
+		}
+		globalDir = filepath.Join(home, codebutlerDir)
+	}
+	var g GlobalConfig
+	issues, ok := checkFile(filepath.Join(globalDir, configFile), &g, RequiredGlobal)
+	if !ok {
+		return nil, issues
+	}
+	return &g, issues
+}
+
+// CheckRepo is CheckGlobal for the repo config under repoRoot.
+func CheckRepo(repoRoot string) (*RepoConfig, []Issue) {
+	var r RepoConfig
+	issues, ok := checkFile(filepath.Join(repoRoot, codebutlerDir, configFile), &r, RequiredRepo)
+	if !ok {
+		return nil, issues
+	}
+	return &r, issues
+}
+
+// checkFile decodes path into dest and lints it. ok is false when dest
+// could not be filled.
+func checkFile(path string, dest any, required []string) (issues []Issue, ok bool) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Issue{{File: path, Message: "file not found", Fix: "run `codebutler init` to create it"}}, false
+	}
+	if err != nil {
+		return []Issue{{File: path, Message: err.Error()}}, false
+	}
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		msg := err.Error()
+		var syn *json.SyntaxError
+		if errors.As(err, &syn) {
+			line, col := position(data, syn.Offset)
+			msg = fmt.Sprintf("line %d, column %d: %s", line, col, syn)
+		}
+		return []Issue{{File: path, Message: "invalid JSON: " + msg}}, false
+	}
+	if _, isObject := raw.(map[string]any); !isObject {
+		return []Issue{{File: path, Message: "top level must be a JSON object"}}, false
+	}
+
+	t := reflect.TypeOf(dest).Elem()
+	for _, u := range unknownFields(raw, t, "") {
+		issue := Issue{File: path, Field: u.path, Message: "unknown field", Fix: "remove it"}
+		if u.suggest != "" {
+			// encoding/json accepts it, but editors and the schema do not.
+			issue.Message = "wrong case"
+			issue.Fix = "rename it to " + u.suggest
+		}
+		issues = append(issues, issue)
+	}
+	unset := map[string]bool{}
+	for _, ref := range envRefs(raw, "") {
+		if _, set := os.LookupEnv(ref.name); !set {
+			unset[ref.path] = true
+			issues = append(issues, Issue{
+				File: path, Field: ref.path,
+				Message: "references ${" + ref.name + "}, which is not set",
+				Fix:     "export " + ref.name + " before starting CodeButler",
+			})
+		}
+	}
+
+	if err := json.Unmarshal([]byte(resolveEnvVars(string(data))), dest); err != nil {
+		var typ *json.UnmarshalTypeError
+		if !errors.As(err, &typ) {
+			return append(issues, Issue{File: path, Message: err.Error()}), false
+		}
+		issues = append(issues, Issue{
+			File: path, Field: typ.Field,
+			Message: fmt.Sprintf("expected %s, got %s", jsonType(typ.Type), typ.Value),
+		})
+	}
+
+	for _, f := range missingFields(dest, required) {
+		if unset[f] {
+			continue // already reported with the variable to set
+		}
+		issues = append(issues, Issue{File: path, Field: f, Message: "required", Fix: "set " + f})
+	}
+	return issues, true
+}
+
+// position converts a byte offset to a 1-based line and column.
+func position(data []byte, offset int64) (line, col int) {
+	line, col = 1, 1
+	for _, c := range data[:min(int(offset), len(data))] {
+		if c == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+// jsonFields returns a struct type's fields by JSON name, including
+// those promoted from untagged embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	out := map[string]reflect.StructField{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for n, sub := range jsonFields(f.Type) {
+				sub.Index = append([]int{i}, sub.Index...)
+				out[n] = sub
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out[name] = f
+	}
+	return out
+}
+
+// missingFields returns the paths whose value in v (a pointer to a
+// struct) is empty or behind a nil pointer.
+func missingFields(v any, paths []string) []string {
+	var missing []string
+	for _, p := range paths {
+		cur := reflect.ValueOf(v)
+		for _, seg := range strings.Split(p, ".") {
+			for cur.Kind() == reflect.Pointer && !cur.IsNil() {
+				cur = cur.Elem()
+			}
+			if cur.Kind() != reflect.Struct {
+				cur = reflect.Value{}
+				break
+			}
+			f, ok := jsonFields(cur.Type())[seg]
+			if !ok {
+				panic("config: required field " + p + " does not exist")
+			}
+			cur = cur.FieldByIndex(f.Index)
+		}
+		if !cur.IsValid() || cur.IsZero() {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+type unknownField struct {
+	path    string
+	suggest string // the field it matches ignoring case
+}
+
+// unknownFields walks decoded JSON alongside the type it decodes into and
+// returns the keys encoding/json would silently drop.
+func unknownFields(raw any, t reflect.Type, path string) []unknownField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var out []unknownField
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, k := range sortedKeys(obj) {
+			if path == "" && k == schemaKey {
+				continue
+			}
+			f, ok := fields[k]
+			if !ok {
+				u := unknownField{path: join(path, k)}
+				for name := range fields {
+					if strings.EqualFold(name, k) {
+						u.suggest = join(path, name)
+					}
+				}
+				out = append(out, u)
+				continue
+			}
+			out = append(out, unknownFields(obj[k], f.Type, join(path, k))...)
+		}
+	case reflect.Map:
+		obj, _ := raw.(map[string]any)
+		for _, k := range sortedKeys(obj) {
+			out = append(out, unknownFields(obj[k], t.Elem(), join(path, k))...)
+		}
+	case reflect.Slice:
+		arr, _ := raw.([]any)
+		for i, v := range arr {
+			out = append(out, unknownFields(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return out
+}
+
+type envRef struct{ path, name string }
+
+// envRefs returns every ${VAR} reference in decoded JSON's string values.
+func envRefs(raw any, path string) []envRef {
+	var out []envRef
+	switch v := raw.(type) {
+	case string:
+		for _, m := range envVarPattern.FindAllStringSubmatch(v, -1) {
+			out = append(out, envRef{path: path, name: m[1]})
+		}
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			out = append(out, envRefs(v[k], join(path, k))...)
+		}
+	case []any:
+		for i, e := range v {
+			out = append(out, envRefs(e, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
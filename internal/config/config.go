@@ -5,9 +5,48 @@ package config
 // GlobalConfig holds secrets loaded from ~/.codebutler/config.json.
 // This file is never committed to git.
 type GlobalConfig struct {
-	Slack      GlobalSlack      `json:"slack"`
-	OpenRouter GlobalOpenRouter `json:"openrouter"`
-	OpenAI     GlobalOpenAI     `json:"openai"`
+	Slack      GlobalSlack       `json:"slack"`
+	OpenRouter GlobalOpenRouter  `json:"openrouter"`
+	OpenAI     GlobalOpenAI      `json:"openai"`
+	ElevenLabs *GlobalElevenLabs `json:"elevenlabs,omitempty"`
+	Ollama     *GlobalOllama     `json:"ollama,omitempty"`
+	Alerts     GlobalAlerts      `json:"alerts,omitempty"`
+	Sync       *GlobalSync       `json:"sync,omitempty"`
+
+	Observability *GlobalObservability `json:"observability,omitempty"`
+
+	// Tracing exports OpenTelemetry spans for each request over OTLP/HTTP.
+	Tracing *GlobalTracing `json:"tracing,omitempty"`
+
+	// Databases are the connections exposed through the read-only SQLQuery
+	// tool, keyed by name.
+	Databases map[string]DatabaseConfig `json:"databases,omitempty"`
+
+	// Store moves message, session and budget state into a database so
+	// several daemon instances on a shared server see the same state.
+	Store *GlobalStore `json:"store,omitempty"`
+
+	// Encryption seals chat history at rest: conversation files and the
+	// message, session and budget rows of the store.
+	Encryption *GlobalEncryption `json:"encryption,omitempty"`
+}
+
+// GlobalStore selects the shared state backend. Backend is "sqlite"
+// (default DSN .codebutler/store.db in the repo), "postgres", or
+// "memory" (process-local, for demos and tests). Driver defaults to
+// "sqlite3" or "postgres"; the binary must register it.
+type GlobalStore struct {
+	Backend string `json:"backend"`
+	Driver  string `json:"driver,omitempty"`
+	DSN     string `json:"dsn,omitempty"`
+}
+
+// GlobalEncryption enables AES-256-GCM encryption at rest. KeySource is
+// "env" (default, $CODEBUTLER_ENCRYPTION_KEY) or "keychain" (macOS
+// Keychain or the Linux Secret Service).
+type GlobalEncryption struct {
+	Enabled   bool   `json:"enabled"`
+	KeySource string `json:"keySource,omitempty"`
 }
 
 type GlobalSlack struct {
@@ -23,6 +62,122 @@ type GlobalOpenAI struct {
 	APIKey string `json:"apiKey"`
 }
 
+// GlobalElevenLabs holds the key for ElevenLabs text-to-speech.
+type GlobalElevenLabs struct {
+	APIKey string `json:"apiKey"`
+}
+
+// GlobalTracing configures the OTLP/HTTP trace exporter, e.g. a local
+// collector at http://localhost:4318 or a vendor endpoint with an API key
+// header.
+type GlobalTracing struct {
+	Endpoint    string            `json:"endpoint"`              // base URL; spans go to <endpoint>/v1/traces
+	Headers     map[string]string `json:"headers,omitempty"`     // sent with every export, e.g. auth
+	ServiceName string            `json:"serviceName,omitempty"` // default "codebutler"
+	SampleRatio float64           `json:"sampleRatio,omitempty"` // fraction of requests traced; 0 = all
+}
+
+// GlobalOllama points at a local Ollama server. Roles use it by setting
+// their model to "ollama/<name>".
+type GlobalOllama struct {
+	BaseURL string `json:"baseURL,omitempty"` // default http://localhost:11434
+}
+
+// GlobalAlerts configures the out-of-band escalation channels used when an
+// agent hits a fatal state. All channels are optional.
+type GlobalAlerts struct {
+	SlackWebhookURL string        `json:"slackWebhookURL,omitempty"`
+	Pushover        *PushoverAuth `json:"pushover,omitempty"`
+	Email           *EmailAlerts  `json:"email,omitempty"`
+	Ntfy            *NtfyConfig   `json:"ntfy,omitempty"`
+
+	// TaskEvents also pushes task lifecycle events ("done", "approval",
+	// "failed") through the channels above. Empty means fatal alerts only.
+	TaskEvents []string `json:"taskEvents,omitempty"`
+}
+
+// NtfyConfig publishes alerts to an ntfy topic (ntfy.sh or self-hosted).
+type NtfyConfig struct {
+	Server string `json:"server,omitempty"` // default https://ntfy.sh
+	Topic  string `json:"topic"`
+	Token  string `json:"token,omitempty"` // access token for protected topics
+}
+
+type PushoverAuth struct {
+	Token string `json:"token"`
+	User  string `json:"user"`
+}
+
+// GlobalSync configures the optional cross-machine state sync backend.
+type GlobalSync struct {
+	Backend  string `json:"backend"`            // "webdav", "s3", "gcs" or "dir"
+	URL      string `json:"url,omitempty"`      // WebDAV base URL
+	Username string `json:"username,omitempty"` // WebDAV basic auth
+	Password string `json:"password,omitempty"`
+	Dir      string `json:"dir,omitempty"`      // dir backend root (e.g. a mounted bucket)
+
+	// S3 and GCS (through its S3-compatible API with HMAC keys).
+	Bucket          string `json:"bucket,omitempty"`
+	Region          string `json:"region,omitempty"`   // S3 default "us-east-1"; GCS uses "auto"
+	Endpoint        string `json:"endpoint,omitempty"` // S3-compatible server, e.g. MinIO or R2
+	AccessKeyID     string `json:"accessKeyID,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+}
+
+// GlobalObservability configures the backends queried by the LogsQuery and
+// MetricsQuery tools. Each backend is optional.
+type GlobalObservability struct {
+	Loki       *LokiConfig       `json:"loki,omitempty"`
+	Prometheus *PrometheusConfig `json:"prometheus,omitempty"`
+	CloudWatch *CloudWatchConfig `json:"cloudwatch,omitempty"`
+	Datadog    *DatadogConfig    `json:"datadog,omitempty"`
+}
+
+type LokiConfig struct {
+	URL          string `json:"url"`
+	Username     string `json:"username,omitempty"`
+	Password     string `json:"password,omitempty"`
+	TenantID     string `json:"tenantID,omitempty"`
+	ServiceLabel string `json:"serviceLabel,omitempty"` // default "service"
+}
+
+type PrometheusConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type CloudWatchConfig struct {
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	LogGroupPrefix  string `json:"logGroupPrefix,omitempty"` // log group = prefix + service
+}
+
+type DatadogConfig struct {
+	APIKey string `json:"apiKey"`
+	AppKey string `json:"appKey"`
+	Site   string `json:"site,omitempty"` // default "datadoghq.com"
+}
+
+// DatabaseConfig is one SQLQuery connection. Only the listed roles may query
+// it. The driver must be compiled into the binary.
+type DatabaseConfig struct {
+	Driver         string   `json:"driver"` // database/sql driver name, e.g. "postgres", "mysql"
+	DSN            string   `json:"dsn"`
+	Roles          []string `json:"roles"`                    // agent roles allowed to query
+	MaxRows        int      `json:"maxRows,omitempty"`        // default 200
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty"` // default 10
+}
+
+type EmailAlerts struct {
+	SMTPAddr string   `json:"smtpAddr"` // host:port
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
 // RepoConfig holds per-repo settings loaded from <repo>/.codebutler/config.json.
 // This file is committed to git.
 type RepoConfig struct {
@@ -30,6 +185,139 @@ type RepoConfig struct {
 	Models     ModelsConfig   `json:"models"`
 	MultiModel MultiModel     `json:"multiModel"`
 	Limits     LimitsConfig   `json:"limits"`
+	Locale     string         `json:"locale,omitempty"` // bot message locale (e.g. "en", "es"); default "en"
+	HTTP       RepoHTTP       `json:"http,omitempty"`
+
+	// CostFooter appends a "— turns · tokens · cost · model" line to each
+	// bot response.
+	CostFooter bool `json:"costFooter,omitempty"`
+
+	// SessionScope is "thread" (default: one shared session per thread) or
+	// "sender" (each person in a thread gets their own session).
+	SessionScope string `json:"sessionScope,omitempty"`
+
+	Privacy RepoPrivacy `json:"privacy,omitempty"`
+	Review  RepoReview  `json:"review,omitempty"`
+
+	// Mentions adds handoff targets beyond the built-in @codebutler.<role>
+	// agents: custom agents and people.
+	Mentions []RepoMention `json:"mentions,omitempty"`
+
+	Streaming RepoStreaming `json:"streaming,omitempty"`
+
+	VoiceReplies RepoVoiceReplies `json:"voiceReplies,omitempty"`
+
+	Approvals RepoApprovals `json:"approvals,omitempty"`
+
+	GC RepoGC `json:"gc,omitempty"`
+
+	// Schedules are prompts run on a cron schedule, with results posted to
+	// Channel (default: the repo channel).
+	Schedules []RepoSchedule `json:"schedules,omitempty"`
+
+	ToolProfiles RepoToolProfiles `json:"toolProfiles,omitempty"`
+}
+
+// RepoToolProfiles limits which tools a run may use. Built-in profiles are
+// "full", "code-only", "read-only" and "no-network"; Profiles adds or
+// overrides them. Every profile that applies to a run — the chat's, the
+// sender's user role's and the workflow phase's — must allow a tool for it
+// to be offered.
+type RepoToolProfiles struct {
+	Profiles  map[string]RepoToolProfile `json:"profiles,omitempty"`
+	Default   string                     `json:"default,omitempty"`   // applies when a chat has no profile; default "full"
+	Channels  map[string]string          `json:"channels,omitempty"`  // Slack channel ID → profile
+	Users     map[string]string          `json:"users,omitempty"`     // Slack user ID → user role, e.g. "contractor"
+	UserRoles map[string]string          `json:"userRoles,omitempty"` // user role → profile
+	Phases    map[string]string          `json:"phases,omitempty"`    // agent role ("pm", "coder", "reviewer") → profile
+}
+
+// RepoToolProfile is an allow list (empty = every tool) minus a deny list.
+type RepoToolProfile struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// RepoSchedule is a recurring prompt, e.g. a nightly test run.
+type RepoSchedule struct {
+	Name    string `json:"name"`
+	Cron    string `json:"cron"` // five fields, or @hourly/@daily/@weekly/@monthly
+	Prompt  string `json:"prompt"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// RepoStreaming shows an agent's progress as one Slack message that is
+// edited in place while it works, instead of a single reply at the end.
+type RepoStreaming struct {
+	Enabled         bool `json:"enabled,omitempty"`
+	IntervalSeconds int  `json:"intervalSeconds,omitempty"` // minimum time between edits; default 3
+}
+
+// RepoGC tunes worktree garbage collection. Zero values keep the defaults
+// (every 6h, 48h inactivity, 24h grace after the warning, no disk quota).
+type RepoGC struct {
+	IntervalHours   int `json:"intervalHours,omitempty"`
+	InactivityHours int `json:"inactivityHours,omitempty"`
+	GraceHours      int `json:"graceHours,omitempty"`
+	DiskQuotaMB     int `json:"diskQuotaMB,omitempty"`
+}
+
+// RepoApprovals lists the tool calls that pause the run until the task's
+// requester or one of Approvers replies 1 (approve) or 2 (reject) in the
+// thread. Destructive shell commands always ask.
+type RepoApprovals struct {
+	Tools          []string `json:"tools,omitempty"`          // e.g. ["GitPush"]
+	Patterns       []string `json:"patterns,omitempty"`       // command words; default force push, rm, migrate, deploy
+	TimeoutMinutes int      `json:"timeoutMinutes,omitempty"` // unanswered requests are rejected; default 30
+	Approvers      []string `json:"approvers,omitempty"`      // Slack user IDs who may answer besides the requester
+}
+
+// RepoVoiceReplies speaks short final responses back as audio files, for
+// people who talk to the bot hands-free.
+type RepoVoiceReplies struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Provider string `json:"provider,omitempty"` // "openai" (default) or "elevenlabs"
+	Voice    string `json:"voice,omitempty"`    // provider voice name or ID
+	MaxChars int    `json:"maxChars,omitempty"` // longer replies stay text-only; default 600
+}
+
+// RepoMention maps a handle to a custom agent or a person. A UserID makes
+// it a human target; otherwise Handle must be @codebutler.<role>.
+type RepoMention struct {
+	Handle      string `json:"handle"`
+	Role        string `json:"role,omitempty"`
+	UserID      string `json:"userID,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// RepoReview configures the reviewer's static fast path: tiny docs,
+// comment, or rename diffs are checked by rules and commands instead of an
+// LLM review.
+type RepoReview struct {
+	StaticFastPath  bool     `json:"staticFastPath,omitempty"`
+	MaxChangedLines int      `json:"maxChangedLines,omitempty"` // default 30
+	Checks          []string `json:"checks,omitempty"`          // e.g. ["go build ./...", "go vet ./..."]
+}
+
+// RepoPrivacy holds data-residency switches for repos that handle sensitive
+// client data.
+type RepoPrivacy struct {
+	// MetadataOnlyStorage persists conversations as hashes and metadata
+	// instead of message content (see privacy.MetadataStore).
+	MetadataOnlyStorage bool `json:"metadataOnlyStorage,omitempty"`
+	// ScrubLogs hashes message previews and other content in log output.
+	ScrubLogs bool `json:"scrubLogs,omitempty"`
+	// Anonymize replaces emails, phone numbers, and names in everything
+	// sent to the model with placeholders and restores them in replies. KnownNames lists people or clients to always replace.
+	Anonymize  bool     `json:"anonymize,omitempty"`
+	KnownNames []string `json:"knownNames,omitempty"`
+}
+
+// RepoHTTP restricts the hosts the HTTPRequest tool may call. Entries are
+// hostnames ("api.internal"), wildcards ("*.example.com"), or host:port
+// ("localhost:8080"). An empty list disables the tool.
+type RepoHTTP struct {
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
 }
 
 type RepoSlack struct {
@@ -45,6 +333,9 @@ type ModelsConfig struct {
 	Researcher *AgentModelConfig   `json:"researcher,omitempty"`
 	Lead       *AgentModelConfig   `json:"lead,omitempty"`
 	Artist     *ArtistModelConfig  `json:"artist,omitempty"`
+
+	// Utility routes the frequent cheap calls away from the role models.
+	Utility *UtilityModelConfig `json:"utility,omitempty"`
 }
 
 // PMModelConfig supports a default model and a hot-swap pool.
@@ -53,10 +344,26 @@ type PMModelConfig struct {
 	Pool    map[string]string `json:"pool,omitempty"`
 }
 
-// AgentModelConfig holds a single model for a standard agent.
+// AgentModelConfig holds a single model for a standard agent. An
+// "ollama/<name>" model runs on the local Ollama server at no cost.
 type AgentModelConfig struct {
 	Model         string `json:"model"`
 	FallbackModel string `json:"fallbackModel,omitempty"`
+
+	// Pool lists cheaper models the role may switch to once a thread has
+	// spent most of its budget.
+	Pool []string `json:"pool,omitempty"`
+}
+
+// UtilityModelConfig picks models for the high-frequency helper calls:
+// context compaction, thread summaries, and follow-up classification.
+// Pointing them at "ollama/<name>" keeps these calls free and offline while
+// coding roles still use cloud models. Empty fields keep each caller's
+// default.
+type UtilityModelConfig struct {
+	Compaction string `json:"compaction,omitempty"`
+	Summary    string `json:"summary,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
 }
 
 // ArtistModelConfig holds separate models for UX reasoning and image generation.
@@ -76,6 +383,25 @@ type MultiModel struct {
 type LimitsConfig struct {
 	MaxConcurrentThreads int `json:"maxConcurrentThreads,omitempty"`
 	MaxCallsPerHour      int `json:"maxCallsPerHour,omitempty"`
+
+	// PlanApprovalUSD requires explicit approval in chat before the Coder
+	// starts a plan estimated above this cost (0 = never ask).
+	PlanApprovalUSD float64 `json:"planApprovalUSD,omitempty"`
+
+	// BatchConfirmUSD asks the user to confirm a large incoming batch (many
+	// messages or big attachments) whose forecast cost is above this, before
+	// the agent is spawned (0 = never ask).
+	BatchConfirmUSD float64 `json:"batchConfirmUSD,omitempty"`
+
+	// ToolClasses assigns tools to concurrency classes ("heavy", "read",
+	// "default", or custom); ToolClassLimits caps parallel calls per class
+	// (0 = unlimited). Both are merged over the built-in defaults.
+	ToolClasses     map[string]string `json:"toolClasses,omitempty"`
+	ToolClassLimits map[string]int    `json:"toolClassLimits,omitempty"`
+
+	// PhaseTimeoutMinutes caps each role's phase, e.g. {"coder": 90}, over
+	// the defaults (pm 10, coder 60, reviewer 15); 0 removes a limit.
+	PhaseTimeoutMinutes map[string]int `json:"phaseTimeoutMinutes,omitempty"`
 }
 
 // Config is the fully merged configuration from global + per-repo sources.
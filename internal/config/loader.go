@@ -106,18 +106,11 @@ func resolveEnvVars(s string) string {
 func validate(cfg *Config) error {
 	var errs []string
 
-	if cfg.Global.Slack.BotToken == "" {
-		errs = append(errs, "global: slack.botToken is required")
+	for _, f := range missingFields(&cfg.Global, RequiredGlobal) {
+		errs = append(errs, "global: "+f+" is required")
 	}
-	if cfg.Global.Slack.AppToken == "" {
-		errs = append(errs, "global: slack.appToken is required")
-	}
-	if cfg.Global.OpenRouter.APIKey == "" {
-		errs = append(errs, "global: openrouter.apiKey is required")
-	}
-
-	if cfg.Repo.Slack.ChannelID == "" {
-		errs = append(errs, "repo: slack.channelID is required")
+	for _, f := range missingFields(&cfg.Repo, RequiredRepo) {
+		errs = append(errs, "repo: "+f+" is required")
 	}
 
 	if len(errs) > 0 {
@@ -132,3 +125,22 @@ func validate(cfg *Config) error {
 func RepoRoot(startDir string) (string, error) {
 	return findRepoRoot(startDir)
 }
+
+// LoadGlobal reads only the global config from globalDir without validating
+// it. Setup checks use it to report which fields are still missing.
+func LoadGlobal(globalDir string) (*GlobalConfig, error) {
+	var g GlobalConfig
+	if err := loadJSON(filepath.Join(globalDir, configFile), &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// LoadRepo reads only the per-repo config under repoRoot without validating it.
+func LoadRepo(repoRoot string) (*RepoConfig, error) {
+	var r RepoConfig
+	if err := loadJSON(filepath.Join(repoRoot, codebutlerDir, configFile), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
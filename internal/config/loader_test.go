@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -439,3 +440,34 @@ func searchString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestSecretValues(t *testing.T) {
+	g := &GlobalConfig{
+		Slack:      GlobalSlack{BotToken: "xoxb-1", AppToken: "xapp-1"},
+		OpenRouter: GlobalOpenRouter{APIKey: "sk-or-1"},
+		Sync:       &GlobalSync{Backend: "dir", Dir: "/mnt/state"},
+		Databases:  map[string]DatabaseConfig{"main": {DSN: "postgres://u:p@db/app"}},
+	}
+	got := strings.Join(g.SecretValues(), ",")
+	if got != "xoxb-1,xapp-1,sk-or-1,postgres://u:p@db/app" {
+		t.Errorf("SecretValues = %s", got)
+	}
+}
+
+func TestUtilityModels(t *testing.T) {
+	var m ModelsConfig
+	if got := m.CompactionModel("anthropic/claude-sonnet-4"); got != "anthropic/claude-sonnet-4" {
+		t.Errorf("unset CompactionModel = %q", got)
+	}
+
+	m.Utility = &UtilityModelConfig{Compaction: "ollama/llama3.1", Classifier: "ollama/qwen2.5"}
+	if got := m.CompactionModel("x"); got != "ollama/llama3.1" {
+		t.Errorf("CompactionModel = %q", got)
+	}
+	if got := m.ClassifierModel("x"); got != "ollama/qwen2.5" {
+		t.Errorf("ClassifierModel = %q", got)
+	}
+	if got := m.SummaryModel("cloud"); got != "cloud" {
+		t.Errorf("SummaryModel = %q, want fallback", got)
+	}
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config generations older than the current two-file layout:
+//
+//   - v1: a single config.json at the repo root, from the WhatsApp daemon
+//     that drove the claude CLI ({"whatsapp": ..., "claude": ..., "openai": ...}).
+//   - ButlerAgent: .butleragent/config.json, one file holding both the
+//     secrets and the repo settings.
+const (
+	legacyV1File          = "config.json"
+	legacyButlerAgentDir  = ".butleragent"
+	legacyBackupExtension = ".legacy.bak"
+)
+
+// MigrationReport describes a legacy config conversion.
+type MigrationReport struct {
+	Source   string   // legacy file that was converted
+	Backup   string   // where the original was moved
+	Repo     string   // repo config written
+	Global   string   // global config written or updated
+	Changes  []string // what moved where
+	Warnings []string // settings that need a manual follow-up
+}
+
+// String renders the report for the terminal.
+func (r *MigrationReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Migrated legacy config %s\n", r.Source)
+	fmt.Fprintf(&b, "  original backed up to %s\n", r.Backup)
+	for _, c := range r.Changes {
+		fmt.Fprintf(&b, "  - %s\n", c)
+	}
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&b, "  ! %s\n", w)
+	}
+	return b.String()
+}
+
+// MigrateLegacy looks for a legacy config from startDir up to the git root
+// and, when the repo has no .codebutler/config.json yet, converts it to
+// the current layout: secrets merged into the global config (existing
+// values win), everything else into a new repo config. The original is
+// moved aside to <file>.legacy.bak so it is not migrated twice. It returns
+// nil when there is nothing to migrate. globalDir overrides
+// ~/.codebutler/ as in Load.
+func MigrateLegacy(startDir, globalDir string) (*MigrationReport, error) {
+	source, root, kind, err := findLegacy(startDir)
+	if err != nil || source == "" {
+		return nil, err
+	}
+	repoPath := filepath.Join(root, codebutlerDir, configFile)
+	if _, err := os.Stat(repoPath); err == nil {
+		return nil, nil // already on the current layout
+	}
+
+	if globalDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("get home directory: %w", err)
+		}
+		globalDir = filepath.Join(home, codebutlerDir)
+	}
+	globalPath := filepath.Join(globalDir, configFile)
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("read legacy config: %w", err)
+	}
+	var global GlobalConfig
+	if err := readRaw(globalPath, &global); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read global config %s: %w", globalPath, err)
+	}
+
+	report := &MigrationReport{Source: source, Backup: source + legacyBackupExtension, Repo: repoPath, Global: globalPath}
+	var repo RepoConfig
+	switch kind {
+	case "v1":
+		err = convertV1(data, &global, &repo, report)
+	default:
+		err = convertButlerAgent(data, &global, &repo, report)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("convert %s: %w", source, err)
+	}
+
+	if err := writeJSON(globalPath, &global, 0o600); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(repoPath, &repo, 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(source, report.Backup); err != nil {
+		return nil, fmt.Errorf("back up legacy config: %w", err)
+	}
+	return report, nil
+}
+
+// findLegacy walks up from startDir to the git root (or filesystem root)
+// looking for a legacy config. A root config.json only counts when it has
+// v1 keys, since plenty of projects have an unrelated config.json.
+func findLegacy(startDir string) (path, root, kind string, err error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", "", "", fmt.Errorf("resolve path: %w", err)
+	}
+	for {
+		p := filepath.Join(dir, legacyButlerAgentDir, configFile)
+		if _, err := os.Stat(p); err == nil {
+			return p, dir, "butleragent", nil
+		}
+		p = filepath.Join(dir, legacyV1File)
+		if isV1(p) {
+			return p, dir, "v1", nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", "", "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", "", nil
+		}
+		dir = parent
+	}
+}
+
+func isV1(path string) bool {
+	var probe map[string]json.RawMessage
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &probe) != nil {
+		return false
+	}
+	_, hasWhatsApp := probe["whatsapp"]
+	_, hasClaude := probe["claude"]
+	return hasWhatsApp || hasClaude
+}
+
+// legacyV1 is the WhatsApp daemon's root config.json.
+type legacyV1 struct {
+	WhatsApp struct {
+		GroupJID  string `json:"groupJID"`
+		GroupName string `json:"groupName"`
+	} `json:"whatsapp"`
+	Claude struct {
+		Model    string `json:"model"`
+		MaxTurns int    `json:"maxTurns"`
+	} `json:"claude"`
+	OpenAI struct {
+		APIKey string `json:"apiKey"`
+	} `json:"openai"`
+}
+
+func convertV1(data []byte, global *GlobalConfig, repo *RepoConfig, r *MigrationReport) error {
+	var v1 legacyV1
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return fmt.Errorf("parse JSON: %w", err)
+	}
+	if v1.OpenAI.APIKey != "" {
+		setSecret(&global.OpenAI.APIKey, v1.OpenAI.APIKey, "openai.apiKey", r)
+	}
+	if m := v1.Claude.Model; m != "" {
+		if !strings.Contains(m, "/") {
+			m = "anthropic/" + m
+		}
+		repo.Models.Coder = &AgentModelConfig{Model: m}
+		r.Changes = append(r.Changes, fmt.Sprintf("claude.model → models.coder.model (%s)", m))
+	}
+	if v1.Claude.MaxTurns > 0 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("claude.maxTurns (%d) has no equivalent; the native agent loop manages its own turns", v1.Claude.MaxTurns))
+	}
+	if v1.WhatsApp.GroupJID != "" || v1.WhatsApp.GroupName != "" {
+		repo.Slack.ChannelName = slugChannel(v1.WhatsApp.GroupName)
+		r.Warnings = append(r.Warnings, fmt.Sprintf("WhatsApp group %q has no Slack equivalent; set slack.channelID in %s", v1.WhatsApp.GroupName, r.Repo))
+	}
+	r.Warnings = append(r.Warnings, "add slack.botToken, slack.appToken and openrouter.apiKey to "+r.Global)
+	return nil
+}
+
+func slugChannel(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+			return c
+		case c == ' ':
+			return '-'
+		}
+		return -1
+	}, name)
+}
+
+// legacyButlerAgent is ButlerAgent's single combined file: the current
+// repo settings plus the secrets.
+type legacyButlerAgent struct {
+	RepoConfig
+	Slack struct {
+		RepoSlack
+		BotToken string `json:"botToken"`
+		AppToken string `json:"appToken"`
+	} `json:"slack"`
+	OpenRouter GlobalOpenRouter `json:"openrouter"`
+	OpenAI     GlobalOpenAI     `json:"openai"`
+}
+
+func convertButlerAgent(data []byte, global *GlobalConfig, repo *RepoConfig, r *MigrationReport) error {
+	var ba legacyButlerAgent
+	if err := json.Unmarshal(data, &ba); err != nil {
+		return fmt.Errorf("parse JSON: %w", err)
+	}
+	*repo = ba.RepoConfig
+	repo.Slack = ba.Slack.RepoSlack
+	r.Changes = append(r.Changes, "repo settings (slack channel, models, limits, ...) → "+r.Repo)
+
+	setSecret(&global.Slack.BotToken, ba.Slack.BotToken, "slack.botToken", r)
+	setSecret(&global.Slack.AppToken, ba.Slack.AppToken, "slack.appToken", r)
+	setSecret(&global.OpenRouter.APIKey, ba.OpenRouter.APIKey, "openrouter.apiKey", r)
+	setSecret(&global.OpenAI.APIKey, ba.OpenAI.APIKey, "openai.apiKey", r)
+	r.Warnings = append(r.Warnings, "other files in "+filepath.Dir(r.Source)+" (agent MDs, skills) were left in place; move them to .codebutler/ if still needed")
+	return nil
+}
+
+// setSecret moves a secret to the global config unless it already has one.
+func setSecret(dst *string, v, field string, r *MigrationReport) {
+	switch {
+	case v == "":
+	case *dst == "":
+		*dst = v
+		r.Changes = append(r.Changes, field+" → "+r.Global)
+	case *dst != v:
+		r.Warnings = append(r.Warnings, field+" differs from the one already in "+r.Global+"; kept the existing value")
+	}
+}
+
+// readRaw decodes a config file without resolving ${VAR} references, so
+// rewriting it keeps them intact.
+func readRaw(path string, dest any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func writeJSON(path string, v any, perm os.FileMode) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), perm); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
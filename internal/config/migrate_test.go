@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateLegacy_ButlerAgent(t *testing.T) {
+	repo, global := t.TempDir(), t.TempDir()
+	os.Mkdir(filepath.Join(repo, ".git"), 0o755)
+	writeFile(t, filepath.Join(global, "config.json"), `{"slack":{"botToken":"xoxb-existing"},"openai":{"apiKey":"${OPENAI_KEY}"}}`)
+	writeFile(t, filepath.Join(repo, ".butleragent", "config.json"), `{
+		"slack": {"botToken": "xoxb-old", "appToken": "xapp-1", "channelID": "C123", "channelName": "myproject"},
+		"openrouter": {"apiKey": "sk-or-1"},
+		"models": {"coder": {"model": "anthropic/claude-opus-4-6"}},
+		"limits": {"maxConcurrentThreads": 2}
+	}`)
+
+	report, err := MigrateLegacy(repo, global)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report == nil {
+		t.Fatal("expected a migration")
+	}
+
+	cfg, err := Load(repo, global)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Repo.Slack.ChannelID != "C123" || cfg.Repo.Models.Coder.Model != "anthropic/claude-opus-4-6" || cfg.Repo.Limits.MaxConcurrentThreads != 2 {
+		t.Errorf("repo = %+v", cfg.Repo)
+	}
+	if cfg.Global.Slack.BotToken != "xoxb-existing" || cfg.Global.Slack.AppToken != "xapp-1" || cfg.Global.OpenRouter.APIKey != "sk-or-1" {
+		t.Errorf("global = %+v", cfg.Global)
+	}
+	raw, _ := os.ReadFile(filepath.Join(global, "config.json"))
+	if !strings.Contains(string(raw), "${OPENAI_KEY}") {
+		t.Error("env references in the global config should survive")
+	}
+	if !strings.Contains(report.String(), "slack.botToken differs") {
+		t.Errorf("report = %s", report)
+	}
+	if _, err := os.Stat(report.Backup); err != nil {
+		t.Errorf("backup missing: %v", err)
+	}
+
+	if again, err := MigrateLegacy(repo, global); err != nil || again != nil {
+		t.Errorf("second run should do nothing: %v, %v", again, err)
+	}
+}
+
+func TestMigrateLegacy_V1(t *testing.T) {
+	repo, global := t.TempDir(), t.TempDir()
+	os.Mkdir(filepath.Join(repo, ".git"), 0o755)
+	writeFile(t, filepath.Join(repo, "config.json"), `{
+		"whatsapp": {"groupJID": "123@g.us", "groupName": "My App"},
+		"claude": {"model": "claude-sonnet-4-5-20250929", "maxTurns": 5},
+		"openai": {"apiKey": "sk-1"}
+	}`)
+
+	report, err := MigrateLegacy(repo, global)
+	if err != nil || report == nil {
+		t.Fatalf("report = %v, err = %v", report, err)
+	}
+	r, err := LoadRepo(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Models.Coder.Model != "anthropic/claude-sonnet-4-5-20250929" || r.Slack.ChannelName != "my-app" {
+		t.Errorf("repo = %+v", r)
+	}
+	g, _ := LoadGlobal(global)
+	if g.OpenAI.APIKey != "sk-1" {
+		t.Errorf("global = %+v", g)
+	}
+	info, _ := os.Stat(filepath.Join(global, "config.json"))
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("global config perm = %v", info.Mode().Perm())
+	}
+	out := report.String()
+	for _, want := range []string{"WhatsApp group", "maxTurns", "openrouter.apiKey"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMigrateLegacy_IgnoresUnrelatedConfig(t *testing.T) {
+	repo, global := t.TempDir(), t.TempDir()
+	os.Mkdir(filepath.Join(repo, ".git"), 0o755)
+	writeFile(t, filepath.Join(repo, "config.json"), `{"port": 8080}`)
+
+	if report, err := MigrateLegacy(repo, global); err != nil || report != nil {
+		t.Errorf("report = %v, err = %v", report, err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "config.json")); err != nil {
+		t.Error("unrelated config.json must be left alone")
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+// CompactionModel returns the model that summarizes long conversations, or
+// fallback when none is configured.
+func (m ModelsConfig) CompactionModel(fallback string) string {
+	if m.Utility == nil {
+		return fallback
+	}
+	return orDefault(m.Utility.Compaction, fallback)
+}
+
+// SummaryModel returns the model for thread summaries (/tldr), or fallback.
+func (m ModelsConfig) SummaryModel(fallback string) string {
+	if m.Utility == nil {
+		return fallback
+	}
+	return orDefault(m.Utility.Summary, fallback)
+}
+
+// ClassifierModel returns the model that classifies incoming messages
+// (follow-up detection), or fallback.
+func (m ModelsConfig) ClassifierModel(fallback string) string {
+	if m.Utility == nil {
+		return fallback
+	}
+	return orDefault(m.Utility.Classifier, fallback)
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// schemaDialect is the JSON Schema draft the generated schemas declare.
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// RepoSchema returns a JSON Schema for .codebutler/config.json, so editors
+// can complete and check it. Point the file at it with a "$schema" key.
+func RepoSchema() ([]byte, error) {
+	return schemaDoc("CodeButler repo config", reflect.TypeFor[RepoConfig](), RequiredRepo)
+}
+
+// GlobalSchema returns a JSON Schema for ~/.codebutler/config.json.
+func GlobalSchema() ([]byte, error) {
+	return schemaDoc("CodeButler global config", reflect.TypeFor[GlobalConfig](), RequiredGlobal)
+}
+
+func schemaDoc(title string, t reflect.Type, required []string) ([]byte, error) {
+	// Parent path → required child names; "" is the root.
+	req := map[string][]string{}
+	for _, p := range required {
+		parts := strings.Split(p, ".")
+		for i := range parts {
+			parent := strings.Join(parts[:i], ".")
+			if !slices.Contains(req[parent], parts[i]) {
+				req[parent] = append(req[parent], parts[i])
+			}
+		}
+	}
+
+	s := typeSchema(t, req, "")
+	s["$schema"] = schemaDialect
+	s["title"] = title
+	s["properties"].(map[string]any)[schemaKey] = map[string]any{
+		"type":        "string",
+		"description": "JSON Schema for editor completion; ignored by CodeButler.",
+	}
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// typeSchema describes t. Structs are closed (additionalProperties
+// false), so a misspelled key is flagged in the editor just as
+// CheckGlobal and CheckRepo flag it.
+func typeSchema(t reflect.Type, req map[string][]string, path string) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]any{}
+		for name, f := range jsonFields(t) {
+			props[name] = typeSchema(f.Type, req, join(path, name))
+		}
+		s := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+		if r := req[path]; len(r) > 0 {
+			s["required"] = r
+		}
+		return s
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), req, join(path, "*"))}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), req, join(path, "*"))}
+	default:
+		return map[string]any{"type": jsonType(t)}
+	}
+}
+
+// jsonType names the JSON type a Go type decodes from.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "string"
+	}
+}
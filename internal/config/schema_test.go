@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRepoSchema(t *testing.T) {
+	data, err := RepoSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s map[string]any
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s["$schema"] != schemaDialect || s["additionalProperties"] != false {
+		t.Errorf("root = %v", s)
+	}
+	props := s["properties"].(map[string]any)
+	for _, key := range []string{"slack", "models", "limits", "toolProfiles", "$schema"} {
+		if _, ok := props[key]; !ok {
+			t.Errorf("missing property %q", key)
+		}
+	}
+	if req := s["required"].([]any); len(req) != 1 || req[0] != "slack" {
+		t.Errorf("required = %v", req)
+	}
+	slack := props["slack"].(map[string]any)
+	if req := slack["required"].([]any); len(req) != 1 || req[0] != "channelID" {
+		t.Errorf("slack.required = %v", req)
+	}
+
+	limits := props["limits"].(map[string]any)["properties"].(map[string]any)
+	if typ := limits["batchConfirmUSD"].(map[string]any)["type"]; typ != "number" {
+		t.Errorf("batchConfirmUSD type = %v", typ)
+	}
+	classLimits := limits["toolClassLimits"].(map[string]any)
+	if classLimits["type"] != "object" || classLimits["additionalProperties"].(map[string]any)["type"] != "integer" {
+		t.Errorf("toolClassLimits = %v", classLimits)
+	}
+	schedules := props["schedules"].(map[string]any)
+	if schedules["type"] != "array" || schedules["items"].(map[string]any)["type"] != "object" {
+		t.Errorf("schedules = %v", schedules)
+	}
+}
+
+func TestGlobalSchema(t *testing.T) {
+	data, err := GlobalSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s map[string]any
+	json.Unmarshal(data, &s)
+	if req := s["required"].([]any); len(req) != 2 {
+		t.Errorf("required = %v, want slack and openrouter", req)
+	}
+	store := s["properties"].(map[string]any)["store"].(map[string]any)
+	if store["type"] != "object" || store["properties"].(map[string]any)["dsn"] == nil {
+		t.Errorf("store = %v", store)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+// SecretValues returns every credential set in the global config: tokens,
+// API keys, passwords and DSNs. The redaction layer masks these wherever
+// they appear, in addition to generic secret patterns.
+func (g *GlobalConfig) SecretValues() []string {
+	vals := []string{g.Slack.BotToken, g.Slack.AppToken, g.OpenRouter.APIKey, g.OpenAI.APIKey}
+	if g.ElevenLabs != nil {
+		vals = append(vals, g.ElevenLabs.APIKey)
+	}
+	if p := g.Alerts.Pushover; p != nil {
+		vals = append(vals, p.Token, p.User)
+	}
+	if e := g.Alerts.Email; e != nil {
+		vals = append(vals, e.Password)
+	}
+	if n := g.Alerts.Ntfy; n != nil {
+		vals = append(vals, n.Token)
+	}
+	if s := g.Sync; s != nil {
+		vals = append(vals, s.Password, s.SecretAccessKey)
+	}
+	if o := g.Observability; o != nil {
+		if o.Loki != nil {
+			vals = append(vals, o.Loki.Password)
+		}
+		if o.Prometheus != nil {
+			vals = append(vals, o.Prometheus.Password)
+		}
+		if o.CloudWatch != nil {
+			vals = append(vals, o.CloudWatch.SecretAccessKey)
+		}
+		if o.Datadog != nil {
+			vals = append(vals, o.Datadog.APIKey, o.Datadog.AppKey)
+		}
+	}
+	if t := g.Tracing; t != nil {
+		for _, v := range t.Headers {
+			vals = append(vals, v)
+		}
+	}
+	for _, db := range g.Databases {
+		vals = append(vals, db.DSN)
+	}
+
+	out := vals[:0]
+	for _, v := range vals {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
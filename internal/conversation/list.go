@@ -0,0 +1,81 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Summary describes one stored conversation file.
+type Summary struct {
+	Branch    string    `json:"branch"`
+	Role      string    `json:"role"`
+	Sender    string    `json:"sender,omitempty"` // set for per-sender sessions
+	Messages  int       `json:"messages"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// List returns every conversation stored under baseDir, most recently
+// updated first. Branch names may contain slashes ("codebutler/fix-x"), so
+// the branches tree is walked rather than globbed. Files that cannot be
+// parsed are skipped. Pass WithCipher to count messages in sealed files.
+func List(baseDir string, opts ...Option) ([]Summary, error) {
+	cfg := NewFileStore("", opts...)
+	root := filepath.Join(baseDir, ".codebutler", "branches")
+	var paths []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(p) == ".json" && filepath.Base(filepath.Dir(p)) == "conversations" {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list conversations: %w", err)
+	}
+
+	var out []Summary
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		if cfg.cipher != nil {
+			if data, err = cfg.cipher.Open(data); err != nil {
+				continue
+			}
+		}
+		var messages []json.RawMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			continue
+		}
+		branch, err := filepath.Rel(root, filepath.Dir(filepath.Dir(p)))
+		if err != nil {
+			continue
+		}
+		role, sender, _ := strings.Cut(strings.TrimSuffix(filepath.Base(p), ".json"), ".")
+		out = append(out, Summary{
+			Branch:    filepath.ToSlash(branch),
+			Role:      role,
+			Sender:    sender,
+			Messages:  len(messages),
+			UpdatedAt: info.ModTime(),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
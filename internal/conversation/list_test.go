@@ -0,0 +1,34 @@
+package conversation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	if list, err := List(dir); err != nil || len(list) != 0 {
+		t.Fatalf("empty repo: %v, %v", list, err)
+	}
+
+	ctx := context.Background()
+	NewFileStore(FilePath(dir, "codebutler/fix-login", "coder")).Save(ctx, []agent.Message{{Role: "user", Content: "a"}, {Role: "assistant", Content: "b"}})
+	NewFileStore(ScopeSender.FilePath(dir, "main", "pm", "U1")).Save(ctx, []agent.Message{{Role: "user", Content: "c"}})
+
+	list, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]Summary{}
+	for _, s := range list {
+		got[s.Branch+"/"+s.Role] = s
+	}
+	if s, ok := got["codebutler/fix-login/coder"]; !ok || s.Messages != 2 {
+		t.Errorf("slashed branch missing: %+v", list)
+	}
+	if s, ok := got["main/pm"]; !ok || s.Sender != "U1" {
+		t.Errorf("per-sender session missing: %+v", list)
+	}
+}
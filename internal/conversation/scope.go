@@ -0,0 +1,64 @@
+package conversation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Scope decides which messages share a conversation.
+type Scope string
+
+const (
+	// ScopeThread shares one conversation per thread: everyone in it talks
+	// to the same session. This is the default.
+	ScopeThread Scope = "thread"
+	// ScopeSender gives each sender in a thread their own session, so two
+	// people's parallel, unrelated requests don't leak into each other's
+	// context.
+	ScopeSender Scope = "sender"
+)
+
+// ParseScope validates a configured scope. Empty means ScopeThread.
+func ParseScope(s string) (Scope, error) {
+	switch Scope(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ScopeThread:
+		return ScopeThread, nil
+	case ScopeSender:
+		return ScopeSender, nil
+	}
+	return "", fmt.Errorf("unknown session scope %q (want %q or %q)", s, ScopeThread, ScopeSender)
+}
+
+// Key returns the session key for a message: the thread alone, or the
+// thread plus sender under ScopeSender.
+func (s Scope) Key(channel, thread, sender string) string {
+	key := channel + "/" + thread
+	if s == ScopeSender && sender != "" {
+		key += "/" + sender
+	}
+	return key
+}
+
+// FilePath returns the conversation file for role under this scope. With
+// ScopeSender each sender gets a sibling file:
+//
+//	.codebutler/branches/<branch>/conversations/<role>.<sender>.json
+func (s Scope) FilePath(baseDir, branch, role, sender string) string {
+	if s != ScopeSender || sender == "" {
+		return FilePath(baseDir, branch, role)
+	}
+	return FilePath(baseDir, branch, role+"."+sanitizeSender(sender))
+}
+
+// sanitizeSender keeps sender IDs safe as file name components.
+func sanitizeSender(id string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, id)
+	return filepath.Base(clean)
+}
@@ -0,0 +1,45 @@
+package conversation
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseScope(t *testing.T) {
+	for in, want := range map[string]Scope{"": ScopeThread, "thread": ScopeThread, " Sender ": ScopeSender} {
+		got, err := ParseScope(in)
+		if err != nil || got != want {
+			t.Errorf("ParseScope(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseScope("chat"); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
+func TestScope_Key(t *testing.T) {
+	if got := ScopeThread.Key("C1", "T1", "U1"); got != "C1/T1" {
+		t.Errorf("thread key = %q", got)
+	}
+	if a, b := ScopeSender.Key("C1", "T1", "U1"), ScopeSender.Key("C1", "T1", "U2"); a == b {
+		t.Errorf("senders should not share a session: %q", a)
+	}
+}
+
+func TestScope_FilePath(t *testing.T) {
+	shared := filepath.Join("repo", ".codebutler", "branches", "b", "conversations", "pm.json")
+	if got := ScopeThread.FilePath("repo", "b", "pm", "U1"); got != shared {
+		t.Errorf("got %q", got)
+	}
+	if got := ScopeSender.FilePath("repo", "b", "pm", ""); got != shared {
+		t.Errorf("no sender should use the shared file, got %q", got)
+	}
+
+	want := filepath.Join("repo", ".codebutler", "branches", "b", "conversations", "pm.U1.json")
+	if got := ScopeSender.FilePath("repo", "b", "pm", "U1"); got != want {
+		t.Errorf("got %q", got)
+	}
+	if got := ScopeSender.FilePath("repo", "b", "pm", "../x/y"); filepath.Dir(got) != filepath.Dir(want) {
+		t.Errorf("sender must not escape the conversations dir: %q", got)
+	}
+}
@@ -26,9 +26,17 @@ import (
 // never corrupts the existing conversation file.
 type FileStore struct {
 	path   string
+	cipher Cipher
 	logger *slog.Logger
 }
 
+// Cipher seals conversation files at rest. Open must pass unsealed data
+// through, so files written before encryption was enabled still load.
+type Cipher interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(data []byte) ([]byte, error)
+}
+
 // Option configures a FileStore.
 type Option func(*FileStore)
 
@@ -39,6 +47,14 @@ func WithLogger(l *slog.Logger) Option {
 	}
 }
 
+// WithCipher encrypts the file on save and decrypts it on load. Sealed
+// files are written owner-only.
+func WithCipher(c Cipher) Option {
+	return func(s *FileStore) {
+		s.cipher = c
+	}
+}
+
 // NewFileStore creates a store that persists conversations at the given file path.
 // The path should be the full path to the JSON file, e.g.:
 //
@@ -74,6 +90,11 @@ func (s *FileStore) Load(_ context.Context) ([]agent.Message, error) {
 	if len(data) == 0 {
 		return nil, nil
 	}
+	if s.cipher != nil {
+		if data, err = s.cipher.Open(data); err != nil {
+			return nil, fmt.Errorf("decrypt conversation file: %w", err)
+		}
+	}
 
 	var messages []agent.Message
 	if err := json.Unmarshal(data, &messages); err != nil {
@@ -104,8 +125,16 @@ func (s *FileStore) Save(_ context.Context, messages []agent.Message) error {
 		return fmt.Errorf("marshal conversation: %w", err)
 	}
 
+	perm := os.FileMode(0o644)
+	if s.cipher != nil {
+		if data, err = s.cipher.Seal(data); err != nil {
+			return fmt.Errorf("encrypt conversation: %w", err)
+		}
+		perm = 0o600
+	}
+
 	tmp := s.path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	if err := os.WriteFile(tmp, data, perm); err != nil {
 		return fmt.Errorf("write temp conversation file: %w", err)
 	}
 	if err := os.Rename(tmp, s.path); err != nil {
@@ -399,3 +399,63 @@ func TestFileStore_ResumeMidConversation(t *testing.T) {
 		t.Errorf("expected tool_call_id %q, got %q", "call-1", last.ToolCallID)
 	}
 }
+
+// reverseCipher is a reversible stand-in that marks sealed data with "~".
+type reverseCipher struct{}
+
+func (reverseCipher) Seal(p []byte) ([]byte, error) {
+	out := []byte{'~'}
+	for i := len(p) - 1; i >= 0; i-- {
+		out = append(out, p[i])
+	}
+	return out, nil
+}
+
+func (reverseCipher) Open(d []byte) ([]byte, error) {
+	if len(d) == 0 || d[0] != '~' {
+		return d, nil
+	}
+	d = d[1:]
+	out := make([]byte, 0, len(d))
+	for i := len(d) - 1; i >= 0; i-- {
+		out = append(out, d[i])
+	}
+	return out, nil
+}
+
+func TestFileStore_WithCipher(t *testing.T) {
+	dir := t.TempDir()
+	path := FilePath(dir, "codebutler/fix", "coder")
+	ctx := context.Background()
+
+	// A plain file from before encryption still loads.
+	if err := NewFileStore(path).Save(ctx, []agent.Message{{Role: "user", Content: "old"}}); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStore(path, WithCipher(reverseCipher{}))
+	if got, err := store.Load(ctx); err != nil || len(got) != 1 || got[0].Content != "old" {
+		t.Fatalf("legacy load = %v, %v", got, err)
+	}
+
+	msgs := []agent.Message{{Role: "user", Content: "secret plan"}, {Role: "assistant", Content: "ok"}}
+	if err := store.Save(ctx, msgs); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(path)
+	if raw[0] != '~' {
+		t.Errorf("file not sealed: %s", raw)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	if got, err := store.Load(ctx); err != nil || len(got) != 2 || got[0].Content != "secret plan" {
+		t.Errorf("load = %v, %v", got, err)
+	}
+
+	if list, _ := List(dir); len(list) != 0 {
+		t.Errorf("List without cipher = %v, want sealed file skipped", list)
+	}
+	if list, _ := List(dir, WithCipher(reverseCipher{})); len(list) != 1 || list[0].Messages != 2 {
+		t.Errorf("List with cipher = %+v", list)
+	}
+}
@@ -0,0 +1,371 @@
+package demo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/budget"
+	"github.com/leandrotocalini/codebutler/internal/buildinfo"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+	"github.com/leandrotocalini/codebutler/internal/reports"
+	"github.com/leandrotocalini/codebutler/internal/tldr"
+)
+
+const (
+	demoChannel = "demo"
+	demoUser    = "you"
+
+	// DefaultBatchWindow is how long the session waits for more lines
+	// before treating what was typed as one message.
+	DefaultBatchWindow = 1500 * time.Millisecond
+)
+
+const systemPrompt = "You are the PM agent of CodeButler, running in demo mode from a terminal. " +
+	"No tools are available: explain briefly how you would handle each request."
+
+// Session is a simulated chat thread between the terminal user and the PM.
+type Session struct {
+	provider agent.LLMProvider
+	model    string
+	offline  bool
+	window   time.Duration
+	footer   bool
+	out      io.Writer
+	logger   *slog.Logger
+	catalog  *messages.Catalog
+	splitter *agent.BatchSplitter
+	followUp *agent.FollowUpDetector
+	reports  *reports.Store // optional; nil keeps no usage reports
+	commands *chatcmd.Registry
+
+	mu        sync.Mutex // serializes writes to out
+	thread    int
+	history   []agent.Message
+	tokens    int
+	lastReply time.Time
+	usage     agent.Result // PM totals for the current thread's report
+}
+
+// Option configures a Session.
+type Option func(*Session)
+
+// WithProvider uses a real model instead of the scripted provider.
+func WithProvider(p agent.LLMProvider, model string) Option {
+	return func(s *Session) {
+		s.provider = p
+		s.model = model
+		s.offline = false
+	}
+}
+
+// WithBatchWindow sets how long to wait for follow-up lines before sending.
+func WithBatchWindow(d time.Duration) Option {
+	return func(s *Session) {
+		s.window = d
+	}
+}
+
+// WithCostFooter appends a turns/tokens/cost/model footer to each reply.
+func WithCostFooter(on bool) Option {
+	return func(s *Session) {
+		s.footer = on
+	}
+}
+
+// WithBatchSplitter sets how a batch of lines is split into independent
+// tasks. By default the live provider groups them (keywords when offline).
+func WithBatchSplitter(bs *agent.BatchSplitter) Option {
+	return func(s *Session) {
+		s.splitter = bs
+	}
+}
+
+// WithFollowUpDetector sets how the session decides whether a message
+// continues the thread or starts a new one. The default uses topic overlap
+// and the reply window without calling a model.
+func WithFollowUpDetector(d *agent.FollowUpDetector) Option {
+	return func(s *Session) {
+		s.followUp = d
+	}
+}
+
+// WithReportStore saves a usage report for each thread and enables /report.
+func WithReportStore(store *reports.Store) Option {
+	return func(s *Session) {
+		s.reports = store
+	}
+}
+
+// WithLogger sets the logger passed to the agent runner.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Session) {
+		s.logger = l
+	}
+}
+
+// WithCatalog sets the message catalog used for terminal strings.
+func WithCatalog(c *messages.Catalog) Option {
+	return func(s *Session) {
+		s.catalog = c
+	}
+}
+
+// New creates a session that writes replies to out.
+func New(out io.Writer, opts ...Option) *Session {
+	s := &Session{
+		provider: NewScriptedProvider(),
+		model:    agent.DefaultPMConfig().Model,
+		offline:  true,
+		window:   DefaultBatchWindow,
+		out:      out,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		catalog:  messages.New(messages.DefaultLocale),
+		thread:   1,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.splitter == nil {
+		s.splitter = agent.NewBatchSplitter(s.liveProvider(), s.model, s.logger)
+	}
+	if s.followUp == nil {
+		s.followUp = agent.NewFollowUpDetector(nil, "", agent.DefaultReplyWindow, s.logger)
+	}
+
+	s.commands = chatcmd.NewRegistry()
+	s.commands.Register(chatcmd.HelpCommand(s.commands))
+	s.commands.Register(&chatcmd.Command{
+		Name:        "new",
+		Description: "Start a new thread (fresh session)",
+		Run: func(context.Context, chatcmd.Invocation) (string, error) {
+			s.thread++
+			s.history = nil
+			return fmt.Sprintf("Started thread %d.", s.thread), nil
+		},
+	})
+	s.commands.Register(&chatcmd.Command{
+		Name:        "status",
+		Description: "Show the session state",
+		Run: func(context.Context, chatcmd.Invocation) (string, error) {
+			mode := "offline (scripted provider)"
+			if !s.offline {
+				mode = "live (" + s.model + ")"
+			}
+			return fmt.Sprintf("thread %d · %d messages · %d tokens · %s · %s", s.thread, len(s.history), s.tokens, mode, buildinfo.Get()), nil
+		},
+	})
+	if s.reports != nil {
+		s.commands.Register(reports.Command(s.reports))
+	}
+	s.commands.Register(tldr.Command(
+		tldr.NewSummarizer(s.liveProvider(), s.model, tldr.WithLogger(s.logger)),
+		tldr.TranscriptFunc(func(context.Context, string, string) ([]agent.Message, error) {
+			return s.history, nil
+		}),
+	))
+	s.commands.Register(&chatcmd.Command{
+		Name:        "footer",
+		Usage:       "/footer on|off",
+		Description: "Toggle the cost footer on replies",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			switch inv.RawArgs {
+			case "on":
+				s.footer = true
+			case "off":
+				s.footer = false
+			default:
+				return "", fmt.Errorf("usage: /footer on|off")
+			}
+			return "Cost footer " + inv.RawArgs + ".", nil
+		},
+	})
+	return s
+}
+
+// liveProvider returns the model provider, or nil in offline mode so helper
+// features (batch splitting, /tldr) fall back to their keyword versions
+// instead of asking the scripted provider.
+func (s *Session) liveProvider() agent.LLMProvider {
+	if s.offline {
+		return nil
+	}
+	return s.provider
+}
+
+// Commands exposes the registry so callers can add more slash commands.
+func (s *Session) Commands() *chatcmd.Registry {
+	return s.commands
+}
+
+// Run reads lines from in until EOF or ctx is done. Lines typed within the
+// batch window form a batch, which is split into independent tasks that run
+// one after another; slash commands run immediately.
+func (s *Session) Run(ctx context.Context, in io.Reader) error {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(in)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s.printf("%s\n", s.catalog.Render(messages.CLIDemoBanner, nil))
+
+	var batch []string
+	var timer <-chan time.Time
+	flush := func() {
+		if len(batch) > 0 {
+			s.handleBatch(ctx, batch)
+			batch = nil
+		}
+		timer = nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer:
+			flush()
+		case line, ok := <-lines:
+			if !ok {
+				flush()
+				return nil
+			}
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if _, _, isCmd := chatcmd.Parse(line); isCmd {
+				flush()
+				s.handleCommand(ctx, line)
+				continue
+			}
+			batch = append(batch, line)
+			timer = time.After(s.window)
+		}
+	}
+}
+
+func (s *Session) handleCommand(ctx context.Context, line string) {
+	reply, handled, _ := s.commands.Handle(ctx, demoChannel, s.threadID(), demoUser, line)
+	if !handled {
+		reply = s.catalog.Render(messages.CLIUnknownCommand, nil)
+	}
+	if reply != "" {
+		s.printf("codebutler> %s\n", reply)
+	}
+}
+
+func (s *Session) handleBatch(ctx context.Context, lines []string) {
+	tasks := s.splitter.Split(ctx, lines)
+	if len(tasks) > 1 {
+		s.printf("(split %d lines into %d tasks)\n", len(lines), len(tasks))
+	} else if len(lines) > 1 {
+		s.printf("(batched %d lines into one message)\n", len(lines))
+	}
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			return
+		}
+		s.handleMessage(ctx, t.Text())
+	}
+}
+
+func (s *Session) handleMessage(ctx context.Context, text string) {
+	if len(s.history) > 0 && !s.followUp.IsFollowUp(ctx, s.history, text, time.Since(s.lastReply)) {
+		s.thread++
+		s.history = nil
+		s.usage = agent.Result{}
+		s.printf("(new topic, started thread %d)\n", s.thread)
+	}
+	s.history = append(s.history, agent.Message{Role: "user", Content: text})
+
+	cfg := agent.DefaultPMConfig()
+	cfg.Model = s.model
+	cfg.MaxTurns = 3
+	pm := agent.NewPMRunner(s.provider, s, noTools{}, cfg, systemPrompt, agent.WithPMLogger(s.logger))
+
+	res, intent, err := pm.ClassifyAndRun(ctx, agent.Task{
+		Messages: s.history,
+		Channel:  demoChannel,
+		Thread:   s.threadID(),
+	})
+	if err != nil {
+		s.printf("codebutler> error: %v\n", err)
+		return
+	}
+
+	s.tokens += res.TokenUsage.TotalTokens
+	s.saveReport(res)
+	label := string(intent.Type)
+	if intent.Name != "" {
+		label += ":" + intent.Name
+	}
+	reply := res.Response
+	if s.footer {
+		reply += "\n" + budget.FormatFooter(s.model, res.TurnsUsed, budget.TokenUsage(res.TokenUsage)) +
+			budget.DowngradeNote(s.model, res.Model)
+	}
+	s.printf("codebutler> %s\n           [intent %s]\n", reply, label)
+	if res.Response != "" {
+		s.history = append(s.history, agent.Message{Role: "assistant", Content: res.Response})
+	}
+	s.lastReply = time.Now()
+}
+
+// saveReport adds res to the current thread's totals and stores the report.
+func (s *Session) saveReport(res *agent.Result) {
+	if s.reports == nil {
+		return
+	}
+	s.usage.TurnsUsed += res.TurnsUsed
+	s.usage.ToolCalls += res.ToolCalls
+	s.usage.LoopsDetected += res.LoopsDetected
+	s.usage.TokenUsage.PromptTokens += res.TokenUsage.PromptTokens
+	s.usage.TokenUsage.CompletionTokens += res.TokenUsage.CompletionTokens
+	s.usage.TokenUsage.TotalTokens += res.TokenUsage.TotalTokens
+
+	report := agent.NewThreadReport(s.threadID(), map[string]*agent.Result{"pm": &s.usage})
+	report.Outcome = "success"
+	if err := s.reports.Save(report); err != nil {
+		s.logger.Warn("failed to save usage report", "thread", s.threadID(), "err", err)
+	}
+}
+
+// SendMessage implements agent.MessageSender by printing to the terminal.
+func (s *Session) SendMessage(_ context.Context, _, _, text string) error {
+	s.printf("codebutler> %s\n", text)
+	return nil
+}
+
+func (s *Session) threadID() string {
+	return fmt.Sprintf("demo-%d", s.thread)
+}
+
+func (s *Session) printf(format string, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, format, args...)
+}
+
+// noTools is an empty agent.ToolExecutor; the demo never touches the repo.
+type noTools struct{}
+
+func (noTools) Execute(_ context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	return agent.ToolResult{ToolCallID: call.ID, Content: "tools are disabled in demo mode", IsError: true}, nil
+}
+
+func (noTools) ListTools() []agent.ToolDefinition { return nil }
@@ -0,0 +1,191 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/reports"
+)
+
+func runScript(t *testing.T, s *Session, input string) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Run(ctx, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	return s.out.(*bytes.Buffer).String()
+}
+
+func TestSession_OfflineFlow(t *testing.T) {
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(time.Hour))
+
+	got := runScript(t, s, "/status\nfix the login crash\n\n/new\n/status\n")
+
+	for _, want := range []string{
+		"thread 1 · 0 messages",
+		"*bugfix* task",
+		"[intent workflow:bugfix",
+		"Started thread 2.",
+		"thread 2 · 0 messages",
+		"offline (scripted provider)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSession_BatchesLines(t *testing.T) {
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(time.Hour))
+
+	got := runScript(t, s, "the checkout page\nis crashing on submit\n")
+
+	if !strings.Contains(got, "batched 2 lines") {
+		t.Errorf("expected one batched message:\n%s", got)
+	}
+	if strings.Count(got, "[intent") != 1 {
+		t.Errorf("expected a single agent run:\n%s", got)
+	}
+	if len(s.history) != 2 || !strings.Contains(s.history[0].Content, "\n") {
+		t.Errorf("history = %+v", s.history)
+	}
+}
+
+func TestSession_WindowSplitsMessages(t *testing.T) {
+	pr, pw := io.Pipe()
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(20*time.Millisecond))
+
+	done := make(chan error)
+	go func() { done <- s.Run(context.Background(), pr) }()
+
+	io.WriteString(pw, "implement a search page\n")
+	time.Sleep(200 * time.Millisecond)
+	io.WriteString(pw, "also add pagination\n")
+	pw.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(out.String(), "[intent"); n != 2 {
+		t.Errorf("expected 2 runs, got %d:\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), "message 2 in this session") {
+		t.Errorf("second message should see the session history:\n%s", out.String())
+	}
+}
+
+type recordingProvider struct{ reqs []agent.ChatRequest }
+
+func (p *recordingProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	p.reqs = append(p.reqs, req)
+	return &agent.ChatResponse{Message: agent.Message{Role: "assistant", Content: "live reply"}}, nil
+}
+
+func TestSession_LiveProvider(t *testing.T) {
+	var out bytes.Buffer
+	p := &recordingProvider{}
+	s := New(&out, WithProvider(p, "some/model"), WithBatchWindow(time.Hour))
+
+	got := runScript(t, s, "hello\n/status\n/unknown\n/footer on\nagain\n")
+
+	if len(p.reqs) != 2 || p.reqs[0].Model != "some/model" {
+		t.Fatalf("requests = %+v", p.reqs)
+	}
+	if p.reqs[0].Messages[0].Role != "system" {
+		t.Error("expected system prompt first")
+	}
+	for _, want := range []string{"live reply", "live (some/model)", "Unknown command", "Cost footer on.", "live reply\n— 1 turn · 0 tok · $0.00 · model"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSession_CostFooterOption(t *testing.T) {
+	var out bytes.Buffer
+	s := New(&out, WithCostFooter(true), WithBatchWindow(time.Hour))
+
+	got := runScript(t, s, "fix the login crash\n/footer sideways\n")
+
+	if !strings.Contains(got, "— 1 turn · ") || !strings.Contains(got, "· sonnet") {
+		t.Errorf("missing footer:\n%s", got)
+	}
+	if !strings.Contains(got, "/footer failed: usage") {
+		t.Errorf("bad argument should report usage:\n%s", got)
+	}
+}
+
+func TestSession_SplitsUnrelatedBatch(t *testing.T) {
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(time.Hour))
+
+	got := runScript(t, s, "fix the login bug\nwhat's our test coverage?\n")
+
+	if !strings.Contains(got, "split 2 lines into 2 tasks") {
+		t.Errorf("expected split:\n%s", got)
+	}
+	if !strings.Contains(got, "*bugfix* task") || !strings.Contains(got, "*question* task") {
+		t.Errorf("expected one reply per task:\n%s", got)
+	}
+}
+
+func TestSession_NewTopicStartsThread(t *testing.T) {
+	pr, pw := io.Pipe()
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(20*time.Millisecond))
+
+	done := make(chan error)
+	go func() { done <- s.Run(context.Background(), pr) }()
+
+	io.WriteString(pw, "fix the login crash\n")
+	time.Sleep(200 * time.Millisecond)
+	io.WriteString(pw, "how does the router work?\n")
+	pw.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "new topic, started thread 2") {
+		t.Errorf("expected a fresh thread:\n%s", out.String())
+	}
+	if s.thread != 2 || len(s.history) != 2 {
+		t.Errorf("thread %d, history %+v", s.thread, s.history)
+	}
+}
+
+func TestSession_TLDR(t *testing.T) {
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(time.Hour))
+
+	got := runScript(t, s, "/tldr\nfix the login crash\n/tldr\n")
+
+	if !strings.Contains(got, "Nothing to summarize yet.") {
+		t.Errorf("empty thread:\n%s", got)
+	}
+	if !strings.Contains(got, "*Requests*\n• fix the login crash") {
+		t.Errorf("missing summary:\n%s", got)
+	}
+}
+
+func TestSession_Report(t *testing.T) {
+	var out bytes.Buffer
+	s := New(&out, WithBatchWindow(time.Hour), WithReportStore(reports.NewStore(t.TempDir())))
+
+	got := runScript(t, s, "/report\nfix the login crash\n/report\n")
+
+	if !strings.Contains(got, "No usage report for thread demo-1 yet.") {
+		t.Errorf("empty thread:\n%s", got)
+	}
+	if !strings.Contains(got, "**Thread:** demo-1") || !strings.Contains(got, "| pm | 1 |") {
+		t.Errorf("missing report:\n%s", got)
+	}
+}
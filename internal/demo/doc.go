@@ -0,0 +1,6 @@
+// Package demo runs a simulated CodeButler chat in the terminal: a fake
+// messenger reads messages from stdin, batches lines typed in quick
+// succession, keeps a per-session thread history, and answers slash
+// commands. When no API key is configured a scripted provider stands in for
+// the model, so the whole flow can be tried without Slack or credentials.
+package demo
@@ -0,0 +1,57 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// ScriptedProvider is an offline agent.LLMProvider. It classifies the
+// latest user message with the PM's keyword matcher and describes what the
+// real agents would do, without calling any model.
+type ScriptedProvider struct {
+	workflows []agent.WorkflowDef
+}
+
+// NewScriptedProvider creates a provider that matches against the default
+// workflows.
+func NewScriptedProvider() *ScriptedProvider {
+	return &ScriptedProvider{workflows: agent.DefaultWorkflows()}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *ScriptedProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	var last string
+	var turns, promptChars int
+	for _, m := range req.Messages {
+		promptChars += len(m.Content)
+		if m.Role == "user" {
+			last = m.Content
+			turns++
+		}
+	}
+
+	intent := agent.ClassifyIntent(last, p.workflows, nil)
+	var reply string
+	switch intent.Type {
+	case agent.IntentWorkflow:
+		reply = fmt.Sprintf("[demo] This looks like a *%s* task. With a real model I would plan it, "+
+			"delegate to the right agent, and report progress in this thread.", intent.Name)
+	default:
+		reply = "[demo] I'm not sure what you need yet. Try \"fix the login crash\" or \"how does the router work?\"."
+	}
+	if turns > 1 {
+		reply += fmt.Sprintf(" (message %d in this session)", turns)
+	}
+
+	completion := len(reply) / 4
+	return &agent.ChatResponse{
+		Message: agent.Message{Role: "assistant", Content: reply},
+		Usage: agent.TokenUsage{
+			PromptTokens:     promptChars / 4,
+			CompletionTokens: completion,
+			TotalTokens:      promptChars/4 + completion,
+		},
+	}, nil
+}
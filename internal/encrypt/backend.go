@@ -0,0 +1,129 @@
+package encrypt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/store"
+)
+
+// sealedRole marks a session stored as one sealed message. The backend's
+// schema stays the same; only the payload is opaque.
+const sealedRole = "sealed"
+
+// Backend seals message text, sessions, and budgets before they reach the
+// wrapped store. Keys, channels, and timestamps stay in the clear so
+// lookups and ordering still work.
+type Backend struct {
+	store.Backend
+	cipher *Cipher
+}
+
+// WrapBackend returns b with its payloads sealed by c. A nil c returns b.
+func WrapBackend(b store.Backend, c *Cipher) store.Backend {
+	if c == nil {
+		return b
+	}
+	return &Backend{Backend: b, cipher: c}
+}
+
+// OpenStore is store.Open with the result wrapped by c, so a backend
+// cannot be opened without the at-rest cipher by mistake. It returns nil,
+// nil when cfg selects the per-repo files.
+func OpenStore(ctx context.Context, cfg *config.GlobalStore, repoDir string, logger *slog.Logger, c *Cipher) (store.Backend, error) {
+	b, err := store.Open(ctx, cfg, repoDir, logger)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return WrapBackend(b, c), nil
+}
+
+// AppendMessage implements store.Backend.
+func (b *Backend) AppendMessage(ctx context.Context, m store.Message) error {
+	text, err := b.cipher.SealString(m.Text)
+	if err != nil {
+		return err
+	}
+	m.Text = text
+	return b.Backend.AppendMessage(ctx, m)
+}
+
+// Messages implements store.Backend.
+func (b *Backend) Messages(ctx context.Context, channel, thread string, limit int) ([]store.Message, error) {
+	msgs, err := b.Backend.Messages(ctx, channel, thread, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range msgs {
+		if msgs[i].Text, err = b.cipher.OpenString(msgs[i].Text); err != nil {
+			return nil, fmt.Errorf("open message %s: %w", msgs[i].TS, err)
+		}
+	}
+	return msgs, nil
+}
+
+// LoadSession implements store.Backend.
+func (b *Backend) LoadSession(ctx context.Context, key string) ([]agent.Message, error) {
+	msgs, err := b.Backend.LoadSession(ctx, key)
+	if err != nil || len(msgs) != 1 || msgs[0].Role != sealedRole {
+		return msgs, err
+	}
+	data, err := b.cipher.OpenString(msgs[0].Content)
+	if err != nil {
+		return nil, fmt.Errorf("open session %s: %w", key, err)
+	}
+	var out []agent.Message
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("parse session %s: %w", key, err)
+	}
+	return out, nil
+}
+
+// SaveSession implements store.Backend.
+func (b *Backend) SaveSession(ctx context.Context, key string, messages []agent.Message) error {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+	sealed, err := b.cipher.SealString(string(data))
+	if err != nil {
+		return err
+	}
+	return b.Backend.SaveSession(ctx, key, []agent.Message{{Role: sealedRole, Content: sealed}})
+}
+
+// LoadBudget implements store.Backend.
+func (b *Backend) LoadBudget(ctx context.Context, key string) (json.RawMessage, error) {
+	data, err := b.Backend.LoadBudget(ctx, key)
+	if err != nil || data == nil {
+		return data, err
+	}
+	var sealed string
+	if json.Unmarshal(data, &sealed) != nil || !strings.HasPrefix(sealed, textPrefix) {
+		return data, nil // a plain budget written before encryption
+	}
+	plain, err := b.cipher.OpenString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("open budget %s: %w", key, err)
+	}
+	return json.RawMessage(plain), nil
+}
+
+// SaveBudget implements store.Backend. The sealed value is stored as a
+// JSON string so JSON-typed columns still accept it.
+func (b *Backend) SaveBudget(ctx context.Context, key string, data json.RawMessage) error {
+	sealed, err := b.cipher.SealString(string(data))
+	if err != nil {
+		return err
+	}
+	quoted, err := json.Marshal(sealed)
+	if err != nil {
+		return err
+	}
+	return b.Backend.SaveBudget(ctx, key, quoted)
+}
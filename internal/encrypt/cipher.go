@@ -0,0 +1,135 @@
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the AES-256 key length in bytes.
+const KeySize = 32
+
+// magic prefixes every sealed envelope. The version byte lets the format
+// change without guessing.
+var magic = []byte("CBENC\x01")
+
+// ErrWrongKey means a sealed envelope did not authenticate: the key is not
+// the one it was sealed with, or the data was altered.
+var ErrWrongKey = errors.New("encrypt: wrong key or corrupted data")
+
+// Cipher seals and opens data with one key. It is safe for concurrent use.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher returns a Cipher for a KeySize-byte key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encrypt: key is %d bytes, want %d", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts plaintext into an envelope.
+func (c *Cipher) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("encrypt: nonce: %w", err)
+	}
+	out := make([]byte, 0, len(magic)+len(nonce)+len(plaintext)+c.aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plaintext, magic), nil
+}
+
+// Open decrypts an envelope. Data that is not sealed is returned as is.
+func (c *Cipher) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	rest := data[len(magic):]
+	n := c.aead.NonceSize()
+	if len(rest) < n+c.aead.Overhead() {
+		return nil, ErrWrongKey
+	}
+	plain, err := c.aead.Open(nil, rest[:n], rest[n:], magic)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+// IsSealed reports whether data is a sealed envelope.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// textPrefix marks a sealed envelope carried in a text column.
+const textPrefix = "enc:v1:"
+
+// SealString seals s into printable text, for columns and JSON values that
+// cannot hold raw bytes.
+func (c *Cipher) SealString(s string) (string, error) {
+	sealed, err := c.Seal([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return textPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenString reverses SealString. Text without the prefix is returned as is.
+func (c *Cipher) OpenString(s string) (string, error) {
+	enc, ok := strings.CutPrefix(s, textPrefix)
+	if !ok {
+		return s, nil
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(enc)
+	if err != nil || !IsSealed(sealed) {
+		return "", ErrWrongKey
+	}
+	plain, err := c.Open(sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// GenerateKey returns a new random key, base64 encoded as ParseKey expects.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodes a key written as base64 (standard or URL, padded or
+// not) or hex.
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == hex.EncodedLen(KeySize) {
+		if key, err := hex.DecodeString(s); err == nil {
+			return key, nil
+		}
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == KeySize {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("encrypt: key must be %d bytes, base64 or hex encoded", KeySize)
+}
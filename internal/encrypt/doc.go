@@ -0,0 +1,39 @@
+// Package encrypt seals chat history at rest with AES-256-GCM.
+//
+// A Cipher seals bytes into a self-describing envelope: a magic prefix,
+// a random nonce, and the ciphertext. Open passes data without the prefix
+// through unchanged, so turning encryption on does not strand files or
+// rows written before; they are sealed the next time they are saved.
+//
+// The key never lives in config.json. FromConfig reads it from
+// $CODEBUTLER_ENCRYPTION_KEY or the OS keychain (macOS Keychain via
+// `security`, the Linux Secret Service via `secret-tool`), and
+// GenerateKey prints a fresh one for either.
+//
+// The Cipher covers two stores:
+//
+//   - Conversation files under .codebutler/branches, which are also the
+//     agents' per-sender sessions. conversation.WithCipher seals them, and
+//     the readers (conversation.List, export, mediation's
+//     ConversationHistory, the API's WithConversations) take the same
+//     option.
+//   - The shared store. OpenStore opens it already wrapped by
+//     WrapBackend, so message text, sessions, and budgets are sealed
+//     before they reach the database.
+//
+// Other files under .codebutler still hold conversation content in
+// plaintext and are not sealed:
+//
+//   - runs/*.jsonl, the run logs: prompts, turn text, tool arguments and
+//     output (hashes only when privacy.metadataOnlyStorage is on).
+//   - outbox/<role>.json, the text of messages waiting to be delivered.
+//   - tasks.json, the task queue, with the first line of each request.
+//   - knowledge.jsonl, the problem and approach recorded for each solved
+//     thread.
+//   - results/, task outcomes and error text, and feedback.jsonl, user
+//     comments on ratings.
+//
+// There is no separate login-session directory: the Slack tokens live in
+// the global config.json, which is written 0600 and may reference them
+// as ${VAR} so they stay out of the file entirely.
+package encrypt
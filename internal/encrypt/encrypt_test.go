@@ -0,0 +1,268 @@
+package encrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/store"
+)
+
+func testCipher(t *testing.T, b byte) *Cipher {
+	t.Helper()
+	c, err := NewCipher(bytes.Repeat([]byte{b}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := testCipher(t, 1)
+	plain := []byte(`[{"role":"user","content":"deploy key is hunter2"}]`)
+
+	sealed, err := c.Seal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("hunter2")) {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	again, _ := c.Seal(plain)
+	if bytes.Equal(sealed, again) {
+		t.Error("two seals share a nonce")
+	}
+	if got, err := c.Open(sealed); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+	if got, err := c.Open(plain); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("Open(plain) = %q, %v", got, err)
+	}
+
+	if _, err := testCipher(t, 2).Open(sealed); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("wrong key err = %v", err)
+	}
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := c.Open(tampered); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("tampered err = %v", err)
+	}
+	if _, err := c.Open(magic); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("truncated err = %v", err)
+	}
+}
+
+func TestCipher_String(t *testing.T) {
+	c := testCipher(t, 1)
+	s, err := c.SealString("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(s, textPrefix) || strings.Contains(s, "hello") {
+		t.Errorf("SealString = %q", s)
+	}
+	if got, err := c.OpenString(s); err != nil || got != "hello" {
+		t.Errorf("OpenString = %q, %v", got, err)
+	}
+	if got, _ := c.OpenString("plain"); got != "plain" {
+		t.Errorf("OpenString(plain) = %q", got)
+	}
+	if _, err := c.OpenString(textPrefix + "!!"); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("bad text err = %v", err)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	gen, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{0xab}, KeySize)
+	for _, s := range []string{gen, hex.EncodeToString(key), " " + hex.EncodeToString(key) + "\n"} {
+		if k, err := ParseKey(s); err != nil || len(k) != KeySize {
+			t.Errorf("ParseKey(%q) = %d bytes, %v", s, len(k), err)
+		}
+	}
+	for _, s := range []string{"", "short", hex.EncodeToString(key[:16])} {
+		if _, err := ParseKey(s); err == nil {
+			t.Errorf("ParseKey(%q) should fail", s)
+		}
+	}
+	if _, err := NewCipher(key[:16]); err == nil {
+		t.Error("NewCipher accepted a 16-byte key")
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	ctx := context.Background()
+	gen, _ := GenerateKey()
+	env := func(v string) Option {
+		return WithGetenv(func(k string) string {
+			if k == EnvKey {
+				return v
+			}
+			return ""
+		})
+	}
+
+	if c, err := FromConfig(ctx, nil); c != nil || err != nil {
+		t.Errorf("nil config = %v, %v", c, err)
+	}
+	if c, err := FromConfig(ctx, &config.GlobalEncryption{}, env(gen)); c != nil || err != nil {
+		t.Errorf("disabled = %v, %v", c, err)
+	}
+	if c, err := FromConfig(ctx, &config.GlobalEncryption{Enabled: true}, env(gen)); c == nil || err != nil {
+		t.Errorf("env = %v, %v", c, err)
+	}
+	if _, err := FromConfig(ctx, &config.GlobalEncryption{Enabled: true}, env("")); err == nil || !strings.Contains(err.Error(), EnvKey) {
+		t.Errorf("missing env err = %v", err)
+	}
+	if _, err := FromConfig(ctx, &config.GlobalEncryption{Enabled: true, KeySource: "vault"}); err == nil {
+		t.Error("expected unknown source error")
+	}
+
+	var ran []string
+	keychain := func(goos string, out string, fail error) Option {
+		return WithCommand(goos, func(_ context.Context, name string, args ...string) ([]byte, error) {
+			ran = append(ran, name+" "+strings.Join(args, " "))
+			return []byte(out), fail
+		})
+	}
+	cfg := &config.GlobalEncryption{Enabled: true, KeySource: SourceKeychain}
+	if c, err := FromConfig(ctx, cfg, keychain("darwin", gen+"\n", nil)); c == nil || err != nil {
+		t.Errorf("darwin keychain = %v, %v", c, err)
+	}
+	if c, err := FromConfig(ctx, cfg, keychain("linux", gen, nil)); c == nil || err != nil {
+		t.Errorf("linux keychain = %v, %v", c, err)
+	}
+	if len(ran) != 2 || !strings.HasPrefix(ran[0], "security find-generic-password") || !strings.HasPrefix(ran[1], "secret-tool lookup") {
+		t.Errorf("ran = %q", ran)
+	}
+	if _, err := FromConfig(ctx, cfg, keychain("linux", "", errors.New("exit status 1"))); err == nil || !strings.Contains(err.Error(), "secret-tool store") {
+		t.Errorf("keychain failure err = %v", err)
+	}
+	if _, err := FromConfig(ctx, cfg, keychain("windows", gen, nil)); err == nil {
+		t.Error("expected unsupported OS error")
+	}
+}
+
+// memBackend keeps whatever it is given, so tests can inspect what reached
+// the database.
+type memBackend struct {
+	messages []store.Message
+	sessions map[string][]agent.Message
+	budgets  map[string]json.RawMessage
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{sessions: map[string][]agent.Message{}, budgets: map[string]json.RawMessage{}}
+}
+
+func (m *memBackend) AppendMessage(_ context.Context, msg store.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *memBackend) Messages(context.Context, string, string, int) ([]store.Message, error) {
+	return append([]store.Message(nil), m.messages...), nil
+}
+
+func (m *memBackend) LoadSession(_ context.Context, key string) ([]agent.Message, error) {
+	return m.sessions[key], nil
+}
+
+func (m *memBackend) SaveSession(_ context.Context, key string, msgs []agent.Message) error {
+	m.sessions[key] = msgs
+	return nil
+}
+
+func (m *memBackend) LoadBudget(_ context.Context, key string) (json.RawMessage, error) {
+	return m.budgets[key], nil
+}
+
+func (m *memBackend) SaveBudget(_ context.Context, key string, data json.RawMessage) error {
+	m.budgets[key] = data
+	return nil
+}
+
+func (m *memBackend) Close() error { return nil }
+
+func TestWrapBackend(t *testing.T) {
+	ctx := context.Background()
+	inner := newMemBackend()
+	if WrapBackend(inner, nil) != store.Backend(inner) {
+		t.Error("nil cipher should return the backend unchanged")
+	}
+	b := WrapBackend(inner, testCipher(t, 1))
+
+	// Rows written before encryption was turned on.
+	inner.messages = append(inner.messages, store.Message{TS: "1", Text: "old"})
+	inner.sessions["old"] = []agent.Message{{Role: "user", Content: "old"}}
+	inner.budgets["old"] = json.RawMessage(`{"cost":1}`)
+
+	b.AppendMessage(ctx, store.Message{TS: "2", Text: "the password is hunter2"})
+	b.SaveSession(ctx, "s", []agent.Message{{Role: "user", Content: "hunter2"}})
+	b.SaveBudget(ctx, "day", json.RawMessage(`{"cost":2}`))
+
+	if inner.messages[1].Text == "the password is hunter2" || inner.sessions["s"][0].Role != sealedRole {
+		t.Errorf("stored in the clear: %+v %+v", inner.messages[1], inner.sessions["s"])
+	}
+	if strings.Contains(inner.sessions["s"][0].Content, "hunter2") {
+		t.Error("session content not sealed")
+	}
+	var quoted string
+	if err := json.Unmarshal(inner.budgets["day"], &quoted); err != nil || !strings.HasPrefix(quoted, textPrefix) {
+		t.Errorf("budget = %s", inner.budgets["day"])
+	}
+
+	msgs, err := b.Messages(ctx, "", "", 0)
+	if err != nil || msgs[0].Text != "old" || msgs[1].Text != "the password is hunter2" {
+		t.Errorf("messages = %+v, %v", msgs, err)
+	}
+	for key, want := range map[string]string{"s": "hunter2", "old": "old"} {
+		if got, err := b.LoadSession(ctx, key); err != nil || len(got) != 1 || got[0].Content != want {
+			t.Errorf("session %s = %+v, %v", key, got, err)
+		}
+	}
+	for key, want := range map[string]string{"day": `{"cost":2}`, "old": `{"cost":1}`} {
+		if got, err := b.LoadBudget(ctx, key); err != nil || string(got) != want {
+			t.Errorf("budget %s = %s, %v", key, got, err)
+		}
+	}
+	if got, err := b.LoadBudget(ctx, "none"); got != nil || err != nil {
+		t.Errorf("missing budget = %s, %v", got, err)
+	}
+
+	other := WrapBackend(inner, testCipher(t, 2))
+	if _, err := other.LoadSession(ctx, "s"); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("wrong key err = %v", err)
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, 1)
+
+	b, err := OpenStore(ctx, &config.GlobalStore{Backend: "file"}, t.TempDir(), nil, c)
+	if b != nil || err != nil {
+		t.Errorf("file backend = %v, %v; want nil, nil", b, err)
+	}
+
+	b, err = OpenStore(ctx, &config.GlobalStore{Backend: "memory"}, t.TempDir(), nil, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.(*Backend); !ok {
+		t.Fatalf("memory backend not sealed: %T", b)
+	}
+
+	if _, err := OpenStore(ctx, &config.GlobalStore{Backend: "nope"}, t.TempDir(), nil, c); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
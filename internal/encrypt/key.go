@@ -0,0 +1,130 @@
+package encrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// Key sources for config.GlobalEncryption.KeySource.
+const (
+	SourceEnv      = "env"
+	SourceKeychain = "keychain"
+)
+
+// EnvKey holds the key when KeySource is "env".
+const EnvKey = "CODEBUTLER_ENCRYPTION_KEY"
+
+// Keychain entry the key is stored under.
+const (
+	keychainService = "codebutler"
+	keychainAccount = "encryption-key"
+)
+
+// CommandFunc runs a command and returns its stdout.
+type CommandFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+type loader struct {
+	getenv func(string) string
+	run    CommandFunc
+	goos   string
+}
+
+// Option configures FromConfig.
+type Option func(*loader)
+
+// WithGetenv replaces os.Getenv.
+func WithGetenv(fn func(string) string) Option {
+	return func(l *loader) {
+		l.getenv = fn
+	}
+}
+
+// WithCommand replaces how keychain tools are run, and the OS that picks
+// the tool.
+func WithCommand(goos string, run CommandFunc) Option {
+	return func(l *loader) {
+		l.goos = goos
+		l.run = run
+	}
+}
+
+// FromConfig returns the Cipher cfg asks for, or nil, nil when encryption
+// is off. A missing key is an error rather than a silent fallback to
+// plaintext.
+func FromConfig(ctx context.Context, cfg *config.GlobalEncryption, opts ...Option) (*Cipher, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	l := &loader{getenv: os.Getenv, run: runCommand, goos: runtime.GOOS}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	var raw string
+	switch cfg.KeySource {
+	case "", SourceEnv:
+		raw = l.getenv(EnvKey)
+		if raw == "" {
+			return nil, fmt.Errorf("encryption is enabled but $%s is not set (generate one with: codebutler keygen)", EnvKey)
+		}
+	case SourceKeychain:
+		var err error
+		if raw, err = l.keychain(ctx); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown encryption keySource %q (want %q or %q)", cfg.KeySource, SourceEnv, SourceKeychain)
+	}
+
+	key, err := ParseKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(key)
+}
+
+func (l *loader) keychain(ctx context.Context) (string, error) {
+	var name string
+	var args []string
+	switch l.goos {
+	case "darwin":
+		name, args = "security", []string{"find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w"}
+	case "linux":
+		name, args = "secret-tool", []string{"lookup", "service", keychainService, "account", keychainAccount}
+	default:
+		return "", errors.New("keychain key source is not supported on " + l.goos)
+	}
+	out, err := l.run(ctx, name, args...)
+	if err != nil {
+		return "", fmt.Errorf("read encryption key from keychain (%s): %w; store one with: %s", name, err, KeychainStoreHint(l.goos))
+	}
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", fmt.Errorf("keychain entry %s/%s is empty", keychainService, keychainAccount)
+	}
+	return key, nil
+}
+
+// KeychainStoreHint is the command that saves a key where the keychain
+// source looks for it.
+func KeychainStoreHint(goos string) string {
+	switch goos {
+	case "darwin":
+		return fmt.Sprintf("security add-generic-password -s %s -a %s -w <key>", keychainService, keychainAccount)
+	case "linux":
+		return fmt.Sprintf("secret-tool store --label=CodeButler service %s account %s", keychainService, keychainAccount)
+	default:
+		return "export " + EnvKey + "=<key>"
+	}
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
@@ -0,0 +1,300 @@
+package errreport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	slackapi "github.com/slack-go/slack"
+
+	"github.com/leandrotocalini/codebutler/internal/budget"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+	"github.com/leandrotocalini/codebutler/internal/provider/openrouter"
+)
+
+// Code is a stable error identifier. Codes are safe to show to users and
+// are logged alongside the raw error so a report can be traced back.
+type Code string
+
+const (
+	CodeUnknown Code = "CB-000"
+
+	// LLM provider (OpenRouter)
+	CodeLLMRateLimit      Code = "CB-LLM-001"
+	CodeLLMOverloaded     Code = "CB-LLM-002"
+	CodeLLMContextTooLong Code = "CB-LLM-003"
+	CodeLLMContentFilter  Code = "CB-LLM-004"
+	CodeLLMAuth           Code = "CB-LLM-005"
+	CodeLLMMalformed      Code = "CB-LLM-006"
+	CodeLLMTimeout        Code = "CB-LLM-007"
+	CodeLLMUnknown        Code = "CB-LLM-099"
+
+	// Budget
+	CodeBudgetThread  Code = "CB-BUD-001"
+	CodeBudgetDaily   Code = "CB-BUD-002"
+	CodeBudgetMonthly Code = "CB-BUD-003"
+	CodeBudgetModel   Code = "CB-BUD-004"
+
+	// Git / GitHub CLI
+	CodeGitNotRepo      Code = "CB-GIT-001"
+	CodeGitConflict     Code = "CB-GIT-002"
+	CodeGitAuth         Code = "CB-GIT-003"
+	CodeGitPushRejected Code = "CB-GIT-004"
+	CodeGHNotAuthed     Code = "CB-GIT-005"
+
+	// Local commands
+	CodeCmdNotFound Code = "CB-CMD-001"
+	CodeTimeout     Code = "CB-CMD-002"
+	CodeCancelled   Code = "CB-CMD-003"
+
+	// Messenger (Slack)
+	CodeSlackAuth      Code = "CB-MSG-001"
+	CodeSlackChannel   Code = "CB-MSG-002"
+	CodeSlackNotInChan Code = "CB-MSG-003"
+	CodeSlackRateLimit Code = "CB-MSG-004"
+	CodeSlackUnknown   Code = "CB-MSG-099"
+)
+
+// Category groups codes by the subsystem that failed.
+type Category string
+
+const (
+	CategoryProvider  Category = "provider"
+	CategoryBudget    Category = "budget"
+	CategoryGit       Category = "git"
+	CategoryCommand   Category = "command"
+	CategoryMessenger Category = "messenger"
+	CategoryUnknown   Category = "unknown"
+)
+
+// Report is a classified error ready to be shown to a user. Its text comes
+// from the message catalog (messages.ErrorMessage and
+// messages.ErrorRemediation for the code), so it follows the repo's locale
+// and overrides.
+type Report struct {
+	Code      Code
+	Category  Category
+	Data      map[string]any // values for the code's message templates
+	Retryable bool           // true if retrying later is likely to succeed
+	Err       error          // original error (for logs, never shown verbatim)
+}
+
+// Error implements error so a Report can be returned up the stack. Logs
+// use the built-in English text.
+func (r *Report) Error() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", r.Code, r.Message(nil), r.Err)
+	}
+	return fmt.Sprintf("%s: %s", r.Code, r.Message(nil))
+}
+
+// Unwrap returns the original error.
+func (r *Report) Unwrap() error {
+	return r.Err
+}
+
+// Message renders the one-line, user-facing description. A nil cat uses
+// the built-in English text.
+func (r *Report) Message(cat *messages.Catalog) string {
+	return catalogOrDefault(cat).Render(messages.ErrorMessage(string(r.Code)), r.Data)
+}
+
+// Remediation renders the concrete steps the user can take.
+func (r *Report) Remediation(cat *messages.Catalog) []string {
+	text := catalogOrDefault(cat).Render(messages.ErrorRemediation(string(r.Code)), r.Data)
+	var steps []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
+
+// Format renders the report as a chat message.
+func (r *Report) Format(cat *messages.Catalog) string {
+	cat = catalogOrDefault(cat)
+	var b strings.Builder
+	b.WriteString(cat.Render(messages.ErrorReport, map[string]string{"Message": r.Message(cat), "Code": string(r.Code)}))
+	if steps := r.Remediation(cat); len(steps) > 0 {
+		b.WriteString("\n\n" + cat.Render(messages.ErrorRemediationHeader, nil) + "\n")
+		for _, step := range steps {
+			b.WriteString(fmt.Sprintf("- %s\n", step))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func catalogOrDefault(cat *messages.Catalog) *messages.Catalog {
+	if cat == nil {
+		return messages.New(messages.DefaultLocale)
+	}
+	return cat
+}
+
+// Classify maps an error to a Report. It recognizes OpenRouter classified
+// errors, budget limits, Slack API errors, missing binaries, context
+// cancellation, and common git/gh failure messages. Anything else becomes
+// CodeUnknown. Classify never returns nil for a non-nil error.
+func Classify(err error) *Report {
+	if err == nil {
+		return nil
+	}
+
+	var existing *Report
+	if errors.As(err, &existing) {
+		return existing
+	}
+
+	var llmErr *openrouter.ClassifiedError
+	if errors.As(err, &llmErr) {
+		return classifyLLM(llmErr, err)
+	}
+
+	var budgetErr *budget.BudgetExceeded
+	if errors.As(err, &budgetErr) {
+		return classifyBudget(budgetErr, err)
+	}
+
+	var slackRate *slackapi.RateLimitedError
+	if errors.As(err, &slackRate) {
+		return &Report{
+			Code:      CodeSlackRateLimit,
+			Category:  CategoryMessenger,
+			Data:      map[string]any{"RetryAfter": slackRate.RetryAfter},
+			Retryable: true,
+			Err:       err,
+		}
+	}
+
+	var slackErr slackapi.SlackErrorResponse
+	if errors.As(err, &slackErr) {
+		return classifySlack(slackErr.Err, err)
+	}
+
+	var execErr *exec.Error
+	if errors.As(err, &execErr) && errors.Is(execErr.Err, exec.ErrNotFound) {
+		return &Report{
+			Code:     CodeCmdNotFound,
+			Category: CategoryCommand,
+			Data:     map[string]any{"Command": execErr.Name},
+			Err:      err,
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Report{
+			Code:      CodeTimeout,
+			Category:  CategoryCommand,
+			Retryable: true,
+			Err:       err,
+		}
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return &Report{
+			Code:     CodeCancelled,
+			Category: CategoryCommand,
+			Err:      err,
+		}
+	}
+
+	if r := classifyGitMessage(err); r != nil {
+		return r
+	}
+
+	return &Report{
+		Code:     CodeUnknown,
+		Category: CategoryUnknown,
+		Err:      err,
+	}
+}
+
+// llmCodes maps OpenRouter error types to codes; anything else is
+// CodeLLMUnknown.
+var llmCodes = map[openrouter.ErrorType]Code{
+	openrouter.ErrRateLimit:          CodeLLMRateLimit,
+	openrouter.ErrProviderOverloaded: CodeLLMOverloaded,
+	openrouter.ErrContextTooLong:     CodeLLMContextTooLong,
+	openrouter.ErrContentFiltered:    CodeLLMContentFilter,
+	openrouter.ErrAuth:               CodeLLMAuth,
+	openrouter.ErrMalformedResponse:  CodeLLMMalformed,
+	openrouter.ErrTimeout:            CodeLLMTimeout,
+}
+
+func classifyLLM(e *openrouter.ClassifiedError, err error) *Report {
+	code, ok := llmCodes[e.Type]
+	if !ok {
+		code = CodeLLMUnknown
+	}
+	return &Report{Code: code, Category: CategoryProvider, Retryable: e.Retryable(), Err: err}
+}
+
+// budgetCodes maps a BudgetExceeded scope to its code; anything else is
+// CodeBudgetDaily.
+var budgetCodes = map[string]Code{
+	"thread": CodeBudgetThread,
+	"day":    CodeBudgetDaily,
+	"month":  CodeBudgetMonthly,
+	"model":  CodeBudgetModel,
+}
+
+func classifyBudget(e *budget.BudgetExceeded, err error) *Report {
+	code, ok := budgetCodes[e.Scope]
+	if !ok {
+		code = CodeBudgetDaily
+	}
+	return &Report{
+		Code:     code,
+		Category: CategoryBudget,
+		Data:     map[string]any{"Model": e.Model, "Spent": e.ActualUSD, "Limit": e.LimitUSD},
+		Err:      err,
+	}
+}
+
+func classifySlack(slackErr string, err error) *Report {
+	r := &Report{Category: CategoryMessenger, Data: map[string]any{"SlackError": slackErr}, Err: err}
+	switch slackErr {
+	case "invalid_auth", "not_authed", "token_revoked", "account_inactive":
+		r.Code = CodeSlackAuth
+	case "channel_not_found":
+		r.Code = CodeSlackChannel
+	case "not_in_channel", "is_archived":
+		r.Code = CodeSlackNotInChan
+	default:
+		r.Code = CodeSlackUnknown
+	}
+	return r
+}
+
+// gitPatterns maps substrings of git/gh output to codes. Order matters:
+// the first match wins. Matching ignores case unless exact is set, which
+// keeps git's "CONFLICT (content)" apart from an HTTP "409 Conflict".
+var gitPatterns = []struct {
+	substr string
+	code   Code
+	exact  bool
+}{
+	{"not a git repository", CodeGitNotRepo, false},
+	{"merge conflict", CodeGitConflict, false},
+	{"CONFLICT (", CodeGitConflict, true},
+	{"automatic merge failed", CodeGitConflict, false},
+	{"authentication failed", CodeGitAuth, false},
+	{"could not read username", CodeGitAuth, false},
+	{"[rejected]", CodeGitPushRejected, false},
+	{"gh auth login", CodeGHNotAuthed, false},
+}
+
+// classifyGitMessage matches well-known git/gh failure messages.
+func classifyGitMessage(err error) *Report {
+	msg := err.Error()
+	lower := strings.ToLower(msg)
+	for _, p := range gitPatterns {
+		if p.exact && strings.Contains(msg, p.substr) || !p.exact && strings.Contains(lower, p.substr) {
+			return &Report{Code: p.code, Category: CategoryGit, Err: err}
+		}
+	}
+	return nil
+}
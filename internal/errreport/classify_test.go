@@ -0,0 +1,212 @@
+package errreport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	slackapi "github.com/slack-go/slack"
+
+	"github.com/leandrotocalini/codebutler/internal/budget"
+	"github.com/leandrotocalini/codebutler/internal/messages"
+	"github.com/leandrotocalini/codebutler/internal/provider/openrouter"
+)
+
+func TestClassify_Nil(t *testing.T) {
+	if Classify(nil) != nil {
+		t.Error("expected nil report for nil error")
+	}
+}
+
+func TestClassify_LLMErrors(t *testing.T) {
+	tests := []struct {
+		typ  openrouter.ErrorType
+		want Code
+	}{
+		{openrouter.ErrRateLimit, CodeLLMRateLimit},
+		{openrouter.ErrProviderOverloaded, CodeLLMOverloaded},
+		{openrouter.ErrContextTooLong, CodeLLMContextTooLong},
+		{openrouter.ErrContentFiltered, CodeLLMContentFilter},
+		{openrouter.ErrAuth, CodeLLMAuth},
+		{openrouter.ErrMalformedResponse, CodeLLMMalformed},
+		{openrouter.ErrTimeout, CodeLLMTimeout},
+		{openrouter.ErrUnknown, CodeLLMUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ.String(), func(t *testing.T) {
+			err := fmt.Errorf("llm call failed on turn 2: %w", &openrouter.ClassifiedError{Type: tt.typ})
+			r := Classify(err)
+			if r.Code != tt.want {
+				t.Errorf("got %s, want %s", r.Code, tt.want)
+			}
+			if r.Category != CategoryProvider {
+				t.Errorf("expected provider category, got %s", r.Category)
+			}
+			if len(r.Remediation(nil)) == 0 {
+				t.Error("expected remediation steps")
+			}
+		})
+	}
+}
+
+func TestClassify_Budget(t *testing.T) {
+	r := Classify(&budget.BudgetExceeded{Scope: "thread", LimitUSD: 5, ActualUSD: 5.2, ThreadID: "t1"})
+	if r.Code != CodeBudgetThread {
+		t.Errorf("got %s, want %s", r.Code, CodeBudgetThread)
+	}
+
+	r = Classify(&budget.BudgetExceeded{Scope: "day", LimitUSD: 50, ActualUSD: 51})
+	if r.Code != CodeBudgetDaily {
+		t.Errorf("got %s, want %s", r.Code, CodeBudgetDaily)
+	}
+}
+
+func TestClassify_Slack(t *testing.T) {
+	tests := []struct {
+		slackErr string
+		want     Code
+	}{
+		{"invalid_auth", CodeSlackAuth},
+		{"channel_not_found", CodeSlackChannel},
+		{"not_in_channel", CodeSlackNotInChan},
+		{"msg_too_long", CodeSlackUnknown},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("slack send message: %w", slackapi.SlackErrorResponse{Err: tt.slackErr})
+		if got := Classify(err).Code; got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.slackErr, got, tt.want)
+		}
+	}
+}
+
+func TestClassify_SlackRateLimit(t *testing.T) {
+	err := fmt.Errorf("slack send message: %w", &slackapi.RateLimitedError{RetryAfter: 3 * time.Second})
+	r := Classify(err)
+	if r.Code != CodeSlackRateLimit || !r.Retryable {
+		t.Errorf("unexpected report: %+v", r)
+	}
+}
+
+func TestClassify_CommandNotFound(t *testing.T) {
+	cmdErr := &exec.Error{Name: "gh", Err: exec.ErrNotFound}
+	r := Classify(fmt.Errorf("create PR: %w", cmdErr))
+	if r.Code != CodeCmdNotFound {
+		t.Errorf("got %s, want %s", r.Code, CodeCmdNotFound)
+	}
+	if msg := r.Message(nil); msg != `Required command "gh" was not found` {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestClassify_Context(t *testing.T) {
+	if got := Classify(fmt.Errorf("run: %w", context.DeadlineExceeded)).Code; got != CodeTimeout {
+		t.Errorf("got %s, want %s", got, CodeTimeout)
+	}
+	if got := Classify(context.Canceled).Code; got != CodeCancelled {
+		t.Errorf("got %s, want %s", got, CodeCancelled)
+	}
+}
+
+func TestClassify_GitMessages(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want Code
+	}{
+		{"git status: fatal: not a git repository (or any of the parent directories)", CodeGitNotRepo},
+		{"git merge: CONFLICT (content): Merge conflict in main.go", CodeGitConflict},
+		{"git rebase: CONFLICT (modify/delete): main.go deleted in HEAD", CodeGitConflict},
+		{"git pull: Automatic merge failed; fix conflicts and then commit the result.", CodeGitConflict},
+		{"github: create ref: 409 Conflict (reference already exists)", CodeUnknown},
+		{"slack: edit conflict", CodeUnknown},
+		{"git push: fatal: Authentication failed for 'https://github.com/x/y'", CodeGitAuth},
+		{"git push: ! [rejected] main -> main (fetch first)", CodeGitPushRejected},
+		{"gh pr create: To get started with GitHub CLI, please run:  gh auth login", CodeGHNotAuthed},
+	}
+
+	for _, tt := range tests {
+		if got := Classify(errors.New(tt.msg)).Code; got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestClassify_Unknown(t *testing.T) {
+	r := Classify(errors.New("something odd"))
+	if r.Code != CodeUnknown || r.Category != CategoryUnknown {
+		t.Errorf("unexpected report: %+v", r)
+	}
+}
+
+func TestClassify_AlreadyReport(t *testing.T) {
+	orig := &Report{Code: CodeGitConflict}
+	if got := Classify(fmt.Errorf("wrapped: %w", orig)); got != orig {
+		t.Error("expected existing report to be returned as-is")
+	}
+}
+
+func TestReport_Format(t *testing.T) {
+	r := Classify(&openrouter.ClassifiedError{Type: openrouter.ErrAuth})
+	out := r.Format(nil)
+	if !strings.HasPrefix(out, ":warning: The model provider rejected the API key (error `"+string(CodeLLMAuth)+"`)") {
+		t.Errorf("expected message and code in output: %s", out)
+	}
+	if !strings.Contains(out, "What you can do:\n- Check openrouter.apiKey") {
+		t.Errorf("expected remediation section: %s", out)
+	}
+
+	es := r.Format(messages.New("es"))
+	if !strings.Contains(es, "rechazó la API key") || !strings.Contains(es, "Qué podés hacer:") {
+		t.Errorf("expected Spanish output: %s", es)
+	}
+
+	if out := Classify(context.Canceled).Format(nil); strings.Contains(out, "\n") {
+		t.Errorf("report without remediation should be one line: %q", out)
+	}
+}
+
+func TestCodesHaveCatalogText(t *testing.T) {
+	cat := messages.New(messages.DefaultLocale)
+	codes := []Code{CodeUnknown, CodeLLMUnknown, CodeBudgetModel, CodeCmdNotFound, CodeSlackUnknown, CodeGHNotAuthed}
+	for _, c := range llmCodes {
+		codes = append(codes, c)
+	}
+	for _, c := range budgetCodes {
+		codes = append(codes, c)
+	}
+	for _, p := range gitPatterns {
+		codes = append(codes, p.code)
+	}
+	for _, c := range codes {
+		key := messages.ErrorMessage(string(c))
+		if cat.Render(key, map[string]any{}) == string(key) {
+			t.Errorf("code %s has no catalog message", c)
+		}
+	}
+}
+
+func TestReport_ErrorAndUnwrap(t *testing.T) {
+	base := errors.New("boom")
+	r := Classify(base)
+	if !errors.Is(r, base) {
+		t.Error("expected report to unwrap to original error")
+	}
+	if !strings.Contains(r.Error(), "boom") || !strings.Contains(r.Error(), string(CodeUnknown)) {
+		t.Errorf("unexpected Error(): %s", r.Error())
+	}
+}
+
+func TestClassifyMonthlyAndModelBudget(t *testing.T) {
+	if r := Classify(&budget.BudgetExceeded{Scope: "month", LimitUSD: 500, ActualUSD: 501}); r.Code != CodeBudgetMonthly {
+		t.Errorf("month: got %s", r.Code)
+	}
+	r := Classify(&budget.BudgetExceeded{Scope: "model", Model: "opus", LimitUSD: 100, ActualUSD: 101})
+	if r.Code != CodeBudgetModel || r.Message(nil) != "This month's opus budget was reached ($101.00 of $100.00)" {
+		t.Errorf("model: got %s %q", r.Code, r.Message(nil))
+	}
+}
@@ -0,0 +1,3 @@
+// Package errreport classifies provider, git, tool, and messenger failures
+// into user-facing reports with stable error codes and remediation steps.
+package errreport
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// SnippetSender uploads a file to a thread (slack.Client).
+type SnippetSender interface {
+	SendCodeSnippet(ctx context.Context, channel, threadTS, filename, content string) error
+}
+
+// Command returns /export [md|json] [channel]: the current thread's
+// export, or the whole channel's, uploaded as a file.
+func Command(e *Exporter, sender SnippetSender) *chatcmd.Command {
+	const usage = "/export [md|json] [channel]"
+	return &chatcmd.Command{
+		Name:        "export",
+		Usage:       usage,
+		Description: "Export this thread's history, sessions and task results as Markdown or JSON",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			format, thread := FormatMarkdown, inv.Thread
+			for _, arg := range inv.Args {
+				switch strings.ToLower(arg) {
+				case FormatMarkdown, "markdown":
+					format = FormatMarkdown
+				case FormatJSON:
+					format = FormatJSON
+				case "channel":
+					thread = ""
+				default:
+					return "Usage: " + usage, nil
+				}
+			}
+
+			doc, err := e.Export(ctx, inv.Channel, thread)
+			if err != nil {
+				return "", err
+			}
+			out, err := Render(doc, format)
+			if err != nil {
+				return "", err
+			}
+			if err := sender.SendCodeSnippet(ctx, inv.Channel, inv.Thread, Filename(doc, format), out); err != nil {
+				return "", err
+			}
+			return "", nil
+		},
+	}
+}
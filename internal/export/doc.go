@@ -0,0 +1,5 @@
+// Package export dumps everything CodeButler kept about a chat — tasks,
+// task results, and the full message history of each agent session that
+// worked on it — into a shareable Markdown or JSON document. It backs both
+// `codebutler export` and the /export chat command.
+package export
@@ -0,0 +1,193 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/conversation"
+	"github.com/leandrotocalini/codebutler/internal/results"
+	"github.com/leandrotocalini/codebutler/internal/taskqueue"
+)
+
+// Formats accepted by Render.
+const (
+	FormatMarkdown = "md"
+	FormatJSON     = "json"
+)
+
+// TaskSource lists CodeButler tasks (taskqueue.Queue).
+type TaskSource interface {
+	Since(t time.Time) []taskqueue.Task
+}
+
+// ResultSource lists task results (results.Store).
+type ResultSource interface {
+	List() ([]*results.TaskResult, error)
+}
+
+// Session is one agent conversation that worked on the chat.
+type Session struct {
+	conversation.Summary
+	History []agent.Message `json:"history"`
+}
+
+// Document is a chat's export.
+type Document struct {
+	Channel     string                `json:"channel"`
+	Thread      string                `json:"thread,omitempty"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Tasks       []taskqueue.Task      `json:"tasks"`
+	Results     []*results.TaskResult `json:"results"`
+	Sessions    []Session             `json:"sessions"`
+}
+
+// Exporter gathers a chat's data from a repo's .codebutler directory.
+type Exporter struct {
+	repoDir string
+	tasks   TaskSource
+	results ResultSource
+	cipher  conversation.Cipher
+	now     func() time.Time
+}
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithTasks reads tasks from a live queue instead of the queue file.
+func WithTasks(s TaskSource) Option {
+	return func(e *Exporter) {
+		e.tasks = s
+	}
+}
+
+// WithResults overrides the repo's results store.
+func WithResults(s ResultSource) Option {
+	return func(e *Exporter) {
+		e.results = s
+	}
+}
+
+// WithCipher reads conversation files sealed at rest.
+func WithCipher(c conversation.Cipher) Option {
+	return func(e *Exporter) {
+		e.cipher = c
+	}
+}
+
+// New creates an exporter for the repo at repoDir.
+func New(repoDir string, opts ...Option) *Exporter {
+	e := &Exporter{
+		repoDir: repoDir,
+		results: results.NewStore(results.DefaultDir(repoDir)),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ParseChat splits a chat reference, "<channel>" or "<channel>/<thread>".
+func ParseChat(chat string) (channel, thread string, err error) {
+	channel, thread, _ = strings.Cut(strings.TrimSpace(chat), "/")
+	if channel == "" {
+		return "", "", fmt.Errorf("chat %q: want <channel> or <channel>/<thread>", chat)
+	}
+	return channel, thread, nil
+}
+
+// Export gathers the chat's tasks, results and sessions. An empty thread
+// exports every thread in the channel. Sessions are the conversations on
+// the branches the chat's results recorded.
+func (e *Exporter) Export(_ context.Context, channel, thread string) (*Document, error) {
+	doc := &Document{Channel: channel, Thread: thread, GeneratedAt: e.now()}
+	match := func(c, t string) bool {
+		return c == channel && (thread == "" || t == thread)
+	}
+
+	tasks, err := e.allTasks()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if match(t.Channel, t.Thread) {
+			doc.Tasks = append(doc.Tasks, t)
+		}
+	}
+
+	all, err := e.results.List()
+	if err != nil {
+		return nil, err
+	}
+	branches := make(map[string]bool)
+	for _, r := range all {
+		if match(r.Channel, r.Thread) {
+			doc.Results = append(doc.Results, r)
+			if r.Branch != "" {
+				branches[r.Branch] = true
+			}
+		}
+	}
+
+	var convOpts []conversation.Option
+	if e.cipher != nil {
+		convOpts = append(convOpts, conversation.WithCipher(e.cipher))
+	}
+	sessions, err := conversation.List(e.repoDir, convOpts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if !branches[s.Branch] {
+			continue
+		}
+		role := s.Role
+		if s.Sender != "" {
+			role += "." + s.Sender
+		}
+		history, err := conversation.NewFileStore(conversation.FilePath(e.repoDir, s.Branch, role), convOpts...).Load(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("load %s/%s conversation: %w", s.Branch, role, err)
+		}
+		doc.Sessions = append(doc.Sessions, Session{Summary: s, History: history})
+	}
+	return doc, nil
+}
+
+func (e *Exporter) allTasks() ([]taskqueue.Task, error) {
+	if e.tasks != nil {
+		return e.tasks.Since(time.Time{}), nil
+	}
+	return taskqueue.Read(taskqueue.DefaultPath(e.repoDir))
+}
+
+// Render encodes doc as FormatMarkdown or FormatJSON.
+func Render(doc *Document, format string) (string, error) {
+	switch format {
+	case FormatMarkdown, "markdown", "":
+		return Markdown(doc), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshal export: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want %s or %s)", format, FormatMarkdown, FormatJSON)
+}
+
+// Filename suggests a file name for the export.
+func Filename(doc *Document, format string) string {
+	if format != FormatJSON {
+		format = FormatMarkdown
+	}
+	name := doc.Channel
+	if doc.Thread != "" {
+		name += "-" + doc.Thread
+	}
+	return "codebutler-export-" + name + "." + format
+}
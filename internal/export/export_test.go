@@ -0,0 +1,161 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/conversation"
+	"github.com/leandrotocalini/codebutler/internal/results"
+	"github.com/leandrotocalini/codebutler/internal/taskqueue"
+)
+
+type mockTasks struct {
+	tasks []taskqueue.Task
+}
+
+func (m *mockTasks) Since(time.Time) []taskqueue.Task { return m.tasks }
+
+type mockSnippets struct {
+	filename string
+	content  string
+}
+
+func (m *mockSnippets) SendCodeSnippet(_ context.Context, _, _, filename, content string) error {
+	m.filename, m.content = filename, content
+	return nil
+}
+
+// setupRepo stores two threads' results and conversations in a temp repo.
+func setupRepo(t *testing.T) (string, *mockTasks) {
+	t.Helper()
+	dir := t.TempDir()
+	store := results.NewStore(results.DefaultDir(dir))
+	for _, r := range []*results.TaskResult{
+		{TaskID: "t1", Channel: "C1", Thread: "100.1", Branch: "codebutler/fix-login", Outcome: results.OutcomeSuccess,
+			PRURL: "https://github.com/acme/app/pull/7", StartedAt: time.Unix(100, 0)},
+		{TaskID: "t2", Channel: "C1", Thread: "200.2", Branch: "codebutler/other", Outcome: results.OutcomeFailed, StartedAt: time.Unix(200, 0)},
+	} {
+		if err := store.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	coder := conversation.NewFileStore(conversation.FilePath(dir, "codebutler/fix-login", "coder"))
+	err := coder.Save(context.Background(), []agent.Message{
+		{Role: "system", Content: "You are the coder."},
+		{Role: "user", Content: "fix the login redirect"},
+		{Role: "assistant", ToolCalls: []agent.ToolCall{{ID: "1", Name: "Read", Arguments: `{"path":"auth.go"}`}}},
+		{Role: "tool", ToolCallID: "1", Content: "package auth"},
+		{Role: "assistant", Content: "Fixed in PR #7."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := conversation.NewFileStore(conversation.FilePath(dir, "codebutler/other", "coder"))
+	other.Save(context.Background(), []agent.Message{{Role: "user", Content: "unrelated"}})
+
+	tasks := &mockTasks{tasks: []taskqueue.Task{
+		{ID: "t1", Channel: "C1", Thread: "100.1", Summary: "fix the login redirect", Status: taskqueue.StatusDone},
+		{ID: "t2", Channel: "C1", Thread: "200.2", Summary: "unrelated", Status: taskqueue.StatusFailed},
+		{ID: "t3", Channel: "C2", Thread: "100.1", Summary: "other channel"},
+	}}
+	return dir, tasks
+}
+
+func TestExport_Thread(t *testing.T) {
+	dir, tasks := setupRepo(t)
+	doc, err := New(dir, WithTasks(tasks)).Export(context.Background(), "C1", "100.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Tasks) != 1 || len(doc.Results) != 1 || len(doc.Sessions) != 1 {
+		t.Fatalf("tasks=%d results=%d sessions=%d", len(doc.Tasks), len(doc.Results), len(doc.Sessions))
+	}
+	if s := doc.Sessions[0]; s.Role != "coder" || len(s.History) != 5 {
+		t.Errorf("session = %+v", s.Summary)
+	}
+
+	md := Markdown(doc)
+	for _, want := range []string{"# CodeButler export — C1 / 100.1", "fix the login redirect", "https://github.com/acme/app/pull/7",
+		"**Assistant:** Fixed in PR #7.", "`Read`", "package auth"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+	if strings.Contains(md, "unrelated") {
+		t.Error("other thread leaked into export")
+	}
+}
+
+func TestExport_ChannelJSON(t *testing.T) {
+	dir, tasks := setupRepo(t)
+	doc, err := New(dir, WithTasks(tasks)).Export(context.Background(), "C1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Render(doc, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Document
+	if err := json.Unmarshal([]byte(out), &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Tasks) != 2 || len(back.Results) != 2 || len(back.Sessions) != 2 {
+		t.Errorf("tasks=%d results=%d sessions=%d", len(back.Tasks), len(back.Results), len(back.Sessions))
+	}
+	if _, err := Render(doc, "pdf"); err == nil {
+		t.Error("expected unknown format error")
+	}
+}
+
+func TestExport_ReadsQueueFile(t *testing.T) {
+	dir, _ := setupRepo(t)
+	q, err := taskqueue.Open(taskqueue.DefaultPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Enqueue("C1", "100.1", "U1", "fix the login redirect")
+
+	doc, err := New(dir).Export(context.Background(), "C1", "100.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Tasks) != 1 {
+		t.Errorf("tasks = %+v", doc.Tasks)
+	}
+}
+
+func TestParseChat(t *testing.T) {
+	if c, th, err := ParseChat("C1/100.1"); err != nil || c != "C1" || th != "100.1" {
+		t.Errorf("got %q %q %v", c, th, err)
+	}
+	if _, _, err := ParseChat(""); err == nil {
+		t.Error("expected error for empty chat")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir, tasks := setupRepo(t)
+	s := &mockSnippets{}
+	cmd := Command(New(dir, WithTasks(tasks)), s)
+
+	inv := chatcmd.Invocation{Channel: "C1", Thread: "100.1", Args: []string{"json"}}
+	reply, err := cmd.Run(context.Background(), inv)
+	if err != nil || reply != "" {
+		t.Fatalf("reply = %q, err = %v", reply, err)
+	}
+	if s.filename != "codebutler-export-C1-100.1.json" || !strings.Contains(s.content, `"task_id": "t1"`) {
+		t.Errorf("uploaded %s: %.80s", s.filename, s.content)
+	}
+
+	inv.Args = []string{"csv"}
+	if reply, _ := cmd.Run(context.Background(), inv); !strings.HasPrefix(reply, "Usage:") {
+		t.Errorf("reply = %q", reply)
+	}
+}
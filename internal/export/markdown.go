@@ -0,0 +1,105 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// maxToolOutput caps each tool result in the Markdown rendering; the JSON
+// export keeps them whole.
+const maxToolOutput = 2000
+
+// Markdown renders doc for reading and sharing.
+func Markdown(doc *Document) string {
+	var b strings.Builder
+	chat := doc.Channel
+	if doc.Thread != "" {
+		chat += " / " + doc.Thread
+	}
+	fmt.Fprintf(&b, "# CodeButler export — %s\n\n", chat)
+	fmt.Fprintf(&b, "_Generated %s_\n\n", doc.GeneratedAt.UTC().Format(time.RFC1123))
+
+	b.WriteString("## Tasks\n\n")
+	if len(doc.Tasks) == 0 {
+		b.WriteString("No tasks.\n\n")
+	} else {
+		b.WriteString("| ID | Thread | Status | Request | Turns | Cost |\n")
+		b.WriteString("|----|--------|--------|---------|-------|------|\n")
+		for _, t := range doc.Tasks {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %d | $%.2f |\n",
+				t.ID, t.Thread, t.Status, cell(t.Summary), t.Turns, t.Cost)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Results\n\n")
+	if len(doc.Results) == 0 {
+		b.WriteString("No task results.\n\n")
+	}
+	for _, r := range doc.Results {
+		fmt.Fprintf(&b, "### %s — %s\n\n", r.TaskID, r.Outcome)
+		if r.Branch != "" {
+			fmt.Fprintf(&b, "- Branch: `%s`\n", r.Branch)
+		}
+		if r.PRURL != "" {
+			fmt.Fprintf(&b, "- PR: %s\n", r.PRURL)
+		}
+		if r.Error != "" {
+			fmt.Fprintf(&b, "- Error: %s\n", r.Error)
+		}
+		if len(r.FilesChanged) > 0 {
+			fmt.Fprintf(&b, "- Files changed: %s\n", strings.Join(r.FilesChanged, ", "))
+		}
+		fmt.Fprintf(&b, "- Cost: $%.4f · %d tokens · %s\n\n",
+			r.TotalCost, r.TotalTokens, (time.Duration(r.DurationMS) * time.Millisecond).Round(time.Second))
+	}
+
+	b.WriteString("## Sessions\n\n")
+	if len(doc.Sessions) == 0 {
+		b.WriteString("No agent sessions.\n")
+	}
+	for _, s := range doc.Sessions {
+		who := s.Role
+		if s.Sender != "" {
+			who += " (" + s.Sender + ")"
+		}
+		fmt.Fprintf(&b, "### %s on `%s`\n\n", who, s.Branch)
+		fmt.Fprintf(&b, "_%d messages, last updated %s_\n\n", s.Messages, s.UpdatedAt.UTC().Format(time.RFC1123))
+		for _, m := range s.History {
+			writeMessage(&b, m)
+		}
+	}
+	return b.String()
+}
+
+func writeMessage(b *strings.Builder, m agent.Message) {
+	switch m.Role {
+	case "system":
+		b.WriteString("<details><summary>System prompt</summary>\n\n")
+		b.WriteString(m.Content)
+		b.WriteString("\n\n</details>\n\n")
+	case "user":
+		fmt.Fprintf(b, "**User:** %s\n\n", m.Content)
+	case "assistant":
+		if m.Content != "" {
+			fmt.Fprintf(b, "**Assistant:** %s\n\n", m.Content)
+		}
+		for _, c := range m.ToolCalls {
+			fmt.Fprintf(b, "> 🔧 `%s` `%s`\n\n", c.Name, c.Arguments)
+		}
+	case "tool":
+		out := m.Content
+		if len(out) > maxToolOutput {
+			out = out[:maxToolOutput] + "\n... (truncated)"
+		}
+		fmt.Fprintf(b, "```\n%s\n```\n\n", strings.ReplaceAll(out, "```", "'''"))
+	}
+}
+
+// cell keeps a value on one table row.
+func cell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
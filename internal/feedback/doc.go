@@ -0,0 +1,4 @@
+// Package feedback records /good and /bad ratings for completed tasks in an
+// append-only JSONL file and surfaces recent negative feedback to the Lead's
+// retrospective.
+package feedback
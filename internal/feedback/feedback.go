@@ -0,0 +1,253 @@
+package feedback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// Score is a task rating.
+type Score int
+
+const (
+	Bad  Score = -1
+	Good Score = 1
+)
+
+// Rating is one piece of user feedback on a thread's outcome.
+type Rating struct {
+	ThreadID  string    `json:"thread_id"`
+	Channel   string    `json:"channel"`
+	UserID    string    `json:"user_id"`
+	Score     Score     `json:"score"`
+	Comment   string    `json:"comment,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Store appends ratings to a JSONL file. Thread-safe.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// DefaultPath returns the feedback log path for a repo.
+func DefaultPath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "feedback.jsonl")
+}
+
+// NewStore creates a store backed by the JSONL file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// Add appends a rating. Timestamp is set if zero.
+func (s *Store) Add(r Rating) error {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = s.now()
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal rating: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create feedback dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open feedback log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("write rating: %w", err)
+	}
+	return nil
+}
+
+// All reads every rating. A missing file returns no ratings. Malformed lines
+// are skipped.
+func (s *Store) All() ([]Rating, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open feedback log: %w", err)
+	}
+	defer f.Close()
+
+	var out []Rating
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Rating
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, scanner.Err()
+}
+
+// ForThread returns the ratings for one thread.
+func (s *Store) ForThread(threadID string) ([]Rating, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	var out []Rating
+	for _, r := range all {
+		if r.ThreadID == threadID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// ThreadFeedback returns a thread's net score and the comments left with
+// its ratings, for agent.NewThreadReport.
+func (s *Store) ThreadFeedback(threadID string) (rating int, comments []string, err error) {
+	ratings, err := s.ForThread(threadID)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, r := range ratings {
+		if r.Comment != "" {
+			comments = append(comments, r.Comment)
+		}
+	}
+	return Net(ratings), comments, nil
+}
+
+// RecentNegative returns up to limit /bad ratings since the given time,
+// newest first.
+func (s *Store) RecentNegative(since time.Time, limit int) ([]Rating, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	var out []Rating
+	for i := len(all) - 1; i >= 0 && (limit <= 0 || len(out) < limit); i-- {
+		r := all[i]
+		if r.Score == Bad && !r.Timestamp.Before(since) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// Summary aggregates ratings.
+type Summary struct {
+	Good int
+	Bad  int
+}
+
+// Total returns the number of ratings.
+func (s Summary) Total() int { return s.Good + s.Bad }
+
+// Summarize counts good and bad ratings.
+func Summarize(ratings []Rating) Summary {
+	var s Summary
+	for _, r := range ratings {
+		switch r.Score {
+		case Good:
+			s.Good++
+		case Bad:
+			s.Bad++
+		}
+	}
+	return s
+}
+
+// Net returns the thread's net score (sum of ratings).
+func Net(ratings []Rating) int {
+	n := 0
+	for _, r := range ratings {
+		n += int(r.Score)
+	}
+	return n
+}
+
+// FormatForRetro renders negative feedback as lines for the retrospective
+// prompt, e.g. "thread 1712.44 — wrong file edited".
+func FormatForRetro(ratings []Rating) []string {
+	out := make([]string, 0, len(ratings))
+	for _, r := range ratings {
+		comment := r.Comment
+		if comment == "" {
+			comment = "(no comment)"
+		}
+		out = append(out, fmt.Sprintf("thread %s — %s", r.ThreadID, comment))
+	}
+	return out
+}
+
+// Commands returns the /good and /bad chat commands backed by store.
+func Commands(store *Store) []*chatcmd.Command {
+	rate := func(score Score) func(context.Context, chatcmd.Invocation) (string, error) {
+		return func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			if err := store.Add(Rating{
+				ThreadID: inv.Thread,
+				Channel:  inv.Channel,
+				UserID:   inv.UserID,
+				Score:    score,
+				Comment:  inv.RawArgs,
+			}); err != nil {
+				return "", err
+			}
+			if score == Good {
+				return "Thanks — noted :+1:", nil
+			}
+			reply := "Thanks — the Lead will look at this in the next retrospective."
+			if strings.TrimSpace(inv.RawArgs) == "" {
+				reply += " Add a comment next time (`/bad <what went wrong>`) to make it actionable."
+			}
+			return reply, nil
+		}
+	}
+
+	return []*chatcmd.Command{
+		{
+			Name:        "good",
+			Usage:       "/good [comment]",
+			Description: "Rate this thread's outcome as good",
+			Run:         rate(Good),
+		},
+		{
+			Name:        "bad",
+			Usage:       "/bad [what went wrong]",
+			Description: "Rate this thread's outcome as bad",
+			Run:         rate(Bad),
+		},
+	}
+}
+
+// RetroSource returns a function listing /bad feedback from the last window
+// (at most limit entries), for agent.WithRecentFeedback. Read errors yield
+// no feedback rather than blocking the retrospective.
+func RetroSource(store *Store, window time.Duration, limit int) func() []string {
+	return func() []string {
+		ratings, err := store.RecentNegative(store.now().Add(-window), limit)
+		if err != nil {
+			return nil
+		}
+		return FormatForRetro(ratings)
+	}
+}
@@ -0,0 +1,140 @@
+package feedback
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return NewStore(filepath.Join(t.TempDir(), ".codebutler", "feedback.jsonl"))
+}
+
+func TestStore_AddAndRead(t *testing.T) {
+	s := newTestStore(t)
+	s.Add(Rating{ThreadID: "t1", Score: Good})
+	s.Add(Rating{ThreadID: "t1", Score: Bad, Comment: "missed a test"})
+	s.Add(Rating{ThreadID: "t2", Score: Good})
+
+	all, err := s.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 ratings, got %d", len(all))
+	}
+	if all[0].Timestamp.IsZero() {
+		t.Error("timestamp should be set")
+	}
+
+	t1, _ := s.ForThread("t1")
+	if len(t1) != 2 || Net(t1) != 0 {
+		t.Errorf("unexpected thread ratings: %+v", t1)
+	}
+	if sum := Summarize(all); sum.Good != 2 || sum.Bad != 1 || sum.Total() != 3 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+}
+
+func TestStore_ThreadFeedback(t *testing.T) {
+	s := newTestStore(t)
+	s.Add(Rating{ThreadID: "t1", Score: Bad, Comment: "missed a test"})
+	s.Add(Rating{ThreadID: "t1", Score: Bad})
+	s.Add(Rating{ThreadID: "t1", Score: Good, Comment: "fixed after retry"})
+	s.Add(Rating{ThreadID: "t2", Score: Good, Comment: "other thread"})
+
+	report := agent.NewThreadReport("t1", nil, agent.WithReportFeedback(s))
+	if report.Rating != -1 {
+		t.Errorf("rating = %d, want -1", report.Rating)
+	}
+	if strings.Join(report.Feedback, "|") != "missed a test|fixed after retry" {
+		t.Errorf("feedback = %v", report.Feedback)
+	}
+}
+
+func TestStore_MissingFile(t *testing.T) {
+	all, err := newTestStore(t).All()
+	if err != nil || all != nil {
+		t.Errorf("expected empty, got %v %v", all, err)
+	}
+}
+
+func TestStore_RecentNegative(t *testing.T) {
+	s := newTestStore(t)
+	base := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	s.Add(Rating{ThreadID: "old", Score: Bad, Timestamp: base.Add(-48 * time.Hour)})
+	s.Add(Rating{ThreadID: "a", Score: Bad, Timestamp: base.Add(time.Hour)})
+	s.Add(Rating{ThreadID: "b", Score: Good, Timestamp: base.Add(2 * time.Hour)})
+	s.Add(Rating{ThreadID: "c", Score: Bad, Timestamp: base.Add(3 * time.Hour)})
+
+	got, err := s.RecentNegative(base, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ThreadID != "c" || got[1].ThreadID != "a" {
+		t.Errorf("unexpected: %+v", got)
+	}
+
+	got, _ = s.RecentNegative(base, 1)
+	if len(got) != 1 {
+		t.Errorf("limit not applied: %+v", got)
+	}
+}
+
+func TestFormatForRetro(t *testing.T) {
+	lines := FormatForRetro([]Rating{
+		{ThreadID: "t1", Comment: "wrong file"},
+		{ThreadID: "t2"},
+	})
+	if lines[0] != "thread t1 — wrong file" || !strings.Contains(lines[1], "no comment") {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	s := newTestStore(t)
+	reg := chatcmd.NewRegistry()
+	for _, c := range Commands(s) {
+		reg.Register(c)
+	}
+
+	reply, handled, err := reg.Handle(context.Background(), "C1", "T1", "U1", "/bad edited the wrong service")
+	if err != nil || !handled {
+		t.Fatalf("handled=%v err=%v", handled, err)
+	}
+	if !strings.Contains(reply, "retrospective") {
+		t.Errorf("unexpected reply: %s", reply)
+	}
+
+	reply, _, _ = reg.Handle(context.Background(), "C1", "T1", "U1", "/good")
+	if !strings.Contains(reply, "noted") {
+		t.Errorf("unexpected reply: %s", reply)
+	}
+
+	ratings, _ := s.ForThread("T1")
+	if len(ratings) != 2 {
+		t.Fatalf("expected 2 ratings, got %d", len(ratings))
+	}
+	if ratings[0].Score != Bad || ratings[0].Comment != "edited the wrong service" || ratings[0].UserID != "U1" {
+		t.Errorf("unexpected rating: %+v", ratings[0])
+	}
+}
+
+func TestRetroSource(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.Add(Rating{ThreadID: "old", Score: Bad, Comment: "stale", Timestamp: now.Add(-30 * 24 * time.Hour)})
+	s.Add(Rating{ThreadID: "new", Score: Bad, Comment: "fresh"})
+
+	lines := RetroSource(s, 7*24*time.Hour, 5)()
+	if len(lines) != 1 || !strings.Contains(lines[0], "fresh") {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+}
@@ -0,0 +1,120 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Commit is one entry of git log.
+type Commit struct {
+	Hash    string    `json:"hash"` // abbreviated
+	Author  string    `json:"author"`
+	Subject string    `json:"subject"`
+	Time    time.Time `json:"time"`
+}
+
+// logSep separates git log fields; it cannot appear in a subject line.
+const logSep = "\x1f"
+
+// CommitsSince returns commits on the current branch since t, newest
+// first. Merge commits are skipped; merged PRs are reported separately.
+func (g *GitOps) CommitsSince(ctx context.Context, t time.Time) ([]Commit, error) {
+	out, err := g.runCmd(ctx, g.dir, "git", "log",
+		"--no-merges",
+		"--since="+t.Format(time.RFC3339),
+		"--pretty=format:%h"+logSep+"%an"+logSep+"%cI"+logSep+"%s",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("git log: %s: %w", out, err)
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.SplitN(line, logSep, 4)
+		if len(f) != 4 {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, f[2])
+		commits = append(commits, Commit{Hash: f[0], Author: f[1], Time: ts, Subject: f[3]})
+	}
+	return commits, nil
+}
+
+// MergedPR is a pull request merged in a time window.
+type MergedPR struct {
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	MergedAt time.Time `json:"mergedAt"`
+	Author   struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// MergedPRsSince returns PRs merged since t, newest first.
+func (g *GHOps) MergedPRsSince(ctx context.Context, t time.Time) ([]MergedPR, error) {
+	out, err := g.runCmd(ctx, g.dir, "gh", "pr", "list",
+		"--state", "merged",
+		"--search", "merged:>="+t.UTC().Format("2006-01-02"),
+		"--json", "number,title,url,mergedAt,author",
+		"--limit", "50",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gh pr list: %s: %w", out, err)
+	}
+
+	var prs []MergedPR
+	if err := decodeList(out, &prs); err != nil {
+		return nil, fmt.Errorf("parse pr list: %w", err)
+	}
+	// The search is day-granular; trim to the exact window.
+	kept := prs[:0]
+	for _, pr := range prs {
+		if !pr.MergedAt.Before(t) {
+			kept = append(kept, pr)
+		}
+	}
+	return kept, nil
+}
+
+// WorkflowRun is one CI run.
+type WorkflowRun struct {
+	ID         int64     `json:"databaseId"`
+	Name       string    `json:"name"`
+	Branch     string    `json:"headBranch"`
+	Conclusion string    `json:"conclusion"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// FailedRunsSince returns CI runs that failed since t, newest first.
+func (g *GHOps) FailedRunsSince(ctx context.Context, t time.Time) ([]WorkflowRun, error) {
+	out, err := g.runCmd(ctx, g.dir, "gh", "run", "list",
+		"--status", "failure",
+		"--created", ">="+t.UTC().Format(time.RFC3339),
+		"--json", "databaseId,name,headBranch,conclusion,url,createdAt",
+		"--limit", "50",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gh run list: %s: %w", out, err)
+	}
+
+	var runs []WorkflowRun
+	if err := decodeList(out, &runs); err != nil {
+		return nil, fmt.Errorf("parse run list: %w", err)
+	}
+	return runs, nil
+}
+
+// decodeList unmarshals gh's JSON list output, treating empty output as an
+// empty list.
+func decodeList(out string, v any) error {
+	out = strings.TrimSpace(out)
+	if out == "" || out == "[]" {
+		return nil
+	}
+	return json.Unmarshal([]byte(out), v)
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitOps_CommitsSince(t *testing.T) {
+	var gotArgs []string
+	runner := func(_ context.Context, _, _ string, args ...string) (string, error) {
+		gotArgs = args
+		return "abc123\x1fAna\x1f2026-03-02T09:00:00Z\x1fFix login redirect\n" +
+			"def456\x1fBo\x1f2026-03-02T08:00:00Z\x1fAdd rate limit: per user", nil
+	}
+	g := NewGitOps("/tmp/repo", WithGitCommandRunner(runner))
+
+	since := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	commits, err := g.CommitsSince(context.Background(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Author != "Ana" || commits[1].Subject != "Add rate limit: per user" {
+		t.Errorf("commits = %+v", commits)
+	}
+	if !strings.Contains(strings.Join(gotArgs, " "), "--since=2026-03-01T09:00:00Z") {
+		t.Errorf("args = %v", gotArgs)
+	}
+}
+
+func TestGHOps_MergedPRsSince(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{{out: `[
+		{"number":12,"title":"Add caching","url":"u12","mergedAt":"2026-03-02T10:00:00Z","author":{"login":"ana"}},
+		{"number":11,"title":"Old","url":"u11","mergedAt":"2026-03-01T01:00:00Z","author":{"login":"bo"}}
+	]`}})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner))
+
+	prs, err := g.MergedPRsSince(context.Background(), time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].Number != 12 || prs[0].Author.Login != "ana" {
+		t.Errorf("prs = %+v", prs)
+	}
+}
+
+func TestGHOps_FailedRunsSince(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{{out: `[{"databaseId":99,"name":"CI","headBranch":"main","conclusion":"failure","url":"u","createdAt":"2026-03-02T10:00:00Z"}]`}})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner))
+
+	runs, err := g.FailedRunsSince(context.Background(), time.Now())
+	if err != nil || len(runs) != 1 || runs[0].ID != 99 || runs[0].Branch != "main" {
+		t.Errorf("runs = %+v, %v", runs, err)
+	}
+
+	runner, _ = newMockRunner([]mockCall{{out: ""}})
+	g = NewGHOps("/tmp/repo", WithGHCommandRunner(runner))
+	if runs, err := g.FailedRunsSince(context.Background(), time.Now()); err != nil || runs != nil {
+		t.Errorf("empty = %v, %v", runs, err)
+	}
+}
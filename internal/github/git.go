@@ -135,3 +135,13 @@ func (g *GitOps) CurrentBranch(ctx context.Context) (string, error) {
 	}
 	return out, nil
 }
+
+// Diff returns the changes on head since it branched from base
+// (git diff base...head).
+func (g *GitOps) Diff(ctx context.Context, base, head string) (string, error) {
+	out, err := g.runCmd(ctx, g.dir, "git", "diff", base+"..."+head)
+	if err != nil {
+		return "", fmt.Errorf("git diff %s...%s: %s: %w", base, head, out, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Issue holds the fields of a GitHub issue needed for triage.
+type Issue struct {
+	Number    int          `json:"number"`
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	URL       string       `json:"url"`
+	Labels    []IssueLabel `json:"labels"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// IssueLabel is a label attached to an issue.
+type IssueLabel struct {
+	Name string `json:"name"`
+}
+
+// HasLabel reports whether the issue carries the named label.
+func (i Issue) HasLabel(name string) bool {
+	for _, l := range i.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListOpenIssues returns up to limit open issues, newest first.
+func (g *GHOps) ListOpenIssues(ctx context.Context, limit int) ([]Issue, error) {
+	out, err := g.runCmd(ctx, g.dir, "gh", "issue", "list",
+		"--state", "open",
+		"--json", "number,title,body,url,labels,createdAt",
+		"--limit", fmt.Sprintf("%d", limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gh issue list: %s: %w", out, err)
+	}
+
+	out = strings.TrimSpace(out)
+	if out == "" || out == "[]" {
+		return nil, nil
+	}
+
+	var issues []Issue
+	if err := json.Unmarshal([]byte(out), &issues); err != nil {
+		return nil, fmt.Errorf("parse issue list: %w", err)
+	}
+	return issues, nil
+}
+
+// AddIssueLabels adds labels to an issue.
+// Idempotent: adding a label that is already present is a no-op in gh.
+func (g *GHOps) AddIssueLabels(ctx context.Context, number int, labels ...string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	out, err := g.runCmd(ctx, g.dir, "gh", "issue", "edit",
+		fmt.Sprintf("%d", number),
+		"--add-label", strings.Join(labels, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("gh issue edit: %s: %w", out, err)
+	}
+
+	g.logger.Info("labeled issue", "number", number, "labels", labels)
+	return nil
+}
+
+// CommentIssue posts a comment on an issue.
+func (g *GHOps) CommentIssue(ctx context.Context, number int, body string) error {
+	out, err := g.runCmd(ctx, g.dir, "gh", "issue", "comment",
+		fmt.Sprintf("%d", number),
+		"--body", body,
+	)
+	if err != nil {
+		return fmt.Errorf("gh issue comment: %s: %w", out, err)
+	}
+
+	g.logger.Info("commented on issue", "number", number)
+	return nil
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+func TestGHOps_ListOpenIssues(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{
+		{out: `[{"number":7,"title":"Crash on login","body":"stack trace...","url":"https://github.com/org/repo/issues/7","labels":[{"name":"bug"}],"createdAt":"2026-03-01T10:00:00Z"}]`},
+	})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	issues, err := g.ListOpenIssues(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Number != 7 {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+	if !issues[0].HasLabel("BUG") {
+		t.Error("expected case-insensitive label match")
+	}
+}
+
+func TestGHOps_ListOpenIssues_Empty(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{{out: "[]"}})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	issues, err := g.ListOpenIssues(context.Background(), 20)
+	if err != nil || issues != nil {
+		t.Errorf("expected no issues, got %v %v", issues, err)
+	}
+}
+
+func TestGHOps_AddIssueLabels(t *testing.T) {
+	var gotArgs []string
+	runner := func(_ context.Context, _, _ string, args ...string) (string, error) {
+		gotArgs = args
+		return "", nil
+	}
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	if err := g.AddIssueLabels(context.Background(), 7, "bug", "complexity:simple"); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprint([]string{"issue", "edit", "7", "--add-label", "bug,complexity:simple"})
+	if fmt.Sprint(gotArgs) != want {
+		t.Errorf("got %v, want %s", gotArgs, want)
+	}
+}
+
+func TestGHOps_CommentIssue_Fails(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{{out: "not found", err: fmt.Errorf("exit 1")}})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	if err := g.CommentIssue(context.Background(), 7, "hi"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -100,6 +100,17 @@ func (g *GHOps) PRExists(ctx context.Context, head string) (*PRInfo, error) {
 	return &prs[0], nil
 }
 
+// HasOpenPR reports whether an open PR exists for the head branch. It
+// implements worktree.PRChecker, so the GC never removes a worktree whose
+// PR is still under review.
+func (g *GHOps) HasOpenPR(ctx context.Context, head string) (bool, error) {
+	pr, err := g.PRExists(ctx, head)
+	if err != nil {
+		return false, err
+	}
+	return pr != nil && strings.EqualFold(pr.State, "OPEN"), nil
+}
+
 // CreatePR creates a pull request.
 // Idempotent: if a PR already exists for the head branch, returns existing PR info.
 func (g *GHOps) CreatePR(ctx context.Context, input PRCreateInput) (*PRInfo, error) {
@@ -203,3 +214,24 @@ func (g *GHOps) PRStatus(ctx context.Context, number int) (*PRInfo, error) {
 
 	return &pr, nil
 }
+
+// PostReview submits a review on a pull request (number or URL): an
+// approval, or a change request when approve is false. GitHub rejects both
+// on the author's own PR, which is the common case when the bot opened it,
+// so that falls back to a comment review with the same body.
+func (g *GHOps) PostReview(ctx context.Context, pr string, approve bool, body string) error {
+	event := "--request-changes"
+	if approve {
+		event = "--approve"
+	}
+	out, err := g.runCmd(ctx, g.dir, "gh", "pr", "review", pr, event, "--body", body)
+	if err != nil && strings.Contains(out, "own pull request") {
+		g.logger.Info("cannot review own PR, posting review as a comment", "pr", pr)
+		out, err = g.runCmd(ctx, g.dir, "gh", "pr", "review", pr, "--comment", "--body", body)
+	}
+	if err != nil {
+		return fmt.Errorf("gh pr review: %s: %w", out, err)
+	}
+	g.logger.Info("posted PR review", "pr", pr, "approve", approve)
+	return nil
+}
@@ -74,6 +74,21 @@ func TestGHOps_PRExists_Error(t *testing.T) {
 	}
 }
 
+func TestGHOps_HasOpenPR(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{
+		{out: `[{"number":42,"state":"OPEN","headRefName":"codebutler/feat"}]`},
+		{out: "[]"},
+	})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner))
+
+	if open, err := g.HasOpenPR(context.Background(), "codebutler/feat"); err != nil || !open {
+		t.Errorf("open PR: open=%v err=%v", open, err)
+	}
+	if open, err := g.HasOpenPR(context.Background(), "codebutler/none"); err != nil || open {
+		t.Errorf("no PR: open=%v err=%v", open, err)
+	}
+}
+
 func TestGHOps_CreatePR_New(t *testing.T) {
 	runner, _ := newMockRunner([]mockCall{
 		// PRExists check
@@ -278,3 +293,30 @@ func TestGHOps_PRStatus_Fails(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestGHOps_PostReview_FallsBackToCommentOnOwnPR(t *testing.T) {
+	var calls [][]string
+	runner := func(_ context.Context, _, _ string, args ...string) (string, error) {
+		calls = append(calls, args)
+		if len(calls) == 1 {
+			return "failed to create review: Can not request changes on your own pull request", fmt.Errorf("exit status 1")
+		}
+		return "", nil
+	}
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	if err := g.PostReview(context.Background(), "https://github.com/org/repo/pull/7", false, "blocker: nil map"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 || calls[0][3] != "--request-changes" || calls[1][3] != "--comment" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestGHOps_PostReview_Error(t *testing.T) {
+	runner, _ := newMockRunner([]mockCall{{out: "not found", err: fmt.Errorf("exit status 1")}})
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+	if err := g.PostReview(context.Background(), "7", true, "LGTM"); err == nil {
+		t.Fatal("expected error")
+	}
+}
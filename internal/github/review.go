@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// reviewPayload is the body of POST /repos/{owner}/{repo}/pulls/{n}/reviews.
+type reviewPayload struct {
+	Event    string          `json:"event"` // APPROVE, REQUEST_CHANGES, COMMENT
+	Body     string          `json:"body"`
+	Comments []reviewComment `json:"comments,omitempty"`
+}
+
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+var prURLRe = regexp.MustCompile(`github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// reviewsEndpoint returns the REST path for a PR's reviews. A bare number
+// uses gh's {owner}/{repo} placeholders, which resolve to the repo in dir.
+func reviewsEndpoint(pr string) (string, error) {
+	if m := prURLRe.FindStringSubmatch(pr); m != nil {
+		return fmt.Sprintf("repos/%s/%s/pulls/%s/reviews", m[1], m[2], m[3]), nil
+	}
+	n := strings.TrimPrefix(strings.TrimSpace(pr), "#")
+	if n == "" || strings.Trim(n, "0123456789") != "" {
+		return "", fmt.Errorf("not a PR number or URL: %q", pr)
+	}
+	return "repos/{owner}/{repo}/pulls/" + n + "/reviews", nil
+}
+
+// PostInlineReview submits one batch review: the verdict and body plus
+// comments on specific lines of the diff. On the author's own PR it falls
+// back to a comment review like PostReview. When GitHub cannot anchor a
+// comment (the line is outside the diff), the comments are folded into the
+// body instead, so no feedback is lost.
+func (g *GHOps) PostInlineReview(ctx context.Context, pr string, approve bool, body string, comments []agent.InlineComment) error {
+	endpoint, err := reviewsEndpoint(pr)
+	if err != nil {
+		return err
+	}
+
+	payload := reviewPayload{Event: "REQUEST_CHANGES", Body: body}
+	if approve {
+		payload.Event = "APPROVE"
+	}
+	for _, c := range comments {
+		payload.Comments = append(payload.Comments, reviewComment{Path: c.Path, Line: c.Line, Side: "RIGHT", Body: c.Body})
+	}
+
+	out, err := g.postReviewPayload(ctx, endpoint, payload)
+	if err != nil && strings.Contains(out, "own pull request") {
+		g.logger.Info("cannot review own PR, posting review as a comment", "pr", pr)
+		payload.Event = "COMMENT"
+		out, err = g.postReviewPayload(ctx, endpoint, payload)
+	}
+	if err != nil && len(payload.Comments) > 0 && strings.Contains(out, "could not be resolved") {
+		g.logger.Info("inline comments outside the diff, folding them into the review body", "pr", pr)
+		payload.Body = body + "\n\n" + formatCommentList(comments)
+		payload.Comments = nil
+		out, err = g.postReviewPayload(ctx, endpoint, payload)
+	}
+	if err != nil {
+		return fmt.Errorf("gh api %s: %s: %w", endpoint, out, err)
+	}
+	g.logger.Info("posted inline PR review", "pr", pr, "approve", approve, "comments", len(payload.Comments))
+	return nil
+}
+
+// postReviewPayload sends payload through `gh api --input`, since the
+// runner has no stdin.
+func (g *GHOps) postReviewPayload(ctx context.Context, endpoint string, payload reviewPayload) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal review: %w", err)
+	}
+	f, err := os.CreateTemp("", "codebutler-review-*.json")
+	if err != nil {
+		return "", fmt.Errorf("create review payload: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", fmt.Errorf("write review payload: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write review payload: %w", err)
+	}
+	return g.runCmd(ctx, g.dir, "gh", "api", "--method", "POST", endpoint, "--input", f.Name())
+}
+
+func formatCommentList(comments []agent.InlineComment) string {
+	var b strings.Builder
+	for _, c := range comments {
+		fmt.Fprintf(&b, "- `%s:%d` %s\n", c.Path, c.Line, c.Body)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// payloadRunner records the endpoint and decoded --input payload of each
+// gh api call, failing the first len(failures) calls with those outputs.
+func payloadRunner(failures ...string) (CommandRunner, *[]string, *[]reviewPayload) {
+	var endpoints []string
+	var payloads []reviewPayload
+	return func(_ context.Context, _, _ string, args ...string) (string, error) {
+		endpoints = append(endpoints, args[3])
+		data, _ := os.ReadFile(args[5])
+		var p reviewPayload
+		json.Unmarshal(data, &p)
+		payloads = append(payloads, p)
+		if n := len(payloads); n <= len(failures) {
+			return failures[n-1], fmt.Errorf("exit status 1")
+		}
+		return "{}", nil
+	}, &endpoints, &payloads
+}
+
+func TestGHOps_PostInlineReview(t *testing.T) {
+	runner, endpoints, payloads := payloadRunner()
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	comments := []agent.InlineComment{{Path: "auth.go", Line: 42, Body: "**[security]** SQL injection (blocker)"}}
+	if err := g.PostInlineReview(context.Background(), "https://github.com/org/repo/pull/7", false, "Summary", comments); err != nil {
+		t.Fatal(err)
+	}
+	if (*endpoints)[0] != "repos/org/repo/pulls/7/reviews" {
+		t.Errorf("endpoint = %s", (*endpoints)[0])
+	}
+	p := (*payloads)[0]
+	if p.Event != "REQUEST_CHANGES" || len(p.Comments) != 1 || p.Comments[0].Line != 42 || p.Comments[0].Side != "RIGHT" {
+		t.Errorf("payload = %+v", p)
+	}
+}
+
+func TestGHOps_PostInlineReview_Fallbacks(t *testing.T) {
+	runner, endpoints, payloads := payloadRunner(
+		`{"message":"Unprocessable Entity","errors":["Can not request changes on your own pull request"]}`,
+		`{"message":"Unprocessable Entity","errors":["Line could not be resolved"]}`,
+	)
+	g := NewGHOps("/tmp/repo", WithGHCommandRunner(runner), WithGHLogger(slog.Default()))
+
+	comments := []agent.InlineComment{{Path: "auth.go", Line: 1, Body: "missing test"}}
+	if err := g.PostInlineReview(context.Background(), "12", false, "Summary", comments); err != nil {
+		t.Fatal(err)
+	}
+	if len(*payloads) != 3 || (*endpoints)[0] != "repos/{owner}/{repo}/pulls/12/reviews" {
+		t.Fatalf("calls = %v", *endpoints)
+	}
+	last := (*payloads)[2]
+	if last.Event != "COMMENT" || len(last.Comments) != 0 || last.Body != "Summary\n\n- `auth.go:1` missing test" {
+		t.Errorf("final payload = %+v", last)
+	}
+}
+
+func TestReviewsEndpoint_Invalid(t *testing.T) {
+	if _, err := reviewsEndpoint("feature-branch"); err == nil {
+		t.Error("expected error for a branch name")
+	}
+}
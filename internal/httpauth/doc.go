@@ -0,0 +1,3 @@
+// Package httpauth checks the shared bearer token the daemon's HTTP
+// endpoints (the API and the log stream) require.
+package httpauth
@@ -0,0 +1,25 @@
+package httpauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Bearer returns the token from an "Authorization: Bearer <token>" header.
+// ok is false when the header is missing or uses another scheme.
+func Bearer(r *http.Request) (token string, ok bool) {
+	return strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// Match compares got to the configured token in constant time. An empty
+// configured token never matches.
+func Match(got, want string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// Authorized reports whether r carries want as a bearer token.
+func Authorized(r *http.Request, want string) bool {
+	got, ok := Bearer(r)
+	return ok && Match(got, want)
+}
@@ -0,0 +1,32 @@
+package httpauth
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorized(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   bool
+	}{
+		{"Bearer secret", true},
+		{"Bearer wrong", false},
+		{"secret", false}, // no scheme
+		{"Basic secret", false},
+		{"", false},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		if got := Authorized(req, "secret"); got != tc.want {
+			t.Errorf("Authorization %q: got %v, want %v", tc.header, got, tc.want)
+		}
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	if Authorized(req, "") {
+		t.Error("an empty configured token must never match")
+	}
+}
@@ -0,0 +1,191 @@
+package initwiz
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/router"
+)
+
+// CheckItem is one line of the setup checklist.
+type CheckItem struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Optional bool   `json:"optional,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Fix      string `json:"fix,omitempty"` // what to do when !OK
+}
+
+// Checker inspects a machine and repo for everything CodeButler needs before
+// its first task, so missing pieces surface up front instead of at task time.
+type Checker struct {
+	homeDir string
+	repoDir string
+
+	lookPath func(string) (string, error)
+	run      func(ctx context.Context, name string, args ...string) error
+}
+
+// NewChecker creates a checker for the given home and repo directories.
+func NewChecker(homeDir, repoDir string) *Checker {
+	return &Checker{
+		homeDir:  homeDir,
+		repoDir:  repoDir,
+		lookPath: exec.LookPath,
+		run: func(ctx context.Context, name string, args ...string) error {
+			return exec.CommandContext(ctx, name, args...).Run()
+		},
+	}
+}
+
+// Run executes every check in display order.
+func (c *Checker) Run(ctx context.Context) []CheckItem {
+	var items []CheckItem
+	items = append(items, c.checkGlobal()...)
+	items = append(items, c.checkRepo()...)
+	items = append(items, c.checkBinary("git", "git installed", false, "install git from https://git-scm.com"))
+	gh := c.checkBinary("gh", "gh CLI installed", false, "install the GitHub CLI from https://cli.github.com")
+	items = append(items, gh)
+	if gh.OK {
+		items = append(items, c.checkGHAuth(ctx))
+	}
+	return items
+}
+
+func (c *Checker) checkGlobal() []CheckItem {
+	globalDir := filepath.Join(c.homeDir, codebutlerDir)
+	path := filepath.Join(globalDir, "config.json")
+	g, err := config.LoadGlobal(globalDir)
+	if err != nil {
+		return []CheckItem{{
+			Name:   "global config",
+			Detail: err.Error(),
+			Fix:    "run `codebutler init` to create " + path,
+		}}
+	}
+
+	return []CheckItem{
+		{Name: "global config", OK: true, Detail: path},
+		secretItem("Slack bot token", g.Slack.BotToken, false, "set slack.botToken (xoxb-...) in "+path),
+		secretItem("Slack app token", g.Slack.AppToken, false, "set slack.appToken (xapp-...) in "+path),
+		secretItem("OpenRouter API key", g.OpenRouter.APIKey, false, "set openrouter.apiKey in "+path),
+		secretItem("OpenAI API key", g.OpenAI.APIKey, true, "set openai.apiKey in "+path+" to enable images and transcription"),
+	}
+}
+
+func (c *Checker) checkRepo() []CheckItem {
+	path := filepath.Join(c.repoDir, codebutlerDir, "config.json")
+	r, err := config.LoadRepo(c.repoDir)
+	if err != nil {
+		return []CheckItem{{
+			Name:   "repo config",
+			Detail: err.Error(),
+			Fix:    "run `codebutler init` in the repo root",
+		}}
+	}
+
+	channel := CheckItem{Name: "Slack channel", OK: r.Slack.ChannelID != "", Detail: r.Slack.ChannelName}
+	if !channel.OK {
+		channel.Fix = "set slack.channelID in " + path
+	}
+
+	items := []CheckItem{{Name: "repo config", OK: true, Detail: path}, channel}
+	if len(r.Mentions) > 0 {
+		items = append(items, checkMentions(r.Mentions, path))
+	}
+	if missing := Validate(c.homeDir, c.repoDir); len(missing) > 0 {
+		items = append(items, CheckItem{
+			Name:   "agent MDs",
+			Detail: strings.Join(missing, "; "),
+			Fix:    "re-run `codebutler init` or restore the missing files",
+		})
+	} else {
+		items = append(items, CheckItem{Name: "agent MDs", OK: true})
+	}
+	return items
+}
+
+// checkMentions validates configured handoff targets so a typo surfaces at
+// setup instead of as a delegation nobody answers.
+func checkMentions(mentions []config.RepoMention, path string) CheckItem {
+	targets := make([]router.MentionTarget, len(mentions))
+	for i, m := range mentions {
+		targets[i] = router.MentionTarget{Handle: m.Handle, Role: m.Role, UserID: m.UserID, Description: m.Description}
+	}
+	if _, err := router.NewMentionRegistry(targets...); err != nil {
+		return CheckItem{Name: "mention targets", Detail: err.Error(), Fix: "fix mentions in " + path}
+	}
+	return CheckItem{Name: "mention targets", OK: true, Detail: fmt.Sprintf("%d custom", len(mentions))}
+}
+
+func (c *Checker) checkBinary(bin, name string, optional bool, fix string) CheckItem {
+	path, err := c.lookPath(bin)
+	if err != nil {
+		return CheckItem{Name: name, Optional: optional, Detail: bin + " not found in PATH", Fix: fix}
+	}
+	return CheckItem{Name: name, OK: true, Optional: optional, Detail: path}
+}
+
+func (c *Checker) checkGHAuth(ctx context.Context) CheckItem {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := c.run(ctx, "gh", "auth", "status"); err != nil {
+		return CheckItem{Name: "gh authenticated", Detail: err.Error(), Fix: "run `gh auth login`"}
+	}
+	return CheckItem{Name: "gh authenticated", OK: true}
+}
+
+func secretItem(name, value string, optional bool, fix string) CheckItem {
+	if value == "" {
+		return CheckItem{Name: name, Optional: optional, Fix: fix}
+	}
+	return CheckItem{Name: name, OK: true, Optional: optional}
+}
+
+// Ready reports whether every required item passed.
+func Ready(items []CheckItem) bool {
+	return MissingRequired(items) == 0
+}
+
+// MissingRequired counts the required items that failed.
+func MissingRequired(items []CheckItem) int {
+	n := 0
+	for _, it := range items {
+		if !it.OK && !it.Optional {
+			n++
+		}
+	}
+	return n
+}
+
+// FormatChecklist renders the checklist with a ✓/✗ per item and the fix for
+// each failing one.
+func FormatChecklist(items []CheckItem) string {
+	var b strings.Builder
+	for _, it := range items {
+		mark := "✓"
+		if !it.OK {
+			mark = "✗"
+			if it.Optional {
+				mark = "–"
+			}
+		}
+		line := fmt.Sprintf("  %s %s", mark, it.Name)
+		if it.Optional && !it.OK {
+			line += " (optional)"
+		}
+		if it.Detail != "" {
+			line += "  " + it.Detail
+		}
+		b.WriteString(line + "\n")
+		if !it.OK && it.Fix != "" {
+			fmt.Fprintf(&b, "      → %s\n", it.Fix)
+		}
+	}
+	return b.String()
+}
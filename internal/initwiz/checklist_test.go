@@ -0,0 +1,123 @@
+package initwiz
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fakeChecker(homeDir, repoDir string, missing map[string]bool, ghAuthErr error) *Checker {
+	c := NewChecker(homeDir, repoDir)
+	c.lookPath = func(bin string) (string, error) {
+		if missing[bin] {
+			return "", fmt.Errorf("not found")
+		}
+		return "/usr/bin/" + bin, nil
+	}
+	c.run = func(ctx context.Context, name string, args ...string) error { return ghAuthErr }
+	return c
+}
+
+func itemByName(items []CheckItem, name string) (CheckItem, bool) {
+	for _, it := range items {
+		if it.Name == name {
+			return it, true
+		}
+	}
+	return CheckItem{}, false
+}
+
+func TestChecker_FreshInit(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir := t.TempDir()
+	if _, err := NewWizard(homeDir, repoDir, &mockPrompter{}).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	items := fakeChecker(homeDir, repoDir, map[string]bool{"gh": true}, nil).Run(context.Background())
+
+	if Ready(items) {
+		t.Error("fresh init has empty tokens and should not be ready")
+	}
+	if it, _ := itemByName(items, "global config"); !it.OK {
+		t.Error("global config should exist after init")
+	}
+	if it, _ := itemByName(items, "Slack bot token"); it.OK || it.Fix == "" {
+		t.Errorf("empty bot token should fail with a fix: %+v", it)
+	}
+	if it, _ := itemByName(items, "OpenAI API key"); !it.Optional {
+		t.Error("OpenAI key should be optional")
+	}
+	if _, ok := itemByName(items, "gh authenticated"); ok {
+		t.Error("auth check should be skipped when gh is missing")
+	}
+
+	out := FormatChecklist(items)
+	for _, want := range []string{"✗ Slack bot token", "✓ agent MDs", "– OpenAI API key (optional)", "→ install the GitHub CLI"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("checklist missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestChecker_AllGood(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir := t.TempDir()
+	if _, err := NewWizard(homeDir, repoDir, &mockPrompter{}).Run(); err != nil {
+		t.Fatal(err)
+	}
+	writeJSON(filepath.Join(homeDir, codebutlerDir, "config.json"), map[string]any{
+		"slack":      map[string]string{"botToken": "xoxb-1", "appToken": "xapp-1"},
+		"openrouter": map[string]string{"apiKey": "or-key"},
+	}, 0600)
+	writeJSON(filepath.Join(repoDir, codebutlerDir, "config.json"), map[string]any{
+		"slack": map[string]string{"channelID": "C1", "channelName": "dev"},
+	}, 0644)
+
+	items := fakeChecker(homeDir, repoDir, nil, nil).Run(context.Background())
+	if !Ready(items) {
+		t.Errorf("expected ready:\n%s", FormatChecklist(items))
+	}
+	if MissingRequired(items) != 0 {
+		t.Errorf("missing = %d", MissingRequired(items))
+	}
+
+	items = fakeChecker(homeDir, repoDir, nil, fmt.Errorf("exit status 1")).Run(context.Background())
+	if it, _ := itemByName(items, "gh authenticated"); it.OK || Ready(items) {
+		t.Error("failed gh auth should block readiness")
+	}
+}
+
+func TestChecker_NoConfig(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir := t.TempDir()
+	os.MkdirAll(filepath.Join(repoDir, codebutlerDir), 0755)
+
+	items := fakeChecker(homeDir, repoDir, nil, nil).Run(context.Background())
+	for _, name := range []string{"global config", "repo config"} {
+		if it, _ := itemByName(items, name); it.OK || !strings.Contains(it.Fix, "codebutler init") {
+			t.Errorf("%s: %+v", name, it)
+		}
+	}
+}
+
+func TestChecker_MentionTargets(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir := t.TempDir()
+	if _, err := NewWizard(homeDir, repoDir, &mockPrompter{}).Run(); err != nil {
+		t.Fatal(err)
+	}
+	writeJSON(filepath.Join(repoDir, codebutlerDir, "config.json"), map[string]any{
+		"slack":    map[string]string{"channelID": "C1"},
+		"mentions": []map[string]string{{"handle": "codebutler.security"}, {"handle": "maria"}},
+	}, 0644)
+
+	items := fakeChecker(homeDir, repoDir, nil, nil).Run(context.Background())
+	it, ok := itemByName(items, "mention targets")
+	if !ok || it.OK || !strings.Contains(it.Detail, "@maria") {
+		t.Errorf("expected invalid target: %+v", it)
+	}
+}
@@ -0,0 +1,222 @@
+package initwiz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/store"
+)
+
+// HTTPDoer is the subset of *http.Client the key checks use.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Default provider API roots for key verification.
+const (
+	DefaultSlackAPI      = "https://slack.com/api"
+	DefaultOpenRouterAPI = "https://openrouter.ai/api/v1"
+	DefaultOpenAIAPI     = "https://api.openai.com/v1"
+)
+
+// KeyVerifier checks configured credentials against their providers with
+// one cheap call each, so a revoked or mistyped key fails at setup instead
+// of on the first message. Every call is read-only except the Slack app
+// token check; see Verify.
+type KeyVerifier struct {
+	client        HTTPDoer
+	slackAPI      string
+	openRouterAPI string
+	openAIAPI     string
+}
+
+// VerifierOption configures a KeyVerifier.
+type VerifierOption func(*KeyVerifier)
+
+// WithHTTPClient replaces the HTTP client.
+func WithHTTPClient(c HTTPDoer) VerifierOption {
+	return func(v *KeyVerifier) {
+		v.client = c
+	}
+}
+
+// WithAPIBases points the checks at other API roots; empty keeps the
+// default.
+func WithAPIBases(slack, openRouter, openAI string) VerifierOption {
+	return func(v *KeyVerifier) {
+		if slack != "" {
+			v.slackAPI = slack
+		}
+		if openRouter != "" {
+			v.openRouterAPI = openRouter
+		}
+		if openAI != "" {
+			v.openAIAPI = openAI
+		}
+	}
+}
+
+// NewKeyVerifier creates a verifier that talks to the public APIs.
+func NewKeyVerifier(opts ...VerifierOption) *KeyVerifier {
+	v := &KeyVerifier{
+		client:        &http.Client{Timeout: 15 * time.Second},
+		slackAPI:      DefaultSlackAPI,
+		openRouterAPI: DefaultOpenRouterAPI,
+		openAIAPI:     DefaultOpenAIAPI,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify checks every key set in g. Unset keys are skipped; missing
+// required ones are reported by the config checks.
+//
+// The app token is checked with apps.connections.open, the call Socket
+// Mode makes at startup, so a token missing connections:write fails here
+// too. As a side effect the call mints a Socket Mode WebSocket URL. The
+// URL is never dialed, so no connection opens and no events are routed to
+// it; Slack expires it unused after a few seconds.
+func (v *KeyVerifier) Verify(ctx context.Context, g *config.GlobalConfig) []CheckItem {
+	var items []CheckItem
+	if g.Slack.BotToken != "" {
+		items = append(items, v.slack(ctx, "Slack bot token works", "auth.test", g.Slack.BotToken,
+			"check slack.botToken: it must be the Bot User OAuth Token (xoxb-...) of an installed app"))
+	}
+	if g.Slack.AppToken != "" {
+		items = append(items, v.slack(ctx, "Slack app token works", "apps.connections.open", g.Slack.AppToken,
+			"check slack.appToken: it must be an app-level token (xapp-...) with connections:write and Socket Mode enabled"))
+	}
+	if g.OpenRouter.APIKey != "" {
+		items = append(items, v.bearer(ctx, "OpenRouter API key works", v.openRouterAPI+"/key", g.OpenRouter.APIKey, false,
+			"check openrouter.apiKey or create a new key at https://openrouter.ai/keys"))
+	}
+	if g.OpenAI.APIKey != "" {
+		items = append(items, v.bearer(ctx, "OpenAI API key works", v.openAIAPI+"/models", g.OpenAI.APIKey, true,
+			"check openai.apiKey or create a new key at https://platform.openai.com/api-keys"))
+	}
+	return items
+}
+
+// slack calls a Web API method. Slack answers 200 with ok=false for bad
+// tokens, so the body decides.
+func (v *KeyVerifier) slack(ctx context.Context, name, method, token, fix string) CheckItem {
+	var out struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		Team  string `json:"team"`
+		User  string `json:"user"`
+	}
+	item := CheckItem{Name: name, Fix: fix}
+	body, status, err := v.do(ctx, http.MethodPost, v.slackAPI+"/"+method, token)
+	if err != nil {
+		item.Detail = err.Error()
+		return item
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		item.Detail = fmt.Sprintf("unexpected response (HTTP %d)", status)
+		return item
+	}
+	if !out.OK {
+		item.Detail = "Slack says: " + out.Error
+		return item
+	}
+	item.OK = true
+	if out.Team != "" {
+		item.Detail = out.User + " @ " + out.Team
+	}
+	return item
+}
+
+func (v *KeyVerifier) bearer(ctx context.Context, name, url, key string, optional bool, fix string) CheckItem {
+	item := CheckItem{Name: name, Optional: optional, Fix: fix}
+	_, status, err := v.do(ctx, http.MethodGet, url, key)
+	switch {
+	case err != nil:
+		item.Detail = err.Error()
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		item.Detail = fmt.Sprintf("key rejected (HTTP %d)", status)
+	case status >= 300:
+		item.Detail = fmt.Sprintf("unexpected HTTP %d", status)
+	default:
+		item.OK = true
+	}
+	return item
+}
+
+func (v *KeyVerifier) do(ctx context.Context, method, url, token string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return body, resp.StatusCode, err
+}
+
+// ValidateConfig checks both config files in depth: syntax, unknown or
+// mistyped fields, unset ${VAR} references, required fields, and, when
+// verifier is non-nil, whether each key is accepted by its provider. It
+// ends with the tools agents shell out to.
+func (c *Checker) ValidateConfig(ctx context.Context, verifier *KeyVerifier) []CheckItem {
+	globalPath := filepath.Join(c.homeDir, codebutlerDir, "config.json")
+	g, globalIssues := config.CheckGlobal(filepath.Dir(globalPath))
+	r, repoIssues := config.CheckRepo(c.repoDir)
+
+	items := configItems("global config", globalPath, g != nil, globalIssues)
+	items = append(items, configItems("repo config", filepath.Join(c.repoDir, codebutlerDir, "config.json"), r != nil, repoIssues)...)
+
+	if g != nil && g.Store != nil {
+		items = append(items, storeItem(g.Store))
+	}
+	if g != nil && verifier != nil {
+		items = append(items, verifier.Verify(ctx, g)...)
+	}
+	items = append(items, c.checkBinary("git", "git installed", false, "install git from https://git-scm.com"))
+	items = append(items, c.checkBinary("gh", "gh CLI installed", false, "install the GitHub CLI from https://cli.github.com"))
+	return items
+}
+
+// storeItem reports whether this binary can open the configured store
+// backend; the default build has no SQL drivers.
+func storeItem(cfg *config.GlobalStore) CheckItem {
+	item := CheckItem{Name: "store backend available", Detail: cfg.Backend}
+	if err := store.CheckDriver(cfg); err != nil {
+		item.Detail = err.Error()
+		item.Fix = "rebuild with the backend's build tag, or set store.backend to \"file\" or \"memory\""
+		return item
+	}
+	item.OK = true
+	return item
+}
+
+// configItems reports one file: a header line, then a line per issue
+// named after its field. A file that could not be loaded is one failing
+// line carrying the reason.
+func configItems(name, path string, loaded bool, issues []config.Issue) []CheckItem {
+	if !loaded {
+		last := issues[len(issues)-1]
+		return []CheckItem{{Name: name, Detail: path + ": " + last.Message, Fix: last.Fix}}
+	}
+	items := []CheckItem{{Name: name, OK: true, Detail: path}}
+	for _, i := range issues {
+		field := i.Field
+		if field == "" {
+			field = "(file)"
+		}
+		items = append(items, CheckItem{Name: name + ": " + field, Detail: i.Message, Fix: i.Fix})
+	}
+	return items
+}
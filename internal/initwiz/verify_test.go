@@ -0,0 +1,142 @@
+package initwiz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// fakeProviders accepts only the keys named "good-*".
+func fakeProviders(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		good := strings.HasPrefix(r.Header.Get("Authorization"), "Bearer good-")
+		switch r.URL.Path {
+		case "/slack/auth.test", "/slack/apps.connections.open":
+			if r.Method != http.MethodPost {
+				t.Errorf("%s %s", r.Method, r.URL.Path)
+			}
+			if good {
+				w.Write([]byte(`{"ok":true,"team":"Acme","user":"codebutler"}`))
+			} else {
+				w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+			}
+		case "/openrouter/key", "/openai/models":
+			if !good {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testVerifier(srv *httptest.Server) *KeyVerifier {
+	return NewKeyVerifier(
+		WithHTTPClient(srv.Client()),
+		WithAPIBases(srv.URL+"/slack", srv.URL+"/openrouter", srv.URL+"/openai"),
+	)
+}
+
+func TestKeyVerifier(t *testing.T) {
+	v := testVerifier(fakeProviders(t))
+	g := &config.GlobalConfig{
+		Slack:      config.GlobalSlack{BotToken: "good-bot", AppToken: "bad-app"},
+		OpenRouter: config.GlobalOpenRouter{APIKey: "good-or"},
+		OpenAI:     config.GlobalOpenAI{APIKey: "bad-openai"},
+	}
+	items := v.Verify(context.Background(), g)
+	if len(items) != 4 {
+		t.Fatalf("items = %+v", items)
+	}
+
+	if it, _ := itemByName(items, "Slack bot token works"); !it.OK || it.Detail != "codebutler @ Acme" {
+		t.Errorf("bot: %+v", it)
+	}
+	if it, _ := itemByName(items, "Slack app token works"); it.OK || !strings.Contains(it.Detail, "invalid_auth") || !strings.Contains(it.Fix, "xapp") {
+		t.Errorf("app: %+v", it)
+	}
+	if it, _ := itemByName(items, "OpenRouter API key works"); !it.OK {
+		t.Errorf("openrouter: %+v", it)
+	}
+	if it, _ := itemByName(items, "OpenAI API key works"); it.OK || !it.Optional || !strings.Contains(it.Detail, "401") {
+		t.Errorf("openai: %+v", it)
+	}
+	if MissingRequired(items) != 1 {
+		t.Errorf("missing = %d, want 1 (optional OpenAI does not count)", MissingRequired(items))
+	}
+
+	if items := v.Verify(context.Background(), &config.GlobalConfig{}); len(items) != 0 {
+		t.Errorf("unset keys should be skipped: %+v", items)
+	}
+}
+
+func TestKeyVerifier_Unreachable(t *testing.T) {
+	srv := fakeProviders(t)
+	v := testVerifier(srv)
+	srv.Close()
+	items := v.Verify(context.Background(), &config.GlobalConfig{OpenRouter: config.GlobalOpenRouter{APIKey: "good-or"}})
+	if len(items) != 1 || items[0].OK || items[0].Detail == "" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestChecker_ValidateConfig(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir := t.TempDir()
+	globalDir := filepath.Join(homeDir, codebutlerDir)
+	os.MkdirAll(globalDir, 0o755)
+	os.WriteFile(filepath.Join(globalDir, "config.json"), []byte(`{
+  "slack": {"botToken": "good-bot", "appToken": "good-app"},
+  "openrouter": {"apiKey": "bad-or"},
+  "slak": {}
+}`), 0o600)
+
+	c := fakeChecker(homeDir, repoDir, map[string]bool{"gh": true}, nil)
+	items := c.ValidateConfig(context.Background(), testVerifier(fakeProviders(t)))
+
+	if it, _ := itemByName(items, "global config"); !it.OK {
+		t.Errorf("global header: %+v", it)
+	}
+	if it, _ := itemByName(items, "global config: slak"); it.OK || it.Detail != "unknown field" {
+		t.Errorf("unknown field: %+v", it)
+	}
+	if it, _ := itemByName(items, "repo config"); it.OK || !strings.Contains(it.Detail, "file not found") || !strings.Contains(it.Fix, "codebutler init") {
+		t.Errorf("repo header: %+v", it)
+	}
+	if it, _ := itemByName(items, "OpenRouter API key works"); it.OK {
+		t.Errorf("openrouter: %+v", it)
+	}
+	if it, _ := itemByName(items, "gh CLI installed"); it.OK {
+		t.Errorf("gh: %+v", it)
+	}
+	// slak, repo config, OpenRouter key, gh.
+	if n := MissingRequired(items); n != 4 {
+		t.Errorf("missing = %d:\n%s", n, FormatChecklist(items))
+	}
+
+	offline := c.ValidateConfig(context.Background(), nil)
+	if _, ok := itemByName(offline, "Slack bot token works"); ok {
+		t.Error("nil verifier should skip key checks")
+	}
+}
+
+func TestStoreItem(t *testing.T) {
+	if it := storeItem(&config.GlobalStore{Backend: "memory"}); !it.OK {
+		t.Errorf("memory: %+v", it)
+	}
+	it := storeItem(&config.GlobalStore{Backend: "postgres", DSN: "postgres://db/cb"})
+	if it.OK || !strings.Contains(it.Detail, "-tags postgres") || it.Fix == "" {
+		t.Errorf("postgres without driver: %+v", it)
+	}
+}
@@ -360,6 +360,20 @@ func updateGitignore(repoDir string) error {
 	entries := []string{
 		".codebutler/branches/",
 		".codebutler/images/",
+		".codebutler/crash/",
+		".codebutler/slack/",
+		".codebutler/outbox/",
+		".codebutler/runs/",
+		".codebutler/calls/",
+		".codebutler/audit.jsonl",
+		".codebutler/store.db*",
+		".codebutler/feedback.jsonl",
+		".codebutler/knowledge.jsonl",
+		".codebutler/results/",
+		".codebutler/tasks.json",
+		".codebutler/schedules.json",
+		".codebutler/phases.json",
+		".codebutler/workflows.json",
 	}
 
 	var toAdd []string
@@ -0,0 +1,7 @@
+// Package injection guards prompts against instructions smuggled in through
+// external content — fetched web pages, search results, HTTP responses,
+// GitHub issue and PR text. Content is scanned for instruction-like
+// patterns, wrapped in explicit untrusted-content delimiters so the model
+// treats it as data, and suspicious hits are logged and surfaced in the
+// thread.
+package injection
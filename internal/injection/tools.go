@@ -0,0 +1,55 @@
+package injection
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// ExternalTools return content from outside the repo and the team.
+var ExternalTools = []string{"WebFetch", "WebSearch", "HTTPRequest"}
+
+// Tools wraps the results of external tools before they reach the model.
+type Tools struct {
+	agent.ToolExecutor
+	filter   *Filter
+	channel  string
+	thread   string
+	external map[string]bool
+}
+
+// WrapTools returns e with ExternalTools results filtered for the thread.
+func (f *Filter) WrapTools(e agent.ToolExecutor, channel, thread string) *Tools {
+	external := make(map[string]bool, len(ExternalTools))
+	for _, name := range ExternalTools {
+		external[name] = true
+	}
+	return &Tools{ToolExecutor: e, filter: f, channel: channel, thread: thread, external: external}
+}
+
+// Execute implements agent.ToolExecutor.
+func (t *Tools) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	result, err := t.ToolExecutor.Execute(ctx, call)
+	if err != nil || result.IsError || !t.external[call.Name] {
+		return result, err
+	}
+	result.Content = t.filter.Check(ctx, t.channel, t.thread, source(call), result.Content)
+	return result, nil
+}
+
+// source labels a call by its URL or query when it has one.
+func source(call agent.ToolCall) string {
+	var args struct {
+		URL   string `json:"url"`
+		Query string `json:"query"`
+	}
+	_ = json.Unmarshal([]byte(call.Arguments), &args)
+	switch {
+	case args.URL != "":
+		return call.Name + " " + args.URL
+	case args.Query != "":
+		return call.Name + " " + args.Query
+	}
+	return call.Name
+}
@@ -0,0 +1,6 @@
+// Package knowledge keeps a searchable record of past solutions. When a
+// thread completes successfully a short "problem → approach → files
+// touched" record is appended; before a new task starts, the closest past
+// records are retrieved and injected as context so agents don't rediscover
+// the same solution twice.
+package knowledge
@@ -0,0 +1,96 @@
+package knowledge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatContext renders search matches as a prompt section injected before
+// a new task. Returns "" when there are no matches.
+func FormatContext(matches []Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Similar Past Solutions\n\n")
+	b.WriteString("These past threads solved related problems. Reuse the approach if it fits; verify file paths still exist.\n\n")
+	for i, m := range matches {
+		b.WriteString(fmt.Sprintf("%d. **Problem:** %s\n", i+1, m.Record.Problem))
+		b.WriteString(fmt.Sprintf("   **Approach:** %s\n", m.Record.Approach))
+		if len(m.Record.Files) > 0 {
+			b.WriteString(fmt.Sprintf("   **Files:** %s\n", strings.Join(m.Record.Files, ", ")))
+		}
+	}
+	return b.String()
+}
+
+// FormatExtractionPrompt asks a model to distill a completed thread into a
+// record. The model must answer in the Problem/Approach/Files format parsed
+// by ParseRecord.
+func FormatExtractionPrompt(threadSummary string, filesChanged []string) string {
+	var b strings.Builder
+	b.WriteString("Summarize this completed task for a knowledge base of past solutions.\n\n")
+	b.WriteString("### Thread\n\n")
+	b.WriteString(threadSummary)
+	b.WriteString("\n\n")
+	if len(filesChanged) > 0 {
+		b.WriteString("### Files Changed\n\n")
+		for _, f := range filesChanged {
+			b.WriteString("- " + f + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Reply with exactly three lines:\n")
+	b.WriteString("Problem: <one sentence: what was wrong or needed>\n")
+	b.WriteString("Approach: <one or two sentences: how it was solved>\n")
+	b.WriteString("Files: <comma-separated key files>\n")
+	return b.String()
+}
+
+// ParseRecord extracts a record from a model reply in the format requested
+// by FormatExtractionPrompt. Field labels are case-insensitive and may be
+// bolded. filesFallback is used when the reply lists no files.
+func ParseRecord(threadID, reply string, filesFallback []string) (Record, error) {
+	r := Record{ThreadID: threadID}
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "-* "))
+		label, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.Trim(value, "* "))
+		switch strings.ToLower(strings.Trim(label, "* ")) {
+		case "problem":
+			r.Problem = value
+		case "approach":
+			r.Approach = value
+		case "files":
+			for _, f := range strings.Split(value, ",") {
+				if f = strings.Trim(strings.TrimSpace(f), "`"); f != "" {
+					r.Files = append(r.Files, f)
+				}
+			}
+		}
+	}
+
+	if len(r.Files) == 0 {
+		r.Files = filesFallback
+	}
+	if r.Problem == "" || r.Approach == "" {
+		return r, fmt.Errorf("reply is missing problem or approach")
+	}
+	return r, nil
+}
+
+// ContextSource returns a lookup for agent.WithPastSolutions that formats
+// the top k matches for a query. Read errors yield no context.
+func ContextSource(store *Store, k int) func(query string) string {
+	return func(query string) string {
+		matches, err := store.Search(query, k)
+		if err != nil {
+			return ""
+		}
+		return FormatContext(matches)
+	}
+}
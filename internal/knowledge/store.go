@@ -0,0 +1,207 @@
+package knowledge
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Record is one solved problem.
+type Record struct {
+	ThreadID  string    `json:"thread_id"`
+	Problem   string    `json:"problem"`
+	Approach  string    `json:"approach"`
+	Files     []string  `json:"files,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Match is a search hit.
+type Match struct {
+	Record Record
+	Score  float64
+}
+
+// Store is an append-only JSONL knowledge base. Thread-safe.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// DefaultPath returns the knowledge base path for a repo.
+func DefaultPath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "knowledge.jsonl")
+}
+
+// NewStore creates a store backed by path.
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// Add appends a record. Records with an empty problem or approach are
+// rejected: they would only add noise to retrieval.
+func (s *Store) Add(r Record) error {
+	r.Problem = strings.TrimSpace(r.Problem)
+	r.Approach = strings.TrimSpace(r.Approach)
+	if r.Problem == "" || r.Approach == "" {
+		return fmt.Errorf("knowledge record needs both problem and approach")
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = s.now()
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create knowledge dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open knowledge base: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return nil
+}
+
+// All returns every record. A missing file returns none.
+func (s *Store) All() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open knowledge base: %w", err)
+	}
+	defer f.Close()
+
+	var out []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, scanner.Err()
+}
+
+// Search returns the top k records most similar to query, scored with
+// BM25 over problem, approach, and file paths. Records scoring zero are
+// never returned.
+func (s *Store) Search(query string, k int) ([]Match, error) {
+	records, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	return rank(records, query, k), nil
+}
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+func rank(records []Record, query string, k int) []Match {
+	qTerms := uniq(tokenize(query))
+	if len(records) == 0 || len(qTerms) == 0 {
+		return nil
+	}
+
+	docs := make([][]string, len(records))
+	df := make(map[string]int)
+	var totalLen int
+	for i, r := range records {
+		docs[i] = tokenize(r.Problem + " " + r.Approach + " " + strings.Join(r.Files, " "))
+		totalLen += len(docs[i])
+		for _, t := range uniq(docs[i]) {
+			df[t]++
+		}
+	}
+	avgLen := float64(totalLen) / float64(len(records))
+	n := float64(len(records))
+
+	var matches []Match
+	for i, doc := range docs {
+		tf := make(map[string]int)
+		for _, t := range doc {
+			tf[t]++
+		}
+		var score float64
+		for _, q := range qTerms {
+			f := float64(tf[q])
+			if f == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-float64(df[q])+0.5)/(float64(df[q])+0.5))
+			score += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*float64(len(doc))/avgLen))
+		}
+		if score > 0 {
+			matches = append(matches, Match{Record: records[i], Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// stopwords are dropped from queries and documents.
+var stopwords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true, "to": true, "of": true,
+	"in": true, "on": true, "for": true, "with": true, "is": true, "it": true, "this": true,
+	"that": true, "be": true, "are": true, "was": true, "we": true, "i": true, "from": true,
+	"by": true, "as": true, "at": true, "add": true, "fix": true, "make": true, "use": true,
+}
+
+// tokenize lowercases and splits on non-alphanumerics, so file paths like
+// internal/auth/login.go contribute "internal", "auth", "login", "go".
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len(f) > 1 && !stopwords[f] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func uniq(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var out []string
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package knowledge
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return NewStore(filepath.Join(t.TempDir(), "knowledge.jsonl"))
+}
+
+func seed(t *testing.T, s *Store) {
+	t.Helper()
+	records := []Record{
+		{ThreadID: "t1", Problem: "Login fails with expired JWT tokens", Approach: "Refresh the token in the auth middleware before validation", Files: []string{"internal/auth/middleware.go"}},
+		{ThreadID: "t2", Problem: "Slow dashboard query on orders table", Approach: "Added a composite index on (user_id, created_at)", Files: []string{"migrations/0042_orders_index.sql"}},
+		{ThreadID: "t3", Problem: "Flaky websocket reconnect test", Approach: "Injected a fake clock instead of sleeping", Files: []string{"internal/ws/client_test.go"}},
+	}
+	for _, r := range records {
+		if err := s.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStore_Search(t *testing.T) {
+	s := newTestStore(t)
+	seed(t, s)
+
+	matches, err := s.Search("users get logged out when the JWT expires", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 || matches[0].Record.ThreadID != "t1" {
+		t.Fatalf("expected t1 first, got %+v", matches)
+	}
+
+	matches, _ = s.Search("orders index migration", 1)
+	if len(matches) != 1 || matches[0].Record.ThreadID != "t2" {
+		t.Errorf("expected t2, got %+v", matches)
+	}
+}
+
+func TestStore_SearchMatchesFilePaths(t *testing.T) {
+	s := newTestStore(t)
+	seed(t, s)
+
+	matches, _ := s.Search("change something in middleware.go", 3)
+	if len(matches) == 0 || matches[0].Record.ThreadID != "t1" {
+		t.Errorf("file path tokens should match, got %+v", matches)
+	}
+}
+
+func TestStore_SearchNoMatch(t *testing.T) {
+	s := newTestStore(t)
+	seed(t, s)
+
+	matches, _ := s.Search("kubernetes helm chart", 3)
+	if len(matches) != 0 {
+		t.Errorf("expected no matches, got %+v", matches)
+	}
+}
+
+func TestStore_Empty(t *testing.T) {
+	matches, err := newTestStore(t).Search("anything", 3)
+	if err != nil || matches != nil {
+		t.Errorf("expected nothing, got %v %v", matches, err)
+	}
+}
+
+func TestStore_RejectsIncomplete(t *testing.T) {
+	if err := newTestStore(t).Add(Record{Problem: "x"}); err == nil {
+		t.Error("expected error for missing approach")
+	}
+}
+
+func TestFormatContext(t *testing.T) {
+	out := FormatContext([]Match{{Record: Record{Problem: "P", Approach: "A", Files: []string{"a.go", "b.go"}}}})
+	for _, want := range []string{"Similar Past Solutions", "**Problem:** P", "**Approach:** A", "a.go, b.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in %s", want, out)
+		}
+	}
+	if FormatContext(nil) != "" {
+		t.Error("no matches should render nothing")
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	reply := "**Problem:** Login fails on expired tokens\n**Approach:** Refresh in middleware\nFiles: `internal/auth/middleware.go`, internal/auth/jwt.go"
+	r, err := ParseRecord("t9", reply, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Problem != "Login fails on expired tokens" || r.Approach != "Refresh in middleware" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if len(r.Files) != 2 || r.Files[0] != "internal/auth/middleware.go" {
+		t.Errorf("unexpected files: %v", r.Files)
+	}
+
+	r, _ = ParseRecord("t9", "Problem: p\nApproach: a", []string{"x.go"})
+	if len(r.Files) != 1 || r.Files[0] != "x.go" {
+		t.Errorf("fallback files not used: %v", r.Files)
+	}
+
+	if _, err := ParseRecord("t9", "nothing useful", nil); err == nil {
+		t.Error("expected error for unparseable reply")
+	}
+}
+
+func TestContextSource(t *testing.T) {
+	s := newTestStore(t)
+	seed(t, s)
+
+	ctx := ContextSource(s, 1)("the JWT expired and login broke")
+	if !strings.Contains(ctx, "auth middleware") {
+		t.Errorf("expected t1 approach in context: %s", ctx)
+	}
+	if ContextSource(s, 1)("kubernetes") != "" {
+		t.Error("no match should yield empty context")
+	}
+}
@@ -0,0 +1,261 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CrashReport records a recovered panic. Reports are written to
+// <repo>/.codebutler/crash/<role>-<timestamp>.json.
+type CrashReport struct {
+	Role      string         `json:"role"`
+	Panic     string         `json:"panic"`
+	Stack     string         `json:"stack"`
+	Time      time.Time      `json:"time"`
+	Restart   int            `json:"restart"`             // consecutive crash count, 1-based (1 = first crash)
+	InFlight  *RecoveryState `json:"in_flight,omitempty"` // work in progress at crash time
+	Announced bool           `json:"announced"`           // true once the recovery note was posted
+}
+
+// CrashDir returns the crash report directory for a repo.
+func CrashDir(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "crash")
+}
+
+// WriteCrashReport persists a crash report atomically and returns its path.
+func WriteCrashReport(dir string, report *CrashReport) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create crash dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal crash report: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.json", report.Role, report.Time.UTC().Format("20060102T150405.000000000Z"))
+	path := filepath.Join(dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("write crash report: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("rename crash report: %w", err)
+	}
+	return path, nil
+}
+
+// UnannouncedCrashes returns crash reports for a role that have not yet been
+// announced in chat, oldest first. Used at startup so an agent restarted by
+// launchd/systemd after a hard crash still posts its recovery note.
+func UnannouncedCrashes(dir, role string) ([]string, []*CrashReport, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("read crash dir: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), role+"-") && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var paths []string
+	var reports []*CrashReport
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var r CrashReport
+		if err := json.Unmarshal(data, &r); err != nil || r.Announced {
+			continue
+		}
+		paths = append(paths, path)
+		reports = append(reports, &r)
+	}
+	return paths, reports, nil
+}
+
+// MarkAnnounced flags a crash report as announced.
+func MarkAnnounced(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read crash report: %w", err)
+	}
+	var r CrashReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("parse crash report: %w", err)
+	}
+	r.Announced = true
+	_, err = WriteCrashReport(filepath.Dir(path), &r)
+	return err
+}
+
+// FormatCrashNote creates the chat message posted after recovering.
+func FormatCrashNote(r *CrashReport) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(":adhesive_bandage: *%s* recovered from a crash at %s",
+		r.Role, r.Time.Format(time.RFC3339)))
+	if r.Restart > 0 {
+		b.WriteString(fmt.Sprintf(" (restart #%d)", r.Restart))
+	}
+	b.WriteString(fmt.Sprintf("\n> %s", truncateLine(r.Panic, 200)))
+	if r.InFlight != nil && len(r.InFlight.ActiveThreads) > 0 {
+		b.WriteString(fmt.Sprintf("\nResuming %d in-flight thread(s).", len(r.InFlight.ActiveThreads)))
+	}
+	return b.String()
+}
+
+func truncateLine(s string, maxLen int) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > maxLen {
+		return s[:maxLen] + "..."
+	}
+	return s
+}
+
+// SupervisorConfig configures panic recovery and restart.
+type SupervisorConfig struct {
+	Role        string
+	CrashDir    string
+	MaxRestarts int           // give up after this many consecutive crashes (0 = unlimited)
+	BaseBackoff time.Duration // first restart delay; doubles each crash
+	MaxBackoff  time.Duration
+	StableAfter time.Duration // run this long without crashing to reset the backoff
+
+	// Snapshot returns the in-flight work to store in the crash report. Optional.
+	Snapshot func() *RecoveryState
+	// OnRecover is called after a crash report is written, before restarting.
+	// Typically posts FormatCrashNote to the chat. Optional.
+	OnRecover func(ctx context.Context, report *CrashReport)
+}
+
+// DefaultSupervisorConfig returns sensible defaults.
+func DefaultSupervisorConfig(role, crashDir string) SupervisorConfig {
+	return SupervisorConfig{
+		Role:        role,
+		CrashDir:    crashDir,
+		MaxRestarts: 5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  time.Minute,
+		StableAfter: 10 * time.Minute,
+	}
+}
+
+// ErrTooManyCrashes is returned by Supervise when MaxRestarts is exceeded.
+var ErrTooManyCrashes = fmt.Errorf("too many consecutive crashes")
+
+// Supervise runs fn, converting panics into crash reports and restarting fn
+// with exponential backoff. It returns when fn returns normally (with fn's
+// error), when ctx is cancelled, or when MaxRestarts consecutive crashes
+// occur. It is meant to wrap the function passed to Manager.Run.
+func Supervise(ctx context.Context, cfg SupervisorConfig, logger *slog.Logger, fn func(ctx context.Context) error) error {
+	return supervise(ctx, cfg, logger, fn, time.Now, sleepCtx)
+}
+
+func supervise(
+	ctx context.Context,
+	cfg SupervisorConfig,
+	logger *slog.Logger,
+	fn func(ctx context.Context) error,
+	now func() time.Time,
+	sleep func(ctx context.Context, d time.Duration) error,
+) error {
+	consecutive := 0
+	backoff := cfg.BaseBackoff
+
+	for {
+		started := now()
+		err, panicked, report := runProtected(ctx, fn)
+		if !panicked {
+			return err
+		}
+
+		if now().Sub(started) >= cfg.StableAfter {
+			consecutive = 0
+			backoff = cfg.BaseBackoff
+		}
+
+		report.Role = cfg.Role
+		report.Time = now()
+		report.Restart = consecutive + 1
+		if cfg.Snapshot != nil {
+			report.InFlight = cfg.Snapshot()
+		}
+
+		path, werr := WriteCrashReport(cfg.CrashDir, report)
+		if werr != nil {
+			logger.Error("failed to write crash report", "err", werr)
+		}
+		logger.Error("recovered from panic",
+			"panic", report.Panic,
+			"report", path,
+			"restart", report.Restart,
+		)
+
+		consecutive++
+		if cfg.MaxRestarts > 0 && consecutive > cfg.MaxRestarts {
+			return fmt.Errorf("%w (%d): last panic: %s", ErrTooManyCrashes, consecutive, report.Panic)
+		}
+
+		if err := sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+		if cfg.MaxBackoff > 0 && backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+
+		if cfg.OnRecover != nil {
+			cfg.OnRecover(ctx, report)
+			if path != "" {
+				if err := MarkAnnounced(path); err != nil {
+					logger.Warn("failed to mark crash announced", "err", err)
+				}
+			}
+		}
+	}
+}
+
+// runProtected runs fn and recovers any panic into a crash report.
+func runProtected(ctx context.Context, fn func(ctx context.Context) error) (err error, panicked bool, report *CrashReport) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			report = &CrashReport{
+				Panic: fmt.Sprint(r),
+				Stack: string(debug.Stack()),
+			}
+		}
+	}()
+	return fn(ctx), false, nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func noSleep(ctx context.Context, d time.Duration) error { return ctx.Err() }
+
+func TestSupervise_NormalReturn(t *testing.T) {
+	cfg := DefaultSupervisorConfig("coder", t.TempDir())
+	want := errors.New("done")
+
+	err := supervise(context.Background(), cfg, testLogger(), func(ctx context.Context) error {
+		return want
+	}, time.Now, noSleep)
+
+	if err != want {
+		t.Errorf("expected fn error, got %v", err)
+	}
+}
+
+func TestSupervise_RecoversAndRestarts(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultSupervisorConfig("coder", dir)
+	cfg.Snapshot = func() *RecoveryState {
+		return &RecoveryState{Role: "coder", ActiveThreads: []ThreadInfo{{Branch: "codebutler/login"}}}
+	}
+
+	var recovered []*CrashReport
+	cfg.OnRecover = func(_ context.Context, r *CrashReport) {
+		recovered = append(recovered, r)
+	}
+
+	var backoffs []time.Duration
+	sleep := func(_ context.Context, d time.Duration) error {
+		backoffs = append(backoffs, d)
+		return nil
+	}
+
+	runs := 0
+	err := supervise(context.Background(), cfg, testLogger(), func(ctx context.Context) error {
+		runs++
+		if runs < 3 {
+			panic("nil map write")
+		}
+		return nil
+	}, time.Now, sleep)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+	if len(recovered) != 2 {
+		t.Fatalf("expected 2 recover callbacks, got %d", len(recovered))
+	}
+	if recovered[0].Panic != "nil map write" || !strings.Contains(recovered[0].Stack, "goroutine") {
+		t.Errorf("unexpected report: %+v", recovered[0])
+	}
+	if recovered[0].InFlight == nil || len(recovered[0].InFlight.ActiveThreads) != 1 {
+		t.Error("expected in-flight state in report")
+	}
+	if len(backoffs) != 2 || backoffs[1] != 2*backoffs[0] {
+		t.Errorf("expected doubling backoff, got %v", backoffs)
+	}
+
+	// Both reports were announced via OnRecover.
+	paths, _, err := UnannouncedCrashes(dir, "coder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("expected no unannounced crashes, got %d", len(paths))
+	}
+}
+
+func TestSupervise_TooManyCrashes(t *testing.T) {
+	cfg := DefaultSupervisorConfig("pm", t.TempDir())
+	cfg.MaxRestarts = 2
+
+	runs := 0
+	err := supervise(context.Background(), cfg, testLogger(), func(ctx context.Context) error {
+		runs++
+		panic("boom")
+	}, time.Now, noSleep)
+
+	if !errors.Is(err, ErrTooManyCrashes) {
+		t.Fatalf("expected ErrTooManyCrashes, got %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+}
+
+func TestSupervise_ContextCancelledDuringBackoff(t *testing.T) {
+	cfg := DefaultSupervisorConfig("pm", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := supervise(ctx, cfg, testLogger(), func(ctx context.Context) error {
+		panic("boom")
+	}, time.Now, noSleep)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestUnannouncedCrashes(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	WriteCrashReport(dir, &CrashReport{Role: "coder", Panic: "first", Time: base})
+	WriteCrashReport(dir, &CrashReport{Role: "coder", Panic: "second", Time: base.Add(time.Minute)})
+	WriteCrashReport(dir, &CrashReport{Role: "pm", Panic: "other role", Time: base})
+
+	paths, reports, err := UnannouncedCrashes(dir, "coder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0].Panic != "first" {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+
+	if err := MarkAnnounced(paths[0]); err != nil {
+		t.Fatal(err)
+	}
+	_, reports, _ = UnannouncedCrashes(dir, "coder")
+	if len(reports) != 1 || reports[0].Panic != "second" {
+		t.Errorf("expected only second crash left, got %+v", reports)
+	}
+}
+
+func TestUnannouncedCrashes_MissingDir(t *testing.T) {
+	paths, _, err := UnannouncedCrashes(t.TempDir()+"/nope", "coder")
+	if err != nil || paths != nil {
+		t.Errorf("expected empty result, got %v %v", paths, err)
+	}
+}
+
+func TestFormatCrashNote(t *testing.T) {
+	note := FormatCrashNote(&CrashReport{
+		Role:     "coder",
+		Panic:    "index out of range\nmore detail",
+		Time:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Restart:  2,
+		InFlight: &RecoveryState{ActiveThreads: []ThreadInfo{{Branch: "a"}}},
+	})
+
+	for _, want := range []string{"coder", "recovered from a crash", "restart #2", "index out of range", "1 in-flight"} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note missing %q: %s", want, note)
+		}
+	}
+	if strings.Contains(note, "more detail") {
+		t.Error("note should only include the first line of the panic")
+	}
+}
+
+func TestCrashDir(t *testing.T) {
+	if got := CrashDir("/repo"); got != "/repo/.codebutler/crash" {
+		t.Errorf("got %s", got)
+	}
+}
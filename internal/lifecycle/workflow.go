@@ -0,0 +1,196 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkflowState is where a multi-agent workflow stands, persisted after
+// every phase change so a restarted daemon can pick it up.
+type WorkflowState struct {
+	Thread           string            `json:"thread"`
+	Channel          string            `json:"channel"`
+	Workflow         string            `json:"workflow"` // e.g. "implement", "bugfix"
+	Phase            string            `json:"phase"`    // planning, coder, review
+	Role             string            `json:"role"`     // agent that owns the phase
+	Worktree         string            `json:"worktree,omitempty"`
+	Branch           string            `json:"branch,omitempty"`
+	PR               int               `json:"pr,omitempty"`
+	Artifacts        map[string]string `json:"artifacts,omitempty"` // name -> path or URL (plan, diff, ...)
+	PendingApprovals []string          `json:"pending_approvals,omitempty"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// WorkflowStore persists in-flight workflows in a JSON file keyed by
+// thread. Finished workflows are removed. Thread-safe.
+type WorkflowStore struct {
+	mu        sync.Mutex
+	path      string
+	now       func() time.Time
+	workflows map[string]*WorkflowState
+}
+
+// DefaultWorkflowPath returns the workflow file for a repo.
+func DefaultWorkflowPath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "workflows.json")
+}
+
+// OpenWorkflowStore loads the workflow file at path; a missing file is an
+// empty store.
+func OpenWorkflowStore(path string) (*WorkflowStore, error) {
+	s := &WorkflowStore{path: path, now: time.Now, workflows: make(map[string]*WorkflowState)}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("read workflow store: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.workflows); err != nil {
+		return nil, fmt.Errorf("parse workflow store: %w", err)
+	}
+	return s, nil
+}
+
+// Save records a workflow's current state, replacing any previous one for
+// the thread.
+func (s *WorkflowStore) Save(state WorkflowState) error {
+	if state.Thread == "" {
+		return fmt.Errorf("workflow state needs a thread")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state.UpdatedAt = s.now()
+	s.workflows[state.Thread] = &state
+	return s.save()
+}
+
+// Get returns a thread's workflow state.
+func (s *WorkflowStore) Get(thread string) (WorkflowState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.workflows[thread]
+	if !ok {
+		return WorkflowState{}, false
+	}
+	return *st, true
+}
+
+// Complete drops a finished (or abandoned) workflow.
+func (s *WorkflowStore) Complete(thread string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.workflows[thread]; !ok {
+		return nil
+	}
+	delete(s.workflows, thread)
+	return s.save()
+}
+
+// Unfinished returns every stored workflow, least recently updated first.
+func (s *WorkflowStore) Unfinished() []WorkflowState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]WorkflowState, 0, len(s.workflows))
+	for _, st := range s.workflows {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.Before(out[j].UpdatedAt) })
+	return out
+}
+
+// save writes the store atomically. Callers hold mu.
+func (s *WorkflowStore) save() error {
+	data, err := json.MarshalIndent(s.workflows, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal workflow store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create workflow store dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write workflow store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename workflow store: %w", err)
+	}
+	return nil
+}
+
+// WorkflowResumer restarts a workflow at its saved phase, typically by
+// re-activating the phase's agent on the thread.
+type WorkflowResumer interface {
+	ResumeWorkflow(ctx context.Context, state WorkflowState) error
+}
+
+// Announcer posts a message to a thread.
+type Announcer interface {
+	SendMessage(ctx context.Context, channel, thread, text string) error
+}
+
+// ResumeWorkflows announces and resumes every unfinished workflow at
+// startup. A workflow that fails to resume is logged and left in the store
+// for the next attempt; the rest still run. It returns how many resumed.
+func ResumeWorkflows(ctx context.Context, store *WorkflowStore, resumer WorkflowResumer, announcer Announcer, logger *slog.Logger) int {
+	resumed := 0
+	for _, st := range store.Unfinished() {
+		if ctx.Err() != nil {
+			break
+		}
+		if announcer != nil {
+			if err := announcer.SendMessage(ctx, st.Channel, st.Thread, FormatResumeNote(st)); err != nil {
+				logger.Warn("workflow resume announcement failed", "thread", st.Thread, "err", err)
+			}
+		}
+		if err := resumer.ResumeWorkflow(ctx, st); err != nil {
+			logger.Error("workflow resume failed", "thread", st.Thread, "phase", st.Phase, "err", err)
+			continue
+		}
+		logger.Info("workflow resumed", "thread", st.Thread, "workflow", st.Workflow, "phase", st.Phase)
+		resumed++
+	}
+	return resumed
+}
+
+// FormatResumeNote is the thread message posted when a workflow resumes,
+// e.g. "Resuming review of PR #42 after a restart."
+func FormatResumeNote(st WorkflowState) string {
+	var what string
+	switch st.Phase {
+	case "planning":
+		what = "planning"
+	case "coder":
+		what = "implementation"
+		if st.Branch != "" {
+			what += " on `" + st.Branch + "`"
+		}
+	case "review":
+		what = "review"
+		if st.PR > 0 {
+			what = fmt.Sprintf("review of PR #%d", st.PR)
+		}
+	default:
+		what = st.Phase
+		if what == "" {
+			what = "work"
+		}
+	}
+	note := ":arrows_counterclockwise: Resuming " + what + " after a restart."
+	if n := len(st.PendingApprovals); n > 0 {
+		note += fmt.Sprintf(" %d approval(s) still pending.", n)
+	}
+	return note
+}
@@ -0,0 +1,84 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+)
+
+type recordingResumer struct {
+	resumed []string
+	fail    string
+}
+
+func (r *recordingResumer) ResumeWorkflow(_ context.Context, st WorkflowState) error {
+	if st.Thread == r.fail {
+		return errors.New("worktree missing")
+	}
+	r.resumed = append(r.resumed, st.Thread)
+	return nil
+}
+
+type recordingAnnouncer struct{ texts []string }
+
+func (a *recordingAnnouncer) SendMessage(_ context.Context, _, _, text string) error {
+	a.texts = append(a.texts, text)
+	return nil
+}
+
+func TestWorkflowStore_PersistsAcrossOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflows.json")
+	s, err := OpenWorkflowStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(WorkflowState{Thread: "t1", Channel: "C1", Phase: "review", PR: 42,
+		Artifacts: map[string]string{"plan": "plan.md"}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Save(WorkflowState{Thread: "t2", Channel: "C1", Phase: "coder"})
+	s.Complete("t2")
+
+	reopened, err := OpenWorkflowStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reopened.Unfinished()
+	if len(got) != 1 || got[0].PR != 42 || got[0].Artifacts["plan"] != "plan.md" || got[0].UpdatedAt.IsZero() {
+		t.Errorf("unfinished = %+v", got)
+	}
+	if err := s.Save(WorkflowState{}); err == nil {
+		t.Error("expected error for state without thread")
+	}
+}
+
+func TestResumeWorkflows(t *testing.T) {
+	s, _ := OpenWorkflowStore(filepath.Join(t.TempDir(), "workflows.json"))
+	s.Save(WorkflowState{Thread: "t1", Channel: "C1", Phase: "review", PR: 42})
+	s.Save(WorkflowState{Thread: "t2", Channel: "C1", Phase: "coder", Branch: "codebutler/login"})
+
+	resumer := &recordingResumer{fail: "t2"}
+	announcer := &recordingAnnouncer{}
+	n := ResumeWorkflows(context.Background(), s, resumer, announcer, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if n != 1 || len(resumer.resumed) != 1 || resumer.resumed[0] != "t1" {
+		t.Errorf("resumed %d: %v", n, resumer.resumed)
+	}
+	if len(announcer.texts) != 2 || announcer.texts[0] != ":arrows_counterclockwise: Resuming review of PR #42 after a restart." {
+		t.Errorf("announcements = %q", announcer.texts)
+	}
+	if _, ok := s.Get("t2"); !ok {
+		t.Error("failed workflow should stay in the store")
+	}
+}
+
+func TestFormatResumeNote(t *testing.T) {
+	got := FormatResumeNote(WorkflowState{Phase: "coder", Branch: "codebutler/login", PendingApprovals: []string{"deploy"}})
+	want := ":arrows_counterclockwise: Resuming implementation on `codebutler/login` after a restart. 1 approval(s) still pending."
+	if got != want {
+		t.Errorf("got %q", got)
+	}
+}
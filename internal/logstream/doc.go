@@ -0,0 +1,7 @@
+// Package logstream broadcasts the daemon's structured log and task events
+// to remote observers. A Hub fans events out to subscribers and keeps a
+// short replay buffer; a slog.Handler feeds log records into it; Server
+// exposes the stream as token-authenticated Server-Sent Events and
+// WSServer as a WebSocket at /ws/events; Follow is the matching SSE client
+// used by `codebutler logs --follow`.
+package logstream
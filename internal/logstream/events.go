@@ -0,0 +1,79 @@
+package logstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// Event types published with KindEvent. The type is the event's Message;
+// details are in Attrs.
+const (
+	EventMessageReceived = "message_received"
+	EventTaskStarted     = "task_started"
+	EventToolUse         = "tool_use"
+	EventTaskFinished    = "task_finished"
+	EventCost            = "cost"
+)
+
+// Emit publishes a structured activity event for integrations.
+func (h *Hub) Emit(eventType string, attrs map[string]any) {
+	h.Publish(Event{Kind: KindEvent, Message: eventType, Attrs: attrs})
+}
+
+// MessageReceived publishes an incoming chat message.
+func (h *Hub) MessageReceived(channel, thread, userID, text string) {
+	h.Emit(EventMessageReceived, map[string]any{
+		"channel": channel, "thread": thread, "user": userID, "text": text,
+	})
+}
+
+// TaskStarted publishes the start of a task.
+func (h *Hub) TaskStarted(taskID, channel, thread, summary string) {
+	h.Emit(EventTaskStarted, map[string]any{
+		"task_id": taskID, "channel": channel, "thread": thread, "summary": summary,
+	})
+}
+
+// TaskFinished publishes a task's final status and cost.
+func (h *Hub) TaskFinished(taskID, status string, costUSD float64) {
+	h.Emit(EventTaskFinished, map[string]any{
+		"task_id": taskID, "status": status, "cost_usd": costUSD,
+	})
+}
+
+// Cost publishes the spend of one model call.
+func (h *Hub) Cost(thread, role, model string, tokens int, costUSD float64) {
+	h.Emit(EventCost, map[string]any{
+		"thread": thread, "role": role, "model": model, "tokens": tokens, "cost_usd": costUSD,
+	})
+}
+
+// ToolEvents wraps a tool executor so every call publishes a tool_use
+// event tagged with the agent role and thread.
+type ToolEvents struct {
+	agent.ToolExecutor
+	hub    *Hub
+	role   string
+	thread string
+}
+
+// NewToolEvents creates the wrapper.
+func NewToolEvents(hub *Hub, exec agent.ToolExecutor, role, thread string) *ToolEvents {
+	return &ToolEvents{ToolExecutor: exec, hub: hub, role: role, thread: thread}
+}
+
+// Execute implements agent.ToolExecutor.
+func (t *ToolEvents) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	start := time.Now()
+	result, err := t.ToolExecutor.Execute(ctx, call)
+	t.hub.Emit(EventToolUse, map[string]any{
+		"role":        t.role,
+		"thread":      t.thread,
+		"tool":        call.Name,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"error":       err != nil || result.IsError,
+	})
+	return result, err
+}
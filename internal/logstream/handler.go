@@ -0,0 +1,130 @@
+package logstream
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Handler is a slog.Handler that publishes records to a Hub and, when next
+// is set, also forwards them to next (typically the daemon's own text or
+// JSON handler).
+type Handler struct {
+	hub    *Hub
+	next   slog.Handler
+	level  slog.Leveler
+	attrs  map[string]any
+	prefix string // dotted group prefix for attribute keys
+}
+
+// NewHandler creates a handler that streams records at level and above.
+// next may be nil.
+func NewHandler(hub *Hub, next slog.Handler, level slog.Leveler) *Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &Handler{hub: hub, next: next, level: level}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, l slog.Level) bool {
+	if l >= h.level.Level() {
+		return true
+	}
+	return h.next != nil && h.next.Enabled(ctx, l)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.level.Level() {
+		attrs := make(map[string]any, len(h.attrs)+r.NumAttrs())
+		for k, v := range h.attrs {
+			attrs[k] = v
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			addAttr(attrs, h.prefix, a)
+			return true
+		})
+		if len(attrs) == 0 {
+			attrs = nil
+		}
+		h.hub.Publish(Event{
+			Time:    r.Time,
+			Kind:    KindLog,
+			Level:   r.Level.String(),
+			Message: r.Message,
+			Attrs:   attrs,
+		})
+	}
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(as []slog.Attr) slog.Handler {
+	c := h.clone()
+	for _, a := range as {
+		addAttr(c.attrs, c.prefix, a)
+	}
+	if h.next != nil {
+		c.next = h.next.WithAttrs(as)
+	}
+	return c
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := h.clone()
+	c.prefix = h.prefix + name + "."
+	if h.next != nil {
+		c.next = h.next.WithGroup(name)
+	}
+	return c
+}
+
+func (h *Handler) clone() *Handler {
+	c := *h
+	c.attrs = make(map[string]any, len(h.attrs))
+	for k, v := range h.attrs {
+		c.attrs[k] = v
+	}
+	return &c
+}
+
+// addAttr flattens a (possibly grouped) attribute into dst with dotted keys.
+func addAttr(dst map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(dst, p, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	key := strings.TrimSuffix(prefix+a.Key, ".")
+	switch v.Kind() {
+	case slog.KindDuration:
+		dst[key] = v.Duration().String()
+	case slog.KindTime:
+		dst[key] = v.Time()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			dst[key] = err.Error()
+			return
+		}
+		dst[key] = v.Any()
+	default:
+		dst[key] = v.Any()
+	}
+}
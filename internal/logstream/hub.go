@@ -0,0 +1,116 @@
+package logstream
+
+import (
+	"sync"
+	"time"
+)
+
+// Event kinds.
+const (
+	KindLog   = "log"
+	KindTask  = "task"
+	KindEvent = "event" // structured activity for integrations; see Emit
+)
+
+// Event is one streamed log line or task event.
+type Event struct {
+	ID      uint64         `json:"id"`
+	Time    time.Time      `json:"time"`
+	Kind    string         `json:"kind"`
+	Level   string         `json:"level,omitempty"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// DefaultReplay is how many recent events a Hub keeps for new subscribers.
+const DefaultReplay = 200
+
+// subscriberBuffer bounds each subscriber's queue. Slow subscribers drop
+// events rather than stalling the daemon.
+const subscriberBuffer = 256
+
+// Hub fans events out to subscribers. Safe for concurrent use.
+type Hub struct {
+	mu     sync.Mutex
+	nextID uint64
+	recent []Event
+	replay int
+	subs   map[chan Event]struct{}
+	now    func() time.Time
+}
+
+// NewHub creates a hub that keeps the last replay events for new
+// subscribers. replay <= 0 uses DefaultReplay.
+func NewHub(replay int) *Hub {
+	if replay <= 0 {
+		replay = DefaultReplay
+	}
+	return &Hub{
+		replay: replay,
+		subs:   make(map[chan Event]struct{}),
+		now:    time.Now,
+	}
+}
+
+// Publish assigns an ID (and time, if unset) and delivers ev to every
+// subscriber without blocking.
+func (h *Hub) Publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	ev.ID = h.nextID
+	if ev.Time.IsZero() {
+		ev.Time = h.now()
+	}
+
+	h.recent = append(h.recent, ev)
+	if len(h.recent) > h.replay {
+		h.recent = h.recent[len(h.recent)-h.replay:]
+	}
+
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default: // subscriber is behind; drop
+		}
+	}
+}
+
+// Task publishes a task event with the given attributes.
+func (h *Hub) Task(message string, attrs map[string]any) {
+	h.Publish(Event{Kind: KindTask, Message: message, Attrs: attrs})
+}
+
+// Subscribe returns buffered events with ID > afterID followed by live
+// events on the channel. Call cancel to unsubscribe; the channel is closed.
+func (h *Hub) Subscribe(afterID uint64) (backlog []Event, events <-chan Event, cancel func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	for _, ev := range h.recent {
+		if ev.ID > afterID {
+			backlog = append(backlog, ev)
+		}
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return backlog, ch, cancel
+}
+
+// Subscribers returns the number of live subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
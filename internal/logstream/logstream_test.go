@@ -0,0 +1,253 @@
+package logstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+func TestHub_ReplayAndLive(t *testing.T) {
+	hub := NewHub(2)
+	hub.Task("a", nil)
+	hub.Task("b", nil)
+	hub.Task("c", nil)
+
+	backlog, events, cancel := hub.Subscribe(0)
+	defer cancel()
+	if len(backlog) != 2 || backlog[0].Message != "b" || backlog[1].ID != 3 {
+		t.Fatalf("backlog = %+v", backlog)
+	}
+
+	backlog2, _, cancel2 := hub.Subscribe(2)
+	if len(backlog2) != 1 || backlog2[0].Message != "c" {
+		t.Errorf("resume backlog = %+v", backlog2)
+	}
+	cancel2()
+	cancel2() // idempotent
+
+	hub.Task("d", map[string]any{"thread": "t1"})
+	select {
+	case ev := <-events:
+		if ev.Message != "d" || ev.Kind != KindTask || ev.Time.IsZero() {
+			t.Errorf("live event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no live event")
+	}
+	if hub.Subscribers() != 1 {
+		t.Errorf("subscribers = %d", hub.Subscribers())
+	}
+}
+
+func TestHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	hub := NewHub(0)
+	_, _, cancel := hub.Subscribe(0)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*2; i++ {
+			hub.Task("x", nil)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("publish blocked on a full subscriber")
+	}
+}
+
+func TestHandler_PublishesAndForwards(t *testing.T) {
+	hub := NewHub(0)
+	var buf bytes.Buffer
+	next := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(NewHandler(hub, next, slog.LevelInfo)).With("role", "coder").WithGroup("tool")
+
+	logger.Debug("noisy")
+	logger.Info("tool finished", "name", "Bash", "took", 2*time.Second, "err", errors.New("exit 1"))
+
+	backlog, _, cancel := hub.Subscribe(0)
+	defer cancel()
+	if len(backlog) != 1 {
+		t.Fatalf("debug should not stream, got %+v", backlog)
+	}
+	ev := backlog[0]
+	if ev.Level != "INFO" || ev.Message != "tool finished" {
+		t.Errorf("event = %+v", ev)
+	}
+	if ev.Attrs["role"] != "coder" || ev.Attrs["tool.name"] != "Bash" || ev.Attrs["tool.took"] != "2s" || ev.Attrs["tool.err"] != "exit 1" {
+		t.Errorf("attrs = %+v", ev.Attrs)
+	}
+	if !strings.Contains(buf.String(), "noisy") || !strings.Contains(buf.String(), "tool finished") {
+		t.Errorf("next handler should see both records:\n%s", buf.String())
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	hub := NewHub(0)
+	for _, tc := range []struct {
+		token, header string
+		want          int
+	}{
+		{"", "Bearer x", http.StatusServiceUnavailable},
+		{"secret", "", http.StatusUnauthorized},
+		{"secret", "Bearer wrong", http.StatusUnauthorized},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		NewServer(hub, tc.token).ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("token=%q header=%q: got %d, want %d", tc.token, tc.header, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestServer_FollowStream(t *testing.T) {
+	hub := NewHub(0)
+	hub.Task("queued", nil)
+	slog.New(NewHandler(hub, nil, nil)).Info("started", "thread", "t1")
+
+	srv := httptest.NewServer(NewServer(hub, "secret", WithHeartbeat(10*time.Millisecond)))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []Event
+	stop := errors.New("stop")
+	last, err := Follow(ctx, srv.Client(), srv.URL, "secret", 0, func(ev Event) error {
+		got = append(got, ev)
+		if len(got) == 2 {
+			hub.Task("done", nil)
+		}
+		if len(got) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || last != 3 {
+		t.Fatalf("last=%d err=%v", last, err)
+	}
+	if got[0].Message != "queued" || got[1].Attrs["thread"] != "t1" || got[2].Message != "done" {
+		t.Errorf("events = %+v", got)
+	}
+
+	// Resume after the last seen ID, filtered to task events only.
+	hub.Task("next", nil)
+	var resumed []Event
+	Follow(ctx, srv.Client(), srv.URL+"?kind=task", "secret", last, func(ev Event) error {
+		resumed = append(resumed, ev)
+		return stop
+	})
+	if len(resumed) != 1 || resumed[0].Message != "next" {
+		t.Errorf("resumed = %+v", resumed)
+	}
+
+	if _, err := Follow(ctx, srv.Client(), srv.URL, "wrong", 0, func(Event) error { return nil }); err == nil {
+		t.Error("expected auth error")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ev := Event{Time: time.Now(), Kind: KindLog, Level: "WARN", Message: "slow tool", Attrs: map[string]any{"tool": "Bash", "avg": "3s"}}
+	if got := Format(ev); !strings.HasSuffix(got, " WARN  slow tool avg=3s tool=Bash") {
+		t.Errorf("Format = %q", got)
+	}
+	if got := Format(Event{Kind: KindTask, Message: "done"}); !strings.Contains(got, " TASK done") {
+		t.Errorf("Format task = %q", got)
+	}
+}
+
+// wsDial connects to a test server's WebSocket endpoint.
+func wsDial(t *testing.T, url, token string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+WSPath,
+		http.Header{"Authorization": {"Bearer " + token}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status %d", resp.StatusCode)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func TestWSServer_StreamsEvents(t *testing.T) {
+	hub := NewHub(0)
+	hub.Task("queued", nil) // not an integration event; filtered out
+	hub.MessageReceived("C1", "t1", "U1", "add login")
+
+	mux := http.NewServeMux()
+	mux.Handle(WSPath, NewWSServer(hub, "secret"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	conn := wsDial(t, srv.URL, "secret")
+	defer conn.Close()
+
+	op, payload, err := conn.ReadMessage()
+	if err != nil || op != websocket.TextMessage {
+		t.Fatalf("op=%d err=%v", op, err)
+	}
+	var ev Event
+	json.Unmarshal(payload, &ev)
+	if ev.Kind != KindEvent || ev.Message != EventMessageReceived || ev.Attrs["text"] != "add login" {
+		t.Errorf("backlog event = %+v", ev)
+	}
+
+	exec := NewToolEvents(hub, fakeExecutor{}, "coder", "t1")
+	exec.Execute(context.Background(), agent.ToolCall{Name: "Bash"})
+	_, payload, _ = conn.ReadMessage()
+	json.Unmarshal(payload, &ev)
+	if ev.Message != EventToolUse || ev.Attrs["tool"] != "Bash" || ev.Attrs["role"] != "coder" {
+		t.Errorf("live event = %+v", ev)
+	}
+
+	// A client close is echoed and ends the stream.
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("close reply err=%v", err)
+	}
+}
+
+func TestWSServer_RejectsPlainRequests(t *testing.T) {
+	s := NewWSServer(NewHub(0), "secret")
+	req := httptest.NewRequest(http.MethodGet, WSPath, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("no upgrade: %d", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: %d", rec.Code)
+	}
+}
+
+type fakeExecutor struct{}
+
+func (fakeExecutor) Execute(_ context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	return agent.ToolResult{ToolCallID: call.ID, Content: "ok"}, nil
+}
+
+func (fakeExecutor) ListTools() []agent.ToolDefinition { return nil }
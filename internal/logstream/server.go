@@ -0,0 +1,203 @@
+package logstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/httpauth"
+)
+
+// DefaultHeartbeat is how often an idle stream sends a keep-alive comment.
+const DefaultHeartbeat = 15 * time.Second
+
+// Server streams hub events as Server-Sent Events. Requests must carry the
+// shared token as "Authorization: Bearer <token>" or ?token=. Clients
+// resume with the Last-Event-ID header (or ?since=) and may filter with
+// ?kind=log|task|event.
+type Server struct {
+	hub       *Hub
+	token     string
+	heartbeat time.Duration
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithHeartbeat overrides DefaultHeartbeat.
+func WithHeartbeat(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.heartbeat = d
+	}
+}
+
+// NewServer creates a stream server. An empty token disables the endpoint.
+func NewServer(hub *Hub, token string, opts ...ServerOption) *Server {
+	s := &Server{hub: hub, token: token, heartbeat: DefaultHeartbeat}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.token == "" {
+		http.Error(w, "log streaming is disabled (no token configured)", http.StatusServiceUnavailable)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	afterID, _ := strconv.ParseUint(since, 10, 64)
+	kind := r.URL.Query().Get("kind")
+
+	backlog, events, cancel := s.hub.Subscribe(afterID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, ev := range backlog {
+		if kind == "" || ev.Kind == kind {
+			writeEvent(w, ev)
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if kind != "" && ev.Kind != kind {
+				continue
+			}
+			writeEvent(w, ev)
+			flusher.Flush()
+		}
+	}
+}
+
+// authorized accepts the bearer header or, for EventSource clients that
+// cannot set headers, ?token=.
+func (s *Server) authorized(r *http.Request) bool {
+	got, ok := httpauth.Bearer(r)
+	if !ok {
+		got = r.URL.Query().Get("token")
+	}
+	return httpauth.Match(got, s.token)
+}
+
+func writeEvent(w http.ResponseWriter, ev Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Kind, data)
+}
+
+// Follow connects to a stream at url and calls fn for each event until ctx
+// is done, the server closes the stream, or fn returns an error. It returns
+// the ID of the last event seen so callers can reconnect with since.
+func Follow(ctx context.Context, client *http.Client, url, token string, since uint64, fn func(Event) error) (uint64, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return since, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "text/event-stream")
+	if since > 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatUint(since, 10))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return since, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return since, fmt.Errorf("log stream: %s", resp.Status)
+	}
+
+	last := since
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			continue
+		}
+		last = ev.ID
+		if err := fn(ev); err != nil {
+			return last, err
+		}
+	}
+	if err := sc.Err(); err != nil && ctx.Err() == nil {
+		return last, err
+	}
+	return last, ctx.Err()
+}
+
+// Format renders an event as a single terminal line.
+func Format(ev Event) string {
+	var b strings.Builder
+	b.WriteString(ev.Time.Local().Format("15:04:05"))
+	switch ev.Kind {
+	case KindTask:
+		b.WriteString(" TASK ")
+	case KindEvent:
+		b.WriteString(" EVENT ")
+	default:
+		fmt.Fprintf(&b, " %-5s ", ev.Level)
+	}
+	b.WriteString(ev.Message)
+
+	keys := make([]string, 0, len(ev.Attrs))
+	for k := range ev.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, ev.Attrs[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package logstream
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WSPath is where WSServer is mounted.
+const WSPath = "/ws/events"
+
+// wsWriteTimeout bounds each frame write so a stuck client is dropped.
+const wsWriteTimeout = 10 * time.Second
+
+// maxClientMessage caps messages read from clients; they only send
+// control frames, which RFC 6455 limits to 125 bytes.
+const maxClientMessage = 4096
+
+// upgrader accepts any origin: the stream is authorized by token, not by
+// cookies, so a cross-site page gains nothing without the token.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// WSServer streams hub events as JSON text frames over a WebSocket, for
+// dashboards and IDE plugins. Authentication, ?since= and ?kind= work as
+// in Server; ?kind defaults to "event", so integrations get structured
+// activity rather than raw log lines. The stream is one-way: client data
+// frames are ignored.
+type WSServer struct {
+	stream *Server // shares the hub, token, and heartbeat settings
+}
+
+// NewWSServer creates the WebSocket endpoint. An empty token disables it.
+// WithHeartbeat sets the ping interval.
+func NewWSServer(hub *Hub, token string, opts ...ServerOption) *WSServer {
+	return &WSServer{stream: NewServer(hub, token, opts...)}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *WSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.stream.token == "" {
+		http.Error(w, "event streaming is disabled (no token configured)", http.StatusServiceUnavailable)
+		return
+	}
+	if !s.stream.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	afterID, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = KindEvent
+	}
+
+	// Upgrade writes its own error response for non-WebSocket requests.
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxClientMessage)
+
+	// The default handlers answer pings and echo closes; reading is only
+	// needed to run them and to notice the client going away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	backlog, events, cancel := s.stream.hub.Subscribe(afterID)
+	defer cancel()
+	for _, ev := range backlog {
+		if kind == "all" || ev.Kind == kind {
+			if writeWSEvent(conn, ev) != nil {
+				return
+			}
+		}
+	}
+
+	ticker := time.NewTicker(s.stream.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)) != nil {
+				return
+			}
+		case ev, ok := <-events:
+			if !ok {
+				msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout)) //nolint:errcheck // closing anyway
+				return
+			}
+			if kind != "all" && ev.Kind != kind {
+				continue
+			}
+			if writeWSEvent(conn, ev) != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeWSEvent sends ev as one text frame. The reader goroutine's pong
+// and close replies go through gorilla's control-frame path, which is
+// safe alongside this single writer.
+func writeWSEvent(conn *websocket.Conn, ev Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil // skip unencodable attrs rather than drop the client
+	}
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)) //nolint:errcheck // best effort
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
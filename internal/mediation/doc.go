@@ -0,0 +1,5 @@
+// Package mediation gives LeadRunner.Mediate an entry point. When the Coder
+// and Reviewer keep disagreeing, /mediate (or an automatic trigger after N
+// review rounds with unresolved blockers) gathers both positions from their
+// thread conversations and asks the Lead for a binding decision.
+package mediation
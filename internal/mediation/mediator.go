@@ -0,0 +1,193 @@
+package mediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/conversation"
+)
+
+const (
+	// positionMessages is how many recent replies per agent make up its
+	// position.
+	positionMessages = 3
+	// maxPositionChars keeps each position short enough for one prompt.
+	maxPositionChars = 2000
+)
+
+// ErrNoDispute is returned when one side has said nothing in the thread.
+var ErrNoDispute = errors.New("need messages from both the coder and the reviewer to mediate")
+
+// History reads an agent's conversation in a thread, oldest first.
+type History interface {
+	Messages(ctx context.Context, channel, thread, role string) ([]agent.Message, error)
+}
+
+// MessageSender posts the decision to the thread.
+type MessageSender interface {
+	SendMessage(ctx context.Context, channel, threadTS, text string) error
+}
+
+// Mediator runs the Lead on a Coder/Reviewer dispute.
+type Mediator struct {
+	lead       *agent.LeadRunner
+	history    History
+	sender     MessageSender
+	autoRounds int // 0 disables automatic mediation
+	logger     *slog.Logger
+
+	mu       sync.Mutex
+	mediated map[string]bool // channel/thread already auto-mediated
+}
+
+// Option configures a Mediator.
+type Option func(*Mediator)
+
+// WithAutoMediation mediates automatically once a review round at or past
+// rounds still reports blockers. Each thread is auto-mediated at most once.
+func WithAutoMediation(rounds int) Option {
+	return func(m *Mediator) {
+		m.autoRounds = rounds
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(m *Mediator) {
+		m.logger = l
+	}
+}
+
+// New creates a mediator.
+func New(lead *agent.LeadRunner, history History, sender MessageSender, opts ...Option) *Mediator {
+	m := &Mediator{
+		lead:     lead,
+		history:  history,
+		sender:   sender,
+		logger:   slog.Default(),
+		mediated: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Mediate gathers both positions and returns the Lead's decision, formatted
+// for the thread.
+func (m *Mediator) Mediate(ctx context.Context, channel, thread string) (string, *agent.Result, error) {
+	coder, err := m.position(ctx, channel, thread, "coder")
+	if err != nil {
+		return "", nil, err
+	}
+	reviewer, err := m.position(ctx, channel, thread, "reviewer")
+	if err != nil {
+		return "", nil, err
+	}
+	if coder == "" || reviewer == "" {
+		return "", nil, ErrNoDispute
+	}
+
+	dispute := agent.FormatMediationContext("Coder", coder, "Reviewer", reviewer)
+	res, err := m.lead.Mediate(ctx, dispute, channel, thread)
+	if err != nil {
+		return "", res, fmt.Errorf("lead mediation: %w", err)
+	}
+	m.logger.Info("lead mediation done", "thread", thread)
+	return FormatDecision(res.Response), res, nil
+}
+
+// AfterReviewRound triggers automatic mediation when the round has reached
+// the configured limit and blockers remain. It reports whether it mediated.
+func (m *Mediator) AfterReviewRound(ctx context.Context, channel, thread string, round int, issues []agent.ReviewIssue) (bool, error) {
+	if m.autoRounds <= 0 || round < m.autoRounds || !agent.HasBlockers(issues) {
+		return false, nil
+	}
+	key := channel + "/" + thread
+	m.mu.Lock()
+	if m.mediated[key] {
+		m.mu.Unlock()
+		return false, nil
+	}
+	m.mediated[key] = true
+	m.mu.Unlock()
+
+	decision, _, err := m.Mediate(ctx, channel, thread)
+	if err != nil {
+		return false, err
+	}
+	if err := m.sender.SendMessage(ctx, channel, thread, decision); err != nil {
+		return true, fmt.Errorf("post mediation decision: %w", err)
+	}
+	return true, nil
+}
+
+// position joins the agent's last few replies in the thread.
+func (m *Mediator) position(ctx context.Context, channel, thread, role string) (string, error) {
+	msgs, err := m.history.Messages(ctx, channel, thread, role)
+	if err != nil {
+		return "", fmt.Errorf("load %s conversation: %w", role, err)
+	}
+	var replies []string
+	for i := len(msgs) - 1; i >= 0 && len(replies) < positionMessages; i-- {
+		if msgs[i].Role == "assistant" && strings.TrimSpace(msgs[i].Content) != "" {
+			replies = append([]string{strings.TrimSpace(msgs[i].Content)}, replies...)
+		}
+	}
+	text := strings.Join(replies, "\n\n")
+	if len(text) > maxPositionChars {
+		text = "…" + text[len(text)-maxPositionChars:]
+	}
+	return text, nil
+}
+
+// FormatDecision marks the Lead's answer as the binding outcome.
+func FormatDecision(response string) string {
+	return ":scales: *Lead decision (binding):*\n\n" + strings.TrimSpace(response)
+}
+
+// Command returns the /mediate chat command.
+func Command(m *Mediator) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "mediate",
+		Description: "Ask the Lead to settle a Coder/Reviewer disagreement in this thread",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			decision, _, err := m.Mediate(ctx, inv.Channel, inv.Thread)
+			if errors.Is(err, ErrNoDispute) {
+				return "Nothing to mediate yet: " + err.Error() + ".", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return decision, nil
+		},
+	}
+}
+
+// ConversationHistory reads agent conversations from the per-branch files
+// written by conversation.FileStore. branch maps a thread to its worktree
+// branch. Cipher must be the one the files were saved with, if any.
+type ConversationHistory struct {
+	BaseDir string
+	Branch  func(channel, thread string) (string, error)
+	Cipher  conversation.Cipher
+}
+
+// Messages implements History.
+func (h ConversationHistory) Messages(ctx context.Context, channel, thread, role string) ([]agent.Message, error) {
+	branch, err := h.Branch(channel, thread)
+	if err != nil {
+		return nil, err
+	}
+	var opts []conversation.Option
+	if h.Cipher != nil {
+		opts = append(opts, conversation.WithCipher(h.Cipher))
+	}
+	return conversation.NewFileStore(conversation.FilePath(h.BaseDir, branch, role), opts...).Load(ctx)
+}
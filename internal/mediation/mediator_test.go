@@ -0,0 +1,115 @@
+package mediation
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/conversation"
+	"github.com/leandrotocalini/codebutler/internal/encrypt"
+)
+
+type leadProvider struct{ prompts []string }
+
+func (p *leadProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	p.prompts = append(p.prompts, req.Messages[len(req.Messages)-1].Content)
+	return &agent.ChatResponse{Message: agent.Message{Role: "assistant", Content: "Keep the retry; add the test the Reviewer asked for."}}, nil
+}
+
+type noTools struct{}
+
+func (noTools) Execute(context.Context, agent.ToolCall) (agent.ToolResult, error) {
+	return agent.ToolResult{}, nil
+}
+func (noTools) ListTools() []agent.ToolDefinition { return nil }
+
+type threadSender struct{ sent []string }
+
+func (s *threadSender) SendMessage(_ context.Context, _, _, text string) error {
+	s.sent = append(s.sent, text)
+	return nil
+}
+
+func newTestMediator(t *testing.T, opts ...Option) (*Mediator, *leadProvider, *threadSender) {
+	t.Helper()
+	dir := t.TempDir()
+	save := func(role string, msgs ...agent.Message) {
+		if err := conversation.NewFileStore(conversation.FilePath(dir, "codebutler/retry", role)).Save(context.Background(), msgs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("coder",
+		agent.Message{Role: "user", Content: "review feedback"},
+		agent.Message{Role: "assistant", Content: "The retry loop is needed for flaky upstreams."})
+	save("reviewer",
+		agent.Message{Role: "assistant", Content: "1. [test] client.go:30 — retry has no test, blocker"})
+
+	provider := &leadProvider{}
+	lead := agent.NewLeadRunner(provider, &threadSender{}, noTools{}, agent.DefaultLeadConfig(), "You are the Lead.")
+	history := ConversationHistory{BaseDir: dir, Branch: func(string, string) (string, error) { return "codebutler/retry", nil }}
+	sender := &threadSender{}
+	return New(lead, history, sender, opts...), provider, sender
+}
+
+func TestCommand_MediatesFromConversations(t *testing.T) {
+	m, provider, _ := newTestMediator(t)
+
+	out, err := Command(m).Run(context.Background(), chatcmd.Invocation{Channel: "C1", Thread: "1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, ":scales: *Lead decision (binding):*") || !strings.Contains(out, "Keep the retry") {
+		t.Errorf("decision = %q", out)
+	}
+	prompt := provider.prompts[0]
+	if !strings.Contains(prompt, "**Coder's position:** The retry loop is needed") ||
+		!strings.Contains(prompt, "**Reviewer's position:** 1. [test] client.go:30") {
+		t.Errorf("prompt missing positions:\n%s", prompt)
+	}
+}
+
+func TestAfterReviewRound(t *testing.T) {
+	m, provider, sender := newTestMediator(t, WithAutoMediation(3))
+	blocker := []agent.ReviewIssue{{Tag: "test", Severity: "blocker", Message: "no test"}}
+
+	if ok, _ := m.AfterReviewRound(context.Background(), "C1", "1.1", 2, blocker); ok {
+		t.Error("mediated before the round limit")
+	}
+	if ok, _ := m.AfterReviewRound(context.Background(), "C1", "1.1", 3, nil); ok {
+		t.Error("mediated without blockers")
+	}
+	if ok, err := m.AfterReviewRound(context.Background(), "C1", "1.1", 3, blocker); !ok || err != nil {
+		t.Fatalf("expected mediation: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := m.AfterReviewRound(context.Background(), "C1", "1.1", 4, blocker); ok {
+		t.Error("thread mediated twice")
+	}
+	if len(provider.prompts) != 1 || len(sender.sent) != 1 {
+		t.Errorf("lead calls = %d, posts = %d", len(provider.prompts), len(sender.sent))
+	}
+}
+
+func TestConversationHistory_Sealed(t *testing.T) {
+	dir := t.TempDir()
+	c, err := encrypt.NewCipher(bytes.Repeat([]byte{7}, encrypt.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := conversation.FilePath(dir, "codebutler/retry", "coder")
+	msgs := []agent.Message{{Role: "assistant", Content: "keep the retry"}}
+	if err := conversation.NewFileStore(path, conversation.WithCipher(c)).Save(context.Background(), msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	branch := func(string, string) (string, error) { return "codebutler/retry", nil }
+	got, err := ConversationHistory{BaseDir: dir, Branch: branch, Cipher: c}.Messages(context.Background(), "C1", "1.1", "coder")
+	if err != nil || len(got) != 1 || got[0].Content != "keep the retry" {
+		t.Errorf("sealed history = %+v, %v", got, err)
+	}
+	if _, err := (ConversationHistory{BaseDir: dir, Branch: branch}).Messages(context.Background(), "C1", "1.1", "coder"); err == nil {
+		t.Error("reading a sealed file without the cipher should fail")
+	}
+}
@@ -0,0 +1,263 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/template"
+)
+
+// Key identifies a user-facing message in the catalog.
+type Key string
+
+const (
+	// Escalation is posted when an agent exhausts its escape strategies.
+	// Data: Summary, Target.
+	Escalation Key = "escalation"
+	// BudgetThreadExceeded is posted when a thread hits its cost limit.
+	// Data: Spent, Limit.
+	BudgetThreadExceeded Key = "budget.thread_exceeded"
+	// BudgetDailyExceeded is posted when the daily cost limit is hit.
+	// Data: Spent, Limit.
+	BudgetDailyExceeded Key = "budget.daily_exceeded"
+	// BudgetMonthlyExceeded is posted when the monthly cost limit is hit.
+	// Data: Spent, Limit.
+	BudgetMonthlyExceeded Key = "budget.monthly_exceeded"
+	// BudgetModelExceeded is posted when one model's monthly cap is hit.
+	// Data: Model, Spent, Limit.
+	BudgetModelExceeded Key = "budget.model_exceeded"
+	// BudgetApproved replies to /approve-budget when work resumes.
+	// Data: Limited (the thread has a limit), Remaining.
+	BudgetApproved Key = "budget.approved"
+	// BudgetNothingPaused replies to /approve-budget when nothing waits.
+	BudgetNothingPaused Key = "budget.nothing_paused"
+	// BudgetNeedsGlobal replies to /approve-budget when the thread waits on
+	// a limit shared by every thread.
+	BudgetNeedsGlobal Key = "budget.needs_global"
+	// BudgetApproveDenied replies to /approve-budget from someone who is
+	// not a configured approver.
+	BudgetApproveDenied Key = "budget.approve_denied"
+	// BudgetPlanApproval follows the cost table when a plan needs approval
+	// before the Coder starts. Data: Total, Threshold.
+	BudgetPlanApproval Key = "budget.plan_approval"
+	// BudgetBatchForecast asks before a large batch starts an expensive
+	// run. Data: Messages, Attachments (human-readable size, empty if
+	// none), Cost, Threshold.
+	BudgetBatchForecast Key = "budget.batch_forecast"
+	// GCInactiveWarning is posted before an idle worktree is cleaned up.
+	// Data: Branch, GracePeriod, Size (human-readable disk usage).
+	GCInactiveWarning Key = "gc.inactive_warning"
+	// WorkflowMenuHeader opens the workflow menu.
+	WorkflowMenuHeader Key = "workflow.menu_header"
+	// WorkflowMenuFooter closes the workflow menu.
+	WorkflowMenuFooter Key = "workflow.menu_footer"
+	// TaskDone is posted when a task completes.
+	TaskDone Key = "task.done"
+	// TaskFailed is posted when a task fails. Data: Error.
+	TaskFailed Key = "task.failed"
+	// ThreadQueued is posted when a thread waits for a free slot under
+	// maxConcurrentThreads. Data: Position.
+	ThreadQueued Key = "thread.queued"
+	// ThreadDequeued is posted when a queued thread starts.
+	ThreadDequeued Key = "thread.dequeued"
+	// ApprovalPrompt asks the thread to confirm a risky step.
+	// Data: Question.
+	ApprovalPrompt Key = "approval.prompt"
+	// ApprovalTimedOut is posted when nobody answers an approval prompt.
+	// Data: Timeout.
+	ApprovalTimedOut Key = "approval.timed_out"
+	// ApprovalToolCall is the question asked before a gated tool call.
+	// Data: Summary.
+	ApprovalToolCall Key = "approval.tool_call"
+
+	// CLIDemoBanner is printed when `codebutler demo` starts.
+	CLIDemoBanner Key = "cli.demo_banner"
+	// CLIUnknownCommand replies to an unregistered slash command in the terminal.
+	CLIUnknownCommand Key = "cli.unknown_command"
+	// CLIDoctorHeader opens the `codebutler doctor` checklist. Data: Repo.
+	CLIDoctorHeader Key = "cli.doctor_header"
+	// CLIDoctorReady closes the checklist when every required item passed.
+	CLIDoctorReady Key = "cli.doctor_ready"
+	// CLIDoctorNotReady closes the checklist when something is missing.
+	// Data: Missing.
+	CLIDoctorNotReady Key = "cli.doctor_not_ready"
+)
+
+// DefaultLocale is used when no locale is configured or a key is missing
+// from the requested locale.
+const DefaultLocale = "en"
+
+// builtin holds the shipped templates per locale.
+var builtin = map[string]map[Key]string{
+	"en": {
+		Escalation:            "I'm stuck. Here's what I tried: {{.Summary}}. I need help. Escalating to {{.Target}}.",
+		BudgetThreadExceeded:  ":money_with_wings: This thread's budget is exceeded (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}}). Work is paused; reply /approve-budget to continue.",
+		BudgetDailyExceeded:   ":money_with_wings: Today's budget is exceeded (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}}). Work is paused; reply /approve-budget global to continue.",
+		BudgetMonthlyExceeded: ":money_with_wings: This month's budget is exceeded (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}}). Work is paused; reply /approve-budget global to continue.",
+		BudgetModelExceeded:   ":money_with_wings: This month's *{{.Model}}* budget is exceeded (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}}). Work is paused; reply /approve-budget global to continue.",
+		BudgetApproved:        "Budget approved; resuming.{{if .Limited}} ${{printf \"%.2f\" .Remaining}} available for this thread.{{end}}",
+		BudgetNothingPaused:   "Nothing is paused on a budget limit here.",
+		BudgetNeedsGlobal:     "This thread is paused on a shared daily, monthly or per-model limit. Reply /approve-budget global to lift it for every thread.",
+		BudgetApproveDenied:   "Only a configured approver can lift a budget limit.",
+		BudgetPlanApproval:    "This plan is estimated at ${{printf \"%.2f\" .Total}}, above the ${{printf \"%.2f\" .Threshold}} approval threshold. Approve before the Coder starts?",
+		BudgetBatchForecast:   "This request ({{.Messages}} messages{{if .Attachments}} and {{.Attachments}} of attachments{{end}}) is forecast at ~${{printf \"%.2f\" .Cost}}, above the ${{printf \"%.2f\" .Threshold}} confirmation threshold. Run it?",
+		GCInactiveWarning:     "This thread has been inactive. The worktree `{{.Branch}}` ({{.Size}} on disk) will be cleaned up in {{.GracePeriod}} unless there is new activity.",
+		WorkflowMenuHeader:    "I can help you with:",
+		WorkflowMenuFooter:    "What would you like to do?",
+		TaskDone:              "Done ✓",
+		TaskFailed:            "Something went wrong: {{.Error}}",
+		ThreadQueued:          "All agents are busy. You're #{{.Position}} in the queue; I'll start as soon as a slot frees up.",
+		ThreadDequeued:        "A slot freed up. Starting now.",
+		ApprovalPrompt:        "{{.Question}}\nReply *1* to approve / *2* to reject.",
+		ApprovalTimedOut:      "No answer within {{.Timeout}}; treating it as rejected.",
+		ApprovalToolCall:      ":warning: About to run {{.Summary}}",
+		CLIDemoBanner:         "CodeButler demo — type a request, /help for commands, Ctrl-D to quit.",
+		CLIUnknownCommand:     "Unknown command. Try /help.",
+		CLIDoctorHeader:       "CodeButler setup ({{.Repo}}):",
+		CLIDoctorReady:        "All required checks passed.",
+		CLIDoctorNotReady:     "{{.Missing}} required item(s) missing.",
+	},
+	"es": {
+		Escalation:            "Estoy trabado. Esto es lo que intenté: {{.Summary}}. Necesito ayuda. Escalando a {{.Target}}.",
+		BudgetThreadExceeded:  ":money_with_wings: Se superó el presupuesto de este hilo (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}}). El trabajo está en pausa; respondé /approve-budget para continuar.",
+		BudgetDailyExceeded:   ":money_with_wings: Se superó el presupuesto de hoy (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}}). El trabajo está en pausa; respondé /approve-budget global para continuar.",
+		BudgetMonthlyExceeded: ":money_with_wings: Se superó el presupuesto del mes (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}}). El trabajo está en pausa; respondé /approve-budget global para continuar.",
+		BudgetModelExceeded:   ":money_with_wings: Se superó el presupuesto del mes para *{{.Model}}* (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}}). El trabajo está en pausa; respondé /approve-budget global para continuar.",
+		BudgetApproved:        "Presupuesto aprobado; sigo.{{if .Limited}} Quedan ${{printf \"%.2f\" .Remaining}} para este hilo.{{end}}",
+		BudgetNothingPaused:   "No hay nada en pausa por presupuesto acá.",
+		BudgetNeedsGlobal:     "Este hilo está en pausa por un límite compartido (diario, mensual o por modelo). Respondé /approve-budget global para levantarlo en todos los hilos.",
+		BudgetApproveDenied:   "Solo un aprobador configurado puede levantar un límite de presupuesto.",
+		BudgetPlanApproval:    "Este plan se estima en ${{printf \"%.2f\" .Total}}, por encima del umbral de aprobación de ${{printf \"%.2f\" .Threshold}}. ¿Lo aprobás antes de que arranque el Coder?",
+		BudgetBatchForecast:   "Este pedido ({{.Messages}} mensajes{{if .Attachments}} y {{.Attachments}} de adjuntos{{end}}) se estima en ~${{printf \"%.2f\" .Cost}}, por encima del umbral de confirmación de ${{printf \"%.2f\" .Threshold}}. ¿Lo ejecuto?",
+		GCInactiveWarning:     "Este hilo está inactivo. El worktree `{{.Branch}}` ({{.Size}} en disco) se va a limpiar en {{.GracePeriod}} si no hay actividad nueva.",
+		WorkflowMenuHeader:    "Puedo ayudarte con:",
+		WorkflowMenuFooter:    "¿Qué querés hacer?",
+		TaskDone:              "Listo ✓",
+		TaskFailed:            "Algo salió mal: {{.Error}}",
+		ThreadQueued:          "Todos los agentes están ocupados. Estás #{{.Position}} en la cola; arranco apenas se libere un lugar.",
+		ThreadDequeued:        "Se liberó un lugar. Arranco ahora.",
+		ApprovalPrompt:        "{{.Question}}\nRespondé *1* para aprobar / *2* para rechazar.",
+		ApprovalTimedOut:      "No hubo respuesta en {{.Timeout}}; lo tomo como rechazado.",
+		ApprovalToolCall:      ":warning: Estoy por ejecutar {{.Summary}}",
+		CLIDemoBanner:         "Demo de CodeButler — escribí un pedido, /help para ver comandos, Ctrl-D para salir.",
+		CLIUnknownCommand:     "Comando desconocido. Probá /help.",
+		CLIDoctorHeader:       "Configuración de CodeButler ({{.Repo}}):",
+		CLIDoctorReady:        "Todos los chequeos obligatorios pasaron.",
+		CLIDoctorNotReady:     "Faltan {{.Missing}} elemento(s) obligatorio(s).",
+	},
+}
+
+// Catalog renders user-facing messages for a single locale. Lookups fall
+// back to the default locale, then to the key itself. Thread-safe.
+type Catalog struct {
+	mu        sync.RWMutex
+	locale    string
+	templates map[Key]*template.Template
+}
+
+// New creates a catalog for the given locale using only built-in templates.
+// An unknown or empty locale falls back to DefaultLocale.
+func New(locale string) *Catalog {
+	if _, ok := builtin[locale]; !ok {
+		locale = DefaultLocale
+	}
+
+	c := &Catalog{
+		locale:    locale,
+		templates: make(map[Key]*template.Template),
+	}
+
+	// Default locale first so the requested locale overwrites it
+	for key, text := range builtin[DefaultLocale] {
+		c.templates[key] = template.Must(template.New(string(key)).Parse(text))
+	}
+	for key, text := range builtin[locale] {
+		c.templates[key] = template.Must(template.New(string(key)).Parse(text))
+	}
+	return c
+}
+
+// Load creates a catalog for the locale and applies overrides from
+// <repoDir>/.codebutler/messages/<locale>.json, if present. The file is a
+// flat JSON object of key → template text.
+func Load(repoDir, locale string) (*Catalog, error) {
+	c := New(locale)
+
+	path := OverridePath(repoDir, c.locale)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("read message overrides: %w", err)
+	}
+
+	var overrides map[string]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parse message overrides %s: %w", path, err)
+	}
+
+	for key, text := range overrides {
+		if err := c.Override(Key(key), text); err != nil {
+			return nil, fmt.Errorf("message override %q: %w", key, err)
+		}
+	}
+	return c, nil
+}
+
+// OverridePath returns the path of the per-repo override file for a locale.
+func OverridePath(repoDir, locale string) string {
+	return filepath.Join(repoDir, ".codebutler", "messages", locale+".json")
+}
+
+// Locale returns the catalog's resolved locale.
+func (c *Catalog) Locale() string {
+	return c.locale
+}
+
+// Override replaces the template for a key. Returns an error if the
+// template text does not parse.
+func (c *Catalog) Override(key Key, text string) error {
+	tmpl, err := template.New(string(key)).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates[key] = tmpl
+	return nil
+}
+
+// Render executes the template for key with data. Unknown keys render as
+// the key itself, and execution errors fall back to the raw key so a bad
+// override never swallows a message entirely.
+func (c *Catalog) Render(key Key, data any) string {
+	c.mu.RLock()
+	tmpl, ok := c.templates[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return string(key)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return string(key)
+	}
+	return b.String()
+}
+
+// Locales returns the built-in locales, sorted.
+func Locales() []string {
+	locales := make([]string, 0, len(builtin))
+	for l := range builtin {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
@@ -0,0 +1,164 @@
+package messages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNew_DefaultLocale(t *testing.T) {
+	c := New("")
+	if c.Locale() != DefaultLocale {
+		t.Errorf("expected %q, got %q", DefaultLocale, c.Locale())
+	}
+	if got := c.Render(TaskDone, nil); got != "Done ✓" {
+		t.Errorf("unexpected render: %q", got)
+	}
+}
+
+func TestNew_UnknownLocaleFallsBack(t *testing.T) {
+	c := New("xx")
+	if c.Locale() != DefaultLocale {
+		t.Errorf("expected fallback to %q, got %q", DefaultLocale, c.Locale())
+	}
+}
+
+func TestRender_Spanish(t *testing.T) {
+	c := New("es")
+	got := c.Render(BudgetThreadExceeded, map[string]float64{"Spent": 5.5, "Limit": 5})
+	want := ":money_with_wings: Se superó el presupuesto de este hilo ($5.50 de $5.00). El trabajo está en pausa; respondé /approve-budget para continuar."
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRender_Escalation(t *testing.T) {
+	c := New("en")
+	got := c.Render(Escalation, map[string]string{"Summary": "ran tests twice", "Target": "@codebutler.pm"})
+	want := "I'm stuck. Here's what I tried: ran tests twice. I need help. Escalating to @codebutler.pm."
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRender_UnknownKey(t *testing.T) {
+	c := New("en")
+	if got := c.Render(Key("nope"), nil); got != "nope" {
+		t.Errorf("expected key as fallback, got %q", got)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	c := New("en")
+	if err := c.Override(TaskDone, "Shipped, {{.Name}}!"); err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	if got := c.Render(TaskDone, map[string]string{"Name": "team"}); got != "Shipped, team!" {
+		t.Errorf("unexpected render: %q", got)
+	}
+}
+
+func TestOverride_InvalidTemplate(t *testing.T) {
+	c := New("en")
+	if err := c.Override(TaskDone, "{{.Broken"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestLoad_WithOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := OverridePath(dir, "es")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"task.done": "¡Terminado!"}`), 0644)
+
+	c, err := Load(dir, "es")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := c.Render(TaskDone, nil); got != "¡Terminado!" {
+		t.Errorf("expected override, got %q", got)
+	}
+	// Non-overridden keys keep the built-in locale text
+	if got := c.Render(WorkflowMenuFooter, nil); got != "¿Qué querés hacer?" {
+		t.Errorf("unexpected render: %q", got)
+	}
+}
+
+func TestLoad_NoOverrideFile(t *testing.T) {
+	c, err := Load(t.TempDir(), "en")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := c.Render(TaskDone, nil); got != "Done ✓" {
+		t.Errorf("unexpected render: %q", got)
+	}
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := OverridePath(dir, "en")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{not json`), 0644)
+
+	if _, err := Load(dir, "en"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestLocales(t *testing.T) {
+	locales := Locales()
+	if len(locales) != 2 || locales[0] != "en" || locales[1] != "es" {
+		t.Errorf("unexpected locales: %v", locales)
+	}
+}
+
+func TestBuiltinLocalesHaveSameKeys(t *testing.T) {
+	for locale, templates := range builtin {
+		for key := range builtin[DefaultLocale] {
+			if _, ok := templates[key]; !ok {
+				t.Errorf("locale %q missing key %q", locale, key)
+			}
+		}
+	}
+}
+
+func TestSystemLocale(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"LANG": "es_AR.UTF-8"}, "es"},
+		{map[string]string{"LC_ALL": "en_US.UTF-8", "LANG": "es_ES.UTF-8"}, "en"},
+		{map[string]string{"LC_MESSAGES": "de_DE@euro"}, "de"},
+		{map[string]string{"LANG": "C.UTF-8"}, ""},
+		{map[string]string{"LC_ALL": "POSIX", "LANG": "es_AR"}, ""},
+		{map[string]string{}, ""},
+	}
+	for _, tt := range tests {
+		got := SystemLocale(func(k string) string { return tt.env[k] })
+		if got != tt.want {
+			t.Errorf("SystemLocale(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_AR.UTF-8")
+
+	if got := Resolve("en"); got != "en" {
+		t.Errorf("configured locale should win, got %q", got)
+	}
+	if got := Resolve(""); got != "es" {
+		t.Errorf("expected system locale, got %q", got)
+	}
+	if got := Resolve("fr"); got != "es" {
+		t.Errorf("unknown configured locale should fall through, got %q", got)
+	}
+
+	t.Setenv("LANG", "ja_JP.UTF-8")
+	if got := Resolve(""); got != DefaultLocale {
+		t.Errorf("expected default, got %q", got)
+	}
+}
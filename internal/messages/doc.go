@@ -0,0 +1,3 @@
+// Package messages provides the catalog of user-facing bot strings with
+// built-in locales and per-repo template overrides.
+package messages
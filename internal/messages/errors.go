@@ -0,0 +1,158 @@
+package messages
+
+const (
+	// ErrorReport frames a classified error. Data: Message, Code.
+	ErrorReport Key = "error.report"
+	// ErrorRemediationHeader introduces an error's remediation steps.
+	ErrorRemediationHeader Key = "error.remediation_header"
+)
+
+// ErrorMessage is the key of the one-line description for an errreport
+// code. Its data depends on the code: Spent and Limit for budgets, Model
+// for per-model budgets, Command for a missing binary, RetryAfter and
+// SlackError for Slack.
+func ErrorMessage(code string) Key {
+	return Key("error." + code)
+}
+
+// ErrorRemediation is the key of an errreport code's remediation steps, one
+// per line. It takes the same data as ErrorMessage.
+func ErrorRemediation(code string) Key {
+	return Key("error." + code + ".remediation")
+}
+
+// builtinErrors holds the error texts per locale, merged into builtin.
+var builtinErrors = map[string]map[Key]string{
+	"en": {
+		ErrorReport:            ":warning: {{.Message}} (error `{{.Code}}`)",
+		ErrorRemediationHeader: "What you can do:",
+
+		ErrorMessage("CB-000"):     "An unexpected error occurred",
+		ErrorRemediation("CB-000"): "Retry the request\nIf it persists, check the agent logs for this error code",
+
+		ErrorMessage("CB-LLM-001"):     "The model provider is rate limiting requests",
+		ErrorRemediation("CB-LLM-001"): "Wait a few minutes and retry\nLower limits.maxCallsPerHour or pick a less busy model",
+		ErrorMessage("CB-LLM-002"):     "The model provider is overloaded or unavailable",
+		ErrorRemediation("CB-LLM-002"): "Retry in a few minutes\nConfigure a fallbackModel for this role",
+		ErrorMessage("CB-LLM-003"):     "The conversation is too long for the model's context window",
+		ErrorRemediation("CB-LLM-003"): "Start a new thread with a shorter summary of the task\nUse a model with a larger context window",
+		ErrorMessage("CB-LLM-004"):     "The model provider refused the request (content filter)",
+		ErrorRemediation("CB-LLM-004"): "Rephrase the request\nRemove sensitive or flagged content from the thread",
+		ErrorMessage("CB-LLM-005"):     "The model provider rejected the API key",
+		ErrorRemediation("CB-LLM-005"): "Check openrouter.apiKey in ~/.codebutler/config.json\nVerify the key has credit at openrouter.ai",
+		ErrorMessage("CB-LLM-006"):     "The model returned a response that could not be parsed",
+		ErrorRemediation("CB-LLM-006"): "Retry the request\nSwitch to a different model if it keeps happening",
+		ErrorMessage("CB-LLM-007"):     "The model provider did not respond in time",
+		ErrorRemediation("CB-LLM-007"): "Retry the request",
+		ErrorMessage("CB-LLM-099"):     "The model provider returned an unexpected error",
+		ErrorRemediation("CB-LLM-099"): "Retry the request\nCheck the OpenRouter status page",
+
+		ErrorMessage("CB-BUD-001"):     "This thread reached its budget (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-001"): "Reply /approve-budget to resume with a fresh allocation\nRaise the per-thread limit in the repo config",
+		ErrorMessage("CB-BUD-002"):     "The daily budget was reached (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-002"): "Work resumes automatically tomorrow\nAn approver can reply /approve-budget global to continue today\nRaise the per-day limit in the repo config",
+		ErrorMessage("CB-BUD-003"):     "This month's budget was reached (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-003"): "An approver can reply /approve-budget global to continue with a fresh allocation\nWork resumes automatically next month\nRaise the per-month limit in the budget config",
+		ErrorMessage("CB-BUD-004"):     "This month's {{.Model}} budget was reached (${{printf \"%.2f\" .Spent}} of ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-004"): "Switch the role to a cheaper model\nAn approver can reply /approve-budget global to continue with a fresh allocation\nRaise the per-model limit in the budget config",
+
+		ErrorMessage("CB-GIT-001"):     "The working directory is not a git repository",
+		ErrorRemediation("CB-GIT-001"): "Run codebutler from inside the repository\nCheck that the worktree was not deleted",
+		ErrorMessage("CB-GIT-002"):     "Git hit a merge conflict",
+		ErrorRemediation("CB-GIT-002"): "Ask the Coder to rebase on the base branch and resolve conflicts\nResolve the conflict manually and push",
+		ErrorMessage("CB-GIT-003"):     "Git could not authenticate with the remote",
+		ErrorRemediation("CB-GIT-003"): "Check the git credentials of the agent service user\nConfigure a credential helper or SSH key for the agent service user",
+		ErrorMessage("CB-GIT-004"):     "The remote rejected the push",
+		ErrorRemediation("CB-GIT-004"): "Pull the latest changes and retry\nCheck branch protection rules",
+		ErrorMessage("CB-GIT-005"):     "The GitHub CLI is not authenticated",
+		ErrorRemediation("CB-GIT-005"): "Run `gh auth login` as the agent service user",
+
+		ErrorMessage("CB-CMD-001"):     "Required command {{printf \"%q\" .Command}} was not found",
+		ErrorRemediation("CB-CMD-001"): "Install {{.Command}} and make sure it is on the PATH of the agent service",
+		ErrorMessage("CB-CMD-002"):     "The operation timed out",
+		ErrorRemediation("CB-CMD-002"): "Retry the request\nSplit the task into smaller steps if it keeps timing out",
+		ErrorMessage("CB-CMD-003"):     "The operation was cancelled",
+		ErrorRemediation("CB-CMD-003"): "",
+
+		ErrorMessage("CB-MSG-001"):     "Slack rejected the bot token",
+		ErrorRemediation("CB-MSG-001"): "Check slack.botToken and slack.appToken in ~/.codebutler/config.json\nReinstall the Slack app if the token was revoked",
+		ErrorMessage("CB-MSG-002"):     "The configured Slack channel does not exist",
+		ErrorRemediation("CB-MSG-002"): "Check slack.channelID in .codebutler/config.json",
+		ErrorMessage("CB-MSG-003"):     "The bot cannot post in the configured Slack channel",
+		ErrorRemediation("CB-MSG-003"): "Invite the bot to the channel with /invite\nUnarchive the channel if it was archived",
+		ErrorMessage("CB-MSG-004"):     "Slack is rate limiting messages (retry after {{.RetryAfter}})",
+		ErrorRemediation("CB-MSG-004"): "Wait a moment — the message will be retried automatically",
+		ErrorMessage("CB-MSG-099"):     "Slack returned an error ({{.SlackError}})",
+		ErrorRemediation("CB-MSG-099"): "Retry the request\nCheck the Slack app configuration",
+	},
+	"es": {
+		ErrorReport:            ":warning: {{.Message}} (error `{{.Code}}`)",
+		ErrorRemediationHeader: "Qué podés hacer:",
+
+		ErrorMessage("CB-000"):     "Ocurrió un error inesperado",
+		ErrorRemediation("CB-000"): "Reintentá el pedido\nSi persiste, buscá este código de error en los logs del agente",
+
+		ErrorMessage("CB-LLM-001"):     "El proveedor del modelo está limitando los pedidos",
+		ErrorRemediation("CB-LLM-001"): "Esperá unos minutos y reintentá\nBajá limits.maxCallsPerHour o elegí un modelo menos cargado",
+		ErrorMessage("CB-LLM-002"):     "El proveedor del modelo está sobrecargado o no disponible",
+		ErrorRemediation("CB-LLM-002"): "Reintentá en unos minutos\nConfigurá un fallbackModel para este rol",
+		ErrorMessage("CB-LLM-003"):     "La conversación es demasiado larga para la ventana de contexto del modelo",
+		ErrorRemediation("CB-LLM-003"): "Abrí un hilo nuevo con un resumen más corto de la tarea\nUsá un modelo con una ventana de contexto más grande",
+		ErrorMessage("CB-LLM-004"):     "El proveedor del modelo rechazó el pedido (filtro de contenido)",
+		ErrorRemediation("CB-LLM-004"): "Reformulá el pedido\nSacá del hilo el contenido sensible o marcado",
+		ErrorMessage("CB-LLM-005"):     "El proveedor del modelo rechazó la API key",
+		ErrorRemediation("CB-LLM-005"): "Revisá openrouter.apiKey en ~/.codebutler/config.json\nVerificá que la key tenga crédito en openrouter.ai",
+		ErrorMessage("CB-LLM-006"):     "El modelo devolvió una respuesta que no se pudo interpretar",
+		ErrorRemediation("CB-LLM-006"): "Reintentá el pedido\nCambiá de modelo si sigue pasando",
+		ErrorMessage("CB-LLM-007"):     "El proveedor del modelo no respondió a tiempo",
+		ErrorRemediation("CB-LLM-007"): "Reintentá el pedido",
+		ErrorMessage("CB-LLM-099"):     "El proveedor del modelo devolvió un error inesperado",
+		ErrorRemediation("CB-LLM-099"): "Reintentá el pedido\nRevisá la página de estado de OpenRouter",
+
+		ErrorMessage("CB-BUD-001"):     "Este hilo llegó a su presupuesto (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-001"): "Respondé /approve-budget para seguir con una asignación nueva\nSubí el límite por hilo en la config del repo",
+		ErrorMessage("CB-BUD-002"):     "Se alcanzó el presupuesto diario (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-002"): "El trabajo se retoma solo mañana\nUn aprobador puede responder /approve-budget global para seguir hoy\nSubí el límite diario en la config del repo",
+		ErrorMessage("CB-BUD-003"):     "Se alcanzó el presupuesto del mes (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-003"): "Un aprobador puede responder /approve-budget global para seguir con una asignación nueva\nEl trabajo se retoma solo el mes que viene\nSubí el límite mensual en la config de presupuesto",
+		ErrorMessage("CB-BUD-004"):     "Se alcanzó el presupuesto del mes para {{.Model}} (${{printf \"%.2f\" .Spent}} de ${{printf \"%.2f\" .Limit}})",
+		ErrorRemediation("CB-BUD-004"): "Pasá el rol a un modelo más barato\nUn aprobador puede responder /approve-budget global para seguir con una asignación nueva\nSubí el límite por modelo en la config de presupuesto",
+
+		ErrorMessage("CB-GIT-001"):     "El directorio de trabajo no es un repositorio git",
+		ErrorRemediation("CB-GIT-001"): "Corré codebutler desde adentro del repositorio\nRevisá que el worktree no se haya borrado",
+		ErrorMessage("CB-GIT-002"):     "Git encontró un conflicto de merge",
+		ErrorRemediation("CB-GIT-002"): "Pedile al Coder que haga rebase sobre la rama base y resuelva los conflictos\nResolvé el conflicto a mano y pusheá",
+		ErrorMessage("CB-GIT-003"):     "Git no se pudo autenticar con el remoto",
+		ErrorRemediation("CB-GIT-003"): "Revisá las credenciales de git del usuario del servicio\nConfigurá un credential helper o una clave SSH para el usuario del servicio",
+		ErrorMessage("CB-GIT-004"):     "El remoto rechazó el push",
+		ErrorRemediation("CB-GIT-004"): "Traé los últimos cambios y reintentá\nRevisá las reglas de protección de la rama",
+		ErrorMessage("CB-GIT-005"):     "El CLI de GitHub no está autenticado",
+		ErrorRemediation("CB-GIT-005"): "Corré `gh auth login` con el usuario del servicio",
+
+		ErrorMessage("CB-CMD-001"):     "No se encontró el comando requerido {{printf \"%q\" .Command}}",
+		ErrorRemediation("CB-CMD-001"): "Instalá {{.Command}} y asegurate de que esté en el PATH del servicio",
+		ErrorMessage("CB-CMD-002"):     "La operación tardó demasiado",
+		ErrorRemediation("CB-CMD-002"): "Reintentá el pedido\nDividí la tarea en pasos más chicos si sigue pasando",
+		ErrorMessage("CB-CMD-003"):     "La operación se canceló",
+		ErrorRemediation("CB-CMD-003"): "",
+
+		ErrorMessage("CB-MSG-001"):     "Slack rechazó el token del bot",
+		ErrorRemediation("CB-MSG-001"): "Revisá slack.botToken y slack.appToken en ~/.codebutler/config.json\nReinstalá la app de Slack si el token fue revocado",
+		ErrorMessage("CB-MSG-002"):     "El canal de Slack configurado no existe",
+		ErrorRemediation("CB-MSG-002"): "Revisá slack.channelID en .codebutler/config.json",
+		ErrorMessage("CB-MSG-003"):     "El bot no puede publicar en el canal de Slack configurado",
+		ErrorRemediation("CB-MSG-003"): "Invitá al bot al canal con /invite\nDesarchivá el canal si estaba archivado",
+		ErrorMessage("CB-MSG-004"):     "Slack está limitando los mensajes (reintento en {{.RetryAfter}})",
+		ErrorRemediation("CB-MSG-004"): "Esperá un momento: el mensaje se reintenta solo",
+		ErrorMessage("CB-MSG-099"):     "Slack devolvió un error ({{.SlackError}})",
+		ErrorRemediation("CB-MSG-099"): "Reintentá el pedido\nRevisá la configuración de la app de Slack",
+	},
+}
+
+func init() {
+	for locale, templates := range builtinErrors {
+		for key, text := range templates {
+			builtin[locale][key] = text
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package messages
+
+import (
+	"os"
+	"strings"
+)
+
+// localeEnv lists the POSIX variables consulted for the system locale, in
+// precedence order.
+var localeEnv = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// SystemLocale returns the language part of the first set POSIX locale
+// variable ("es_AR.UTF-8" → "es"). It returns "" for the C/POSIX locale or
+// when nothing is set. getenv is usually os.Getenv.
+func SystemLocale(getenv func(string) string) string {
+	for _, name := range localeEnv {
+		v := getenv(name)
+		if v == "" {
+			continue
+		}
+		if v == "C" || v == "POSIX" || strings.HasPrefix(v, "C.") {
+			return ""
+		}
+		lang, _, _ := strings.Cut(v, ".")
+		lang, _, _ = strings.Cut(lang, "@")
+		lang, _, _ = strings.Cut(lang, "_")
+		lang, _, _ = strings.Cut(lang, "-")
+		return strings.ToLower(lang)
+	}
+	return ""
+}
+
+// Resolve picks the locale to use: the configured one when it is built in,
+// otherwise the system locale when it is built in, otherwise DefaultLocale.
+func Resolve(configured string) string {
+	for _, l := range []string{configured, SystemLocale(os.Getenv)} {
+		if _, ok := builtin[l]; ok && l != "" {
+			return l
+		}
+	}
+	return DefaultLocale
+}
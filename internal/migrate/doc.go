@@ -0,0 +1,11 @@
+// Package migrate applies versioned SQL migrations to a SQLite database.
+// The schema version lives in PRAGMA user_version, so an existing database
+// file carries its own version and upgrades in place: each pending
+// migration runs in its own transaction together with the version bump,
+// and a failed migration leaves the database at the last good version.
+//
+// Stores keep their migrations next to the code as NNNN_name.sql files,
+// embed them with //go:embed, and call Run when opening the database.
+// Databases without user_version (Postgres) keep the version in a table
+// instead; see WithVersionTable. Drivers are not bundled, as in sqldb.
+package migrate
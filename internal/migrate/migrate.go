@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is one schema step.
+type Migration struct {
+	Version int    // from the file name prefix; 1-based and contiguous
+	Name    string // file name without the version prefix and extension
+	SQL     string
+}
+
+var fileRe = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_-]+)\.sql$`)
+
+// Load reads NNNN_name.sql files from dir in fsys. Versions must start at 1
+// and have no gaps or duplicates, so a missing file is caught at startup
+// rather than silently skipped. Other files are ignored.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: m[2], SQL: string(data)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d_%s: expected version %d (versions must be contiguous from 1)", m.Version, m.Name, i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// Result reports what Run did.
+type Result struct {
+	From    int // user_version before
+	To      int // user_version after
+	Applied []Migration
+}
+
+// Option configures Run.
+type Option func(*runner)
+
+type runner struct {
+	logger *slog.Logger
+	table  string // version table; empty uses PRAGMA user_version
+}
+
+// WithLogger sets the logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(r *runner) {
+		r.logger = l
+	}
+}
+
+// WithVersionTable keeps the schema version in a one-row table instead of
+// PRAGMA user_version, for databases other than SQLite. The table is
+// created on first use.
+func WithVersionTable(name string) Option {
+	return func(r *runner) {
+		r.table = name
+	}
+}
+
+// Version returns the database's PRAGMA user_version (0 for a new file).
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	var v int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&v); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return v, nil
+}
+
+// Run applies every migration above the database's current version, in
+// order. A database newer than the latest migration is refused: it was
+// written by a newer build and this one would misread it.
+func Run(ctx context.Context, db *sql.DB, migrations []Migration, opts ...Option) (Result, error) {
+	r := &runner{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	current, err := r.version(ctx, db)
+	if err != nil {
+		return Result{}, err
+	}
+	res := Result{From: current, To: current}
+	latest := len(migrations)
+	if current > latest {
+		return res, fmt.Errorf("database schema version %d is newer than this build supports (%d); upgrade codebutler", current, latest)
+	}
+
+	for _, m := range migrations[current:] {
+		if err := r.apply(ctx, db, m); err != nil {
+			return res, fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		res.To = m.Version
+		res.Applied = append(res.Applied, m)
+		r.logger.Info("applied schema migration", "version", m.Version, "name", m.Name)
+	}
+	return res, nil
+}
+
+func (r *runner) version(ctx context.Context, db *sql.DB) (int, error) {
+	if r.table == "" {
+		return Version(ctx, db)
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version INTEGER NOT NULL)", r.table)); err != nil {
+		return 0, fmt.Errorf("create version table: %w", err)
+	}
+	var v int
+	err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT version FROM %s", r.table)).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version) VALUES (0)", r.table)); err != nil {
+			return 0, fmt.Errorf("init version table: %w", err)
+		}
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return v, nil
+}
+
+// setVersion is the statement recording m as applied. Neither form
+// accepts bound parameters.
+func (r *runner) setVersion(v int) string {
+	if r.table == "" {
+		return fmt.Sprintf("PRAGMA user_version = %d", v)
+	}
+	return fmt.Sprintf("UPDATE %s SET version = %d", r.table, v)
+}
+
+// apply runs m and the version bump in one transaction. SQLite's
+// user_version is part of the database header and rolls back with it, as
+// does the version table on databases with transactional DDL.
+func (r *runner) apply(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range Statements(m.SQL) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, r.setVersion(m.Version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Statements splits a migration file on semicolons that end a line,
+// dropping blank statements and full-line "--" comments. Migrations that
+// need semicolons mid-line (triggers, string literals) keep them on the
+// same line as other text.
+func Statements(src string) []string {
+	var stmts []string
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		cur.Reset()
+	}
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	flush()
+	return stmts
+}
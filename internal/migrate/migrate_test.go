@@ -0,0 +1,209 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// fakeDriver keeps a user_version and the executed statements, applying a
+// transaction's work only on commit like SQLite does.
+type fakeDriver struct {
+	version   int
+	applied   []string
+	tableInit bool // version table has its row
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct {
+	d       *fakeDriver
+	pending []string
+	version int
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.pending, c.version = nil, c.d.version
+	return &fakeTx{c: c}, nil
+}
+
+var setVersion = regexp.MustCompile(`^(?:PRAGMA user_version|UPDATE schema_version SET version) = (\d+)$`)
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "FAIL") {
+		return nil, errors.New("syntax error")
+	}
+	switch m := setVersion.FindStringSubmatch(query); {
+	case m != nil:
+		c.version, _ = strconv.Atoi(m[1])
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS schema_version"):
+	case strings.HasPrefix(query, "INSERT INTO schema_version"):
+		c.d.tableInit = true
+	default:
+		c.pending = append(c.pending, query)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	switch query {
+	case "PRAGMA user_version":
+		return &versionRows{v: int64(c.d.version)}, nil
+	case "SELECT version FROM schema_version":
+		return &versionRows{v: int64(c.d.version), done: !c.d.tableInit}, nil
+	}
+	return nil, errors.New("unexpected query " + query)
+}
+
+type fakeTx struct{ c *fakeConn }
+
+func (t *fakeTx) Commit() error {
+	t.c.d.applied = append(t.c.d.applied, t.c.pending...)
+	t.c.d.version = t.c.version
+	return nil
+}
+
+func (t *fakeTx) Rollback() error { return nil }
+
+type versionRows struct {
+	v    int64
+	done bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"user_version"} }
+func (r *versionRows) Close() error      { return nil }
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0], r.done = r.v, true
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() { sql.Register("migrate-fake", fake) }
+
+func openFake(t *testing.T, version int) *sql.DB {
+	t.Helper()
+	fake.version, fake.applied, fake.tableInit = version, nil, false
+	db, err := sql.Open("migrate-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func quiet() Option {
+	return WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+var testFS = fstest.MapFS{
+	"migrations/0001_tasks.sql":  {Data: []byte("-- task store\nCREATE TABLE tasks (id TEXT PRIMARY KEY);\nCREATE INDEX tasks_id ON tasks (id);\n")},
+	"migrations/0002_audit.sql":  {Data: []byte("CREATE TABLE audit (\n  id INTEGER PRIMARY KEY,\n  tool TEXT\n);\n")},
+	"migrations/0003_budget.sql": {Data: []byte("ALTER TABLE tasks ADD COLUMN cost REAL;")},
+	"migrations/README.md":       {Data: []byte("not a migration")},
+}
+
+func TestLoad(t *testing.T) {
+	ms, err := Load(testFS, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 3 || ms[0].Name != "tasks" || ms[2].Version != 3 {
+		t.Fatalf("migrations = %+v", ms)
+	}
+
+	gap := fstest.MapFS{
+		"m/0001_a.sql": {Data: []byte("SELECT 1;")},
+		"m/0003_c.sql": {Data: []byte("SELECT 1;")},
+	}
+	if _, err := Load(gap, "m"); err == nil || !strings.Contains(err.Error(), "expected version 2") {
+		t.Errorf("gap not rejected: %v", err)
+	}
+}
+
+func TestStatements(t *testing.T) {
+	got := Statements("-- comment\nCREATE TABLE a (\n  x INT\n);\n\nINSERT INTO a VALUES (1);\nSELECT 1")
+	if len(got) != 3 || !strings.HasPrefix(got[0], "CREATE TABLE a (") || got[2] != "SELECT 1" {
+		t.Errorf("Statements = %q", got)
+	}
+}
+
+func TestRun_FreshAndIncremental(t *testing.T) {
+	ms, _ := Load(testFS, "migrations")
+	db := openFake(t, 0)
+
+	res, err := Run(context.Background(), db, ms[:2], quiet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.From != 0 || res.To != 2 || len(res.Applied) != 2 || len(fake.applied) != 3 {
+		t.Fatalf("res = %+v, applied = %q", res, fake.applied)
+	}
+
+	// A later build adds a migration: only that one runs.
+	res, err = Run(context.Background(), db, ms, quiet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.From != 2 || res.To != 3 || len(res.Applied) != 1 || fake.applied[3] != "ALTER TABLE tasks ADD COLUMN cost REAL;" {
+		t.Fatalf("res = %+v, applied = %q", res, fake.applied)
+	}
+
+	if res, _ := Run(context.Background(), db, ms, quiet()); len(res.Applied) != 0 {
+		t.Error("up-to-date database should apply nothing")
+	}
+}
+
+func TestRun_FailureKeepsLastGoodVersion(t *testing.T) {
+	ms := []Migration{
+		{Version: 1, Name: "ok", SQL: "CREATE TABLE a (x INT);"},
+		{Version: 2, Name: "bad", SQL: "CREATE TABLE b (x INT);\nFAIL;"},
+	}
+	db := openFake(t, 0)
+
+	res, err := Run(context.Background(), db, ms, quiet())
+	if err == nil || !strings.Contains(err.Error(), "0002_bad") {
+		t.Fatalf("err = %v", err)
+	}
+	if res.To != 1 || fake.version != 1 || len(fake.applied) != 1 {
+		t.Errorf("res = %+v, version = %d, applied = %q", res, fake.version, fake.applied)
+	}
+}
+
+func TestRun_RefusesNewerDatabase(t *testing.T) {
+	db := openFake(t, 5)
+	ms, _ := Load(testFS, "migrations")
+	if _, err := Run(context.Background(), db, ms, quiet()); err == nil || !strings.Contains(err.Error(), "newer than this build") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRun_VersionTable(t *testing.T) {
+	ms, _ := Load(testFS, "migrations")
+	db := openFake(t, 0)
+
+	res, err := Run(context.Background(), db, ms, quiet(), WithVersionTable("schema_version"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.To != 3 || fake.version != 3 || !fake.tableInit {
+		t.Fatalf("res = %+v, version = %d", res, fake.version)
+	}
+	if res, _ := Run(context.Background(), db, ms, quiet(), WithVersionTable("schema_version")); len(res.Applied) != 0 {
+		t.Error("up-to-date database should apply nothing")
+	}
+}
@@ -1,5 +1,7 @@
 package multimodel
 
+import "strings"
+
 // modelPricing maps model IDs to per-million-token prices (input, output).
 // These are approximate rates — used for estimation, not billing.
 var modelPricing = map[string][2]float64{
@@ -21,6 +23,10 @@ var modelPricing = map[string][2]float64{
 	"moonshotai/kimi-k2":       {0.6, 2.0},
 }
 
+// localModelPrefix marks models served by a local Ollama instance, which
+// cost nothing per token.
+const localModelPrefix = "ollama/"
+
 // defaultInputPrice is used when a model isn't in the pricing table.
 const defaultInputPrice = 3.0
 const defaultOutputPrice = 15.0
@@ -62,6 +68,9 @@ func modelPrice(model string) (float64, float64) {
 	if prices, ok := modelPricing[model]; ok {
 		return prices[0], prices[1]
 	}
+	if strings.HasPrefix(model, localModelPrefix) {
+		return 0, 0
+	}
 	return defaultInputPrice, defaultOutputPrice
 }
 
@@ -0,0 +1,46 @@
+package observability
+
+import (
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/tools"
+)
+
+// FromConfig builds the named log and metric backends from the global
+// config. Unconfigured backends are omitted; both maps may be empty.
+func FromConfig(cfg *config.GlobalObservability) (map[string]tools.LogSource, map[string]tools.MetricSource) {
+	logs := make(map[string]tools.LogSource)
+	metrics := make(map[string]tools.MetricSource)
+	if cfg == nil {
+		return logs, metrics
+	}
+
+	if cfg.Loki != nil && cfg.Loki.URL != "" {
+		logs["loki"] = NewLoki(*cfg.Loki, nil)
+	}
+	if cfg.Prometheus != nil && cfg.Prometheus.URL != "" {
+		metrics["prometheus"] = NewPrometheus(*cfg.Prometheus, nil)
+	}
+	if cfg.CloudWatch != nil && cfg.CloudWatch.Region != "" {
+		logs["cloudwatch"] = NewCloudWatch(*cfg.CloudWatch, nil)
+	}
+	if cfg.Datadog != nil && cfg.Datadog.APIKey != "" {
+		dd := NewDatadog(*cfg.Datadog, nil)
+		logs["datadog"] = dd
+		metrics["datadog"] = dd
+	}
+	return logs, metrics
+}
+
+// Tools returns the LogsQuery and MetricsQuery tools for the configured
+// backends, skipping a tool when it has no backend.
+func Tools(cfg *config.GlobalObservability) []tools.Tool {
+	logs, metrics := FromConfig(cfg)
+	var out []tools.Tool
+	if len(logs) > 0 {
+		out = append(out, tools.NewLogsQueryTool(logs))
+	}
+	if len(metrics) > 0 {
+		out = append(out, tools.NewMetricsQueryTool(metrics))
+	}
+	return out
+}
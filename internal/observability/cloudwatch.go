@@ -0,0 +1,82 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/sigv4"
+	"github.com/leandrotocalini/codebutler/internal/tools"
+)
+
+// CloudWatch queries CloudWatch Logs via FilterLogEvents. Requests are
+// signed with SigV4 directly, so no AWS SDK is needed.
+type CloudWatch struct {
+	cfg      config.CloudWatchConfig
+	client   HTTPDoer
+	endpoint string
+	now      func() time.Time
+}
+
+// NewCloudWatch creates a CloudWatch Logs backend.
+func NewCloudWatch(cfg config.CloudWatchConfig, client HTTPDoer) *CloudWatch {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &CloudWatch{
+		cfg:      cfg,
+		client:   client,
+		endpoint: fmt.Sprintf("https://logs.%s.amazonaws.com/", cfg.Region),
+		now:      time.Now,
+	}
+}
+
+// QueryLogs implements tools.LogSource. The log group is the configured
+// prefix plus the service name; the filter is a CloudWatch filter pattern.
+func (c *CloudWatch) QueryLogs(ctx context.Context, q tools.LogQuery) ([]tools.LogLine, error) {
+	body := map[string]any{
+		"logGroupName": c.cfg.LogGroupPrefix + q.Service,
+		"startTime":    q.Start.UnixMilli(),
+		"endTime":      q.End.UnixMilli(),
+		"limit":        q.Limit,
+	}
+	if q.Filter != "" {
+		body["filterPattern"] = q.Filter
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328.FilterLogEvents")
+	sigv4.Sign(req, data, c.cfg.Region, "logs", sigv4.Credentials{AccessKeyID: c.cfg.AccessKeyID, SecretAccessKey: c.cfg.SecretAccessKey}, c.now())
+
+	var resp struct {
+		Events []struct {
+			Timestamp int64  `json:"timestamp"`
+			Message   string `json:"message"`
+		} `json:"events"`
+	}
+	if err := doJSON(ctx, c.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	lines := make([]tools.LogLine, 0, len(resp.Events))
+	for _, e := range resp.Events {
+		lines = append(lines, tools.LogLine{
+			Time:    time.UnixMilli(e.Timestamp),
+			Service: q.Service,
+			Message: e.Message,
+		})
+	}
+	return lines, nil
+}
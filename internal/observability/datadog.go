@@ -0,0 +1,149 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/tools"
+)
+
+// Datadog queries logs (v2 search) and metrics (v1 query) from Datadog.
+type Datadog struct {
+	cfg    config.DatadogConfig
+	client HTTPDoer
+}
+
+// NewDatadog creates a Datadog backend.
+func NewDatadog(cfg config.DatadogConfig, client HTTPDoer) *Datadog {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	if cfg.Site == "" {
+		cfg.Site = "datadoghq.com"
+	}
+	return &Datadog{cfg: cfg, client: client}
+}
+
+func (d *Datadog) baseURL() string {
+	if strings.HasPrefix(d.cfg.Site, "http://") || strings.HasPrefix(d.cfg.Site, "https://") {
+		return strings.TrimRight(d.cfg.Site, "/")
+	}
+	return "https://api." + d.cfg.Site
+}
+
+func (d *Datadog) authorize(req *http.Request) {
+	req.Header.Set("DD-API-KEY", d.cfg.APIKey)
+	req.Header.Set("DD-APPLICATION-KEY", d.cfg.AppKey)
+}
+
+// QueryLogs implements tools.LogSource.
+func (d *Datadog) QueryLogs(ctx context.Context, q tools.LogQuery) ([]tools.LogLine, error) {
+	query := "service:" + q.Service
+	if q.Filter != "" {
+		query += " " + q.Filter
+	}
+	var body struct {
+		Filter struct {
+			Query string `json:"query"`
+			From  string `json:"from"`
+			To    string `json:"to"`
+		} `json:"filter"`
+		Sort string `json:"sort"`
+		Page struct {
+			Limit int `json:"limit"`
+		} `json:"page"`
+	}
+	body.Filter.Query = query
+	body.Filter.From = q.Start.UTC().Format(time.RFC3339)
+	body.Filter.To = q.End.UTC().Format(time.RFC3339)
+	body.Sort = "-timestamp"
+	body.Page.Limit = q.Limit
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, d.baseURL()+"/api/v2/logs/events/search", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	d.authorize(req)
+
+	var resp struct {
+		Data []struct {
+			Attributes struct {
+				Timestamp time.Time `json:"timestamp"`
+				Service   string    `json:"service"`
+				Status    string    `json:"status"`
+				Message   string    `json:"message"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, d.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	lines := make([]tools.LogLine, 0, len(resp.Data))
+	for _, e := range resp.Data {
+		lines = append(lines, tools.LogLine{
+			Time:    e.Attributes.Timestamp,
+			Service: e.Attributes.Service,
+			Level:   e.Attributes.Status,
+			Message: e.Attributes.Message,
+		})
+	}
+	return lines, nil
+}
+
+// QueryMetrics implements tools.MetricSource.
+func (d *Datadog) QueryMetrics(ctx context.Context, q tools.MetricQuery) ([]tools.MetricSeries, error) {
+	params := url.Values{}
+	params.Set("query", q.Query)
+	params.Set("from", strconv.FormatInt(q.Start.Unix(), 10))
+	params.Set("to", strconv.FormatInt(q.End.Unix(), 10))
+
+	req, err := http.NewRequest(http.MethodGet, d.baseURL()+"/api/v1/query?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	d.authorize(req)
+
+	var resp struct {
+		Series []struct {
+			Expression string        `json:"expression"`
+			Scope      string        `json:"scope"`
+			Pointlist  [][2]*float64 `json:"pointlist"`
+		} `json:"series"`
+	}
+	if err := doJSON(ctx, d.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var out []tools.MetricSeries
+	for _, s := range resp.Series {
+		name := s.Expression
+		if s.Scope != "" {
+			name += " {" + s.Scope + "}"
+		}
+		series := tools.MetricSeries{Name: name}
+		for _, p := range s.Pointlist {
+			if p[0] == nil || p[1] == nil {
+				continue
+			}
+			series.Points = append(series.Points, tools.MetricPoint{
+				Time:  time.UnixMilli(int64(*p[0])),
+				Value: *p[1],
+			})
+		}
+		out = append(out, series)
+	}
+	return out, nil
+}
@@ -0,0 +1,5 @@
+// Package observability implements the log and metric backends behind the
+// LogsQuery and MetricsQuery tools: Loki, Prometheus, CloudWatch Logs, and
+// Datadog. Each backend is a small HTTP client with no vendor SDK; backends
+// are built from the global config so credentials never live in the repo.
+package observability
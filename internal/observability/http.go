@@ -0,0 +1,47 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPDoer is the subset of *http.Client used by the backends.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// defaultHTTPClient bounds every backend request.
+var defaultHTTPClient HTTPDoer = &http.Client{Timeout: 30 * time.Second}
+
+// maxResponseBytes caps how much of a backend response is read.
+const maxResponseBytes = 10 << 20
+
+// doJSON sends req and decodes a JSON response into out. Non-2xx responses
+// become errors carrying a prefix of the body for diagnosis.
+func doJSON(ctx context.Context, client HTTPDoer, req *http.Request, out any) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := string(body)
+		if len(snippet) > 300 {
+			snippet = snippet[:300] + "..."
+		}
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
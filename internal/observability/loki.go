@@ -0,0 +1,199 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/tools"
+)
+
+// Loki queries logs through the Loki HTTP API (query_range).
+type Loki struct {
+	cfg    config.LokiConfig
+	client HTTPDoer
+}
+
+// NewLoki creates a Loki backend. A nil client uses a default with timeout.
+func NewLoki(cfg config.LokiConfig, client HTTPDoer) *Loki {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	if cfg.ServiceLabel == "" {
+		cfg.ServiceLabel = "service"
+	}
+	return &Loki{cfg: cfg, client: client}
+}
+
+// LogQL builds the query for a service: a stream selector on the service
+// label, followed by the filter. Filters starting with "|" are treated as a
+// raw LogQL pipeline; anything else becomes a line-contains filter.
+func (l *Loki) LogQL(service, filter string) string {
+	q := fmt.Sprintf("{%s=%s}", l.cfg.ServiceLabel, strconv.Quote(service))
+	filter = strings.TrimSpace(filter)
+	switch {
+	case filter == "":
+	case strings.HasPrefix(filter, "|"):
+		q += " " + filter
+	default:
+		q += " |= " + strconv.Quote(filter)
+	}
+	return q
+}
+
+// QueryLogs implements tools.LogSource.
+func (l *Loki) QueryLogs(ctx context.Context, q tools.LogQuery) ([]tools.LogLine, error) {
+	params := url.Values{}
+	params.Set("query", l.LogQL(q.Service, q.Filter))
+	params.Set("start", strconv.FormatInt(q.Start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(q.End.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(q.Limit))
+	params.Set("direction", "backward") // most recent lines first when limited
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(l.cfg.URL, "/")+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if l.cfg.Username != "" {
+		req.SetBasicAuth(l.cfg.Username, l.cfg.Password)
+	}
+	if l.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", l.cfg.TenantID)
+	}
+
+	var resp struct {
+		Data struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, l.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var lines []tools.LogLine
+	for _, stream := range resp.Data.Result {
+		level := stream.Stream["level"]
+		if level == "" {
+			level = stream.Stream["detected_level"]
+		}
+		for _, v := range stream.Values {
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			lines = append(lines, tools.LogLine{
+				Time:    time.Unix(0, ns),
+				Service: stream.Stream[l.cfg.ServiceLabel],
+				Level:   level,
+				Message: v[1],
+			})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	return lines, nil
+}
+
+// Prometheus queries metrics through the Prometheus HTTP API (query_range).
+// It also works against Mimir, Thanos, and VictoriaMetrics.
+type Prometheus struct {
+	cfg    config.PrometheusConfig
+	client HTTPDoer
+}
+
+// NewPrometheus creates a Prometheus backend.
+func NewPrometheus(cfg config.PrometheusConfig, client HTTPDoer) *Prometheus {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &Prometheus{cfg: cfg, client: client}
+}
+
+// QueryMetrics implements tools.MetricSource.
+func (p *Prometheus) QueryMetrics(ctx context.Context, q tools.MetricQuery) ([]tools.MetricSeries, error) {
+	step := q.Step
+	if step < time.Second {
+		step = time.Second
+	}
+	params := url.Values{}
+	params.Set("query", q.Query)
+	params.Set("start", strconv.FormatInt(q.Start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(q.End.Unix(), 10))
+	params.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(p.cfg.URL, "/")+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.cfg.Username != "" {
+		req.SetBasicAuth(p.cfg.Username, p.cfg.Password)
+	}
+
+	var resp struct {
+		Data struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+				Values [][2]any          `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var out []tools.MetricSeries
+	for _, r := range resp.Data.Result {
+		s := tools.MetricSeries{Name: promSeriesName(r.Metric)}
+		for _, v := range r.Values {
+			ts, ok := v[0].(float64)
+			if !ok {
+				continue
+			}
+			str, ok := v[1].(string)
+			if !ok {
+				continue
+			}
+			val, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				continue
+			}
+			s.Points = append(s.Points, tools.MetricPoint{
+				Time:  time.Unix(0, int64(ts*float64(time.Second))),
+				Value: val,
+			})
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// promSeriesName renders labels as name{k="v",...} with sorted keys.
+func promSeriesName(labels map[string]string) string {
+	name := labels["__name__"]
+	var keys []string
+	for k := range labels {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	if len(parts) == 0 && name == "" {
+		return "{}"
+	}
+	if len(parts) == 0 {
+		return name
+	}
+	return name + "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,213 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/tools"
+)
+
+func window() (time.Time, time.Time) {
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return end.Add(-15 * time.Minute), end
+}
+
+func TestLoki_QueryLogs(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"status":"success","data":{"resultType":"streams","result":[
+			{"stream":{"service":"checkout","level":"error"},"values":[
+				["1772366100000000000","payment timeout"],
+				["1772366040000000000","db pool exhausted"]
+			]}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	loki := NewLoki(config.LokiConfig{URL: srv.URL, TenantID: "team-a", Username: "u", Password: "p"}, nil)
+	start, end := window()
+	lines, err := loki.QueryLogs(context.Background(), tools.LogQuery{Service: "checkout", Filter: "timeout", Start: start, End: end, Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.URL.Path != "/loki/api/v1/query_range" {
+		t.Errorf("path = %s", got.URL.Path)
+	}
+	if q := got.URL.Query().Get("query"); q != `{service="checkout"} |= "timeout"` {
+		t.Errorf("query = %s", q)
+	}
+	if got.Header.Get("X-Scope-OrgID") != "team-a" {
+		t.Error("missing tenant header")
+	}
+	if _, _, ok := got.BasicAuth(); !ok {
+		t.Error("missing basic auth")
+	}
+	if len(lines) != 2 || lines[0].Message != "db pool exhausted" || lines[0].Level != "error" {
+		t.Errorf("lines should be sorted oldest first: %+v", lines)
+	}
+}
+
+func TestLoki_LogQL(t *testing.T) {
+	loki := NewLoki(config.LokiConfig{ServiceLabel: "app"}, nil)
+	tests := []struct{ filter, want string }{
+		{"", `{app="api"}`},
+		{"panic", `{app="api"} |= "panic"`},
+		{`| json | status >= 500`, `{app="api"} | json | status >= 500`},
+	}
+	for _, tt := range tests {
+		if got := loki.LogQL("api", tt.filter); got != tt.want {
+			t.Errorf("LogQL(%q) = %s, want %s", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestPrometheus_QueryMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("step") != "15" {
+			t.Errorf("step = %s", r.URL.Query().Get("step"))
+		}
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"__name__":"http_errors","route":"/pay","code":"500"},"values":[[1772366100,"3"],[1772366115,"7.5"]]}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	prom := NewPrometheus(config.PrometheusConfig{URL: srv.URL}, nil)
+	start, end := window()
+	series, err := prom.QueryMetrics(context.Background(), tools.MetricQuery{Query: "http_errors", Start: start, End: end, Step: 15 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].Name != `http_errors{code="500",route="/pay"}` {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+	if len(series[0].Points) != 2 || series[0].Points[1].Value != 7.5 {
+		t.Errorf("unexpected points: %+v", series[0].Points)
+	}
+}
+
+func TestDatadog_Logs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("DD-API-KEY") != "api" || r.Header.Get("DD-APPLICATION-KEY") != "app" {
+			t.Error("missing Datadog auth headers")
+		}
+		var body struct {
+			Filter struct{ Query string } `json:"filter"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Filter.Query != "service:checkout status:error" {
+			t.Errorf("query = %q", body.Filter.Query)
+		}
+		w.Write([]byte(`{"data":[{"attributes":{"timestamp":"2026-03-01T11:55:00Z","service":"checkout","status":"error","message":"card declined"}}]}`))
+	}))
+	defer srv.Close()
+
+	dd := NewDatadog(config.DatadogConfig{APIKey: "api", AppKey: "app", Site: srv.URL}, nil)
+	start, end := window()
+	lines, err := dd.QueryLogs(context.Background(), tools.LogQuery{Service: "checkout", Filter: "status:error", Start: start, End: end, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0].Message != "card declined" || lines[0].Level != "error" {
+		t.Errorf("unexpected lines: %+v", lines)
+	}
+}
+
+func TestDatadog_Metrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"series":[{"expression":"avg:latency{service:checkout}","pointlist":[[1772366100000,120.5],[1772366160000,null]]}]}`))
+	}))
+	defer srv.Close()
+
+	dd := NewDatadog(config.DatadogConfig{APIKey: "api", AppKey: "app", Site: srv.URL}, nil)
+	start, end := window()
+	series, err := dd.QueryMetrics(context.Background(), tools.MetricQuery{Query: "avg:latency{service:checkout}", Start: start, End: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || len(series[0].Points) != 1 || series[0].Points[0].Value != 120.5 {
+		t.Errorf("null points should be skipped: %+v", series)
+	}
+}
+
+func TestDatadog_DefaultSite(t *testing.T) {
+	dd := NewDatadog(config.DatadogConfig{}, nil)
+	if dd.baseURL() != "https://api.datadoghq.com" {
+		t.Errorf("got %s", dd.baseURL())
+	}
+}
+
+func TestCloudWatch_QueryLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "Logs_20140328.FilterLogEvents" {
+			t.Errorf("target = %s", r.Header.Get("X-Amz-Target"))
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20260301/us-east-1/logs/aws4_request") {
+			t.Errorf("authorization = %s", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"logGroupName":"/ecs/checkout"`) || !strings.Contains(string(body), `"filterPattern":"ERROR"`) {
+			t.Errorf("body = %s", body)
+		}
+		w.Write([]byte(`{"events":[{"timestamp":1772366100000,"message":"ERROR upstream reset"}]}`))
+	}))
+	defer srv.Close()
+
+	cw := NewCloudWatch(config.CloudWatchConfig{Region: "us-east-1", AccessKeyID: "AKID", SecretAccessKey: "secret", LogGroupPrefix: "/ecs/"}, nil)
+	cw.endpoint = srv.URL + "/"
+	cw.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	start, end := window()
+	lines, err := cw.QueryLogs(context.Background(), tools.LogQuery{Service: "checkout", Filter: "ERROR", Start: start, End: end, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0].Service != "checkout" {
+		t.Errorf("unexpected lines: %+v", lines)
+	}
+}
+
+func TestBackendErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	start, end := window()
+	_, err := NewLoki(config.LokiConfig{URL: srv.URL}, nil).QueryLogs(context.Background(), tools.LogQuery{Service: "x", Start: start, End: end, Limit: 1})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("expected HTTP 401 error, got %v", err)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	logs, metrics := FromConfig(nil)
+	if len(logs) != 0 || len(metrics) != 0 {
+		t.Error("nil config should yield no backends")
+	}
+
+	cfg := &config.GlobalObservability{
+		Loki:       &config.LokiConfig{URL: "http://loki:3100"},
+		Prometheus: &config.PrometheusConfig{URL: "http://prom:9090"},
+		CloudWatch: &config.CloudWatchConfig{Region: "eu-west-1"},
+		Datadog:    &config.DatadogConfig{APIKey: "k"},
+	}
+	logs, metrics = FromConfig(cfg)
+	if len(logs) != 3 || len(metrics) != 2 {
+		t.Errorf("got %d log and %d metric backends", len(logs), len(metrics))
+	}
+
+	ts := Tools(&config.GlobalObservability{Loki: cfg.Loki})
+	if len(ts) != 1 || ts[0].Name() != "LogsQuery" {
+		t.Errorf("expected only LogsQuery, got %d tools", len(ts))
+	}
+}
@@ -0,0 +1,261 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CriticalKind labels a message whose delivery must be confirmed.
+type CriticalKind string
+
+const (
+	KindApproval CriticalKind = "approval" // plan / destructive-tool approval prompts
+	KindResult   CriticalKind = "result"   // final task results
+)
+
+// CriticalStatus is the delivery state of a tracked message.
+type CriticalStatus string
+
+const (
+	StatusUndelivered  CriticalStatus = "undelivered"  // primary send failed
+	StatusDelivered    CriticalStatus = "delivered"    // posted, no response yet
+	StatusAcknowledged CriticalStatus = "acknowledged" // a human replied, reacted, or clicked
+	StatusFallback     CriticalStatus = "fallback"     // re-sent through the fallback channel
+)
+
+// maxFallbackAttempts caps how many Checks try the fallback for one
+// message; after that it is left for the digest.
+const maxFallbackAttempts = 3
+
+// CriticalMessage is a tracked approval prompt or final result.
+type CriticalMessage struct {
+	ID        string
+	Kind      CriticalKind
+	Channel   string
+	Thread    string
+	Text      string
+	Status    CriticalStatus
+	SentAt    time.Time
+	LastError string
+	Attempts  int // fallback sends tried so far
+}
+
+// CriticalTracker sends critical messages, watches for acknowledgment, and
+// re-sends through a fallback channel (e.g. an alert webhook or DM) when the
+// primary send failed or nobody responded within the ack timeout. Anything
+// still unresolved is listed in Digest.
+type CriticalTracker struct {
+	primary         Sender
+	fallback        Sender // optional
+	fallbackChannel string // optional; where fallback re-sends go
+	ackTimeout      time.Duration
+	logger          *slog.Logger
+	now             func() time.Time
+
+	mu   sync.Mutex
+	seq  int
+	msgs []*CriticalMessage
+}
+
+// CriticalOption configures a CriticalTracker.
+type CriticalOption func(*CriticalTracker)
+
+// WithFallback sets the alternate sender used for re-delivery.
+func WithFallback(s Sender) CriticalOption {
+	return func(c *CriticalTracker) {
+		c.fallback = s
+	}
+}
+
+// WithFallbackChannel sends fallback re-sends to channel (e.g. an
+// escalation channel or a DM) as new top-level messages that name the
+// original thread. Without it the fallback sender gets the original
+// channel and thread, which suits senders that deliver elsewhere by
+// themselves, such as an alert webhook; re-posting through Slack to the
+// thread nobody answered would not reach anyone new.
+func WithFallbackChannel(channel string) CriticalOption {
+	return func(c *CriticalTracker) {
+		c.fallbackChannel = channel
+	}
+}
+
+// WithAckTimeout sets how long to wait for a response before escalating (default 30m).
+func WithAckTimeout(d time.Duration) CriticalOption {
+	return func(c *CriticalTracker) {
+		c.ackTimeout = d
+	}
+}
+
+// WithCriticalLogger sets the logger.
+func WithCriticalLogger(l *slog.Logger) CriticalOption {
+	return func(c *CriticalTracker) {
+		c.logger = l
+	}
+}
+
+// WithCriticalClock sets the clock (for testing).
+func WithCriticalClock(now func() time.Time) CriticalOption {
+	return func(c *CriticalTracker) {
+		c.now = now
+	}
+}
+
+// NewCriticalTracker creates a tracker that sends through primary.
+func NewCriticalTracker(primary Sender, opts ...CriticalOption) *CriticalTracker {
+	c := &CriticalTracker{
+		primary:    primary,
+		ackTimeout: 30 * time.Minute,
+		logger:     slog.Default(),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Send delivers a critical message and starts tracking it. A failed send is
+// not returned as an error: it is recorded and retried via the fallback on
+// the next Check. The returned ID identifies the message in the digest.
+func (c *CriticalTracker) Send(ctx context.Context, kind CriticalKind, channel, thread, text string) string {
+	err := c.primary.SendMessage(ctx, channel, thread, text)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	m := &CriticalMessage{
+		ID:      fmt.Sprintf("crit-%d", c.seq),
+		Kind:    kind,
+		Channel: channel,
+		Thread:  thread,
+		Text:    text,
+		Status:  StatusDelivered,
+		SentAt:  c.now(),
+	}
+	if err != nil {
+		m.Status = StatusUndelivered
+		m.LastError = err.Error()
+		c.logger.Error("critical message not delivered", "id", m.ID, "kind", kind, "channel", channel, "err", err)
+	}
+	c.msgs = append(c.msgs, m)
+	return m.ID
+}
+
+// Acknowledge marks every tracked message in the thread as acknowledged.
+// Call it when a human replies in the thread, reacts, or clicks a button.
+func (c *CriticalTracker) Acknowledge(channel, thread string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range c.msgs {
+		if m.Channel == channel && m.Thread == thread && m.Status != StatusAcknowledged {
+			m.Status = StatusAcknowledged
+		}
+	}
+}
+
+// Check re-sends undelivered messages and unacknowledged ones past the ack
+// timeout through the fallback channel. A successful fallback is not
+// repeated; a failed one is retried on later Checks, up to
+// maxFallbackAttempts sends in all, after which the message is only listed
+// in Digest. Acknowledged messages are dropped from tracking.
+func (c *CriticalTracker) Check(ctx context.Context) {
+	type resend struct {
+		m                     *CriticalMessage
+		channel, thread, text string
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	var toResend []resend
+	kept := c.msgs[:0]
+	for _, m := range c.msgs {
+		if m.Status == StatusAcknowledged {
+			continue
+		}
+		kept = append(kept, m)
+		if c.fallback == nil || m.Attempts >= maxFallbackAttempts {
+			continue
+		}
+		if m.Status == StatusUndelivered || (m.Status == StatusDelivered && now.Sub(m.SentAt) >= c.ackTimeout) {
+			// Copy what the send needs: Acknowledge may change m once
+			// the lock is released.
+			r := resend{m: m, channel: m.Channel, thread: m.Thread,
+				text: fmt.Sprintf("[%s needs attention — %s] %s", m.Kind, reason(m.Status), m.Text)}
+			if c.fallbackChannel != "" {
+				r.channel, r.thread = c.fallbackChannel, ""
+				r.text = fmt.Sprintf("[%s in %s, thread %s needs attention — %s] %s",
+					m.Kind, m.Channel, m.Thread, reason(m.Status), m.Text)
+			}
+			m.Attempts++
+			toResend = append(toResend, r)
+		}
+	}
+	c.msgs = kept
+	c.mu.Unlock()
+
+	for _, r := range toResend {
+		err := c.fallback.SendMessage(ctx, r.channel, r.thread, r.text)
+
+		c.mu.Lock()
+		if err != nil {
+			r.m.LastError = err.Error()
+			c.logger.Error("critical fallback failed", "id", r.m.ID, "err", err)
+		} else if r.m.Status != StatusAcknowledged {
+			r.m.Status = StatusFallback
+			c.logger.Warn("critical message re-sent via fallback", "id", r.m.ID, "kind", r.m.Kind, "channel", r.channel)
+		}
+		c.mu.Unlock()
+	}
+}
+
+func reason(s CriticalStatus) string {
+	if s == StatusUndelivered {
+		return "original message failed to send"
+	}
+	return "no response yet"
+}
+
+// Unresolved returns tracked messages that are not yet acknowledged.
+func (c *CriticalTracker) Unresolved() []CriticalMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []CriticalMessage
+	for _, m := range c.msgs {
+		if m.Status != StatusAcknowledged {
+			out = append(out, *m)
+		}
+	}
+	return out
+}
+
+// Digest renders unresolved critical messages for a status or daily digest.
+// Returns "" when everything has been acknowledged.
+func (c *CriticalTracker) Digest() string {
+	pending := c.Unresolved()
+	if len(pending) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%d critical message(s) awaiting response:*\n", len(pending)))
+	for _, m := range pending {
+		preview := m.Text
+		if len(preview) > 60 {
+			cut := 60
+			for cut > 0 && !utf8.RuneStart(preview[cut]) {
+				cut--
+			}
+			preview = preview[:cut] + "..."
+		}
+		b.WriteString(fmt.Sprintf("- %s [%s, %s] thread %s: %s\n", m.ID, m.Kind, m.Status, m.Thread, preview))
+	}
+	return b.String()
+}
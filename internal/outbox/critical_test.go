@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestCriticalTracker_UndeliveredFallsBack(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	primary := &flakySender{down: true}
+	fallback := &flakySender{}
+	c := NewCriticalTracker(primary, WithFallback(fallback), WithCriticalLogger(testLogger()), WithCriticalClock(clock.now))
+
+	c.Send(context.Background(), KindApproval, "C1", "T1", "Approve plan?")
+	if u := c.Unresolved(); len(u) != 1 || u[0].Status != StatusUndelivered {
+		t.Fatalf("expected undelivered, got %+v", u)
+	}
+
+	c.Check(context.Background())
+	got := fallback.texts()
+	if len(got) != 1 || !strings.Contains(got[0], "failed to send") || !strings.Contains(got[0], "Approve plan?") {
+		t.Fatalf("unexpected fallback: %v", got)
+	}
+
+	// Falls back only once.
+	c.Check(context.Background())
+	if len(fallback.texts()) != 1 {
+		t.Error("fallback should be sent at most once")
+	}
+}
+
+func TestCriticalTracker_AckTimeout(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	fallback := &flakySender{}
+	c := NewCriticalTracker(&flakySender{}, WithFallback(fallback), WithAckTimeout(10*time.Minute),
+		WithCriticalLogger(testLogger()), WithCriticalClock(clock.now))
+
+	c.Send(context.Background(), KindResult, "C1", "T1", "PR ready")
+	c.Check(context.Background())
+	if len(fallback.texts()) != 0 {
+		t.Fatal("should not fall back before ack timeout")
+	}
+
+	clock.advance(11 * time.Minute)
+	c.Check(context.Background())
+	if got := fallback.texts(); len(got) != 1 || !strings.Contains(got[0], "no response yet") {
+		t.Errorf("unexpected fallback: %v", got)
+	}
+}
+
+func TestCriticalTracker_Acknowledge(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	fallback := &flakySender{}
+	c := NewCriticalTracker(&flakySender{}, WithFallback(fallback), WithAckTimeout(time.Minute),
+		WithCriticalLogger(testLogger()), WithCriticalClock(clock.now))
+
+	c.Send(context.Background(), KindApproval, "C1", "T1", "Approve?")
+	c.Send(context.Background(), KindApproval, "C1", "T2", "Approve other?")
+	c.Acknowledge("C1", "T1")
+
+	clock.advance(time.Hour)
+	c.Check(context.Background())
+
+	if got := fallback.texts(); len(got) != 1 || !strings.Contains(got[0], "Approve other?") {
+		t.Errorf("only the unacknowledged message should fall back, got %v", got)
+	}
+	if d := c.Digest(); !strings.Contains(d, "1 critical message") || !strings.Contains(d, "T2") {
+		t.Errorf("unexpected digest: %s", d)
+	}
+
+	c.Acknowledge("C1", "T2")
+	if d := c.Digest(); d != "" {
+		t.Errorf("expected empty digest, got %s", d)
+	}
+}
+
+func TestCriticalTracker_NoFallbackOnlyDigest(t *testing.T) {
+	c := NewCriticalTracker(&flakySender{down: true}, WithCriticalLogger(testLogger()))
+	c.Send(context.Background(), KindResult, "C1", "T1", "done")
+	c.Check(context.Background())
+
+	if d := c.Digest(); !strings.Contains(d, "undelivered") {
+		t.Errorf("digest should flag undelivered message: %s", d)
+	}
+}
+
+func TestCriticalTracker_FallbackChannel(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	fallback := &flakySender{}
+	c := NewCriticalTracker(&flakySender{down: true}, WithFallback(fallback), WithFallbackChannel("CESC"),
+		WithCriticalLogger(testLogger()), WithCriticalClock(clock.now))
+
+	c.Send(context.Background(), KindApproval, "C1", "T1", "Approve plan?")
+	c.Check(context.Background())
+
+	fallback.mu.Lock()
+	defer fallback.mu.Unlock()
+	if len(fallback.sent) != 1 {
+		t.Fatalf("fallbacks = %+v", fallback.sent)
+	}
+	got := fallback.sent[0]
+	if got.channel != "CESC" || got.thread != "" {
+		t.Errorf("fallback went to %s/%s, want the escalation channel top level", got.channel, got.thread)
+	}
+	if !strings.Contains(got.text, "C1, thread T1") || !strings.Contains(got.text, "Approve plan?") {
+		t.Errorf("fallback text = %q", got.text)
+	}
+}
+
+// countingSender counts every send attempt, failed or not.
+type countingSender struct {
+	flakySender
+	calls int
+}
+
+func (s *countingSender) SendMessage(ctx context.Context, channel, thread, text string) error {
+	s.calls++
+	return s.flakySender.SendMessage(ctx, channel, thread, text)
+}
+
+func TestCriticalTracker_FailedFallbackRetriesThenStops(t *testing.T) {
+	fallback := &countingSender{flakySender: flakySender{down: true}}
+	c := NewCriticalTracker(&flakySender{down: true}, WithFallback(fallback), WithCriticalLogger(testLogger()))
+	c.Send(context.Background(), KindApproval, "C1", "T1", "Approve plan?")
+
+	for range maxFallbackAttempts + 2 {
+		c.Check(context.Background())
+	}
+	if fallback.calls != maxFallbackAttempts {
+		t.Errorf("fallback attempts = %d, want %d", fallback.calls, maxFallbackAttempts)
+	}
+	if d := c.Digest(); !strings.Contains(d, "undelivered") {
+		t.Errorf("message should stay in the digest: %s", d)
+	}
+}
+
+func TestCriticalTracker_DigestPreviewRuneSafe(t *testing.T) {
+	c := NewCriticalTracker(&flakySender{}, WithCriticalLogger(testLogger()))
+	c.Send(context.Background(), KindResult, "C1", "T1", "x"+strings.Repeat("é", 60))
+
+	if d := c.Digest(); !utf8.ValidString(d) || !strings.Contains(d, "...") {
+		t.Errorf("digest = %q", d)
+	}
+}
@@ -0,0 +1,5 @@
+// Package outbox provides a persistent outbound message queue. Messages are
+// written to disk before delivery, retried with exponential backoff, and
+// delivered in strict order per chat (channel + thread). Messages that
+// exhaust their retries move to a dead-letter list surfaced in /status.
+package outbox
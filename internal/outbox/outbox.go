@@ -0,0 +1,350 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sender delivers a message. agent.MessageSender and *slack.Client satisfy it.
+type Sender interface {
+	SendMessage(ctx context.Context, channel, thread, text string) error
+}
+
+// Message is a queued outbound message.
+type Message struct {
+	ID          string    `json:"id"`
+	Channel     string    `json:"channel"`
+	Thread      string    `json:"thread"`
+	Text        string    `json:"text"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (m *Message) chatKey() string {
+	return m.Channel + "/" + m.Thread
+}
+
+// state is the on-disk representation.
+type state struct {
+	Seq     int64      `json:"seq"`
+	Pending []*Message `json:"pending"`
+	Dead    []*Message `json:"dead"`
+}
+
+// Outbox is a persistent, ordered, retrying outbound queue. Thread-safe.
+type Outbox struct {
+	path        string
+	sender      Sender
+	logger      *slog.Logger
+	now         func() time.Time
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+
+	flushMu sync.Mutex // serializes Flush
+	mu      sync.Mutex // guards state
+	state   state
+	wake    chan struct{}
+}
+
+// Option configures an Outbox.
+type Option func(*Outbox)
+
+// WithOutboxLogger sets the logger.
+func WithOutboxLogger(l *slog.Logger) Option {
+	return func(o *Outbox) {
+		o.logger = l
+	}
+}
+
+// WithMaxAttempts sets how many deliveries are tried before dead-lettering (default 8).
+func WithMaxAttempts(n int) Option {
+	return func(o *Outbox) {
+		o.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the first retry delay and the cap (default 2s, 5m).
+func WithBackoff(base, max time.Duration) Option {
+	return func(o *Outbox) {
+		o.baseBackoff = base
+		o.maxBackoff = max
+	}
+}
+
+// WithOutboxClock sets the clock (for testing).
+func WithOutboxClock(now func() time.Time) Option {
+	return func(o *Outbox) {
+		o.now = now
+	}
+}
+
+// DefaultPath returns the outbox file for a role in a repo.
+func DefaultPath(repoDir, role string) string {
+	return filepath.Join(repoDir, ".codebutler", "outbox", role+".json")
+}
+
+// New opens (or creates) the outbox persisted at path.
+func New(path string, sender Sender, opts ...Option) (*Outbox, error) {
+	o := &Outbox{
+		path:        path,
+		sender:      sender,
+		logger:      slog.Default(),
+		now:         time.Now,
+		maxAttempts: 8,
+		baseBackoff: 2 * time.Second,
+		maxBackoff:  5 * time.Minute,
+		wake:        make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("read outbox: %w", err)
+	default:
+		if err := json.Unmarshal(data, &o.state); err != nil {
+			return nil, fmt.Errorf("parse outbox: %w", err)
+		}
+	}
+	return o, nil
+}
+
+// Enqueue persists a message for delivery and returns its ID.
+func (o *Outbox) Enqueue(channel, thread, text string) (string, error) {
+	o.mu.Lock()
+	o.state.Seq++
+	now := o.now()
+	m := &Message{
+		ID:          fmt.Sprintf("out-%d", o.state.Seq),
+		Channel:     channel,
+		Thread:      thread,
+		Text:        text,
+		NextAttempt: now,
+		CreatedAt:   now,
+	}
+	o.state.Pending = append(o.state.Pending, m)
+	err := o.saveLocked()
+	o.mu.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return m.ID, nil
+}
+
+// SendMessage enqueues the message, so an Outbox can be dropped in wherever
+// a MessageSender is expected. Delivery happens in Run/Flush.
+func (o *Outbox) SendMessage(_ context.Context, channel, thread, text string) error {
+	_, err := o.Enqueue(channel, thread, text)
+	return err
+}
+
+// Flush attempts delivery of every due message. Per chat, delivery stops at
+// the first message that fails so later messages never overtake it. Sends
+// happen without holding the queue lock, so Enqueue never waits on the
+// network.
+func (o *Outbox) Flush(ctx context.Context) {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+
+	o.mu.Lock()
+	now := o.now()
+	due := make([]Message, 0, len(o.state.Pending))
+	blocked := make(map[string]bool)
+	for _, m := range o.state.Pending {
+		key := m.chatKey()
+		if blocked[key] || m.NextAttempt.After(now) {
+			blocked[key] = true
+			continue
+		}
+		due = append(due, *m)
+	}
+	o.mu.Unlock()
+
+	// Send outside the lock, recording the outcome per message.
+	outcome := make(map[string]error, len(due))
+	failed := make(map[string]bool)
+	for _, m := range due {
+		key := m.chatKey()
+		if failed[key] || ctx.Err() != nil {
+			continue
+		}
+		err := o.sender.SendMessage(ctx, m.Channel, m.Thread, m.Text)
+		outcome[m.ID] = err
+		if err != nil && m.Attempts+1 < o.maxAttempts {
+			failed[key] = true
+		}
+	}
+	if len(outcome) == 0 {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	remaining := o.state.Pending[:0]
+	for _, m := range o.state.Pending {
+		err, tried := outcome[m.ID]
+		if !tried {
+			remaining = append(remaining, m)
+			continue
+		}
+		m.Attempts++
+		if err == nil {
+			continue
+		}
+
+		m.LastError = err.Error()
+		if m.Attempts >= o.maxAttempts {
+			o.logger.Error("outbound message dead-lettered",
+				"id", m.ID, "channel", m.Channel, "attempts", m.Attempts, "err", err)
+			o.state.Dead = append(o.state.Dead, m)
+			continue // unblock the rest of the chat
+		}
+
+		m.NextAttempt = now.Add(o.backoff(m.Attempts))
+		o.logger.Warn("outbound message failed, will retry",
+			"id", m.ID, "channel", m.Channel, "attempt", m.Attempts, "retry_at", m.NextAttempt, "err", err)
+		remaining = append(remaining, m)
+	}
+	o.state.Pending = remaining
+
+	if err := o.saveLocked(); err != nil {
+		o.logger.Error("failed to persist outbox", "err", err)
+	}
+}
+
+// Run flushes the queue whenever a message is enqueued and at least every
+// interval (to pick up retries). Blocks until ctx is cancelled.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	o.Flush(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		case <-ticker.C:
+		}
+		o.Flush(ctx)
+	}
+}
+
+func (o *Outbox) backoff(attempts int) time.Duration {
+	d := o.baseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= o.maxBackoff {
+			return o.maxBackoff
+		}
+	}
+	return d
+}
+
+// Pending returns the number of messages awaiting delivery.
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.state.Pending)
+}
+
+// DeadLetters returns a copy of the dead-letter list.
+func (o *Outbox) DeadLetters() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make([]Message, len(o.state.Dead))
+	for i, m := range o.state.Dead {
+		out[i] = *m
+	}
+	return out
+}
+
+// Retry moves a dead-lettered message back to the end of the queue.
+func (o *Outbox) Retry(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, m := range o.state.Dead {
+		if m.ID != id {
+			continue
+		}
+		m.Attempts = 0
+		m.NextAttempt = o.now()
+		o.state.Dead = append(o.state.Dead[:i], o.state.Dead[i+1:]...)
+		o.state.Pending = append(o.state.Pending, m)
+		return o.saveLocked()
+	}
+	return fmt.Errorf("dead letter %q not found", id)
+}
+
+// Discard removes a dead-lettered message permanently.
+func (o *Outbox) Discard(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, m := range o.state.Dead {
+		if m.ID == id {
+			o.state.Dead = append(o.state.Dead[:i], o.state.Dead[i+1:]...)
+			return o.saveLocked()
+		}
+	}
+	return fmt.Errorf("dead letter %q not found", id)
+}
+
+// FormatStatus renders queue depth and dead letters for /status.
+func (o *Outbox) FormatStatus() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Outbox: %d pending, %d dead-lettered\n", len(o.state.Pending), len(o.state.Dead)))
+	for _, m := range o.state.Dead {
+		preview := m.Text
+		if len(preview) > 60 {
+			preview = preview[:60] + "..."
+		}
+		b.WriteString(fmt.Sprintf("  %s  %s  %d attempts  %s\n    %q\n",
+			m.ID, m.Channel, m.Attempts, m.LastError, preview))
+	}
+	return b.String()
+}
+
+// saveLocked writes state atomically. Caller must hold o.mu.
+func (o *Outbox) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(o.path), 0o755); err != nil {
+		return fmt.Errorf("create outbox dir: %w", err)
+	}
+	data, err := json.MarshalIndent(o.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal outbox: %w", err)
+	}
+	tmp := o.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write outbox: %w", err)
+	}
+	if err := os.Rename(tmp, o.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename outbox: %w", err)
+	}
+	return nil
+}
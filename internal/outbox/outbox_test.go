@@ -0,0 +1,220 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+}
+
+type sent struct {
+	channel, thread, text string
+}
+
+type flakySender struct {
+	mu      sync.Mutex
+	failing map[string]bool // texts that fail
+	down    bool            // everything fails
+	sent    []sent
+}
+
+func (f *flakySender) SendMessage(_ context.Context, channel, thread, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down || f.failing[text] {
+		return errors.New("not connected")
+	}
+	f.sent = append(f.sent, sent{channel, thread, text})
+	return nil
+}
+
+func (f *flakySender) texts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, s := range f.sent {
+		out = append(out, s.text)
+	}
+	return out
+}
+
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time          { return c.t }
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestOutbox(t *testing.T, s Sender, clock *testClock, opts ...Option) (*Outbox, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "outbox", "pm.json")
+	opts = append([]Option{WithOutboxLogger(testLogger()), WithOutboxClock(clock.now), WithBackoff(time.Second, 10*time.Second)}, opts...)
+	o, err := New(path, s, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o, path
+}
+
+func TestOutbox_DeliversInOrder(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	s := &flakySender{}
+	o, _ := newTestOutbox(t, s, clock)
+
+	for _, text := range []string{"a", "b", "c"} {
+		if err := o.SendMessage(context.Background(), "C1", "T1", text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	o.Flush(context.Background())
+
+	if got := strings.Join(s.texts(), ""); got != "abc" {
+		t.Errorf("got %q, want abc", got)
+	}
+	if o.Pending() != 0 {
+		t.Errorf("expected empty queue, got %d", o.Pending())
+	}
+}
+
+func TestOutbox_RetryPreservesPerChatOrder(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	s := &flakySender{failing: map[string]bool{"a1": true}}
+	o, _ := newTestOutbox(t, s, clock)
+
+	o.Enqueue("C1", "T1", "a1")
+	o.Enqueue("C1", "T1", "a2")
+	o.Enqueue("C1", "T2", "b1") // other thread is unaffected
+
+	o.Flush(context.Background())
+	if got := s.texts(); len(got) != 1 || got[0] != "b1" {
+		t.Fatalf("expected only b1 delivered, got %v", got)
+	}
+
+	// Still in backoff: nothing new.
+	o.Flush(context.Background())
+	if len(s.texts()) != 1 {
+		t.Fatal("a2 must not overtake a1")
+	}
+
+	s.mu.Lock()
+	s.failing = nil
+	s.mu.Unlock()
+	clock.advance(2 * time.Second)
+	o.Flush(context.Background())
+
+	if got := strings.Join(s.texts(), ","); got != "b1,a1,a2" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestOutbox_DeadLetterAndRetry(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	s := &flakySender{failing: map[string]bool{"doomed": true}}
+	o, _ := newTestOutbox(t, s, clock, WithMaxAttempts(2))
+
+	o.Enqueue("C1", "T1", "doomed")
+	o.Enqueue("C1", "T1", "after")
+
+	o.Flush(context.Background())
+	clock.advance(time.Minute)
+	o.Flush(context.Background())
+
+	dead := o.DeadLetters()
+	if len(dead) != 1 || dead[0].Text != "doomed" || dead[0].LastError == "" {
+		t.Fatalf("unexpected dead letters: %+v", dead)
+	}
+	if got := s.texts(); len(got) != 1 || got[0] != "after" {
+		t.Errorf("later message should be delivered once head is dead-lettered, got %v", got)
+	}
+	if !strings.Contains(o.FormatStatus(), "1 dead-lettered") {
+		t.Errorf("status missing dead letter: %s", o.FormatStatus())
+	}
+
+	s.mu.Lock()
+	s.failing = nil
+	s.mu.Unlock()
+	if err := o.Retry(dead[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	o.Flush(context.Background())
+	if len(o.DeadLetters()) != 0 || len(s.texts()) != 2 {
+		t.Errorf("retry should redeliver, got %v", s.texts())
+	}
+	if err := o.Retry("nope"); err == nil {
+		t.Error("expected error for unknown id")
+	}
+}
+
+func TestOutbox_PersistsAcrossRestart(t *testing.T) {
+	clock := &testClock{t: time.Unix(1000, 0)}
+	down := &flakySender{down: true}
+	o, path := newTestOutbox(t, down, clock)
+
+	o.Enqueue("C1", "T1", "survives")
+	o.Flush(context.Background())
+
+	up := &flakySender{}
+	o2, err := New(path, up, WithOutboxLogger(testLogger()), WithOutboxClock(clock.now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o2.Pending() != 1 {
+		t.Fatalf("expected 1 pending after reload, got %d", o2.Pending())
+	}
+
+	clock.advance(time.Hour)
+	o2.Flush(context.Background())
+	if got := up.texts(); len(got) != 1 || got[0] != "survives" {
+		t.Errorf("got %v", got)
+	}
+
+	// IDs keep increasing after reload.
+	id, _ := o2.Enqueue("C1", "T1", "next")
+	if id != "out-2" {
+		t.Errorf("expected out-2, got %s", id)
+	}
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	o := &Outbox{baseBackoff: time.Second, maxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := o.backoff(i + 1); got != w {
+			t.Errorf("attempt %d: got %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestOutbox_RunWakesOnEnqueue(t *testing.T) {
+	s := &flakySender{}
+	o, err := New(filepath.Join(t.TempDir(), "o.json"), s, WithOutboxLogger(testLogger()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		o.Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	o.Enqueue("C1", "T1", "hello")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(s.texts()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if len(s.texts()) != 1 {
+		t.Error("expected message delivered by Run")
+	}
+}
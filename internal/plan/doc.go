@@ -0,0 +1,6 @@
+// Package plan spots multi-step plans in an agent's final response and
+// turns them into an interactive thread: instead of posting the whole plan
+// as one message, CodeButler posts the outline and asks "Found a 4-step
+// plan — run step 1?", then runs the steps one at a time (or all at once)
+// as the user replies.
+package plan
@@ -0,0 +1,131 @@
+package plan
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MinSteps is the shortest numbered list treated as a plan. Shorter lists
+// are usually options or examples rather than work to carry out.
+const MinSteps = 3
+
+// Step is one item of a plan.
+type Step struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body,omitempty"` // lines under the item, unindented
+}
+
+// Plan is a numbered list extracted from a response.
+type Plan struct {
+	Intro string `json:"intro,omitempty"` // text before the first step
+	Steps []Step `json:"steps"`
+}
+
+var (
+	// 1. Add the migration / 1) Add the migration
+	listItemRe = regexp.MustCompile(`^(\d+)[.)]\s+(.+)$`)
+	// ## Step 1: Add the migration / ### 1. Add the migration
+	headingRe = regexp.MustCompile(`^#{1,4}\s+(?:Step\s+)?(\d+)[.:)]?\s+(.+)$`)
+)
+
+// Extract finds the first run of at least MinSteps consecutively numbered
+// top-level items starting at 1, either list items or headings. Text
+// between items belongs to the preceding step; a run ends at the first
+// unindented paragraph after a blank line. Numbered lines inside code
+// fences don't count.
+func Extract(text string) (*Plan, bool) {
+	lines := strings.Split(text, "\n")
+
+	var (
+		steps   []Step
+		body    []string
+		start   int
+		inFence bool
+		blank   bool
+		found   bool
+	)
+	flush := func() {
+		if len(steps) > 0 {
+			steps[len(steps)-1].Body = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+	// end closes the current run and reports whether it is a plan.
+	end := func() bool {
+		flush()
+		if len(steps) >= MinSteps {
+			return true
+		}
+		steps = nil
+		return false
+	}
+
+scan:
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if n, title, ok := parseItem(line); ok && !inFence {
+			if n == 1 && len(steps) > 0 && n != len(steps)+1 {
+				// A new list starts; keep the previous one if it qualifies.
+				if found = end(); found {
+					break scan
+				}
+			}
+			if n == len(steps)+1 {
+				flush()
+				if n == 1 {
+					start = i
+				}
+				steps = append(steps, Step{Number: n, Title: title})
+				blank = false
+				continue
+			}
+		}
+		if len(steps) == 0 {
+			continue
+		}
+		if trimmed == "" {
+			blank = true
+			body = append(body, "")
+			continue
+		}
+		if blank && !inFence && line == trimmed && !strings.HasPrefix(trimmed, "-") && !strings.HasPrefix(trimmed, "*") {
+			if found = end(); found {
+				break scan
+			}
+			continue
+		}
+		blank = false
+		body = append(body, strings.TrimPrefix(strings.TrimPrefix(line, "   "), "\t"))
+	}
+	if !found && !end() {
+		return nil, false
+	}
+	return &Plan{
+		Intro: strings.TrimSpace(strings.Join(lines[:start], "\n")),
+		Steps: steps,
+	}, true
+}
+
+// parseItem recognizes a top-level numbered item.
+func parseItem(line string) (int, string, bool) {
+	if line != strings.TrimLeft(line, " \t") {
+		return 0, "", false
+	}
+	m := listItemRe.FindStringSubmatch(line)
+	if m == nil {
+		m = headingRe.FindStringSubmatch(line)
+	}
+	if m == nil {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, "", false
+	}
+	return n, strings.Trim(strings.TrimSpace(m[2]), "*"), true
+}
@@ -0,0 +1,203 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+// MessageSender posts plan offers and progress.
+type MessageSender interface {
+	SendMessage(ctx context.Context, channel, thread, text string) error
+}
+
+// Executor carries out one step, typically by handing it to the agent that
+// wrote the plan. The whole plan is passed for context.
+type Executor interface {
+	RunStep(ctx context.Context, channel, thread string, p *Plan, step Step) error
+}
+
+// pending is a plan whose next step awaits the user's go-ahead.
+type pending struct {
+	plan *Plan
+	next int // index into plan.Steps
+}
+
+// Offerer posts extracted plans and runs their steps on request.
+// Thread-safe.
+type Offerer struct {
+	sender  MessageSender
+	exec    Executor
+	logger  *slog.Logger
+	catalog *messages.Catalog
+
+	mu      sync.Mutex
+	pending map[string]*pending // channel/thread
+	running map[string]bool     // channel/thread
+}
+
+// Option configures an Offerer.
+type Option func(*Offerer)
+
+// WithLogger sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *Offerer) {
+		o.logger = l
+	}
+}
+
+// WithCatalog sets the catalog the done and failed notices are rendered
+// from. Default: the default locale.
+func WithCatalog(cat *messages.Catalog) Option {
+	return func(o *Offerer) {
+		o.catalog = cat
+	}
+}
+
+// NewOfferer creates an Offerer that posts through sender and runs steps
+// with exec.
+func NewOfferer(sender MessageSender, exec Executor, opts ...Option) *Offerer {
+	o := &Offerer{
+		sender:  sender,
+		exec:    exec,
+		logger:  slog.Default(),
+		pending: make(map[string]*pending),
+		running: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.catalog == nil {
+		o.catalog = messages.New(messages.DefaultLocale)
+	}
+	return o
+}
+
+func key(channel, thread string) string {
+	return channel + "/" + thread
+}
+
+// Deliver posts an agent's final response. When it contains a plan, the
+// outline is posted with an offer to run step 1; otherwise the response is
+// posted as-is. It reports whether a plan was offered.
+func (o *Offerer) Deliver(ctx context.Context, channel, thread, response string) (bool, error) {
+	p, ok := Extract(response)
+	if !ok {
+		return false, o.sender.SendMessage(ctx, channel, thread, response)
+	}
+	o.mu.Lock()
+	o.pending[key(channel, thread)] = &pending{plan: p}
+	o.mu.Unlock()
+	return true, o.sender.SendMessage(ctx, channel, thread, FormatOffer(p))
+}
+
+// HandleReply treats a thread message as the answer to a plan offer:
+// "1"/"yes" runs the next step, "2"/"all" runs the remaining steps in
+// order, "3"/"stop" drops the plan. It returns true when the message was
+// consumed.
+func (o *Offerer) HandleReply(ctx context.Context, channel, thread, text string) bool {
+	var all, stop bool
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(text), ".!")) {
+	case "1", "yes", "run", "next":
+	case "2", "all":
+		all = true
+	case "3", "stop", "no":
+		stop = true
+	default:
+		return false
+	}
+
+	k := key(channel, thread)
+	o.mu.Lock()
+	p, ok := o.pending[k]
+	if ok && o.running[k] {
+		o.mu.Unlock()
+		o.sender.SendMessage(ctx, channel, thread, "A plan step is still running.") //nolint:errcheck // best-effort notice
+		return true
+	}
+	if ok && stop {
+		delete(o.pending, k)
+	}
+	if ok && !stop {
+		o.running[k] = true
+	}
+	o.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if stop {
+		o.sender.SendMessage(ctx, channel, thread, fmt.Sprintf("Plan dropped after %d of %d steps.", p.next, len(p.plan.Steps))) //nolint:errcheck // best-effort notice
+		return true
+	}
+
+	// The reply's context ends with its handler; the steps outlive it.
+	bg := context.WithoutCancel(ctx)
+	go o.run(bg, channel, thread, p, all)
+	return true
+}
+
+// run executes the next step, or every remaining step when all is set,
+// then offers the following one. A failed step stays next so "1" retries.
+func (o *Offerer) run(ctx context.Context, channel, thread string, p *pending, all bool) {
+	k := key(channel, thread)
+	defer func() {
+		o.mu.Lock()
+		delete(o.running, k)
+		if p.next >= len(p.plan.Steps) {
+			delete(o.pending, k)
+		}
+		o.mu.Unlock()
+	}()
+
+	total := len(p.plan.Steps)
+	for p.next < total {
+		step := p.plan.Steps[p.next]
+		o.sender.SendMessage(ctx, channel, thread, fmt.Sprintf(":arrow_forward: Step %d/%d: *%s*", step.Number, total, step.Title)) //nolint:errcheck // best-effort notice
+		if err := o.exec.RunStep(ctx, channel, thread, p.plan, step); err != nil {
+			o.logger.Warn("plan step failed", "thread", thread, "step", step.Number, "err", err)
+			failed := o.catalog.Render(messages.TaskFailed, map[string]string{"Error": fmt.Sprintf("step %d failed: %v", step.Number, err)})
+			o.sender.SendMessage(ctx, channel, thread, failed+"\nReply *1* to retry it / *3* to stop.") //nolint:errcheck // best-effort notice
+			return
+		}
+		p.next++
+		if !all {
+			break
+		}
+	}
+
+	if p.next >= total {
+		o.sender.SendMessage(ctx, channel, thread, o.catalog.Render(messages.TaskDone, nil)) //nolint:errcheck // best-effort notice
+		return
+	}
+	next := p.plan.Steps[p.next]
+	o.sender.SendMessage(ctx, channel, thread, fmt.Sprintf("Step %d/%d done. Run step %d: *%s*?\n%s", //nolint:errcheck // best-effort notice
+		p.next, total, next.Number, next.Title, replyOptions))
+}
+
+// Pending reports whether the thread has a plan awaiting an answer.
+func (o *Offerer) Pending(channel, thread string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.pending[key(channel, thread)]
+	return ok
+}
+
+const replyOptions = "Reply *1* to run it / *2* to run all remaining steps / *3* to stop."
+
+// FormatOffer renders a plan's outline with the offer to run step 1.
+func FormatOffer(p *Plan) string {
+	var b strings.Builder
+	if p.Intro != "" {
+		b.WriteString(p.Intro + "\n\n")
+	}
+	fmt.Fprintf(&b, ":clipboard: Found a %d-step plan — run step 1?\n", len(p.Steps))
+	for _, s := range p.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", s.Number, s.Title)
+	}
+	b.WriteString(replyOptions)
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const response = `Here's how I'd add rate limiting:
+
+1. Add a token bucket to internal/ratelimit
+   with per-user buckets.
+2. **Wire it into the HTTP middleware**
+3. Add tests for burst and refill
+
+` + "```go\n1. not a step\n```" + `
+
+Let me know if that works.`
+
+func TestExtract(t *testing.T) {
+	p, ok := Extract(response)
+	if !ok {
+		t.Fatal("plan not found")
+	}
+	if p.Intro != "Here's how I'd add rate limiting:" {
+		t.Errorf("intro = %q", p.Intro)
+	}
+	if len(p.Steps) != 3 {
+		t.Fatalf("got %d steps, want 3", len(p.Steps))
+	}
+	if p.Steps[0].Body != "with per-user buckets." {
+		t.Errorf("step 1 body = %q", p.Steps[0].Body)
+	}
+	if p.Steps[1].Title != "Wire it into the HTTP middleware" {
+		t.Errorf("step 2 title = %q", p.Steps[1].Title)
+	}
+}
+
+func TestExtractHeadings(t *testing.T) {
+	p, ok := Extract("## Step 1: Schema\nadd table\n## Step 2: API\n## Step 3: UI\n")
+	if !ok || len(p.Steps) != 3 || p.Steps[0].Body != "add table" {
+		t.Fatalf("Extract = %+v, %v", p, ok)
+	}
+}
+
+func TestExtractRejects(t *testing.T) {
+	for name, text := range map[string]string{
+		"short":     "Two options:\n1. a\n2. b\n",
+		"gap":       "1. a\n2. b\n4. c\n",
+		"in fence":  "```\n1. a\n2. b\n3. c\n```",
+		"indented":  "  1. a\n  2. b\n  3. c\n",
+		"no list":   "All done, tests pass.",
+		"broken up": "1. a\n2. b\n\nSome prose.\n\n3. c\n",
+	} {
+		if p, ok := Extract(text); ok {
+			t.Errorf("%s: unexpected plan %+v", name, p)
+		}
+	}
+}
+
+func TestExtractSkipsShortList(t *testing.T) {
+	p, ok := Extract("Either:\n1. a\n2. b\n\nPlan:\n1. x\n2. y\n3. z\n")
+	if !ok || p.Steps[0].Title != "x" || p.Intro != "Either:\n1. a\n2. b\n\nPlan:" {
+		t.Fatalf("Extract = %+v, %v", p, ok)
+	}
+}
+
+type recordingSender struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (s *recordingSender) SendMessage(_ context.Context, _, _, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, text)
+	return nil
+}
+
+func (s *recordingSender) last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.msgs) == 0 {
+		return ""
+	}
+	return s.msgs[len(s.msgs)-1]
+}
+
+type mockExecutor struct {
+	mu   sync.Mutex
+	ran  []int
+	fail map[int]bool
+}
+
+func (e *mockExecutor) RunStep(_ context.Context, _, _ string, _ *Plan, step Step) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.fail[step.Number] {
+		delete(e.fail, step.Number)
+		return errors.New("boom")
+	}
+	e.ran = append(e.ran, step.Number)
+	return nil
+}
+
+func (e *mockExecutor) steps() []int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]int(nil), e.ran...)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestOffererStepThrough(t *testing.T) {
+	sender := &recordingSender{}
+	exec := &mockExecutor{fail: map[int]bool{2: true}}
+	o := NewOfferer(sender, exec)
+	ctx := context.Background()
+
+	offered, err := o.Deliver(ctx, "C", "T", response)
+	if err != nil || !offered {
+		t.Fatalf("Deliver = %v, %v", offered, err)
+	}
+	if !strings.Contains(sender.last(), "Found a 3-step plan — run step 1?") {
+		t.Errorf("offer = %q", sender.last())
+	}
+
+	if !o.HandleReply(ctx, "C", "T", "1") {
+		t.Fatal("reply not consumed")
+	}
+	waitFor(t, func() bool { return strings.Contains(sender.last(), "Run step 2") })
+
+	o.HandleReply(ctx, "C", "T", "all")
+	waitFor(t, func() bool { return strings.Contains(sender.last(), "Something went wrong: step 2 failed") })
+
+	o.HandleReply(ctx, "C", "T", "2")
+	waitFor(t, func() bool { return strings.Contains(sender.last(), "Done ✓") })
+
+	if got := exec.steps(); len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("ran = %v", got)
+	}
+	waitFor(t, func() bool { return !o.Pending("C", "T") })
+}
+
+func TestOffererStopAndPlainResponse(t *testing.T) {
+	sender := &recordingSender{}
+	o := NewOfferer(sender, &mockExecutor{})
+	ctx := context.Background()
+
+	if offered, _ := o.Deliver(ctx, "C", "T", "Fixed the typo."); offered || sender.last() != "Fixed the typo." {
+		t.Errorf("plain response: offered=%v last=%q", offered, sender.last())
+	}
+	if o.HandleReply(ctx, "C", "T", "1") {
+		t.Error("reply consumed with no plan pending")
+	}
+
+	o.Deliver(ctx, "C", "T", response)
+	if !o.HandleReply(ctx, "C", "T", "stop") || o.Pending("C", "T") {
+		t.Error("stop did not drop the plan")
+	}
+	if !strings.Contains(sender.last(), "Plan dropped after 0 of 3 steps") {
+		t.Errorf("stop message = %q", sender.last())
+	}
+}
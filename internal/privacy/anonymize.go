@@ -0,0 +1,201 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// PII kinds used in placeholders.
+const (
+	KindEmail = "EMAIL"
+	KindPhone = "PHONE"
+	KindName  = "NAME"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// phonePattern is deliberately loose; candidates with fewer than
+	// minPhoneDigits digits (dates, versions, short IDs) are skipped.
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().-]{6,}\d`)
+	// titledName matches honorific-prefixed names ("Dr. Jane Smith").
+	titledName = regexp.MustCompile(`\b(?:Mr|Mrs|Ms|Miss|Dr|Prof)\.?\s+[A-Z][a-z]+(?:\s+[A-Z][a-z]+)?`)
+	// placeholderPattern matches placeholders produced by an Anonymizer.
+	placeholderPattern = regexp.MustCompile(`<(EMAIL|PHONE|NAME)_(\d+)>`)
+)
+
+const minPhoneDigits = 9
+
+// Anonymizer replaces emails, phone numbers, and names with stable
+// placeholders ("<EMAIL_1>") and restores them afterwards. The mapping
+// never leaves the process. Safe for concurrent use.
+type Anonymizer struct {
+	names []string // known names, longest first
+
+	mu      sync.Mutex
+	forward map[string]string // original → placeholder
+	reverse map[string]string // placeholder → original
+	counts  map[string]int
+}
+
+// NewAnonymizer creates an anonymizer. knownNames (people, clients) are
+// replaced wherever they appear, in addition to emails, phone numbers, and
+// honorific-prefixed names.
+func NewAnonymizer(knownNames ...string) *Anonymizer {
+	var names []string
+	for _, n := range knownNames {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return &Anonymizer{
+		names:   names,
+		forward: make(map[string]string),
+		reverse: make(map[string]string),
+		counts:  make(map[string]int),
+	}
+}
+
+// Anonymize returns text with PII replaced by placeholders. The same value
+// always maps to the same placeholder.
+func (a *Anonymizer) Anonymize(text string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	text = emailPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return a.placeholder(KindEmail, m)
+	})
+	text = phonePattern.ReplaceAllStringFunc(text, func(m string) string {
+		digits := 0
+		for _, r := range m {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		if digits < minPhoneDigits {
+			return m
+		}
+		return a.placeholder(KindPhone, m)
+	})
+	text = titledName.ReplaceAllStringFunc(text, func(m string) string {
+		return a.placeholder(KindName, m)
+	})
+	for _, name := range a.names {
+		text = a.replaceWord(text, name)
+	}
+	return text
+}
+
+// replaceWord replaces case-insensitive whole-word occurrences of name.
+// Word boundaries are checked on Unicode letters so names like "José" work.
+// Must be called with a.mu held.
+func (a *Anonymizer) replaceWord(text, name string) string {
+	re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(name))
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+		after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(a.placeholder(KindName, text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// Restore replaces known placeholders in text with the original values.
+// Unknown placeholders are left as is.
+func (a *Anonymizer) Restore(text string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return placeholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+		if orig, ok := a.reverse[m]; ok {
+			return orig
+		}
+		return m
+	})
+}
+
+// Len returns how many distinct values have been replaced.
+func (a *Anonymizer) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.forward)
+}
+
+// placeholder must be called with a.mu held.
+func (a *Anonymizer) placeholder(kind, value string) string {
+	if p, ok := a.forward[value]; ok {
+		return p
+	}
+	a.counts[kind]++
+	p := fmt.Sprintf("<%s_%d>", kind, a.counts[kind])
+	a.forward[value] = p
+	a.reverse[p] = value
+	return p
+}
+
+// AnonymizingProvider wraps an LLM provider so every outbound message (the
+// system prompt, user turns, earlier replies and their tool arguments, tool
+// results) is anonymized before it leaves the process, and placeholders in
+// the model's reply (text and tool arguments) are restored.
+type AnonymizingProvider struct {
+	inner agent.LLMProvider
+	anon  *Anonymizer
+}
+
+// NewAnonymizingProvider wraps inner. Use one Anonymizer per conversation so
+// placeholders stay consistent across turns.
+func NewAnonymizingProvider(inner agent.LLMProvider, anon *Anonymizer) *AnonymizingProvider {
+	return &AnonymizingProvider{inner: inner, anon: anon}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *AnonymizingProvider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	msgs := make([]agent.Message, len(req.Messages))
+	for i, m := range req.Messages {
+		m.Content = p.anon.Anonymize(m.Content)
+		if len(m.ToolCalls) > 0 {
+			calls := make([]agent.ToolCall, len(m.ToolCalls))
+			for j, tc := range m.ToolCalls {
+				tc.Arguments = p.anon.Anonymize(tc.Arguments)
+				calls[j] = tc
+			}
+			m.ToolCalls = calls
+		}
+		msgs[i] = m
+	}
+	req.Messages = msgs
+
+	resp, err := p.inner.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *resp
+	out.Message.Content = p.anon.Restore(resp.Message.Content)
+	if len(resp.Message.ToolCalls) > 0 {
+		out.Message.ToolCalls = make([]agent.ToolCall, len(resp.Message.ToolCalls))
+		for i, tc := range resp.Message.ToolCalls {
+			tc.Arguments = p.anon.Restore(tc.Arguments)
+			out.Message.ToolCalls[i] = tc
+		}
+	}
+	return &out, nil
+}
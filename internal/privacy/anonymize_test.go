@@ -0,0 +1,117 @@
+package privacy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+func TestAnonymizer_RoundTrip(t *testing.T) {
+	a := NewAnonymizer("Acme Corp", "Lucía", "José")
+	in := "Email jane.doe@example.com or call +1 (555) 123-4567. Dr. Jane Smith from acme corp " +
+		"says Lucía saw it on 2024-01-15 in v1.2.3; ping jane.doe@example.com again."
+
+	got := a.Anonymize(in)
+	for _, leak := range []string{"jane.doe", "555", "Jane Smith", "acme corp", "Lucía"} {
+		if strings.Contains(got, leak) {
+			t.Errorf("anonymized text leaked %q: %s", leak, got)
+		}
+	}
+	for _, keep := range []string{"2024-01-15", "v1.2.3"} {
+		if !strings.Contains(got, keep) {
+			t.Errorf("non-PII %q should survive: %s", keep, got)
+		}
+	}
+	if strings.Count(got, "<EMAIL_1>") != 2 || strings.Contains(got, "<EMAIL_2>") {
+		t.Errorf("repeated email should reuse its placeholder: %s", got)
+	}
+	if !strings.Contains(got, "<PHONE_1>") || !strings.Contains(got, "<NAME_3>") {
+		t.Errorf("placeholders missing: %s", got)
+	}
+	if back := a.Restore(got); back != in {
+		t.Errorf("restore mismatch:\n got %s\nwant %s", back, in)
+	}
+	if got := a.Anonymize("José, Josée and Lucíana"); got != "<NAME_4>, Josée and Lucíana" {
+		t.Errorf("names should match whole words only: %s", got)
+	}
+	if a.Restore("<NAME_99>") != "<NAME_99>" {
+		t.Error("unknown placeholder should be left alone")
+	}
+}
+
+type echoProvider struct{ got agent.ChatRequest }
+
+func (p *echoProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	p.got = req
+	last := req.Messages[len(req.Messages)-1].Content
+	return &agent.ChatResponse{Message: agent.Message{
+		Role:      "assistant",
+		Content:   "Replying to " + last,
+		ToolCalls: []agent.ToolCall{{ID: "c1", Name: "SendMessage", Arguments: `{"to":"<EMAIL_1>"}`}},
+	}}, nil
+}
+
+func TestAnonymizingProvider(t *testing.T) {
+	inner := &echoProvider{}
+	p := NewAnonymizingProvider(inner, NewAnonymizer())
+	req := agent.ChatRequest{Messages: []agent.Message{
+		{Role: "user", Content: "notify bob@example.com"},
+		{Role: "system", Content: "admin is root@example.com"},
+	}}
+
+	resp, err := p.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.got.Messages[0].Content != "notify <EMAIL_1>" {
+		t.Errorf("provider saw %q", inner.got.Messages[0].Content)
+	}
+	if inner.got.Messages[1].Content != "admin is <EMAIL_2>" {
+		t.Errorf("system prompt not anonymized: %q", inner.got.Messages[1].Content)
+	}
+	if req.Messages[0].Content != "notify bob@example.com" {
+		t.Error("caller's request must not be mutated")
+	}
+	if resp.Message.Content != "Replying to admin is root@example.com" || resp.Message.ToolCalls[0].Arguments != `{"to":"bob@example.com"}` {
+		t.Errorf("response not restored: %+v", resp.Message)
+	}
+}
+
+func TestAnonymizingProvider_NoOriginalsAcrossTurns(t *testing.T) {
+	inner := &echoProvider{}
+	p := NewAnonymizingProvider(inner, NewAnonymizer("Lucía"))
+	ctx := context.Background()
+	history := []agent.Message{
+		{Role: "system", Content: "You work for Lucía (lucia@example.com)."},
+		{Role: "user", Content: "notify bob@example.com"},
+	}
+
+	resp, err := p.ChatCompletion(ctx, agent.ChatRequest{Messages: history})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The restored reply goes back into history, followed by a tool result
+	// holding more PII.
+	history = append(history, resp.Message, agent.Message{
+		Role:       "tool",
+		ToolCallID: "c1",
+		Content:    "sent to bob@example.com, cc Lucía at +1 (555) 123-4567",
+	})
+	if _, err := p.ChatCompletion(ctx, agent.ChatRequest{Messages: history}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, m := range inner.got.Messages {
+		sent := m.Content
+		for _, tc := range m.ToolCalls {
+			sent += " " + tc.Arguments
+		}
+		for _, orig := range []string{"bob@example.com", "lucia@example.com", "Lucía", "555"} {
+			if strings.Contains(sent, orig) {
+				t.Errorf("message %d (%s) leaked %q: %q", i, m.Role, orig, sent)
+			}
+		}
+	}
+}
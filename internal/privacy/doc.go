@@ -0,0 +1,7 @@
+// Package privacy implements the data-residency switches for repos that
+// handle sensitive client data: a conversation store wrapper that persists
+// only hashes and metadata instead of message content, and a slog handler
+// that keeps message previews out of logs, and an anonymizing provider
+// wrapper that swaps PII for placeholders before LLM calls. It also applies
+// router.Redactor to outgoing chat messages and logs.
+package privacy
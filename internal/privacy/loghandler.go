@@ -0,0 +1,77 @@
+package privacy
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// contentKeys are log attribute keys that carry message or request content.
+// Keys ending in "_preview" (message_preview, plan_preview, ...) are also
+// treated as content.
+var contentKeys = map[string]bool{
+	"content": true,
+	"prompt":  true,
+	"text":    true,
+}
+
+// IsContentKey reports whether a log attribute key carries user content.
+func IsContentKey(key string) bool {
+	return contentKeys[key] || strings.HasSuffix(key, "_preview")
+}
+
+// LogHandler replaces content-bearing attributes with hashes before passing
+// records to next.
+type LogHandler struct {
+	next slog.Handler
+}
+
+// NewLogHandler wraps next.
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+// Enabled implements slog.Handler.
+func (h *LogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle implements slog.Handler.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	scrubbed := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		scrubbed.AddAttrs(scrubAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, scrubbed)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *LogHandler) WithAttrs(as []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, len(as))
+	for i, a := range as {
+		out[i] = scrubAttr(a)
+	}
+	return &LogHandler{next: h.next.WithAttrs(out)}
+}
+
+// WithGroup implements slog.Handler.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
+
+func scrubAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		group := v.Group()
+		out := make([]any, len(group))
+		for i, ga := range group {
+			out[i] = scrubAttr(ga)
+		}
+		return slog.Group(a.Key, out...)
+	}
+	if IsContentKey(a.Key) {
+		return slog.String(a.Key, Hash(v.String()))
+	}
+	return a
+}
@@ -0,0 +1,82 @@
+package privacy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// hashPrefix marks a value that was replaced by its digest.
+const hashPrefix = "sha256:"
+
+// Hash returns a stable placeholder for s that keeps its length and a short
+// digest (enough to correlate identical content) but not the content.
+// Empty strings stay empty.
+func Hash(s string) string {
+	if s == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(s))
+	return fmt.Sprintf("%s%s (%d bytes)", hashPrefix, hex.EncodeToString(sum[:8]), len(s))
+}
+
+// IsHashed reports whether s is a placeholder produced by Hash.
+func IsHashed(s string) bool {
+	return strings.HasPrefix(s, hashPrefix)
+}
+
+// ScrubMessages returns a copy of messages with content and tool arguments
+// replaced by hashes. Roles, tool names, and call IDs are kept so the shape
+// of the conversation is still auditable.
+func ScrubMessages(messages []agent.Message) []agent.Message {
+	out := make([]agent.Message, len(messages))
+	for i, m := range messages {
+		m.Content = Hash(m.Content)
+		if len(m.ToolCalls) > 0 {
+			calls := make([]agent.ToolCall, len(m.ToolCalls))
+			for j, tc := range m.ToolCalls {
+				tc.Arguments = Hash(tc.Arguments)
+				calls[j] = tc
+			}
+			m.ToolCalls = calls
+		}
+		out[i] = m
+	}
+	return out
+}
+
+// MetadataStore wraps a conversation store so only scrubbed messages reach
+// disk. Crash recovery then restores the conversation's shape but not its
+// content, so a recovered agent starts over with the thread's latest input.
+type MetadataStore struct {
+	inner agent.ConversationStore
+}
+
+// NewMetadataStore wraps inner.
+func NewMetadataStore(inner agent.ConversationStore) *MetadataStore {
+	return &MetadataStore{inner: inner}
+}
+
+// Load implements agent.ConversationStore. Persisted hashes are useless as
+// model context, so a scrubbed conversation loads as empty.
+func (s *MetadataStore) Load(ctx context.Context) ([]agent.Message, error) {
+	msgs, err := s.inner.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		if m.Content != "" && !IsHashed(m.Content) {
+			return msgs, nil // written before the switch was turned on
+		}
+	}
+	return nil, nil
+}
+
+// Save implements agent.ConversationStore.
+func (s *MetadataStore) Save(ctx context.Context, messages []agent.Message) error {
+	return s.inner.Save(ctx, ScrubMessages(messages))
+}
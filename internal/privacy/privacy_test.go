@@ -0,0 +1,93 @@
+package privacy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+type memStore struct{ saved []agent.Message }
+
+func (s *memStore) Load(context.Context) ([]agent.Message, error) { return s.saved, nil }
+func (s *memStore) Save(_ context.Context, m []agent.Message) error {
+	s.saved = m
+	return nil
+}
+
+func TestHash(t *testing.T) {
+	h := Hash("alice@example.com wants a refund")
+	if !IsHashed(h) || strings.Contains(h, "alice") || !strings.HasSuffix(h, "(32 bytes)") {
+		t.Errorf("Hash = %q", h)
+	}
+	if Hash("same") != Hash("same") || Hash("same") == Hash("other") {
+		t.Error("hash should be stable and distinguish content")
+	}
+	if Hash("") != "" {
+		t.Error("empty stays empty")
+	}
+}
+
+func TestMetadataStore(t *testing.T) {
+	inner := &memStore{}
+	store := NewMetadataStore(inner)
+	msgs := []agent.Message{
+		{Role: "user", Content: "client SSN is 123-45-6789"},
+		{Role: "assistant", ToolCalls: []agent.ToolCall{{ID: "c1", Name: "Read", Arguments: `{"path":"clients.csv"}`}}},
+		{Role: "tool", ToolCallID: "c1", Content: "name,ssn"},
+	}
+
+	if err := store.Save(context.Background(), msgs); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range inner.saved {
+		if m.Content != "" && !IsHashed(m.Content) {
+			t.Errorf("raw content persisted: %+v", m)
+		}
+	}
+	if tc := inner.saved[1].ToolCalls[0]; tc.Name != "Read" || tc.ID != "c1" || !IsHashed(tc.Arguments) {
+		t.Errorf("tool call metadata = %+v", tc)
+	}
+	if inner.saved[2].ToolCallID != "c1" || inner.saved[0].Role != "user" {
+		t.Error("metadata should be kept")
+	}
+	if msgs[0].Content != "client SSN is 123-45-6789" || msgs[1].ToolCalls[0].Arguments != `{"path":"clients.csv"}` {
+		t.Error("caller's messages must not be mutated")
+	}
+
+	loaded, err := store.Load(context.Background())
+	if err != nil || loaded != nil {
+		t.Errorf("scrubbed conversation should load empty, got %+v, %v", loaded, err)
+	}
+
+	inner.saved = []agent.Message{{Role: "user", Content: "older raw message"}}
+	if loaded, _ := store.Load(context.Background()); len(loaded) != 1 {
+		t.Error("pre-existing raw conversation should still load")
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil))).With("prompt", "secret prompt")
+
+	logger.Info("pre-classified intent",
+		"type", "workflow",
+		"message_preview", "refund for alice@example.com",
+		slog.Group("req", "content", "more secrets", "model", "m1"),
+	)
+
+	out := buf.String()
+	for _, leak := range []string{"alice", "secret prompt", "more secrets"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("log leaked %q:\n%s", leak, out)
+		}
+	}
+	for _, keep := range []string{"type=workflow", "req.model=m1", "message_preview=\"sha256:"} {
+		if !strings.Contains(out, keep) {
+			t.Errorf("log missing %q:\n%s", keep, out)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package privacy
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/router"
+)
+
+// RedactingSender masks secrets in every message before it reaches the
+// chat.
+type RedactingSender struct {
+	agent.MessageSender
+	r *router.Redactor
+}
+
+// NewRedactingSender wraps inner.
+func NewRedactingSender(inner agent.MessageSender, r *router.Redactor) *RedactingSender {
+	return &RedactingSender{MessageSender: inner, r: r}
+}
+
+// SendMessage implements agent.MessageSender.
+func (s *RedactingSender) SendMessage(ctx context.Context, channel, thread, text string) error {
+	return s.MessageSender.SendMessage(ctx, channel, thread, s.r.Redact(text))
+}
+
+// RedactHandler masks secrets in log messages and attribute values before
+// passing records to next.
+type RedactHandler struct {
+	next slog.Handler
+	r    *router.Redactor
+}
+
+// NewRedactHandler wraps next.
+func NewRedactHandler(next slog.Handler, r *router.Redactor) *RedactHandler {
+	return &RedactHandler{next: next, r: r}
+}
+
+// Enabled implements slog.Handler.
+func (h *RedactHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle implements slog.Handler.
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, h.r.Redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *RedactHandler) WithAttrs(as []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, len(as))
+	for i, a := range as {
+		out[i] = h.redactAttr(a)
+	}
+	return &RedactHandler{next: h.next.WithAttrs(out), r: h.r}
+}
+
+// WithGroup implements slog.Handler.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{next: h.next.WithGroup(name), r: h.r}
+}
+
+// redactAttr masks string values, and values that render as strings
+// (errors, Stringers), when they contain a secret.
+func (h *RedactHandler) redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		out := make([]any, len(group))
+		for i, ga := range group {
+			out[i] = h.redactAttr(ga)
+		}
+		return slog.Group(a.Key, out...)
+	case slog.KindString, slog.KindAny:
+		s := v.String()
+		if red := h.r.Redact(s); red != s {
+			return slog.String(a.Key, red)
+		}
+	}
+	return a
+}
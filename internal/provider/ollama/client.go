@@ -0,0 +1,155 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+const (
+	// DefaultBaseURL is where a local Ollama server listens.
+	DefaultBaseURL = "http://localhost:11434"
+
+	// ModelPrefix marks model IDs served by Ollama.
+	ModelPrefix = "ollama/"
+
+	// Local models are slow on modest hardware; allow long generations.
+	defaultTimeout = 10 * time.Minute
+)
+
+// IsLocalModel reports whether model should be served by Ollama.
+func IsLocalModel(model string) bool {
+	return strings.HasPrefix(model, ModelPrefix)
+}
+
+// Client calls Ollama's /api/chat endpoint.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+	seq        atomic.Int64 // numbers responses for tool call IDs
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets a custom HTTP client (useful for testing).
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithBaseURL overrides DefaultBaseURL.
+func WithBaseURL(url string) Option {
+	return func(cl *Client) {
+		if url != "" {
+			cl.baseURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithLogger sets a structured logger for the client.
+func WithLogger(l *slog.Logger) Option {
+	return func(cl *Client) {
+		cl.logger = l
+	}
+}
+
+// NewClient creates an Ollama client.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		baseURL:    DefaultBaseURL,
+		logger:     slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ChatCompletion implements agent.LLMProvider. The "ollama/" prefix is
+// stripped from the model name before the request is sent.
+func (c *Client) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	body, err := json.Marshal(toChatRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ollama request (is `ollama serve` running at %s?): %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read ollama response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(respBody, &e) //nolint:errcheck // best-effort parse
+		if e.Error == "" {
+			e.Error = strings.TrimSpace(string(respBody))
+		}
+		return nil, fmt.Errorf("ollama HTTP %d: %s", resp.StatusCode, e.Error)
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, fmt.Errorf("parse ollama response: %w", err)
+	}
+
+	c.logger.Debug("ollama chat completed",
+		"model", chatResp.Model,
+		"prompt_tokens", chatResp.PromptEvalCount,
+		"completion_tokens", chatResp.EvalCount,
+		"duration", time.Since(start),
+	)
+	return fromChatResponse(&chatResp, c.seq.Add(1)), nil
+}
+
+// Router sends "ollama/..." models to a local client and everything else to
+// the remote provider, so each role's model config picks where it runs.
+type Router struct {
+	local  agent.LLMProvider
+	remote agent.LLMProvider
+}
+
+// NewRouter creates a provider that routes by model prefix.
+func NewRouter(local, remote agent.LLMProvider) *Router {
+	return &Router{local: local, remote: remote}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (r *Router) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	if IsLocalModel(req.Model) {
+		return r.local.ChatCompletion(ctx, req)
+	}
+	if r.remote == nil {
+		return nil, fmt.Errorf("model %q needs a remote provider, but none is configured", req.Model)
+	}
+	return r.remote.ChatCompletion(ctx, req)
+}
@@ -0,0 +1,118 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(WithBaseURL(srv.URL), WithHTTPClient(srv.Client()),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+func TestClient_ToolCallRoundTrip(t *testing.T) {
+	var got chatRequest
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"model":"qwen2.5-coder","done":true,"prompt_eval_count":40,"eval_count":12,
+			"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"Read","arguments":{"path":"go.mod"}}}]}}`)
+	})
+
+	temp := 0.2
+	resp, err := c.ChatCompletion(context.Background(), agent.ChatRequest{
+		Model: "ollama/qwen2.5-coder",
+		Messages: []agent.Message{
+			{Role: "system", Content: "You review."},
+			{Role: "assistant", ToolCalls: []agent.ToolCall{{ID: "c1", Name: "Grep", Arguments: `{"pattern":"TODO"}`}}},
+			{Role: "tool", ToolCallID: "c1", Content: "no matches"},
+		},
+		Tools:       []agent.ToolDefinition{{Name: "Read", Description: "Read a file", Parameters: json.RawMessage(`{"type":"object"}`)}},
+		Temperature: &temp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Model != "qwen2.5-coder" || got.Stream {
+		t.Errorf("model=%q stream=%v", got.Model, got.Stream)
+	}
+	if got.Options["temperature"] != 0.2 {
+		t.Errorf("options = %v", got.Options)
+	}
+	if string(got.Messages[1].ToolCalls[0].Function.Arguments) != `{"pattern":"TODO"}` {
+		t.Errorf("arguments should be sent as an object: %s", got.Messages[1].ToolCalls[0].Function.Arguments)
+	}
+	if got.Messages[2].ToolName != "Grep" {
+		t.Errorf("tool result should carry the tool name, got %q", got.Messages[2].ToolName)
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Function.Name != "Read" {
+		t.Errorf("tools = %+v", got.Tools)
+	}
+
+	if len(resp.Message.ToolCalls) != 1 {
+		t.Fatalf("tool calls = %+v", resp.Message.ToolCalls)
+	}
+	tc := resp.Message.ToolCalls[0]
+	if tc.ID == "" || tc.Name != "Read" || tc.Arguments != `{"path":"go.mod"}` {
+		t.Errorf("tool call = %+v", tc)
+	}
+	if resp.Usage.TotalTokens != 52 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestClient_UniqueCallIDs(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"message":{"role":"assistant","tool_calls":[{"function":{"name":"Read","arguments":{}}}]}}`)
+	})
+	a, _ := c.ChatCompletion(context.Background(), agent.ChatRequest{Model: "ollama/m"})
+	b, _ := c.ChatCompletion(context.Background(), agent.ChatRequest{Model: "ollama/m"})
+	if a.Message.ToolCalls[0].ID == b.Message.ToolCalls[0].ID {
+		t.Errorf("call IDs repeat across turns: %q", a.Message.ToolCalls[0].ID)
+	}
+}
+
+func TestClient_HTTPError(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"error":"model \"nope\" not found, try pulling it first"}`)
+	})
+	_, err := c.ChatCompletion(context.Background(), agent.ChatRequest{Model: "ollama/nope"})
+	if err == nil || !strings.Contains(err.Error(), "try pulling it first") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+type stubProvider struct{ name string }
+
+func (s stubProvider) ChatCompletion(context.Context, agent.ChatRequest) (*agent.ChatResponse, error) {
+	return &agent.ChatResponse{Message: agent.Message{Content: s.name}}, nil
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter(stubProvider{"local"}, stubProvider{"remote"})
+	for model, want := range map[string]string{"ollama/llama3.1": "local", "anthropic/claude-sonnet-4-20250514": "remote"} {
+		resp, err := r.ChatCompletion(context.Background(), agent.ChatRequest{Model: model})
+		if err != nil || resp.Message.Content != want {
+			t.Errorf("%s: got %v, %v", model, resp, err)
+		}
+	}
+
+	if _, err := NewRouter(stubProvider{"local"}, nil).ChatCompletion(context.Background(), agent.ChatRequest{Model: "openai/gpt-4o"}); err == nil {
+		t.Error("expected error without a remote provider")
+	}
+}
@@ -0,0 +1,4 @@
+// Package ollama provides an agent.LLMProvider for models served locally by
+// Ollama. Models are selected per role with the "ollama/<name>" prefix (e.g.
+// "ollama/qwen2.5-coder:14b") and cost nothing in the budget tracker.
+package ollama
@@ -0,0 +1,132 @@
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// chatRequest is the /api/chat request body.
+type chatRequest struct {
+	Model    string         `json:"model"`
+	Messages []message      `json:"messages"`
+	Tools    []tool         `json:"tools,omitempty"`
+	Stream   bool           `json:"stream"`
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+type message struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"` // on role "tool": which tool produced the result
+}
+
+// toolCall differs from the OpenAI format: there is no call ID and the
+// arguments are a JSON object, not a string.
+type toolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type tool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters"`
+	} `json:"function"`
+}
+
+// chatResponse is the non-streaming /api/chat response.
+type chatResponse struct {
+	Model           string  `json:"model"`
+	Message         message `json:"message"`
+	Done            bool    `json:"done"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+}
+
+// toChatRequest converts an agent request to the Ollama format. Tool
+// results carry the tool name instead of a call ID, so IDs are mapped back
+// to the names of the calls that produced them.
+func toChatRequest(req agent.ChatRequest) chatRequest {
+	out := chatRequest{
+		Model:    strings.TrimPrefix(req.Model, ModelPrefix),
+		Messages: make([]message, 0, len(req.Messages)),
+	}
+	if req.Temperature != nil || req.MaxTokens != nil {
+		out.Options = map[string]any{}
+		if req.Temperature != nil {
+			out.Options["temperature"] = *req.Temperature
+		}
+		if req.MaxTokens != nil {
+			out.Options["num_predict"] = *req.MaxTokens
+		}
+	}
+
+	callNames := make(map[string]string)
+	for _, m := range req.Messages {
+		msg := message{Role: m.Role, Content: m.Content}
+		for _, tc := range m.ToolCalls {
+			callNames[tc.ID] = tc.Name
+			var call toolCall
+			call.Function.Name = tc.Name
+			call.Function.Arguments = argumentsObject(tc.Arguments)
+			msg.ToolCalls = append(msg.ToolCalls, call)
+		}
+		if m.Role == "tool" {
+			msg.ToolName = callNames[m.ToolCallID]
+		}
+		out.Messages = append(out.Messages, msg)
+	}
+
+	for _, t := range req.Tools {
+		var def tool
+		def.Type = "function"
+		def.Function.Name = t.Name
+		def.Function.Description = t.Description
+		def.Function.Parameters = t.Parameters
+		out.Tools = append(out.Tools, def)
+	}
+	return out
+}
+
+// fromChatResponse converts an Ollama response to the agent format,
+// assigning call IDs (unique per client via seq) so tool results can be
+// matched up.
+func fromChatResponse(resp *chatResponse, seq int64) *agent.ChatResponse {
+	out := &agent.ChatResponse{
+		Message: agent.Message{Role: "assistant", Content: resp.Message.Content},
+		Usage: agent.TokenUsage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+		},
+	}
+	for i, tc := range resp.Message.ToolCalls {
+		args := string(tc.Function.Arguments)
+		if args == "" || args == "null" {
+			args = "{}"
+		}
+		out.Message.ToolCalls = append(out.Message.ToolCalls, agent.ToolCall{
+			ID:        fmt.Sprintf("ollama_%d_%d", seq, i),
+			Name:      tc.Function.Name,
+			Arguments: args,
+		})
+	}
+	return out
+}
+
+// argumentsObject turns the agent's JSON-string arguments into the object
+// Ollama expects, falling back to {} for anything unparseable.
+func argumentsObject(args string) json.RawMessage {
+	if json.Valid([]byte(args)) && strings.HasPrefix(strings.TrimSpace(args), "{") {
+		return json.RawMessage(args)
+	}
+	return json.RawMessage("{}")
+}
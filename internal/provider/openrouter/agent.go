@@ -0,0 +1,82 @@
+package openrouter
+
+import (
+	"context"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// AgentProvider adapts a Client to agent.LLMProvider, translating between
+// the agent loop's provider-agnostic types and the OpenRouter wire format.
+type AgentProvider struct {
+	client *Client
+}
+
+// NewAgentProvider wraps c for use by agent runners.
+func NewAgentProvider(c *Client) *AgentProvider {
+	return &AgentProvider{client: c}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *AgentProvider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	resp, err := p.client.ChatCompletion(ctx, ToChatRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	return FromChatResponse(resp), nil
+}
+
+// ToChatRequest converts an agent request to the OpenRouter format.
+func ToChatRequest(req agent.ChatRequest) ChatRequest {
+	out := ChatRequest{
+		Model:       req.Model,
+		Messages:    make([]Message, 0, len(req.Messages)),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	}
+	for _, m := range req.Messages {
+		msg := Message{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, tc := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+				ID:       tc.ID,
+				Type:     "function",
+				Function: FunctionCall{Name: tc.Name, Arguments: tc.Arguments},
+			})
+		}
+		out.Messages = append(out.Messages, msg)
+	}
+	for _, t := range req.Tools {
+		out.Tools = append(out.Tools, ToolDefinition{
+			Type:     "function",
+			Function: FunctionDefinition{Name: t.Name, Description: t.Description, Parameters: t.Parameters},
+		})
+	}
+	return out
+}
+
+// FromChatResponse converts the first choice of an OpenRouter response to
+// the agent format.
+func FromChatResponse(resp *ChatResponse) *agent.ChatResponse {
+	out := &agent.ChatResponse{
+		ID: resp.ID,
+		Usage: agent.TokenUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}
+	if len(resp.Choices) == 0 {
+		out.Message.Role = "assistant"
+		return out
+	}
+	m := resp.Choices[0].Message
+	out.Message = agent.Message{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+	for _, tc := range m.ToolCalls {
+		out.Message.ToolCalls = append(out.Message.ToolCalls, agent.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+func TestAgentProvider_RoundTrip(t *testing.T) {
+	var got ChatRequest
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write(toolCallChatResponse())
+	})
+
+	resp, err := NewAgentProvider(client).ChatCompletion(context.Background(), agent.ChatRequest{
+		Model: "m",
+		Messages: []agent.Message{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", ToolCalls: []agent.ToolCall{{ID: "c1", Name: "Read", Arguments: `{"path":"a"}`}}},
+			{Role: "tool", ToolCallID: "c1", Content: "file"},
+		},
+		Tools: []agent.ToolDefinition{{Name: "Read", Parameters: json.RawMessage(`{"type":"object"}`)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Messages) != 3 || got.Messages[1].ToolCalls[0].Function.Name != "Read" || got.Messages[1].ToolCalls[0].Type != "function" {
+		t.Errorf("request not translated: %+v", got.Messages)
+	}
+	if got.Messages[2].ToolCallID != "c1" || len(got.Tools) != 1 || got.Tools[0].Function.Name != "Read" {
+		t.Errorf("tool data not translated: %+v", got)
+	}
+	if len(resp.Message.ToolCalls) != 2 || resp.Message.ToolCalls[1].Name != "Grep" || resp.Usage.TotalTokens != 30 {
+		t.Errorf("response not translated: %+v", resp)
+	}
+}
@@ -918,3 +918,21 @@ func TestChatCompletion_RetryExhausted429(t *testing.T) {
 		t.Errorf("expected 6 attempts, got %d", attempts.Load())
 	}
 }
+
+func TestGenerationCost(t *testing.T) {
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generation" || r.URL.Query().Get("id") != "gen-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data":{"id":"gen-1","total_cost":0.0123,"cache_discount":0.004,"provider_name":"Anthropic"}}`))
+	})
+
+	cost, err := client.GenerationCost(context.Background(), "gen-1")
+	if err != nil || cost != 0.0123 {
+		t.Errorf("cost=%v err=%v", cost, err)
+	}
+	if _, err := client.GenerationCost(context.Background(), "gen-2"); err == nil {
+		t.Error("expected error for unknown generation")
+	}
+}
@@ -61,6 +61,12 @@ func (e *ClassifiedError) Error() string {
 	return fmt.Sprintf("openrouter %s (HTTP %d): %s", e.Type, e.StatusCode, e.Message)
 }
 
+// ErrorKind returns the classification name, e.g. "rate_limit", for call
+// logs and metrics.
+func (e *ClassifiedError) ErrorKind() string {
+	return e.Type.String()
+}
+
 // Retryable returns true if this error type supports automatic retry.
 func (e *ClassifiedError) Retryable() bool {
 	switch e.Type {
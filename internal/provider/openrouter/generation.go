@@ -0,0 +1,66 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GenerationStats is what OpenRouter reports for a finished generation,
+// including the cost it actually billed after provider routing and cache
+// discounts.
+type GenerationStats struct {
+	ID                 string  `json:"id"`
+	Model              string  `json:"model"`
+	ProviderName       string  `json:"provider_name"`
+	TotalCost          float64 `json:"total_cost"`
+	CacheDiscount      float64 `json:"cache_discount"`
+	NativeTokensPrompt int     `json:"native_tokens_prompt"`
+	NativeTokensOutput int     `json:"native_tokens_completion"`
+}
+
+// Generation fetches the stats of a generation by the ID returned in the
+// chat completion response. Stats appear a few seconds after the call;
+// until then OpenRouter answers 404, returned as a ClassifiedError.
+func (c *Client) Generation(ctx context.Context, id string) (*GenerationStats, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/generation?id="+url.QueryEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &ClassifiedError{Type: ErrTimeout, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, classifyHTTPError(resp)
+	}
+	var body struct {
+		Data GenerationStats `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, &ClassifiedError{
+			Type:    ErrMalformedResponse,
+			Message: fmt.Sprintf("parse generation JSON: %v", err),
+		}
+	}
+	return &body.Data, nil
+}
+
+// GenerationCost returns the billed cost of a generation in USD. It
+// satisfies budget.GenerationCoster.
+func (c *Client) GenerationCost(ctx context.Context, id string) (float64, error) {
+	stats, err := c.Generation(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return stats.TotalCost, nil
+}
@@ -0,0 +1,4 @@
+// Package ratelimit enforces Limits.MaxCallsPerHour. A Bucket is a token
+// bucket shared by every agent; Provider wraps any agent.LLMProvider and
+// pauses calls while the bucket is empty, announcing when the window resets.
+package ratelimit
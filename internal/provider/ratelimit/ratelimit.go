@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// Bucket is a token bucket holding up to perHour calls, refilled
+// continuously at perHour per hour. Thread-safe.
+type Bucket struct {
+	mu       sync.Mutex
+	perHour  int
+	tokens   float64
+	last     time.Time
+	interval time.Duration // time to earn one token
+	now      func() time.Time
+}
+
+// NewBucket creates a full bucket. perHour <= 0 means unlimited.
+func NewBucket(perHour int) *Bucket {
+	b := &Bucket{perHour: perHour, tokens: float64(perHour), now: time.Now}
+	if perHour > 0 {
+		b.interval = time.Hour / time.Duration(perHour)
+	}
+	b.last = b.now()
+	return b
+}
+
+// Limit returns the configured calls per hour.
+func (b *Bucket) Limit() int {
+	return b.perHour
+}
+
+// Take consumes one call. When the bucket is empty it returns false and
+// how long until the next call is allowed.
+func (b *Bucket) Take() (ok bool, wait time.Duration) {
+	if b.perHour <= 0 {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+	if max := float64(b.perHour); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) * float64(b.interval))
+}
+
+// PauseFunc is called once each time calls are paused, with the time the
+// next call is allowed.
+type PauseFunc func(ctx context.Context, resumeAt time.Time)
+
+// Provider wraps an LLMProvider with a shared Bucket.
+type Provider struct {
+	next    agent.LLMProvider
+	bucket  *Bucket
+	onPause PauseFunc
+	logger  *slog.Logger
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithPauseNotifier sets the callback used to announce a pause, e.g. by
+// posting PauseMessage to the thread.
+func WithPauseNotifier(fn PauseFunc) Option {
+	return func(p *Provider) {
+		p.onPause = fn
+	}
+}
+
+// WithLogger sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(p *Provider) {
+		p.logger = l
+	}
+}
+
+// Wrap limits next with bucket. Wrap every provider with the same bucket
+// to share the limit across them.
+func Wrap(next agent.LLMProvider, bucket *Bucket, opts ...Option) *Provider {
+	p := &Provider{next: next, bucket: bucket, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// ChatCompletion implements agent.LLMProvider. While the bucket is empty
+// it waits for the next token; cancelling ctx returns a *LimitError.
+func (p *Provider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	paused := false
+	for {
+		ok, wait := p.bucket.Take()
+		if ok {
+			return p.next.ChatCompletion(ctx, req)
+		}
+
+		resumeAt := p.bucket.now().Add(wait)
+		if !paused {
+			paused = true
+			p.logger.Warn("hourly call limit reached, pausing",
+				"limit", p.bucket.Limit(), "model", req.Model, "resume_at", resumeAt)
+			if p.onPause != nil {
+				p.onPause(ctx, resumeAt)
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &LimitError{Limit: p.bucket.Limit(), ResumeAt: resumeAt, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// LimitError is returned when a run is cancelled while paused by the limit.
+type LimitError struct {
+	Limit    int
+	ResumeAt time.Time
+	Err      error
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %v", PauseMessage(e.Limit, e.ResumeAt), e.Err)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// PauseMessage is the user-facing notice for a pause.
+func PauseMessage(limit int, resumeAt time.Time) string {
+	return fmt.Sprintf("Hourly API call limit reached (%d calls/hour). Pausing; the rate limit window resets at %s.",
+		limit, resumeAt.Format("15:04"))
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+type countingProvider struct{ calls int }
+
+func (c *countingProvider) ChatCompletion(context.Context, agent.ChatRequest) (*agent.ChatResponse, error) {
+	c.calls++
+	return &agent.ChatResponse{}, nil
+}
+
+func TestBucket_RefillsOverTheHour(t *testing.T) {
+	clock := time.Date(2026, 4, 1, 14, 0, 0, 0, time.UTC)
+	b := NewBucket(60)
+	b.now = func() time.Time { return clock }
+	b.last = clock
+
+	for i := 0; i < 60; i++ {
+		if ok, _ := b.Take(); !ok {
+			t.Fatalf("call %d rejected within the limit", i)
+		}
+	}
+	ok, wait := b.Take()
+	if ok || wait != time.Minute {
+		t.Errorf("61st call: ok=%v wait=%s, want wait 1m", ok, wait)
+	}
+
+	clock = clock.Add(time.Minute)
+	if ok, _ := b.Take(); !ok {
+		t.Error("expected a token after one minute")
+	}
+}
+
+func TestBucket_Unlimited(t *testing.T) {
+	b := NewBucket(0)
+	for i := 0; i < 1000; i++ {
+		if ok, _ := b.Take(); !ok {
+			t.Fatal("unlimited bucket rejected a call")
+		}
+	}
+}
+
+func TestProvider_SharedBucketPausesAndNotifies(t *testing.T) {
+	bucket := NewBucket(2)
+	var notices []string
+	notify := WithPauseNotifier(func(_ context.Context, resumeAt time.Time) {
+		notices = append(notices, PauseMessage(bucket.Limit(), resumeAt))
+	})
+	quiet := WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	a, b := &countingProvider{}, &countingProvider{}
+	pa, pb := Wrap(a, bucket, notify, quiet), Wrap(b, bucket, notify, quiet)
+
+	ctx := context.Background()
+	pa.ChatCompletion(ctx, agent.ChatRequest{})
+	pb.ChatCompletion(ctx, agent.ChatRequest{})
+
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err := pa.ChatCompletion(cctx, agent.ChatRequest{})
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v", err)
+	}
+	if a.calls+b.calls != 2 {
+		t.Errorf("calls = %d, want 2", a.calls+b.calls)
+	}
+	if len(notices) != 1 || !strings.Contains(notices[0], "rate limit window resets at ") {
+		t.Errorf("notices = %q", notices)
+	}
+}
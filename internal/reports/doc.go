@@ -0,0 +1,4 @@
+// Package reports persists per-thread usage reports (agent.ThreadReport) as
+// one JSON file per thread under .codebutler/reports/ and serves them back
+// through the /report chat command.
+package reports
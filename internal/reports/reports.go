@@ -0,0 +1,178 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// ErrNotFound is returned when a thread has no stored report.
+var ErrNotFound = errors.New("report not found")
+
+// Store keeps one report file per thread. Thread-safe.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// DefaultDir returns the reports directory for a repo.
+func DefaultDir(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "reports")
+}
+
+// NewStore creates a store backed by dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save writes the report for its thread, replacing any earlier one.
+func (s *Store) Save(report agent.ThreadReport) error {
+	if report.ThreadID == "" {
+		return fmt.Errorf("report has no thread ID")
+	}
+	data, err := agent.MarshalReport(report)
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create reports dir: %w", err)
+	}
+	path := s.path(report.ThreadID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename report: %w", err)
+	}
+	return nil
+}
+
+// Load reads the report for a thread. It returns ErrNotFound when the
+// thread has none.
+func (s *Store) Load(threadID string) (agent.ThreadReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var report agent.ThreadReport
+	data, err := os.ReadFile(s.path(threadID))
+	if os.IsNotExist(err) {
+		return report, fmt.Errorf("thread %s: %w", threadID, ErrNotFound)
+	}
+	if err != nil {
+		return report, fmt.Errorf("read report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("parse report: %w", err)
+	}
+	return report, nil
+}
+
+// Recent returns up to limit stored reports, newest first. Unreadable files
+// are skipped.
+func (s *Store) Recent(limit int) ([]agent.ThreadReport, error) {
+	s.mu.Lock()
+	entries, err := os.ReadDir(s.dir)
+	s.mu.Unlock()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+
+	var out []agent.ThreadReport
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		r, err := s.Load(name)
+		if err != nil {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Timestamp.After(out[j].Timestamp) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// path maps a thread ID to its file. Slack thread timestamps contain only
+// digits and a dot; anything else is replaced so IDs can't escape dir.
+func (s *Store) path(threadID string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, threadID)
+	return filepath.Join(s.dir, filepath.Base(clean)+".json")
+}
+
+// Command returns the /report chat command. Without arguments it shows the
+// current thread's report; "/report <thread>" shows a past thread and
+// "/report list" the most recent ones.
+func Command(store *Store) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "report",
+		Usage:       "/report [thread-id|list]",
+		Description: "Show the usage report for this thread, a past thread, or list recent reports",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			if len(inv.Args) > 0 && inv.Args[0] == "list" {
+				return formatList(store)
+			}
+			thread := inv.Thread
+			if len(inv.Args) > 0 {
+				thread = inv.Args[0]
+			}
+			report, err := store.Load(thread)
+			if errors.Is(err, ErrNotFound) {
+				return fmt.Sprintf("No usage report for thread %s yet.", thread), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return agent.FormatUsageReport(report), nil
+		},
+	}
+}
+
+const listLimit = 10
+
+func formatList(store *Store) (string, error) {
+	recent, err := store.Recent(listLimit)
+	if err != nil {
+		return "", err
+	}
+	if len(recent) == 0 {
+		return "No usage reports stored yet.", nil
+	}
+	var b strings.Builder
+	b.WriteString("Recent reports:\n")
+	for _, r := range recent {
+		outcome := r.Outcome
+		if outcome == "" {
+			outcome = "in progress"
+		}
+		fmt.Fprintf(&b, "• %s — %s, %s, $%.4f\n", r.ThreadID, r.Timestamp.Format("2006-01-02 15:04"), outcome, r.TotalCost)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
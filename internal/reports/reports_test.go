@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+func TestStore_SaveLoad(t *testing.T) {
+	s := NewStore(t.TempDir())
+	report := agent.NewThreadReport("1712.44", map[string]*agent.Result{
+		"coder": {TurnsUsed: 4, ToolCalls: 9, TokenUsage: agent.TokenUsage{TotalTokens: 12000}},
+	})
+	report.Outcome = "success"
+	if err := s.Save(report); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Load("1712.44")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Outcome != "success" || got.AgentMetrics["coder"].ToolCalls != 9 {
+		t.Errorf("got %+v", got)
+	}
+
+	if _, err := s.Load("9999.1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing report err = %v", err)
+	}
+}
+
+func TestStore_PathStaysInDir(t *testing.T) {
+	s := NewStore("/data/reports")
+	if p := s.path("../../etc/passwd"); !strings.HasPrefix(p, "/data/reports/") {
+		t.Errorf("path escaped: %s", p)
+	}
+}
+
+func TestStore_RecentNewestFirst(t *testing.T) {
+	s := NewStore(t.TempDir())
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c"} {
+		if err := s.Save(agent.ThreadReport{ThreadID: id, Timestamp: base.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recent, err := s.Recent(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].ThreadID != "c" || recent[1].ThreadID != "b" {
+		t.Errorf("recent = %+v", recent)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	s := NewStore(t.TempDir())
+	s.Save(agent.ThreadReport{ThreadID: "100.1", Outcome: "partial", Timestamp: time.Now()})
+	cmd := Command(s)
+
+	run := func(thread string, args ...string) string {
+		t.Helper()
+		out, err := cmd.Run(context.Background(), chatcmd.Invocation{Thread: thread, Args: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	if out := run("100.1"); !strings.Contains(out, "**Outcome:** partial") {
+		t.Errorf("current thread: %q", out)
+	}
+	if out := run("200.2", "100.1"); !strings.Contains(out, "**Thread:** 100.1") {
+		t.Errorf("past thread: %q", out)
+	}
+	if out := run("200.2"); !strings.Contains(out, "No usage report for thread 200.2") {
+		t.Errorf("missing: %q", out)
+	}
+	if out := run("200.2", "list"); !strings.Contains(out, "100.1") {
+		t.Errorf("list: %q", out)
+	}
+}
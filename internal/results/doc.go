@@ -0,0 +1,5 @@
+// Package results writes a machine-readable outcome file for every finished
+// task (.codebutler/results/<task-id>.json: outcome, PR URL, files changed,
+// per-role cost and duration) for external tooling and dashboards. The API
+// serves it with the task's status at GET /api/tasks/{id}.
+package results
@@ -0,0 +1,204 @@
+package results
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/budget"
+)
+
+// Outcomes for TaskResult.Outcome.
+const (
+	OutcomeSuccess = "success"
+	OutcomePartial = "partial"
+	OutcomeFailed  = "failed"
+)
+
+// ErrNotFound is returned when a task has no result file.
+var ErrNotFound = errors.New("task result not found")
+
+// TaskResult is the end-of-task artifact. Field names are part of the
+// public format; add fields rather than renaming them.
+type TaskResult struct {
+	TaskID       string                `json:"task_id"`
+	Channel      string                `json:"channel,omitempty"`
+	Thread       string                `json:"thread,omitempty"`
+	Branch       string                `json:"branch,omitempty"`
+	Outcome      string                `json:"outcome"` // success, partial, failed
+	Error        string                `json:"error,omitempty"`
+	PRURL        string                `json:"pr_url,omitempty"`
+	FilesChanged []string              `json:"files_changed,omitempty"`
+	Roles        map[string]RoleResult `json:"roles,omitempty"`
+	TotalCost    float64               `json:"total_cost_usd"`
+	TotalTokens  int                   `json:"total_tokens"`
+	StartedAt    time.Time             `json:"started_at"`
+	FinishedAt   time.Time             `json:"finished_at"`
+	DurationMS   int64                 `json:"duration_ms"`
+}
+
+// RoleResult is one agent's share of a task.
+type RoleResult struct {
+	Model            string  `json:"model,omitempty"`
+	Turns            int     `json:"turns"`
+	ToolCalls        int     `json:"tool_calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost_usd"`
+	DurationMS       int64   `json:"duration_ms"`
+}
+
+// New starts a result for a task.
+func New(taskID, channel, thread string, startedAt time.Time) *TaskResult {
+	return &TaskResult{TaskID: taskID, Channel: channel, Thread: thread, StartedAt: startedAt}
+}
+
+// AddRole records an agent run, adding to any earlier run of the same role.
+// Cost is priced from model.
+func (t *TaskResult) AddRole(role, model string, res *agent.Result, elapsed time.Duration) {
+	if res == nil {
+		return
+	}
+	if t.Roles == nil {
+		t.Roles = make(map[string]RoleResult)
+	}
+	cost := budget.CalculateCost(model, budget.TokenUsage(res.TokenUsage))
+
+	r := t.Roles[role]
+	r.Model = model
+	r.Turns += res.TurnsUsed
+	r.ToolCalls += res.ToolCalls
+	r.PromptTokens += res.TokenUsage.PromptTokens
+	r.CompletionTokens += res.TokenUsage.CompletionTokens
+	r.Cost += cost
+	r.DurationMS += elapsed.Milliseconds()
+	t.Roles[role] = r
+
+	t.TotalCost += cost
+	t.TotalTokens += res.TokenUsage.TotalTokens
+}
+
+// Finish sets the outcome and end time. A non-nil err records the failure
+// message.
+func (t *TaskResult) Finish(outcome string, err error, at time.Time) {
+	t.Outcome = outcome
+	if err != nil {
+		t.Error = err.Error()
+	}
+	t.FinishedAt = at
+	if !t.StartedAt.IsZero() {
+		t.DurationMS = at.Sub(t.StartedAt).Milliseconds()
+	}
+}
+
+// Store keeps one result file per task. Thread-safe.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// DefaultDir returns the results directory for a repo.
+func DefaultDir(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "results")
+}
+
+// NewStore creates a store backed by dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save writes the result file for its task, replacing any earlier one.
+func (s *Store) Save(t *TaskResult) error {
+	if !validID(t.TaskID) {
+		return fmt.Errorf("invalid task ID %q", t.TaskID)
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal task result: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create results dir: %w", err)
+	}
+	path := filepath.Join(s.dir, t.TaskID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write task result: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename task result: %w", err)
+	}
+	return nil
+}
+
+// Load reads a task's result. It returns ErrNotFound for unknown or
+// invalid IDs.
+func (s *Store) Load(taskID string) (*TaskResult, error) {
+	if !validID(taskID) {
+		return nil, fmt.Errorf("task %q: %w", taskID, ErrNotFound)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(s.dir, taskID+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("task %s: %w", taskID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read task result: %w", err)
+	}
+	var t TaskResult
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parse task result: %w", err)
+	}
+	return &t, nil
+}
+
+// List returns every stored result, oldest first. Files that cannot be
+// parsed are skipped.
+func (s *Store) List() ([]*TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list task results: %w", err)
+	}
+	var out []*TaskResult
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var t TaskResult
+		if err := json.Unmarshal(data, &t); err != nil {
+			continue
+		}
+		out = append(out, &t)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out, nil
+}
+
+// validID accepts the characters used by thread timestamps and generated
+// task IDs, so an ID can never name a path outside the results dir.
+func validID(id string) bool {
+	if id == "" || id == "." || id == ".." || len(id) > 128 {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_')
+	}) < 0
+}
@@ -0,0 +1,82 @@
+package results
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+func sampleResult() *TaskResult {
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	t := New("1714.55", "C123", "1714.55", start)
+	t.PRURL = "https://github.com/acme/app/pull/42"
+	t.FilesChanged = []string{"auth/login.go"}
+	t.AddRole("coder", "anthropic/claude-sonnet-4-20250514", &agent.Result{
+		TurnsUsed: 5, ToolCalls: 11,
+		TokenUsage: agent.TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 100_000, TotalTokens: 1_100_000},
+	}, 90*time.Second)
+	t.AddRole("coder", "anthropic/claude-sonnet-4-20250514", &agent.Result{TurnsUsed: 1}, 10*time.Second)
+	t.Finish(OutcomeSuccess, nil, start.Add(3*time.Minute))
+	return t
+}
+
+func TestTaskResult_AddRoleAndFinish(t *testing.T) {
+	r := sampleResult()
+	coder := r.Roles["coder"]
+	if coder.Turns != 6 || coder.DurationMS != 100_000 {
+		t.Errorf("coder = %+v", coder)
+	}
+	if coder.Cost <= 0 || coder.Cost != r.TotalCost {
+		t.Errorf("cost: role %f total %f", coder.Cost, r.TotalCost)
+	}
+	if r.DurationMS != 180_000 || r.Outcome != OutcomeSuccess {
+		t.Errorf("duration %d outcome %q", r.DurationMS, r.Outcome)
+	}
+
+	f := New("x", "", "", time.Now())
+	f.Finish(OutcomeFailed, errors.New("tests failed"), time.Now())
+	if f.Error != "tests failed" {
+		t.Errorf("error = %q", f.Error)
+	}
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if err := s.Save(sampleResult()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Load("1714.55")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PRURL != "https://github.com/acme/app/pull/42" || len(got.FilesChanged) != 1 {
+		t.Errorf("got %+v", got)
+	}
+
+	for _, id := range []string{"nope", "../secrets", ""} {
+		if _, err := s.Load(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Load(%q) err = %v", id, err)
+		}
+	}
+	if err := s.Save(&TaskResult{TaskID: "../x"}); err == nil {
+		t.Error("expected invalid ID error")
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	s := NewStore(t.TempDir())
+	later := sampleResult()
+	earlier := New("1700.1", "C1", "1700.1", later.StartedAt.Add(-time.Hour))
+	s.Save(later)
+	s.Save(earlier)
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].TaskID != "1700.1" || list[1].TaskID != "1714.55" {
+		t.Errorf("list = %+v", list)
+	}
+}
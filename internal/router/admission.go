@@ -0,0 +1,145 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/messages"
+)
+
+// Admission enforces Limits.MaxConcurrentThreads. Threads beyond the limit
+// wait in FIFO order and start as running threads finish. Thread-safe.
+type Admission struct {
+	max     int
+	notify  func(thread, text string) // optional; posts queue notices
+	catalog *messages.Catalog
+
+	mu     sync.Mutex
+	active map[string]bool
+	queue  []pendingThread
+}
+
+type pendingThread struct {
+	thread string
+	start  func()
+}
+
+// AdmissionOption configures an Admission.
+type AdmissionOption func(*Admission)
+
+// WithQueueNotifier calls fn with a notice to post in thread: ThreadQueued
+// when it is queued or moves up the queue, ThreadDequeued when it starts.
+func WithQueueNotifier(fn func(thread, text string)) AdmissionOption {
+	return func(a *Admission) {
+		a.notify = fn
+	}
+}
+
+// WithAdmissionCatalog sets the catalog queue notices are rendered from.
+// Default: the default locale.
+func WithAdmissionCatalog(cat *messages.Catalog) AdmissionOption {
+	return func(a *Admission) {
+		a.catalog = cat
+	}
+}
+
+// NewAdmission creates admission control for up to max concurrent threads.
+// max <= 0 means unlimited.
+func NewAdmission(max int, opts ...AdmissionOption) *Admission {
+	a := &Admission{max: max, active: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.catalog == nil {
+		a.catalog = messages.New(messages.DefaultLocale)
+	}
+	return a
+}
+
+// Admit starts thread now if a slot is free and returns 0. Otherwise it
+// queues start and returns the thread's 1-based queue position. A thread
+// that is already running or queued is not added again; its current
+// position is returned. start must not block: it should hand the thread to
+// a worker goroutine.
+func (a *Admission) Admit(thread string, start func()) int {
+	a.mu.Lock()
+	if a.active[thread] {
+		a.mu.Unlock()
+		return 0
+	}
+	for i, p := range a.queue {
+		if p.thread == thread {
+			a.mu.Unlock()
+			return i + 1
+		}
+	}
+	if a.max > 0 && len(a.active) >= a.max {
+		a.queue = append(a.queue, pendingThread{thread: thread, start: start})
+		pos := len(a.queue)
+		a.mu.Unlock()
+		a.notifyQueued(thread, pos)
+		return pos
+	}
+	a.active[thread] = true
+	a.mu.Unlock()
+
+	start()
+	return 0
+}
+
+// Done frees thread's slot and starts the next queued thread, if any.
+// Calling Done for a queued thread removes it from the queue.
+func (a *Admission) Done(thread string) {
+	a.mu.Lock()
+	var next *pendingThread
+	if a.active[thread] {
+		delete(a.active, thread)
+		if len(a.queue) > 0 && (a.max <= 0 || len(a.active) < a.max) {
+			p := a.queue[0]
+			a.queue = a.queue[1:]
+			a.active[p.thread] = true
+			next = &p
+		}
+	} else if !a.removeQueued(thread) {
+		a.mu.Unlock()
+		return
+	}
+	waiting := make([]string, len(a.queue))
+	for i, p := range a.queue {
+		waiting[i] = p.thread
+	}
+	a.mu.Unlock()
+
+	if next != nil {
+		if a.notify != nil {
+			a.notify(next.thread, a.catalog.Render(messages.ThreadDequeued, nil))
+		}
+		next.start()
+	}
+	for i, t := range waiting {
+		a.notifyQueued(t, i+1)
+	}
+}
+
+func (a *Admission) notifyQueued(thread string, position int) {
+	if a.notify != nil {
+		a.notify(thread, a.catalog.Render(messages.ThreadQueued, map[string]int{"Position": position}))
+	}
+}
+
+// removeQueued drops thread from the queue. Callers hold a.mu.
+func (a *Admission) removeQueued(thread string) bool {
+	for i, p := range a.queue {
+		if p.thread == thread {
+			a.queue = append(a.queue[:i], a.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Stats returns the number of running and waiting threads.
+func (a *Admission) Stats() (active, queued int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.active), len(a.queue)
+}
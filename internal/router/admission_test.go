@@ -0,0 +1,75 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAdmission_QueuesBeyondLimit(t *testing.T) {
+	var started []string
+	var notices []string
+	a := NewAdmission(2, WithQueueNotifier(func(thread, text string) {
+		notices = append(notices, thread+": "+text)
+	}))
+	start := func(thread string) func() {
+		return func() { started = append(started, thread) }
+	}
+
+	for i, thread := range []string{"t1", "t2", "t3", "t4"} {
+		want := 0
+		if i >= 2 {
+			want = i - 1
+		}
+		if pos := a.Admit(thread, start(thread)); pos != want {
+			t.Errorf("Admit(%s) = %d, want %d", thread, pos, want)
+		}
+	}
+	if fmt.Sprint(started) != "[t1 t2]" {
+		t.Fatalf("started = %v", started)
+	}
+	if pos := a.Admit("t4", start("t4")); pos != 2 {
+		t.Errorf("re-admitting a queued thread: pos = %d", pos)
+	}
+
+	a.Done("t1")
+	if fmt.Sprint(started) != "[t1 t2 t3]" {
+		t.Errorf("after Done: started = %v", started)
+	}
+	want := []string{
+		"t3: All agents are busy. You're #1 in the queue; I'll start as soon as a slot frees up.",
+		"t4: All agents are busy. You're #2 in the queue; I'll start as soon as a slot frees up.",
+		"t3: A slot freed up. Starting now.",
+		"t4: All agents are busy. You're #1 in the queue; I'll start as soon as a slot frees up.",
+	}
+	if strings.Join(notices, "\n") != strings.Join(want, "\n") {
+		t.Errorf("notices = %q", notices)
+	}
+	if active, queued := a.Stats(); active != 2 || queued != 1 {
+		t.Errorf("stats = %d active, %d queued", active, queued)
+	}
+}
+
+func TestAdmission_DoneRemovesQueuedThread(t *testing.T) {
+	a := NewAdmission(1)
+	a.Admit("t1", func() {})
+	a.Admit("t2", func() { t.Error("t2 should not start after being cancelled") })
+	a.Done("t2")
+	a.Done("t1")
+	if active, queued := a.Stats(); active != 0 || queued != 0 {
+		t.Errorf("stats = %d active, %d queued", active, queued)
+	}
+}
+
+func TestAdmission_Unlimited(t *testing.T) {
+	a := NewAdmission(0)
+	n := 0
+	for i := 0; i < 50; i++ {
+		if pos := a.Admit(fmt.Sprint(i), func() { n++ }); pos != 0 {
+			t.Fatalf("unlimited admission queued thread %d", i)
+		}
+	}
+	if n != 50 {
+		t.Errorf("started %d", n)
+	}
+}
@@ -1,3 +1,4 @@
 // Package router provides per-agent message filtering and classification
-// based on @codebutler.<role> mentions.
+// based on @codebutler.<role> mentions. MentionRegistry extends the fixed
+// role list with custom agents and human handoff targets.
 package router
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// interruptPrefix marks a message that should stop the thread's running
+// task and redirect it.
+const interruptPrefix = "!!"
+
+// InterruptibleHandler is a ThreadHandler that receives a per-message
+// context. The context is cancelled when an interrupt ("!!") message
+// arrives for the same thread while the handler is still running.
+type InterruptibleHandler func(ctx context.Context, msg ThreadMessage)
+
+// IsInterrupt reports whether text starts with the "!!" interrupt marker
+// (after leading whitespace and an optional @codebutler.<role> mention).
+func IsInterrupt(text string) bool {
+	return strings.HasPrefix(stripLeadingMention(text), interruptPrefix)
+}
+
+// StripInterruptPrefix removes the leading "!!" marker.
+func StripInterruptPrefix(text string) string {
+	t := stripLeadingMention(text)
+	if !strings.HasPrefix(t, interruptPrefix) {
+		return text
+	}
+	return strings.TrimLeft(t[len(interruptPrefix):], " \t")
+}
+
+// InterruptInstruction wraps the new instruction so the agent, resuming its
+// saved conversation, knows to drop the previous course of action.
+func InterruptInstruction(text string) string {
+	return fmt.Sprintf("[Interrupted by the user] Stop the task you were working on and "+
+		"incorporate this new instruction before continuing:\n\n%s", StripInterruptPrefix(text))
+}
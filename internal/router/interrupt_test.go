@@ -0,0 +1,34 @@
+package router
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsInterrupt(t *testing.T) {
+	tests := map[string]bool{
+		"!! stop":                    true,
+		"  !!stop":                   true,
+		"@codebutler.coder !! stop":  true,
+		"! urgent but not interrupt": false,
+		"hello !!":                   false,
+	}
+	for text, want := range tests {
+		if got := IsInterrupt(text); got != want {
+			t.Errorf("%q: got %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestInterruptInstruction(t *testing.T) {
+	got := InterruptInstruction("@codebutler.coder !! use postgres instead")
+	if !strings.HasPrefix(got, "[Interrupted by the user]") {
+		t.Errorf("missing preamble: %s", got)
+	}
+	if !strings.HasSuffix(got, "use postgres instead") {
+		t.Errorf("missing instruction: %s", got)
+	}
+	if strings.Contains(got, "!!") {
+		t.Errorf("marker should be stripped: %s", got)
+	}
+}
@@ -0,0 +1,193 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AgentHandlePrefix starts every agent handle: @codebutler.<role>.
+const AgentHandlePrefix = "codebutler."
+
+// BuiltinRoles are the agent roles every repo has.
+var BuiltinRoles = []string{"pm", "coder", "reviewer", "researcher", "artist", "lead"}
+
+// TargetKind says who answers a mention.
+type TargetKind string
+
+const (
+	TargetAgent TargetKind = "agent" // a runner, built-in or custom
+	TargetHuman TargetKind = "human" // a person, mentioned by Slack user ID
+)
+
+// MentionTarget maps a handle to whoever handles it.
+type MentionTarget struct {
+	Handle      string     // without "@", e.g. "codebutler.coder" or "maria"
+	Kind        TargetKind // agent or human
+	Role        string     // runner role for agents (defaults to the handle suffix)
+	UserID      string     // Slack user ID for humans
+	Description string     // shown when listing targets
+}
+
+// Mention returns the text that notifies the target: the agent handle, or
+// a Slack user mention for humans.
+func (t MentionTarget) Mention() string {
+	if t.Kind == TargetHuman {
+		return "<@" + t.UserID + ">"
+	}
+	return "@" + t.Handle
+}
+
+var (
+	handleRe = regexp.MustCompile(`^[a-zA-Z][\w-]*(\.[\w-]+)*$`)
+	// anyMentionRe finds @handles not preceded by a word character, so
+	// email addresses are not mistaken for mentions.
+	anyMentionRe = regexp.MustCompile(`(^|[^\w.@])@([a-zA-Z][\w-]*(?:\.[\w-]+)*)`)
+)
+
+// MentionRegistry maps handles to agents and people. It replaces the fixed
+// @codebutler.<role> list so custom agents and humans can be delegation
+// targets too.
+type MentionRegistry struct {
+	targets map[string]MentionTarget // keyed by lowercase handle
+}
+
+// NewMentionRegistry creates a registry with the built-in agent roles plus
+// extra targets. It fails on the first invalid or duplicate target.
+func NewMentionRegistry(extra ...MentionTarget) (*MentionRegistry, error) {
+	r := &MentionRegistry{targets: make(map[string]MentionTarget)}
+	for _, role := range BuiltinRoles {
+		r.targets[AgentHandlePrefix+role] = MentionTarget{Handle: AgentHandlePrefix + role, Kind: TargetAgent, Role: role}
+	}
+	for _, t := range extra {
+		if err := r.Register(t); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a target after validating it.
+func (r *MentionRegistry) Register(t MentionTarget) error {
+	t.Handle = strings.TrimPrefix(strings.TrimSpace(t.Handle), "@")
+	if !handleRe.MatchString(t.Handle) {
+		return fmt.Errorf("invalid mention handle %q", t.Handle)
+	}
+	key := strings.ToLower(t.Handle)
+	if _, exists := r.targets[key]; exists {
+		return fmt.Errorf("mention handle @%s is already registered", t.Handle)
+	}
+
+	if t.Kind == "" {
+		t.Kind = TargetAgent
+		if t.UserID != "" {
+			t.Kind = TargetHuman
+		}
+	}
+	switch t.Kind {
+	case TargetAgent:
+		if !strings.HasPrefix(key, AgentHandlePrefix) {
+			return fmt.Errorf("agent handle @%s must start with @%s", t.Handle, AgentHandlePrefix)
+		}
+		if t.Role == "" {
+			t.Role = t.Handle[len(AgentHandlePrefix):]
+		}
+	case TargetHuman:
+		if t.UserID == "" {
+			return fmt.Errorf("human handle @%s needs a Slack user ID", t.Handle)
+		}
+		if strings.HasPrefix(key, AgentHandlePrefix) {
+			return fmt.Errorf("human handle @%s must not use the @%s prefix", t.Handle, AgentHandlePrefix)
+		}
+	default:
+		return fmt.Errorf("mention handle @%s: unknown kind %q", t.Handle, t.Kind)
+	}
+
+	r.targets[key] = t
+	return nil
+}
+
+// Lookup finds a target by handle, with or without the leading "@".
+func (r *MentionRegistry) Lookup(handle string) (MentionTarget, bool) {
+	t, ok := r.targets[strings.ToLower(strings.TrimPrefix(handle, "@"))]
+	return t, ok
+}
+
+// Handles returns every registered handle, sorted.
+func (r *MentionRegistry) Handles() []string {
+	out := make([]string, 0, len(r.targets))
+	for _, t := range r.targets {
+		out = append(out, t.Handle)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Parse returns the registered targets mentioned in text, in order of first
+// appearance, and any @codebutler.* handles that are not registered. Other
+// unknown @words are ordinary chat and ignored.
+func (r *MentionRegistry) Parse(text string) (targets []MentionTarget, unknown []string) {
+	seen := make(map[string]bool)
+	for _, m := range anyMentionRe.FindAllStringSubmatch(text, -1) {
+		handle := strings.TrimRight(m[2], ".-")
+		key := strings.ToLower(handle)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if t, ok := r.targets[key]; ok {
+			targets = append(targets, t)
+		} else if strings.HasPrefix(key, AgentHandlePrefix) {
+			unknown = append(unknown, handle)
+		}
+	}
+	return targets, unknown
+}
+
+// UnknownTargetError reports mentions of agents that do not exist.
+type UnknownTargetError struct {
+	Handles []string
+	Known   []string
+}
+
+func (e *UnknownTargetError) Error() string {
+	mentions := make([]string, len(e.Handles))
+	for i, h := range e.Handles {
+		mentions[i] = "@" + h
+	}
+	known := make([]string, len(e.Known))
+	for i, h := range e.Known {
+		known[i] = "@" + h
+	}
+	return fmt.Sprintf("unknown delegation target %s; known targets: %s",
+		strings.Join(mentions, ", "), strings.Join(known, ", "))
+}
+
+// Validate returns an *UnknownTargetError when text mentions an agent
+// handle that is not registered.
+func (r *MentionRegistry) Validate(text string) error {
+	if _, unknown := r.Parse(text); len(unknown) > 0 {
+		return &UnknownTargetError{Handles: unknown, Known: r.Handles()}
+	}
+	return nil
+}
+
+// ShouldProcess is ShouldProcess for registry-defined agents: the PM takes
+// messages addressed to it or to no agent at all (including unknown
+// handles, so it can answer with the error); other agents take messages
+// that mention their role.
+func (r *MentionRegistry) ShouldProcess(role, text string) bool {
+	targets, _ := r.Parse(text)
+	addressedToAgent := false
+	for _, t := range targets {
+		if t.Kind != TargetAgent {
+			continue
+		}
+		if t.Role == role {
+			return true
+		}
+		addressedToAgent = true
+	}
+	return role == "pm" && !addressedToAgent
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testRegistry(t *testing.T) *MentionRegistry {
+	t.Helper()
+	r, err := NewMentionRegistry(
+		MentionTarget{Handle: "codebutler.security", Description: "security review agent"},
+		MentionTarget{Handle: "@maria", UserID: "U123"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestMentionRegistry_Register(t *testing.T) {
+	r := testRegistry(t)
+
+	sec, ok := r.Lookup("@codebutler.security")
+	if !ok || sec.Kind != TargetAgent || sec.Role != "security" {
+		t.Errorf("security = %+v", sec)
+	}
+	maria, ok := r.Lookup("Maria")
+	if !ok || maria.Kind != TargetHuman || maria.Mention() != "<@U123>" {
+		t.Errorf("maria = %+v", maria)
+	}
+
+	for _, bad := range []MentionTarget{
+		{Handle: "codebutler.coder"},                        // duplicate built-in
+		{Handle: "security"},                                // agent without prefix
+		{Handle: "bob", Kind: TargetHuman},                  // human without user ID
+		{Handle: "codebutler.bob", UserID: "U9"},            // human posing as agent
+		{Handle: "9lives"},                                  // invalid handle
+		{Handle: "codebutler.x", Kind: TargetKind("robot")}, // unknown kind
+	} {
+		if err := r.Register(bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestMentionRegistry_Parse(t *testing.T) {
+	r := testRegistry(t)
+
+	targets, unknown := r.Parse("@codebutler.coder please fix; cc @maria and @codebutler.secruity. Mail ops@maria.dev, @someone")
+	var handles []string
+	for _, t := range targets {
+		handles = append(handles, t.Handle)
+	}
+	if strings.Join(handles, ",") != "codebutler.coder,maria" {
+		t.Errorf("targets = %v", handles)
+	}
+	if len(unknown) != 1 || unknown[0] != "codebutler.secruity" {
+		t.Errorf("unknown = %v", unknown)
+	}
+}
+
+func TestMentionRegistry_Validate(t *testing.T) {
+	r := testRegistry(t)
+
+	if err := r.Validate("@codebutler.security review the auth change"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := r.Validate("@codebutler.qa run the suite")
+	var unknownErr *UnknownTargetError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("got %v", err)
+	}
+	if !strings.Contains(err.Error(), "unknown delegation target @codebutler.qa") || !strings.Contains(err.Error(), "@codebutler.security") {
+		t.Errorf("error = %q", err)
+	}
+}
+
+func TestMentionRegistry_ShouldProcess(t *testing.T) {
+	r := testRegistry(t)
+
+	tests := []struct {
+		role, text string
+		want       bool
+	}{
+		{"security", "@codebutler.security check this", true},
+		{"pm", "@codebutler.security check this", false},
+		{"pm", "hey @maria can you look?", true},
+		{"pm", "@codebutler.qa run tests", true}, // unknown agent: PM answers with the error
+		{"coder", "@codebutler.qa run tests", false},
+		{"coder", "@codebutler.pm and @codebutler.coder", true},
+	}
+	for _, tt := range tests {
+		if got := r.ShouldProcess(tt.role, tt.text); got != tt.want {
+			t.Errorf("ShouldProcess(%q, %q) = %v", tt.role, tt.text, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package router
+
+import "strings"
+
+// Priority orders messages within a thread worker's inbox.
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityUrgent
+)
+
+// String returns the priority name for logging.
+func (p Priority) String() string {
+	if p == PriorityUrgent {
+		return "urgent"
+	}
+	return "normal"
+}
+
+// urgentPrefix marks a message as urgent when it leads the text.
+const urgentPrefix = "!"
+
+// ClassifyPriority returns PriorityUrgent if the message starts with "!"
+// (after leading whitespace and an optional @codebutler.<role> mention) or
+// was sent by one of the designated urgent users.
+func ClassifyPriority(text, userID string, urgentUsers map[string]bool) Priority {
+	if urgentUsers[userID] {
+		return PriorityUrgent
+	}
+	if strings.HasPrefix(stripLeadingMention(text), urgentPrefix) {
+		return PriorityUrgent
+	}
+	return PriorityNormal
+}
+
+// StripPriorityPrefix removes a single leading "!" marker, and the mention
+// before it, so the agent sees the message text without them. It accepts
+// the same positions as ClassifyPriority. Other "!"s (e.g. the "!!"
+// interrupt marker) are left to their own handling.
+func StripPriorityPrefix(text string) string {
+	t := stripLeadingMention(text)
+	if strings.HasPrefix(t, urgentPrefix) && !strings.HasPrefix(t, urgentPrefix+urgentPrefix) {
+		return strings.TrimLeft(t[len(urgentPrefix):], " \t")
+	}
+	return text
+}
+
+// stripLeadingMention drops whitespace and a leading @codebutler.<role>.
+func stripLeadingMention(text string) string {
+	t := strings.TrimLeft(text, " \t")
+	if loc := mentionPattern.FindStringIndex(t); loc != nil && loc[0] == 0 {
+		t = strings.TrimLeft(t[loc[1]:], " \t:,")
+	}
+	return t
+}
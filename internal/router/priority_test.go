@@ -0,0 +1,45 @@
+package router
+
+import "testing"
+
+func TestClassifyPriority(t *testing.T) {
+	urgent := map[string]bool{"U-ONCALL": true}
+
+	tests := []struct {
+		text, user string
+		want       Priority
+	}{
+		{"! prod is down", "U1", PriorityUrgent},
+		{"  !check logs", "U1", PriorityUrgent},
+		{"@codebutler.pm ! prod is down", "U1", PriorityUrgent},
+		{"!! stop and do this instead", "U1", PriorityUrgent},
+		{"refactor the auth module", "U1", PriorityNormal},
+		{"this is broken!", "U1", PriorityNormal},
+		{"refactor the auth module", "U-ONCALL", PriorityUrgent},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyPriority(tt.text, tt.user, urgent); got != tt.want {
+			t.Errorf("%q from %s: got %s, want %s", tt.text, tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestStripPriorityPrefix(t *testing.T) {
+	tests := map[string]string{
+		"! prod is down": "prod is down",
+		"!check logs":    "check logs",
+		"!! interrupt":   "!! interrupt",
+		"no marker":      "no marker",
+		"trailing bang!": "trailing bang!",
+
+		"@codebutler.coder ! fix the build": "fix the build",
+		"@codebutler.pm: !deploy":           "deploy",
+		"@codebutler.pm hello!":             "@codebutler.pm hello!",
+	}
+	for in, want := range tests {
+		if got := StripPriorityPrefix(in); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
@@ -1,60 +1,105 @@
 package router
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 )
 
-// Redactor filters sensitive content from outbound messages.
-// Uses a set of built-in patterns plus optional custom patterns.
+// Redactor filters sensitive content from outbound messages and logs,
+// replacing each match with [REDACTED:<type>]. It checks known credential
+// values (the daemon's own tokens and keys), the built-in patterns, and any
+// custom patterns added from policy.json. Safe for concurrent use once
+// configured.
 type Redactor struct {
-	patterns []*regexp.Regexp
+	known    []string // longest first
+	patterns []redactPattern
 }
 
-// defaultPatterns are the built-in redaction patterns from SPEC.md.
-// They catch API keys, JWTs, private keys, connection strings, and internal IPs.
-var defaultPatterns = []*regexp.Regexp{
-	// API keys (common prefixes)
-	regexp.MustCompile(`(?i)(sk-[a-zA-Z0-9]{20,})`),                      // OpenAI/Anthropic
-	regexp.MustCompile(`(?i)(xoxb-[a-zA-Z0-9-]+)`),                       // Slack bot token
-	regexp.MustCompile(`(?i)(xoxp-[a-zA-Z0-9-]+)`),                       // Slack user token
-	regexp.MustCompile(`(?i)(xapp-[a-zA-Z0-9-]+)`),                       // Slack app token
-	regexp.MustCompile(`(?i)(ghp_[a-zA-Z0-9]{36,})`),                     // GitHub PAT
-	regexp.MustCompile(`(?i)(gho_[a-zA-Z0-9]{36,})`),                     // GitHub OAuth
-	regexp.MustCompile(`(?i)(AKIA[A-Z0-9]{16})`),                         // AWS access key
-	regexp.MustCompile(`(?i)(AIza[A-Za-z0-9_-]{35})`),                    // Google API key
+// redactPattern finds one kind of sensitive content. When group is set,
+// only that submatch is masked so the surrounding label ("API_KEY=") stays
+// readable.
+type redactPattern struct {
+	kind  string
+	re    *regexp.Regexp
+	group int
+}
 
-	// JWTs (three base64-encoded segments separated by dots)
-	regexp.MustCompile(`eyJ[a-zA-Z0-9_-]+\.eyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]+`),
+// minKnownSecret is the shortest known value masked verbatim; shorter
+// values would match ordinary words.
+const minKnownSecret = 8
 
+// defaultPatterns are the built-in redaction patterns from SPEC.md. They are
+// checked in order; specific formats come before the generic ones that
+// would also match them.
+var defaultPatterns = []redactPattern{
 	// Private keys
-	regexp.MustCompile(`(?s)-----BEGIN (?:RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----.*?-----END (?:RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----`),
+	{kind: "private_key", re: regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)},
+
+	// JWTs (three base64-encoded segments separated by dots)
+	{kind: "jwt", re: regexp.MustCompile(`eyJ[a-zA-Z0-9_-]+\.eyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]+`)},
 
-	// Connection strings
-	regexp.MustCompile(`(?i)((?:postgres|mysql|mongodb|redis|amqp)://[^\s"']+)`),
+	// Connection strings, credentials and all
+	{kind: "connection_string", re: regexp.MustCompile(`(?i)\b(?:postgres(?:ql)?|mysql|mongodb(?:\+srv)?|rediss?|amqps?)://[^\s"']+`)},
+
+	// API keys (common prefixes)
+	{kind: "api_key", re: regexp.MustCompile(`\bsk-(?:ant-|or-v1-|proj-)?[A-Za-z0-9_-]{20,}`)},                 // Anthropic/OpenRouter/OpenAI
+	{kind: "api_key", re: regexp.MustCompile(`\bx(?:ox[abposr]|app)-[A-Za-z0-9-]{10,}`)},                       // Slack tokens
+	{kind: "api_key", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)}, // GitHub
+	{kind: "api_key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},                                 // AWS access key
+	{kind: "api_key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},                                       // Google API key
+	{kind: "api_key", re: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}`)},                                 // Stripe
+
+	// Bearer tokens and passwords embedded in other URLs
+	{kind: "secret", re: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{16,}=*)`), group: 1},
+	{kind: "secret", re: regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^:/@\s]+:([^@/\s]{3,})@`), group: 1},
+
+	// .env lines and config entries whose name says they hold a secret.
+	// The value must end the token, so code such as apiKey = os.Getenv("X")
+	// is left alone.
+	{kind: "secret", re: regexp.MustCompile(`(?im)^\s*(?:export\s+)?["']?[A-Z0-9_.-]*(?:secret|token|passw(?:or)?d|api[_-]?key|access[_-]?key|private[_-]?key|credentials?)[A-Z0-9_.-]*["']?\s*[:=]\s*["']?([^\s"',;()]{6,})(?:["',;]|\s|$)`), group: 1},
 
 	// Internal/private IPs
-	regexp.MustCompile(`\b(10\.\d{1,3}\.\d{1,3}\.\d{1,3})\b`),
-	regexp.MustCompile(`\b(172\.(?:1[6-9]|2\d|3[01])\.\d{1,3}\.\d{1,3})\b`),
-	regexp.MustCompile(`\b(192\.168\.\d{1,3}\.\d{1,3})\b`),
+	{kind: "internal_ip", re: regexp.MustCompile(`\b10\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)},
+	{kind: "internal_ip", re: regexp.MustCompile(`\b172\.(?:1[6-9]|2\d|3[01])\.\d{1,3}\.\d{1,3}\b`)},
+	{kind: "internal_ip", re: regexp.MustCompile(`\b192\.168\.\d{1,3}\.\d{1,3}\b`)},
 }
 
-const redactedPlaceholder = "[REDACTED]"
-
-// NewRedactor creates a redactor with default patterns.
-func NewRedactor() *Redactor {
+// NewRedactor creates a redactor with default patterns that also masks the
+// given values verbatim, typically config.GlobalConfig.SecretValues.
+func NewRedactor(known ...string) *Redactor {
+	var vals []string
+	for _, v := range known {
+		if v = strings.TrimSpace(v); len(v) >= minKnownSecret {
+			vals = append(vals, v)
+		}
+	}
+	sort.Slice(vals, func(i, j int) bool { return len(vals[i]) > len(vals[j]) })
 	return &Redactor{
-		patterns: defaultPatterns,
+		known:    vals,
+		patterns: append([]redactPattern(nil), defaultPatterns...),
 	}
 }
 
-// AddPattern adds a custom regex pattern to the redactor.
+// AddPattern adds a custom regex pattern to the redactor. Matches are
+// replaced with [REDACTED:custom].
 func (r *Redactor) AddPattern(pattern string) error {
+	return r.AddNamedPattern("custom", pattern)
+}
+
+// AddNamedPattern adds a custom regex pattern whose matches are replaced
+// with [REDACTED:<name>].
+func (r *Redactor) AddNamedPattern(name, pattern string) error {
 	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return err
 	}
-	r.patterns = append(r.patterns, re)
+	r.patterns = append(r.patterns, redactPattern{kind: name, re: re})
 	return nil
 }
 
@@ -68,26 +113,94 @@ func (r *Redactor) AddPatterns(patterns []string) error {
 	return nil
 }
 
-// Redact replaces all sensitive matches in text with [REDACTED].
+// redactionPolicy is the "redaction" section of .codebutler/policy.json.
+type redactionPolicy struct {
+	Redaction struct {
+		Patterns []struct {
+			Name  string `json:"name"`
+			Regex string `json:"regex"`
+		} `json:"patterns"`
+	} `json:"redaction"`
+}
+
+// LoadPolicy adds the custom patterns from a repo's policy.json. A missing
+// file is not an error.
+func (r *Redactor) LoadPolicy(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var p redactionPolicy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, pat := range p.Redaction.Patterns {
+		name := pat.Name
+		if name == "" {
+			name = "custom"
+		}
+		if err := r.AddNamedPattern(name, pat.Regex); err != nil {
+			return fmt.Errorf("%s: redaction pattern %q: %w", path, name, err)
+		}
+	}
+	return nil
+}
+
+// Redact replaces all sensitive matches in text with [REDACTED:<type>].
 // This is a pure function on the text content — microseconds, no LLM.
 func (r *Redactor) Redact(text string) string {
-	result := text
+	for _, v := range r.known {
+		text = strings.ReplaceAll(text, v, placeholderFor("config"))
+	}
 	for _, p := range r.patterns {
-		result = p.ReplaceAllString(result, redactedPlaceholder)
+		text = p.replace(text)
 	}
-	return result
+	return text
 }
 
-// ContainsSensitive checks if text matches any redaction pattern.
+// ContainsSensitive checks if text matches any known value or redaction
+// pattern.
 func (r *Redactor) ContainsSensitive(text string) bool {
+	for _, v := range r.known {
+		if strings.Contains(text, v) {
+			return true
+		}
+	}
 	for _, p := range r.patterns {
-		if p.MatchString(text) {
+		if p.re.MatchString(text) {
 			return true
 		}
 	}
 	return false
 }
 
+func placeholderFor(kind string) string {
+	return "[REDACTED:" + kind + "]"
+}
+
+func (p redactPattern) replace(text string) string {
+	matches := p.re.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*p.group], m[2*p.group+1]
+		if start < 0 || strings.HasPrefix(text[start:], "[REDACTED:") {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(placeholderFor(p.kind))
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
 // PrefixMessage adds the @codebutler.<role>: prefix to outbound messages.
 func PrefixMessage(role, text string) string {
 	prefix := "@codebutler." + role + ": "
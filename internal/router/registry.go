@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"log/slog"
 	"sync"
 	"time"
@@ -22,6 +23,8 @@ type ThreadMessage struct {
 	MessageTS string
 	UserID    string
 	Text      string
+	Priority  Priority // set by Dispatch when zero; urgent jumps the inbox queue
+	Interrupt bool     // set by Dispatch for "!!" messages; the running task was cancelled
 }
 
 // ThreadHandler is the callback invoked by a thread worker for each message.
@@ -33,11 +36,12 @@ type ThreadHandler func(msg ThreadMessage)
 type ThreadRegistry struct {
 	mu      sync.Mutex
 	workers map[string]*threadWorker
-	handler ThreadHandler
+	handler InterruptibleHandler
 	logger  *slog.Logger
 
 	inactivityTimeout time.Duration
 	inboxSize         int
+	urgentUsers       map[string]bool
 }
 
 // RegistryOption configures the thread registry.
@@ -57,6 +61,15 @@ func WithInboxSize(n int) RegistryOption {
 	}
 }
 
+// WithUrgentUsers marks every message from these Slack user IDs as urgent.
+func WithUrgentUsers(userIDs ...string) RegistryOption {
+	return func(r *ThreadRegistry) {
+		for _, id := range userIDs {
+			r.urgentUsers[id] = true
+		}
+	}
+}
+
 // WithRegistryLogger sets the logger for the registry.
 func WithRegistryLogger(l *slog.Logger) RegistryOption {
 	return func(r *ThreadRegistry) {
@@ -66,12 +79,21 @@ func WithRegistryLogger(l *slog.Logger) RegistryOption {
 
 // NewThreadRegistry creates a new thread registry.
 func NewThreadRegistry(handler ThreadHandler, opts ...RegistryOption) *ThreadRegistry {
+	return NewInterruptibleRegistry(func(_ context.Context, msg ThreadMessage) {
+		handler(msg)
+	}, opts...)
+}
+
+// NewInterruptibleRegistry creates a thread registry whose handler receives
+// a context that is cancelled by "!!" interrupt messages.
+func NewInterruptibleRegistry(handler InterruptibleHandler, opts ...RegistryOption) *ThreadRegistry {
 	r := &ThreadRegistry{
 		workers:           make(map[string]*threadWorker),
 		handler:           handler,
 		logger:            slog.Default(),
 		inactivityTimeout: defaultInactivityTimeout,
 		inboxSize:         defaultInboxSize,
+		urgentUsers:       make(map[string]bool),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -80,8 +102,17 @@ func NewThreadRegistry(handler ThreadHandler, opts ...RegistryOption) *ThreadReg
 }
 
 // Dispatch sends a message to the appropriate thread worker.
-// Creates the worker if it doesn't exist or has died.
+// Creates the worker if it doesn't exist or has died. Urgent messages go to
+// a separate lane that the worker drains before its normal inbox.
 func (r *ThreadRegistry) Dispatch(msg ThreadMessage) {
+	if IsInterrupt(msg.Text) {
+		msg.Interrupt = true
+		msg.Priority = PriorityUrgent
+	}
+	if msg.Priority == PriorityNormal {
+		msg.Priority = ClassifyPriority(msg.Text, msg.UserID, r.urgentUsers)
+	}
+
 	r.mu.Lock()
 
 	w, ok := r.workers[msg.ThreadTS]
@@ -92,9 +123,21 @@ func (r *ThreadRegistry) Dispatch(msg ThreadMessage) {
 
 	r.mu.Unlock()
 
+	inbox := w.inbox
+	if msg.Priority == PriorityUrgent {
+		inbox = w.urgent
+		r.logger.Info("urgent message queued ahead", "thread", msg.ThreadTS, "event_id", msg.EventID)
+	}
+
+	// Cancel before enqueueing so the interrupt message itself can never be
+	// the handler that gets cancelled.
+	if msg.Interrupt && w.cancelCurrent() {
+		r.logger.Info("interrupted running task", "thread", msg.ThreadTS, "event_id", msg.EventID)
+	}
+
 	// Non-blocking send (drop if inbox full — shouldn't happen with reasonable sizes)
 	select {
-	case w.inbox <- msg:
+	case inbox <- msg:
 	default:
 		r.logger.Warn("thread worker inbox full, dropping message",
 			"thread", msg.ThreadTS,
@@ -103,6 +146,18 @@ func (r *ThreadRegistry) Dispatch(msg ThreadMessage) {
 	}
 }
 
+// Interrupt cancels the handler currently running for a thread, if any.
+// Returns true if a running handler was cancelled.
+func (r *ThreadRegistry) Interrupt(threadTS string) bool {
+	r.mu.Lock()
+	w, ok := r.workers[threadTS]
+	r.mu.Unlock()
+	if !ok || !w.alive() {
+		return false
+	}
+	return w.cancelCurrent()
+}
+
 // ActiveThreads returns the number of currently active thread workers.
 func (r *ThreadRegistry) ActiveThreads() int {
 	r.mu.Lock()
@@ -121,6 +176,7 @@ func (r *ThreadRegistry) spawnWorker(threadTS string) *threadWorker {
 	w := &threadWorker{
 		threadTS: threadTS,
 		inbox:    make(chan ThreadMessage, r.inboxSize),
+		urgent:   make(chan ThreadMessage, r.inboxSize),
 		done:     make(chan struct{}),
 		handler:  r.handler,
 		timeout:  r.inactivityTimeout,
@@ -135,10 +191,26 @@ func (r *ThreadRegistry) spawnWorker(threadTS string) *threadWorker {
 type threadWorker struct {
 	threadTS string
 	inbox    chan ThreadMessage
+	urgent   chan ThreadMessage // drained before inbox
 	done     chan struct{}
-	handler  ThreadHandler
+	handler  InterruptibleHandler
 	timeout  time.Duration
 	logger   *slog.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // cancels the in-flight handler; nil when idle
+}
+
+// cancelCurrent cancels the in-flight handler. Returns false if idle.
+func (w *threadWorker) cancelCurrent() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel == nil {
+		return false
+	}
+	w.cancel()
+	w.cancel = nil
+	return true
 }
 
 // alive returns true if the worker goroutine is still running.
@@ -168,31 +240,50 @@ func (w *threadWorker) run() {
 	defer timer.Stop()
 
 	for {
+		// Urgent lane first: only fall through to the normal inbox when no
+		// urgent message is waiting.
+		var msg ThreadMessage
 		select {
-		case msg := <-w.inbox:
-			// Reset inactivity timer
-			if !timer.Stop() {
-				select {
-				case <-timer.C:
-				default:
-				}
+		case msg = <-w.urgent:
+		default:
+			select {
+			case msg = <-w.urgent:
+			case msg = <-w.inbox:
+			case <-timer.C:
+				w.logger.Info("thread worker exiting due to inactivity",
+					"thread", w.threadTS,
+				)
+				return
 			}
-			timer.Reset(w.timeout)
-
-			// Process the message with panic recovery
-			w.processMessage(msg)
+		}
 
-		case <-timer.C:
-			w.logger.Info("thread worker exiting due to inactivity",
-				"thread", w.threadTS,
-			)
-			return
+		// Reset inactivity timer
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
 		}
+		timer.Reset(w.timeout)
+
+		// Process the message with panic recovery
+		w.processMessage(msg)
 	}
 }
 
 // processMessage handles a single message with panic recovery.
 func (w *threadWorker) processMessage(msg ThreadMessage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.mu.Lock()
+	w.cancel = cancel
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		w.cancel = nil
+		w.mu.Unlock()
+		cancel()
+	}()
 	defer func() {
 		if r := recover(); r != nil {
 			w.logger.Error("panic in message handler",
@@ -203,5 +294,5 @@ func (w *threadWorker) processMessage(msg ThreadMessage) {
 		}
 	}()
 
-	w.handler(msg)
+	w.handler(ctx, msg)
 }
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -175,3 +177,104 @@ func TestThreadRegistry_MessageOrdering(t *testing.T) {
 		t.Errorf("expected [first, second, third], got %v", order)
 	}
 }
+
+func TestThreadRegistry_UrgentJumpsQueue(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+
+	registry := NewThreadRegistry(func(msg ThreadMessage) {
+		if msg.Text == "long refactor" {
+			<-release // hold the worker busy while more messages queue up
+		}
+		mu.Lock()
+		order = append(order, msg.Text)
+		mu.Unlock()
+	}, WithInactivityTimeout(1*time.Second))
+
+	registry.Dispatch(ThreadMessage{ThreadTS: "thread-1", Text: "long refactor"})
+	time.Sleep(20 * time.Millisecond)
+	registry.Dispatch(ThreadMessage{ThreadTS: "thread-1", Text: "batch item"})
+	registry.Dispatch(ThreadMessage{ThreadTS: "thread-1", Text: "! production is down"})
+	close(release)
+
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 {
+		t.Fatalf("expected 3 messages, got %v", order)
+	}
+	if order[1] != "! production is down" {
+		t.Errorf("urgent message should be processed next, got %v", order)
+	}
+}
+
+func TestThreadRegistry_UrgentUsers(t *testing.T) {
+	got := make(chan Priority, 1)
+	registry := NewThreadRegistry(func(msg ThreadMessage) {
+		got <- msg.Priority
+	}, WithInactivityTimeout(1*time.Second), WithUrgentUsers("U-ONCALL"))
+
+	registry.Dispatch(ThreadMessage{ThreadTS: "thread-1", UserID: "U-ONCALL", Text: "check the logs"})
+
+	select {
+	case p := <-got:
+		if p != PriorityUrgent {
+			t.Errorf("expected urgent, got %s", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler not called")
+	}
+}
+
+func TestThreadRegistry_InterruptCancelsRunningTask(t *testing.T) {
+	started := make(chan struct{})
+	var mu sync.Mutex
+	var events []string
+
+	registry := NewInterruptibleRegistry(func(ctx context.Context, msg ThreadMessage) {
+		if msg.Text == "long task" {
+			close(started)
+			select {
+			case <-ctx.Done():
+				mu.Lock()
+				events = append(events, "cancelled")
+				mu.Unlock()
+			case <-time.After(2 * time.Second):
+				mu.Lock()
+				events = append(events, "finished")
+				mu.Unlock()
+			}
+			return
+		}
+		mu.Lock()
+		events = append(events, fmt.Sprintf("%s interrupt=%v ctxErr=%v", msg.Text, msg.Interrupt, ctx.Err()))
+		mu.Unlock()
+	}, WithInactivityTimeout(time.Second))
+
+	registry.Dispatch(ThreadMessage{ThreadTS: "thread-1", Text: "long task"})
+	<-started
+	registry.Dispatch(ThreadMessage{ThreadTS: "thread-1", Text: "!! use postgres instead"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+	if events[0] != "cancelled" {
+		t.Errorf("running task should be cancelled, got %q", events[0])
+	}
+	if events[1] != "!! use postgres instead interrupt=true ctxErr=<nil>" {
+		t.Errorf("interrupt message should run with a live context, got %q", events[1])
+	}
+}
+
+func TestThreadRegistry_InterruptIdle(t *testing.T) {
+	registry := NewThreadRegistry(func(msg ThreadMessage) {}, WithInactivityTimeout(time.Second))
+	if registry.Interrupt("nope") {
+		t.Error("interrupting an unknown thread should return false")
+	}
+}
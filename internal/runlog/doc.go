@@ -0,0 +1,9 @@
+// Package runlog records every task run as a JSONL file under
+// .codebutler/runs/<run-id>.jsonl: the prompt, streamed progress, each LLM
+// turn, tool calls with their results, and the final result and cost.
+// `codebutler replay <run-id>` reads it back to show exactly what the agent
+// did. Log wraps the agent's provider, tool executor, and partial sink, so
+// the agent package needs no changes to be recorded. With
+// privacy.metadataOnlyStorage on, create the log WithMetadataOnly so the
+// text, tool arguments, and tool output are stored as hashes.
+package runlog
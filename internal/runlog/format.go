@@ -0,0 +1,109 @@
+package runlog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// previewChars is how much of long text Format shows unless full is set.
+const previewChars = 200
+
+// Format renders a run as a readable timeline for `codebutler replay`.
+// With full, prompts, model output, and tool arguments and results are
+// printed in full instead of truncated.
+func Format(records []Record, full bool) string {
+	if len(records) == 0 {
+		return "(empty run)\n"
+	}
+	var b strings.Builder
+	start := records[0].Time
+	for _, r := range records {
+		fmt.Fprintf(&b, "%s %7s  %-7s", r.Time.Local().Format("15:04:05"), "+"+offset(r.Time.Sub(start)), label(r.Type))
+		if r.Role != "" {
+			fmt.Fprintf(&b, " [%s]", r.Role)
+		}
+		b.WriteString(" " + headline(r))
+		b.WriteString("\n")
+		if body := body(r, full); body != "" {
+			b.WriteString(indent(body))
+		}
+	}
+	return b.String()
+}
+
+func label(typ string) string {
+	switch typ {
+	case TypePrompt:
+		return "PROMPT"
+	case TypeStream:
+		return "STREAM"
+	case TypeTurn:
+		return "LLM"
+	case TypeToolUse:
+		return "TOOL"
+	case TypeToolResult:
+		return "  ->"
+	case TypeResult:
+		return "RESULT"
+	}
+	return strings.ToUpper(typ)
+}
+
+func headline(r Record) string {
+	var parts []string
+	switch r.Type {
+	case TypeTurn:
+		parts = append(parts, r.Model)
+		if r.Tokens != nil {
+			parts = append(parts, fmt.Sprintf("%d in / %d out tokens", r.Tokens.PromptTokens, r.Tokens.CompletionTokens))
+		}
+		parts = append(parts, fmt.Sprintf("%dms", r.DurationMS))
+	case TypeToolUse:
+		parts = append(parts, r.Tool)
+	case TypeToolResult:
+		status := "ok"
+		if r.IsError || r.Error != "" {
+			status = "error"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s (%dms, %d bytes)", r.Tool, status, r.DurationMS, len(r.Text)))
+	case TypeResult:
+		parts = append(parts, fmt.Sprintf("%d turns", r.Turns))
+		if r.Tokens != nil {
+			parts = append(parts, fmt.Sprintf("%d tokens", r.Tokens.TotalTokens))
+		}
+		if r.CostUSD > 0 {
+			parts = append(parts, fmt.Sprintf("$%.4f", r.CostUSD))
+		}
+	}
+	if r.Error != "" {
+		parts = append(parts, "error: "+r.Error)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func body(r Record, full bool) string {
+	text := r.Text
+	if r.Type == TypeToolUse {
+		text = r.Args
+	}
+	if r.Type == TypeToolResult && !full {
+		return "" // tool output is noise unless asked for
+	}
+	text = strings.TrimSpace(text)
+	if !full && len(text) > previewChars {
+		text = text[:previewChars] + "…"
+	}
+	return text
+}
+
+func indent(s string) string {
+	return "    " + strings.ReplaceAll(s, "\n", "\n    ") + "\n"
+}
+
+func offset(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,304 @@
+package runlog
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/privacy"
+)
+
+// Record types.
+const (
+	TypePrompt     = "prompt"
+	TypeStream     = "stream"
+	TypeTurn       = "turn"
+	TypeToolUse    = "tool_use"
+	TypeToolResult = "tool_result"
+	TypeResult     = "result"
+)
+
+// ErrNotFound is returned for unknown run IDs.
+var ErrNotFound = errors.New("run not found")
+
+// Record is one line of a run log. Fields beyond Time, Type, and Role
+// depend on the type.
+type Record struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	Role string    `json:"role,omitempty"`
+
+	Text       string            `json:"text,omitempty"`  // prompt, stream, turn, and result text; tool output
+	Model      string            `json:"model,omitempty"` // turn
+	Tokens     *agent.TokenUsage `json:"tokens,omitempty"`
+	Tool       string            `json:"tool,omitempty"`    // tool_use, tool_result
+	CallID     string            `json:"call_id,omitempty"` // tool_use, tool_result
+	Args       string            `json:"args,omitempty"`    // tool_use
+	IsError    bool              `json:"is_error,omitempty"`
+	DurationMS int64             `json:"duration_ms,omitempty"`
+	Turns      int               `json:"turns,omitempty"`    // result
+	CostUSD    float64           `json:"cost_usd,omitempty"` // result
+	Error      string            `json:"error,omitempty"`
+}
+
+// Dir returns the run log directory for a repo.
+func Dir(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "runs")
+}
+
+// NewID returns a sortable run ID, e.g. "20261016-101500-3fa2".
+func NewID(now time.Time) string {
+	b := make([]byte, 2)
+	rand.Read(b) //nolint:errcheck // crypto/rand never fails on supported platforms
+	return now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// validID keeps run IDs inside the runs directory.
+func validID(id string) bool {
+	if id == "" || id == "." || id == ".." || len(id) > 128 {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_')
+	}) < 0
+}
+
+// Log appends records to one run's file. Thread-safe; a nil *Log
+// discards everything.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	w    *bufio.Writer
+	now  func() time.Time
+	role string
+
+	metadataOnly bool
+}
+
+// Option configures a Log.
+type Option func(*Log)
+
+// WithMetadataOnly replaces text, tool arguments, and tool output with
+// privacy.Hash placeholders, for privacy.metadataOnlyStorage.
+func WithMetadataOnly(on bool) Option {
+	return func(l *Log) {
+		l.metadataOnly = on
+	}
+}
+
+// Create opens the log for runID in dir, appending if it exists. The file
+// is readable only by its owner.
+func Create(dir, runID, role string, opts ...Option) (*Log, error) {
+	if !validID(runID) {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create runs dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, runID+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open run log: %w", err)
+	}
+	l := &Log{f: f, w: bufio.NewWriter(f), now: time.Now, role: role}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l, nil
+}
+
+// Append writes rec, filling in Time and Role. Each record is flushed so a
+// crashed run still leaves a readable log.
+func (l *Log) Append(rec Record) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if rec.Time.IsZero() {
+		rec.Time = l.now()
+	}
+	if rec.Role == "" {
+		rec.Role = l.role
+	}
+	if l.metadataOnly {
+		rec.Text = privacy.Hash(rec.Text)
+		rec.Args = privacy.Hash(rec.Args)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal run record: %w", err)
+	}
+	l.w.Write(data)
+	l.w.WriteByte('\n')
+	return l.w.Flush()
+}
+
+// Prompt records the input that started the run.
+func (l *Log) Prompt(text string) error {
+	return l.Append(Record{Type: TypePrompt, Text: text})
+}
+
+// Result records how the run ended.
+func (l *Log) Result(res *agent.Result, costUSD float64, err error) error {
+	rec := Record{Type: TypeResult, CostUSD: costUSD}
+	if res != nil {
+		rec.Text = res.Response
+		rec.Turns = res.TurnsUsed
+		rec.Model = res.Model
+		rec.Tokens = &res.TokenUsage
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return l.Append(rec)
+}
+
+// Close flushes and closes the file.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.w.Flush(); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// Provider records each LLM turn.
+type Provider struct {
+	agent.LLMProvider
+	log *Log
+}
+
+// WrapProvider wraps p so its responses are recorded in l.
+func (l *Log) WrapProvider(p agent.LLMProvider) *Provider {
+	return &Provider{LLMProvider: p, log: l}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *Provider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	start := time.Now()
+	resp, err := p.LLMProvider.ChatCompletion(ctx, req)
+	rec := Record{Type: TypeTurn, Model: req.Model, DurationMS: time.Since(start).Milliseconds()}
+	if resp != nil {
+		rec.Text = resp.Message.Content
+		rec.Tokens = &resp.Usage
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	p.log.Append(rec) //nolint:errcheck // logging must not fail the run
+	return resp, err
+}
+
+// Tools records each tool call and its result.
+type Tools struct {
+	agent.ToolExecutor
+	log *Log
+}
+
+// WrapTools wraps e so its calls are recorded in l.
+func (l *Log) WrapTools(e agent.ToolExecutor) *Tools {
+	return &Tools{ToolExecutor: e, log: l}
+}
+
+// Execute implements agent.ToolExecutor.
+func (t *Tools) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	t.log.Append(Record{Type: TypeToolUse, Tool: call.Name, CallID: call.ID, Args: call.Arguments}) //nolint:errcheck // see Provider
+	start := time.Now()
+	result, err := t.ToolExecutor.Execute(ctx, call)
+	rec := Record{
+		Type:       TypeToolResult,
+		Tool:       call.Name,
+		CallID:     call.ID,
+		Text:       result.Content,
+		IsError:    result.IsError,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	t.log.Append(rec) //nolint:errcheck // see Provider
+	return result, err
+}
+
+// Partial implements agent.PartialSink, recording streamed progress. Chain
+// it with the messenger's sink via Tee.
+func (l *Log) Partial(_ context.Context, _, _, text string) {
+	l.Append(Record{Type: TypeStream, Text: text}) //nolint:errcheck // see Provider
+}
+
+// Tee forwards partial output to every sink.
+type Tee []agent.PartialSink
+
+// Partial implements agent.PartialSink.
+func (t Tee) Partial(ctx context.Context, channel, thread, text string) {
+	for _, s := range t {
+		s.Partial(ctx, channel, thread, text)
+	}
+}
+
+// Read loads a run's records. Malformed lines (e.g. a torn final write)
+// are skipped.
+func Read(dir, runID string) ([]Record, error) {
+	if !validID(runID) {
+		return nil, fmt.Errorf("run %q: %w", runID, ErrNotFound)
+	}
+	f, err := os.Open(filepath.Join(dir, runID+".jsonl"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("run %s: %w", runID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open run log: %w", err)
+	}
+	defer f.Close()
+
+	var out []Record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue
+		}
+		out = append(out, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return out, fmt.Errorf("read run log: %w", err)
+	}
+	return out, nil
+}
+
+// List returns run IDs in dir, newest first.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read runs dir: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".jsonl"); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
@@ -0,0 +1,136 @@
+package runlog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+type stubProvider struct{}
+
+func (stubProvider) ChatCompletion(context.Context, agent.ChatRequest) (*agent.ChatResponse, error) {
+	return &agent.ChatResponse{
+		Message: agent.Message{Role: "assistant", Content: "Reading the handler first."},
+		Usage:   agent.TokenUsage{PromptTokens: 1200, CompletionTokens: 80, TotalTokens: 1280},
+	}, nil
+}
+
+type stubTools struct{}
+
+func (stubTools) Execute(_ context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	return agent.ToolResult{ToolCallID: call.ID, Content: "package auth\n\nfunc Login() {}"}, nil
+}
+func (stubTools) ListTools() []agent.ToolDefinition { return nil }
+
+func TestLog_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	id := NewID(time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC))
+	if !strings.HasPrefix(id, "20261016-101500-") {
+		t.Errorf("id = %q", id)
+	}
+
+	l, err := Create(dir, id, "coder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	l.Prompt("add a login page")
+	l.WrapProvider(stubProvider{}).ChatCompletion(ctx, agent.ChatRequest{Model: "openai/gpt-4o"})
+	Tee{l}.Partial(ctx, "C1", "t1", "_running `ReadFile`…_")
+	l.WrapTools(stubTools{}).Execute(ctx, agent.ToolCall{ID: "c1", Name: "ReadFile", Arguments: `{"path":"auth/login.go"}`})
+	l.Result(&agent.Result{Response: "Done.", TurnsUsed: 2, TokenUsage: agent.TokenUsage{TotalTokens: 1500}}, 0.0123, nil)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A torn final line is skipped.
+	f, _ := os.OpenFile(filepath.Join(dir, id+".jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"type":"tur`)
+	f.Close()
+
+	records, err := Read(dir, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, r := range records {
+		types = append(types, r.Type)
+	}
+	if got := strings.Join(types, ","); got != "prompt,turn,stream,tool_use,tool_result,result" {
+		t.Fatalf("types = %s", got)
+	}
+	if records[3].Args != `{"path":"auth/login.go"}` || records[4].CallID != "c1" || records[1].Role != "coder" {
+		t.Errorf("records = %+v", records)
+	}
+
+	out := Format(records, false)
+	for _, want := range []string{"PROMPT  [coder] ", "add a login page", "openai/gpt-4o, 1200 in / 80 out tokens", "ReadFile ok", "2 turns, 1500 tokens, $0.0123"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("replay missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "func Login") {
+		t.Error("tool output should only show with full")
+	}
+	if !strings.Contains(Format(records, true), "func Login") {
+		t.Error("full replay should include tool output")
+	}
+
+	ids, _ := List(dir)
+	if len(ids) != 1 || ids[0] != id {
+		t.Errorf("List = %v", ids)
+	}
+}
+
+func TestLog_MetadataOnly(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Create(dir, "run1", "coder", WithMetadataOnly(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	l.Prompt("add a login page")
+	l.WrapTools(stubTools{}).Execute(ctx, agent.ToolCall{ID: "c1", Name: "ReadFile", Arguments: `{"path":"auth/login.go"}`})
+	l.Close()
+
+	info, err := os.Stat(filepath.Join(dir, "run1.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("run log mode = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "run1.jsonl"))
+	for _, leak := range []string{"login page", "auth/login.go", "func Login"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("metadata-only log contains %q", leak)
+		}
+	}
+	records, _ := Read(dir, "run1")
+	if len(records) != 3 || records[1].Tool != "ReadFile" || records[2].CallID != "c1" {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Read(dir, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing run: %v", err)
+	}
+	if _, err := Read(dir, "../etc/passwd"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("path traversal: %v", err)
+	}
+	if _, err := Create(dir, "a/b", "pm"); err == nil {
+		t.Error("expected invalid ID error")
+	}
+	var nilLog *Log
+	if nilLog.Prompt("x") != nil || nilLog.Close() != nil {
+		t.Error("nil log should be a no-op")
+	}
+}
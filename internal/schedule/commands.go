@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+const scheduleUsage = "Usage:\n" +
+	"• `/schedule add <name> <min> <hour> <day> <month> <weekday> <prompt>`\n" +
+	"• `/schedule add <name> @daily|@weekly|@hourly|@monthly <prompt>`\n" +
+	"• `/schedule list`\n" +
+	"• `/schedule remove <name>`"
+
+// Command returns the /schedule chat command. Jobs added from chat post
+// their results to the channel they were added in.
+func Command(s *Scheduler) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "schedule",
+		Usage:       "/schedule add|list|remove",
+		Description: "Manage recurring prompts that run on a cron schedule",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			if len(inv.Args) == 0 {
+				return scheduleUsage, nil
+			}
+			switch inv.Args[0] {
+			case "list":
+				return formatJobs(s), nil
+			case "remove":
+				if len(inv.Args) != 2 {
+					return scheduleUsage, nil
+				}
+				if err := s.Remove(inv.Args[1]); err != nil {
+					return err.Error(), nil
+				}
+				return fmt.Sprintf("Removed schedule `%s`.", inv.Args[1]), nil
+			case "add":
+				job, ok := parseAdd(inv.Args[1:])
+				if !ok {
+					return scheduleUsage, nil
+				}
+				job.Channel, job.CreatedBy = inv.Channel, inv.UserID
+				if err := s.Add(job); err != nil {
+					return err.Error(), nil
+				}
+				return fmt.Sprintf("Scheduled `%s` (%s). Next run: %s.",
+					job.Name, job.Cron, s.NextRun(job.Name).Format("Mon Jan 2 15:04")), nil
+			}
+			return scheduleUsage, nil
+		},
+	}
+}
+
+// parseAdd splits "<name> <cron…> <prompt…>": a shorthand cron is one
+// word, a standard one is five.
+func parseAdd(args []string) (Job, bool) {
+	if len(args) < 3 {
+		return Job{}, false
+	}
+	n := 5
+	if strings.HasPrefix(args[1], "@") {
+		n = 1
+	}
+	if len(args) < 2+n {
+		return Job{}, false
+	}
+	return Job{
+		Name:   args[0],
+		Cron:   strings.Join(args[1:1+n], " "),
+		Prompt: strings.Join(args[1+n:], " "),
+	}, true
+}
+
+func formatJobs(s *Scheduler) string {
+	jobs := s.List()
+	if len(jobs) == 0 {
+		return "No scheduled prompts. Add one with `/schedule add`."
+	}
+	var b strings.Builder
+	b.WriteString("Scheduled prompts:\n")
+	for _, j := range jobs {
+		fmt.Fprintf(&b, "• `%s` (%s, %s) next %s — %s\n",
+			j.Name, j.Cron, j.Source, s.NextRun(j.Name).Format("Mon Jan 2 15:04"), j.Prompt)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
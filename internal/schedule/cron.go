@@ -0,0 +1,132 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute hour day-of-month
+// month day-of-week. Fields accept *, lists (1,15), ranges (1-5), and steps
+// (*/15, 0-30/10). Day-of-week is 0-6 with 0 = Sunday (7 is also Sunday).
+// The shorthands @hourly, @daily, @weekly, and @monthly are supported.
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64 // bit sets
+	domAny, dowAny                bool
+}
+
+var shorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@nightly": "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron parses a cron expression.
+func ParseCron(expr string) (Cron, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if s, ok := shorthands[strings.ToLower(expr)]; ok {
+		spec = s
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("cron %q: want 5 fields (minute hour day month weekday), got %d", expr, len(fields))
+	}
+
+	c := Cron{expr: expr, domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	for _, f := range []struct {
+		dst      *uint64
+		text     string
+		min, max int
+		name     string
+	}{
+		{&c.minute, fields[0], 0, 59, "minute"},
+		{&c.hour, fields[1], 0, 23, "hour"},
+		{&c.dom, fields[2], 1, 31, "day of month"},
+		{&c.month, fields[3], 1, 12, "month"},
+		{&c.dow, fields[4], 0, 7, "day of week"},
+	} {
+		if *f.dst, err = parseField(f.text, f.min, f.max); err != nil {
+			return Cron{}, fmt.Errorf("cron %q: %s: %w", expr, f.name, err)
+		}
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	return c, nil
+}
+
+func parseField(text string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(text, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("invalid value %q", b)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// String returns the expression as written.
+func (c Cron) String() string {
+	return c.expr
+}
+
+// Matches reports whether t (to the minute) fires the schedule. As in
+// standard cron, when both day fields are restricted either may match.
+func (c Cron) Matches(t time.Time) bool {
+	if c.minute&(1<<t.Minute()) == 0 || c.hour&(1<<t.Hour()) == 0 || c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domOK := c.dom&(1<<t.Day()) != 0
+	dowOK := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// Next returns the first time after t that fires the schedule, searching
+// up to a year ahead. It returns the zero time if there is none.
+func (c Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for end := t.AddDate(1, 0, 0); t.Before(end); t = t.Add(time.Minute) {
+		if c.Matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,5 @@
+// Package schedule runs recurring prompts on cron schedules: nightly test
+// summaries, weekly dependency audits, and so on. Jobs come from the repo
+// config and from the /schedule chat command, are persisted in
+// .codebutler/schedules.json, and are handed to a JobRunner when due.
+package schedule
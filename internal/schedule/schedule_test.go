@@ -0,0 +1,169 @@
+package schedule
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr  string
+		at    time.Time
+		match bool
+	}{
+		{"0 2 * * *", time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC), true},
+		{"0 2 * * *", time.Date(2026, 3, 4, 2, 1, 0, 0, time.UTC), false},
+		{"*/15 9-17 * * 1-5", time.Date(2026, 3, 4, 9, 45, 0, 0, time.UTC), true},  // Wednesday
+		{"*/15 9-17 * * 1-5", time.Date(2026, 3, 7, 9, 45, 0, 0, time.UTC), false}, // Saturday
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), true},             // Sunday
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 1 * 1", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), true}, // Monday, not the 1st
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if got := c.Matches(tt.at); got != tt.match {
+			t.Errorf("%s at %s = %v, want %v", tt.expr, tt.at, got, tt.match)
+		}
+	}
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@yearly"} {
+		if _, err := ParseCron(bad); err == nil {
+			t.Errorf("ParseCron(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	c, _ := ParseCron("30 3 * * *")
+	got := c.Next(time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 3, 5, 3, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+}
+
+type recordingRunner struct {
+	mu   sync.Mutex
+	jobs []string
+}
+
+func (r *recordingRunner) RunJob(_ context.Context, job Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job.Name)
+	return nil
+}
+
+func newTestScheduler(t *testing.T) (*Scheduler, *recordingRunner, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	runner := &recordingRunner{}
+	s, err := New(path, runner, WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, runner, path
+}
+
+func TestScheduler_TickRunsDueJobsOnce(t *testing.T) {
+	s, runner, path := newTestScheduler(t)
+	if err := s.SyncConfig([]Job{{Name: "nightly-tests", Cron: "0 2 * * *", Prompt: "run tests and summarize failures", Channel: "C1"}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Add(Job{Name: "audit", Cron: "@weekly", Prompt: "dependency audit", Channel: "C1"})
+
+	at := time.Date(2026, 3, 4, 2, 0, 20, 0, time.UTC)
+	s.Tick(context.Background(), at)
+	s.Tick(context.Background(), at.Add(10*time.Second)) // same minute
+	s.wg.Wait()
+	if strings.Join(runner.jobs, ",") != "nightly-tests" {
+		t.Errorf("ran %v", runner.jobs)
+	}
+
+	// Reloaded from disk, the job remembers it already ran this minute.
+	s2, err := New(path, runner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s2.List()) != 2 {
+		t.Fatalf("reloaded %d jobs", len(s2.List()))
+	}
+	s2.Tick(context.Background(), at)
+	s2.wg.Wait()
+	if len(runner.jobs) != 1 {
+		t.Errorf("job ran again after reload: %v", runner.jobs)
+	}
+}
+
+func TestScheduler_SyncConfigAndRemove(t *testing.T) {
+	s, _, _ := newTestScheduler(t)
+	s.SyncConfig([]Job{{Name: "a", Cron: "@daily", Prompt: "x"}, {Name: "b", Cron: "@daily", Prompt: "y"}})
+	s.Add(Job{Name: "mine", Cron: "@hourly", Prompt: "z"})
+	s.SyncConfig([]Job{{Name: "b", Cron: "@daily", Prompt: "y"}})
+
+	var names []string
+	for _, j := range s.List() {
+		names = append(names, j.Name)
+	}
+	if strings.Join(names, ",") != "b,mine" {
+		t.Errorf("jobs = %v", names)
+	}
+	if err := s.Remove("b"); err == nil || !strings.Contains(err.Error(), "repo config") {
+		t.Errorf("removing a config job: %v", err)
+	}
+	if err := s.Remove("mine"); err != nil {
+		t.Error(err)
+	}
+	if err := s.SyncConfig([]Job{{Name: "bad", Cron: "nope", Prompt: "x"}}); err == nil {
+		t.Error("expected invalid cron error")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	s, _, _ := newTestScheduler(t)
+	s.now = func() time.Time { return time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC) }
+	cmd := Command(s)
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := cmd.Run(context.Background(), chatcmd.Invocation{Channel: "C9", UserID: "U1", Args: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	if out := run("add", "nightly", "0", "2", "*", "*", "*", "run", "the", "tests"); !strings.Contains(out, "Next run: Thu Mar 5 02:00") {
+		t.Errorf("add: %q", out)
+	}
+	if out := run("add", "audit", "@weekly", "dependency", "audit"); !strings.Contains(out, "Scheduled `audit`") {
+		t.Errorf("add shorthand: %q", out)
+	}
+	if out := run("add", "nightly", "@daily", "again"); !strings.Contains(out, "already exists") {
+		t.Errorf("duplicate: %q", out)
+	}
+	if out := run("add", "broken", "0", "2"); !strings.HasPrefix(out, "Usage:") {
+		t.Errorf("incomplete add: %q", out)
+	}
+
+	out := run("list")
+	if !strings.Contains(out, "`nightly` (0 2 * * *, chat) next Thu Mar 5 02:00 — run the tests") {
+		t.Errorf("list: %q", out)
+	}
+	if j := s.List()[1]; j.Channel != "C9" || j.CreatedBy != "U1" {
+		t.Errorf("job = %+v", j)
+	}
+
+	if out := run("remove", "audit"); out != "Removed schedule `audit`." {
+		t.Errorf("remove: %q", out)
+	}
+}
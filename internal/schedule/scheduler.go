@@ -0,0 +1,268 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job sources.
+const (
+	SourceConfig = "config" // from the repo config; replaced on reload
+	SourceChat   = "chat"   // added with /schedule add
+)
+
+// Job is a prompt that runs on a cron schedule.
+type Job struct {
+	Name      string    `json:"name"`
+	Cron      string    `json:"cron"`
+	Prompt    string    `json:"prompt"`
+	Channel   string    `json:"channel"`
+	Source    string    `json:"source"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	LastRun   time.Time `json:"last_run,omitempty"`
+}
+
+// JobRunner runs a due job, typically by starting a new thread in
+// job.Channel with job.Prompt as the first message and posting the result.
+type JobRunner interface {
+	RunJob(ctx context.Context, job Job) error
+}
+
+// DefaultPath returns the schedules file for a repo.
+func DefaultPath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "schedules.json")
+}
+
+// Scheduler fires jobs when their cron expression matches the current
+// minute. Thread-safe.
+type Scheduler struct {
+	path   string
+	runner JobRunner
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	crons map[string]Cron
+	wg    sync.WaitGroup // running jobs
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithLogger sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = l
+	}
+}
+
+// New loads the jobs stored at path. A missing file means no jobs yet.
+func New(path string, runner JobRunner, opts ...Option) (*Scheduler, error) {
+	s := &Scheduler{
+		path:   path,
+		runner: runner,
+		logger: slog.Default(),
+		now:    time.Now,
+		jobs:   make(map[string]*Job),
+		crons:  make(map[string]Cron),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read schedules: %w", err)
+	}
+	var jobs []*Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("parse schedules: %w", err)
+	}
+	for _, j := range jobs {
+		c, err := ParseCron(j.Cron)
+		if err != nil {
+			s.logger.Warn("skipping stored schedule", "name", j.Name, "err", err)
+			continue
+		}
+		s.jobs[j.Name], s.crons[j.Name] = j, c
+	}
+	return s, nil
+}
+
+// SyncConfig replaces the config-defined jobs with jobs, keeping the last
+// run time of jobs that still exist. Chat-added jobs are untouched.
+func (s *Scheduler) SyncConfig(jobs []Job) error {
+	parsed := make(map[string]Cron, len(jobs))
+	for _, j := range jobs {
+		c, err := ParseCron(j.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule %q: %w", j.Name, err)
+		}
+		parsed[j.Name] = c
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, j := range s.jobs {
+		if j.Source == SourceConfig {
+			if _, keep := parsed[name]; !keep {
+				delete(s.jobs, name)
+				delete(s.crons, name)
+			}
+		}
+	}
+	for _, j := range jobs {
+		if old, ok := s.jobs[j.Name]; ok {
+			if old.Source != SourceConfig {
+				return fmt.Errorf("schedule %q is defined in config and via /schedule", j.Name)
+			}
+			j.LastRun = old.LastRun
+		}
+		j.Source = SourceConfig
+		s.jobs[j.Name], s.crons[j.Name] = &j, parsed[j.Name]
+	}
+	return s.save()
+}
+
+// Add registers a chat job. Names must be unique.
+func (s *Scheduler) Add(job Job) error {
+	c, err := ParseCron(job.Cron)
+	if err != nil {
+		return err
+	}
+	if job.Name == "" || job.Prompt == "" {
+		return fmt.Errorf("a schedule needs a name and a prompt")
+	}
+	job.Source = SourceChat
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("schedule %q already exists", job.Name)
+	}
+	s.jobs[job.Name], s.crons[job.Name] = &job, c
+	return s.save()
+}
+
+// Remove deletes a chat job. Config jobs must be removed from the config.
+func (s *Scheduler) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("no schedule named %q", name)
+	}
+	if j.Source == SourceConfig {
+		return fmt.Errorf("schedule %q comes from the repo config; remove it there", name)
+	}
+	delete(s.jobs, name)
+	delete(s.crons, name)
+	return s.save()
+}
+
+// List returns all jobs sorted by name.
+func (s *Scheduler) List() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, *j)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Name < out[k].Name })
+	return out
+}
+
+// NextRun returns when a job fires next.
+func (s *Scheduler) NextRun(name string) time.Time {
+	s.mu.Lock()
+	c, ok := s.crons[name]
+	s.mu.Unlock()
+	if !ok {
+		return time.Time{}
+	}
+	return c.Next(s.now())
+}
+
+// Tick starts every job due at now. Each job runs at most once per minute,
+// in its own goroutine.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) {
+	minute := now.Truncate(time.Minute)
+	var due []Job
+	s.mu.Lock()
+	for name, j := range s.jobs {
+		if s.crons[name].Matches(minute) && j.LastRun.Before(minute) {
+			j.LastRun = minute
+			due = append(due, *j)
+		}
+	}
+	if len(due) > 0 {
+		if err := s.save(); err != nil {
+			s.logger.Error("failed to save schedules", "err", err)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, j := range due {
+		s.wg.Add(1)
+		go func(j Job) {
+			defer s.wg.Done()
+			s.logger.Info("running scheduled job", "name", j.Name, "cron", j.Cron)
+			if err := s.runner.RunJob(ctx, j); err != nil {
+				s.logger.Error("scheduled job failed", "name", j.Name, "err", err)
+			}
+		}(j)
+	}
+}
+
+// Run ticks at the start of every minute until ctx is done, then waits for
+// running jobs to return.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.wg.Wait()
+	for {
+		now := s.now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case t := <-timer.C:
+			s.Tick(ctx, t)
+		}
+	}
+}
+
+// save writes the jobs file. Callers hold s.mu.
+func (s *Scheduler) save() error {
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Name < jobs[k].Name })
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal schedules: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create schedules dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write schedules: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename schedules: %w", err)
+	}
+	return nil
+}
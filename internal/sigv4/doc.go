@@ -0,0 +1,4 @@
+// Package sigv4 signs HTTP requests with AWS Signature Version 4, so the
+// CloudWatch log backend and the S3/GCS sync backend can talk to their
+// APIs without an SDK.
+package sigv4
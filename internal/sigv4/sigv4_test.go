@@ -0,0 +1,22 @@
+package sigv4
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestSign_Vanilla checks the signer against the "get-vanilla" case from
+// the AWS SigV4 test suite.
+func TestSign_Vanilla(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	Sign(req, nil, "us-east-1", "service", Credentials{"AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}, now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("authorization:\n got %s\nwant %s", got, want)
+	}
+}
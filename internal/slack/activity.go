@@ -0,0 +1,72 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// RepliesFetcher is the subset of the Slack API used to read thread
+// activity. *slack.Client satisfies it.
+type RepliesFetcher interface {
+	GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error)
+}
+
+// ThreadActivity implements worktree.ThreadChecker with conversations.replies:
+// a thread's last activity is its latest reply, or the parent message when
+// nobody replied.
+type ThreadActivity struct {
+	api    RepliesFetcher
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewThreadActivity creates a checker. A thread counts as active when its
+// last message is newer than window.
+func NewThreadActivity(api RepliesFetcher, window time.Duration) *ThreadActivity {
+	return &ThreadActivity{api: api, window: window, now: time.Now}
+}
+
+// ThreadActivity returns a checker backed by this client's Slack connection.
+func (c *Client) ThreadActivity(window time.Duration) *ThreadActivity {
+	return NewThreadActivity(c.api, window)
+}
+
+// LastActivity returns the time of the newest message in the thread, or the
+// zero time when the thread no longer exists.
+func (a *ThreadActivity) LastActivity(ctx context.Context, channelID, threadTS string) (time.Time, error) {
+	// The parent comes first and carries the latest reply's timestamp, so
+	// one message is enough.
+	msgs, _, _, err := a.api.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: threadTS,
+		Limit:     1,
+	})
+	if err != nil {
+		if err.Error() == "thread_not_found" || err.Error() == "message_not_found" {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("fetch thread %s: %w", threadTS, err)
+	}
+	if len(msgs) == 0 {
+		return time.Time{}, nil
+	}
+
+	latest := msgs[0].Timestamp
+	if tsAfter(msgs[0].LatestReply, latest) {
+		latest = msgs[0].LatestReply
+	}
+	return ParseTS(latest), nil
+}
+
+// IsThreadActive reports whether the thread exists and had a message within
+// the activity window.
+func (a *ThreadActivity) IsThreadActive(ctx context.Context, channelID, threadTS string) (bool, error) {
+	last, err := a.LastActivity(ctx, channelID, threadTS)
+	if err != nil || last.IsZero() {
+		return false, err
+	}
+	return a.now().Sub(last) < a.window, nil
+}
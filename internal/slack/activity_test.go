@@ -0,0 +1,48 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+type fakeReplies struct {
+	parent slack.Message
+	err    error
+}
+
+func (f *fakeReplies) GetConversationRepliesContext(_ context.Context, _ *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	if f.err != nil {
+		return nil, false, "", f.err
+	}
+	return []slack.Message{f.parent}, true, "next", nil
+}
+
+func TestThreadActivity(t *testing.T) {
+	parent := msg("1700000000.000100", "U1", "add login")
+	parent.LatestReply = "1700007200.000200"
+	a := NewThreadActivity(&fakeReplies{parent: parent}, 3*time.Hour)
+	a.now = func() time.Time { return time.Unix(1700007200+3600, 0) }
+
+	last, err := a.LastActivity(context.Background(), "C1", parent.Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !last.Equal(time.Unix(1700007200, 200000)) {
+		t.Errorf("last activity = %v", last)
+	}
+	if active, _ := a.IsThreadActive(context.Background(), "C1", parent.Timestamp); !active {
+		t.Error("thread with a reply an hour ago should be active")
+	}
+
+	gone := NewThreadActivity(&fakeReplies{err: errors.New("thread_not_found")}, time.Hour)
+	if last, err := gone.LastActivity(context.Background(), "C1", "1.1"); err != nil || !last.IsZero() {
+		t.Errorf("deleted thread: last=%v err=%v", last, err)
+	}
+	if active, err := gone.IsThreadActive(context.Background(), "C1", "1.1"); active || err != nil {
+		t.Errorf("deleted thread: active=%v err=%v", active, err)
+	}
+}
@@ -139,14 +139,15 @@ func PlanApproval(planSummary string) *BlockKitMessage {
 	}
 }
 
-// DestructiveToolApproval creates an approval message for destructive tool execution.
-func DestructiveToolApproval(toolName, command string) *BlockKitMessage {
+// CostApproval creates an approval message for a plan whose estimated cost
+// is above the pre-flight threshold. estimate is the rendered cost table.
+func CostApproval(estimate string) *BlockKitMessage {
 	return &BlockKitMessage{
-		HeaderText: "Destructive Action Approval",
-		BodyText:   fmt.Sprintf("Tool `%s` wants to execute:\n```\n%s\n```", toolName, command),
+		HeaderText: "Cost Approval",
+		BodyText:   estimate,
 		Buttons: []ButtonOption{
-			{ActionID: "approve_destructive", Text: "Approve", Value: "approve", Style: "danger"},
-			{ActionID: "reject_destructive", Text: "Reject", Value: "reject"},
+			{ActionID: "approve_cost", Text: "Approve", Value: "approve", Style: "primary"},
+			{ActionID: "reject_cost", Text: "Reject", Value: "reject", Style: "danger"},
 		},
 	}
 }
@@ -72,6 +72,17 @@ func TestBlockKitMessage_BuildFallbackText(t *testing.T) {
 	}
 }
 
+func TestCostApproval(t *testing.T) {
+	msg := CostApproval("**Total estimated:** $42.0000")
+	if msg.HeaderText != "Cost Approval" || msg.BodyText != "**Total estimated:** $42.0000" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	click := Interaction{Type: InteractionButtonClick, ActionID: msg.Buttons[0].ActionID, Value: msg.Buttons[0].Value}
+	if msg.Buttons[0].ActionID != "approve_cost" || !IsApproveSignal(click) {
+		t.Errorf("first button should approve: %+v", msg.Buttons[0])
+	}
+}
+
 func TestPlanApproval(t *testing.T) {
 	msg := PlanApproval("Step 1: Read files\nStep 2: Write code")
 
@@ -94,17 +105,6 @@ func TestPlanApproval(t *testing.T) {
 	}
 }
 
-func TestDestructiveToolApproval(t *testing.T) {
-	msg := DestructiveToolApproval("Bash", "rm -rf /tmp/test")
-
-	if msg.HeaderText != "Destructive Action Approval" {
-		t.Errorf("unexpected header: %q", msg.HeaderText)
-	}
-	if len(msg.Buttons) != 2 {
-		t.Errorf("expected 2 buttons, got %d", len(msg.Buttons))
-	}
-}
-
 func TestEmojiReactionEvent(t *testing.T) {
 	evt := EmojiReactionEvent("C123", "T456", "M789", "U001", "octagonal_sign")
 
@@ -0,0 +1,281 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultCatchUpMaxAge is how old a missed message may be before catch-up
+// ignores it. Anything older is assumed to be stale or handled elsewhere.
+const defaultCatchUpMaxAge = 24 * time.Hour
+
+// HistoryFetcher is the subset of the Slack API used for catch-up.
+// *slack.Client satisfies it.
+type HistoryFetcher interface {
+	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error)
+}
+
+// CatchUpResult is the outcome of a catch-up pass.
+type CatchUpResult struct {
+	Messages []MessageEvent // missed messages, oldest first
+	Skipped  int            // messages dropped for being older than MaxAge
+	LatestTS string         // newest timestamp seen (new last-seen cursor)
+}
+
+// CatchUpOptions bounds a catch-up pass.
+type CatchUpOptions struct {
+	MaxAge time.Duration    // ignore messages older than this (default 24h)
+	Now    func() time.Time // injectable for testing
+
+	// Threads lists thread timestamps the caller is tracking. Their replies
+	// are fetched even when the thread started before the MaxAge window.
+	Threads []string
+}
+
+// CatchUp fetches human messages posted to channel after lastSeenTS,
+// including new replies in threads that were active while offline. Results
+// are returned oldest first. An empty lastSeenTS returns nothing: without a
+// cursor there is no way to tell missed messages from old history.
+//
+// conversations.history lists only top-level messages, so a reply to a
+// thread started before the cursor is found through its parent: history is
+// scanned back to the MaxAge cutoff when that is older than the cursor, and
+// any parent whose latest reply is newer than the cursor has its replies
+// walked. Threads older than the window are covered by opts.Threads.
+func CatchUp(ctx context.Context, api HistoryFetcher, channel, lastSeenTS string, opts CatchUpOptions) (*CatchUpResult, error) {
+	res := &CatchUpResult{LatestTS: lastSeenTS}
+	if lastSeenTS == "" {
+		return res, nil
+	}
+
+	if opts.MaxAge == 0 {
+		opts.MaxAge = defaultCatchUpMaxAge
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	cutoff := opts.Now().Add(-opts.MaxAge)
+
+	// Reach back past the cursor to find parents of threads that started
+	// before it but may have new replies.
+	oldest := lastSeenTS
+	if cutoffTS := formatTS(cutoff); tsAfter(oldest, cutoffTS) {
+		oldest = cutoffTS
+	}
+
+	var raw []slack.Message
+	cursor := ""
+	for {
+		resp, err := api.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: channel,
+			Oldest:    oldest,
+			Cursor:    cursor,
+			Limit:     200,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch channel history: %w", err)
+		}
+		raw = append(raw, resp.Messages...)
+		if !resp.HasMore || resp.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		cursor = resp.ResponseMetaData.NextCursor
+	}
+
+	// History only returns top-level messages. Pull replies for any thread
+	// whose latest reply is newer than the cursor.
+	threads := make(map[string]bool)
+	for _, m := range raw {
+		if m.ReplyCount > 0 && tsAfter(m.LatestReply, lastSeenTS) {
+			threads[m.Timestamp] = true
+		}
+	}
+	for _, ts := range opts.Threads {
+		threads[ts] = true
+	}
+	for threadTS := range threads {
+		cursor := ""
+		for {
+			replies, hasMore, next, err := api.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+				ChannelID: channel,
+				Timestamp: threadTS,
+				Oldest:    lastSeenTS,
+				Cursor:    cursor,
+				Limit:     200,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("fetch thread %s replies: %w", threadTS, err)
+			}
+			for _, r := range replies {
+				if r.Timestamp != threadTS { // parent is already in raw
+					raw = append(raw, r)
+				}
+			}
+			if !hasMore || next == "" {
+				break
+			}
+			cursor = next
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range raw {
+		if seen[m.Timestamp] || !tsAfter(m.Timestamp, lastSeenTS) {
+			continue
+		}
+		seen[m.Timestamp] = true
+
+		if tsAfter(m.Timestamp, res.LatestTS) {
+			res.LatestTS = m.Timestamp
+		}
+		if m.BotID != "" || m.SubType != "" {
+			continue
+		}
+		if ParseTS(m.Timestamp).Before(cutoff) {
+			res.Skipped++
+			continue
+		}
+
+		threadTS := m.ThreadTimestamp
+		if threadTS == "" {
+			threadTS = m.Timestamp
+		}
+		res.Messages = append(res.Messages, MessageEvent{
+			EventID:   m.Timestamp,
+			ChannelID: channel,
+			ThreadTS:  threadTS,
+			MessageTS: m.Timestamp,
+			UserID:    m.User,
+			Text:      m.Text,
+		})
+	}
+
+	sort.Slice(res.Messages, func(i, j int) bool {
+		return tsAfter(res.Messages[j].MessageTS, res.Messages[i].MessageTS)
+	})
+	return res, nil
+}
+
+// CatchUp runs a catch-up pass against this client's Slack connection and
+// marks every returned message in the dedup set, so a live re-delivery of
+// the same message after reconnect is not processed twice.
+func (c *Client) CatchUp(ctx context.Context, channel, lastSeenTS string, opts CatchUpOptions) (*CatchUpResult, error) {
+	res, err := CatchUp(ctx, c.history, channel, lastSeenTS, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range res.Messages {
+		c.dedup.Check(MessageKey(m.ChannelID, m.MessageTS))
+	}
+	c.logger.Info("catch-up complete",
+		"channel", channel,
+		"missed", len(res.Messages),
+		"skipped_stale", res.Skipped,
+	)
+	return res, nil
+}
+
+// FormatCatchUpBatch consolidates missed messages for one thread into a
+// single prompt with an explicit offline preamble.
+func FormatCatchUpBatch(msgs []MessageEvent, offlineSince time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[Received while offline — %d message(s) since %s, oldest first. ",
+		len(msgs), offlineSince.UTC().Format(time.RFC3339)))
+	b.WriteString("Later messages may supersede earlier ones.]\n")
+	for _, m := range msgs {
+		b.WriteString(fmt.Sprintf("\n<@%s> (%s): %s", m.UserID, ParseTS(m.MessageTS).UTC().Format("15:04"), m.Text))
+	}
+	return b.String()
+}
+
+// GroupByThread splits catch-up messages by thread, preserving order.
+func GroupByThread(msgs []MessageEvent) (order []string, byThread map[string][]MessageEvent) {
+	byThread = make(map[string][]MessageEvent)
+	for _, m := range msgs {
+		if _, ok := byThread[m.ThreadTS]; !ok {
+			order = append(order, m.ThreadTS)
+		}
+		byThread[m.ThreadTS] = append(byThread[m.ThreadTS], m)
+	}
+	return order, byThread
+}
+
+// ParseTS converts a Slack timestamp ("1700000000.123456") to a time.
+// Invalid input returns the zero time.
+func ParseTS(ts string) time.Time {
+	sec, frac, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	var us int64
+	if frac != "" {
+		us, _ = strconv.ParseInt((frac + "000000")[:6], 10, 64)
+	}
+	return time.Unix(s, us*1000)
+}
+
+// formatTS is the inverse of ParseTS.
+func formatTS(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/1000)
+}
+
+// tsAfter reports whether Slack timestamp a is strictly after b.
+func tsAfter(a, b string) bool {
+	if b == "" {
+		return a != ""
+	}
+	return ParseTS(a).After(ParseTS(b))
+}
+
+// lastSeenFile is the on-disk cursor for catch-up, one per role and channel.
+type lastSeenFile struct {
+	TS string `json:"ts"`
+}
+
+// LastSeenPath returns the cursor path for a role in a repo.
+func LastSeenPath(repoDir, role, channel string) string {
+	return filepath.Join(repoDir, ".codebutler", "slack", fmt.Sprintf("%s-%s.lastseen.json", role, channel))
+}
+
+// LoadLastSeen reads the catch-up cursor. A missing file returns "".
+func LoadLastSeen(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read last-seen: %w", err)
+	}
+	var f lastSeenFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("parse last-seen: %w", err)
+	}
+	return f.TS, nil
+}
+
+// SaveLastSeen writes the catch-up cursor atomically.
+func SaveLastSeen(path, ts string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create last-seen dir: %w", err)
+	}
+	data, err := json.Marshal(lastSeenFile{TS: ts})
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write last-seen: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
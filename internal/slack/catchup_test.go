@@ -0,0 +1,263 @@
+package slack
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+type fakeHistory struct {
+	history []slack.Message
+	replies map[string][]slack.Message
+	pages   int
+	oldest  string
+}
+
+func (f *fakeHistory) GetConversationHistoryContext(_ context.Context, p *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	f.pages++
+	f.oldest = p.Oldest
+	resp := &slack.GetConversationHistoryResponse{}
+	// Serve one message per page to exercise pagination.
+	idx := 0
+	if p.Cursor != "" {
+		for i, m := range f.history {
+			if m.Timestamp == p.Cursor {
+				idx = i
+			}
+		}
+	}
+	if idx < len(f.history) {
+		resp.Messages = []slack.Message{f.history[idx]}
+	}
+	if idx+1 < len(f.history) {
+		resp.HasMore = true
+		resp.ResponseMetaData.NextCursor = f.history[idx+1].Timestamp
+	}
+	return resp, nil
+}
+
+func (f *fakeHistory) GetConversationRepliesContext(_ context.Context, p *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	return f.replies[p.Timestamp], false, "", nil
+}
+
+func msg(ts, user, text string) slack.Message {
+	var m slack.Message
+	m.Timestamp = ts
+	m.User = user
+	m.Text = text
+	return m
+}
+
+func TestCatchUp_OrderedAndFiltered(t *testing.T) {
+	now := time.Unix(1700100000, 0)
+
+	parent := msg("1700090000.000100", "U1", "deploy failing")
+	parent.ReplyCount = 1
+	parent.LatestReply = "1700095000.000100"
+
+	reply := msg("1700095000.000100", "U2", "still failing")
+	reply.ThreadTimestamp = parent.Timestamp
+
+	bot := msg("1700091000.000100", "", "bot output")
+	bot.BotID = "B1"
+
+	stale := msg("1600000000.000100", "U3", "ancient")
+
+	api := &fakeHistory{
+		// Slack returns newest first.
+		history: []slack.Message{msg("1700099000.000100", "U1", "are you there?"), bot, parent, stale},
+		replies: map[string][]slack.Message{parent.Timestamp: {parent, reply}},
+	}
+
+	res, err := CatchUp(context.Background(), api, "C1", "1500000000.000000", CatchUpOptions{
+		MaxAge: 24 * time.Hour,
+		Now:    func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d: %+v", len(res.Messages), res.Messages)
+	}
+	want := []string{"deploy failing", "still failing", "are you there?"}
+	for i, w := range want {
+		if res.Messages[i].Text != w {
+			t.Errorf("message %d = %q, want %q", i, res.Messages[i].Text, w)
+		}
+	}
+	if res.Messages[1].ThreadTS != parent.Timestamp {
+		t.Errorf("reply should keep its thread, got %s", res.Messages[1].ThreadTS)
+	}
+	if res.Skipped != 1 {
+		t.Errorf("expected 1 stale message skipped, got %d", res.Skipped)
+	}
+	if res.LatestTS != "1700099000.000100" {
+		t.Errorf("latest = %s", res.LatestTS)
+	}
+	if api.pages != 4 {
+		t.Errorf("expected 4 pages fetched, got %d", api.pages)
+	}
+}
+
+func TestCatchUp_ThreadStartedBeforeCursor(t *testing.T) {
+	now := time.Unix(1700100000, 0)
+	lastSeen := "1700099000.000000"
+
+	// Parent predates the cursor; the reply came in while offline.
+	parent := msg("1700080000.000100", "U1", "old question")
+	parent.ReplyCount = 2
+	parent.LatestReply = "1700099500.000100"
+	seenReply := msg("1700085000.000100", "U2", "answered before")
+	seenReply.ThreadTimestamp = parent.Timestamp
+	newReply := msg("1700099500.000100", "U1", "follow-up")
+	newReply.ThreadTimestamp = parent.Timestamp
+
+	// A thread older than MaxAge, known only to the caller.
+	tracked := "1690000000.000100"
+	trackedReply := msg("1700099600.000100", "U3", "ping on old thread")
+	trackedReply.ThreadTimestamp = tracked
+
+	api := &fakeHistory{
+		history: []slack.Message{parent},
+		replies: map[string][]slack.Message{
+			parent.Timestamp: {parent, seenReply, newReply},
+			tracked:          {trackedReply},
+		},
+	}
+	res, err := CatchUp(context.Background(), api, "C1", lastSeen, CatchUpOptions{
+		MaxAge:  time.Hour,
+		Now:     func() time.Time { return now },
+		Threads: []string{tracked},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.oldest != "1700096400.000000" {
+		t.Errorf("history should reach back to the cutoff, oldest = %s", api.oldest)
+	}
+	if len(res.Messages) != 2 {
+		t.Fatalf("expected 2 replies, got %+v", res.Messages)
+	}
+	if res.Messages[0].Text != "follow-up" || res.Messages[0].ThreadTS != parent.Timestamp {
+		t.Errorf("first = %+v", res.Messages[0])
+	}
+	if res.Messages[1].Text != "ping on old thread" || res.Messages[1].ThreadTS != tracked {
+		t.Errorf("second = %+v", res.Messages[1])
+	}
+	if res.LatestTS != trackedReply.Timestamp {
+		t.Errorf("latest = %s", res.LatestTS)
+	}
+}
+
+func TestClient_CatchUpThenLiveDeliversOnce(t *testing.T) {
+	c := NewClient("xoxb-test", "xapp-test", AgentIdentity{},
+		WithSlackLogger(quietLogger()),
+		WithUserDirectory(NewUserDirectory(&fakeUsers{}, WithUserLogger(quietLogger()))))
+	c.history = &fakeHistory{history: []slack.Message{msg("1700099500.000100", "U1", "missed")}}
+
+	var got []MessageEvent
+	c.OnMessage(func(evt MessageEvent) { got = append(got, evt) })
+
+	res, err := c.CatchUp(context.Background(), "C1", "1700099000.000000", CatchUpOptions{
+		Now: func() time.Time { return time.Unix(1700100000, 0) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Messages) != 1 {
+		t.Fatalf("catch-up returned %+v", res.Messages)
+	}
+
+	// The same message arrives live after reconnect, with an event ID
+	// catch-up never saw.
+	live := func(eventID, ts string) slackevents.EventsAPIEvent {
+		return slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			Data: &slackevents.EventsAPICallbackEvent{EventID: eventID},
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &slackevents.MessageEvent{Channel: "C1", User: "U1", TimeStamp: ts, Text: "missed"},
+			},
+		}
+	}
+	c.handleCallbackEvent(live("Ev1", "1700099500.000100"))
+	if len(got) != 0 {
+		t.Fatalf("live re-delivery of a caught-up message was dispatched: %+v", got)
+	}
+
+	// Same timestamp in another channel is a different message.
+	other := live("Ev2", "1700099500.000100")
+	other.InnerEvent.Data.(*slackevents.MessageEvent).Channel = "C2"
+	c.handleCallbackEvent(other)
+	if len(got) != 1 || got[0].ChannelID != "C2" {
+		t.Errorf("expected only the C2 message, got %+v", got)
+	}
+}
+
+func TestCatchUp_NoCursor(t *testing.T) {
+	api := &fakeHistory{history: []slack.Message{msg("1700000000.000100", "U1", "hi")}}
+	res, err := CatchUp(context.Background(), api, "C1", "", CatchUpOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Messages) != 0 || api.pages != 0 {
+		t.Error("catch-up without a cursor should not fetch history")
+	}
+}
+
+func TestFormatCatchUpBatch(t *testing.T) {
+	out := FormatCatchUpBatch([]MessageEvent{
+		{UserID: "U1", MessageTS: "1700000000.000100", Text: "first"},
+		{UserID: "U2", MessageTS: "1700000060.000100", Text: "second"},
+	}, time.Unix(1699990000, 0))
+
+	if !strings.HasPrefix(out, "[Received while offline — 2 message(s)") {
+		t.Errorf("missing preamble: %s", out)
+	}
+	if strings.Index(out, "first") > strings.Index(out, "second") {
+		t.Error("messages should be in order")
+	}
+}
+
+func TestGroupByThread(t *testing.T) {
+	order, by := GroupByThread([]MessageEvent{
+		{ThreadTS: "b", Text: "1"},
+		{ThreadTS: "a", Text: "2"},
+		{ThreadTS: "b", Text: "3"},
+	})
+	if len(order) != 2 || order[0] != "b" || len(by["b"]) != 2 {
+		t.Errorf("unexpected grouping: %v %v", order, by)
+	}
+}
+
+func TestParseTS(t *testing.T) {
+	got := ParseTS("1700000000.123456")
+	if got.Unix() != 1700000000 || got.Nanosecond() != 123456000 {
+		t.Errorf("got %v", got)
+	}
+	if !ParseTS("garbage").IsZero() {
+		t.Error("invalid ts should parse to zero time")
+	}
+}
+
+func TestLastSeen_RoundTrip(t *testing.T) {
+	path := LastSeenPath(t.TempDir(), "pm", "C1")
+	if ts, err := LoadLastSeen(path); err != nil || ts != "" {
+		t.Fatalf("missing file: %q %v", ts, err)
+	}
+	if err := SaveLastSeen(path, "1700000000.000100"); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := LoadLastSeen(path)
+	if err != nil || ts != "1700000000.000100" {
+		t.Errorf("got %q %v", ts, err)
+	}
+	if filepath.Base(path) != "pm-C1.lastseen.json" {
+		t.Errorf("unexpected path %s", path)
+	}
+}
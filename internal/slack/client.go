@@ -1,9 +1,11 @@
 package slack
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
+	"path"
 	"strings"
 
 	"github.com/slack-go/slack"
@@ -17,9 +19,11 @@ const codeSnippetThreshold = 20
 // Client wraps the Slack API and Socket Mode for agent communication.
 type Client struct {
 	api      *slack.Client
+	history  HistoryFetcher // c.api unless a test replaces it
 	socket   *socketmode.Client
 	identity AgentIdentity
 	dedup    *DedupSet
+	users    *UserDirectory
 	logger   *slog.Logger
 
 	// handler is called for each new message event that passes dedup.
@@ -54,6 +58,14 @@ func WithDedupSet(d *DedupSet) ClientOption {
 	}
 }
 
+// WithUserDirectory sets the directory used to resolve user names, e.g. one
+// with an on-disk cache.
+func WithUserDirectory(d *UserDirectory) ClientOption {
+	return func(c *Client) {
+		c.users = d
+	}
+}
+
 // NewClient creates a Slack client with Socket Mode support.
 // botToken is the xoxb-... token, appToken is the xapp-... token.
 func NewClient(botToken, appToken string, identity AgentIdentity, opts ...ClientOption) *Client {
@@ -66,6 +78,7 @@ func NewClient(botToken, appToken string, identity AgentIdentity, opts ...Client
 
 	c := &Client{
 		api:      api,
+		history:  api,
 		socket:   socket,
 		identity: identity,
 		dedup:    NewDedupSet(),
@@ -75,10 +88,18 @@ func NewClient(botToken, appToken string, identity AgentIdentity, opts ...Client
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.users == nil {
+		c.users = NewUserDirectory(api, WithUserLogger(c.logger))
+	}
 
 	return c
 }
 
+// Users returns the client's user directory.
+func (c *Client) Users() *UserDirectory {
+	return c.users
+}
+
 // OnMessage registers a handler for incoming message events.
 // The handler is called for each new, non-duplicate message.
 func (c *Client) OnMessage(handler func(evt MessageEvent)) {
@@ -159,8 +180,9 @@ func (c *Client) handleCallbackEvent(evt slackevents.EventsAPIEvent) {
 			eventID = ev.TimeStamp // fallback
 		}
 
-		// Dedup check
-		if !c.dedup.Check(eventID) {
+		// Dedup on the message, not the event: Slack retries reuse the
+		// event ID but catch-up has none, and both carry channel and ts.
+		if !c.dedup.Check(MessageKey(ev.Channel, ev.TimeStamp)) {
 			c.logger.Debug("duplicate event skipped", "event_id", eventID)
 			return
 		}
@@ -185,6 +207,7 @@ func (c *Client) handleCallbackEvent(evt slackevents.EventsAPIEvent) {
 			"channel", msgEvt.ChannelID,
 			"thread", msgEvt.ThreadTS,
 			"user", msgEvt.UserID,
+			"user_name", c.users.Name(context.Background(), msgEvt.UserID),
 		)
 
 		if c.handler != nil {
@@ -193,8 +216,21 @@ func (c *Client) handleCallbackEvent(evt slackevents.EventsAPIEvent) {
 	}
 }
 
+// MessageKey identifies a Slack message for dedup. A timestamp is only
+// unique within its channel.
+func MessageKey(channel, ts string) string {
+	return channel + "/" + ts
+}
+
 // SendMessage posts a message to a Slack channel/thread with the agent's identity.
 func (c *Client) SendMessage(ctx context.Context, channel, threadTS, text string) error {
+	_, err := c.PostMessage(ctx, channel, threadTS, text)
+	return err
+}
+
+// PostMessage is SendMessage that also returns the new message's timestamp,
+// for later edits with UpdateMessage.
+func (c *Client) PostMessage(ctx context.Context, channel, threadTS, text string) (string, error) {
 	opts := []slack.MsgOption{
 		slack.MsgOptionText(text, false),
 		slack.MsgOptionUsername(c.identity.DisplayName),
@@ -205,11 +241,20 @@ func (c *Client) SendMessage(ctx context.Context, channel, threadTS, text string
 		opts = append(opts, slack.MsgOptionTS(threadTS))
 	}
 
-	_, _, err := c.api.PostMessageContext(ctx, channel, opts...)
+	_, ts, err := c.api.PostMessageContext(ctx, channel, opts...)
 	if err != nil {
-		return fmt.Errorf("slack send message: %w", err)
+		return "", fmt.Errorf("slack send message: %w", err)
 	}
 
+	return ts, nil
+}
+
+// UpdateMessage replaces the text of a message the agent posted.
+func (c *Client) UpdateMessage(ctx context.Context, channel, messageTS, text string) error {
+	_, _, _, err := c.api.UpdateMessageContext(ctx, channel, messageTS, slack.MsgOptionText(text, false))
+	if err != nil {
+		return fmt.Errorf("slack update message: %w", err)
+	}
 	return nil
 }
 
@@ -224,21 +269,23 @@ func (c *Client) SendCodeSnippet(ctx context.Context, channel, threadTS, filenam
 		return c.SendMessage(ctx, channel, threadTS, text)
 	}
 
-	// File upload for longer snippets
+	// File upload for longer snippets, highlighted by the file's language
+	lang := extLanguages[strings.ToLower(path.Ext(filename))]
+	return c.uploadSnippet(ctx, channel, threadTS, filename, lang, content)
+}
+
+// UploadAudio posts an audio file (e.g. a spoken reply) to a thread.
+func (c *Client) UploadAudio(ctx context.Context, channel, threadTS, filename string, data []byte) error {
 	params := slack.FileUploadParameters{
-		Filename: filename,
-		Content:  content,
-		Channels: []string{channel},
+		Filename:        filename,
+		Reader:          bytes.NewReader(data),
+		Filetype:        "mp3",
+		Channels:        []string{channel},
+		ThreadTimestamp: threadTS,
 	}
-	if threadTS != "" {
-		params.ThreadTimestamp = threadTS
+	if _, err := c.api.UploadFileContext(ctx, params); err != nil {
+		return fmt.Errorf("slack audio upload: %w", err)
 	}
-
-	_, err := c.api.UploadFileContext(ctx, params)
-	if err != nil {
-		return fmt.Errorf("slack file upload: %w", err)
-	}
-
 	return nil
 }
 
@@ -0,0 +1,210 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// extLanguages maps file extensions to Slack snippet filetypes.
+var extLanguages = map[string]string{
+	".go": "go", ".py": "python", ".js": "javascript", ".jsx": "javascript", ".mjs": "javascript",
+	".ts": "typescript", ".tsx": "typescript", ".rb": "ruby", ".rs": "rust", ".java": "java",
+	".kt": "kotlin", ".swift": "swift", ".c": "c", ".h": "c", ".cpp": "cpp", ".cc": "cpp",
+	".cs": "csharp", ".php": "php", ".sh": "shell", ".bash": "shell", ".sql": "sql",
+	".yaml": "yaml", ".yml": "yaml", ".json": "json", ".toml": "toml", ".xml": "xml",
+	".html": "html", ".css": "css", ".md": "markdown", ".diff": "diff", ".patch": "diff",
+	".dockerfile": "dockerfile",
+}
+
+// langAliases normalizes fence info strings to Slack filetypes.
+var langAliases = map[string]string{
+	"golang": "go", "py": "python", "python3": "python", "js": "javascript", "node": "javascript",
+	"ts": "typescript", "rb": "ruby", "rs": "rust", "sh": "shell", "bash": "shell", "zsh": "shell",
+	"console": "shell", "yml": "yaml", "c++": "cpp", "cs": "csharp", "patch": "diff",
+	"md": "markdown", "plaintext": "text", "txt": "text",
+}
+
+// langExts is the default extension for generated snippet filenames.
+var langExts = map[string]string{
+	"go": ".go", "python": ".py", "javascript": ".js", "typescript": ".ts", "ruby": ".rb",
+	"rust": ".rs", "java": ".java", "kotlin": ".kt", "swift": ".swift", "c": ".c", "cpp": ".cpp",
+	"csharp": ".cs", "php": ".php", "shell": ".sh", "sql": ".sql", "yaml": ".yaml", "json": ".json",
+	"toml": ".toml", "xml": ".xml", "html": ".html", "css": ".css", "markdown": ".md", "diff": ".diff",
+	"dockerfile": ".dockerfile",
+}
+
+var (
+	fenceRe = regexp.MustCompile("^\\s*```\\s*(\\S*)(.*)$")
+	// pathRe finds file paths mentioned in prose ("in `internal/api/server.go`:").
+	pathRe = regexp.MustCompile("[\\w./-]*[\\w-]+\\.[A-Za-z]{1,10}\\b")
+)
+
+// CodeBlock is a fenced block lifted out of a message to post as a snippet.
+type CodeBlock struct {
+	Language string // Slack filetype, "text" when unknown
+	Filename string
+	Code     string
+}
+
+// ExtractSnippets lifts fenced code blocks of at least minLines lines out of
+// text. Each is replaced by a pointer to its snippet, so the remaining text
+// stays short and readable. Language comes from the fence, else the file
+// named just before the block, else the code itself; the filename is that
+// file's base name or snippet-N with the language's extension.
+func ExtractSnippets(text string, minLines int) (string, []CodeBlock) {
+	lines := strings.Split(text, "\n")
+	var (
+		out    []string
+		blocks []CodeBlock
+	)
+	for i := 0; i < len(lines); i++ {
+		m := fenceRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "```" {
+			end++
+		}
+		if end == len(lines) {
+			// Unterminated fence: leave the rest as is.
+			out = append(out, lines[i:]...)
+			break
+		}
+		body := lines[i+1 : end]
+		if len(body) < minLines {
+			out = append(out, lines[i:end+1]...)
+			i = end
+			continue
+		}
+
+		b := CodeBlock{Code: strings.Join(body, "\n")}
+		b.Language, b.Filename = inferSnippet(m[1]+m[2], mentionedPath(out), b.Code, len(blocks)+1)
+		blocks = append(blocks, b)
+		out = append(out, fmt.Sprintf("_(`%s` attached below)_", b.Filename))
+		i = end
+	}
+	return strings.Join(out, "\n"), blocks
+}
+
+// mentionedPath returns the last file path named in the two lines before a
+// block.
+func mentionedPath(before []string) string {
+	for i := len(before) - 1; i >= 0 && i >= len(before)-2; i-- {
+		if found := pathRe.FindAllString(before[i], -1); len(found) > 0 {
+			return found[len(found)-1]
+		}
+	}
+	return ""
+}
+
+func inferSnippet(info, mentioned, code string, n int) (lang, filename string) {
+	info = strings.TrimSpace(info)
+	fenceLang, fenceRest, _ := strings.Cut(info, " ")
+
+	// ```main.go or ```go title="main.go"
+	var named string
+	if ext := path.Ext(fenceLang); ext != "" && extLanguages[strings.ToLower(ext)] != "" {
+		named, fenceLang = fenceLang, ""
+	} else if t := pathRe.FindString(fenceRest); t != "" {
+		named = t
+	}
+
+	lang = normalizeLang(fenceLang)
+	if named == "" && mentioned != "" {
+		if ml := extLanguages[strings.ToLower(path.Ext(mentioned))]; ml != "" && (lang == "" || ml == lang) {
+			named = mentioned
+		}
+	}
+	if lang == "" && named != "" {
+		lang = extLanguages[strings.ToLower(path.Ext(named))]
+	}
+	if lang == "" {
+		lang = sniffLanguage(code)
+	}
+	if named != "" {
+		return lang, path.Base(named)
+	}
+	return lang, fmt.Sprintf("snippet-%d%s", n, orText(langExts[lang]))
+}
+
+func normalizeLang(s string) string {
+	s = strings.ToLower(s)
+	if a, ok := langAliases[s]; ok {
+		return a
+	}
+	return s
+}
+
+func orText(ext string) string {
+	if ext == "" {
+		return ".txt"
+	}
+	return ext
+}
+
+// sniffLanguage guesses from the first non-blank line for unlabeled blocks.
+func sniffLanguage(code string) string {
+	first := ""
+	for _, l := range strings.Split(code, "\n") {
+		if first = strings.TrimSpace(l); first != "" {
+			break
+		}
+	}
+	switch {
+	case strings.HasPrefix(first, "package "):
+		return "go"
+	case strings.HasPrefix(first, "diff --git"), strings.HasPrefix(first, "--- a/"):
+		return "diff"
+	case strings.HasPrefix(first, "#!") && strings.Contains(first, "sh"):
+		return "shell"
+	case strings.HasPrefix(first, "#!") && strings.Contains(first, "python"),
+		strings.HasPrefix(first, "def "), strings.HasPrefix(first, "from ") && strings.Contains(first, " import "):
+		return "python"
+	case strings.HasPrefix(first, "{"), strings.HasPrefix(first, "["):
+		return "json"
+	case strings.HasPrefix(first, "<"):
+		return "html"
+	}
+	return "text"
+}
+
+// SendResponse posts an agent response, uploading fenced code blocks of
+// codeSnippetThreshold lines or more as language-tagged snippets in the
+// same thread instead of inlining them, which also keeps long answers clear
+// of Slack's message length limit.
+func (c *Client) SendResponse(ctx context.Context, channel, threadTS, text string) error {
+	prose, blocks := ExtractSnippets(text, codeSnippetThreshold)
+	if strings.TrimSpace(prose) != "" {
+		if err := c.SendMessage(ctx, channel, threadTS, prose); err != nil {
+			return err
+		}
+	}
+	for _, b := range blocks {
+		if err := c.uploadSnippet(ctx, channel, threadTS, b.Filename, b.Language, b.Code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) uploadSnippet(ctx context.Context, channel, threadTS, filename, lang, content string) error {
+	params := slack.FileUploadParameters{
+		Filename:        filename,
+		Title:           filename,
+		Filetype:        lang,
+		Content:         content,
+		Channels:        []string{channel},
+		ThreadTimestamp: threadTS,
+	}
+	if _, err := c.api.UploadFileContext(ctx, params); err != nil {
+		return fmt.Errorf("slack file upload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"strings"
+	"testing"
+)
+
+func lines(n int, s string) string {
+	return strings.TrimSuffix(strings.Repeat(s+"\n", n), "\n")
+}
+
+func TestExtractSnippets(t *testing.T) {
+	text := "Here's the new handler in `internal/api/server.go`:\n" +
+		"```go\n" + lines(25, "x := 1") + "\n```\n" +
+		"And a short one:\n```\nfoo()\n```\n" +
+		"Config:\n```yaml title=\"deploy.yml\"\n" + lines(30, "a: b") + "\n```"
+
+	prose, blocks := ExtractSnippets(text, 20)
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(blocks))
+	}
+	if b := blocks[0]; b.Language != "go" || b.Filename != "server.go" || strings.Count(b.Code, "\n") != 24 {
+		t.Errorf("block 0 = %s %s (%d lines)", b.Language, b.Filename, strings.Count(b.Code, "\n")+1)
+	}
+	if b := blocks[1]; b.Language != "yaml" || b.Filename != "deploy.yml" {
+		t.Errorf("block 1 = %s %s", b.Language, b.Filename)
+	}
+	if !strings.Contains(prose, "```\nfoo()\n```") {
+		t.Errorf("short block should stay inline:\n%s", prose)
+	}
+	if !strings.Contains(prose, "_(`server.go` attached below)_") || strings.Contains(prose, "x := 1") {
+		t.Errorf("prose = %s", prose)
+	}
+}
+
+func TestExtractSnippetsInference(t *testing.T) {
+	cases := []struct {
+		name, text, lang, file string
+	}{
+		{"fence filename", "```main.py\n" + lines(20, "print(1)") + "\n```", "python", "main.py"},
+		{"alias", "```sh\n" + lines(20, "ls") + "\n```", "shell", "snippet-1.sh"},
+		{"mentioned file", "Update `web/app.tsx`:\n```\n" + lines(20, "<div/>") + "\n```", "typescript", "app.tsx"},
+		{"mismatched mention", "See README.md\n```go\n" + lines(20, "x") + "\n```", "go", "snippet-1.go"},
+		{"sniffed", "```\npackage main\n" + lines(20, "x") + "\n```", "go", "snippet-1.go"},
+		{"unknown", "```\n" + lines(20, "hello") + "\n```", "text", "snippet-1.txt"},
+	}
+	for _, c := range cases {
+		_, blocks := ExtractSnippets(c.text, 20)
+		if len(blocks) != 1 {
+			t.Errorf("%s: got %d blocks", c.name, len(blocks))
+			continue
+		}
+		if blocks[0].Language != c.lang || blocks[0].Filename != c.file {
+			t.Errorf("%s: got %s %s, want %s %s", c.name, blocks[0].Language, blocks[0].Filename, c.lang, c.file)
+		}
+	}
+}
+
+func TestExtractSnippetsUnterminated(t *testing.T) {
+	text := "start\n```go\n" + lines(30, "x")
+	prose, blocks := ExtractSnippets(text, 20)
+	if len(blocks) != 0 || prose != text {
+		t.Errorf("unterminated fence changed: %d blocks", len(blocks))
+	}
+}
@@ -0,0 +1,138 @@
+package slack
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStreamInterval is the minimum time between edits of a live
+	// message. Slack rate-limits chat.update to roughly one call per second
+	// per channel, so stay well above that.
+	DefaultStreamInterval = 3 * time.Second
+
+	// maxStreamChars keeps a live message under Slack's text limit; older
+	// progress is dropped from the top.
+	maxStreamChars = 3500
+)
+
+// MessageEditor posts messages and edits them in place. *Client implements it.
+type MessageEditor interface {
+	PostMessage(ctx context.Context, channel, threadTS, text string) (string, error)
+	UpdateMessage(ctx context.Context, channel, messageTS, text string) error
+}
+
+// Streamer shows an agent's progress as a single thread message that is
+// edited as partial output arrives, at most once per interval. It
+// implements agent.PartialSink. Thread-safe.
+type Streamer struct {
+	editor   MessageEditor
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu   sync.Mutex
+	live map[string]*liveMessage // keyed by channel/thread
+}
+
+type liveMessage struct {
+	ts        string // empty until the first post succeeds
+	text      string
+	lastFlush time.Time
+}
+
+// StreamerOption configures a Streamer.
+type StreamerOption func(*Streamer)
+
+// WithStreamInterval overrides DefaultStreamInterval.
+func WithStreamInterval(d time.Duration) StreamerOption {
+	return func(s *Streamer) {
+		if d > 0 {
+			s.interval = d
+		}
+	}
+}
+
+// WithStreamLogger sets the structured logger.
+func WithStreamLogger(l *slog.Logger) StreamerOption {
+	return func(s *Streamer) {
+		s.logger = l
+	}
+}
+
+// NewStreamer creates a streamer that posts through editor.
+func NewStreamer(editor MessageEditor, opts ...StreamerOption) *Streamer {
+	s := &Streamer{
+		editor:   editor,
+		interval: DefaultStreamInterval,
+		logger:   slog.Default(),
+		now:      time.Now,
+		live:     make(map[string]*liveMessage),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Partial appends text to the thread's live message. The first call posts
+// it; later calls edit it once the interval has passed since the last edit.
+// Errors are logged, never returned: progress is best-effort.
+func (s *Streamer) Partial(ctx context.Context, channel, thread, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.live[channel+"/"+thread]
+	if m == nil {
+		m = &liveMessage{}
+		s.live[channel+"/"+thread] = m
+	}
+	if m.text != "" {
+		m.text += "\n"
+	}
+	m.text = tail(m.text+text, maxStreamChars)
+
+	if m.ts != "" && s.now().Sub(m.lastFlush) < s.interval {
+		return
+	}
+	s.flush(ctx, channel, thread, m)
+}
+
+// Finish replaces the live message with the final reply, or posts the reply
+// if nothing was streamed, and forgets the thread.
+func (s *Streamer) Finish(ctx context.Context, channel, thread, final string) error {
+	s.mu.Lock()
+	m := s.live[channel+"/"+thread]
+	delete(s.live, channel+"/"+thread)
+	s.mu.Unlock()
+
+	if m == nil || m.ts == "" {
+		_, err := s.editor.PostMessage(ctx, channel, thread, final)
+		return err
+	}
+	return s.editor.UpdateMessage(ctx, channel, m.ts, final)
+}
+
+// flush sends the live message's text. Callers hold s.mu.
+func (s *Streamer) flush(ctx context.Context, channel, thread string, m *liveMessage) {
+	var err error
+	if m.ts == "" {
+		m.ts, err = s.editor.PostMessage(ctx, channel, thread, m.text)
+	} else {
+		err = s.editor.UpdateMessage(ctx, channel, m.ts, m.text)
+	}
+	if err != nil {
+		s.logger.Warn("stream update failed", "channel", channel, "thread", thread, "err", err)
+		return
+	}
+	m.lastFlush = s.now()
+}
+
+func tail(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return "…" + string(r[len(r)-max:])
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type editCall struct {
+	op, ts, text string
+}
+
+type mockEditor struct {
+	calls []editCall
+}
+
+func (m *mockEditor) PostMessage(_ context.Context, _, _, text string) (string, error) {
+	m.calls = append(m.calls, editCall{op: "post", text: text})
+	return "1700.1", nil
+}
+
+func (m *mockEditor) UpdateMessage(_ context.Context, _, ts, text string) error {
+	m.calls = append(m.calls, editCall{op: "update", ts: ts, text: text})
+	return nil
+}
+
+func TestStreamer_ThrottlesEdits(t *testing.T) {
+	ed := &mockEditor{}
+	s := NewStreamer(ed, WithStreamInterval(3*time.Second), WithStreamLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	s.Partial(ctx, "C1", "100.1", "reading files")
+	clock = clock.Add(time.Second)
+	s.Partial(ctx, "C1", "100.1", "running tests")
+	if len(ed.calls) != 1 || ed.calls[0].op != "post" {
+		t.Fatalf("expected one post before the interval, got %+v", ed.calls)
+	}
+
+	clock = clock.Add(3 * time.Second)
+	s.Partial(ctx, "C1", "100.1", "editing login.go")
+	if len(ed.calls) != 2 || ed.calls[1].op != "update" || ed.calls[1].ts != "1700.1" {
+		t.Fatalf("expected an edit after the interval, got %+v", ed.calls)
+	}
+	if ed.calls[1].text != "reading files\nrunning tests\nediting login.go" {
+		t.Errorf("edit text = %q", ed.calls[1].text)
+	}
+
+	if err := s.Finish(ctx, "C1", "100.1", "Done: PR #42"); err != nil {
+		t.Fatal(err)
+	}
+	last := ed.calls[len(ed.calls)-1]
+	if last.op != "update" || last.text != "Done: PR #42" {
+		t.Errorf("finish should replace the live message, got %+v", last)
+	}
+}
+
+func TestStreamer_FinishWithoutPartialPosts(t *testing.T) {
+	ed := &mockEditor{}
+	s := NewStreamer(ed)
+	if err := s.Finish(context.Background(), "C1", "100.1", "quick answer"); err != nil {
+		t.Fatal(err)
+	}
+	if len(ed.calls) != 1 || ed.calls[0].op != "post" {
+		t.Errorf("calls = %+v", ed.calls)
+	}
+}
+
+func TestStreamer_TruncatesOldProgress(t *testing.T) {
+	ed := &mockEditor{}
+	s := NewStreamer(ed)
+	s.Partial(context.Background(), "C1", "1", strings.Repeat("x", maxStreamChars+100))
+	if got := ed.calls[0].text; !strings.HasPrefix(got, "…") || len([]rune(got)) != maxStreamChars+1 {
+		t.Errorf("len = %d", len([]rune(got)))
+	}
+}
@@ -0,0 +1,205 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultUserTTL is how long a resolved name is trusted before it is fetched
+// again; people rarely rename themselves.
+const defaultUserTTL = 24 * time.Hour
+
+// UserInfoFetcher is the subset of the Slack API used to resolve names.
+type UserInfoFetcher interface {
+	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
+}
+
+// userEntry is one cached name.
+type userEntry struct {
+	Name      string    `json:"name"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// UserDirectory resolves Slack user IDs to the names people go by, so logs,
+// prompts, and audit entries read "Maria" instead of "U04ABCDEF". Names are
+// cached in memory and, with a path, on disk across restarts.
+type UserDirectory struct {
+	api    UserInfoFetcher
+	path   string // optional JSON cache file
+	ttl    time.Duration
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	entries map[string]userEntry
+	loaded  bool
+}
+
+// UserDirectoryOption configures a UserDirectory.
+type UserDirectoryOption func(*UserDirectory)
+
+// WithUserCachePath persists resolved names to a JSON file.
+func WithUserCachePath(path string) UserDirectoryOption {
+	return func(d *UserDirectory) {
+		d.path = path
+	}
+}
+
+// WithUserTTL sets how long a cached name is used before refreshing it.
+func WithUserTTL(ttl time.Duration) UserDirectoryOption {
+	return func(d *UserDirectory) {
+		d.ttl = ttl
+	}
+}
+
+// WithUserClock sets a custom time function (for testing).
+func WithUserClock(fn func() time.Time) UserDirectoryOption {
+	return func(d *UserDirectory) {
+		d.now = fn
+	}
+}
+
+// WithUserLogger sets the structured logger.
+func WithUserLogger(l *slog.Logger) UserDirectoryOption {
+	return func(d *UserDirectory) {
+		d.logger = l
+	}
+}
+
+// NewUserDirectory creates a directory backed by api.
+func NewUserDirectory(api UserInfoFetcher, opts ...UserDirectoryOption) *UserDirectory {
+	d := &UserDirectory{
+		api:     api,
+		ttl:     defaultUserTTL,
+		now:     time.Now,
+		logger:  slog.Default(),
+		entries: make(map[string]userEntry),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// UserCachePath returns the default cache file inside a repo's .codebutler
+// directory.
+func UserCachePath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "slack", "users.json")
+}
+
+// Name returns the display name for userID. On lookup failure it returns a
+// stale cached name if there is one, otherwise the ID itself, so callers can
+// always use the result.
+func (d *UserDirectory) Name(ctx context.Context, userID string) string {
+	if userID == "" {
+		return ""
+	}
+
+	d.mu.Lock()
+	d.loadLocked()
+	e, ok := d.entries[userID]
+	d.mu.Unlock()
+	if ok && d.now().Sub(e.FetchedAt) < d.ttl {
+		return e.Name
+	}
+
+	u, err := d.api.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		d.logger.Warn("slack user lookup failed", "user", userID, "err", err)
+		if ok {
+			return e.Name
+		}
+		return userID
+	}
+
+	name := PreferredName(u)
+	if name == "" {
+		name = userID
+	}
+	d.mu.Lock()
+	d.entries[userID] = userEntry{Name: name, FetchedAt: d.now()}
+	if err := d.saveLocked(); err != nil {
+		d.logger.Warn("failed to save slack user cache", "path", d.path, "err", err)
+	}
+	d.mu.Unlock()
+	return name
+}
+
+// Label returns "Name (<@ID>)" for prompts: readable, and still usable as a
+// mention.
+func (d *UserDirectory) Label(ctx context.Context, userID string) string {
+	name := d.Name(ctx, userID)
+	if name == "" || name == userID {
+		return fmt.Sprintf("<@%s>", userID)
+	}
+	return fmt.Sprintf("%s (<@%s>)", name, userID)
+}
+
+// PreferredName picks the name a person chose to be shown: the profile
+// display name, then the real name, then the account handle.
+func PreferredName(u *slack.User) string {
+	switch {
+	case u == nil:
+		return ""
+	case u.Profile.DisplayName != "":
+		return u.Profile.DisplayName
+	case u.Profile.RealName != "":
+		return u.Profile.RealName
+	case u.RealName != "":
+		return u.RealName
+	}
+	return u.Name
+}
+
+func (d *UserDirectory) loadLocked() {
+	if d.loaded || d.path == "" {
+		d.loaded = true
+		return
+	}
+	d.loaded = true
+
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			d.logger.Warn("failed to read slack user cache", "path", d.path, "err", err)
+		}
+		return
+	}
+	var entries map[string]userEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		d.logger.Warn("ignoring corrupt slack user cache", "path", d.path, "err", err)
+		return
+	}
+	for id, e := range entries {
+		if _, ok := d.entries[id]; !ok {
+			d.entries[id] = e
+		}
+	}
+}
+
+// saveLocked writes the cache atomically (temp file + rename).
+func (d *UserDirectory) saveLocked() error {
+	if d.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := d.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.path)
+}
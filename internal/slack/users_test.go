@@ -0,0 +1,115 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+type fakeUsers struct {
+	users map[string]*slack.User
+	calls int
+	err   error
+}
+
+func (f *fakeUsers) GetUserInfoContext(_ context.Context, id string) (*slack.User, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	u, ok := f.users[id]
+	if !ok {
+		return nil, errors.New("user_not_found")
+	}
+	return u, nil
+}
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestPreferredName(t *testing.T) {
+	tests := []struct {
+		user *slack.User
+		want string
+	}{
+		{&slack.User{Name: "maria.g", RealName: "Maria Garcia", Profile: slack.UserProfile{DisplayName: "Maria"}}, "Maria"},
+		{&slack.User{Name: "maria.g", Profile: slack.UserProfile{RealName: "Maria Garcia"}}, "Maria Garcia"},
+		{&slack.User{Name: "maria.g"}, "maria.g"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := PreferredName(tt.user); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestUserDirectory_CachesAndRefreshes(t *testing.T) {
+	api := &fakeUsers{users: map[string]*slack.User{
+		"U1": {Profile: slack.UserProfile{DisplayName: "Maria"}},
+	}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewUserDirectory(api, WithUserTTL(time.Hour), WithUserClock(func() time.Time { return now }))
+
+	for i := 0; i < 3; i++ {
+		if got := d.Name(context.Background(), "U1"); got != "Maria" {
+			t.Fatalf("got %q", got)
+		}
+	}
+	if api.calls != 1 {
+		t.Errorf("expected 1 lookup, got %d", api.calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	api.users["U1"].Profile.DisplayName = "Mari"
+	if got := d.Name(context.Background(), "U1"); got != "Mari" || api.calls != 2 {
+		t.Errorf("expected refresh, got %q after %d calls", got, api.calls)
+	}
+}
+
+func TestUserDirectory_Fallbacks(t *testing.T) {
+	api := &fakeUsers{users: map[string]*slack.User{"U1": {Name: "maria"}}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewUserDirectory(api, WithUserTTL(time.Minute), WithUserLogger(quietLogger()),
+		WithUserClock(func() time.Time { return now }))
+
+	if got := d.Name(context.Background(), "U9"); got != "U9" {
+		t.Errorf("unknown user should fall back to the ID, got %q", got)
+	}
+	d.Name(context.Background(), "U1")
+
+	now = now.Add(time.Hour)
+	api.err = errors.New("ratelimited")
+	if got := d.Name(context.Background(), "U1"); got != "maria" {
+		t.Errorf("expected stale name on error, got %q", got)
+	}
+	if got := d.Label(context.Background(), "U9"); got != "<@U9>" {
+		t.Errorf("label = %q", got)
+	}
+	if got := d.Label(context.Background(), "U1"); got != "maria (<@U1>)" {
+		t.Errorf("label = %q", got)
+	}
+}
+
+func TestUserDirectory_PersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slack", "users.json")
+	api := &fakeUsers{users: map[string]*slack.User{"U1": {RealName: "Leandro"}}}
+
+	NewUserDirectory(api, WithUserCachePath(path)).Name(context.Background(), "U1")
+
+	offline := &fakeUsers{err: errors.New("offline")}
+	d := NewUserDirectory(offline, WithUserCachePath(path), WithUserLogger(quietLogger()))
+	if got := d.Name(context.Background(), "U1"); got != "Leandro" {
+		t.Errorf("got %q", got)
+	}
+	if offline.calls != 0 {
+		t.Errorf("fresh cached name should not be refetched, got %d calls", offline.calls)
+	}
+}
@@ -0,0 +1,5 @@
+// Package sqldb adapts database/sql connections to the SQLQuery tool. Each
+// query runs in a read-only transaction with a timeout and row limit, on
+// top of the tool's own SELECT-only validation. Drivers are not bundled:
+// the binary registers the ones it needs with a blank import.
+package sqldb
@@ -0,0 +1,122 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/tools"
+)
+
+const (
+	defaultMaxRows = 200
+	defaultTimeout = 10 * time.Second
+)
+
+// DB runs read-only queries against a database/sql connection.
+type DB struct {
+	db      *sql.DB
+	maxRows int
+	timeout time.Duration
+}
+
+// New wraps an open *sql.DB. Zero maxRows or timeout use the defaults.
+func New(db *sql.DB, maxRows int, timeout time.Duration) *DB {
+	if maxRows <= 0 {
+		maxRows = defaultMaxRows
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &DB{db: db, maxRows: maxRows, timeout: timeout}
+}
+
+// QueryReadOnly implements tools.SQLDatabase. The transaction is always
+// rolled back, so even a statement that slipped past validation cannot
+// persist changes on drivers that honor read-only transactions.
+func (d *DB) QueryReadOnly(ctx context.Context, query string) (*tools.SQLResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("begin read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &tools.SQLResult{Columns: cols}
+	for rows.Next() {
+		if len(res.Rows) == d.maxRows {
+			res.Truncated = true
+			break
+		}
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(cols))
+		for i, v := range values {
+			row[i] = formatValue(v)
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// OpenForRole opens the configured databases the given role may query.
+// Databases whose driver is not compiled in are reported in the error but
+// do not prevent the others from opening.
+func OpenForRole(cfgs map[string]config.DatabaseConfig, role string) (map[string]tools.SQLDatabase, error) {
+	out := make(map[string]tools.SQLDatabase)
+	var errs []error
+	for name, cfg := range cfgs {
+		if !slices.Contains(cfg.Roles, role) {
+			continue
+		}
+		db, err := sql.Open(cfg.Driver, cfg.DSN)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("database %q: %w", name, err))
+			continue
+		}
+		out[name] = New(db, cfg.MaxRows, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	}
+	if len(errs) > 0 {
+		return out, errors.Join(errs...)
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// fakeDriver serves a fixed result set and records transaction options.
+type fakeDriver struct {
+	rows       [][]driver.Value
+	readOnly   bool
+	rolledBack bool
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return &fakeTx{d: c.d}, nil }
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.d.readOnly = opts.ReadOnly
+	return &fakeTx{d: c.d}, nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{rows: c.d.rows}, nil
+}
+
+type fakeTx struct{ d *fakeDriver }
+
+func (t *fakeTx) Commit() error   { return nil }
+func (t *fakeTx) Rollback() error { t.d.rolledBack = true; return nil }
+
+type fakeRows struct {
+	rows [][]driver.Value
+	pos  int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"day", "signups"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() { sql.Register("sqldb-fake", fake) }
+
+func TestQueryReadOnly(t *testing.T) {
+	fake.rows = [][]driver.Value{
+		{[]byte("2026-02-28"), int64(42)},
+		{[]byte("2026-03-01"), nil},
+		{[]byte("2026-03-02"), int64(7)},
+	}
+	db, err := sql.Open("sqldb-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	res, err := New(db, 2, 0).QueryReadOnly(context.Background(), "SELECT day, signups FROM daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fake.readOnly || !fake.rolledBack {
+		t.Errorf("expected a rolled-back read-only transaction (readOnly=%v rolledBack=%v)", fake.readOnly, fake.rolledBack)
+	}
+	if len(res.Rows) != 2 || !res.Truncated {
+		t.Errorf("expected 2 rows with truncation, got %d truncated=%v", len(res.Rows), res.Truncated)
+	}
+	if res.Rows[0][1] != "42" || res.Rows[1][1] != "NULL" {
+		t.Errorf("unexpected rows: %v", res.Rows)
+	}
+}
+
+func TestOpenForRole(t *testing.T) {
+	cfgs := map[string]config.DatabaseConfig{
+		"analytics": {Driver: "sqldb-fake", Roles: []string{"pm", "researcher"}},
+		"billing":   {Driver: "sqldb-fake", Roles: []string{"lead"}},
+		"legacy":    {Driver: "not-compiled-in", Roles: []string{"pm"}},
+	}
+
+	dbs, err := OpenForRole(cfgs, "pm")
+	if err == nil || !strings.Contains(err.Error(), "legacy") {
+		t.Errorf("expected error naming the unknown driver, got %v", err)
+	}
+	if len(dbs) != 1 || dbs["analytics"] == nil {
+		t.Errorf("pm should only get analytics, got %v", dbs)
+	}
+
+	dbs, err = OpenForRole(cfgs, "coder")
+	if err != nil || len(dbs) != 0 {
+		t.Errorf("coder should get no databases, got %v (%v)", dbs, err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package standup implements /standup: a short stand-up style update on the
+// last 24 hours of repo activity — commits, merged PRs, CodeButler tasks and
+// failing CI runs — written by a cheap model from git log, gh and the task
+// store, with a plain listing as the fallback.
+package standup
@@ -0,0 +1,262 @@
+package standup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/github"
+	"github.com/leandrotocalini/codebutler/internal/taskqueue"
+)
+
+// DefaultWindow is how far back /standup looks.
+const DefaultWindow = 24 * time.Hour
+
+// maxItems caps each section in the prompt and the fallback listing.
+const maxItems = 25
+
+const summaryPrompt = "Write a stand-up update from this repo activity for the team channel. " +
+	"Use three short bulleted sections: *Shipped* (merged PRs and notable commits), " +
+	"*In progress* (running or pending tasks), *Needs attention* (failing CI, failed tasks). " +
+	"Group related commits, name people when given, omit empty sections, stay under 10 bullets. " +
+	"Do not invent anything not in the activity.\n\n"
+
+// CommitSource lists recent commits (github.GitOps).
+type CommitSource interface {
+	CommitsSince(ctx context.Context, t time.Time) ([]github.Commit, error)
+}
+
+// GitHubSource lists merged PRs and failed CI runs (github.GHOps).
+type GitHubSource interface {
+	MergedPRsSince(ctx context.Context, t time.Time) ([]github.MergedPR, error)
+	FailedRunsSince(ctx context.Context, t time.Time) ([]github.WorkflowRun, error)
+}
+
+// TaskSource lists CodeButler tasks (taskqueue.Queue).
+type TaskSource interface {
+	Since(t time.Time) []taskqueue.Task
+}
+
+// Activity is everything that happened in the window.
+type Activity struct {
+	Since      time.Time
+	Commits    []github.Commit
+	MergedPRs  []github.MergedPR
+	FailedRuns []github.WorkflowRun
+	Tasks      []taskqueue.Task
+	Errors     []string // sources that could not be read
+}
+
+// Empty reports whether nothing happened.
+func (a Activity) Empty() bool {
+	return len(a.Commits) == 0 && len(a.MergedPRs) == 0 && len(a.FailedRuns) == 0 && len(a.Tasks) == 0
+}
+
+// Reporter gathers activity and writes the update.
+type Reporter struct {
+	provider agent.LLMProvider // optional; nil posts the plain listing
+	model    string
+	commits  CommitSource
+	github   GitHubSource
+	tasks    TaskSource
+	window   time.Duration
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// Option configures a Reporter.
+type Option func(*Reporter)
+
+// WithCommits adds git log as a source.
+func WithCommits(s CommitSource) Option {
+	return func(r *Reporter) {
+		r.commits = s
+	}
+}
+
+// WithGitHub adds merged PRs and failed CI runs as sources.
+func WithGitHub(s GitHubSource) Option {
+	return func(r *Reporter) {
+		r.github = s
+	}
+}
+
+// WithTasks adds the task store as a source.
+func WithTasks(s TaskSource) Option {
+	return func(r *Reporter) {
+		r.tasks = s
+	}
+}
+
+// WithWindow overrides DefaultWindow.
+func WithWindow(d time.Duration) Option {
+	return func(r *Reporter) {
+		if d > 0 {
+			r.window = d
+		}
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(r *Reporter) {
+		r.logger = l
+	}
+}
+
+// New creates a reporter that writes updates with model via provider.
+func New(provider agent.LLMProvider, model string, opts ...Option) *Reporter {
+	r := &Reporter{
+		provider: provider,
+		model:    model,
+		window:   DefaultWindow,
+		now:      time.Now,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Gather reads every configured source for the window ending now. A failing
+// source is noted in Errors rather than failing the whole update.
+func (r *Reporter) Gather(ctx context.Context, window time.Duration) Activity {
+	a := Activity{Since: r.now().Add(-window)}
+	note := func(source string, err error) {
+		r.logger.Warn("standup source failed", "source", source, "err", err)
+		a.Errors = append(a.Errors, source)
+	}
+	if r.commits != nil {
+		commits, err := r.commits.CommitsSince(ctx, a.Since)
+		if err != nil {
+			note("git log", err)
+		}
+		a.Commits = commits
+	}
+	if r.github != nil {
+		prs, err := r.github.MergedPRsSince(ctx, a.Since)
+		if err != nil {
+			note("merged PRs", err)
+		}
+		a.MergedPRs = prs
+		runs, err := r.github.FailedRunsSince(ctx, a.Since)
+		if err != nil {
+			note("CI runs", err)
+		}
+		a.FailedRuns = runs
+	}
+	if r.tasks != nil {
+		a.Tasks = r.tasks.Since(a.Since)
+	}
+	return a
+}
+
+// Standup returns the update for the window. If the model call fails, the
+// plain listing is returned instead.
+func (r *Reporter) Standup(ctx context.Context, window time.Duration) (string, error) {
+	a := r.Gather(ctx, window)
+	if a.Empty() {
+		return fmt.Sprintf("No repo activity in the last %s.", formatWindow(window)) + sourceNote(a), nil
+	}
+	listing := FormatActivity(a)
+	if r.provider == nil {
+		return listing, nil
+	}
+
+	resp, err := r.provider.ChatCompletion(ctx, agent.ChatRequest{
+		Model:    r.model,
+		Messages: []agent.Message{{Role: "user", Content: summaryPrompt + listing}},
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		r.logger.Warn("standup model call failed, using plain listing", "err", err)
+		return listing, nil
+	}
+	update := strings.TrimSpace(resp.Message.Content)
+	if update == "" {
+		return listing, nil
+	}
+	return fmt.Sprintf("*Stand-up — last %s*\n%s", formatWindow(window), update) + sourceNote(a), nil
+}
+
+// FormatActivity lists the window's activity by source.
+func FormatActivity(a Activity) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Repo activity since %s*\n", a.Since.UTC().Format("Jan 2 15:04 MST"))
+
+	if len(a.MergedPRs) > 0 {
+		b.WriteString("*Merged PRs*\n")
+		for _, pr := range head(a.MergedPRs) {
+			fmt.Fprintf(&b, "• #%d %s (%s)\n", pr.Number, pr.Title, pr.Author.Login)
+		}
+	}
+	if len(a.Commits) > 0 {
+		fmt.Fprintf(&b, "*Commits* (%d)\n", len(a.Commits))
+		for _, c := range head(a.Commits) {
+			fmt.Fprintf(&b, "• `%s` %s — %s\n", c.Hash, c.Subject, c.Author)
+		}
+	}
+	if len(a.Tasks) > 0 {
+		b.WriteString("*CodeButler tasks*\n")
+		for _, t := range head(a.Tasks) {
+			fmt.Fprintf(&b, "• %s [%s] %s\n", t.ID, t.Status, t.Summary)
+		}
+	}
+	if len(a.FailedRuns) > 0 {
+		b.WriteString("*Failing CI*\n")
+		for _, run := range head(a.FailedRuns) {
+			fmt.Fprintf(&b, "• %s on `%s` %s\n", run.Name, run.Branch, run.URL)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + sourceNote(a)
+}
+
+func head[T any](items []T) []T {
+	if len(items) > maxItems {
+		return items[:maxItems]
+	}
+	return items
+}
+
+func sourceNote(a Activity) string {
+	if len(a.Errors) == 0 {
+		return ""
+	}
+	return "\n_Could not read: " + strings.Join(a.Errors, ", ") + "._"
+}
+
+func formatWindow(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return d.String()
+}
+
+// Command returns /standup [hours].
+func Command(r *Reporter) *chatcmd.Command {
+	const usage = "/standup [hours]"
+	return &chatcmd.Command{
+		Name:        "standup",
+		Usage:       usage,
+		Description: "Summarize recent commits, merged PRs, tasks and failing CI as a stand-up update",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			window := r.window
+			if arg := strings.TrimSpace(inv.RawArgs); arg != "" {
+				hours, err := strconv.Atoi(strings.TrimSuffix(arg, "h"))
+				if err != nil || hours <= 0 {
+					return "Usage: " + usage, nil
+				}
+				window = time.Duration(hours) * time.Hour
+			}
+			return r.Standup(ctx, window)
+		},
+	}
+}
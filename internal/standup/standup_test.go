@@ -0,0 +1,173 @@
+package standup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/github"
+	"github.com/leandrotocalini/codebutler/internal/taskqueue"
+)
+
+type mockProvider struct {
+	reply  string
+	err    error
+	prompt string
+	model  string
+}
+
+func (m *mockProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	m.prompt = req.Messages[0].Content
+	m.model = req.Model
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &agent.ChatResponse{Message: agent.Message{Role: "assistant", Content: m.reply}}, nil
+}
+
+type mockCommits struct {
+	commits []github.Commit
+	err     error
+	since   time.Time
+}
+
+func (m *mockCommits) CommitsSince(_ context.Context, t time.Time) ([]github.Commit, error) {
+	m.since = t
+	return m.commits, m.err
+}
+
+type mockGitHub struct {
+	prs    []github.MergedPR
+	runs   []github.WorkflowRun
+	prErr  error
+	runErr error
+}
+
+func (m *mockGitHub) MergedPRsSince(context.Context, time.Time) ([]github.MergedPR, error) {
+	return m.prs, m.prErr
+}
+
+func (m *mockGitHub) FailedRunsSince(context.Context, time.Time) ([]github.WorkflowRun, error) {
+	return m.runs, m.runErr
+}
+
+type mockTasks struct {
+	tasks []taskqueue.Task
+}
+
+func (m *mockTasks) Since(time.Time) []taskqueue.Task {
+	return m.tasks
+}
+
+var now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func newTestReporter(p agent.LLMProvider, opts ...Option) *Reporter {
+	opts = append(opts, WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	r := New(p, "cheap-model", opts...)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func sampleSources() (*mockCommits, *mockGitHub, *mockTasks) {
+	commits := &mockCommits{commits: []github.Commit{
+		{Hash: "abc1234", Author: "Maria", Subject: "Fix checkout crash"},
+	}}
+	pr := github.MergedPR{Number: 12, Title: "Fix checkout crash"}
+	pr.Author.Login = "maria"
+	gh := &mockGitHub{
+		prs:  []github.MergedPR{pr},
+		runs: []github.WorkflowRun{{Name: "CI", Branch: "main", URL: "https://ci/1"}},
+	}
+	tasks := &mockTasks{tasks: []taskqueue.Task{
+		{ID: "t1", Status: taskqueue.StatusRunning, Summary: "add regression test"},
+	}}
+	return commits, gh, tasks
+}
+
+func TestStandup_UsesModel(t *testing.T) {
+	commits, gh, tasks := sampleSources()
+	p := &mockProvider{reply: "• Shipped #12"}
+	r := newTestReporter(p, WithCommits(commits), WithGitHub(gh), WithTasks(tasks))
+
+	out, err := r.Standup(context.Background(), DefaultWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Shipped #12") || !strings.Contains(out, "last 24h") {
+		t.Errorf("unexpected update: %q", out)
+	}
+	if p.model != "cheap-model" {
+		t.Errorf("model = %q", p.model)
+	}
+	for _, want := range []string{"#12 Fix checkout crash", "abc1234", "add regression test", "CI on `main`"} {
+		if !strings.Contains(p.prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if !commits.since.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("since = %v", commits.since)
+	}
+}
+
+func TestStandup_FallbackOnModelError(t *testing.T) {
+	commits, gh, tasks := sampleSources()
+	r := newTestReporter(&mockProvider{err: errors.New("boom")}, WithCommits(commits), WithGitHub(gh), WithTasks(tasks))
+
+	out, err := r.Standup(context.Background(), DefaultWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "*Merged PRs*") || !strings.Contains(out, "*Failing CI*") {
+		t.Errorf("expected plain listing, got %q", out)
+	}
+}
+
+func TestStandup_SourceErrorNoted(t *testing.T) {
+	commits, gh, _ := sampleSources()
+	gh.runErr = errors.New("gh not authenticated")
+	gh.runs = nil
+	r := newTestReporter(nil, WithCommits(commits), WithGitHub(gh))
+
+	out, err := r.Standup(context.Background(), DefaultWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "abc1234") || !strings.Contains(out, "Could not read: CI runs") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestStandup_NoActivity(t *testing.T) {
+	p := &mockProvider{reply: "should not be called"}
+	r := newTestReporter(p, WithCommits(&mockCommits{}))
+
+	out, _ := r.Standup(context.Background(), 48*time.Hour)
+	if !strings.Contains(out, "No repo activity in the last 48h") {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if p.prompt != "" {
+		t.Error("model should not be called without activity")
+	}
+}
+
+func TestCommand_Hours(t *testing.T) {
+	commits := &mockCommits{}
+	cmd := Command(newTestReporter(nil, WithCommits(commits)))
+
+	if _, err := cmd.Run(context.Background(), chatcmd.Invocation{RawArgs: "72"}); err != nil {
+		t.Fatal(err)
+	}
+	if !commits.since.Equal(now.Add(-72 * time.Hour)) {
+		t.Errorf("since = %v", commits.since)
+	}
+	out, _ := cmd.Run(context.Background(), chatcmd.Invocation{RawArgs: "soon"})
+	if !strings.HasPrefix(out, "Usage:") {
+		t.Errorf("expected usage, got %q", out)
+	}
+}
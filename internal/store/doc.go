@@ -0,0 +1,12 @@
+// Package store keeps daemon state — inbound chat messages, agent
+// sessions and budget records — behind a Backend interface, so teams
+// running several CodeButler instances on a shared server can point them
+// at one Postgres database instead of per-process files. SQLite is the
+// single-machine option, and Memory backs tests and the ephemeral demo
+// without any database. Schemas are versioned with package migrate.
+//
+// The default build bundles no SQL driver, as in sqldb. Build with
+// -tags sqlite (cgo) or -tags postgres to compile in the driver for that
+// backend; CheckDriver reports a missing one so config validation and
+// startup can say which tag to use.
+package store
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// Memory is a Backend that keeps everything in process. It suits tests
+// and the ephemeral demo; state is lost on Close or exit. Values are
+// copied on the way in and out, so callers never share slices with the
+// store or with each other.
+type Memory struct {
+	mu       sync.RWMutex
+	messages map[threadKey][]Message
+	seen     map[string]bool // channel + "/" + ts
+	sessions map[string][]agent.Message
+	budgets  map[string]json.RawMessage
+	now      func() time.Time
+}
+
+type threadKey struct{ channel, thread string }
+
+// NewMemory returns an empty in-memory backend.
+func NewMemory() *Memory {
+	return &Memory{
+		messages: make(map[threadKey][]Message),
+		seen:     make(map[string]bool),
+		sessions: make(map[string][]agent.Message),
+		budgets:  make(map[string]json.RawMessage),
+		now:      time.Now,
+	}
+}
+
+// AppendMessage implements Backend.
+func (m *Memory) AppendMessage(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := msg.Channel + "/" + msg.TS
+	if m.seen[id] {
+		return nil
+	}
+	m.seen[id] = true
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = m.now()
+	}
+	k := threadKey{msg.Channel, msg.Thread}
+	msgs := append(m.messages[k], msg)
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].TS < msgs[j].TS })
+	m.messages[k] = msgs
+	return nil
+}
+
+// Messages implements Backend.
+func (m *Memory) Messages(_ context.Context, channel, thread string, limit int) ([]Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	msgs := m.messages[threadKey{channel, thread}]
+	if limit > 0 && len(msgs) > limit {
+		msgs = msgs[len(msgs)-limit:]
+	}
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+	return append([]Message(nil), msgs...), nil
+}
+
+// LoadSession implements Backend.
+func (m *Memory) LoadSession(_ context.Context, key string) ([]agent.Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return cloneMessages(m.sessions[key]), nil
+}
+
+// SaveSession implements Backend.
+func (m *Memory) SaveSession(_ context.Context, key string, messages []agent.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[key] = cloneMessages(messages)
+	return nil
+}
+
+// LoadBudget implements Backend.
+func (m *Memory) LoadBudget(_ context.Context, key string) (json.RawMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.budgets[key]
+	if !ok {
+		return nil, nil
+	}
+	return append(json.RawMessage(nil), data...), nil
+}
+
+// SaveBudget implements Backend.
+func (m *Memory) SaveBudget(_ context.Context, key string, data json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.budgets[key] = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Close implements Backend. It drops all state.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.messages)
+	clear(m.seen)
+	clear(m.sessions)
+	clear(m.budgets)
+	return nil
+}
+
+// cloneMessages copies messages deeply enough that tool calls are not
+// shared either. nil stays nil so an unknown session loads as nil.
+func cloneMessages(msgs []agent.Message) []agent.Message {
+	if msgs == nil {
+		return nil
+	}
+	out := make([]agent.Message, len(msgs))
+	for i, msg := range msgs {
+		msg.ToolCalls = append([]agent.ToolCall(nil), msg.ToolCalls...)
+		out[i] = msg
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+var _ Backend = (*Memory)(nil)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	for _, ts := range []string{"100.2", "100.1", "100.3", "100.1"} {
+		m.AppendMessage(ctx, Message{Channel: "C1", Thread: "100.1", TS: ts, Text: "m" + ts})
+	}
+	m.AppendMessage(ctx, Message{Channel: "C1", Thread: "200.1", TS: "200.1"})
+	all, _ := m.Messages(ctx, "C1", "100.1", 0)
+	if len(all) != 3 || all[0].TS != "100.1" || all[2].TS != "100.3" || all[0].CreatedAt.IsZero() {
+		t.Errorf("messages = %+v", all)
+	}
+	if recent, _ := m.Messages(ctx, "C1", "100.1", 2); len(recent) != 2 || recent[0].TS != "100.2" {
+		t.Errorf("recent = %+v", recent)
+	}
+	all[0].Text = "changed"
+	if again, _ := m.Messages(ctx, "C1", "100.1", 0); again[0].Text != "m100.1" {
+		t.Error("Messages returned shared storage")
+	}
+
+	if got, err := m.LoadSession(ctx, "none"); got != nil || err != nil {
+		t.Errorf("unknown session = %v, %v", got, err)
+	}
+	msgs := []agent.Message{{Role: "assistant", ToolCalls: []agent.ToolCall{{ID: "1", Name: "Read"}}}}
+	m.SaveSession(ctx, "s", msgs)
+	msgs[0].ToolCalls[0].Name = "Write"
+	got, _ := m.LoadSession(ctx, "s")
+	if got[0].ToolCalls[0].Name != "Read" {
+		t.Error("SaveSession kept the caller's slice")
+	}
+	got[0].ToolCalls[0].Name = "Bash"
+	if again, _ := m.LoadSession(ctx, "s"); again[0].ToolCalls[0].Name != "Read" {
+		t.Error("LoadSession returned shared storage")
+	}
+
+	m.SaveBudget(ctx, "day", json.RawMessage(`{"cost":1}`))
+	if b, _ := m.LoadBudget(ctx, "day"); string(b) != `{"cost":1}` {
+		t.Errorf("budget = %s", b)
+	}
+	if b, err := m.LoadBudget(ctx, "none"); b != nil || err != nil {
+		t.Errorf("unknown budget = %s, %v", b, err)
+	}
+
+	m.Close()
+	if got, _ := m.Messages(ctx, "C1", "100.1", 0); got != nil {
+		t.Errorf("after Close = %+v", got)
+	}
+}
+
+func TestMemory_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("s%d", i%4)
+			for j := range 50 {
+				m.AppendMessage(ctx, Message{Channel: "C", Thread: "T", TS: fmt.Sprintf("%d.%03d", i, j)})
+				m.SaveSession(ctx, key, []agent.Message{{Role: "user", Content: key}})
+				if got, _ := m.LoadSession(ctx, key); len(got) != 1 || got[0].Content != key {
+					t.Errorf("session %s = %+v", key, got)
+				}
+				m.Messages(ctx, "C", "T", 10)
+			}
+		}()
+	}
+	wg.Wait()
+	if all, _ := m.Messages(ctx, "C", "T", 0); len(all) != 1000 {
+		t.Errorf("messages = %d, want 1000", len(all))
+	}
+}
+
+func TestOpen_Memory(t *testing.T) {
+	b, err := Open(context.Background(), &config.GlobalStore{Backend: "memory"}, "", quiet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.(*Memory); !ok {
+		t.Errorf("backend = %T", b)
+	}
+}
@@ -0,0 +1,23 @@
+-- Shared daemon state: inbound chat messages, agent sessions, budgets.
+CREATE TABLE messages (
+  channel    TEXT NOT NULL,
+  ts         TEXT NOT NULL,
+  thread     TEXT NOT NULL,
+  user_id    TEXT NOT NULL DEFAULT '',
+  text       TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (channel, ts)
+);
+CREATE INDEX messages_thread ON messages (channel, thread, ts);
+
+CREATE TABLE sessions (
+  key        TEXT PRIMARY KEY,
+  data       TEXT NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE budgets (
+  key        TEXT PRIMARY KEY,
+  data       TEXT NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
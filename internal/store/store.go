@@ -0,0 +1,335 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/migrate"
+)
+
+//go:embed migrations
+var migrations embed.FS
+
+// Message is one inbound chat message.
+type Message struct {
+	Channel   string    `json:"channel"`
+	Thread    string    `json:"thread"`
+	TS        string    `json:"ts"`
+	UserID    string    `json:"user_id,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Backend is shared daemon state. Implementations are safe for concurrent
+// use, including from several processes.
+type Backend interface {
+	// AppendMessage records m. Recording the same channel/ts twice is a
+	// no-op, so instances that both saw an event do not duplicate it.
+	AppendMessage(ctx context.Context, m Message) error
+	// Messages returns a thread's messages, oldest first, at most limit
+	// (0 = all).
+	Messages(ctx context.Context, channel, thread string, limit int) ([]Message, error)
+
+	// LoadSession returns nil, nil for an unknown key.
+	LoadSession(ctx context.Context, key string) ([]agent.Message, error)
+	SaveSession(ctx context.Context, key string, messages []agent.Message) error
+
+	// LoadBudget returns nil, nil for an unknown key. Keys are the
+	// budget package's ("thread:<id>", "day:<date>", "month:<YYYY-MM>").
+	LoadBudget(ctx context.Context, key string) (json.RawMessage, error)
+	SaveBudget(ctx context.Context, key string, data json.RawMessage) error
+
+	Close() error
+}
+
+// Dialect is the SQL flavor a SQLBackend speaks.
+type Dialect struct {
+	Name         string
+	Driver       string // default database/sql driver name
+	VersionTable string // empty uses PRAGMA user_version
+	numbered     bool   // $1, $2 placeholders instead of ?
+}
+
+var (
+	SQLite   = Dialect{Name: "sqlite", Driver: "sqlite3"}
+	Postgres = Dialect{Name: "postgres", Driver: "postgres", VersionTable: "schema_version", numbered: true}
+)
+
+// rebind rewrites ? placeholders for the dialect. Queries here never
+// contain a literal "?".
+func (d Dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Migrations returns the dialect's embedded schema migrations.
+func (d Dialect) Migrations() ([]migrate.Migration, error) {
+	return migrate.Load(migrations, "migrations/"+d.Name)
+}
+
+// DefaultSQLitePath is the SQLite database for a repo.
+func DefaultSQLitePath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "store.db")
+}
+
+// Open connects to the backend cfg selects and brings its schema up to
+// date. A nil cfg or empty Backend means state stays in the per-repo
+// files, and Open returns nil, nil. "memory" keeps state in process only.
+func Open(ctx context.Context, cfg *config.GlobalStore, repoDir string, logger *slog.Logger) (Backend, error) {
+	if cfg == nil || cfg.Backend == "" || cfg.Backend == "file" {
+		return nil, nil
+	}
+	if cfg.Backend == "memory" {
+		return NewMemory(), nil
+	}
+	d, driver, err := resolve(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dsn := cfg.DSN
+	if dsn == "" {
+		if d.Name != SQLite.Name {
+			return nil, fmt.Errorf("store: %s backend needs a dsn", d.Name)
+		}
+		dsn = DefaultSQLitePath(repoDir)
+	}
+	if err := registered(d, driver); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open %s store: %w", d.Name, err)
+	}
+	b, err := NewSQLBackend(ctx, db, d, logger)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// CheckDriver reports whether this binary can open the backend cfg
+// selects. The default build registers no SQL drivers; the error says how
+// to build one that does.
+func CheckDriver(cfg *config.GlobalStore) error {
+	if cfg == nil || cfg.Backend == "" || cfg.Backend == "file" || cfg.Backend == "memory" {
+		return nil
+	}
+	d, driver, err := resolve(cfg)
+	if err != nil {
+		return err
+	}
+	return registered(d, driver)
+}
+
+// resolve picks the dialect and driver for a SQL backend.
+func resolve(cfg *config.GlobalStore) (Dialect, string, error) {
+	var d Dialect
+	switch cfg.Backend {
+	case SQLite.Name:
+		d = SQLite
+	case Postgres.Name:
+		d = Postgres
+	default:
+		return d, "", fmt.Errorf("unknown store backend %q (want %q, %q or \"memory\")", cfg.Backend, SQLite.Name, Postgres.Name)
+	}
+	driver := cfg.Driver
+	if driver == "" {
+		driver = d.Driver
+	}
+	return d, driver, nil
+}
+
+// registered reports a driver missing from this binary.
+func registered(d Dialect, driver string) error {
+	if slices.Contains(sql.Drivers(), driver) {
+		return nil
+	}
+	if driver == d.Driver {
+		return fmt.Errorf("store: the %q driver is not compiled into this binary; rebuild with `go build -tags %s ./cmd/codebutler`", driver, d.Name)
+	}
+	return fmt.Errorf("store: the %q driver is not compiled into this binary; register it with a blank import", driver)
+}
+
+// SQLBackend implements Backend on database/sql.
+type SQLBackend struct {
+	db      *sql.DB
+	dialect Dialect
+	now     func() time.Time
+}
+
+// NewSQLBackend migrates db to the dialect's latest schema and wraps it.
+func NewSQLBackend(ctx context.Context, db *sql.DB, d Dialect, logger *slog.Logger) (*SQLBackend, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ms, err := d.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	opts := []migrate.Option{migrate.WithLogger(logger)}
+	if d.VersionTable != "" {
+		opts = append(opts, migrate.WithVersionTable(d.VersionTable))
+	}
+	if _, err := migrate.Run(ctx, db, ms, opts...); err != nil {
+		return nil, fmt.Errorf("migrate %s store: %w", d.Name, err)
+	}
+	return &SQLBackend{db: db, dialect: d, now: time.Now}, nil
+}
+
+func (b *SQLBackend) exec(ctx context.Context, query string, args ...any) error {
+	_, err := b.db.ExecContext(ctx, b.dialect.rebind(query), args...)
+	return err
+}
+
+// AppendMessage implements Backend.
+func (b *SQLBackend) AppendMessage(ctx context.Context, m Message) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = b.now()
+	}
+	err := b.exec(ctx, `INSERT INTO messages (channel, ts, thread, user_id, text, created_at)
+VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (channel, ts) DO NOTHING`,
+		m.Channel, m.TS, m.Thread, m.UserID, m.Text, m.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("append message: %w", err)
+	}
+	return nil
+}
+
+// Messages implements Backend.
+func (b *SQLBackend) Messages(ctx context.Context, channel, thread string, limit int) ([]Message, error) {
+	query := `SELECT channel, thread, ts, user_id, text, created_at FROM messages
+WHERE channel = ? AND thread = ? ORDER BY ts`
+	args := []any{channel, thread}
+	if limit > 0 {
+		// Newest limit messages, returned oldest first.
+		query = `SELECT channel, thread, ts, user_id, text, created_at FROM (
+SELECT channel, thread, ts, user_id, text, created_at FROM messages
+WHERE channel = ? AND thread = ? ORDER BY ts DESC LIMIT ?) recent ORDER BY ts`
+		args = append(args, limit)
+	}
+	rows, err := b.db.QueryContext(ctx, b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query messages: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Channel, &m.Thread, &m.TS, &m.UserID, &m.Text, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// LoadSession implements Backend.
+func (b *SQLBackend) LoadSession(ctx context.Context, key string) ([]agent.Message, error) {
+	data, err := b.load(ctx, "sessions", key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var messages []agent.Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("parse session %s: %w", key, err)
+	}
+	return messages, nil
+}
+
+// SaveSession implements Backend.
+func (b *SQLBackend) SaveSession(ctx context.Context, key string, messages []agent.Message) error {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+	return b.save(ctx, "sessions", key, data)
+}
+
+// LoadBudget implements Backend.
+func (b *SQLBackend) LoadBudget(ctx context.Context, key string) (json.RawMessage, error) {
+	return b.load(ctx, "budgets", key)
+}
+
+// SaveBudget implements Backend.
+func (b *SQLBackend) SaveBudget(ctx context.Context, key string, data json.RawMessage) error {
+	return b.save(ctx, "budgets", key, data)
+}
+
+// load and save serve the two key → JSON tables; table is never user
+// input.
+func (b *SQLBackend) load(ctx context.Context, table, key string) (json.RawMessage, error) {
+	var data string
+	err := b.db.QueryRowContext(ctx, b.dialect.rebind("SELECT data FROM "+table+" WHERE key = ?"), key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load %s %s: %w", table, key, err)
+	}
+	return json.RawMessage(data), nil
+}
+
+func (b *SQLBackend) save(ctx context.Context, table, key string, data []byte) error {
+	err := b.exec(ctx, "INSERT INTO "+table+` (key, data, updated_at) VALUES (?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		key, string(data), b.now().UTC())
+	if err != nil {
+		return fmt.Errorf("save %s %s: %w", table, key, err)
+	}
+	return nil
+}
+
+// Close implements Backend.
+func (b *SQLBackend) Close() error {
+	return b.db.Close()
+}
+
+// Session adapts one session key to agent.ConversationStore, so an
+// AgentRunner can persist to the shared backend instead of a file.
+type Session struct {
+	backend Backend
+	key     string
+}
+
+// NewSession returns the session stored under key, e.g. a
+// conversation.Scope key plus the agent role.
+func NewSession(backend Backend, key string) *Session {
+	return &Session{backend: backend, key: key}
+}
+
+// Load implements agent.ConversationStore.
+func (s *Session) Load(ctx context.Context) ([]agent.Message, error) {
+	return s.backend.LoadSession(ctx, s.key)
+}
+
+// Save implements agent.ConversationStore.
+func (s *Session) Save(ctx context.Context, messages []agent.Message) error {
+	return s.backend.SaveSession(ctx, s.key, messages)
+}
@@ -0,0 +1,275 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// memDriver is an in-memory stand-in for SQLite and Postgres that
+// understands exactly the statements this package issues.
+type memDriver struct {
+	mu        sync.Mutex
+	version   int
+	versioned bool // version table row exists
+	ddl       []string
+	queries   []string
+	messages  map[string][]driver.Value // channel/ts → row
+	kv        map[string]map[string]string
+}
+
+func newMemDriver() *memDriver {
+	return &memDriver{messages: map[string][]driver.Value{}, kv: map[string]map[string]string{"sessions": {}, "budgets": {}}}
+}
+
+var (
+	drivers   sync.Map // dsn → *memDriver
+	setVer    = regexp.MustCompile(`^(?:PRAGMA user_version|UPDATE schema_version SET version) = (\d+)$`)
+	kvTable   = regexp.MustCompile(`^(?:INSERT INTO|SELECT data FROM) (sessions|budgets)`)
+	placeheld = regexp.MustCompile(`\?|\$\d+`)
+)
+
+type memConnector struct{}
+
+func (memConnector) Open(dsn string) (driver.Conn, error) {
+	d, _ := drivers.LoadOrStore(dsn, newMemDriver())
+	return &memConn{d: d.(*memDriver)}, nil
+}
+
+func init() { sql.Register("store-mem", memConnector{}) }
+
+type memConn struct{ d *memDriver }
+
+func (c *memConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *memConn) Close() error                        { return nil }
+func (c *memConn) Begin() (driver.Tx, error)           { return memTx{}, nil }
+
+type memTx struct{}
+
+func (memTx) Commit() error   { return nil }
+func (memTx) Rollback() error { return nil }
+
+func values(args []driver.NamedValue) []driver.Value {
+	out := make([]driver.Value, len(args))
+	for i, a := range args {
+		out[i] = a.Value
+	}
+	return out
+}
+
+func (c *memConn) ExecContext(_ context.Context, q string, args []driver.NamedValue) (driver.Result, error) {
+	d := c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, q)
+	v := values(args)
+	switch m := setVer.FindStringSubmatch(q); {
+	case m != nil:
+		d.version = int(m[1][0] - '0')
+	case strings.HasPrefix(q, "CREATE TABLE IF NOT EXISTS schema_version"):
+	case strings.HasPrefix(q, "INSERT INTO schema_version"):
+		d.versioned = true
+	case strings.HasPrefix(q, "CREATE"):
+		d.ddl = append(d.ddl, q)
+	case strings.HasPrefix(q, "INSERT INTO messages"):
+		key := v[0].(string) + "/" + v[1].(string)
+		if _, ok := d.messages[key]; !ok {
+			// channel, thread, ts, user_id, text, created_at
+			d.messages[key] = []driver.Value{v[0], v[2], v[1], v[3], v[4], v[5]}
+		}
+	case kvTable.MatchString(q):
+		table := kvTable.FindStringSubmatch(q)[1]
+		d.kv[table][v[0].(string)] = v[1].(string)
+	default:
+		return nil, errors.New("unexpected exec: " + q)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *memConn) QueryContext(_ context.Context, q string, args []driver.NamedValue) (driver.Rows, error) {
+	d := c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, q)
+	v := values(args)
+	switch {
+	case q == "PRAGMA user_version":
+		return &memRows{cols: []string{"user_version"}, rows: [][]driver.Value{{int64(d.version)}}}, nil
+	case q == "SELECT version FROM schema_version":
+		if !d.versioned {
+			return &memRows{cols: []string{"version"}}, nil
+		}
+		return &memRows{cols: []string{"version"}, rows: [][]driver.Value{{int64(d.version)}}}, nil
+	case kvTable.MatchString(q):
+		table := kvTable.FindStringSubmatch(q)[1]
+		data, ok := d.kv[table][v[0].(string)]
+		if !ok {
+			return &memRows{cols: []string{"data"}}, nil
+		}
+		return &memRows{cols: []string{"data"}, rows: [][]driver.Value{{data}}}, nil
+	case strings.Contains(q, "FROM messages"):
+		var rows [][]driver.Value
+		for _, r := range d.messages {
+			if r[0] == v[0] && r[1] == v[1] {
+				rows = append(rows, r)
+			}
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][2].(string) < rows[j][2].(string) })
+		if len(v) > 2 {
+			if n := int(v[2].(int64)); len(rows) > n {
+				rows = rows[len(rows)-n:]
+			}
+		}
+		return &memRows{cols: []string{"channel", "thread", "ts", "user_id", "text", "created_at"}, rows: rows}, nil
+	}
+	return nil, errors.New("unexpected query: " + q)
+}
+
+type memRows struct {
+	cols []string
+	rows [][]driver.Value
+	pos  int
+}
+
+func (r *memRows) Columns() []string { return r.cols }
+func (r *memRows) Close() error      { return nil }
+func (r *memRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func quiet() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) }
+
+func openMem(t *testing.T, d Dialect) (*SQLBackend, *memDriver) {
+	t.Helper()
+	dsn := t.Name()
+	db, err := sql.Open("store-mem", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSQLBackend(context.Background(), db, d, quiet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	mem, _ := drivers.Load(dsn)
+	return b, mem.(*memDriver)
+}
+
+func TestSQLBackend(t *testing.T) {
+	for _, d := range []Dialect{SQLite, Postgres} {
+		t.Run(d.Name, func(t *testing.T) {
+			b, mem := openMem(t, d)
+			ctx := context.Background()
+			if mem.version != 1 || len(mem.ddl) != 4 {
+				t.Fatalf("version = %d, ddl = %d", mem.version, len(mem.ddl))
+			}
+
+			at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			for _, ts := range []string{"100.2", "100.1", "100.3", "100.1"} {
+				if err := b.AppendMessage(ctx, Message{Channel: "C1", Thread: "100.1", TS: ts, Text: "m" + ts, CreatedAt: at}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			all, err := b.Messages(ctx, "C1", "100.1", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != 3 || all[0].TS != "100.1" || all[2].Text != "m100.3" || !all[0].CreatedAt.Equal(at) {
+				t.Errorf("messages = %+v", all)
+			}
+			if recent, _ := b.Messages(ctx, "C1", "100.1", 2); len(recent) != 2 || recent[0].TS != "100.2" {
+				t.Errorf("recent = %+v", recent)
+			}
+
+			s := NewSession(b, "C1/100.1/coder")
+			if got, err := s.Load(ctx); err != nil || got != nil {
+				t.Errorf("new session = %v, %v", got, err)
+			}
+			want := []agent.Message{{Role: "user", Content: "fix it"}, {Role: "assistant", Content: "done"}}
+			s.Save(ctx, want)
+			s.Save(ctx, want) // upsert
+			if got, _ := s.Load(ctx); len(got) != 2 || got[1].Content != "done" {
+				t.Errorf("session = %+v", got)
+			}
+
+			b.SaveBudget(ctx, "thread:100.1", json.RawMessage(`{"total_cost_usd":1.5}`))
+			if got, _ := b.LoadBudget(ctx, "thread:100.1"); string(got) != `{"total_cost_usd":1.5}` {
+				t.Errorf("budget = %s", got)
+			}
+			if got, err := b.LoadBudget(ctx, "thread:none"); err != nil || got != nil {
+				t.Errorf("missing budget = %s, %v", got, err)
+			}
+
+			for _, q := range mem.queries {
+				ph := placeheld.FindString(q)
+				if d.Name == "postgres" && ph == "?" || d.Name == "sqlite" && strings.HasPrefix(ph, "$") {
+					t.Errorf("%s query has %q placeholders: %s", d.Name, ph, q)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSQLBackend_MigratesOnce(t *testing.T) {
+	_, mem := openMem(t, Postgres)
+	db, _ := sql.Open("store-mem", t.Name())
+	if _, err := NewSQLBackend(context.Background(), db, Postgres, quiet()); err != nil {
+		t.Fatal(err)
+	}
+	if len(mem.ddl) != 4 {
+		t.Errorf("second instance re-ran migrations: %d DDL statements", len(mem.ddl))
+	}
+}
+
+func TestCheckDriver(t *testing.T) {
+	for _, cfg := range []*config.GlobalStore{nil, {Backend: "file"}, {Backend: "memory"}, {Backend: "sqlite", Driver: "store-mem"}} {
+		if err := CheckDriver(cfg); err != nil {
+			t.Errorf("CheckDriver(%+v) = %v", cfg, err)
+		}
+	}
+	if err := CheckDriver(&config.GlobalStore{Backend: "postgres"}); err == nil || !strings.Contains(err.Error(), "-tags postgres") {
+		t.Errorf("postgres without driver = %v", err)
+	}
+	if err := CheckDriver(&config.GlobalStore{Backend: "postgres", Driver: "pgx"}); err == nil || !strings.Contains(err.Error(), "blank import") {
+		t.Errorf("custom driver = %v", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	if b, err := Open(ctx, nil, t.TempDir(), quiet()); b != nil || err != nil {
+		t.Errorf("nil config = %v, %v", b, err)
+	}
+	if _, err := Open(ctx, &config.GlobalStore{Backend: "mongo"}, "", quiet()); err == nil {
+		t.Error("expected unknown backend error")
+	}
+	if _, err := Open(ctx, &config.GlobalStore{Backend: "postgres"}, "", quiet()); err == nil || !strings.Contains(err.Error(), "dsn") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := Open(ctx, &config.GlobalStore{Backend: "sqlite"}, t.TempDir(), quiet()); err == nil || !strings.Contains(err.Error(), "-tags sqlite") {
+		t.Errorf("missing driver err = %v", err)
+	}
+	b, err := Open(ctx, &config.GlobalStore{Backend: "postgres", Driver: "store-mem", DSN: t.Name()}, "", quiet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+}
@@ -0,0 +1,91 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// StatusSection adds a block to /status output for the thread the command
+// was typed in, e.g. its phase or the worktree GC state. An empty result is
+// skipped.
+type StatusSection func(channel, thread string) string
+
+// Commands returns the /queue, /cancel, and /status chat commands. Each
+// section is appended to the /status reply.
+func Commands(q *Queue, sections ...StatusSection) []*chatcmd.Command {
+	return []*chatcmd.Command{
+		{
+			Name:        "queue",
+			Description: "List pending and running tasks",
+			Run: func(context.Context, chatcmd.Invocation) (string, error) {
+				active := q.Active()
+				if len(active) == 0 {
+					return "No pending or running tasks.", nil
+				}
+				var b strings.Builder
+				b.WriteString("Tasks:\n")
+				for _, t := range active {
+					fmt.Fprintf(&b, "• `%s` %s — %s\n", t.ID, t.Status, t.Summary)
+				}
+				return strings.TrimRight(b.String(), "\n"), nil
+			},
+		},
+		{
+			Name:        "cancel",
+			Usage:       "/cancel <task-id>",
+			Description: "Stop a running task or remove a pending one",
+			Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+				if len(inv.Args) != 1 {
+					return "Usage: /cancel <task-id> (see /queue for IDs)", nil
+				}
+				t, err := q.Cancel(inv.Args[0])
+				if errors.Is(err, ErrNotFound) {
+					return fmt.Sprintf("No task `%s`. See /queue for IDs.", inv.Args[0]), nil
+				}
+				if err != nil {
+					return err.Error(), nil
+				}
+				if t.Status == StatusRunning {
+					return fmt.Sprintf("Stopped `%s` (%s).", t.ID, t.Summary), nil
+				}
+				return fmt.Sprintf("Removed `%s` from the queue.", t.ID), nil
+			},
+		},
+		{
+			Name:        "status",
+			Description: "Show this thread's current task: elapsed time, turns, cost",
+			Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+				reply := "No task in this thread yet."
+				if t, ok := q.Latest(inv.Channel, inv.Thread); ok {
+					reply = FormatStatus(t, q.now())
+				}
+				for _, section := range sections {
+					if extra := section(inv.Channel, inv.Thread); extra != "" {
+						reply += "\n\n" + extra
+					}
+				}
+				return reply, nil
+			},
+		},
+	}
+}
+
+// FormatStatus renders a task for /status.
+func FormatStatus(t Task, now time.Time) string {
+	var elapsed time.Duration
+	switch {
+	case t.StartedAt.IsZero():
+		return fmt.Sprintf("`%s` is pending: %s", t.ID, t.Summary)
+	case t.EndedAt.IsZero():
+		elapsed = now.Sub(t.StartedAt)
+	default:
+		elapsed = t.EndedAt.Sub(t.StartedAt)
+	}
+	return fmt.Sprintf("`%s` %s: %s\nElapsed %s · %d turns · $%.2f",
+		t.ID, t.Status, t.Summary, elapsed.Round(time.Second), t.Turns, t.Cost)
+}
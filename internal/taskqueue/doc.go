@@ -0,0 +1,4 @@
+// Package taskqueue tracks pending and running tasks in a JSON file under
+// .codebutler/ so they survive restarts, and provides the /queue, /cancel,
+// and /status chat commands to see and abort what the agents are doing.
+package taskqueue
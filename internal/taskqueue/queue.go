@@ -0,0 +1,317 @@
+package taskqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Status is a task's lifecycle state.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// ErrNotFound is returned for unknown task IDs.
+var ErrNotFound = errors.New("task not found")
+
+// Task is one queued or running request.
+type Task struct {
+	ID        string    `json:"id"`
+	Channel   string    `json:"channel"`
+	Thread    string    `json:"thread"`
+	UserID    string    `json:"user_id,omitempty"`
+	Summary   string    `json:"summary"` // first line of the request
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+	Turns     int       `json:"turns"`
+	Cost      float64   `json:"cost_usd"`
+}
+
+// Active reports whether the task is pending or running.
+func (t Task) Active() bool {
+	return t.Status == StatusPending || t.Status == StatusRunning
+}
+
+// state is the file format.
+type state struct {
+	NextID int     `json:"next_id"`
+	Tasks  []*Task `json:"tasks"`
+}
+
+// Queue is a persistent task list. Finished tasks are kept up to a limit
+// so /status can still show the last result. Thread-safe.
+type Queue struct {
+	mu      sync.Mutex
+	path    string
+	now     func() time.Time
+	state   state
+	cancels map[string]context.CancelFunc // running tasks in this process
+}
+
+// maxFinished is how many finished tasks are kept in the file.
+const maxFinished = 50
+
+// DefaultPath returns the queue file for a repo.
+func DefaultPath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "tasks.json")
+}
+
+// Open loads the queue at path. Tasks left running by a previous process
+// are marked failed, since nothing is running them anymore.
+func Open(path string) (*Queue, error) {
+	q := &Queue{path: path, now: time.Now, cancels: make(map[string]context.CancelFunc)}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		q.state.NextID = 1
+		return q, nil
+	case err != nil:
+		return nil, fmt.Errorf("read task queue: %w", err)
+	}
+	if err := json.Unmarshal(data, &q.state); err != nil {
+		return nil, fmt.Errorf("parse task queue: %w", err)
+	}
+	for _, t := range q.state.Tasks {
+		if t.Status == StatusRunning {
+			t.Status = StatusFailed
+			t.EndedAt = q.now()
+		}
+	}
+	return q, q.save()
+}
+
+// Read returns the tasks stored at path without opening the queue, so
+// tools can inspect it while a daemon owns it. A missing file is empty.
+func Read(path string) ([]Task, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read task queue: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parse task queue: %w", err)
+	}
+	tasks := make([]Task, len(st.Tasks))
+	for i, t := range st.Tasks {
+		tasks[i] = *t
+	}
+	return tasks, nil
+}
+
+// Enqueue adds a pending task and returns it.
+func (q *Queue) Enqueue(channel, thread, userID, summary string) (Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := &Task{
+		ID:        fmt.Sprintf("t%d", q.state.NextID),
+		Channel:   channel,
+		Thread:    thread,
+		UserID:    userID,
+		Summary:   summary,
+		Status:    StatusPending,
+		CreatedAt: q.now(),
+	}
+	q.state.NextID++
+	q.state.Tasks = append(q.state.Tasks, t)
+	return *t, q.save()
+}
+
+// Start marks a pending task running. cancel is called by Cancel to stop
+// the run; pass the CancelFunc of the run's context.
+func (q *Queue) Start(id string, cancel context.CancelFunc) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.find(id)
+	if t == nil {
+		return fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	if t.Status != StatusPending {
+		return fmt.Errorf("task %s is %s, not pending", id, t.Status)
+	}
+	t.Status = StatusRunning
+	t.StartedAt = q.now()
+	if cancel != nil {
+		q.cancels[id] = cancel
+	}
+	return q.save()
+}
+
+// Progress updates a running task's turn count and cost.
+func (q *Queue) Progress(id string, turns int, cost float64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.find(id)
+	if t == nil {
+		return fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	t.Turns, t.Cost = turns, cost
+	return q.save()
+}
+
+// Finish records a task's final status. A task already cancelled stays
+// cancelled.
+func (q *Queue) Finish(id string, status Status) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.find(id)
+	if t == nil {
+		return fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	delete(q.cancels, id)
+	if t.Status != StatusCancelled {
+		t.Status = status
+	}
+	t.EndedAt = q.now()
+	q.prune()
+	return q.save()
+}
+
+// Cancel dequeues a pending task or stops a running one. It returns the
+// task as it was before cancelling.
+func (q *Queue) Cancel(id string) (Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.find(id)
+	if t == nil {
+		return Task{}, fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	before := *t
+	if !t.Active() {
+		return before, fmt.Errorf("task %s already %s", id, t.Status)
+	}
+	if cancel := q.cancels[id]; cancel != nil {
+		cancel()
+		delete(q.cancels, id)
+	}
+	t.Status = StatusCancelled
+	t.EndedAt = q.now()
+	return before, q.save()
+}
+
+// Active returns pending and running tasks, oldest first.
+func (q *Queue) Active() []Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var out []Task
+	for _, t := range q.state.Tasks {
+		if t.Active() {
+			out = append(out, *t)
+		}
+	}
+	return out
+}
+
+// Since returns tasks created, or finished, at or after t, oldest first.
+// Pruned tasks are not included.
+func (q *Queue) Since(t time.Time) []Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var out []Task
+	for _, task := range q.state.Tasks {
+		if !task.CreatedAt.Before(t) || (!task.EndedAt.IsZero() && !task.EndedAt.Before(t)) {
+			out = append(out, *task)
+		}
+	}
+	return out
+}
+
+// Latest returns the most recent task for a thread.
+func (q *Queue) Latest(channel, thread string) (Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := len(q.state.Tasks) - 1; i >= 0; i-- {
+		if t := q.state.Tasks[i]; t.Channel == channel && t.Thread == thread {
+			return *t, true
+		}
+	}
+	return Task{}, false
+}
+
+// Get returns the task with id.
+func (q *Queue) Get(id string) (Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.find(id)
+	if t == nil {
+		return Task{}, fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	return *t, nil
+}
+
+// find returns the task with id. Callers hold q.mu.
+func (q *Queue) find(id string) *Task {
+	for _, t := range q.state.Tasks {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// prune drops the oldest finished tasks beyond maxFinished. Callers hold q.mu.
+func (q *Queue) prune() {
+	finished := 0
+	for _, t := range q.state.Tasks {
+		if !t.Active() {
+			finished++
+		}
+	}
+	if finished <= maxFinished {
+		return
+	}
+	drop := finished - maxFinished
+	kept := q.state.Tasks[:0]
+	for _, t := range q.state.Tasks {
+		if !t.Active() && drop > 0 {
+			drop--
+			continue
+		}
+		kept = append(kept, t)
+	}
+	q.state.Tasks = kept
+}
+
+// save writes the queue file. Callers hold q.mu.
+func (q *Queue) save() error {
+	data, err := json.MarshalIndent(q.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal task queue: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return fmt.Errorf("create task queue dir: %w", err)
+	}
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write task queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename task queue: %w", err)
+	}
+	return nil
+}
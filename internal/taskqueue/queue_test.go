@@ -0,0 +1,152 @@
+package taskqueue
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+func openTemp(t *testing.T) (*Queue, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	q, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q, path
+}
+
+func TestQueue_Lifecycle(t *testing.T) {
+	q, path := openTemp(t)
+	a, _ := q.Enqueue("C1", "100.1", "U1", "fix login")
+	b, _ := q.Enqueue("C1", "200.2", "U2", "add dark mode")
+	if a.ID != "t1" || b.ID != "t2" {
+		t.Fatalf("ids = %s, %s", a.ID, b.ID)
+	}
+
+	cancelled := false
+	if err := q.Start(a.ID, func() { cancelled = true }); err != nil {
+		t.Fatal(err)
+	}
+	q.Progress(a.ID, 4, 0.12)
+
+	if got := q.Active(); len(got) != 2 || got[0].Status != StatusRunning {
+		t.Errorf("active = %+v", got)
+	}
+
+	if _, err := q.Cancel(a.ID); err != nil || !cancelled {
+		t.Fatalf("cancel running: err=%v cancelled=%v", err, cancelled)
+	}
+	// The run's own Finish after cancellation keeps the cancelled status.
+	q.Finish(a.ID, StatusFailed)
+	if got, _ := q.Latest("C1", "100.1"); got.Status != StatusCancelled || got.Turns != 4 {
+		t.Errorf("after cancel = %+v", got)
+	}
+
+	// Persisted: a reopened queue sees the pending task and keeps numbering.
+	q2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q2.Active(); len(got) != 1 || got[0].ID != "t2" {
+		t.Errorf("reopened active = %+v", got)
+	}
+	if c, _ := q2.Enqueue("C1", "300.3", "", "x"); c.ID != "t3" {
+		t.Errorf("next id = %s", c.ID)
+	}
+}
+
+func TestOpen_MarksOrphanedRunsFailed(t *testing.T) {
+	q, path := openTemp(t)
+	task, _ := q.Enqueue("C1", "1", "", "long build")
+	q.Start(task.ID, nil)
+
+	q2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := q2.Latest("C1", "1"); got.Status != StatusFailed {
+		t.Errorf("status = %s", got.Status)
+	}
+}
+
+func TestRead_DoesNotTouchRunningTasks(t *testing.T) {
+	q, path := openTemp(t)
+	task, _ := q.Enqueue("C1", "1", "", "long build")
+	q.Start(task.ID, nil)
+
+	tasks, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Status != StatusRunning {
+		t.Errorf("tasks = %+v", tasks)
+	}
+	if tasks, err := Read(path + ".missing"); err != nil || tasks != nil {
+		t.Errorf("missing file: %v, %v", tasks, err)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	q, _ := openTemp(t)
+	start := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return start }
+	task, _ := q.Enqueue("C1", "100.1", "U1", "fix login")
+	q.Start(task.ID, func() {})
+	q.Progress(task.ID, 3, 0.5)
+	q.now = func() time.Time { return start.Add(90 * time.Second) }
+
+	cmds := map[string]*chatcmd.Command{}
+	phase := func(_, thread string) string { return "Phase of " + thread }
+	gcStatus := func(_, _ string) string { return "Worktree GC: not scheduled" }
+	for _, c := range Commands(q, phase, gcStatus) {
+		cmds[c.Name] = c
+	}
+	run := func(name string, args ...string) string {
+		t.Helper()
+		out, err := cmds[name].Run(context.Background(), chatcmd.Invocation{Channel: "C1", Thread: "100.1", Args: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	if out := run("queue"); !strings.Contains(out, "`t1` running — fix login") {
+		t.Errorf("queue: %q", out)
+	}
+	if out := run("status"); !strings.Contains(out, "Elapsed 1m30s · 3 turns · $0.50") ||
+		!strings.HasSuffix(out, "\n\nPhase of 100.1\n\nWorktree GC: not scheduled") {
+		t.Errorf("status: %q", out)
+	}
+	if out := run("cancel", "t9"); !strings.Contains(out, "No task `t9`") {
+		t.Errorf("cancel unknown: %q", out)
+	}
+	if out := run("cancel", "t1"); !strings.Contains(out, "Stopped `t1`") {
+		t.Errorf("cancel: %q", out)
+	}
+	if out := run("queue"); out != "No pending or running tasks." {
+		t.Errorf("queue after cancel: %q", out)
+	}
+}
+
+func TestQueue_Since(t *testing.T) {
+	q, _ := openTemp(t)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	old, _ := q.Enqueue("C1", "1.1", "U1", "old, finished late")
+	q.Enqueue("C1", "2.2", "U1", "old, still pending")
+
+	now = now.Add(48 * time.Hour)
+	q.Finish(old.ID, StatusDone)
+	q.Enqueue("C1", "3.3", "U2", "new")
+
+	got := q.Since(now.Add(-24 * time.Hour))
+	if len(got) != 2 || got[0].Summary != "old, finished late" || got[1].Summary != "new" {
+		t.Errorf("Since = %+v", got)
+	}
+}
@@ -0,0 +1,5 @@
+// Package tldr implements /tldr: a short summary of the current thread
+// (decisions, changes made, open items) for a teammate who joins mid-task.
+// The summary comes from a cheap model over the stored transcript, with an
+// extractive fallback when no model is configured.
+package tldr
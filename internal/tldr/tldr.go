@@ -0,0 +1,181 @@
+package tldr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// defaultMaxChars caps the transcript sent to the model. When a thread is
+// longer, the oldest messages are dropped first.
+const defaultMaxChars = 24000
+
+const summaryPrompt = "Summarize this chat thread for a teammate who just joined. " +
+	"Use three short bulleted sections: *Decisions*, *Changes made*, *Open items*. " +
+	"Omit a section if it would be empty. Attribute requests to people when names are given. " +
+	"Keep it under 12 bullets.\n\n"
+
+// TranscriptSource loads the stored messages of a thread.
+type TranscriptSource interface {
+	Transcript(ctx context.Context, channel, thread string) ([]agent.Message, error)
+}
+
+// TranscriptFunc adapts a function to TranscriptSource.
+type TranscriptFunc func(ctx context.Context, channel, thread string) ([]agent.Message, error)
+
+// Transcript implements TranscriptSource.
+func (f TranscriptFunc) Transcript(ctx context.Context, channel, thread string) ([]agent.Message, error) {
+	return f(ctx, channel, thread)
+}
+
+// Summarizer produces thread summaries.
+type Summarizer struct {
+	provider agent.LLMProvider // optional; nil uses the extractive fallback
+	model    string
+	maxChars int
+	logger   *slog.Logger
+}
+
+// Option configures a Summarizer.
+type Option func(*Summarizer)
+
+// WithMaxChars caps the transcript length sent to the model.
+func WithMaxChars(n int) Option {
+	return func(s *Summarizer) {
+		if n > 0 {
+			s.maxChars = n
+		}
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Summarizer) {
+		s.logger = l
+	}
+}
+
+// NewSummarizer creates a summarizer that uses model via provider.
+func NewSummarizer(provider agent.LLMProvider, model string, opts ...Option) *Summarizer {
+	s := &Summarizer{
+		provider: provider,
+		model:    model,
+		maxChars: defaultMaxChars,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Summarize returns a chat-ready summary of msgs. System prompts and tool
+// traffic are skipped; only what people and agents said counts. If the model
+// call fails the extractive summary is returned instead.
+func (s *Summarizer) Summarize(ctx context.Context, msgs []agent.Message) (string, error) {
+	turns := conversational(msgs)
+	if len(turns) == 0 {
+		return "Nothing to summarize yet.", nil
+	}
+	if s.provider == nil {
+		return Extractive(turns), nil
+	}
+
+	resp, err := s.provider.ChatCompletion(ctx, agent.ChatRequest{
+		Model:    s.model,
+		Messages: []agent.Message{{Role: "user", Content: summaryPrompt + FormatTranscript(turns, s.maxChars)}},
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		s.logger.Warn("tldr model call failed, using extractive summary", "err", err)
+		return Extractive(turns), nil
+	}
+	summary := strings.TrimSpace(resp.Message.Content)
+	if summary == "" {
+		return Extractive(turns), nil
+	}
+	return "*TL;DR*\n" + summary, nil
+}
+
+// FormatTranscript renders turns as "role: text" lines, dropping the oldest
+// ones until the result fits in maxChars.
+func FormatTranscript(turns []agent.Message, maxChars int) string {
+	lines := make([]string, len(turns))
+	total := 0
+	for i, m := range turns {
+		lines[i] = m.Role + ": " + strings.TrimSpace(m.Content)
+		total += len(lines[i]) + 1
+	}
+	start := 0
+	for maxChars > 0 && total > maxChars && start < len(lines)-1 {
+		total -= len(lines[start]) + 1
+		start++
+	}
+	out := strings.Join(lines[start:], "\n")
+	if start > 0 {
+		out = fmt.Sprintf("(%d earlier messages omitted)\n", start) + out
+	}
+	return out
+}
+
+// Extractive builds a summary without a model: every user request in order
+// and the latest assistant reply as the current state.
+func Extractive(turns []agent.Message) string {
+	var b strings.Builder
+	b.WriteString("*TL;DR*\n*Requests*\n")
+	var last string
+	for _, m := range turns {
+		switch m.Role {
+		case "user":
+			fmt.Fprintf(&b, "• %s\n", firstLine(m.Content, 160))
+		case "assistant":
+			last = m.Content
+		}
+	}
+	if last != "" {
+		fmt.Fprintf(&b, "*Latest*\n• %s\n", firstLine(last, 300))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Command returns the /tldr chat command.
+func Command(s *Summarizer, src TranscriptSource) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "tldr",
+		Description: "Summarize this thread: decisions, changes made, open items",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			msgs, err := src.Transcript(ctx, inv.Channel, inv.Thread)
+			if err != nil {
+				return "", fmt.Errorf("load transcript: %w", err)
+			}
+			return s.Summarize(ctx, msgs)
+		},
+	}
+}
+
+func conversational(msgs []agent.Message) []agent.Message {
+	var out []agent.Message
+	for _, m := range msgs {
+		if (m.Role == "user" || m.Role == "assistant") && strings.TrimSpace(m.Content) != "" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func firstLine(s string, max int) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " …"
+	}
+	if r := []rune(s); len(r) > max {
+		s = string(r[:max]) + "…"
+	}
+	return s
+}
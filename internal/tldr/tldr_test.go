@@ -0,0 +1,120 @@
+package tldr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+type mockProvider struct {
+	reply  string
+	err    error
+	prompt string
+	model  string
+}
+
+func (m *mockProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	m.prompt = req.Messages[0].Content
+	m.model = req.Model
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &agent.ChatResponse{Message: agent.Message{Role: "assistant", Content: m.reply}}, nil
+}
+
+func sampleThread() []agent.Message {
+	return []agent.Message{
+		{Role: "system", Content: "You are the PM."},
+		{Role: "user", Content: "Maria: the checkout page crashes on submit"},
+		{Role: "assistant", ToolCalls: []agent.ToolCall{{ID: "1", Name: "Read"}}},
+		{Role: "tool", ToolCallID: "1", Content: "file contents"},
+		{Role: "assistant", Content: "Found a nil cart; fixed in PR #12."},
+		{Role: "user", Content: "Juan: can we also add a regression test?"},
+	}
+}
+
+func TestSummarize_Model(t *testing.T) {
+	p := &mockProvider{reply: "*Decisions*\n• fix nil cart"}
+	s := NewSummarizer(p, "cheap/model")
+
+	got, err := s.Summarize(context.Background(), sampleThread())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "*TL;DR*\n*Decisions*\n• fix nil cart" {
+		t.Errorf("got %q", got)
+	}
+	if p.model != "cheap/model" {
+		t.Errorf("model = %q", p.model)
+	}
+	for _, want := range []string{"user: Maria: the checkout", "assistant: Found a nil cart", "Open items"} {
+		if !strings.Contains(p.prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, p.prompt)
+		}
+	}
+	if strings.Contains(p.prompt, "You are the PM") || strings.Contains(p.prompt, "file contents") {
+		t.Errorf("prompt should skip system and tool messages:\n%s", p.prompt)
+	}
+}
+
+func TestSummarize_FallsBackToExtractive(t *testing.T) {
+	p := &mockProvider{err: errors.New("rate limited")}
+	s := NewSummarizer(p, "m", WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	got, err := s.Summarize(context.Background(), sampleThread())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"• Maria: the checkout page crashes on submit", "• Juan: can we also", "*Latest*\n• Found a nil cart"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	got, err := NewSummarizer(nil, "").Summarize(context.Background(), []agent.Message{{Role: "system", Content: "x"}})
+	if err != nil || got != "Nothing to summarize yet." {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
+
+func TestFormatTranscript_DropsOldest(t *testing.T) {
+	turns := []agent.Message{
+		{Role: "user", Content: strings.Repeat("a", 50)},
+		{Role: "assistant", Content: strings.Repeat("b", 50)},
+		{Role: "user", Content: "latest"},
+	}
+	got := FormatTranscript(turns, 60)
+	if !strings.HasPrefix(got, "(2 earlier messages omitted)") || !strings.HasSuffix(got, "user: latest") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	var gotChannel, gotThread string
+	src := TranscriptFunc(func(_ context.Context, channel, thread string) ([]agent.Message, error) {
+		gotChannel, gotThread = channel, thread
+		return sampleThread(), nil
+	})
+
+	r := chatcmd.NewRegistry()
+	r.Register(Command(NewSummarizer(nil, ""), src))
+
+	reply, handled, err := r.Handle(context.Background(), "C1", "T1", "U1", "/tldr")
+	if err != nil || !handled {
+		t.Fatalf("handled=%v err=%v", handled, err)
+	}
+	if gotChannel != "C1" || gotThread != "T1" {
+		t.Errorf("loaded %s/%s", gotChannel, gotThread)
+	}
+	if !strings.HasPrefix(reply, "*TL;DR*") {
+		t.Errorf("reply = %q", reply)
+	}
+}
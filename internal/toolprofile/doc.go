@@ -0,0 +1,7 @@
+// Package toolprofile resolves named tool permission profiles ("full",
+// "code-only", "read-only", "no-network", plus any defined in the repo
+// config) for a run. A chat, the sender's user role and the workflow phase
+// can each select a profile; a tool is offered only if all of them allow
+// it. The result limits both the AgentRunner executor (Wrap) and the CLI
+// path (AllowedToolsFlag for --allowedTools).
+package toolprofile
@@ -0,0 +1,44 @@
+package toolprofile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// Tools hides and refuses the tools a profile set does not allow.
+type Tools struct {
+	agent.ToolExecutor
+	set Set
+}
+
+// Wrap returns e limited to set.
+func Wrap(e agent.ToolExecutor, set Set) *Tools {
+	return &Tools{ToolExecutor: e, set: set}
+}
+
+// ListTools implements agent.ToolExecutor.
+func (t *Tools) ListTools() []agent.ToolDefinition {
+	all := t.ToolExecutor.ListTools()
+	defs := make([]agent.ToolDefinition, 0, len(all))
+	for _, d := range all {
+		if t.set.Allows(d.Name) {
+			defs = append(defs, d)
+		}
+	}
+	return defs
+}
+
+// Execute implements agent.ToolExecutor. A call to a tool the model was
+// never offered still gets an error result rather than running.
+func (t *Tools) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	if !t.set.Allows(call.Name) {
+		return agent.ToolResult{
+			ToolCallID: call.ID,
+			Content:    fmt.Sprintf("tool %q is not allowed by the %s tool profile", call.Name, t.set),
+			IsError:    true,
+		}, nil
+	}
+	return t.ToolExecutor.Execute(ctx, call)
+}
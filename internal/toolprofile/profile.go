@@ -0,0 +1,233 @@
+package toolprofile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// Full is the unrestricted profile and the default.
+const Full = "full"
+
+// NetworkTools reach outside the machine.
+var NetworkTools = []string{"WebFetch", "WebSearch", "HTTPRequest", "GitPush", "GHCreatePR"}
+
+// Builtin are the profiles every repo has. Repo config may override them.
+var Builtin = map[string]Profile{
+	Full:         {Name: Full},
+	"code-only":  {Name: "code-only", Allow: []string{"Read", "Write", "Edit", "Glob", "Grep", "Bash", "ListTargets", "RunTarget", "GitCommit"}},
+	"read-only":  {Name: "read-only", Allow: []string{"Read", "Glob", "Grep", "ListTargets"}},
+	"no-network": {Name: "no-network", Deny: NetworkTools},
+}
+
+// Profile is an allow list (empty = every tool) minus a deny list.
+type Profile struct {
+	Name  string
+	Allow []string
+	Deny  []string
+}
+
+// Allows reports whether the profile permits the tool.
+func (p Profile) Allows(tool string) bool {
+	if contains(p.Deny, tool) {
+		return false
+	}
+	return len(p.Allow) == 0 || contains(p.Allow, tool)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Set is the profiles in force for one run.
+type Set []Profile
+
+// Allows reports whether every profile in the set permits the tool.
+func (s Set) Allows(tool string) bool {
+	for _, p := range s {
+		if !p.Allows(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+// Unrestricted reports whether the set allows every tool.
+func (s Set) Unrestricted() bool {
+	for _, p := range s {
+		if len(p.Allow) > 0 || len(p.Deny) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the tools in names that the set allows, in order.
+func (s Set) Filter(names []string) []string {
+	var out []string
+	for _, n := range names {
+		if s.Allows(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// AllowedToolsFlag returns the --allowedTools value for the CLI path:
+// the allowed subset of known, comma-separated. It is empty when the set
+// is unrestricted, meaning the flag should be omitted.
+func (s Set) AllowedToolsFlag(known []string) string {
+	if s.Unrestricted() {
+		return ""
+	}
+	return strings.Join(s.Filter(known), ",")
+}
+
+// String names the profiles, e.g. "code-only + no-network".
+func (s Set) String() string {
+	names := make([]string, 0, len(s))
+	for _, p := range s {
+		if !contains(names, p.Name) {
+			names = append(names, p.Name)
+		}
+	}
+	return strings.Join(names, " + ")
+}
+
+// Resolver picks the profiles for a run from the repo config, plus any
+// chat selections made with /tools. Safe for concurrent use.
+type Resolver struct {
+	cfg      config.RepoToolProfiles
+	profiles map[string]Profile
+
+	mu        sync.Mutex
+	overrides map[string]string // channel → profile chosen in chat
+}
+
+// NewResolver builds a resolver, failing if the config refers to a
+// profile that is not defined.
+func NewResolver(cfg config.RepoToolProfiles) (*Resolver, error) {
+	profiles := make(map[string]Profile, len(Builtin)+len(cfg.Profiles))
+	for name, p := range Builtin {
+		profiles[name] = p
+	}
+	for name, p := range cfg.Profiles {
+		profiles[name] = Profile{Name: name, Allow: p.Allow, Deny: p.Deny}
+	}
+	r := &Resolver{cfg: cfg, profiles: profiles, overrides: make(map[string]string)}
+
+	var unknown []string
+	check := func(where, name string) {
+		if _, ok := profiles[name]; name != "" && !ok {
+			unknown = append(unknown, fmt.Sprintf("%s: %q", where, name))
+		}
+	}
+	check("default", cfg.Default)
+	for k, v := range cfg.Channels {
+		check("channels."+k, v)
+	}
+	for k, v := range cfg.UserRoles {
+		check("userRoles."+k, v)
+	}
+	for k, v := range cfg.Phases {
+		check("phases."+k, v)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown tool profile: %s", strings.Join(unknown, ", "))
+	}
+	return r, nil
+}
+
+// Names lists the defined profiles, sorted.
+func (r *Resolver) Names() []string {
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ChannelProfile returns the profile selected for the chat: a /tools
+// choice, then the config, then the default.
+func (r *Resolver) ChannelProfile(channel string) string {
+	r.mu.Lock()
+	name, ok := r.overrides[channel]
+	r.mu.Unlock()
+	if ok {
+		return name
+	}
+	if name := r.cfg.Channels[channel]; name != "" {
+		return name
+	}
+	if r.cfg.Default != "" {
+		return r.cfg.Default
+	}
+	return Full
+}
+
+// SetChannel selects a profile for the chat; an empty name drops the
+// selection back to the config.
+func (r *Resolver) SetChannel(channel, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == "" {
+		delete(r.overrides, channel)
+		return nil
+	}
+	if _, ok := r.profiles[name]; !ok {
+		return fmt.Errorf("unknown tool profile %q", name)
+	}
+	r.overrides[channel] = name
+	return nil
+}
+
+// Resolve returns the profiles in force for a run in channel, started by
+// userID, in the given workflow phase (agent role).
+func (r *Resolver) Resolve(channel, userID, phase string) Set {
+	set := Set{r.profiles[r.ChannelProfile(channel)]}
+	if role := r.cfg.Users[userID]; role != "" {
+		if name := r.cfg.UserRoles[role]; name != "" {
+			set = append(set, r.profiles[name])
+		}
+	}
+	if name := r.cfg.Phases[phase]; name != "" {
+		set = append(set, r.profiles[name])
+	}
+	return set
+}
+
+// Command returns /tools [profile|reset].
+func Command(r *Resolver) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "tools",
+		Usage:       "/tools [profile|reset]",
+		Description: "Show or switch this chat's tool permission profile",
+		Run: func(_ context.Context, inv chatcmd.Invocation) (string, error) {
+			arg := strings.TrimSpace(inv.RawArgs)
+			switch arg {
+			case "":
+				return fmt.Sprintf("Tool profile for this chat: *%s*. Available: %s.",
+					r.ChannelProfile(inv.Channel), strings.Join(r.Names(), ", ")), nil
+			case "reset":
+				_ = r.SetChannel(inv.Channel, "")
+				return fmt.Sprintf("Tool profile reset to *%s*.", r.ChannelProfile(inv.Channel)), nil
+			}
+			if err := r.SetChannel(inv.Channel, arg); err != nil {
+				return fmt.Sprintf("Unknown profile %q. Available: %s.", arg, strings.Join(r.Names(), ", ")), nil
+			}
+			return fmt.Sprintf("Tool profile for this chat set to *%s*.", arg), nil
+		},
+	}
+}
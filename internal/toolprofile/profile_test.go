@@ -0,0 +1,128 @@
+package toolprofile
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+type mockExecutor struct {
+	calls []string
+}
+
+func (m *mockExecutor) Execute(_ context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	m.calls = append(m.calls, call.Name)
+	return agent.ToolResult{ToolCallID: call.ID, Content: "ok"}, nil
+}
+
+func (m *mockExecutor) ListTools() []agent.ToolDefinition {
+	return []agent.ToolDefinition{{Name: "Read"}, {Name: "Write"}, {Name: "Bash"}, {Name: "WebFetch"}}
+}
+
+var known = []string{"Read", "Write", "Edit", "Glob", "Grep", "Bash", "WebFetch", "GitPush"}
+
+func testConfig() config.RepoToolProfiles {
+	return config.RepoToolProfiles{
+		Profiles: map[string]config.RepoToolProfile{
+			"docs": {Allow: []string{"Read", "Write", "Glob", "Grep"}},
+		},
+		Channels:  map[string]string{"C-docs": "docs"},
+		Users:     map[string]string{"U-ext": "contractor"},
+		UserRoles: map[string]string{"contractor": "no-network"},
+		Phases:    map[string]string{"reviewer": "read-only"},
+	}
+}
+
+func TestBuiltinProfiles(t *testing.T) {
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"full", ""},
+		{"read-only", "Read,Glob,Grep"},
+		{"code-only", "Read,Write,Edit,Glob,Grep,Bash"},
+		{"no-network", "Read,Write,Edit,Glob,Grep,Bash"},
+	}
+	for _, tt := range tests {
+		got := Set{Builtin[tt.profile]}.AllowedToolsFlag(known)
+		if got != tt.want {
+			t.Errorf("%s: --allowedTools = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestResolve_Intersects(t *testing.T) {
+	r, err := NewResolver(testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if set := r.Resolve("C-main", "U-dev", "coder"); !set.Unrestricted() {
+		t.Errorf("default run should be unrestricted, got %s", set)
+	}
+	set := r.Resolve("C-docs", "U-ext", "coder")
+	if got := set.AllowedToolsFlag(known); got != "Read,Write,Glob,Grep" {
+		t.Errorf("docs + contractor = %q", got)
+	}
+	if set.String() != "docs + no-network" {
+		t.Errorf("String = %q", set.String())
+	}
+	if got := r.Resolve("C-docs", "U-dev", "reviewer").Filter(known); strings.Join(got, ",") != "Read,Glob,Grep" {
+		t.Errorf("docs + reviewer phase = %v", got)
+	}
+}
+
+func TestNewResolver_UnknownProfile(t *testing.T) {
+	cfg := testConfig()
+	cfg.Phases["pm"] = "nope"
+	if _, err := NewResolver(cfg); err == nil || !strings.Contains(err.Error(), `phases.pm: "nope"`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	exec := &mockExecutor{}
+	tools := Wrap(exec, Set{Builtin["read-only"]})
+
+	defs := tools.ListTools()
+	if len(defs) != 1 || defs[0].Name != "Read" {
+		t.Errorf("ListTools = %v", defs)
+	}
+	res, err := tools.Execute(context.Background(), agent.ToolCall{ID: "1", Name: "Bash"})
+	if err != nil || !res.IsError || !strings.Contains(res.Content, "read-only") {
+		t.Errorf("Bash should be refused: %+v, %v", res, err)
+	}
+	if _, err := tools.Execute(context.Background(), agent.ToolCall{ID: "2", Name: "Read"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "Read" {
+		t.Errorf("calls = %v", exec.calls)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	r, _ := NewResolver(testConfig())
+	cmd := Command(r)
+	run := func(args string) string {
+		out, _ := cmd.Run(context.Background(), chatcmd.Invocation{Channel: "C-docs", RawArgs: args})
+		return out
+	}
+
+	if out := run(""); !strings.Contains(out, "*docs*") {
+		t.Errorf("show = %q", out)
+	}
+	run("read-only")
+	if r.ChannelProfile("C-docs") != "read-only" {
+		t.Error("selection not applied")
+	}
+	if out := run("bogus"); !strings.HasPrefix(out, "Unknown profile") {
+		t.Errorf("bogus = %q", out)
+	}
+	if out := run("reset"); !strings.Contains(out, "*docs*") {
+		t.Errorf("reset = %q", out)
+	}
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Approver asks a person to approve a tool call and blocks until they
+// answer, the request times out, or ctx is cancelled.
+type Approver interface {
+	RequestApproval(ctx context.Context, summary string) (bool, error)
+}
+
+// DefaultApprovalPatterns are the command words that need approval when
+// no patterns are configured: deletions, migrations, deploys. Force pushes
+// always need approval, since ClassifyBashCommand rates them Destructive.
+var DefaultApprovalPatterns = []string{
+	"rm", "migrate", "deploy",
+}
+
+// ApprovalPolicy decides which tool calls pause for approval. A call needs
+// approval when its tool is listed in Tools, when its shell command contains
+// one of Patterns as whole words, or when ClassifyToolRisk rates it
+// Destructive.
+type ApprovalPolicy struct {
+	Tools    []string // tool names that always need approval, e.g. "GitPush"
+	Patterns []string // case-insensitive command words; nil means DefaultApprovalPatterns
+}
+
+// Requires reports whether call needs approval and describes it for the
+// approval prompt.
+func (p ApprovalPolicy) Requires(call ToolCall) (summary string, ok bool) {
+	var args map[string]interface{}
+	json.Unmarshal(call.Arguments, &args) //nolint:errcheck // best-effort: bad args fail in the tool
+	command, _ := args["command"].(string)
+
+	summary = call.Name
+	if command != "" {
+		summary = fmt.Sprintf("%s: `%s`", call.Name, command)
+	}
+
+	for _, name := range p.Tools {
+		if name == call.Name {
+			return summary, true
+		}
+	}
+	if ClassifyToolRisk(call.Name, args) == Destructive {
+		return summary, true
+	}
+	if command == "" {
+		return "", false
+	}
+	patterns := p.Patterns
+	if patterns == nil {
+		patterns = DefaultApprovalPatterns
+	}
+	lower := strings.ToLower(command)
+	for _, pattern := range patterns {
+		if containsWords(lower, strings.ToLower(strings.TrimSpace(pattern))) {
+			return summary, true
+		}
+	}
+	return "", false
+}
+
+// containsWords reports whether pattern occurs in command with a word
+// boundary on both sides, so "rm" matches "rm -rf x" and "git rm x" but
+// not "npm run format" or "confirm".
+func containsWords(command, pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	for i := 0; ; {
+		j := strings.Index(command[i:], pattern)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(pattern)
+		if (start == 0 || !isWordByte(command[start-1])) && (end == len(command) || !isWordByte(command[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+type approvedKey struct{}
+
+// withApproved marks ctx as carrying a granted approval, so tools that
+// refuse destructive work on their own (Bash) let the approved call run.
+func withApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, approvedKey{}, true)
+}
+
+// isApproved reports whether the call running under ctx was approved.
+func isApproved(ctx context.Context) bool {
+	ok, _ := ctx.Value(approvedKey{}).(bool)
+	return ok
+}
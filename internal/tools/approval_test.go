@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fakeApprover struct {
+	answer    bool
+	summaries []string
+}
+
+func (f *fakeApprover) RequestApproval(_ context.Context, summary string) (bool, error) {
+	f.summaries = append(f.summaries, summary)
+	return f.answer, nil
+}
+
+func TestApprovalPolicy_Requires(t *testing.T) {
+	policy := ApprovalPolicy{Tools: []string{"GitPush"}}
+	tests := []struct {
+		call ToolCall
+		want bool
+	}{
+		{ToolCall{Name: "GitPush", Arguments: json.RawMessage(`{}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git push --force origin main"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git push origin main --force"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git push origin +main"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git push --force-with-lease origin main"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git push origin main"}`)}, false},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"rm -rf build"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"npm run migrate"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"go test ./..."}`)}, false},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git rm old.go"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"./scripts/deploy.sh prod"}`)}, true},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"npm run format && echo confirm done"}`)}, false},
+		{ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"go run ./cmd/platform"}`)}, false},
+		{ToolCall{Name: "Read", Arguments: json.RawMessage(`{"path":"deploy.md"}`)}, false},
+	}
+	for _, tt := range tests {
+		if _, got := policy.Requires(tt.call); got != tt.want {
+			t.Errorf("Requires(%s %s) = %v, want %v", tt.call.Name, tt.call.Arguments, got, tt.want)
+		}
+	}
+
+	narrow := ApprovalPolicy{Patterns: []string{}}
+	if _, got := narrow.Requires(ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"npm run migrate"}`)}); got {
+		t.Error("empty pattern list should only flag destructive commands")
+	}
+	if _, got := narrow.Requires(ToolCall{Name: "Bash", Arguments: json.RawMessage(`{"command":"git push origin +main"}`)}); !got {
+		t.Error("force pushes need approval whatever the patterns")
+	}
+}
+
+func TestRegistry_ApprovalGate(t *testing.T) {
+	approver := &fakeApprover{answer: false}
+	r := NewRegistry(RoleCoder, nil, WithApprovalGate(ApprovalPolicy{Tools: []string{"Deploy"}}, approver))
+	deploy := &mockTool{name: "Deploy", result: ToolResult{Content: "deployed"}}
+	r.Register(deploy)
+
+	result, err := r.Execute(context.Background(), ToolCall{ID: "1", Name: "Deploy", Arguments: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || !strings.Contains(result.Content, "rejected") || deploy.called != 0 {
+		t.Errorf("rejected call: result=%+v called=%d", result, deploy.called)
+	}
+
+	approver.answer = true
+	result, _ = r.Execute(context.Background(), ToolCall{ID: "2", Name: "Deploy", Arguments: json.RawMessage(`{}`)})
+	if result.IsError || deploy.called != 1 {
+		t.Errorf("approved call: result=%+v called=%d", result, deploy.called)
+	}
+	if len(approver.summaries) != 2 || approver.summaries[0] != "Deploy" {
+		t.Errorf("summaries = %v", approver.summaries)
+	}
+}
+
+func TestRegistry_ApprovedDestructiveBashRuns(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "junk.txt"), []byte("x"), 0o644)
+	sb, err := NewSandbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistry(RoleCoder, nil, WithApprovalGate(ApprovalPolicy{}, &fakeApprover{answer: true}))
+	r.Register(NewBashTool(sb))
+
+	result, _ := r.Execute(context.Background(), ToolCall{ID: "1", Name: "Bash", Arguments: json.RawMessage(`{"command":"rm -r junk.txt"}`)})
+	if result.IsError {
+		t.Fatalf("approved command refused: %s", result.Content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "junk.txt")); !os.IsNotExist(err) {
+		t.Error("approved rm did not run")
+	}
+}
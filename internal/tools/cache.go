@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheableTools are side-effect-free tools whose results depend
+// only on their arguments and the worktree contents.
+var DefaultCacheableTools = []string{"Read", "Glob", "Grep", "ListTargets"}
+
+// HeadFunc returns the current repo revision used to key cached results.
+type HeadFunc func(ctx context.Context) (string, error)
+
+// GitHead returns a HeadFunc that reads HEAD of the repo at dir.
+func GitHead(dir string) HeadFunc {
+	return func(ctx context.Context) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+}
+
+// ResultCache is a short-lived cache of idempotent tool results keyed on
+// tool name, normalized arguments, and repo HEAD. Any call to a tool that
+// is not cacheable (Write, Edit, Bash, ...) may change the worktree, so it
+// clears the cache.
+type ResultCache struct {
+	ttl       time.Duration
+	head      HeadFunc
+	cacheable map[string]bool
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	gen     uint64 // bumped by Invalidate
+	hits    int
+	misses  int
+}
+
+// CacheSlot is where a missed call's result is stored once it has run. It
+// remembers the cache generation at lookup, so a result that raced with an
+// invalidating write is dropped instead of cached.
+type CacheSlot struct {
+	key string
+	gen uint64
+}
+
+type cacheEntry struct {
+	result  ToolResult
+	expires time.Time
+}
+
+// NewResultCache creates a cache for the given tools (DefaultCacheableTools
+// if none). head may be nil, in which case only the TTL and invalidation
+// on writes apply.
+func NewResultCache(ttl time.Duration, head HeadFunc, tools ...string) *ResultCache {
+	if len(tools) == 0 {
+		tools = DefaultCacheableTools
+	}
+	c := &ResultCache{
+		ttl:       ttl,
+		head:      head,
+		cacheable: make(map[string]bool, len(tools)),
+		now:       time.Now,
+		entries:   make(map[string]cacheEntry),
+	}
+	for _, t := range tools {
+		c.cacheable[t] = true
+	}
+	return c
+}
+
+// Cacheable reports whether results of the named tool are cached.
+func (c *ResultCache) Cacheable(tool string) bool { return c.cacheable[tool] }
+
+// key builds the cache key, or "" when the call cannot be cached (e.g.
+// HEAD could not be read).
+func (c *ResultCache) key(ctx context.Context, call ToolCall) string {
+	args := []byte(call.Arguments)
+	var v any
+	if err := json.Unmarshal(call.Arguments, &v); err == nil {
+		args, _ = json.Marshal(v) // normalizes key order and whitespace
+	}
+
+	head := ""
+	if c.head != nil {
+		h, err := c.head(ctx)
+		if err != nil {
+			return ""
+		}
+		head = h
+	}
+
+	var b bytes.Buffer
+	b.WriteString(call.Name)
+	b.WriteByte(0)
+	b.Write(args)
+	b.WriteByte(0)
+	b.WriteString(head)
+	return b.String()
+}
+
+// Get returns a cached result for the call, if fresh. On a miss it returns
+// the slot to pass to Put after running the call; the zero slot means the
+// result cannot be cached.
+func (c *ResultCache) Get(ctx context.Context, call ToolCall) (ToolResult, CacheSlot, bool) {
+	if !c.cacheable[call.Name] {
+		return ToolResult{}, CacheSlot{}, false
+	}
+	key := c.key(ctx, call)
+	if key == "" {
+		return ToolResult{}, CacheSlot{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || c.now().After(e.expires) {
+		delete(c.entries, key)
+		c.misses++
+		return ToolResult{}, CacheSlot{key: key, gen: c.gen}, false
+	}
+	c.hits++
+	return e.result, CacheSlot{}, true
+}
+
+// Put stores a successful result in slot. Errors are never cached, and
+// neither is a result whose slot predates the latest Invalidate.
+func (c *ResultCache) Put(slot CacheSlot, result ToolResult) {
+	if slot.key == "" || result.IsError {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if slot.gen != c.gen {
+		return
+	}
+	c.entries[slot.key] = cacheEntry{result: result, expires: c.now().Add(c.ttl)}
+}
+
+// Invalidate drops all cached results.
+func (c *ResultCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+	c.gen++
+}
+
+// Stats returns the hit and miss counts.
+func (c *ResultCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func newCachedRegistry(t *testing.T, head HeadFunc) (*Registry, *mockTool, *mockTool, *ResultCache) {
+	t.Helper()
+	cache := NewResultCache(time.Minute, head)
+	r := NewRegistry(RoleCoder, nil, WithResultCache(cache))
+	read := &mockTool{name: "Read", result: ToolResult{Content: "package main"}}
+	write := &mockTool{name: "Write", result: ToolResult{Content: "ok"}}
+	r.Register(read)
+	r.Register(write)
+	return r, read, write, cache
+}
+
+func TestResultCache_RepeatedReadServedFromCache(t *testing.T) {
+	r, read, _, cache := newCachedRegistry(t, nil)
+	ctx := context.Background()
+
+	r.Execute(ctx, ToolCall{ID: "1", Name: "Read", Arguments: json.RawMessage(`{"path": "main.go", "limit": 10}`)})
+	res, err := r.Execute(ctx, ToolCall{ID: "2", Name: "Read", Arguments: json.RawMessage(`{"limit":10,"path":"main.go"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.called != 1 {
+		t.Errorf("equivalent args should hit the cache, tool called %d times", read.called)
+	}
+	if res.ToolCallID != "2" || res.Content != "package main" {
+		t.Errorf("cached result should carry the new call ID: %+v", res)
+	}
+	if hits, _ := cache.Stats(); hits != 1 {
+		t.Errorf("hits = %d", hits)
+	}
+
+	r.Execute(ctx, ToolCall{ID: "3", Name: "Read", Arguments: json.RawMessage(`{"path": "other.go"}`)})
+	if read.called != 2 {
+		t.Error("different args should miss")
+	}
+}
+
+func TestResultCache_WriteInvalidates(t *testing.T) {
+	r, read, _, _ := newCachedRegistry(t, nil)
+	ctx := context.Background()
+	args := json.RawMessage(`{"path": "main.go"}`)
+
+	r.Execute(ctx, ToolCall{ID: "1", Name: "Read", Arguments: args})
+	r.Execute(ctx, ToolCall{ID: "2", Name: "Write", Arguments: json.RawMessage(`{"path": "main.go", "content": "x"}`)})
+	r.Execute(ctx, ToolCall{ID: "3", Name: "Read", Arguments: args})
+	if read.called != 2 {
+		t.Errorf("read after write should re-execute, called %d", read.called)
+	}
+}
+
+func TestResultCache_ReadRacingWriteNotCached(t *testing.T) {
+	cache := NewResultCache(time.Minute, nil)
+	ctx := context.Background()
+	call := ToolCall{Name: "Read", Arguments: json.RawMessage(`{"path": "main.go"}`)}
+
+	// A Read looks up, then an Edit invalidates before the Read stores its
+	// (now stale) result.
+	_, slot, _ := cache.Get(ctx, call)
+	cache.Invalidate()
+	cache.Put(slot, ToolResult{Content: "old content"})
+	if _, _, ok := cache.Get(ctx, call); ok {
+		t.Error("result computed across an invalidation should be dropped")
+	}
+}
+
+func TestResultCache_HeadReadOncePerCall(t *testing.T) {
+	calls := 0
+	r, _, _, _ := newCachedRegistry(t, func(context.Context) (string, error) { calls++; return "abc", nil })
+	r.Execute(context.Background(), ToolCall{ID: "1", Name: "Read", Arguments: json.RawMessage(`{"path": "main.go"}`)})
+	if calls != 1 {
+		t.Errorf("HEAD read %d times for one call", calls)
+	}
+}
+
+func TestResultCache_HeadChangeMisses(t *testing.T) {
+	head := "abc"
+	r, read, _, _ := newCachedRegistry(t, func(context.Context) (string, error) { return head, nil })
+	ctx := context.Background()
+	args := json.RawMessage(`{"path": "main.go"}`)
+
+	r.Execute(ctx, ToolCall{ID: "1", Name: "Read", Arguments: args})
+	head = "def"
+	r.Execute(ctx, ToolCall{ID: "2", Name: "Read", Arguments: args})
+	if read.called != 2 {
+		t.Errorf("new HEAD should miss, called %d", read.called)
+	}
+}
+
+func TestResultCache_HeadErrorBypasses(t *testing.T) {
+	r, read, _, _ := newCachedRegistry(t, func(context.Context) (string, error) { return "", fmt.Errorf("not a repo") })
+	ctx := context.Background()
+	args := json.RawMessage(`{"path": "main.go"}`)
+
+	r.Execute(ctx, ToolCall{ID: "1", Name: "Read", Arguments: args})
+	r.Execute(ctx, ToolCall{ID: "2", Name: "Read", Arguments: args})
+	if read.called != 2 {
+		t.Error("unknown HEAD should disable caching")
+	}
+}
+
+func TestResultCache_ExpiryAndErrors(t *testing.T) {
+	cache := NewResultCache(time.Second, nil)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+	call := ToolCall{Name: "Grep", Arguments: json.RawMessage(`{"pattern": "TODO"}`)}
+
+	_, slot, _ := cache.Get(ctx, call)
+	cache.Put(slot, ToolResult{Content: "boom", IsError: true})
+	_, slot, ok := cache.Get(ctx, call)
+	if ok {
+		t.Error("errors should not be cached")
+	}
+
+	cache.Put(slot, ToolResult{Content: "a.go:1"})
+	if _, _, ok := cache.Get(ctx, call); !ok {
+		t.Error("fresh entry should hit")
+	}
+	now = now.Add(2 * time.Second)
+	if _, _, ok := cache.Get(ctx, call); ok {
+		t.Error("expired entry should miss")
+	}
+	if cache.Cacheable("Bash") {
+		t.Error("Bash must not be cacheable")
+	}
+}
+
+func TestGitHead(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=t@t", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	head, err := GitHead(dir)(context.Background())
+	if err != nil || len(head) != 40 {
+		t.Errorf("head = %q, err = %v", head, err)
+	}
+}
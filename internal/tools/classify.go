@@ -50,6 +50,9 @@ func ClassifyBashCommand(command string) RiskTier {
 			return Destructive
 		}
 	}
+	if isForcePush(cmd) {
+		return Destructive
+	}
 
 	// Check safe commands
 	for _, safe := range safeCommands {
@@ -66,13 +69,21 @@ func ClassifyBashCommand(command string) RiskTier {
 // For Bash tools, it analyzes the command string. For others, returns the tool's default tier.
 func ClassifyToolRisk(toolName string, args map[string]interface{}) RiskTier {
 	switch toolName {
-	case "Read", "Grep", "Glob":
+	case "Read", "Grep", "Glob", "LogsQuery", "MetricsQuery", "SQLQuery", "ListTargets", "DevServerLogs":
 		return Read
 	case "Write", "Edit":
 		return WriteLocal
 	case "GitCommit", "GitPush", "GHCreatePR", "SendMessage":
 		return WriteVisible
-	case "Bash":
+	case "HTTPRequest":
+		method, _ := args["method"].(string)
+		switch strings.ToUpper(method) {
+		case "", "GET", "HEAD":
+			return Read
+		default:
+			return WriteVisible
+		}
+	case "Bash", "StartDevServer":
 		if cmd, ok := args["command"].(string); ok {
 			return ClassifyBashCommand(cmd)
 		}
@@ -81,3 +92,60 @@ func ClassifyToolRisk(toolName string, args map[string]interface{}) RiskTier {
 		return WriteLocal
 	}
 }
+
+// isForcePush reports whether any git invocation in command is a push that
+// can overwrite remote history: --force, -f (alone or in a flag cluster
+// such as -fu), --force-with-lease, --mirror, or a "+"-prefixed refspec.
+// Flags are recognized wherever they appear after "push".
+func isForcePush(command string) bool {
+	for _, segment := range shellSegments(command) {
+		args := strings.Fields(segment)
+		i := gitSubcommand(args)
+		if i < 0 || args[i] != "push" {
+			continue
+		}
+		for _, arg := range args[i+1:] {
+			switch {
+			case arg == "--force", arg == "--mirror",
+				arg == "--force-with-lease", strings.HasPrefix(arg, "--force-with-lease="):
+				return true
+			case strings.HasPrefix(arg, "+") && len(arg) > 1:
+				return true
+			case len(arg) > 1 && arg[0] == '-' && arg[1] != '-' && strings.ContainsRune(arg[1:], 'f'):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shellSegments splits command at ;, &, | and newlines, roughly where the
+// shell starts a new command. Quoting is not honored, which can only add
+// segments.
+func shellSegments(command string) []string {
+	return strings.FieldsFunc(command, func(r rune) bool {
+		return r == ';' || r == '&' || r == '|' || r == '\n'
+	})
+}
+
+// gitSubcommand returns the index of the subcommand in a git argv,
+// skipping git's own options, or -1 if args is not a git invocation.
+func gitSubcommand(args []string) int {
+	i := 0
+	for i < len(args) && strings.Contains(args[i], "=") && !strings.HasPrefix(args[i], "-") {
+		i++ // leading VAR=value assignments
+	}
+	if i >= len(args) || (args[i] != "git" && !strings.HasSuffix(args[i], "/git")) {
+		return -1
+	}
+	for i++; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-C" || arg == "-c" || arg == "--git-dir" || arg == "--work-tree" || arg == "--namespace":
+			i++ // option with a separate value
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return i
+		}
+	}
+	return -1
+}
@@ -54,6 +54,32 @@ func TestClassifyBashCommand(t *testing.T) {
 	}
 }
 
+func TestIsForcePush(t *testing.T) {
+	tests := map[string]bool{
+		"git push --force origin main":                  true,
+		"git push origin main --force":                  true,
+		"git push -f origin main":                       true,
+		"git push -uf origin feature":                   true,
+		"git push origin +main":                         true,
+		"git push origin +refs/heads/x:refs/heads/main": true,
+		"git push --force-with-lease origin main":       true,
+		"git push origin main --force-with-lease=main":  true,
+		"git push --mirror backup":                      true,
+		"git -C repo push origin main --force":          true,
+		"go test ./... && git push origin main -f":      true,
+		"git push origin main":                          false,
+		"git push -u origin feature":                    false,
+		"git push --follow-tags origin main":            false,
+		"git commit -m '+1 --force'":                    false,
+		"echo git push --force":                         false,
+	}
+	for cmd, want := range tests {
+		if got := isForcePush(cmd); got != want {
+			t.Errorf("isForcePush(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
+
 func TestClassifyToolRisk(t *testing.T) {
 	tests := []struct {
 		name     string
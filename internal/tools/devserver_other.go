@@ -0,0 +1,12 @@
+//go:build !unix
+
+package tools
+
+import "os/exec"
+
+func setProcessGroup(*exec.Cmd) {}
+
+// signalGroup kills the process; process groups are unix-only.
+func signalGroup(cmd *exec.Cmd, _ bool) {
+	_ = cmd.Process.Kill()
+}
@@ -50,19 +50,51 @@ type Registry struct {
 	// idempotency: track executed tool-call IDs and their cached results
 	cacheMu sync.RWMutex
 	cache   map[string]ToolResult
+
+	// results caches idempotent tool output by name + args + HEAD (optional)
+	results *ResultCache
+
+	// approval pauses dangerous calls until a person answers (optional)
+	policy   ApprovalPolicy
+	approver Approver
+}
+
+// RegistryOption configures optional Registry behavior.
+type RegistryOption func(*Registry)
+
+// WithResultCache enables caching of idempotent tool results, so repeated
+// identical Read/Glob/Grep calls within a task return instantly.
+func WithResultCache(c *ResultCache) RegistryOption {
+	return func(r *Registry) {
+		r.results = c
+	}
+}
+
+// WithApprovalGate pauses every call that policy flags until approver gets
+// an answer. Rejected calls return an error result to the model instead of
+// running.
+func WithApprovalGate(policy ApprovalPolicy, approver Approver) RegistryOption {
+	return func(r *Registry) {
+		r.policy = policy
+		r.approver = approver
+	}
 }
 
 // NewRegistry creates a new tool registry for the given agent role.
-func NewRegistry(role Role, logger *slog.Logger) *Registry {
+func NewRegistry(role Role, logger *slog.Logger, opts ...RegistryOption) *Registry {
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Registry{
+	r := &Registry{
 		tools: make(map[string]Tool),
 		role:  role,
 		log:   logger,
 		cache: make(map[string]ToolResult),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Register adds a tool to the registry. Returns an error if a tool
@@ -155,10 +187,59 @@ func (r *Registry) Execute(ctx context.Context, call ToolCall) (ToolResult, erro
 		}, fmt.Errorf("unknown tool %q", call.Name)
 	}
 
+	// Pause dangerous calls for approval
+	if r.approver != nil {
+		if summary, needed := r.policy.Requires(call); needed {
+			r.log.Info("tool call awaiting approval", "tool", call.Name, "call_id", call.ID)
+			approved, err := r.approver.RequestApproval(ctx, summary)
+			if err != nil {
+				return ToolResult{
+					ToolCallID: call.ID,
+					Content:    fmt.Sprintf("approval for %s failed: %v", summary, err),
+					IsError:    true,
+				}, nil
+			}
+			if !approved {
+				r.log.Info("tool call rejected", "tool", call.Name, "call_id", call.ID)
+				return ToolResult{
+					ToolCallID: call.ID,
+					Content:    fmt.Sprintf("the user rejected %s; do not retry it, ask how to proceed instead", summary),
+					IsError:    true,
+				}, nil
+			}
+			ctx = withApproved(ctx)
+		}
+	}
+
+	// Serve repeated idempotent calls from the result cache; any other tool
+	// may modify the worktree, so it invalidates the cache.
+	var slot CacheSlot
+	if r.results != nil {
+		if !r.results.Cacheable(call.Name) {
+			r.results.Invalidate()
+		} else if cached, s, ok := r.results.Get(ctx, call); ok {
+			r.log.Info("tool result served from cache", "tool", call.Name, "call_id", call.ID)
+			cached.ToolCallID = call.ID
+			return cached, nil
+		} else {
+			slot = s
+		}
+	}
+
 	// Execute tool
 	result, err := t.Execute(ctx, call)
 	result.ToolCallID = call.ID
 
+	if r.results != nil {
+		if r.results.Cacheable(call.Name) {
+			if err == nil {
+				r.results.Put(slot, result)
+			}
+		} else {
+			r.results.Invalidate() // drop reads that raced with this write
+		}
+	}
+
 	// Cache result for idempotency (even errors, to avoid re-executing)
 	if call.ID != "" && err == nil {
 		r.cacheMu.Lock()
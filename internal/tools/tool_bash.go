@@ -59,9 +59,9 @@ func (t *BashTool) Execute(ctx context.Context, call ToolCall) (ToolResult, erro
 		return ToolResult{Content: "command is required", IsError: true}, nil
 	}
 
-	// Classify the command risk
+	// Classify the command risk; destructive commands run only once approved
 	risk := ClassifyBashCommand(args.Command)
-	if risk == Destructive {
+	if risk == Destructive && !isApproved(ctx) {
 		return ToolResult{
 			Content: fmt.Sprintf("command classified as DESTRUCTIVE: %q — requires user approval", args.Command),
 			IsError: true,
@@ -109,3 +109,21 @@ func (t *BashTool) Execute(ctx context.Context, call ToolCall) (ToolResult, erro
 
 	return ToolResult{Content: output}, nil
 }
+
+// RunCheck runs a repo check command (build, lint) in the sandbox root and
+// returns its combined output. It satisfies agent.CheckRunner for the
+// reviewer's static fast path.
+func (t *BashTool) RunCheck(ctx context.Context, command string) (string, error) {
+	args, err := json.Marshal(bashArgs{Command: command})
+	if err != nil {
+		return "", err
+	}
+	res, err := t.Execute(ctx, ToolCall{Name: t.Name(), Arguments: args})
+	if err != nil {
+		return "", err
+	}
+	if res.IsError {
+		return res.Content, fmt.Errorf("check %q failed", command)
+	}
+	return res.Content, nil
+}
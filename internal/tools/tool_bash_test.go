@@ -137,3 +137,15 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestBashTool_RunCheck(t *testing.T) {
+	sb, _ := NewSandbox(t.TempDir())
+	tool := NewBashTool(sb)
+
+	if out, err := tool.RunCheck(context.Background(), "echo ok"); err != nil || !containsStr(out, "ok") {
+		t.Errorf("passing check: %q, %v", out, err)
+	}
+	if out, err := tool.RunCheck(context.Background(), "echo broken >&2; exit 2"); err == nil || !containsStr(out, "broken") {
+		t.Errorf("failing check: %q, %v", out, err)
+	}
+}
@@ -0,0 +1,473 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Dev Server Tools ---
+
+const (
+	defaultDevServerWait = 30 * time.Second
+	maxDevServerWait     = 180 * time.Second
+	devServerLogLimit    = 64 * 1024
+	devServerGrace       = 5 * time.Second
+)
+
+// portPattern finds the listening port in typical dev server banners
+// ("http://localhost:5173", "listening on :8080", "port 3000").
+var portPattern = regexp.MustCompile(`(?i)(?:https?://(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1?\])|listening on|\bport)\s*:?\s*(\d{2,5})\b`)
+
+// DetectPort returns the first port mentioned in dev server output, or 0.
+func DetectPort(output string) int {
+	m := portPattern.FindStringSubmatch(output)
+	if m == nil {
+		return 0
+	}
+	p, err := strconv.Atoi(m[1])
+	if err != nil || p > 65535 {
+		return 0
+	}
+	return p
+}
+
+// ringLog keeps the most recent output of a background process.
+type ringLog struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (l *ringLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf = append(l.buf, p...)
+	if over := len(l.buf) - devServerLogLimit; over > 0 {
+		l.buf = l.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (l *ringLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return string(l.buf)
+}
+
+// tail returns the last n lines.
+func (l *ringLog) tail(n int) string {
+	lines := strings.Split(strings.TrimRight(l.String(), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DevServer is a running background dev command.
+type DevServer struct {
+	Name    string
+	Command string
+	PID     int
+	Port    int
+	Started time.Time
+
+	cmd  *exec.Cmd
+	logs *ringLog
+	done chan struct{}
+	err  error // exit error, valid after done is closed
+}
+
+// exited reports whether the process has terminated.
+func (s *DevServer) exited() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// DevServerManager tracks dev servers started by an agent so they can be
+// inspected and cleaned up at task end. One manager is shared by the
+// StartDevServer, StopDevServer, and DevServerLogs tools.
+type DevServerManager struct {
+	sandbox *Sandbox
+
+	mu       sync.Mutex
+	servers  map[string]*DevServer
+	starting map[string]bool // names reserved by a Start in progress
+}
+
+// NewDevServerManager creates a manager that runs commands in the sandbox root.
+func NewDevServerManager(sandbox *Sandbox) *DevServerManager {
+	return &DevServerManager{
+		sandbox:  sandbox,
+		servers:  make(map[string]*DevServer),
+		starting: make(map[string]bool),
+	}
+}
+
+// Start launches command in the background and waits until its port accepts
+// connections, the process exits, or wait elapses. port 0 means detect it
+// from the output.
+func (m *DevServerManager) Start(ctx context.Context, name, command string, port int, wait time.Duration) (*DevServer, error) {
+	// Reserve the name under the lock so two concurrent Starts cannot both
+	// launch a process under it.
+	m.mu.Lock()
+	if existing, ok := m.servers[name]; ok && !existing.exited() {
+		m.mu.Unlock()
+		return existing, fmt.Errorf("dev server %q is already running (pid %d)", name, existing.PID)
+	}
+	if m.starting[name] {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("dev server %q is already starting", name)
+	}
+	m.starting[name] = true
+	m.mu.Unlock()
+
+	logs := &ringLog{}
+	// Not tied to ctx: the server must outlive the tool call.
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = m.sandbox.Root
+	cmd.Stdout = logs
+	cmd.Stderr = logs
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		m.mu.Lock()
+		delete(m.starting, name)
+		m.mu.Unlock()
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	srv := &DevServer{
+		Name:    name,
+		Command: command,
+		PID:     cmd.Process.Pid,
+		Port:    port,
+		Started: time.Now(),
+		cmd:     cmd,
+		logs:    logs,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		srv.err = cmd.Wait()
+		close(srv.done)
+	}()
+
+	// srv is published only after waitReady settles Port, so List and Get
+	// never race with the port detection. The name stays reserved until
+	// then.
+	err := m.waitReady(ctx, srv, wait)
+	m.mu.Lock()
+	delete(m.starting, name)
+	m.servers[name] = srv
+	m.mu.Unlock()
+	return srv, err
+}
+
+// waitReady polls until the server's port accepts connections. It may set
+// srv.Port, so srv must not be published yet.
+func (m *DevServerManager) waitReady(ctx context.Context, srv *DevServer, wait time.Duration) error {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	tick := time.NewTicker(200 * time.Millisecond)
+	defer tick.Stop()
+
+	for {
+		if srv.Port == 0 {
+			srv.Port = DetectPort(srv.logs.String())
+		}
+		if srv.Port != 0 {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(srv.Port)), 200*time.Millisecond)
+			if err == nil {
+				conn.Close()
+				return nil
+			}
+		}
+
+		select {
+		case <-srv.done:
+			return fmt.Errorf("process exited before becoming ready: %v", srv.err)
+		case <-deadline.C:
+			if srv.Port == 0 {
+				return fmt.Errorf("no listening port detected after %s (still running)", wait)
+			}
+			return fmt.Errorf("port %d not accepting connections after %s (still running)", srv.Port, wait)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+		}
+	}
+}
+
+// Stop terminates a dev server and its children. Returns the final logs.
+func (m *DevServerManager) Stop(name string) (string, error) {
+	m.mu.Lock()
+	srv, ok := m.servers[name]
+	delete(m.servers, name)
+	m.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("no dev server named %q", name)
+	}
+	m.terminate(srv)
+	return srv.logs.tail(20), nil
+}
+
+// StopAll terminates every tracked dev server. Register it with
+// agent.WithTeardown so servers stop when the run ends.
+func (m *DevServerManager) StopAll() {
+	m.mu.Lock()
+	servers := make([]*DevServer, 0, len(m.servers))
+	for _, s := range m.servers {
+		servers = append(servers, s)
+	}
+	m.servers = make(map[string]*DevServer)
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range servers {
+		wg.Add(1)
+		go func(s *DevServer) {
+			defer wg.Done()
+			m.terminate(s)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// terminate sends SIGTERM to the process group, then SIGKILL after a grace period.
+func (m *DevServerManager) terminate(srv *DevServer) {
+	if srv.exited() {
+		return
+	}
+	signalGroup(srv.cmd, false)
+	select {
+	case <-srv.done:
+	case <-time.After(devServerGrace):
+		signalGroup(srv.cmd, true)
+		<-srv.done
+	}
+}
+
+// Get returns a tracked dev server by name.
+func (m *DevServerManager) Get(name string) (*DevServer, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.servers[name]
+	return s, ok
+}
+
+// List returns the tracked dev servers sorted by name.
+func (m *DevServerManager) List() []*DevServer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*DevServer, 0, len(m.servers))
+	for _, s := range m.servers {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// StartDevServerTool launches the repo's dev command in the background.
+type StartDevServerTool struct {
+	manager *DevServerManager
+}
+
+// NewStartDevServerTool creates a StartDevServer tool.
+func NewStartDevServerTool(manager *DevServerManager) *StartDevServerTool {
+	return &StartDevServerTool{manager: manager}
+}
+
+func (t *StartDevServerTool) Name() string { return "StartDevServer" }
+
+func (t *StartDevServerTool) Description() string {
+	return "Start a long-running dev server (e.g. `npm run dev`, `go run ./cmd/server`) in the background. " +
+		"Waits until it accepts connections and returns the URL and recent logs. Servers are stopped at task end."
+}
+
+func (t *StartDevServerTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"command": {
+				"type": "string",
+				"description": "The dev command to run"
+			},
+			"name": {
+				"type": "string",
+				"description": "Name to refer to this server (default \"dev\")"
+			},
+			"port": {
+				"type": "integer",
+				"description": "Port the server listens on; detected from output if omitted"
+			},
+			"wait_seconds": {
+				"type": "integer",
+				"description": "How long to wait for the server to become ready (default 30, max 180)"
+			}
+		},
+		"required": ["command"]
+	}`)
+}
+
+func (t *StartDevServerTool) RiskTier() RiskTier { return WriteLocal }
+
+func (t *StartDevServerTool) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Command     string `json:"command"`
+		Name        string `json:"name"`
+		Port        int    `json:"port"`
+		WaitSeconds int    `json:"wait_seconds"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+	}
+	if args.Command == "" {
+		return ToolResult{ToolCallID: call.ID, Content: "command is required", IsError: true}, nil
+	}
+	if ClassifyBashCommand(args.Command) == Destructive && !isApproved(ctx) {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("command classified as DESTRUCTIVE: %q — requires user approval", args.Command), IsError: true}, nil
+	}
+	if args.Name == "" {
+		args.Name = "dev"
+	}
+	wait := defaultDevServerWait
+	if args.WaitSeconds > 0 {
+		wait = min(time.Duration(args.WaitSeconds)*time.Second, maxDevServerWait)
+	}
+
+	srv, err := t.manager.Start(ctx, args.Name, args.Command, args.Port, wait)
+	if err != nil {
+		content := fmt.Sprintf("dev server %q: %s", args.Name, err)
+		if srv != nil {
+			content += "\n\nRecent output:\n" + srv.logs.tail(30)
+		}
+		return ToolResult{ToolCallID: call.ID, Content: content, IsError: true}, nil
+	}
+
+	return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf(
+		"dev server %q running (pid %d) at http://localhost:%d\n\nRecent output:\n%s",
+		srv.Name, srv.PID, srv.Port, srv.logs.tail(15))}, nil
+}
+
+// StopDevServerTool stops a dev server started by StartDevServer.
+type StopDevServerTool struct {
+	manager *DevServerManager
+}
+
+// NewStopDevServerTool creates a StopDevServer tool.
+func NewStopDevServerTool(manager *DevServerManager) *StopDevServerTool {
+	return &StopDevServerTool{manager: manager}
+}
+
+func (t *StopDevServerTool) Name() string { return "StopDevServer" }
+
+func (t *StopDevServerTool) Description() string {
+	return "Stop a dev server started with StartDevServer and return its final log lines."
+}
+
+func (t *StopDevServerTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {
+				"type": "string",
+				"description": "Server name (default \"dev\")"
+			}
+		}
+	}`)
+}
+
+func (t *StopDevServerTool) RiskTier() RiskTier { return WriteLocal }
+
+func (t *StopDevServerTool) Execute(_ context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Name string `json:"name"`
+	}
+	if len(call.Arguments) > 0 {
+		if err := json.Unmarshal(call.Arguments, &args); err != nil {
+			return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+		}
+	}
+	if args.Name == "" {
+		args.Name = "dev"
+	}
+
+	logs, err := t.manager.Stop(args.Name)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("dev server %q stopped\n\nFinal output:\n%s", args.Name, logs)}, nil
+}
+
+// DevServerLogsTool returns recent output from a running dev server.
+type DevServerLogsTool struct {
+	manager *DevServerManager
+}
+
+// NewDevServerLogsTool creates a DevServerLogs tool.
+func NewDevServerLogsTool(manager *DevServerManager) *DevServerLogsTool {
+	return &DevServerLogsTool{manager: manager}
+}
+
+func (t *DevServerLogsTool) Name() string { return "DevServerLogs" }
+
+func (t *DevServerLogsTool) Description() string {
+	return "Show recent output from a dev server started with StartDevServer."
+}
+
+func (t *DevServerLogsTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {
+				"type": "string",
+				"description": "Server name (default \"dev\")"
+			},
+			"lines": {
+				"type": "integer",
+				"description": "Number of lines (default 50)"
+			}
+		}
+	}`)
+}
+
+func (t *DevServerLogsTool) RiskTier() RiskTier { return Read }
+
+func (t *DevServerLogsTool) Execute(_ context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Name  string `json:"name"`
+		Lines int    `json:"lines"`
+	}
+	if len(call.Arguments) > 0 {
+		if err := json.Unmarshal(call.Arguments, &args); err != nil {
+			return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+		}
+	}
+	if args.Name == "" {
+		args.Name = "dev"
+	}
+	if args.Lines <= 0 {
+		args.Lines = 50
+	}
+
+	srv, ok := t.manager.Get(args.Name)
+	if !ok {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("no dev server named %q", args.Name), IsError: true}, nil
+	}
+	status := "running"
+	if srv.exited() {
+		status = fmt.Sprintf("exited (%v)", srv.err)
+	}
+	return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("dev server %q %s\n\n%s", srv.Name, status, srv.logs.tail(args.Lines))}, nil
+}
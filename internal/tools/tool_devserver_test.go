@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDetectPort(t *testing.T) {
+	tests := []struct {
+		output string
+		want   int
+	}{
+		{"  VITE ready\n  ➜  Local:   http://localhost:5173/", 5173},
+		{"Server listening on :8080", 8080},
+		{"started server on 0.0.0.0:3000, url: http://0.0.0.0:3000", 3000},
+		{"Listening on port 4000", 4000},
+		{"compiling...", 0},
+	}
+	for _, tt := range tests {
+		if got := DetectPort(tt.output); got != tt.want {
+			t.Errorf("DetectPort(%q) = %d, want %d", tt.output, got, tt.want)
+		}
+	}
+}
+
+// listen opens a port in the test process so the readiness probe succeeds.
+func listen(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func newDevServerTools(t *testing.T) (*DevServerManager, *StartDevServerTool, *StopDevServerTool, *DevServerLogsTool) {
+	t.Helper()
+	sb, err := NewSandbox(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewDevServerManager(sb)
+	t.Cleanup(m.StopAll)
+	return m, NewStartDevServerTool(m), NewStopDevServerTool(m), NewDevServerLogsTool(m)
+}
+
+func TestDevServer_StartDetectsPortAndStops(t *testing.T) {
+	m, start, stop, logs := newDevServerTools(t)
+	port := listen(t)
+
+	args, _ := json.Marshal(map[string]any{
+		"command":      fmt.Sprintf("echo 'Listening on port %d'; sleep 30", port),
+		"wait_seconds": 5,
+	})
+	result, err := start.Execute(context.Background(), ToolCall{ID: "d-1", Arguments: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.Content)
+	}
+	if !strings.Contains(result.Content, fmt.Sprintf("http://localhost:%d", port)) {
+		t.Errorf("missing URL: %s", result.Content)
+	}
+
+	srv, ok := m.Get("dev")
+	if !ok || srv.PID == 0 {
+		t.Fatal("server should be tracked")
+	}
+
+	result, _ = logs.Execute(context.Background(), ToolCall{})
+	if result.IsError || !strings.Contains(result.Content, "running") {
+		t.Errorf("logs: %+v", result)
+	}
+
+	result, _ = stop.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"name": "dev"}`)})
+	if result.IsError {
+		t.Fatalf("stop failed: %s", result.Content)
+	}
+	select {
+	case <-srv.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process should have exited after stop")
+	}
+	if len(m.List()) != 0 {
+		t.Error("stopped server should be untracked")
+	}
+}
+
+func TestDevServer_ExitsEarly(t *testing.T) {
+	_, start, _, _ := newDevServerTools(t)
+
+	result, _ := start.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"command": "echo 'missing module foo'; exit 1", "wait_seconds": 5}`)})
+	if !result.IsError || !strings.Contains(result.Content, "exited before becoming ready") || !strings.Contains(result.Content, "missing module foo") {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestDevServer_AlreadyRunning(t *testing.T) {
+	m, _, _, _ := newDevServerTools(t)
+	port := listen(t)
+
+	cmd := fmt.Sprintf("echo 'port %d'; sleep 30", port)
+	if _, err := m.Start(context.Background(), "api", cmd, 0, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Start(context.Background(), "api", cmd, 0, 5*time.Second); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("expected already-running error, got %v", err)
+	}
+
+	m.StopAll()
+	if len(m.List()) != 0 {
+		t.Error("StopAll should untrack all servers")
+	}
+}
+
+func TestDevServer_ConcurrentStartSameName(t *testing.T) {
+	m, _, _, _ := newDevServerTools(t)
+	defer m.StopAll()
+	port := listen(t)
+	cmd := fmt.Sprintf("echo 'port %d'; sleep 30", port)
+
+	var wg sync.WaitGroup
+	var started atomic.Int32
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Start(context.Background(), "api", cmd, 0, 5*time.Second); err == nil {
+				started.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := started.Load(); n != 1 {
+		t.Errorf("%d starts succeeded, want 1", n)
+	}
+}
+
+func TestDevServer_Validation(t *testing.T) {
+	_, start, stop, _ := newDevServerTools(t)
+
+	result, _ := start.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{}`)})
+	if !result.IsError {
+		t.Error("missing command should error")
+	}
+	result, _ = start.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"command": "sudo npm run dev"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "DESTRUCTIVE") {
+		t.Errorf("destructive command should be refused: %+v", result)
+	}
+	result, _ = start.Execute(withApproved(context.Background()), ToolCall{Arguments: json.RawMessage(`{"command": "sudo false", "wait_seconds": 1}`)})
+	if strings.Contains(result.Content, "DESTRUCTIVE") {
+		t.Errorf("approved command should run: %+v", result)
+	}
+	result, _ = stop.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"name": "nope"}`)})
+	if !result.IsError {
+		t.Error("stopping unknown server should error")
+	}
+}
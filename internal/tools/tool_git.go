@@ -116,12 +116,37 @@ func (t *GitPushTool) Execute(ctx context.Context, call ToolCall) (ToolResult, e
 
 // GHCreatePRTool creates a GitHub pull request.
 type GHCreatePRTool struct {
-	pr PRCreator
+	pr        PRCreator
+	onCreated func(ctx context.Context, pr CreatedPR) // optional
+}
+
+// CreatedPR describes a pull request opened by GHCreatePR.
+type CreatedPR struct {
+	URL   string
+	Title string
+	Base  string
+	Head  string
+}
+
+// GHCreatePROption configures a GHCreatePRTool.
+type GHCreatePROption func(*GHCreatePRTool)
+
+// WithPRCreatedHook calls fn after each successful PR creation, e.g. to
+// start an automatic review. fn runs before the tool returns, so it should
+// hand long work to a goroutine.
+func WithPRCreatedHook(fn func(ctx context.Context, pr CreatedPR)) GHCreatePROption {
+	return func(t *GHCreatePRTool) {
+		t.onCreated = fn
+	}
 }
 
 // NewGHCreatePRTool creates a GHCreatePR tool.
-func NewGHCreatePRTool(pr PRCreator) *GHCreatePRTool {
-	return &GHCreatePRTool{pr: pr}
+func NewGHCreatePRTool(pr PRCreator, opts ...GHCreatePROption) *GHCreatePRTool {
+	t := &GHCreatePRTool{pr: pr}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 func (t *GHCreatePRTool) Name() string        { return "GHCreatePR" }
@@ -174,5 +199,9 @@ func (t *GHCreatePRTool) Execute(ctx context.Context, call ToolCall) (ToolResult
 		return ToolResult{Content: fmt.Sprintf("PR creation failed: %v", err), IsError: true}, nil
 	}
 
+	if t.onCreated != nil {
+		t.onCreated(ctx, CreatedPR{URL: url, Title: args.Title, Base: args.Base, Head: args.Head})
+	}
+
 	return ToolResult{Content: fmt.Sprintf("PR created: %s", url)}, nil
 }
@@ -170,6 +170,27 @@ func TestGHCreatePRTool_Success(t *testing.T) {
 	}
 }
 
+func TestGHCreatePRTool_CreatedHook(t *testing.T) {
+	var got []CreatedPR
+	hook := WithPRCreatedHook(func(_ context.Context, pr CreatedPR) { got = append(got, pr) })
+
+	tool := NewGHCreatePRTool(&mockPRCreator{url: "https://github.com/org/repo/pull/42"}, hook)
+	tool.Execute(context.Background(), ToolCall{
+		Arguments: json.RawMessage(`{"title": "feat: add login", "body": "d", "base": "main", "head": "codebutler/login"}`),
+	})
+	if len(got) != 1 || got[0].URL != "https://github.com/org/repo/pull/42" || got[0].Head != "codebutler/login" {
+		t.Errorf("hook got %+v", got)
+	}
+
+	failing := NewGHCreatePRTool(&mockPRCreator{err: fmt.Errorf("auth error")}, hook)
+	failing.Execute(context.Background(), ToolCall{
+		Arguments: json.RawMessage(`{"title": "feat", "body": "b", "base": "main", "head": "feat"}`),
+	})
+	if len(got) != 1 {
+		t.Error("hook should not run when creation fails")
+	}
+}
+
 func TestGHCreatePRTool_MissingFields(t *testing.T) {
 	tool := NewGHCreatePRTool(&mockPRCreator{})
 	result, _ := tool.Execute(context.Background(), ToolCall{
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// --- HTTPRequest Tool ---
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	maxHTTPTimeout     = 120 * time.Second
+	maxHTTPBodyRead    = 1 << 20
+	maxHTTPOutput      = 20000
+)
+
+// httpMethods are the methods HTTPRequest accepts.
+var httpMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+}
+
+// HostAllowlist matches request hosts against per-repo patterns.
+type HostAllowlist []string
+
+// Allows reports whether u's host matches an entry. Patterns without a port
+// match any port; "*.example.com" matches subdomains but not the apex.
+func (a HostAllowlist) Allows(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	hostPort := strings.ToLower(u.Host)
+	for _, pattern := range a {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if _, _, err := net.SplitHostPort(pattern); err == nil {
+			if pattern == hostPort {
+				return true
+			}
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if pattern == host {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPRequestTool lets agents call allowlisted HTTP endpoints: internal
+// APIs, deploy webhooks, or endpoints they just implemented.
+type HTTPRequestTool struct {
+	allowlist HostAllowlist
+	client    *http.Client
+}
+
+// NewHTTPRequestTool creates an HTTPRequest tool restricted to allowedHosts.
+// Redirects are followed only to allowlisted hosts.
+func NewHTTPRequestTool(allowedHosts []string) *HTTPRequestTool {
+	t := &HTTPRequestTool{allowlist: HostAllowlist(allowedHosts)}
+	t.client = &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+			if !t.allowlist.Allows(req.URL) {
+				return fmt.Errorf("redirect to %s is not allowlisted", req.URL.Host)
+			}
+			return nil
+		},
+	}
+	return t
+}
+
+func (t *HTTPRequestTool) Name() string { return "HTTPRequest" }
+
+func (t *HTTPRequestTool) Description() string {
+	return fmt.Sprintf("Send an HTTP request (GET, HEAD, POST, PUT, PATCH, DELETE) with custom headers and body "+
+		"to an allowlisted host (%s). Returns the status, key headers, and body.", strings.Join(t.allowlist, ", "))
+}
+
+func (t *HTTPRequestTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"method": {
+				"type": "string",
+				"description": "HTTP method (default GET)"
+			},
+			"url": {
+				"type": "string",
+				"description": "Absolute http(s) URL on an allowlisted host"
+			},
+			"headers": {
+				"type": "object",
+				"description": "Request headers",
+				"additionalProperties": {"type": "string"}
+			},
+			"body": {
+				"type": "string",
+				"description": "Request body"
+			},
+			"timeout_seconds": {
+				"type": "integer",
+				"description": "Request timeout (default 30, max 120)"
+			}
+		},
+		"required": ["url"]
+	}`)
+}
+
+// RiskTier is WriteVisible because POST/PUT can trigger external effects;
+// ClassifyToolRisk lowers GET/HEAD calls to Read.
+func (t *HTTPRequestTool) RiskTier() RiskTier { return WriteVisible }
+
+func (t *HTTPRequestTool) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Method         string            `json:"method"`
+		URL            string            `json:"url"`
+		Headers        map[string]string `json:"headers"`
+		Body           string            `json:"body"`
+		TimeoutSeconds int               `json:"timeout_seconds"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+	}
+
+	method := strings.ToUpper(args.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !httpMethods[method] {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("unsupported method %q", args.Method), IsError: true}, nil
+	}
+
+	u, err := url.Parse(args.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ToolResult{ToolCallID: call.ID, Content: "url must be an absolute http(s) URL", IsError: true}, nil
+	}
+	if !t.allowlist.Allows(u) {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf(
+			"host %s is not allowlisted (allowed: %s); add it to http.allowedHosts in .codebutler/config.json",
+			u.Host, strings.Join(t.allowlist, ", ")), IsError: true}, nil
+	}
+
+	timeout := defaultHTTPTimeout
+	if args.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(args.TimeoutSeconds)*time.Second, maxHTTPTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if args.Body != "" {
+		body = strings.NewReader(args.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("build request: %s", err), IsError: true}, nil
+	}
+	for k, v := range args.Headers {
+		req.Header.Set(k, v)
+	}
+	if args.Body != "" && req.Header.Get("Content-Type") == "" && json.Valid([]byte(args.Body)) {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("request failed: %s", err), IsError: true}, nil
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodyRead))
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("read response: %s", err), IsError: true}, nil
+	}
+
+	return ToolResult{
+		ToolCallID: call.ID,
+		Content:    formatHTTPResponse(resp, data),
+		IsError:    resp.StatusCode >= 400,
+	}, nil
+}
+
+// formatHTTPResponse renders status, a few useful headers, and the body.
+func formatHTTPResponse(resp *http.Response, body []byte) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %s\n", resp.Proto, resp.Status))
+
+	var keys []string
+	for _, k := range []string{"Content-Type", "Location", "Retry-After", "X-Request-Id"} {
+		if resp.Header.Get(k) != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf("%s: %s\n", k, resp.Header.Get(k)))
+	}
+
+	b.WriteString("\n")
+	content := string(body)
+	if len(content) > maxHTTPOutput {
+		cut := maxHTTPOutput
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		content = content[:cut] + "\n\n... (response truncated)"
+	}
+	b.WriteString(content)
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHostAllowlist(t *testing.T) {
+	list := HostAllowlist{"api.internal", "*.example.com", "localhost:8080"}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://api.internal/v1", true},
+		{"https://API.internal:9443/v1", true},
+		{"https://hooks.example.com/deploy", true},
+		{"https://example.com/", false},
+		{"https://evil-example.com/", false},
+		{"http://localhost:8080/health", true},
+		{"http://localhost:9090/health", false},
+		{"https://api.internal.evil.com/", false},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := list.Allows(u); got != tt.want {
+			t.Errorf("Allows(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPRequestTool_Post(t *testing.T) {
+	var gotBody, gotType, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody, gotType, gotAuth = string(b), r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"deploy":"queued"}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	tool := NewHTTPRequestTool([]string{u.Host})
+
+	args, _ := json.Marshal(map[string]any{
+		"method":  "post",
+		"url":     srv.URL + "/deploy",
+		"headers": map[string]string{"Authorization": "Bearer t"},
+		"body":    `{"ref":"main"}`,
+	})
+	result, err := tool.Execute(context.Background(), ToolCall{ID: "h-1", Arguments: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.Content)
+	}
+	if gotBody != `{"ref":"main"}` || gotType != "application/json" || gotAuth != "Bearer t" {
+		t.Errorf("server got body=%q type=%q auth=%q", gotBody, gotType, gotAuth)
+	}
+	if !strings.Contains(result.Content, "202 Accepted") || !strings.Contains(result.Content, `{"deploy":"queued"}`) {
+		t.Errorf("unexpected output: %s", result.Content)
+	}
+}
+
+func TestHTTPRequestTool_RejectsHost(t *testing.T) {
+	tool := NewHTTPRequestTool([]string{"api.internal"})
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"url": "https://attacker.com/x"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "not allowlisted") {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestHTTPRequestTool_Validation(t *testing.T) {
+	tool := NewHTTPRequestTool([]string{"api.internal"})
+	for _, args := range []string{
+		`{"url": "file:///etc/passwd"}`,
+		`{"url": "/relative"}`,
+		`{"url": "https://api.internal", "method": "TRACE"}`,
+	} {
+		result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(args)})
+		if !result.IsError {
+			t.Errorf("expected error for %s", args)
+		}
+	}
+}
+
+func TestHTTPRequestTool_RedirectOffAllowlist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://attacker.com/steal", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	tool := NewHTTPRequestTool([]string{u.Host})
+	args, _ := json.Marshal(map[string]string{"url": srv.URL})
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: args})
+	if !result.IsError || !strings.Contains(result.Content, "not allowlisted") {
+		t.Errorf("redirect should be blocked, got %+v", result)
+	}
+}
+
+func TestHTTPRequestTool_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	tool := NewHTTPRequestTool([]string{u.Host})
+	args, _ := json.Marshal(map[string]string{"url": srv.URL})
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: args})
+	if !result.IsError || !strings.Contains(result.Content, "500") {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestFormatHTTPResponse_TruncatesOnRuneBoundary(t *testing.T) {
+	// "é" is two bytes, so the limit falls inside a rune.
+	body := []byte("x" + strings.Repeat("é", maxHTTPOutput))
+	resp := &http.Response{Proto: "HTTP/1.1", Status: "200 OK", Header: http.Header{}}
+	out := formatHTTPResponse(resp, body)
+	if !utf8.ValidString(out) {
+		t.Error("truncated response is not valid UTF-8")
+	}
+	if !strings.HasSuffix(out, "(response truncated)") {
+		t.Errorf("missing truncation marker: %q", out[len(out)-40:])
+	}
+}
+
+func TestClassifyToolRisk_HTTPRequest(t *testing.T) {
+	if ClassifyToolRisk("HTTPRequest", map[string]interface{}{"method": "GET"}) != Read {
+		t.Error("GET should be READ")
+	}
+	if ClassifyToolRisk("HTTPRequest", map[string]interface{}{}) != Read {
+		t.Error("default method should be READ")
+	}
+	if ClassifyToolRisk("HTTPRequest", map[string]interface{}{"method": "post"}) != WriteVisible {
+		t.Error("POST should be WRITE_VISIBLE")
+	}
+}
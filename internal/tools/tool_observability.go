@@ -0,0 +1,357 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Observability Tools ---
+
+// LogQuery selects log lines from an observability backend.
+type LogQuery struct {
+	Service string    // service/app name, mapped to the backend's label or log group
+	Filter  string    // backend-native filter expression (optional)
+	Start   time.Time // inclusive
+	End     time.Time // exclusive
+	Limit   int
+}
+
+// LogLine is a single log entry returned by a LogSource.
+type LogLine struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service,omitempty"`
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message"`
+}
+
+// LogSource queries recent logs from a backend (Loki, CloudWatch, Datadog).
+type LogSource interface {
+	QueryLogs(ctx context.Context, q LogQuery) ([]LogLine, error)
+}
+
+// MetricQuery selects a metric time series from an observability backend.
+type MetricQuery struct {
+	Query string // backend-native query (PromQL, Datadog metric query)
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+// MetricPoint is a single sample in a metric series.
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricSeries is one labeled series returned by a MetricSource.
+type MetricSeries struct {
+	Name   string        `json:"name"`
+	Points []MetricPoint `json:"points"`
+}
+
+// MetricSource queries metric time series from a backend.
+type MetricSource interface {
+	QueryMetrics(ctx context.Context, q MetricQuery) ([]MetricSeries, error)
+}
+
+const (
+	defaultObservabilityWindow = 15 * time.Minute
+	maxObservabilityWindow     = 24 * time.Hour
+	defaultLogLimit            = 100
+	maxLogLimit                = 1000
+	maxObservabilityOutput     = 30000
+)
+
+// observabilityRange resolves the since/until arguments shared by the
+// observability tools. since is a duration ago ("15m", "2h"); until defaults
+// to now. The window is capped to keep queries cheap.
+func observabilityRange(now time.Time, since, until string) (time.Time, time.Time, error) {
+	end := now
+	if until != "" {
+		d, err := time.ParseDuration(until)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid until %q: %w", until, err)
+		}
+		end = now.Add(-d)
+	}
+	window := defaultObservabilityWindow
+	if since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		window = d
+	}
+	if window <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("since must be positive")
+	}
+	if window > maxObservabilityWindow {
+		window = maxObservabilityWindow
+	}
+	start := now.Add(-window)
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("since must be earlier than until")
+	}
+	return start, end, nil
+}
+
+// pickBackend resolves a named backend, defaulting to the only one configured.
+func pickBackend[T any](backends map[string]T, name string) (T, string, error) {
+	var zero T
+	if name != "" {
+		b, ok := backends[name]
+		if !ok {
+			return zero, "", fmt.Errorf("unknown backend %q (configured: %s)", name, strings.Join(backendNames(backends), ", "))
+		}
+		return b, name, nil
+	}
+	if len(backends) == 1 {
+		for n, b := range backends {
+			return b, n, nil
+		}
+	}
+	if len(backends) == 0 {
+		return zero, "", fmt.Errorf("no observability backends configured")
+	}
+	return zero, "", fmt.Errorf("backend is required (configured: %s)", strings.Join(backendNames(backends), ", "))
+}
+
+func backendNames[T any](backends map[string]T) []string {
+	names := make([]string, 0, len(backends))
+	for n := range backends {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LogsQueryTool lets agents read recent logs for a service without SSH.
+type LogsQueryTool struct {
+	backends map[string]LogSource
+	now      func() time.Time
+}
+
+// NewLogsQueryTool creates a LogsQuery tool over the named backends
+// (e.g. "loki", "cloudwatch", "datadog").
+func NewLogsQueryTool(backends map[string]LogSource) *LogsQueryTool {
+	return &LogsQueryTool{backends: backends, now: time.Now}
+}
+
+func (t *LogsQueryTool) Name() string { return "LogsQuery" }
+
+func (t *LogsQueryTool) Description() string {
+	return fmt.Sprintf("Fetch recent logs for a service from an observability backend (%s). "+
+		"Use to investigate errors in production without SSH.", strings.Join(backendNames(t.backends), ", "))
+}
+
+func (t *LogsQueryTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"service": {
+				"type": "string",
+				"description": "Service name (e.g. checkout)"
+			},
+			"filter": {
+				"type": "string",
+				"description": "Backend-native filter, e.g. a LogQL line filter, CloudWatch filter pattern, or Datadog search query"
+			},
+			"since": {
+				"type": "string",
+				"description": "How far back to look, as a duration (default 15m, max 24h)"
+			},
+			"until": {
+				"type": "string",
+				"description": "End of the window as a duration ago (default now)"
+			},
+			"limit": {
+				"type": "integer",
+				"description": "Maximum log lines to return (default 100, max 1000)"
+			},
+			"backend": {
+				"type": "string",
+				"description": "Backend name; required only when several are configured"
+			}
+		},
+		"required": ["service"]
+	}`)
+}
+
+func (t *LogsQueryTool) RiskTier() RiskTier { return Read }
+
+func (t *LogsQueryTool) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Service string `json:"service"`
+		Filter  string `json:"filter"`
+		Since   string `json:"since"`
+		Until   string `json:"until"`
+		Limit   int    `json:"limit"`
+		Backend string `json:"backend"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+	}
+	if args.Service == "" {
+		return ToolResult{ToolCallID: call.ID, Content: "service is required", IsError: true}, nil
+	}
+
+	source, name, err := pickBackend(t.backends, args.Backend)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	start, end, err := observabilityRange(t.now(), args.Since, args.Until)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultLogLimit
+	}
+	if args.Limit > maxLogLimit {
+		args.Limit = maxLogLimit
+	}
+
+	lines, err := source.QueryLogs(ctx, LogQuery{
+		Service: args.Service,
+		Filter:  args.Filter,
+		Start:   start,
+		End:     end,
+		Limit:   args.Limit,
+	})
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("%s query failed: %s", name, err), IsError: true}, nil
+	}
+	if len(lines) == 0 {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("no logs for %s between %s and %s",
+			args.Service, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))}, nil
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d log line(s) from %s:\n", len(lines), name))
+	for _, l := range lines {
+		line := l.Time.UTC().Format("2006-01-02T15:04:05.000Z") + " "
+		if l.Level != "" {
+			line += "[" + l.Level + "] "
+		}
+		line += strings.TrimRight(l.Message, "\n") + "\n"
+		if b.Len()+len(line) > maxObservabilityOutput {
+			b.WriteString("... (output truncated)\n")
+			break
+		}
+		b.WriteString(line)
+	}
+	return ToolResult{ToolCallID: call.ID, Content: b.String()}, nil
+}
+
+// MetricsQueryTool lets agents read metric series (error rates, latency).
+type MetricsQueryTool struct {
+	backends map[string]MetricSource
+	now      func() time.Time
+}
+
+// NewMetricsQueryTool creates a MetricsQuery tool over the named backends
+// (e.g. "prometheus", "datadog").
+func NewMetricsQueryTool(backends map[string]MetricSource) *MetricsQueryTool {
+	return &MetricsQueryTool{backends: backends, now: time.Now}
+}
+
+func (t *MetricsQueryTool) Name() string { return "MetricsQuery" }
+
+func (t *MetricsQueryTool) Description() string {
+	return fmt.Sprintf("Query metric time series from an observability backend (%s). "+
+		"Returns min/max/last per series plus the samples.", strings.Join(backendNames(t.backends), ", "))
+}
+
+func (t *MetricsQueryTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "Backend-native metric query (PromQL for prometheus, metric query for datadog)"
+			},
+			"since": {
+				"type": "string",
+				"description": "How far back to look, as a duration (default 15m, max 24h)"
+			},
+			"step": {
+				"type": "string",
+				"description": "Resolution as a duration (default: window/60)"
+			},
+			"backend": {
+				"type": "string",
+				"description": "Backend name; required only when several are configured"
+			}
+		},
+		"required": ["query"]
+	}`)
+}
+
+func (t *MetricsQueryTool) RiskTier() RiskTier { return Read }
+
+func (t *MetricsQueryTool) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Query   string `json:"query"`
+		Since   string `json:"since"`
+		Step    string `json:"step"`
+		Backend string `json:"backend"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+	}
+	if args.Query == "" {
+		return ToolResult{ToolCallID: call.ID, Content: "query is required", IsError: true}, nil
+	}
+
+	source, name, err := pickBackend(t.backends, args.Backend)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	start, end, err := observabilityRange(t.now(), args.Since, "")
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	step := end.Sub(start) / 60
+	if args.Step != "" {
+		if step, err = time.ParseDuration(args.Step); err != nil || step <= 0 {
+			return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid step %q", args.Step), IsError: true}, nil
+		}
+	}
+
+	series, err := source.QueryMetrics(ctx, MetricQuery{Query: args.Query, Start: start, End: end, Step: step})
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("%s query failed: %s", name, err), IsError: true}, nil
+	}
+	if len(series) == 0 {
+		return ToolResult{ToolCallID: call.ID, Content: "no data"}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d series from %s:\n", len(series), name))
+	for _, s := range series {
+		b.WriteString(fmt.Sprintf("\n%s\n", s.Name))
+		if len(s.Points) == 0 {
+			b.WriteString("  (no samples)\n")
+			continue
+		}
+		lo, hi := s.Points[0].Value, s.Points[0].Value
+		for _, p := range s.Points {
+			lo = min(lo, p.Value)
+			hi = max(hi, p.Value)
+		}
+		b.WriteString(fmt.Sprintf("  min=%g max=%g last=%g\n", lo, hi, s.Points[len(s.Points)-1].Value))
+		for _, p := range s.Points {
+			if b.Len() > maxObservabilityOutput {
+				b.WriteString("  ... (output truncated)\n")
+				break
+			}
+			b.WriteString(fmt.Sprintf("  %s %g\n", p.Time.UTC().Format("15:04:05"), p.Value))
+		}
+	}
+	return ToolResult{ToolCallID: call.ID, Content: b.String()}, nil
+}
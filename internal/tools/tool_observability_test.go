@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockLogSource struct {
+	lines []LogLine
+	err   error
+	got   LogQuery
+}
+
+func (m *mockLogSource) QueryLogs(_ context.Context, q LogQuery) ([]LogLine, error) {
+	m.got = q
+	return m.lines, m.err
+}
+
+type mockMetricSource struct {
+	series []MetricSeries
+	got    MetricQuery
+}
+
+func (m *mockMetricSource) QueryMetrics(_ context.Context, q MetricQuery) ([]MetricSeries, error) {
+	m.got = q
+	return m.series, nil
+}
+
+var obsNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestLogsQueryTool_Success(t *testing.T) {
+	src := &mockLogSource{lines: []LogLine{
+		{Time: obsNow.Add(-time.Minute), Level: "error", Message: "payment timeout"},
+		{Time: obsNow.Add(-2 * time.Minute), Message: "retrying"},
+	}}
+	tool := NewLogsQueryTool(map[string]LogSource{"loki": src})
+	tool.now = func() time.Time { return obsNow }
+
+	result, err := tool.Execute(context.Background(), ToolCall{
+		ID:        "l-1",
+		Arguments: json.RawMessage(`{"service": "checkout", "filter": "timeout", "since": "1h", "limit": 5000}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.Content)
+	}
+	if src.got.Service != "checkout" || src.got.Filter != "timeout" || src.got.Limit != maxLogLimit {
+		t.Errorf("unexpected query: %+v", src.got)
+	}
+	if !src.got.Start.Equal(obsNow.Add(-time.Hour)) || !src.got.End.Equal(obsNow) {
+		t.Errorf("unexpected window: %s - %s", src.got.Start, src.got.End)
+	}
+	if strings.Index(result.Content, "retrying") > strings.Index(result.Content, "payment timeout") {
+		t.Error("lines should be in chronological order")
+	}
+	if !strings.Contains(result.Content, "[error] payment timeout") {
+		t.Errorf("missing level: %s", result.Content)
+	}
+}
+
+func TestLogsQueryTool_Validation(t *testing.T) {
+	tool := NewLogsQueryTool(map[string]LogSource{"loki": &mockLogSource{}, "datadog": &mockLogSource{}})
+
+	tests := []struct {
+		name, args, want string
+	}{
+		{"missing service", `{}`, "service is required"},
+		{"ambiguous backend", `{"service": "api"}`, "backend is required"},
+		{"unknown backend", `{"service": "api", "backend": "splunk"}`, "unknown backend"},
+		{"bad since", `{"service": "api", "backend": "loki", "since": "yesterday"}`, "invalid since"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(tt.args)})
+			if !result.IsError || !strings.Contains(result.Content, tt.want) {
+				t.Errorf("got %+v, want error containing %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogsQueryTool_BackendError(t *testing.T) {
+	tool := NewLogsQueryTool(map[string]LogSource{"loki": &mockLogSource{err: fmt.Errorf("HTTP 401")}})
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"service": "api"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "loki query failed: HTTP 401") {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestLogsQueryTool_WindowCapped(t *testing.T) {
+	start, end, err := observabilityRange(obsNow, "72h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end.Sub(start) != maxObservabilityWindow {
+		t.Errorf("window = %s, want %s", end.Sub(start), maxObservabilityWindow)
+	}
+	if _, _, err := observabilityRange(obsNow, "10m", "20m"); err == nil {
+		t.Error("expected error when until is before since")
+	}
+}
+
+func TestMetricsQueryTool_Success(t *testing.T) {
+	src := &mockMetricSource{series: []MetricSeries{{
+		Name: `errors{route="/pay"}`,
+		Points: []MetricPoint{
+			{Time: obsNow.Add(-2 * time.Minute), Value: 1},
+			{Time: obsNow.Add(-time.Minute), Value: 9},
+			{Time: obsNow, Value: 4},
+		},
+	}}}
+	tool := NewMetricsQueryTool(map[string]MetricSource{"prometheus": src})
+	tool.now = func() time.Time { return obsNow }
+
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"query": "errors", "since": "1h"}`)})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.Content)
+	}
+	if src.got.Step != time.Minute {
+		t.Errorf("default step = %s, want 1m", src.got.Step)
+	}
+	if !strings.Contains(result.Content, "min=1 max=9 last=4") {
+		t.Errorf("missing summary: %s", result.Content)
+	}
+}
+
+func TestObservabilityTools_RiskTier(t *testing.T) {
+	if NewLogsQueryTool(nil).RiskTier() != Read || NewMetricsQueryTool(nil).RiskTier() != Read {
+		t.Error("observability tools should be read-only")
+	}
+}
@@ -16,15 +16,41 @@ type SendMessageTool struct {
 	sender    MessageSender
 	channelID string
 	threadTS  string
+	validate  func(text string) error // optional mention check
+	onSent    func(ctx context.Context, text string)
+}
+
+// SendMessageOption configures a SendMessageTool.
+type SendMessageOption func(*SendMessageTool)
+
+// WithMentionValidator rejects messages whose mentions fail validate (e.g.
+// router.MentionRegistry.Validate). The error goes back to the model so it
+// can pick an existing target instead of posting a dead handoff.
+func WithMentionValidator(validate func(text string) error) SendMessageOption {
+	return func(t *SendMessageTool) {
+		t.validate = validate
+	}
+}
+
+// WithMessageSentHook calls fn after each message is posted, e.g. to parse
+// its mentions and record the handoff in a worktree.PhaseStore.
+func WithMessageSentHook(fn func(ctx context.Context, text string)) SendMessageOption {
+	return func(t *SendMessageTool) {
+		t.onSent = fn
+	}
 }
 
 // NewSendMessageTool creates a SendMessage tool bound to a specific thread.
-func NewSendMessageTool(sender MessageSender, channelID, threadTS string) *SendMessageTool {
-	return &SendMessageTool{
+func NewSendMessageTool(sender MessageSender, channelID, threadTS string, opts ...SendMessageOption) *SendMessageTool {
+	t := &SendMessageTool{
 		sender:    sender,
 		channelID: channelID,
 		threadTS:  threadTS,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 func (t *SendMessageTool) Name() string        { return "SendMessage" }
@@ -56,10 +82,18 @@ func (t *SendMessageTool) Execute(ctx context.Context, call ToolCall) (ToolResul
 	if args.Text == "" {
 		return ToolResult{Content: "text is required", IsError: true}, nil
 	}
+	if t.validate != nil {
+		if err := t.validate(args.Text); err != nil {
+			return ToolResult{Content: fmt.Sprintf("message not sent: %v", err), IsError: true}, nil
+		}
+	}
 
 	if err := t.sender.SendMessage(ctx, t.channelID, t.threadTS, args.Text); err != nil {
 		return ToolResult{Content: fmt.Sprintf("failed to send message: %v", err), IsError: true}, nil
 	}
+	if t.onSent != nil {
+		t.onSent(ctx, args.Text)
+	}
 
 	return ToolResult{Content: "Message sent."}, nil
 }
@@ -3,7 +3,9 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -71,3 +73,40 @@ func TestSendMessageTool_Properties(t *testing.T) {
 		t.Errorf("risk tier: got %v", tool.RiskTier())
 	}
 }
+
+func TestSendMessageTool_MentionValidator(t *testing.T) {
+	sender := &mockMessageSender{}
+	tool := NewSendMessageTool(sender, "C1", "T1", WithMentionValidator(func(text string) error {
+		if strings.Contains(text, "@codebutler.qa") {
+			return errors.New("unknown delegation target @codebutler.qa")
+		}
+		return nil
+	}))
+
+	args, _ := json.Marshal(map[string]string{"text": "@codebutler.qa run the tests"})
+	result, _ := tool.Execute(context.Background(), ToolCall{ID: "1", Arguments: args})
+	if !result.IsError || !strings.Contains(result.Content, "unknown delegation target") {
+		t.Errorf("result = %+v", result)
+	}
+	if len(sender.sent) != 0 {
+		t.Error("invalid handoff should not be posted")
+	}
+}
+
+func TestSendMessageTool_SentHook(t *testing.T) {
+	var hooked []string
+	tool := NewSendMessageTool(&mockMessageSender{}, "C1", "T1", WithMessageSentHook(func(_ context.Context, text string) {
+		hooked = append(hooked, text)
+	}))
+	args, _ := json.Marshal(map[string]string{"text": "@codebutler.coder plan approved"})
+	tool.Execute(context.Background(), ToolCall{ID: "1", Arguments: args})
+
+	failing := NewSendMessageTool(&mockMessageSender{err: errors.New("down")}, "C1", "T1", WithMessageSentHook(func(_ context.Context, text string) {
+		hooked = append(hooked, text)
+	}))
+	failing.Execute(context.Background(), ToolCall{ID: "2", Arguments: args})
+
+	if len(hooked) != 1 || hooked[0] != "@codebutler.coder plan approved" {
+		t.Errorf("hook calls = %v, want one for the posted message", hooked)
+	}
+}
@@ -0,0 +1,310 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// --- SQLQuery Tool ---
+
+// SQLResult is the output of a read-only query.
+type SQLResult struct {
+	Columns   []string
+	Rows      [][]string
+	Truncated bool // more rows existed than the limit
+}
+
+// SQLDatabase runs read-only queries. Implementations enforce the row limit
+// and timeout, and should run inside a read-only transaction.
+type SQLDatabase interface {
+	QueryReadOnly(ctx context.Context, query string) (*SQLResult, error)
+}
+
+// forbiddenSQLKeywords may not appear anywhere outside string literals, even
+// in an otherwise read-only statement (e.g. SELECT ... INTO, CTEs with DML).
+var forbiddenSQLKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true,
+	"REPLACE": true, "DROP": true, "ALTER": true, "CREATE": true, "TRUNCATE": true,
+	"RENAME": true, "GRANT": true, "REVOKE": true, "COPY": true, "CALL": true,
+	"EXEC": true, "EXECUTE": true, "INTO": true, "LOCK": true, "SET": true,
+	"VACUUM": true, "ATTACH": true, "DETACH": true, "PRAGMA": true, "LOAD": true,
+	"COMMIT": true, "ROLLBACK": true, "BEGIN": true,
+}
+
+// sqlQuoting is one dialect's idea of where a string literal ends.
+type sqlQuoting struct {
+	backslash bool // MySQL: \ escapes the next character in '...' and "..."
+	dollar    bool // Postgres: $$...$$ and $tag$...$tag$ strings, and E'...' escapes
+}
+
+// sqlQuotings are the readings a query must pass. The database's dialect
+// is not known here, and a literal that ends in one dialect can run on in
+// another, hiding a keyword from one scan; requiring every reading to pass
+// closes that gap at the cost of rejecting some unusual literals.
+var sqlQuotings = []sqlQuoting{
+	{},                // standard SQL (SQLite)
+	{dollar: true},    // Postgres
+	{backslash: true}, // MySQL
+}
+
+// ValidateReadOnlySQL rejects anything other than a single SELECT (or WITH
+// ... SELECT) statement. Comments and string literals are ignored when
+// scanning for keywords, under the quoting rules of standard SQL, Postgres
+// and MySQL alike. MySQL executable comments (/*! ... */) are rejected
+// outright, since MySQL runs their contents.
+func ValidateReadOnlySQL(query string) error {
+	for _, q := range sqlQuotings {
+		if err := validateReadOnlySQL(query, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateReadOnlySQL(query string, q sqlQuoting) error {
+	words, statements, err := sqlKeywords(query, q)
+	if err != nil {
+		return err
+	}
+	if statements > 1 {
+		return fmt.Errorf("only a single statement is allowed")
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("query is empty")
+	}
+	if words[0] != "SELECT" && words[0] != "WITH" {
+		return fmt.Errorf("only SELECT queries are allowed, got %s", words[0])
+	}
+	for _, w := range words {
+		if forbiddenSQLKeywords[w] {
+			return fmt.Errorf("keyword %s is not allowed in read-only queries", w)
+		}
+	}
+	return nil
+}
+
+// sqlKeywords returns the upper-cased bare words of a query (skipping
+// comments, string literals, and quoted identifiers) and the number of
+// non-empty statements separated by semicolons.
+func sqlKeywords(query string, q sqlQuoting) ([]string, int, error) {
+	var words []string
+	statements := 0
+	inStatement := false
+	r := []rune(query)
+	begin := func() {
+		if !inStatement {
+			inStatement = true
+			statements++
+		}
+	}
+
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		switch {
+		case c == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			if i+2 < len(r) && r[i+2] == '!' {
+				// MySQL runs the body of /*! ... */ as SQL.
+				return nil, 0, fmt.Errorf("executable comments (/*!) are not allowed")
+			}
+			end := strings.Index(string(r[i+2:]), "*/")
+			if end < 0 {
+				return nil, 0, fmt.Errorf("unterminated comment")
+			}
+			i += 2 + len([]rune(string(r[i+2:])[:end])) + 1
+		case q.dollar && (c == 'E' || c == 'e') && i+1 < len(r) && r[i+1] == '\'' && (i == 0 || !isSQLIdentRune(r[i-1])):
+			j, ok := skipQuoted(r, i+1, true)
+			if !ok {
+				return nil, 0, fmt.Errorf("unterminated quote")
+			}
+			i = j
+			begin()
+		case q.dollar && c == '$' && (i == 0 || !isSQLIdentRune(r[i-1])) && dollarTag(r, i) != "":
+			tag := dollarTag(r, i)
+			n := len([]rune(tag))
+			end := strings.Index(string(r[i+n:]), tag)
+			if end < 0 {
+				return nil, 0, fmt.Errorf("unterminated dollar quote")
+			}
+			i += n + len([]rune(string(r[i+n:])[:end])) + n - 1
+			begin()
+		case c == '\'' || c == '"' || c == '`':
+			j, ok := skipQuoted(r, i, q.backslash && c != '`')
+			if !ok {
+				return nil, 0, fmt.Errorf("unterminated quote")
+			}
+			i = j
+			begin()
+		case c == ';':
+			inStatement = false
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(r) && (unicode.IsLetter(r[j]) || unicode.IsDigit(r[j]) || r[j] == '_') {
+				j++
+			}
+			words = append(words, strings.ToUpper(string(r[i:j])))
+			i = j - 1
+			begin()
+		case !unicode.IsSpace(c):
+			begin()
+		}
+	}
+	return words, statements, nil
+}
+
+// skipQuoted returns the index of the quote closing the literal that opens
+// at r[start]. A doubled quote is an escaped quote; with backslash, so is
+// any character after a backslash.
+func skipQuoted(r []rune, start int, backslash bool) (int, bool) {
+	quote := r[start]
+	for j := start + 1; j < len(r); j++ {
+		switch {
+		case backslash && r[j] == '\\':
+			j++
+		case r[j] == quote:
+			if j+1 < len(r) && r[j+1] == quote { // doubled quote escape
+				j++
+				continue
+			}
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// dollarTag returns the Postgres dollar-quote delimiter ("$$" or
+// "$name$") starting at r[i], or "" if there is none.
+func dollarTag(r []rune, i int) string {
+	j := i + 1
+	for j < len(r) && (unicode.IsLetter(r[j]) || r[j] == '_' || j > i+1 && unicode.IsDigit(r[j])) {
+		j++
+	}
+	if j < len(r) && r[j] == '$' {
+		return string(r[i : j+1])
+	}
+	return ""
+}
+
+func isSQLIdentRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '$'
+}
+
+const (
+	maxSQLCellLen = 200
+	maxSQLOutput  = 20000
+)
+
+// SQLQueryTool lets agents answer data questions with read-only SQL.
+type SQLQueryTool struct {
+	databases map[string]SQLDatabase
+}
+
+// NewSQLQueryTool creates a SQLQuery tool over the named databases. Only
+// pass databases the current role is allowed to query.
+func NewSQLQueryTool(databases map[string]SQLDatabase) *SQLQueryTool {
+	return &SQLQueryTool{databases: databases}
+}
+
+func (t *SQLQueryTool) Name() string { return "SQLQuery" }
+
+func (t *SQLQueryTool) Description() string {
+	names := make([]string, 0, len(t.databases))
+	for n := range t.databases {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("Run a read-only SQL SELECT against a configured database (%s). "+
+		"Results are row-limited and truncated; aggregate in SQL instead of fetching raw rows.", strings.Join(names, ", "))
+}
+
+func (t *SQLQueryTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"database": {
+				"type": "string",
+				"description": "Database name; required only when several are configured"
+			},
+			"query": {
+				"type": "string",
+				"description": "A single SELECT statement"
+			}
+		},
+		"required": ["query"]
+	}`)
+}
+
+func (t *SQLQueryTool) RiskTier() RiskTier { return Read }
+
+func (t *SQLQueryTool) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Database string `json:"database"`
+		Query    string `json:"query"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+	}
+	if err := ValidateReadOnlySQL(args.Query); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: "query rejected: " + err.Error(), IsError: true}, nil
+	}
+
+	db, name, err := pickBackend(t.databases, args.Database)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: strings.Replace(err.Error(), "backend", "database", 1), IsError: true}, nil
+	}
+
+	res, err := db.QueryReadOnly(ctx, args.Query)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("%s query failed: %s", name, err), IsError: true}, nil
+	}
+	return ToolResult{ToolCallID: call.ID, Content: FormatSQLResult(res)}, nil
+}
+
+// FormatSQLResult renders rows as a pipe-separated table, truncating long
+// cells and the overall output.
+func FormatSQLResult(res *SQLResult) string {
+	if len(res.Rows) == 0 {
+		return "(0 rows)"
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(res.Columns, " | ") + "\n")
+	shown := 0
+	for _, row := range res.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if len(cell) > maxSQLCellLen {
+				cut := maxSQLCellLen
+				for cut > 0 && !utf8.RuneStart(cell[cut]) {
+					cut--
+				}
+				cell = cell[:cut] + "..."
+			}
+			cells[i] = strings.ReplaceAll(cell, "\n", " ")
+		}
+		line := strings.Join(cells, " | ") + "\n"
+		if b.Len()+len(line) > maxSQLOutput {
+			break
+		}
+		b.WriteString(line)
+		shown++
+	}
+
+	switch {
+	case shown < len(res.Rows):
+		b.WriteString(fmt.Sprintf("(%d of %d rows shown; output truncated)", shown, len(res.Rows)))
+	case res.Truncated:
+		b.WriteString(fmt.Sprintf("(%d rows; row limit reached, more rows exist)", shown))
+	default:
+		b.WriteString(fmt.Sprintf("(%d rows)", shown))
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+type mockSQLDatabase struct {
+	result  *SQLResult
+	err     error
+	queries []string
+}
+
+func (m *mockSQLDatabase) QueryReadOnly(_ context.Context, query string) (*SQLResult, error) {
+	m.queries = append(m.queries, query)
+	return m.result, m.err
+}
+
+func TestValidateReadOnlySQL(t *testing.T) {
+	tests := []struct {
+		query string
+		ok    bool
+	}{
+		{"SELECT count(*) FROM users WHERE created_at >= now() - interval '1 day'", true},
+		{"select id from users;", true},
+		{"WITH recent AS (SELECT * FROM users) SELECT count(*) FROM recent", true},
+		{"SELECT 'DROP TABLE users' AS note", true},
+		{"SELECT 1 -- DELETE FROM users", true},
+		{"SELECT \"update\" FROM t", true},
+		{"SELECT 1 /* ; DROP */ FROM t", true},
+		{"DELETE FROM users", false},
+		{"SELECT 1; DROP TABLE users", false},
+		{"SELECT * INTO backup FROM users", false},
+		{"WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", false},
+		{"EXPLAIN ANALYZE DELETE FROM users", false},
+		{"", false},
+		{"SELECT 'unterminated", false},
+		// Literals that end differently across dialects must pass every reading.
+		{`SELECT 'x\'', DELETE FROM users --'`, false},
+		{`SELECT "x\"", DROP TABLE users --"`, false},
+		{`SELECT $$'$$; DELETE FROM users; --'`, false},
+		{`SELECT $q$'$q$; DELETE FROM users; --'`, false},
+		{`SELECT E'\''; DELETE FROM users; --'`, false},
+		{`SELECT $$unterminated`, false},
+		{`SELECT 'a\\b', "c" FROM t`, true},
+		{`SELECT price$ FROM t`, true},
+		{`SELECT $1 FROM t`, true},
+		{`SELECT 1 /*! ; DROP TABLE users */`, false},
+		{`SELECT 1 /*!50000 INTO OUTFILE '/tmp/x' */ FROM t`, false},
+		{`SELECT '/*!' FROM t`, true},
+	}
+	for _, tt := range tests {
+		err := ValidateReadOnlySQL(tt.query)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateReadOnlySQL(%q) = %v, want ok=%v", tt.query, err, tt.ok)
+		}
+	}
+}
+
+func TestSQLQueryTool_Success(t *testing.T) {
+	db := &mockSQLDatabase{result: &SQLResult{
+		Columns: []string{"day", "signups"},
+		Rows:    [][]string{{"2026-03-01", "42"}},
+	}}
+	tool := NewSQLQueryTool(map[string]SQLDatabase{"analytics": db})
+
+	result, err := tool.Execute(context.Background(), ToolCall{
+		ID:        "sql-1",
+		Arguments: json.RawMessage(`{"query": "SELECT day, signups FROM daily_signups"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.Content)
+	}
+	if result.Content != "day | signups\n2026-03-01 | 42\n(1 rows)" {
+		t.Errorf("got %q", result.Content)
+	}
+}
+
+func TestSQLQueryTool_RejectsWrites(t *testing.T) {
+	db := &mockSQLDatabase{}
+	tool := NewSQLQueryTool(map[string]SQLDatabase{"analytics": db})
+
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"query": "UPDATE users SET admin = true"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "query rejected") {
+		t.Errorf("got %+v", result)
+	}
+	if len(db.queries) != 0 {
+		t.Error("rejected query should not reach the database")
+	}
+}
+
+func TestSQLQueryTool_DatabaseSelection(t *testing.T) {
+	tool := NewSQLQueryTool(map[string]SQLDatabase{
+		"analytics": &mockSQLDatabase{result: &SQLResult{}},
+		"billing":   &mockSQLDatabase{err: fmt.Errorf("timeout")},
+	})
+
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"query": "SELECT 1"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "database is required") {
+		t.Errorf("got %+v", result)
+	}
+
+	result, _ = tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"query": "SELECT 1", "database": "billing"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "billing query failed: timeout") {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestFormatSQLResult_Truncation(t *testing.T) {
+	long := strings.Repeat("x", 500)
+	res := &SQLResult{Columns: []string{"v"}, Truncated: true}
+	for i := 0; i < 3; i++ {
+		res.Rows = append(res.Rows, []string{long})
+	}
+	out := FormatSQLResult(res)
+	if strings.Contains(out, strings.Repeat("x", maxSQLCellLen+1)) {
+		t.Error("cells should be truncated")
+	}
+	if !strings.Contains(out, "row limit reached") {
+		t.Errorf("missing row limit note: %s", out)
+	}
+
+	for i := 0; i < 200; i++ {
+		res.Rows = append(res.Rows, []string{long})
+	}
+	if out := FormatSQLResult(res); !strings.Contains(out, "output truncated") || len(out) > maxSQLOutput+100 {
+		t.Errorf("output should be capped, got %d bytes", len(out))
+	}
+
+	multibyte := &SQLResult{Columns: []string{"v"}, Rows: [][]string{{"x" + strings.Repeat("é", maxSQLCellLen)}}}
+	if out := FormatSQLResult(multibyte); !utf8.ValidString(out) {
+		t.Errorf("truncation split a rune: %q", out)
+	}
+}
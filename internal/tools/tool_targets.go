@@ -0,0 +1,379 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Project Target Tools ---
+
+// Target is a canonical project command (Makefile target, Taskfile task,
+// or package.json script).
+type Target struct {
+	Runner      string `json:"runner"` // make, task, npm, yarn, pnpm
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ID is the runner-qualified name used by RunTarget (e.g. "make:test").
+func (t Target) ID() string { return t.Runner + ":" + t.Name }
+
+// Argv is the command that runs the target.
+func (t Target) Argv() []string {
+	switch t.Runner {
+	case "npm", "pnpm":
+		return []string{t.Runner, "run", t.Name}
+	default: // make, task, yarn
+		return []string{t.Runner, t.Name}
+	}
+}
+
+// DiscoverTargets parses the Makefile, Taskfile, and package.json at root.
+// Missing files are skipped; malformed ones are reported.
+func DiscoverTargets(root string) ([]Target, error) {
+	var targets []Target
+
+	for _, name := range []string{"Makefile", "makefile", "GNUmakefile"} {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err == nil {
+			targets = append(targets, ParseMakefile(data)...)
+			break
+		}
+	}
+
+	for _, name := range []string{"Taskfile.yml", "Taskfile.yaml", "taskfile.yml"} {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err == nil {
+			targets = append(targets, ParseTaskfile(data)...)
+			break
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil {
+		scripts, err := ParsePackageScripts(data, detectJSRunner(root))
+		if err != nil {
+			return targets, fmt.Errorf("package.json: %w", err)
+		}
+		targets = append(targets, scripts...)
+	}
+
+	return targets, nil
+}
+
+var (
+	makeTargetLine = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_./-]*)\s*:([^=]|$)`)
+	makeInlineDesc = regexp.MustCompile(`##\s*(.+)$`)
+)
+
+// ParseMakefile extracts explicit targets. Descriptions come from a
+// trailing "## text" (the self-documenting Makefile convention) or a
+// comment on the line above. Pattern rules and special targets are skipped.
+func ParseMakefile(data []byte) []Target {
+	var targets []Target
+	seen := make(map[string]bool)
+	var prevComment string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "#") {
+			prevComment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+		if strings.HasPrefix(line, "\t") || trimmed == "" {
+			prevComment = ""
+			continue
+		}
+
+		m := makeTargetLine.FindStringSubmatch(line)
+		if m == nil {
+			prevComment = ""
+			continue
+		}
+		name := m[1]
+		if strings.Contains(name, "%") || seen[name] {
+			prevComment = ""
+			continue
+		}
+		seen[name] = true
+
+		desc := prevComment
+		if d := makeInlineDesc.FindStringSubmatch(line); d != nil {
+			desc = strings.TrimSpace(d[1])
+		}
+		targets = append(targets, Target{Runner: "make", Name: name, Description: desc})
+		prevComment = ""
+	}
+	return targets
+}
+
+// ParseTaskfile extracts tasks from a go-task Taskfile. It reads only the
+// keys directly under "tasks:" and their "desc:"/"summary:" fields, which
+// is enough for discovery without a YAML dependency.
+func ParseTaskfile(data []byte) []Target {
+	var targets []Target
+	inTasks := false
+	taskIndent := -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if indent == 0 {
+			inTasks = trimmed == "tasks:"
+			taskIndent = -1
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+
+		if taskIndent == -1 {
+			taskIndent = indent
+		}
+		key, value, isKey := cutYAMLKey(trimmed)
+		if !isKey {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch {
+		case indent == taskIndent:
+			name := key
+			if !strings.HasPrefix(name, "_") {
+				targets = append(targets, Target{Runner: "task", Name: name})
+			}
+		case len(targets) > 0 && (key == "desc" || key == "summary") && targets[len(targets)-1].Description == "":
+			if value != "|" && value != ">" {
+				targets[len(targets)-1].Description = value
+			}
+		}
+	}
+	return targets
+}
+
+// cutYAMLKey splits "key: value", honoring quoted keys that contain colons.
+func cutYAMLKey(line string) (key, value string, ok bool) {
+	if q := line[0]; q == '"' || q == '\'' {
+		end := strings.IndexByte(line[1:], q)
+		if end < 0 {
+			return "", "", false
+		}
+		rest := strings.TrimSpace(line[end+2:])
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return line[1 : end+1], rest[1:], true
+	}
+	return strings.Cut(line, ":")
+}
+
+// ParsePackageScripts extracts package.json scripts. The script body is
+// used as the description. Lifecycle hooks (pre*/post*) are skipped.
+func ParsePackageScripts(data []byte, runner string) ([]Target, error) {
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		if (strings.HasPrefix(name, "pre") && pkg.Scripts[strings.TrimPrefix(name, "pre")] != "") ||
+			(strings.HasPrefix(name, "post") && pkg.Scripts[strings.TrimPrefix(name, "post")] != "") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	targets := make([]Target, 0, len(names))
+	for _, name := range names {
+		targets = append(targets, Target{Runner: runner, Name: name, Description: pkg.Scripts[name]})
+	}
+	return targets, nil
+}
+
+// detectJSRunner picks the package manager from the lockfile.
+func detectJSRunner(root string) string {
+	switch {
+	case fileExists(filepath.Join(root, "pnpm-lock.yaml")):
+		return "pnpm"
+	case fileExists(filepath.Join(root, "yarn.lock")):
+		return "yarn"
+	default:
+		return "npm"
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// FormatTargets renders targets for the LLM, grouped by runner.
+func FormatTargets(targets []Target) string {
+	if len(targets) == 0 {
+		return "no Makefile, Taskfile, or package.json scripts found"
+	}
+	var b strings.Builder
+	runner := ""
+	for _, t := range targets {
+		if t.Runner != runner {
+			if runner != "" {
+				b.WriteString("\n")
+			}
+			runner = t.Runner
+			b.WriteString(runner + ":\n")
+		}
+		line := "  " + t.ID()
+		if t.Description != "" {
+			desc := t.Description
+			if len(desc) > 100 {
+				desc = desc[:100] + "..."
+			}
+			line += " — " + desc
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// ListTargetsTool shows the project's canonical commands.
+type ListTargetsTool struct {
+	sandbox *Sandbox
+}
+
+// NewListTargetsTool creates a ListTargets tool.
+func NewListTargetsTool(sandbox *Sandbox) *ListTargetsTool {
+	return &ListTargetsTool{sandbox: sandbox}
+}
+
+func (t *ListTargetsTool) Name() string { return "ListTargets" }
+
+func (t *ListTargetsTool) Description() string {
+	return "List the project's canonical commands from Makefile, Taskfile, and package.json scripts. " +
+		"Prefer these over guessing build/test flags."
+}
+
+func (t *ListTargetsTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{"type": "object", "properties": {}}`)
+}
+
+func (t *ListTargetsTool) RiskTier() RiskTier { return Read }
+
+func (t *ListTargetsTool) Execute(_ context.Context, call ToolCall) (ToolResult, error) {
+	targets, err := DiscoverTargets(t.sandbox.Root)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	return ToolResult{ToolCallID: call.ID, Content: FormatTargets(targets)}, nil
+}
+
+// RunTargetTool runs a discovered target by ID.
+type RunTargetTool struct {
+	sandbox *Sandbox
+	timeout time.Duration
+}
+
+// NewRunTargetTool creates a RunTarget tool.
+func NewRunTargetTool(sandbox *Sandbox) *RunTargetTool {
+	return &RunTargetTool{sandbox: sandbox, timeout: 10 * time.Minute}
+}
+
+func (t *RunTargetTool) Name() string { return "RunTarget" }
+
+func (t *RunTargetTool) Description() string {
+	return "Run a project target listed by ListTargets (e.g. \"make:test\", \"npm:lint\")."
+}
+
+func (t *RunTargetTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"target": {
+				"type": "string",
+				"description": "Target ID from ListTargets, e.g. make:test"
+			},
+			"timeout": {
+				"type": "integer",
+				"description": "Optional timeout in seconds (default 600)"
+			}
+		},
+		"required": ["target"]
+	}`)
+}
+
+func (t *RunTargetTool) RiskTier() RiskTier { return WriteLocal }
+
+func (t *RunTargetTool) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var args struct {
+		Target  string `json:"target"`
+		Timeout int    `json:"timeout"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("invalid arguments: %s", err), IsError: true}, nil
+	}
+
+	targets, err := DiscoverTargets(t.sandbox.Root)
+	if err != nil {
+		return ToolResult{ToolCallID: call.ID, Content: err.Error(), IsError: true}, nil
+	}
+	var target *Target
+	for i := range targets {
+		if targets[i].ID() == args.Target {
+			target = &targets[i]
+			break
+		}
+	}
+	if target == nil {
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("unknown target %q; use ListTargets to see available targets", args.Target), IsError: true}, nil
+	}
+
+	timeout := t.timeout
+	if args.Timeout > 0 {
+		timeout = time.Duration(args.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	argv := target.Argv()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = t.sandbox.Root
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err = cmd.Run()
+	output := out.String()
+	const maxOutput = 50000
+	if len(output) > maxOutput {
+		output = "... (output truncated)\n" + output[len(output)-maxOutput:]
+	}
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("%s timed out after %s\n%s", strings.Join(argv, " "), timeout, output), IsError: true}, nil
+		}
+		return ToolResult{ToolCallID: call.ID, Content: fmt.Sprintf("%s: %v\n%s", strings.Join(argv, " "), err, output), IsError: true}, nil
+	}
+	return ToolResult{ToolCallID: call.ID, Content: output}, nil
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMakefile = `GO ?= go
+VERSION := 1.2.3
+
+.PHONY: build test lint
+
+# Build the binary
+build: ## Compile all packages
+	$(GO) build ./...
+
+# Run unit tests
+test:
+	$(GO) test ./...
+
+lint: build
+	golangci-lint run
+
+%.o: %.c
+	cc -c $<
+
+build:
+	@echo duplicate
+`
+
+func TestParseMakefile(t *testing.T) {
+	targets := ParseMakefile([]byte(testMakefile))
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 targets, got %+v", targets)
+	}
+	want := []Target{
+		{Runner: "make", Name: "build", Description: "Compile all packages"},
+		{Runner: "make", Name: "test", Description: "Run unit tests"},
+		{Runner: "make", Name: "lint"},
+	}
+	for i, w := range want {
+		if targets[i] != w {
+			t.Errorf("target %d = %+v, want %+v", i, targets[i], w)
+		}
+	}
+}
+
+func TestParseTaskfile(t *testing.T) {
+	data := []byte(`version: '3'
+
+vars:
+  BIN: app
+
+tasks:
+  build:
+    desc: Build the app
+    cmds:
+      - go build -o {{.BIN}} .
+  test:
+    cmds:
+      - go test ./...
+  _internal:
+    cmds:
+      - echo hidden
+  "db:migrate":
+    summary: "Apply migrations"
+`)
+	targets := ParseTaskfile(data)
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 tasks, got %+v", targets)
+	}
+	if targets[0].Name != "build" || targets[0].Description != "Build the app" {
+		t.Errorf("got %+v", targets[0])
+	}
+	if targets[1].Name != "test" || targets[1].Description != "" {
+		t.Errorf("got %+v", targets[1])
+	}
+	if targets[2].Name != "db:migrate" || targets[2].Description != "Apply migrations" {
+		t.Errorf("got %+v", targets[2])
+	}
+}
+
+func TestParsePackageScripts(t *testing.T) {
+	data := []byte(`{"scripts": {"dev": "vite", "build": "tsc && vite build", "prebuild": "rm -rf dist", "test": "vitest"}}`)
+	targets, err := ParsePackageScripts(data, "pnpm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, tg := range targets {
+		ids = append(ids, tg.ID())
+	}
+	if strings.Join(ids, ",") != "pnpm:build,pnpm:dev,pnpm:test" {
+		t.Errorf("got %v", ids)
+	}
+	if strings.Join(targets[0].Argv(), " ") != "pnpm run build" {
+		t.Errorf("argv = %v", targets[0].Argv())
+	}
+
+	if _, err := ParsePackageScripts([]byte("{"), "npm"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestDiscoverTargets_RunnerFromLockfile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"scripts": {"lint": "eslint ."}}`), 0644)
+	os.WriteFile(filepath.Join(dir, "yarn.lock"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "Makefile"), []byte("test:\n\tgo test ./...\n"), 0644)
+
+	targets, err := DiscoverTargets(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := FormatTargets(targets)
+	for _, want := range []string{"make:test", "yarn:lint — eslint ."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestListTargetsTool_Empty(t *testing.T) {
+	sb, _ := NewSandbox(t.TempDir())
+	result, _ := NewListTargetsTool(sb).Execute(context.Background(), ToolCall{})
+	if result.IsError || !strings.Contains(result.Content, "no Makefile") {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestRunTargetTool(t *testing.T) {
+	if _, err := exec.LookPath("make"); err != nil {
+		t.Skip("make not installed")
+	}
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Makefile"), []byte("hello:\n\t@echo hello from make\nfail:\n\t@exit 3\n"), 0644)
+	sb, _ := NewSandbox(dir)
+	tool := NewRunTargetTool(sb)
+
+	result, _ := tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"target": "make:hello"}`)})
+	if result.IsError || !strings.Contains(result.Content, "hello from make") {
+		t.Errorf("got %+v", result)
+	}
+
+	result, _ = tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"target": "make:fail"}`)})
+	if !result.IsError {
+		t.Error("failing target should be an error")
+	}
+
+	result, _ = tool.Execute(context.Background(), ToolCall{Arguments: json.RawMessage(`{"target": "make:deploy"}`)})
+	if !result.IsError || !strings.Contains(result.Content, "unknown target") {
+		t.Errorf("got %+v", result)
+	}
+}
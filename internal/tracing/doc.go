@@ -0,0 +1,8 @@
+// Package tracing records OpenTelemetry-compatible spans for a request's
+// path through CodeButler (message, batch, agent run, LLM turns, tools,
+// reply) and exports them over OTLP/HTTP with JSON encoding, so any
+// OpenTelemetry collector or vendor can show the trace. Like the
+// observability backends, it is a small client with no vendor SDK. The
+// agent package stays tracing-free: Provider, Tools, Sender, and Runner
+// wrap its interfaces.
+package tracing
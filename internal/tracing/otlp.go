@@ -0,0 +1,182 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+// HTTPDoer is the subset of *http.Client used by the exporter.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// OTLPExporter posts spans to an OTLP/HTTP collector using the JSON
+// encoding (Content-Type application/json on <endpoint>/v1/traces).
+type OTLPExporter struct {
+	url     string
+	headers map[string]string
+	client  HTTPDoer
+}
+
+// NewOTLPExporter creates an exporter for the collector at endpoint, e.g.
+// "http://localhost:4318". A nil client uses a 10-second timeout.
+func NewOTLPExporter(endpoint string, headers map[string]string, client HTTPDoer) *OTLPExporter {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	url := strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	return &OTLPExporter{url: url, headers: headers, client: client}
+}
+
+// Export implements Exporter.
+func (e *OTLPExporter) Export(ctx context.Context, service string, spans []SpanData) error {
+	body, err := json.Marshal(encodeTraces(service, spans))
+	if err != nil {
+		return fmt.Errorf("marshal spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return fmt.Errorf("OTLP HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+// FromConfig builds a tracer from the global config. It returns nil (a
+// valid no-op tracer) when tracing is not configured.
+func FromConfig(cfg *config.GlobalTracing, logger *slog.Logger) *Tracer {
+	if cfg == nil || cfg.Endpoint == "" {
+		return nil
+	}
+	return New(NewOTLPExporter(cfg.Endpoint, cfg.Headers, nil),
+		WithServiceName(cfg.ServiceName),
+		WithSampleRatio(cfg.SampleRatio),
+		WithLogger(logger),
+	)
+}
+
+// OTLP JSON wire types (opentelemetry-proto, JSON mapping). IDs are hex
+// strings and 64-bit integers are decimal strings.
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"` // 1 ok, 2 error
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+)
+
+// spanKindInternal is OTLP SPAN_KIND_INTERNAL.
+const spanKindInternal = 1
+
+func encodeTraces(service string, spans []SpanData) otlpTraces {
+	scope := otlpScopeSpans{Spans: make([]otlpSpan, 0, len(spans))}
+	scope.Scope.Name = "github.com/leandrotocalini/codebutler"
+	for _, s := range spans {
+		out := otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        encodeAttrs(s.Attrs),
+			Status:            otlpStatus{Code: 1},
+		}
+		if s.Err != "" {
+			out.Status = otlpStatus{Code: 2, Message: s.Err}
+		}
+		scope.Spans = append(scope.Spans, out)
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttrs(map[string]any{"service.name": service})},
+		ScopeSpans: []otlpScopeSpans{scope},
+	}}}
+}
+
+// encodeAttrs converts attributes, sorted by key for stable output.
+func encodeAttrs(attrs map[string]any) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		var v map[string]any
+		switch x := attrs[k].(type) {
+		case string:
+			v = map[string]any{"stringValue": x}
+		case bool:
+			v = map[string]any{"boolValue": x}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(x)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(x, 10)}
+		case float64:
+			v = map[string]any{"doubleValue": x}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(x)}
+		}
+		out = append(out, otlpKeyValue{Key: k, Value: v})
+	}
+	return out
+}
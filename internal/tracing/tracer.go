@@ -0,0 +1,241 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultServiceName is the service.name resource attribute.
+const DefaultServiceName = "codebutler"
+
+// defaultBatchSize is how many finished spans trigger an export.
+const defaultBatchSize = 256
+
+// Exporter ships finished spans to a backend.
+type Exporter interface {
+	Export(ctx context.Context, service string, spans []SpanData) error
+}
+
+// SpanData is a finished span.
+type SpanData struct {
+	TraceID  string // 32 hex chars
+	SpanID   string // 16 hex chars
+	ParentID string // empty for a root span
+	Name     string
+	Start    time.Time
+	End      time.Time
+	Attrs    map[string]any
+	Err      string // non-empty marks the span failed
+}
+
+// Tracer creates spans and batches them for export. Thread-safe.
+type Tracer struct {
+	exporter  Exporter
+	service   string
+	ratio     float64
+	batchSize int
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu      sync.Mutex
+	pending []SpanData
+}
+
+// Option configures a Tracer.
+type Option func(*Tracer)
+
+// WithServiceName overrides DefaultServiceName.
+func WithServiceName(name string) Option {
+	return func(t *Tracer) {
+		if name != "" {
+			t.service = name
+		}
+	}
+}
+
+// WithSampleRatio traces only this fraction of root spans (0 < r < 1).
+// Other values trace everything.
+func WithSampleRatio(r float64) Option {
+	return func(t *Tracer) {
+		if r > 0 && r < 1 {
+			t.ratio = r
+		}
+	}
+}
+
+// WithLogger sets the structured logger for export failures.
+func WithLogger(l *slog.Logger) Option {
+	return func(t *Tracer) {
+		if l != nil {
+			t.logger = l
+		}
+	}
+}
+
+// New creates a tracer that exports through exporter.
+func New(exporter Exporter, opts ...Option) *Tracer {
+	t := &Tracer{
+		exporter:  exporter,
+		service:   DefaultServiceName,
+		ratio:     1,
+		batchSize: defaultBatchSize,
+		logger:    slog.Default(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Span is an in-progress operation. A nil *Span is a valid no-op, so
+// callers never need to check whether tracing is enabled.
+type Span struct {
+	tracer  *Tracer
+	sampled bool
+
+	mu   sync.Mutex
+	data SpanData
+	done bool
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the current span, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// Start begins a span as a child of the span in ctx, or as a new trace's
+// root. End it with Span.End. A nil Tracer returns a nil span.
+func (t *Tracer) Start(ctx context.Context, name string, attrs map[string]any) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	s := &Span{tracer: t, data: SpanData{Name: name, Start: t.now(), SpanID: randomHex(8)}}
+	if parent := SpanFromContext(ctx); parent != nil {
+		s.sampled = parent.sampled
+		s.data.TraceID = parent.data.TraceID
+		s.data.ParentID = parent.data.SpanID
+	} else {
+		s.data.TraceID = randomHex(16)
+		s.sampled = t.sample(s.data.TraceID)
+	}
+	if len(attrs) > 0 {
+		s.data.Attrs = make(map[string]any, len(attrs))
+		for k, v := range attrs {
+			s.data.Attrs[k] = v
+		}
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// sample decides from the trace ID so the choice is stable per trace.
+func (t *Tracer) sample(traceID string) bool {
+	if t.ratio >= 1 {
+		return true
+	}
+	b, _ := hex.DecodeString(traceID[:16])
+	return float64(binary.BigEndian.Uint64(b)>>11)/float64(1<<53) < t.ratio
+}
+
+// SetAttr adds or replaces an attribute.
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Attrs == nil {
+		s.data.Attrs = make(map[string]any)
+	}
+	s.data.Attrs[key] = value
+}
+
+// End finishes the span; a non-nil err marks it failed. Calls after the
+// first are ignored.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return
+	}
+	s.done = true
+	s.data.End = s.tracer.now()
+	if err != nil {
+		s.data.Err = err.Error()
+	}
+	data := s.data
+	s.mu.Unlock()
+
+	if s.sampled {
+		s.tracer.enqueue(data)
+	}
+}
+
+// TraceID returns the span's trace ID, e.g. for log correlation.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.data.TraceID
+}
+
+func (t *Tracer) enqueue(d SpanData) {
+	t.mu.Lock()
+	t.pending = append(t.pending, d)
+	full := len(t.pending) >= t.batchSize
+	t.mu.Unlock()
+	if full {
+		go t.Flush(context.Background()) //nolint:errcheck // logged in Flush
+	}
+}
+
+// Flush exports every finished span. Failed batches are dropped (and
+// logged) rather than retried, so a dead collector cannot grow memory.
+func (t *Tracer) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := t.exporter.Export(ctx, t.service, batch); err != nil {
+		t.logger.Warn("trace export failed", "spans", len(batch), "err", err)
+		return err
+	}
+	return nil
+}
+
+// Run flushes every interval until ctx is done, then flushes once more.
+func (t *Tracer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			t.Flush(flushCtx) //nolint:errcheck // logged in Flush
+			cancel()
+			return
+		case <-ticker.C:
+			t.Flush(ctx) //nolint:errcheck // logged in Flush
+		}
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b) //nolint:errcheck // crypto/rand never fails on supported platforms
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/config"
+)
+
+type memExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (m *memExporter) Export(_ context.Context, _ string, spans []SpanData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = append(m.spans, spans...)
+	return nil
+}
+
+func (m *memExporter) byName(name string) (SpanData, bool) {
+	for _, s := range m.spans {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return SpanData{}, false
+}
+
+type stubProvider struct{}
+
+func (stubProvider) ChatCompletion(context.Context, agent.ChatRequest) (*agent.ChatResponse, error) {
+	return &agent.ChatResponse{ID: "gen-1", Usage: agent.TokenUsage{PromptTokens: 10, CompletionTokens: 5}}, nil
+}
+
+type stubTools struct{}
+
+func (stubTools) Execute(context.Context, agent.ToolCall) (agent.ToolResult, error) {
+	return agent.ToolResult{}, errors.New("boom")
+}
+func (stubTools) ListTools() []agent.ToolDefinition { return nil }
+
+// stubRunner makes one LLM call and one tool call, like a tiny agent loop.
+type stubRunner struct {
+	provider agent.LLMProvider
+	tools    agent.ToolExecutor
+}
+
+func (r stubRunner) Run(ctx context.Context, _ agent.Task) (*agent.Result, error) {
+	r.provider.ChatCompletion(ctx, agent.ChatRequest{Model: "openai/gpt-4o"})
+	r.tools.Execute(ctx, agent.ToolCall{Name: "Bash"})
+	return &agent.Result{TurnsUsed: 1, ToolCalls: 1}, nil
+}
+
+func TestWrappers_NestUnderOneTrace(t *testing.T) {
+	exp := &memExporter{}
+	tr := New(exp)
+
+	ctx, root := tr.Start(context.Background(), "message", map[string]any{"channel": "C1"})
+	runner := WrapRunner(tr, stubRunner{provider: WrapProvider(tr, stubProvider{}), tools: WrapTools(tr, stubTools{})}, "coder")
+	runner.Run(ctx, agent.Task{Thread: "t1"})
+	root.End(nil)
+	root.End(errors.New("ignored")) // second End is a no-op
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(exp.spans) != 4 {
+		t.Fatalf("got %d spans", len(exp.spans))
+	}
+	msg, _ := exp.byName("message")
+	run, _ := exp.byName("agent.run")
+	llm, _ := exp.byName("llm.chat")
+	tool, _ := exp.byName("tool Bash")
+	for _, s := range exp.spans {
+		if s.TraceID != root.TraceID() {
+			t.Errorf("%s in trace %s, want %s", s.Name, s.TraceID, root.TraceID())
+		}
+	}
+	if msg.ParentID != "" || run.ParentID != msg.SpanID || llm.ParentID != run.SpanID || tool.ParentID != run.SpanID {
+		t.Errorf("bad nesting: %+v", exp.spans)
+	}
+	if llm.Attrs["llm.generation_id"] != "gen-1" || run.Attrs["agent.role"] != "coder" || tool.Err != "boom" || msg.Err != "" {
+		t.Errorf("attrs: llm=%v run=%v tool=%q", llm.Attrs, run.Attrs, tool.Err)
+	}
+}
+
+func TestNilTracerIsNoop(t *testing.T) {
+	var tr *Tracer
+	ctx, span := tr.Start(context.Background(), "x", nil)
+	span.SetAttr("k", 1)
+	span.End(nil)
+	if SpanFromContext(ctx) != nil || span.TraceID() != "" {
+		t.Error("nil tracer should not create spans")
+	}
+	if FromConfig(nil, nil) != nil || FromConfig(&config.GlobalTracing{}, nil) != nil {
+		t.Error("unconfigured tracing should yield nil")
+	}
+}
+
+func TestSampling(t *testing.T) {
+	exp := &memExporter{}
+	tr := New(exp, WithSampleRatio(0.5))
+	kept := 0
+	for i := 0; i < 400; i++ {
+		ctx, root := tr.Start(context.Background(), "root", nil)
+		_, child := tr.Start(ctx, "child", nil)
+		child.End(nil)
+		root.End(nil)
+	}
+	tr.Flush(context.Background())
+	for _, s := range exp.spans {
+		if s.Name == "root" {
+			kept++
+		}
+	}
+	if len(exp.spans) != 2*kept {
+		t.Errorf("children must follow their root's sampling: %d spans, %d roots", len(exp.spans), kept)
+	}
+	if kept < 120 || kept > 280 {
+		t.Errorf("kept %d of 400 at ratio 0.5", kept)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		auth = r.Header.Get("x-api-key")
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+	}))
+	defer srv.Close()
+
+	tr := FromConfig(&config.GlobalTracing{Endpoint: srv.URL, Headers: map[string]string{"x-api-key": "k"}, ServiceName: "cb-test"}, nil)
+	_, span := tr.Start(context.Background(), "agent.run", map[string]any{"agent.turns": 3, "agent.role": "pm"})
+	span.End(errors.New("timed out"))
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := json.Marshal(body)
+	for _, want := range []string{`"stringValue":"cb-test"`, `"name":"agent.run"`, `"intValue":"3"`, `"code":2`, `"message":"timed out"`} {
+		if !strings.Contains(string(raw), want) {
+			t.Errorf("export missing %s:\n%s", want, raw)
+		}
+	}
+	if auth != "k" {
+		t.Errorf("auth header = %q", auth)
+	}
+}
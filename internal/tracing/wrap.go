@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// Provider traces every LLM call as an "llm.chat" span.
+type Provider struct {
+	agent.LLMProvider
+	tracer *Tracer
+}
+
+// WrapProvider wraps p.
+func WrapProvider(t *Tracer, p agent.LLMProvider) *Provider {
+	return &Provider{LLMProvider: p, tracer: t}
+}
+
+// ChatCompletion implements agent.LLMProvider.
+func (p *Provider) ChatCompletion(ctx context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	ctx, span := p.tracer.Start(ctx, "llm.chat", map[string]any{
+		"llm.model":    req.Model,
+		"llm.messages": len(req.Messages),
+		"llm.tools":    len(req.Tools),
+	})
+	resp, err := p.LLMProvider.ChatCompletion(ctx, req)
+	if resp != nil {
+		span.SetAttr("llm.prompt_tokens", resp.Usage.PromptTokens)
+		span.SetAttr("llm.completion_tokens", resp.Usage.CompletionTokens)
+		span.SetAttr("llm.tool_calls", len(resp.Message.ToolCalls))
+		if resp.ID != "" {
+			span.SetAttr("llm.generation_id", resp.ID)
+		}
+	}
+	span.End(err)
+	return resp, err
+}
+
+// Tools traces every tool call as a "tool <name>" span.
+type Tools struct {
+	agent.ToolExecutor
+	tracer *Tracer
+}
+
+// WrapTools wraps e.
+func WrapTools(t *Tracer, e agent.ToolExecutor) *Tools {
+	return &Tools{ToolExecutor: e, tracer: t}
+}
+
+// Execute implements agent.ToolExecutor.
+func (t *Tools) Execute(ctx context.Context, call agent.ToolCall) (agent.ToolResult, error) {
+	ctx, span := t.tracer.Start(ctx, "tool "+call.Name, map[string]any{"tool.name": call.Name})
+	result, err := t.ToolExecutor.Execute(ctx, call)
+	span.SetAttr("tool.is_error", result.IsError)
+	span.SetAttr("tool.output_bytes", len(result.Content))
+	span.End(err)
+	return result, err
+}
+
+// Sender traces messenger sends as "messenger.send" spans.
+type Sender struct {
+	agent.MessageSender
+	tracer *Tracer
+}
+
+// WrapSender wraps s.
+func WrapSender(t *Tracer, s agent.MessageSender) *Sender {
+	return &Sender{MessageSender: s, tracer: t}
+}
+
+// SendMessage implements agent.MessageSender.
+func (s *Sender) SendMessage(ctx context.Context, channel, thread, text string) error {
+	ctx, span := s.tracer.Start(ctx, "messenger.send", map[string]any{
+		"messenger.channel": channel,
+		"messenger.thread":  thread,
+		"messenger.length":  len(text),
+	})
+	err := s.MessageSender.SendMessage(ctx, channel, thread, text)
+	span.End(err)
+	return err
+}
+
+// TaskRunner is satisfied by every agent runner.
+type TaskRunner interface {
+	Run(ctx context.Context, task agent.Task) (*agent.Result, error)
+}
+
+// Runner traces an agent activation as an "agent.run" span; the LLM and
+// tool spans of the run nest under it.
+type Runner struct {
+	runner TaskRunner
+	tracer *Tracer
+	role   string
+}
+
+// WrapRunner wraps r for role.
+func WrapRunner(t *Tracer, r TaskRunner, role string) *Runner {
+	return &Runner{runner: r, tracer: t, role: role}
+}
+
+// Run implements TaskRunner.
+func (r *Runner) Run(ctx context.Context, task agent.Task) (*agent.Result, error) {
+	ctx, span := r.tracer.Start(ctx, "agent.run", map[string]any{
+		"agent.role":     r.role,
+		"agent.channel":  task.Channel,
+		"agent.thread":   task.Thread,
+		"agent.messages": len(task.Messages),
+	})
+	res, err := r.runner.Run(ctx, task)
+	if res != nil {
+		span.SetAttr("agent.turns", res.TurnsUsed)
+		span.SetAttr("agent.tool_calls", res.ToolCalls)
+		span.SetAttr("agent.total_tokens", res.TokenUsage.TotalTokens)
+		span.SetAttr("agent.escalated", res.Escalated)
+	}
+	span.End(err)
+	return res, err
+}
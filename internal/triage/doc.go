@@ -0,0 +1,5 @@
+// Package triage implements the issue triage workflow: read open GitHub
+// issues, classify each as bug/feature/question, estimate complexity, draft
+// clarifying questions for underspecified issues, and summarize the result
+// for chat. It runs on demand via /triage or on a schedule.
+package triage
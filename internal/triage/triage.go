@@ -0,0 +1,271 @@
+package triage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/github"
+	"github.com/leandrotocalini/codebutler/internal/injection"
+)
+
+// TriagedLabel marks an issue as already triaged so later runs skip it.
+const TriagedLabel = "triaged"
+
+// Kind is the issue category.
+type Kind string
+
+const (
+	KindBug      Kind = "bug"
+	KindFeature  Kind = "feature"
+	KindQuestion Kind = "question"
+)
+
+// Assessment is the model's triage of one issue.
+type Assessment struct {
+	Number     int      `json:"number"`
+	Kind       Kind     `json:"kind"`
+	Complexity string   `json:"complexity"` // simple, medium, complex
+	Summary    string   `json:"summary"`
+	Questions  []string `json:"questions,omitempty"` // set when the issue is underspecified
+}
+
+// Labels returns the GitHub labels to apply for an assessment.
+func (a Assessment) Labels() []string {
+	labels := []string{string(a.Kind), "complexity:" + a.Complexity, TriagedLabel}
+	if len(a.Questions) > 0 {
+		labels = append(labels, "needs-info")
+	}
+	return labels
+}
+
+// IssueClient is the subset of github.GHOps used for triage.
+type IssueClient interface {
+	ListOpenIssues(ctx context.Context, limit int) ([]github.Issue, error)
+	AddIssueLabels(ctx context.Context, number int, labels ...string) error
+	CommentIssue(ctx context.Context, number int, body string) error
+}
+
+// Options control a triage run.
+type Options struct {
+	Limit         int  // max issues to read (default 30)
+	Apply         bool // apply labels and post clarifying questions on GitHub
+	IncludeTriage bool // re-triage issues already labeled "triaged"
+}
+
+// Triager runs the triage workflow.
+type Triager struct {
+	issues   IssueClient
+	provider agent.LLMProvider
+	model    string
+	logger   *slog.Logger
+}
+
+// NewTriager creates a triager that uses model via provider.
+func NewTriager(issues IssueClient, provider agent.LLMProvider, model string, logger *slog.Logger) *Triager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Triager{issues: issues, provider: provider, model: model, logger: logger}
+}
+
+// Run reads open issues, assesses them in one model call, optionally
+// applies the results on GitHub, and returns the chat summary.
+func (t *Triager) Run(ctx context.Context, opts Options) (string, []Assessment, error) {
+	if opts.Limit == 0 {
+		opts.Limit = 30
+	}
+
+	all, err := t.issues.ListOpenIssues(ctx, opts.Limit)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var issues []github.Issue
+	for _, i := range all {
+		if opts.IncludeTriage || !i.HasLabel(TriagedLabel) {
+			issues = append(issues, i)
+		}
+	}
+	if len(issues) == 0 {
+		return "No open issues need triage.", nil, nil
+	}
+
+	resp, err := t.provider.ChatCompletion(ctx, agent.ChatRequest{
+		Model: t.model,
+		Messages: []agent.Message{
+			{Role: "user", Content: FormatPrompt(issues)},
+		},
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("triage model call: %w", err)
+	}
+
+	assessments, err := ParseAssessments(resp.Message.Content)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if opts.Apply {
+		for _, a := range assessments {
+			if err := t.issues.AddIssueLabels(ctx, a.Number, a.Labels()...); err != nil {
+				t.logger.Warn("failed to label issue", "number", a.Number, "err", err)
+			}
+			if len(a.Questions) > 0 {
+				if err := t.issues.CommentIssue(ctx, a.Number, FormatQuestionsComment(a.Questions)); err != nil {
+					t.logger.Warn("failed to comment on issue", "number", a.Number, "err", err)
+				}
+			}
+		}
+	}
+
+	t.logger.Info("triage complete", "issues", len(issues), "assessed", len(assessments), "applied", opts.Apply)
+	return FormatSummary(issues, assessments, opts.Apply), assessments, nil
+}
+
+// maxBodyChars caps each issue body in the prompt.
+const maxBodyChars = 1500
+
+// FormatPrompt builds the triage prompt for a batch of issues.
+func FormatPrompt(issues []github.Issue) string {
+	var b strings.Builder
+	b.WriteString("## Issue Triage\n\n")
+	b.WriteString("For each issue below, classify it and estimate the work involved.\n\n")
+
+	for _, i := range issues {
+		body := strings.TrimSpace(i.Body)
+		if len(body) > maxBodyChars {
+			body = body[:maxBodyChars] + "..."
+		}
+		if body == "" {
+			body = "(no description)"
+		}
+		// Issue text is written by anyone who can open an issue.
+		b.WriteString(fmt.Sprintf("### #%d\n\n%s\n\n", i.Number,
+			injection.Wrap(fmt.Sprintf("issue #%d", i.Number), i.Title+"\n\n"+body)))
+	}
+
+	b.WriteString("### Instructions\n\n")
+	b.WriteString("Reply with a JSON array only, one object per issue:\n")
+	b.WriteString("```json\n")
+	b.WriteString(`[{"number": 1, "kind": "bug|feature|question", "complexity": "simple|medium|complex", "summary": "one line", "questions": ["..."]}]`)
+	b.WriteString("\n```\n")
+	b.WriteString("Only include `questions` when the issue is too underspecified to start work " +
+		"(missing repro steps, expected behavior, or scope). Ask at most 3 concrete questions.\n")
+	return b.String()
+}
+
+var jsonBlock = regexp.MustCompile("(?s)```(?:json)?\\s*(\\[.*?\\])\\s*```")
+
+// ParseAssessments extracts assessments from the model reply. It accepts a
+// bare JSON array or one wrapped in a code fence. Unknown kinds and
+// complexities are normalized.
+func ParseAssessments(reply string) ([]Assessment, error) {
+	raw := strings.TrimSpace(reply)
+	if m := jsonBlock.FindStringSubmatch(raw); m != nil {
+		raw = m[1]
+	} else if start, end := strings.Index(raw, "["), strings.LastIndex(raw, "]"); start >= 0 && end > start {
+		raw = raw[start : end+1]
+	}
+
+	var out []Assessment
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return nil, fmt.Errorf("parse triage reply: %w", err)
+	}
+
+	for i := range out {
+		switch out[i].Kind {
+		case KindBug, KindFeature, KindQuestion:
+		default:
+			out[i].Kind = KindQuestion
+		}
+		switch out[i].Complexity {
+		case "simple", "medium", "complex":
+		default:
+			out[i].Complexity = "medium"
+		}
+	}
+	return out, nil
+}
+
+// FormatQuestionsComment renders clarifying questions as an issue comment.
+func FormatQuestionsComment(questions []string) string {
+	var b strings.Builder
+	b.WriteString("Thanks for the report! A few questions before this can be picked up:\n\n")
+	for _, q := range questions {
+		b.WriteString("- " + q + "\n")
+	}
+	b.WriteString("\n*Posted by CodeButler triage*")
+	return b.String()
+}
+
+// FormatSummary renders the triage result for chat, grouped by kind.
+func FormatSummary(issues []github.Issue, assessments []Assessment, applied bool) string {
+	titles := make(map[int]string, len(issues))
+	for _, i := range issues {
+		titles[i.Number] = i.Title
+	}
+
+	byKind := make(map[Kind][]Assessment)
+	needsInfo := 0
+	for _, a := range assessments {
+		byKind[a.Kind] = append(byKind[a.Kind], a)
+		if len(a.Questions) > 0 {
+			needsInfo++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*Triage: %d issue(s)* — %d bug, %d feature, %d question; %d need more info\n",
+		len(assessments), len(byKind[KindBug]), len(byKind[KindFeature]), len(byKind[KindQuestion]), needsInfo))
+
+	for _, kind := range []Kind{KindBug, KindFeature, KindQuestion} {
+		list := byKind[kind]
+		if len(list) == 0 {
+			continue
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Number < list[j].Number })
+		b.WriteString(fmt.Sprintf("\n*%s*\n", strings.ToUpper(string(kind[:1]))+string(kind[1:])+"s"))
+		for _, a := range list {
+			line := fmt.Sprintf("• #%d %s — %s [%s]", a.Number, titles[a.Number], a.Summary, a.Complexity)
+			if len(a.Questions) > 0 {
+				line += fmt.Sprintf(" _(%d question(s))_", len(a.Questions))
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if !applied {
+		b.WriteString("\n_Dry run — use `/triage apply` to label issues and post questions._")
+	}
+	return b.String()
+}
+
+// Command returns the /triage chat command. "/triage apply" writes labels
+// and questions to GitHub; plain "/triage" only reports.
+func Command(t *Triager) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "triage",
+		Usage:       "/triage [apply] [all]",
+		Description: "Classify open GitHub issues and draft clarifying questions",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			var opts Options
+			for _, a := range inv.Args {
+				switch a {
+				case "apply":
+					opts.Apply = true
+				case "all":
+					opts.IncludeTriage = true
+				}
+			}
+			summary, _, err := t.Run(ctx, opts)
+			return summary, err
+		},
+	}
+}
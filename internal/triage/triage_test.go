@@ -0,0 +1,184 @@
+package triage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+	"github.com/leandrotocalini/codebutler/internal/github"
+)
+
+type mockIssues struct {
+	issues   []github.Issue
+	labels   map[int][]string
+	comments map[int]string
+}
+
+func (m *mockIssues) ListOpenIssues(_ context.Context, _ int) ([]github.Issue, error) {
+	return m.issues, nil
+}
+
+func (m *mockIssues) AddIssueLabels(_ context.Context, number int, labels ...string) error {
+	if m.labels == nil {
+		m.labels = make(map[int][]string)
+	}
+	m.labels[number] = append(m.labels[number], labels...)
+	return nil
+}
+
+func (m *mockIssues) CommentIssue(_ context.Context, number int, body string) error {
+	if m.comments == nil {
+		m.comments = make(map[int]string)
+	}
+	m.comments[number] = body
+	return nil
+}
+
+type mockProvider struct {
+	reply  string
+	prompt string
+}
+
+func (m *mockProvider) ChatCompletion(_ context.Context, req agent.ChatRequest) (*agent.ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("no messages")
+	}
+	m.prompt = req.Messages[0].Content
+	return &agent.ChatResponse{Message: agent.Message{Role: "assistant", Content: m.reply}}, nil
+}
+
+const sampleReply = "Here you go:\n```json\n" + `[
+  {"number": 1, "kind": "bug", "complexity": "simple", "summary": "nil deref on login"},
+  {"number": 2, "kind": "feature", "complexity": "complex", "summary": "SSO support", "questions": ["Which IdP?", "SAML or OIDC?"]}
+]` + "\n```"
+
+func sampleIssues() []github.Issue {
+	return []github.Issue{
+		{Number: 1, Title: "Crash on login", Body: "panic: nil pointer"},
+		{Number: 2, Title: "Add SSO"},
+		{Number: 3, Title: "Old one", Labels: []github.IssueLabel{{Name: TriagedLabel}}},
+	}
+}
+
+func TestParseAssessments(t *testing.T) {
+	got, err := ParseAssessments(sampleReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 assessments, got %d", len(got))
+	}
+	if got[0].Kind != KindBug || got[1].Complexity != "complex" || len(got[1].Questions) != 2 {
+		t.Errorf("unexpected assessments: %+v", got)
+	}
+}
+
+func TestParseAssessments_Normalizes(t *testing.T) {
+	got, err := ParseAssessments(`[{"number": 5, "kind": "chore", "complexity": "huge"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Kind != KindQuestion || got[0].Complexity != "medium" {
+		t.Errorf("expected normalized values, got %+v", got[0])
+	}
+}
+
+func TestParseAssessments_Invalid(t *testing.T) {
+	if _, err := ParseAssessments("I could not triage these."); err == nil {
+		t.Error("expected error for non-JSON reply")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	a := Assessment{Kind: KindFeature, Complexity: "medium", Questions: []string{"?"}}
+	got := strings.Join(a.Labels(), ",")
+	if got != "feature,complexity:medium,triaged,needs-info" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRun_DryRunSkipsTriaged(t *testing.T) {
+	issues := &mockIssues{issues: sampleIssues()}
+	provider := &mockProvider{reply: sampleReply}
+	tr := NewTriager(issues, provider, "test-model", nil)
+
+	summary, assessments, err := tr.Run(context.Background(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assessments) != 2 {
+		t.Fatalf("expected 2 assessments, got %d", len(assessments))
+	}
+	if strings.Contains(provider.prompt, "Old one") {
+		t.Error("already-triaged issue should be skipped")
+	}
+	if len(issues.labels) != 0 || len(issues.comments) != 0 {
+		t.Error("dry run should not touch GitHub")
+	}
+	for _, want := range []string{"#1 Crash on login", "#2 Add SSO", "1 need more info", "Dry run"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestRun_Apply(t *testing.T) {
+	issues := &mockIssues{issues: sampleIssues()}
+	tr := NewTriager(issues, &mockProvider{reply: sampleReply}, "test-model", nil)
+
+	summary, _, err := tr.Run(context.Background(), Options{Apply: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(issues.labels[1], ",") != "bug,complexity:simple,triaged" {
+		t.Errorf("issue 1 labels: %v", issues.labels[1])
+	}
+	if _, ok := issues.comments[1]; ok {
+		t.Error("issue without questions should not get a comment")
+	}
+	if !strings.Contains(issues.comments[2], "SAML or OIDC?") {
+		t.Errorf("issue 2 comment: %q", issues.comments[2])
+	}
+	if strings.Contains(summary, "Dry run") {
+		t.Error("applied run should not mention dry run")
+	}
+}
+
+func TestRun_NothingToTriage(t *testing.T) {
+	issues := &mockIssues{issues: []github.Issue{sampleIssues()[2]}}
+	provider := &mockProvider{}
+	tr := NewTriager(issues, provider, "test-model", nil)
+
+	summary, _, err := tr.Run(context.Background(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.prompt != "" {
+		t.Error("model should not be called when nothing needs triage")
+	}
+	if !strings.Contains(summary, "No open issues") {
+		t.Errorf("got %q", summary)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	issues := &mockIssues{issues: sampleIssues()}
+	tr := NewTriager(issues, &mockProvider{reply: sampleReply}, "test-model", nil)
+
+	reg := chatcmd.NewRegistry()
+	reg.Register(Command(tr))
+
+	reply, handled, err := reg.Handle(context.Background(), "C1", "T1", "U1", "/triage apply")
+	if err != nil || !handled {
+		t.Fatalf("handled=%v err=%v", handled, err)
+	}
+	if len(issues.labels) != 2 {
+		t.Errorf("expected labels on 2 issues, got %v", issues.labels)
+	}
+	if !strings.Contains(reply, "Triage: 2 issue(s)") {
+		t.Errorf("reply: %q", reply)
+	}
+}
@@ -0,0 +1,6 @@
+// Package watchdog bounds how long each phase of a multi-agent run may
+// take (by default PM 10 min, Coder 60 min, Reviewer 15 min). When a phase
+// overruns, the Watchdog cancels it, posts the partial progress to the
+// thread, and offers "Reply 1 to retry / 2 to escalate", so a hung phase
+// never blocks a thread silently.
+package watchdog
@@ -0,0 +1,278 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+// DefaultTimeouts are the per-role phase limits. Roles not listed run
+// without a limit.
+var DefaultTimeouts = map[string]time.Duration{
+	"pm":       10 * time.Minute,
+	"coder":    60 * time.Minute,
+	"reviewer": 15 * time.Minute,
+}
+
+// DefaultGrace is how long a cancelled phase gets to return before the
+// Watchdog reports it as hung and stops waiting.
+const DefaultGrace = 30 * time.Second
+
+// Phase runs one agent phase. It must stop when ctx is cancelled; the
+// partial result it returns is reported on timeout.
+type Phase func(ctx context.Context) (*agent.Result, error)
+
+// MessageSender posts timeout reports and replies.
+type MessageSender interface {
+	SendMessage(ctx context.Context, channel, thread, text string) error
+}
+
+// Escalator hands a timed-out phase to someone who can unblock it,
+// typically the Lead.
+type Escalator interface {
+	Escalate(ctx context.Context, channel, thread, role, report string) error
+}
+
+// TimeoutError is returned when a phase overran its limit.
+type TimeoutError struct {
+	Role    string
+	Limit   time.Duration
+	Partial *agent.Result // nil when the phase never returned
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s phase timed out after %s", e.Role, e.Limit)
+}
+
+// pending is a timed-out phase awaiting retry or escalation.
+type pending struct {
+	role   string
+	phase  Phase
+	report string
+}
+
+// Watchdog runs phases under per-role timeouts. Thread-safe.
+type Watchdog struct {
+	sender    MessageSender
+	escalator Escalator
+	timeouts  map[string]time.Duration
+	grace     time.Duration
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	pending map[string]pending // channel/thread
+}
+
+// Option configures a Watchdog.
+type Option func(*Watchdog)
+
+// WithTimeouts overrides the limits of the listed roles; a zero or negative
+// duration removes the role's limit.
+func WithTimeouts(t map[string]time.Duration) Option {
+	return func(w *Watchdog) {
+		for role, d := range t {
+			if d <= 0 {
+				delete(w.timeouts, role)
+				continue
+			}
+			w.timeouts[role] = d
+		}
+	}
+}
+
+// WithEscalator enables the "escalate" option.
+func WithEscalator(e Escalator) Option {
+	return func(w *Watchdog) {
+		w.escalator = e
+	}
+}
+
+// WithGrace overrides DefaultGrace.
+func WithGrace(d time.Duration) Option {
+	return func(w *Watchdog) {
+		if d > 0 {
+			w.grace = d
+		}
+	}
+}
+
+// WithLogger sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(w *Watchdog) {
+		w.logger = l
+	}
+}
+
+// New creates a watchdog that reports through sender.
+func New(sender MessageSender, opts ...Option) *Watchdog {
+	w := &Watchdog{
+		sender:   sender,
+		timeouts: make(map[string]time.Duration, len(DefaultTimeouts)),
+		grace:    DefaultGrace,
+		logger:   slog.Default(),
+		pending:  make(map[string]pending),
+	}
+	for role, d := range DefaultTimeouts {
+		w.timeouts[role] = d
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// TimeoutsFromConfig converts configured minutes per role into WithTimeouts
+// input.
+func TimeoutsFromConfig(minutes map[string]int) map[string]time.Duration {
+	out := make(map[string]time.Duration, len(minutes))
+	for role, m := range minutes {
+		out[role] = time.Duration(m) * time.Minute
+	}
+	return out
+}
+
+// Limit returns a role's phase limit; 0 means unlimited.
+func (w *Watchdog) Limit(role string) time.Duration {
+	return w.timeouts[role]
+}
+
+func key(channel, thread string) string {
+	return channel + "/" + thread
+}
+
+// Run runs phase under role's limit. On timeout it cancels the phase,
+// posts the partial progress with retry/escalate options, and returns a
+// *TimeoutError. Cancellation of ctx itself is not a timeout.
+func (w *Watchdog) Run(ctx context.Context, channel, thread, role string, phase Phase) (*agent.Result, error) {
+	limit := w.timeouts[role]
+	if limit <= 0 {
+		return phase(ctx)
+	}
+
+	phaseCtx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+
+	type outcome struct {
+		res *agent.Result
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := phase(phaseCtx)
+		done <- outcome{res, err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+		if phaseCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+			return out.res, out.err
+		}
+	case <-phaseCtx.Done():
+		if ctx.Err() != nil {
+			out = <-done
+			return out.res, out.err
+		}
+		select {
+		case out = <-done:
+		case <-time.After(w.grace):
+			w.logger.Warn("phase ignored cancellation", "role", role, "thread", thread)
+		}
+	}
+
+	report := FormatTimeoutReport(role, limit, out.res)
+	w.mu.Lock()
+	w.pending[key(channel, thread)] = pending{role: role, phase: phase, report: report}
+	w.mu.Unlock()
+
+	prompt := report + "\nReply *1* to retry"
+	if w.escalator != nil {
+		prompt += " / *2* to escalate to the Lead"
+	}
+	prompt += "."
+	if err := w.sender.SendMessage(ctx, channel, thread, prompt); err != nil {
+		w.logger.Warn("post timeout report failed", "thread", thread, "err", err)
+	}
+	w.logger.Warn("phase timed out", "role", role, "thread", thread, "limit", limit)
+	return out.res, &TimeoutError{Role: role, Limit: limit, Partial: out.res}
+}
+
+// HandleReply treats a thread message as the answer to a timeout report.
+// "1"/"retry" reruns the phase in the background; "2"/"escalate" hands it
+// to the escalator. It returns true when the message was consumed.
+func (w *Watchdog) HandleReply(ctx context.Context, channel, thread, text string) bool {
+	var retry bool
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(text), ".!")) {
+	case "1", "retry":
+		retry = true
+	case "2", "escalate":
+		if w.escalator == nil {
+			return false
+		}
+	default:
+		return false
+	}
+
+	k := key(channel, thread)
+	w.mu.Lock()
+	p, ok := w.pending[k]
+	if ok {
+		delete(w.pending, k)
+	}
+	w.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	// The reply's context ends with its handler; the follow-up outlives it.
+	bg := context.WithoutCancel(ctx)
+	if retry {
+		w.sender.SendMessage(ctx, channel, thread, fmt.Sprintf("Retrying the *%s* phase.", p.role)) //nolint:errcheck // best-effort notice
+		go func() {
+			if _, err := w.Run(bg, channel, thread, p.role, p.phase); err != nil {
+				w.logger.Warn("phase retry failed", "role", p.role, "thread", thread, "err", err)
+			}
+		}()
+		return true
+	}
+	go func() {
+		if err := w.escalator.Escalate(bg, channel, thread, p.role, p.report); err != nil {
+			w.logger.Warn("phase escalation failed", "role", p.role, "thread", thread, "err", err)
+			w.sender.SendMessage(bg, channel, thread, "Escalation failed: "+err.Error()) //nolint:errcheck // best-effort notice
+		}
+	}()
+	return true
+}
+
+// Pending reports whether the thread has a timed-out phase awaiting an
+// answer.
+func (w *Watchdog) Pending(channel, thread string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.pending[key(channel, thread)]
+	return ok
+}
+
+// FormatTimeoutReport describes a timed-out phase and how far it got.
+func FormatTimeoutReport(role string, limit time.Duration, partial *agent.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ":hourglass: The *%s* phase hit its %s limit and was stopped.", role, limit)
+	if partial == nil {
+		b.WriteString("\nIt did not respond to cancellation, so no partial progress is available.")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nPartial progress: %d turns, %d tool calls, %d tokens.",
+		partial.TurnsUsed, partial.ToolCalls, partial.TokenUsage.TotalTokens)
+	if last := strings.TrimSpace(partial.Response); last != "" {
+		if len(last) > 300 {
+			last = last[:300] + "..."
+		}
+		b.WriteString("\nLast output:\n> " + strings.ReplaceAll(last, "\n", "\n> "))
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/agent"
+)
+
+type captureSender struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (c *captureSender) SendMessage(_ context.Context, _, _, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.texts = append(c.texts, text)
+	return nil
+}
+
+func (c *captureSender) all() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.texts...)
+}
+
+type escalations struct {
+	ch chan string
+}
+
+func (e *escalations) Escalate(_ context.Context, _, _, role, _ string) error {
+	e.ch <- role
+	return nil
+}
+
+// slowPhase blocks until cancelled and returns partial progress.
+func slowPhase(runs *int) Phase {
+	return func(ctx context.Context) (*agent.Result, error) {
+		*runs++
+		<-ctx.Done()
+		return &agent.Result{TurnsUsed: 4, ToolCalls: 9, Response: "edited auth.go"}, ctx.Err()
+	}
+}
+
+func TestWatchdog_FastPhasePasses(t *testing.T) {
+	sender := &captureSender{}
+	w := New(sender)
+	res, err := w.Run(context.Background(), "C1", "t1", "coder", func(context.Context) (*agent.Result, error) {
+		return &agent.Result{Response: "done"}, nil
+	})
+	if err != nil || res.Response != "done" || len(sender.all()) != 0 {
+		t.Errorf("res=%+v err=%v sent=%q", res, err, sender.all())
+	}
+}
+
+func TestWatchdog_TimeoutReportsAndRetries(t *testing.T) {
+	sender := &captureSender{}
+	esc := &escalations{ch: make(chan string, 1)}
+	w := New(sender, WithTimeouts(map[string]time.Duration{"coder": 20 * time.Millisecond}), WithEscalator(esc))
+
+	runs := 0
+	res, err := w.Run(context.Background(), "C1", "t1", "coder", slowPhase(&runs))
+	var te *TimeoutError
+	if !errors.As(err, &te) || te.Role != "coder" || res.TurnsUsed != 4 {
+		t.Fatalf("res=%+v err=%v", res, err)
+	}
+	report := sender.all()[0]
+	for _, want := range []string{"*coder* phase hit its 20ms limit", "4 turns, 9 tool calls", "edited auth.go", "*1* to retry / *2* to escalate"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if !w.Pending("C1", "t1") {
+		t.Fatal("expected pending timeout")
+	}
+
+	if w.HandleReply(context.Background(), "C1", "t1", "hello") {
+		t.Error("unrelated message consumed")
+	}
+	if !w.HandleReply(context.Background(), "C1", "t1", "2") {
+		t.Fatal("escalate reply not consumed")
+	}
+	select {
+	case role := <-esc.ch:
+		if role != "coder" {
+			t.Errorf("escalated %q", role)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("escalator not called")
+	}
+	if w.Pending("C1", "t1") || w.HandleReply(context.Background(), "C1", "t1", "1") {
+		t.Error("answered timeout should no longer be pending")
+	}
+}
+
+func TestWatchdog_HungPhase(t *testing.T) {
+	sender := &captureSender{}
+	w := New(sender, WithTimeouts(map[string]time.Duration{"pm": 10 * time.Millisecond}), WithGrace(10*time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := w.Run(context.Background(), "C1", "t1", "pm", func(context.Context) (*agent.Result, error) {
+		<-release // ignores ctx
+		return nil, nil
+	})
+	var te *TimeoutError
+	if !errors.As(err, &te) || te.Partial != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if msgs := sender.all(); len(msgs) != 1 || !strings.Contains(msgs[0], "did not respond to cancellation") ||
+		strings.Contains(msgs[0], "escalate") {
+		t.Errorf("sent = %q", msgs)
+	}
+}
+
+func TestWatchdog_ParentCancelIsNotTimeout(t *testing.T) {
+	sender := &captureSender{}
+	w := New(sender)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	runs := 0
+	_, err := w.Run(ctx, "C1", "t1", "reviewer", slowPhase(&runs))
+	if !errors.Is(err, context.Canceled) || len(sender.all()) != 0 {
+		t.Errorf("err=%v sent=%q", err, sender.all())
+	}
+}
+
+func TestWithTimeouts_OverridesAndRemoves(t *testing.T) {
+	w := New(&captureSender{}, WithTimeouts(TimeoutsFromConfig(map[string]int{"coder": 90, "reviewer": 0})))
+	if w.Limit("coder") != 90*time.Minute || w.Limit("reviewer") != 0 || w.Limit("pm") != 10*time.Minute {
+		t.Errorf("limits: coder %s reviewer %s pm %s", w.Limit("coder"), w.Limit("reviewer"), w.Limit("pm"))
+	}
+}
@@ -3,9 +3,16 @@ package worktree
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/messages"
 )
 
 // ThreadChecker checks Slack thread activity.
@@ -43,7 +50,52 @@ type PhaseChecker interface {
 // GCNotifier sends GC-related notifications to Slack threads.
 type GCNotifier interface {
 	// WarnInactive posts a warning message in the thread about pending cleanup.
-	WarnInactive(ctx context.Context, channelID, threadTS string) error
+	// size is the worktree's disk usage in bytes and grace is how long until
+	// the branch's worktree is removed.
+	WarnInactive(ctx context.Context, channelID, threadTS, branch string, size int64, grace time.Duration) error
+}
+
+// MessageSender posts a message in a thread.
+type MessageSender interface {
+	SendMessage(ctx context.Context, channel, thread, text string) error
+}
+
+// MessageNotifier is a GCNotifier that posts messages.GCInactiveWarning.
+type MessageNotifier struct {
+	sender  MessageSender
+	catalog *messages.Catalog
+}
+
+// NewMessageNotifier creates a notifier that posts through sender. A nil cat
+// uses the default locale.
+func NewMessageNotifier(sender MessageSender, cat *messages.Catalog) *MessageNotifier {
+	if cat == nil {
+		cat = messages.New(messages.DefaultLocale)
+	}
+	return &MessageNotifier{sender: sender, catalog: cat}
+}
+
+// WarnInactive implements GCNotifier.
+func (n *MessageNotifier) WarnInactive(ctx context.Context, channelID, threadTS, branch string, size int64, grace time.Duration) error {
+	text := n.catalog.Render(messages.GCInactiveWarning, map[string]string{
+		"Branch":      branch,
+		"Size":        FormatSize(size),
+		"GracePeriod": formatGrace(grace),
+	})
+	return n.sender.SendMessage(ctx, channelID, threadTS, text)
+}
+
+// formatGrace renders a grace period without trailing zero units, e.g.
+// "24h" or "1h30m".
+func formatGrace(d time.Duration) string {
+	s := d.Round(time.Minute).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
 }
 
 // WorktreeMapping maps a worktree branch to its Slack thread.
@@ -71,6 +123,10 @@ type GCConfig struct {
 	// GracePeriod is how long to wait after warning before cleaning up.
 	// Default: 24 hours.
 	GracePeriod time.Duration
+	// DiskQuota caps the total size of all worktrees in bytes. When it is
+	// exceeded, the oldest done-phase worktrees are removed first until the
+	// total fits. Default: 0 (no quota).
+	DiskQuota int64
 }
 
 // DefaultGCConfig returns the default GC configuration.
@@ -82,10 +138,55 @@ func DefaultGCConfig() GCConfig {
 	}
 }
 
+// GCConfigFromRepo applies the repo's gc settings over DefaultGCConfig.
+func GCConfigFromRepo(cfg config.RepoGC) GCConfig {
+	out := DefaultGCConfig()
+	if cfg.IntervalHours > 0 {
+		out.Interval = time.Duration(cfg.IntervalHours) * time.Hour
+	}
+	if cfg.InactivityHours > 0 {
+		out.InactivityTimeout = time.Duration(cfg.InactivityHours) * time.Hour
+	}
+	if cfg.GraceHours > 0 {
+		out.GracePeriod = time.Duration(cfg.GraceHours) * time.Hour
+	}
+	out.DiskQuota = int64(cfg.DiskQuotaMB) << 20
+	return out
+}
+
 // GCState tracks warned worktrees so we can enforce the grace period.
 type GCState struct {
 	// WarnedAt tracks when each branch was warned. Key is branch name.
 	WarnedAt map[string]time.Time
+	// LastRun is when the last real (not dry-run) pass finished.
+	LastRun time.Time
+	// NextRun is when the periodic loop runs next; zero if Run is not active.
+	NextRun time.Time
+}
+
+// GCAction is one worktree a GC pass warned about or cleaned.
+type GCAction struct {
+	Branch string `json:"branch"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
+}
+
+// GCReport describes a GC pass. In a dry run nothing was warned or removed;
+// the report lists what would have been.
+type GCReport struct {
+	DryRun  bool       `json:"dry_run"`
+	Warned  []GCAction `json:"warned,omitempty"`
+	Cleaned []GCAction `json:"cleaned,omitempty"`
+}
+
+// Freed returns the bytes released (or that would be) by the cleaned
+// worktrees.
+func (r *GCReport) Freed() int64 {
+	var total int64
+	for _, a := range r.Cleaned {
+		total += a.Size
+	}
+	return total
 }
 
 // GarbageCollector detects and cleans up orphaned worktrees.
@@ -99,6 +200,7 @@ type GarbageCollector struct {
 	config   GCConfig
 	logger   *slog.Logger
 	now      func() time.Time // injectable clock for testing
+	du       func(path string) (int64, error)
 
 	mu    sync.Mutex
 	state GCState
@@ -128,6 +230,14 @@ func WithGCClock(now func() time.Time) GCOption {
 	}
 }
 
+// WithGCDiskUsage replaces DirSize as the way worktree sizes are measured
+// (for testing).
+func WithGCDiskUsage(du func(path string) (int64, error)) GCOption {
+	return func(gc *GarbageCollector) {
+		gc.du = du
+	}
+}
+
 // NewGarbageCollector creates a new garbage collector.
 func NewGarbageCollector(
 	manager *Manager,
@@ -148,6 +258,7 @@ func NewGarbageCollector(
 		config:   DefaultGCConfig(),
 		logger:   slog.Default(),
 		now:      time.Now,
+		du:       DirSize,
 		state:    GCState{WarnedAt: make(map[string]time.Time)},
 	}
 	for _, opt := range opts {
@@ -158,30 +269,50 @@ func NewGarbageCollector(
 
 // RunOnce performs a single GC pass: detect orphans, warn or clean.
 func (gc *GarbageCollector) RunOnce(ctx context.Context) error {
+	_, err := gc.Trigger(ctx, false)
+	return err
+}
+
+// Trigger runs a GC pass on demand and reports what it did. With dryRun
+// nothing is warned, removed, or recorded; the report shows what a real pass
+// would do right now.
+func (gc *GarbageCollector) Trigger(ctx context.Context, dryRun bool) (*GCReport, error) {
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
 
 	mappings, err := gc.mappings.ListMappings(ctx)
 	if err != nil {
-		return fmt.Errorf("list mappings: %w", err)
+		return nil, fmt.Errorf("list mappings: %w", err)
 	}
 
 	worktrees, err := gc.manager.List(ctx)
 	if err != nil {
-		return fmt.Errorf("list worktrees: %w", err)
+		return nil, fmt.Errorf("list worktrees: %w", err)
 	}
 
-	// Build set of existing worktree branches for quick lookup
-	wtSet := make(map[string]bool, len(worktrees))
+	// Measure each existing worktree once; sizes feed the warnings and the
+	// quota check.
+	sizes := make(map[string]int64, len(worktrees))
 	for _, wt := range worktrees {
-		wtSet[wt.Branch] = true
+		size, err := gc.du(wt.Path)
+		if err != nil {
+			gc.logger.Warn("failed to measure worktree", "branch", wt.Branch, "err", err)
+		}
+		sizes[wt.Branch] = size
 	}
 
 	now := gc.now()
+	report := &GCReport{DryRun: dryRun}
+	var kept []WorktreeMapping
 
 	for _, m := range mappings {
+		size, exists := sizes[m.Branch]
 		// Skip if worktree doesn't exist locally
-		if !wtSet[m.Branch] {
+		if !exists {
+			report.Cleaned = append(report.Cleaned, GCAction{Branch: m.Branch, Reason: "no local worktree"})
+			if dryRun {
+				continue
+			}
 			gc.logger.Info("mapping has no local worktree, cleaning mapping", "branch", m.Branch)
 			gc.mappings.RemoveMapping(ctx, m.Branch)
 			delete(gc.state.WarnedAt, m.Branch)
@@ -191,24 +322,34 @@ func (gc *GarbageCollector) RunOnce(ctx context.Context) error {
 		orphaned, err := gc.isOrphaned(ctx, m, now)
 		if err != nil {
 			gc.logger.Warn("error checking orphan status", "branch", m.Branch, "err", err)
+			kept = append(kept, m)
 			continue
 		}
 
 		if !orphaned {
 			// Reset warning if thread became active again
-			delete(gc.state.WarnedAt, m.Branch)
+			if !dryRun {
+				delete(gc.state.WarnedAt, m.Branch)
+			}
+			kept = append(kept, m)
 			continue
 		}
 
 		// Check if we already warned
 		warnedAt, warned := gc.state.WarnedAt[m.Branch]
 		if !warned {
+			report.Warned = append(report.Warned, GCAction{Branch: m.Branch, Size: size, Reason: "inactive"})
+			if dryRun {
+				kept = append(kept, m)
+				continue
+			}
 			// First detection — warn and record
-			gc.logger.Info("orphan detected, warning", "branch", m.Branch)
-			if err := gc.notifier.WarnInactive(ctx, m.ChannelID, m.ThreadTS); err != nil {
+			gc.logger.Info("orphan detected, warning", "branch", m.Branch, "size", FormatSize(size))
+			if err := gc.notifier.WarnInactive(ctx, m.ChannelID, m.ThreadTS, m.Branch, size, gc.config.GracePeriod); err != nil {
 				gc.logger.Warn("failed to warn thread", "branch", m.Branch, "err", err)
 			}
 			gc.state.WarnedAt[m.Branch] = now
+			kept = append(kept, m)
 			continue
 		}
 
@@ -216,21 +357,123 @@ func (gc *GarbageCollector) RunOnce(ctx context.Context) error {
 		if now.Sub(warnedAt) < gc.config.GracePeriod {
 			gc.logger.Info("orphan in grace period", "branch", m.Branch,
 				"warned_at", warnedAt, "remaining", gc.config.GracePeriod-now.Sub(warnedAt))
+			kept = append(kept, m)
 			continue
 		}
 
 		// Grace period elapsed — clean up
-		gc.logger.Info("cleaning orphan worktree", "branch", m.Branch)
-		if err := gc.manager.Remove(ctx, m.Branch, true); err != nil {
-			gc.logger.Warn("failed to remove worktree", "branch", m.Branch, "err", err)
+		report.Cleaned = append(report.Cleaned, GCAction{Branch: m.Branch, Size: size, Reason: "grace period elapsed"})
+		delete(sizes, m.Branch)
+		if dryRun {
+			continue
+		}
+		gc.logger.Info("cleaning orphan worktree", "branch", m.Branch, "size", FormatSize(size))
+		gc.remove(ctx, m.Branch)
+	}
+
+	if gc.config.DiskQuota > 0 {
+		gc.enforceQuota(ctx, kept, sizes, report)
+	}
+
+	if !dryRun {
+		gc.state.LastRun = now
+	}
+	return report, nil
+}
+
+// remove deletes a worktree, its mapping, and any pending warning.
+func (gc *GarbageCollector) remove(ctx context.Context, branch string) {
+	if err := gc.manager.Remove(ctx, branch, true); err != nil {
+		gc.logger.Warn("failed to remove worktree", "branch", branch, "err", err)
+	}
+	if err := gc.mappings.RemoveMapping(ctx, branch); err != nil {
+		gc.logger.Warn("failed to remove mapping", "branch", branch, "err", err)
+	}
+	delete(gc.state.WarnedAt, branch)
+}
+
+// enforceQuota removes done-phase worktrees, least recently active first,
+// until the total size of all worktrees fits in the disk quota. Worktrees in
+// any other phase are never removed for space.
+func (gc *GarbageCollector) enforceQuota(ctx context.Context, mappings []WorktreeMapping, sizes map[string]int64, report *GCReport) {
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	if total <= gc.config.DiskQuota {
+		return
+	}
+
+	type candidate struct {
+		mapping      WorktreeMapping
+		lastActivity time.Time
+	}
+	var candidates []candidate
+	for _, m := range mappings {
+		phase, err := gc.phases.GetPhase(ctx, m.ThreadTS)
+		if err != nil || phase != PhaseDone {
+			continue
+		}
+		last, err := gc.threads.LastActivity(ctx, m.ChannelID, m.ThreadTS)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{mapping: m, lastActivity: last})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].lastActivity.Before(candidates[j].lastActivity)
+	})
+
+	for _, c := range candidates {
+		if total <= gc.config.DiskQuota {
+			break
 		}
-		if err := gc.mappings.RemoveMapping(ctx, m.Branch); err != nil {
-			gc.logger.Warn("failed to remove mapping", "branch", m.Branch, "err", err)
+		size := sizes[c.mapping.Branch]
+		report.Cleaned = append(report.Cleaned, GCAction{Branch: c.mapping.Branch, Size: size, Reason: "disk quota"})
+		total -= size
+		if report.DryRun {
+			continue
 		}
-		delete(gc.state.WarnedAt, m.Branch)
+		gc.logger.Info("disk quota exceeded, cleaning done worktree", "branch", c.mapping.Branch,
+			"size", FormatSize(size), "total", FormatSize(total+size), "quota", FormatSize(gc.config.DiskQuota))
+		gc.remove(ctx, c.mapping.Branch)
+	}
+	if total > gc.config.DiskQuota && !report.DryRun {
+		gc.logger.Warn("worktrees still exceed disk quota", "total", FormatSize(total), "quota", FormatSize(gc.config.DiskQuota))
 	}
+}
 
-	return nil
+// DirSize returns the total size in bytes of the regular files under path.
+func DirSize(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// FormatSize renders a byte count for humans, e.g. "1.4 GB".
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
 // isOrphaned checks if a worktree is orphaned based on the three criteria:
@@ -269,8 +512,29 @@ func (gc *GarbageCollector) isOrphaned(ctx context.Context, m WorktreeMapping, n
 	return true, nil
 }
 
+// Status returns a copy of the GC state: warned branches and run times.
+func (gc *GarbageCollector) Status() GCState {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	warned := make(map[string]time.Time, len(gc.state.WarnedAt))
+	for branch, at := range gc.state.WarnedAt {
+		warned[branch] = at
+	}
+	return GCState{WarnedAt: warned, LastRun: gc.state.LastRun, NextRun: gc.state.NextRun}
+}
+
+// scheduleNext records when the periodic loop runs next.
+func (gc *GarbageCollector) scheduleNext(next time.Time) {
+	gc.mu.Lock()
+	gc.state.NextRun = next
+	gc.mu.Unlock()
+}
+
 // Run starts the periodic GC loop. Blocks until context is cancelled.
 func (gc *GarbageCollector) Run(ctx context.Context) error {
+	defer gc.scheduleNext(time.Time{})
+
 	// Run once immediately
 	if err := gc.RunOnce(ctx); err != nil {
 		gc.logger.Warn("initial GC run failed", "err", err)
@@ -278,6 +542,7 @@ func (gc *GarbageCollector) Run(ctx context.Context) error {
 
 	ticker := time.NewTicker(gc.config.Interval)
 	defer ticker.Stop()
+	gc.scheduleNext(gc.now().Add(gc.config.Interval))
 
 	for {
 		select {
@@ -287,6 +552,7 @@ func (gc *GarbageCollector) Run(ctx context.Context) error {
 			if err := gc.RunOnce(ctx); err != nil {
 				gc.logger.Warn("GC run failed", "err", err)
 			}
+			gc.scheduleNext(gc.now().Add(gc.config.Interval))
 		}
 	}
 }
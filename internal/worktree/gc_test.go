@@ -4,8 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/config"
+	"github.com/leandrotocalini/codebutler/internal/github"
+	"github.com/leandrotocalini/codebutler/internal/slack"
+)
+
+// The production checkers must keep satisfying the GC's interfaces.
+var (
+	_ ThreadChecker = (*slack.ThreadActivity)(nil)
+	_ PRChecker     = (*github.GHOps)(nil)
+	_ PhaseChecker  = (*PhaseStore)(nil)
 )
 
 // --- Mock implementations ---
@@ -56,14 +70,16 @@ func (m *mockPhaseChecker) GetPhase(_ context.Context, threadID string) (ThreadP
 
 type mockGCNotifier struct {
 	warned []string // channelID+threadTS pairs
+	sizes  []int64
 	err    error
 }
 
-func (m *mockGCNotifier) WarnInactive(_ context.Context, channelID, threadTS string) error {
+func (m *mockGCNotifier) WarnInactive(_ context.Context, channelID, threadTS, _ string, size int64, _ time.Duration) error {
 	if m.err != nil {
 		return m.err
 	}
 	m.warned = append(m.warned, channelID+threadTS)
+	m.sizes = append(m.sizes, size)
 	return nil
 }
 
@@ -468,3 +484,185 @@ func TestDefaultGCConfig(t *testing.T) {
 		t.Errorf("expected 24h grace period, got %v", cfg.GracePeriod)
 	}
 }
+
+func TestGC_WarningIncludesSize(t *testing.T) {
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+
+	mgr := newMockManager([]WorktreeInfo{
+		{Path: "/repo/.codebutler/branches/codebutler/feat-a", Branch: "codebutler/feat-a"},
+	})
+	threads := &mockThreadChecker{lastActivity: map[string]time.Time{"C123T100": now.Add(-72 * time.Hour)}}
+	notifier := &mockGCNotifier{}
+	store := &mockMappingStore{mappings: []WorktreeMapping{
+		{Branch: "codebutler/feat-a", ChannelID: "C123", ThreadTS: "T100"},
+	}}
+
+	gc := NewGarbageCollector(mgr, threads, &mockPRChecker{}, &mockPhaseChecker{phases: map[string]ThreadPhase{"T100": PhaseDone}}, notifier, store,
+		WithGCClock(func() time.Time { return now }),
+		WithGCDiskUsage(func(string) (int64, error) { return 3 << 20, nil }),
+	)
+	if err := gc.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sizes) != 1 || notifier.sizes[0] != 3<<20 {
+		t.Errorf("warned sizes = %v, want [%d]", notifier.sizes, 3<<20)
+	}
+}
+
+func TestGC_DiskQuota_RemovesOldestDoneFirst(t *testing.T) {
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+
+	mgr := newMockManager([]WorktreeInfo{
+		{Path: "/repo/.codebutler/branches/codebutler/old", Branch: "codebutler/old"},
+		{Path: "/repo/.codebutler/branches/codebutler/newer", Branch: "codebutler/newer"},
+		{Path: "/repo/.codebutler/branches/codebutler/coding", Branch: "codebutler/coding"},
+	})
+	// All recently active, so none is orphaned; only the quota applies.
+	threads := &mockThreadChecker{lastActivity: map[string]time.Time{
+		"C1T1": now.Add(-3 * time.Hour),
+		"C1T2": now.Add(-1 * time.Hour),
+		"C1T3": now.Add(-5 * time.Hour),
+	}}
+	phases := &mockPhaseChecker{phases: map[string]ThreadPhase{"T1": PhaseDone, "T2": PhaseDone, "T3": PhaseCoding}}
+	store := &mockMappingStore{mappings: []WorktreeMapping{
+		{Branch: "codebutler/newer", ChannelID: "C1", ThreadTS: "T2"},
+		{Branch: "codebutler/old", ChannelID: "C1", ThreadTS: "T1"},
+		{Branch: "codebutler/coding", ChannelID: "C1", ThreadTS: "T3"},
+	}}
+	cfg := DefaultGCConfig()
+	cfg.DiskQuota = 250
+
+	gc := NewGarbageCollector(mgr, threads, &mockPRChecker{}, phases, &mockGCNotifier{}, store,
+		WithGCConfig(cfg),
+		WithGCClock(func() time.Time { return now }),
+		WithGCDiskUsage(func(string) (int64, error) { return 100, nil }),
+	)
+	if err := gc.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.removed) != 1 || store.removed[0] != "codebutler/old" {
+		t.Errorf("removed = %v, want only the oldest done worktree", store.removed)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), make([]byte, 10), 0o644)
+	os.MkdirAll(filepath.Join(dir, "sub"), 0o755)
+	os.WriteFile(filepath.Join(dir, "sub", "b.txt"), make([]byte, 32), 0o644)
+
+	size, err := DirSize(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 42 {
+		t.Errorf("DirSize = %d, want 42", size)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		512:        "512 B",
+		2048:       "2.0 KB",
+		3 << 20:    "3.0 MB",
+		1536 << 20: "1.5 GB",
+	}
+	for in, want := range tests {
+		if got := FormatSize(in); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func newDryRunFixture(now time.Time) (*GarbageCollector, *mockGCNotifier, *mockMappingStore) {
+	mgr := newMockManager([]WorktreeInfo{
+		{Path: "/repo/.codebutler/branches/codebutler/idle", Branch: "codebutler/idle"},
+		{Path: "/repo/.codebutler/branches/codebutler/expired", Branch: "codebutler/expired"},
+	})
+	threads := &mockThreadChecker{lastActivity: map[string]time.Time{
+		"C1T1": now.Add(-72 * time.Hour),
+		"C1T2": now.Add(-96 * time.Hour),
+	}}
+	phases := &mockPhaseChecker{phases: map[string]ThreadPhase{"T1": PhaseDone, "T2": PhaseDone}}
+	notifier := &mockGCNotifier{}
+	store := &mockMappingStore{mappings: []WorktreeMapping{
+		{Branch: "codebutler/idle", ChannelID: "C1", ThreadTS: "T1"},
+		{Branch: "codebutler/expired", ChannelID: "C1", ThreadTS: "T2"},
+	}}
+	gc := NewGarbageCollector(mgr, threads, &mockPRChecker{}, phases, notifier, store,
+		WithGCClock(func() time.Time { return now }),
+		WithGCDiskUsage(func(string) (int64, error) { return 2 << 20, nil }),
+	)
+	gc.state.WarnedAt["codebutler/expired"] = now.Add(-30 * time.Hour)
+	return gc, notifier, store
+}
+
+func TestGC_DryRun_ChangesNothing(t *testing.T) {
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	gc, notifier, store := newDryRunFixture(now)
+
+	report, err := gc.Trigger(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Warned) != 1 || report.Warned[0].Branch != "codebutler/idle" {
+		t.Errorf("warned = %+v", report.Warned)
+	}
+	if len(report.Cleaned) != 1 || report.Cleaned[0].Branch != "codebutler/expired" {
+		t.Errorf("cleaned = %+v", report.Cleaned)
+	}
+	if len(notifier.warned) != 0 || len(store.removed) != 0 {
+		t.Errorf("dry run had side effects: warned %v, removed %v", notifier.warned, store.removed)
+	}
+	status := gc.Status()
+	if len(status.WarnedAt) != 1 || !status.LastRun.IsZero() {
+		t.Errorf("dry run changed state: %+v", status)
+	}
+
+	out := FormatGCReport(report)
+	for _, want := range []string{"Dry run", "Would warn `codebutler/idle`", "Would clean `codebutler/expired` (2.0 MB", "Space freed: 2.0 MB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := gc.Trigger(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.warned) != 1 || len(store.removed) != 1 {
+		t.Errorf("real run: warned %v, removed %v", notifier.warned, store.removed)
+	}
+	if out := FormatGCStatus(gc.Status(), now); !strings.Contains(out, "last run 0s ago") || !strings.Contains(out, "`codebutler/idle` warned") {
+		t.Errorf("status: %q", out)
+	}
+}
+
+func TestGCConfigFromRepo(t *testing.T) {
+	cfg := GCConfigFromRepo(config.RepoGC{InactivityHours: 12, DiskQuotaMB: 2048})
+	if cfg.InactivityTimeout != 12*time.Hour || cfg.DiskQuota != 2<<30 {
+		t.Errorf("got %+v", cfg)
+	}
+	if cfg.Interval != 6*time.Hour || cfg.GracePeriod != 24*time.Hour {
+		t.Errorf("unset fields lost their defaults: %+v", cfg)
+	}
+}
+
+type recordingSender struct{ text string }
+
+func (s *recordingSender) SendMessage(_ context.Context, _, _, text string) error {
+	s.text = text
+	return nil
+}
+
+func TestMessageNotifier_WarnInactive(t *testing.T) {
+	sender := &recordingSender{}
+	n := NewMessageNotifier(sender, nil)
+	if err := n.WarnInactive(context.Background(), "C1", "T1", "codebutler/fix-login", 3<<20, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"`codebutler/fix-login`", "3.0 MB", "24h"} {
+		if !strings.Contains(sender.text, want) {
+			t.Errorf("warning %q missing %q", sender.text, want)
+		}
+	}
+}
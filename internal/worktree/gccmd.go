@@ -0,0 +1,91 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leandrotocalini/codebutler/internal/chatcmd"
+)
+
+// GCCommand returns the /gc chat command: "/gc run [--dry-run]" triggers a
+// pass on demand and "/gc status" shows the warned branches and next run.
+func GCCommand(gc *GarbageCollector) *chatcmd.Command {
+	return &chatcmd.Command{
+		Name:        "gc",
+		Usage:       "/gc run [--dry-run] | /gc status",
+		Description: "Run worktree garbage collection now, or preview it",
+		Run: func(ctx context.Context, inv chatcmd.Invocation) (string, error) {
+			sub := ""
+			if len(inv.Args) > 0 {
+				sub = inv.Args[0]
+			}
+			switch sub {
+			case "run":
+				dryRun := len(inv.Args) > 1 && (inv.Args[1] == "--dry-run" || inv.Args[1] == "dry-run")
+				report, err := gc.Trigger(ctx, dryRun)
+				if err != nil {
+					return "", err
+				}
+				return FormatGCReport(report), nil
+			case "status", "":
+				return FormatGCStatus(gc.Status(), gc.now()), nil
+			}
+			return "Usage: /gc run [--dry-run] | /gc status", nil
+		},
+	}
+}
+
+// FormatGCReport renders a GC pass for chat.
+func FormatGCReport(r *GCReport) string {
+	if len(r.Warned) == 0 && len(r.Cleaned) == 0 {
+		if r.DryRun {
+			return "Dry run: nothing would be warned or cleaned."
+		}
+		return "GC pass done: nothing to warn or clean."
+	}
+
+	var b strings.Builder
+	warnVerb, cleanVerb := "Warned", "Cleaned"
+	if r.DryRun {
+		b.WriteString("Dry run, nothing was changed.\n")
+		warnVerb, cleanVerb = "Would warn", "Would clean"
+	}
+	for _, a := range r.Warned {
+		fmt.Fprintf(&b, "• %s `%s` (%s, %s)\n", warnVerb, a.Branch, FormatSize(a.Size), a.Reason)
+	}
+	for _, a := range r.Cleaned {
+		fmt.Fprintf(&b, "• %s `%s` (%s, %s)\n", cleanVerb, a.Branch, FormatSize(a.Size), a.Reason)
+	}
+	if len(r.Cleaned) > 0 {
+		fmt.Fprintf(&b, "Space freed: %s", FormatSize(r.Freed()))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// FormatGCStatus renders the GC state for /gc status and /status.
+func FormatGCStatus(s GCState, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("Worktree GC: ")
+	switch {
+	case !s.NextRun.IsZero():
+		fmt.Fprintf(&b, "next run in %s", s.NextRun.Sub(now).Round(time.Minute))
+	default:
+		b.WriteString("not scheduled")
+	}
+	if !s.LastRun.IsZero() {
+		fmt.Fprintf(&b, " · last run %s ago", now.Sub(s.LastRun).Round(time.Minute))
+	}
+
+	branches := make([]string, 0, len(s.WarnedAt))
+	for branch := range s.WarnedAt {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+	for _, branch := range branches {
+		fmt.Fprintf(&b, "\n• `%s` warned %s ago", branch, now.Sub(s.WarnedAt[branch]).Round(time.Minute))
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package worktree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PhaseTransition is one phase change in a thread's history.
+type PhaseTransition struct {
+	Phase ThreadPhase `json:"phase"`
+	Role  string      `json:"role,omitempty"` // agent that took over, if any
+	At    time.Time   `json:"at"`
+}
+
+// PhaseRecord is a thread's current phase and how it got there.
+type PhaseRecord struct {
+	Phase   ThreadPhase       `json:"phase"`
+	Since   time.Time         `json:"since"`
+	History []PhaseTransition `json:"history"`
+}
+
+// PhaseStore persists per-thread phases in a JSON file, keyed by thread
+// timestamp. It implements PhaseChecker for the GC. Thread-safe.
+type PhaseStore struct {
+	mu      sync.Mutex
+	path    string
+	now     func() time.Time
+	threads map[string]*PhaseRecord
+}
+
+// DefaultPhasePath returns the phase file for a repo.
+func DefaultPhasePath(repoDir string) string {
+	return filepath.Join(repoDir, ".codebutler", "phases.json")
+}
+
+// OpenPhaseStore loads the phase file at path; a missing file is an empty
+// store.
+func OpenPhaseStore(path string) (*PhaseStore, error) {
+	s := &PhaseStore{path: path, now: time.Now, threads: make(map[string]*PhaseRecord)}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("read phase store: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.threads); err != nil {
+		return nil, fmt.Errorf("parse phase store: %w", err)
+	}
+	return s, nil
+}
+
+// PhaseForRole maps the agent a thread is handed to onto its phase. ok is
+// false for roles that do not move the thread along (researcher, artist, ...).
+func PhaseForRole(role string) (ThreadPhase, bool) {
+	switch role {
+	case "pm":
+		return PhasePlanning, true
+	case "coder":
+		return PhaseCoding, true
+	case "reviewer":
+		return PhaseReview, true
+	}
+	return PhaseUnknown, false
+}
+
+// SetPhase records a transition. Setting the current phase again is a no-op.
+func (s *PhaseStore) SetPhase(thread string, phase ThreadPhase, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.threads[thread]
+	if ok && rec.Phase == phase {
+		return nil
+	}
+	if !ok {
+		rec = &PhaseRecord{}
+		s.threads[thread] = rec
+	}
+	now := s.now()
+	rec.Phase = phase
+	rec.Since = now
+	rec.History = append(rec.History, PhaseTransition{Phase: phase, Role: role, At: now})
+	return s.save()
+}
+
+// RecordHandoff moves the thread to the phase of the role it was handed
+// to. Handoffs to roles without a phase are ignored.
+func (s *PhaseStore) RecordHandoff(thread, role string) error {
+	phase, ok := PhaseForRole(role)
+	if !ok {
+		return nil
+	}
+	return s.SetPhase(thread, phase, role)
+}
+
+// GetPhase implements PhaseChecker. Unknown threads are PhaseUnknown.
+func (s *PhaseStore) GetPhase(_ context.Context, threadID string) (ThreadPhase, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.threads[threadID]; ok {
+		return rec.Phase, nil
+	}
+	return PhaseUnknown, nil
+}
+
+// Record returns a copy of a thread's phase record.
+func (s *PhaseStore) Record(thread string) (PhaseRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.threads[thread]
+	if !ok {
+		return PhaseRecord{}, false
+	}
+	out := *rec
+	out.History = append([]PhaseTransition(nil), rec.History...)
+	return out, true
+}
+
+// StatusLine answers "what stage is my task in?" for /status. It is empty
+// for threads with no recorded phase.
+func (s *PhaseStore) StatusLine(thread string) string {
+	rec, ok := s.Record(thread)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("Phase: %s (for %s)", rec.Phase, s.now().Sub(rec.Since).Round(time.Minute))
+}
+
+// save writes the store atomically. Callers hold mu.
+func (s *PhaseStore) save() error {
+	data, err := json.MarshalIndent(s.threads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal phase store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create phase store dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write phase store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename phase store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package worktree
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPhaseStore_HandoffsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phases.json")
+	s, err := OpenPhaseStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+
+	s.RecordHandoff("T1", "pm")
+	s.RecordHandoff("T1", "researcher") // no phase of its own
+	s.now = func() time.Time { return start.Add(20 * time.Minute) }
+	s.RecordHandoff("T1", "coder")
+	s.RecordHandoff("T1", "coder") // same phase again
+
+	reopened, err := OpenPhaseStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	phase, _ := reopened.GetPhase(context.Background(), "T1")
+	if phase != PhaseCoding {
+		t.Errorf("phase = %q, want %q", phase, PhaseCoding)
+	}
+	rec, _ := reopened.Record("T1")
+	if len(rec.History) != 2 || rec.History[0].Phase != PhasePlanning || rec.History[1].Role != "coder" {
+		t.Errorf("history = %+v", rec.History)
+	}
+	if phase, _ := reopened.GetPhase(context.Background(), "T9"); phase != PhaseUnknown {
+		t.Errorf("unknown thread phase = %q", phase)
+	}
+
+	reopened.now = func() time.Time { return start.Add(65 * time.Minute) }
+	if got := reopened.StatusLine("T1"); got != "Phase: coder (for 45m0s)" {
+		t.Errorf("status line = %q", got)
+	}
+}
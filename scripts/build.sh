@@ -0,0 +1,28 @@
+#!/bin/bash
+# Build a stamped codebutler binary and append its entry to SHA256SUMS
+# next to the output. The build time is the commit time, so building the
+# same commit again reproduces the same binary and hash.
+# With CODEBUTLER_SIGNING_KEY set to an Ed25519 PEM private key, SHA256SUMS
+# is also signed into SHA256SUMS.sig for `codebutler version --verify`.
+# Usage: scripts/build.sh [version] [output]
+set -euo pipefail
+
+version="${1:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}"
+out="${2:-dist/codebutler}"
+pkg="github.com/leandrotocalini/codebutler/internal/buildinfo"
+
+commit="$(git rev-parse HEAD 2>/dev/null || echo "")"
+date="$(git log -1 --format=%cI 2>/dev/null || echo "")"
+
+mkdir -p "$(dirname "$out")"
+go build -trimpath \
+    -ldflags "-X ${pkg}.Version=${version} -X ${pkg}.Commit=${commit} -X ${pkg}.Date=${date}" \
+    -o "$out" ./cmd/codebutler
+
+cd "$(dirname "$out")"
+sha256sum "$(basename "$out")" >> SHA256SUMS
+if [ -n "${CODEBUTLER_SIGNING_KEY:-}" ]; then
+    openssl pkeyutl -sign -inkey "$CODEBUTLER_SIGNING_KEY" -rawin -in SHA256SUMS | base64 -w0 > SHA256SUMS.sig
+    echo "signed SHA256SUMS"
+fi
+echo "built $out ($version, ${commit:0:12})"